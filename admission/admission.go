@@ -0,0 +1,381 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admission provides the glue to verify container images with
+// notation in Kubernetes admission webhooks: verifier construction, image
+// reference normalization, tag to digest resolution, decision caching and
+// structured deny messages.
+//
+// A webhook creates a [Validator] once and calls [Validator.Validate] with the
+// images of each admitted pod:
+//
+//	validator, err := admission.New(admission.Options{})
+//	...
+//	decision := validator.Validate(ctx, images)
+//	if !decision.Allowed {
+//		// deny with decision.Message()
+//	}
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/verifier"
+)
+
+// defaultMaxSignatureAttempts is the default maximum number of signatures
+// processed per image.
+const defaultMaxSignatureAttempts = 50
+
+// defaultCacheMaxEntries is the default maximum number of cached decisions.
+const defaultCacheMaxEntries = 10000
+
+// Options contains parameters for [New].
+type Options struct {
+	// Verifier verifies the signatures of the images. If nil, a verifier
+	// based on the local configuration is created with
+	// verifier.NewServerVerifierFromConfig.
+	Verifier notation.Verifier
+
+	// RepositoryFunc returns the repository client of a repository. If nil,
+	// anonymous repository clients are used.
+	RepositoryFunc notation.RepositoryFunc
+
+	// MaxSignatureAttempts is the maximum number of signatures processed per
+	// image. If set to less than or equals to zero, 50 is used.
+	MaxSignatureAttempts int
+
+	// PluginConfig is a map of plugin configs.
+	PluginConfig map[string]string
+
+	// CacheTTL is the duration for which the decision on an image digest is
+	// cached. If set to less than or equals to zero, decisions are not
+	// cached. Tags are resolved to digests on every validation.
+	//
+	// Only definitive decisions are cached: an image passing verification,
+	// or an image whose signatures failed verification. Denials caused by
+	// errors such as registry failures are not cached.
+	CacheTTL time.Duration
+
+	// CacheMaxEntries is the maximum number of cached decisions. When the
+	// cache is full, the expired decisions are evicted first, followed by
+	// arbitrary decisions if needed. If set to less than or equals to zero,
+	// 10000 is used.
+	CacheMaxEntries int
+}
+
+// Validator validates the images of admission requests. It is safe for
+// concurrent use.
+type Validator struct {
+	verifier             notation.Verifier
+	repoFunc             notation.RepositoryFunc
+	maxSignatureAttempts int
+	pluginConfig         map[string]string
+	cacheTTL             time.Duration
+	cacheMaxEntries      int
+
+	mu        sync.Mutex
+	repos     map[string]*repositoryEntry
+	decisions map[string]cachedResult
+}
+
+// repositoryEntry creates the repository client of a repository once.
+type repositoryEntry struct {
+	once sync.Once
+	repo registry.Repository
+	err  error
+}
+
+type cachedResult struct {
+	result ImageResult
+	expiry time.Time
+}
+
+// New creates a Validator.
+func New(opts Options) (*Validator, error) {
+	v := opts.Verifier
+	if v == nil {
+		serverVerifier, err := verifier.NewServerVerifierFromConfig(verifier.VerifierOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the verifier: %w", err)
+		}
+		v = serverVerifier
+	}
+	repoFunc := opts.RepositoryFunc
+	if repoFunc == nil {
+		repoFunc = func(ctx context.Context, repository string) (registry.Repository, error) {
+			remoteRepo, err := remote.NewRepository(repository)
+			if err != nil {
+				return nil, err
+			}
+			return registry.NewRepository(remoteRepo), nil
+		}
+	}
+	maxSignatureAttempts := opts.MaxSignatureAttempts
+	if maxSignatureAttempts <= 0 {
+		maxSignatureAttempts = defaultMaxSignatureAttempts
+	}
+	cacheMaxEntries := opts.CacheMaxEntries
+	if cacheMaxEntries <= 0 {
+		cacheMaxEntries = defaultCacheMaxEntries
+	}
+	return &Validator{
+		verifier:             v,
+		repoFunc:             repoFunc,
+		maxSignatureAttempts: maxSignatureAttempts,
+		pluginConfig:         opts.PluginConfig,
+		cacheTTL:             opts.CacheTTL,
+		cacheMaxEntries:      cacheMaxEntries,
+		repos:                make(map[string]*repositoryEntry),
+		decisions:            make(map[string]cachedResult),
+	}, nil
+}
+
+// Decision is the admission decision on a set of images.
+type Decision struct {
+	// Allowed is true if all images are allowed.
+	Allowed bool
+
+	// Results are the results of the images in the order of validation.
+	Results []ImageResult
+}
+
+// ImageResult is the validation result of an image.
+type ImageResult struct {
+	// Image is the image as specified in the admission request, e.g.
+	// "nginx:1.25".
+	Image string
+
+	// Reference is the normalized digest reference of the image, e.g.
+	// "docker.io/library/nginx@sha256:...". It is empty if the image cannot
+	// be resolved.
+	Reference string
+
+	// Allowed is true if the image passed verification.
+	Allowed bool
+
+	// Reason describes why the image is denied. It is empty if the image is
+	// allowed.
+	Reason string
+
+	// Error is the error that caused the image to be denied.
+	Error error
+}
+
+// Message returns the deny message of the decision listing the reason of
+// each denied image, or an empty string if the decision is allowed.
+func (d *Decision) Message() string {
+	if d.Allowed {
+		return ""
+	}
+	var reasons []string
+	for _, result := range d.Results {
+		if !result.Allowed {
+			reasons = append(reasons, fmt.Sprintf("image %q: %s", result.Image, result.Reason))
+		}
+	}
+	return "notation signature verification failed: " + strings.Join(reasons, "; ")
+}
+
+// Validate verifies the signatures of images and returns the admission
+// decision. Images are validated sequentially; duplicated images are
+// validated once.
+//
+// For a multi-tenant webhook, the trust policy of the tenant of the request,
+// e.g. the namespace of the pod, is selected with [verifier.WithTenant] on
+// ctx. The decisions are cached per tenant.
+func (v *Validator) Validate(ctx context.Context, images []string) *Decision {
+	decision := &Decision{Allowed: true}
+	validated := make(map[string]ImageResult)
+	for _, image := range images {
+		result, ok := validated[image]
+		if !ok {
+			result = v.validateImage(ctx, image)
+			validated[image] = result
+		}
+		decision.Results = append(decision.Results, result)
+		if !result.Allowed {
+			decision.Allowed = false
+		}
+	}
+	return decision
+}
+
+// validateImage verifies the signature of image.
+func (v *Validator) validateImage(ctx context.Context, image string) ImageResult {
+	logger := log.GetLogger(ctx)
+	result := ImageResult{Image: image}
+	ref, err := NormalizeReference(image)
+	if err != nil {
+		return deny(result, "invalid image reference", err)
+	}
+	repo, err := v.repository(ctx, ref.Registry+"/"+ref.Repository)
+	if err != nil {
+		return deny(result, "failed to access the repository", err)
+	}
+
+	// resolve tags to digests so that the decision is made on the content
+	if ref.ValidateReferenceAsDigest() != nil {
+		desc, err := repo.Resolve(ctx, ref.Reference)
+		if err != nil {
+			return deny(result, "failed to resolve the image tag", err)
+		}
+		ref.Reference = desc.Digest.String()
+	}
+	result.Reference = ref.String()
+
+	cacheKey := decisionCacheKey(ctx, result.Reference)
+	if cached, ok := v.cachedResult(cacheKey); ok {
+		logger.Debugf("Admission decision of %s found in cache", result.Reference)
+		cached.Image = image
+		return cached
+	}
+	_, _, err = notation.Verify(ctx, v.verifier, repo, notation.VerifyOptions{
+		ArtifactReference:    result.Reference,
+		PluginConfig:         v.pluginConfig,
+		MaxSignatureAttempts: v.maxSignatureAttempts,
+	})
+	if err != nil {
+		result = deny(result, denyReason(err), err)
+	} else {
+		result.Allowed = true
+	}
+	if isDefinitive(result) {
+		v.cacheResult(cacheKey, result)
+	}
+	return result
+}
+
+// isDefinitive returns true if the result does not depend on transient
+// conditions, i.e. the image passed verification or its signatures failed
+// verification.
+func isDefinitive(result ImageResult) bool {
+	if result.Allowed {
+		return true
+	}
+	var errVerificationFailed notation.ErrorVerificationFailed
+	return errors.As(result.Error, &errVerificationFailed)
+}
+
+// denyReason returns a short description of the verification error.
+func denyReason(err error) string {
+	var errNoApplicablePolicy notation.ErrorNoApplicableTrustPolicy
+	var errNoSignature notation.ErrorSignatureRetrievalFailed
+	var errVerificationFailed notation.ErrorVerificationFailed
+	switch {
+	case errors.As(err, &errNoApplicablePolicy):
+		return "no applicable trust policy"
+	case errors.As(err, &errNoSignature):
+		return "failed to retrieve signatures"
+	case errors.As(err, &errVerificationFailed):
+		return "no valid signature found"
+	}
+	return "signature verification failed"
+}
+
+func deny(result ImageResult, reason string, err error) ImageResult {
+	result.Allowed = false
+	result.Reason = fmt.Sprintf("%s: %v", reason, err)
+	result.Error = err
+	return result
+}
+
+// repository returns the repository client of the named repository, creating
+// it on first use. Failures to create the client are not cached.
+func (v *Validator) repository(ctx context.Context, name string) (registry.Repository, error) {
+	v.mu.Lock()
+	entry, ok := v.repos[name]
+	if !ok {
+		entry = &repositoryEntry{}
+		v.repos[name] = entry
+	}
+	v.mu.Unlock()
+	entry.once.Do(func() {
+		entry.repo, entry.err = v.repoFunc(ctx, name)
+	})
+	if entry.err != nil {
+		v.mu.Lock()
+		if v.repos[name] == entry {
+			delete(v.repos, name)
+		}
+		v.mu.Unlock()
+		return nil, entry.err
+	}
+	return entry.repo, nil
+}
+
+// decisionCacheKey returns the cache key of the decision on the digest
+// reference for the tenant selected in ctx, if any.
+func decisionCacheKey(ctx context.Context, reference string) string {
+	if tenantID, ok := verifier.TenantFromContext(ctx); ok {
+		return tenantID + "|" + reference
+	}
+	return reference
+}
+
+// cachedResult returns the cached result of the cache key.
+func (v *Validator) cachedResult(key string) (ImageResult, bool) {
+	if v.cacheTTL <= 0 {
+		return ImageResult{}, false
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	cached, ok := v.decisions[key]
+	if !ok {
+		return ImageResult{}, false
+	}
+	if time.Now().After(cached.expiry) {
+		delete(v.decisions, key)
+		return ImageResult{}, false
+	}
+	return cached.result, true
+}
+
+// cacheResult caches the result by its cache key. If the cache is full, the
+// expired results are evicted first, followed by arbitrary results if
+// needed.
+func (v *Validator) cacheResult(key string, result ImageResult) {
+	if v.cacheTTL <= 0 {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := time.Now()
+	if _, ok := v.decisions[key]; !ok && len(v.decisions) >= v.cacheMaxEntries {
+		for reference, cached := range v.decisions {
+			if now.After(cached.expiry) {
+				delete(v.decisions, reference)
+			}
+		}
+		for reference := range v.decisions {
+			if len(v.decisions) < v.cacheMaxEntries {
+				break
+			}
+			delete(v.decisions, reference)
+		}
+	}
+	v.decisions[key] = cachedResult{
+		result: result,
+		expiry: now.Add(v.cacheTTL),
+	}
+}
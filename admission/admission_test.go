@@ -0,0 +1,256 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// testVerifier counts the verifications, fails the artifacts of the "denied"
+// repository and errors on the artifacts of the "flaky" repository.
+type testVerifier struct {
+	calls int
+}
+
+func (v *testVerifier) Verify(ctx context.Context, desc ocispec.Descriptor, signature []byte, opts notation.VerifierVerifyOptions) (*notation.VerificationOutcome, error) {
+	v.calls++
+	outcome := &notation.VerificationOutcome{VerificationLevel: trustpolicy.LevelStrict}
+	if strings.Contains(opts.ArtifactReference, "/denied@") {
+		outcome.Error = errors.New("signature is not trusted")
+		return outcome, outcome.Error
+	}
+	if strings.Contains(opts.ArtifactReference, "/flaky@") {
+		// no outcome, e.g. the verification plugin timed out
+		return nil, errors.New("plugin timed out")
+	}
+	return outcome, nil
+}
+
+func newTestValidator(t *testing.T, ttl time.Duration) (*Validator, *testVerifier) {
+	v := &testVerifier{}
+	validator, err := New(Options{
+		Verifier: v,
+		RepositoryFunc: func(ctx context.Context, repository string) (registry.Repository, error) {
+			if strings.HasSuffix(repository, "/unreachable") {
+				return nil, errors.New("connection refused")
+			}
+			return mock.NewRepository(), nil
+		},
+		CacheTTL: ttl,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return validator, v
+}
+
+func TestValidate(t *testing.T) {
+	validator, _ := newTestValidator(t, 0)
+	decision := validator.Validate(context.Background(), []string{"registry.acme-rockets.io/software/net-monitor:v1", "nginx"})
+	if !decision.Allowed {
+		t.Fatalf("Validate() denied: %s", decision.Message())
+	}
+	if decision.Message() != "" {
+		t.Fatalf("Message() = %q, want empty", decision.Message())
+	}
+	want := "registry.acme-rockets.io/software/net-monitor@" + mock.SampleDigest.String()
+	if decision.Results[0].Reference != want {
+		t.Fatalf("Validate() reference = %s, want %s", decision.Results[0].Reference, want)
+	}
+	if decision.Results[1].Reference != "docker.io/library/nginx@"+mock.SampleDigest.String() {
+		t.Fatalf("Validate() reference = %s", decision.Results[1].Reference)
+	}
+}
+
+func TestValidate_Deny(t *testing.T) {
+	validator, _ := newTestValidator(t, 0)
+	decision := validator.Validate(context.Background(), []string{
+		"registry.acme-rockets.io/software/net-monitor:v1",
+		"registry.acme-rockets.io/software/denied:v1",
+		"registry.acme-rockets.io/software/unreachable:v1",
+		"Invalid/Image",
+	})
+	if decision.Allowed {
+		t.Fatal("Validate() allowed denied images")
+	}
+	if !decision.Results[0].Allowed {
+		t.Fatalf("Validate() denied %s: %s", decision.Results[0].Image, decision.Results[0].Reason)
+	}
+	for _, result := range decision.Results[1:] {
+		if result.Allowed || result.Error == nil || result.Reason == "" {
+			t.Fatalf("Validate() result = %+v, want denied", result)
+		}
+	}
+	message := decision.Message()
+	for _, image := range []string{"denied:v1", "unreachable:v1", "Invalid/Image"} {
+		if !strings.Contains(message, image) {
+			t.Fatalf("Message() = %q, want it to mention %s", message, image)
+		}
+	}
+	if strings.Contains(message, "net-monitor") {
+		t.Fatalf("Message() = %q mentions the allowed image", message)
+	}
+}
+
+func TestValidate_Cache(t *testing.T) {
+	validator, v := newTestValidator(t, time.Hour)
+	ctx := context.Background()
+	images := []string{"registry.acme-rockets.io/software/net-monitor:v1", "registry.acme-rockets.io/software/net-monitor:v1"}
+	if decision := validator.Validate(ctx, images); !decision.Allowed {
+		t.Fatalf("Validate() denied: %s", decision.Message())
+	}
+	calls := v.calls
+	decision := validator.Validate(ctx, []string{"registry.acme-rockets.io/software/net-monitor:v2"})
+	if !decision.Allowed {
+		t.Fatalf("Validate() denied: %s", decision.Message())
+	}
+	if v.calls != calls {
+		t.Fatal("Validate() verified the cached digest again")
+	}
+	if decision.Results[0].Image != "registry.acme-rockets.io/software/net-monitor:v2" {
+		t.Fatalf("Validate() image = %s", decision.Results[0].Image)
+	}
+
+	uncached, v := newTestValidator(t, 0)
+	uncached.Validate(ctx, images[:1])
+	uncached.Validate(ctx, images[:1])
+	if v.calls != 2 {
+		t.Fatalf("Validate() verified %d times without cache, want 2", v.calls)
+	}
+}
+
+func TestValidate_CachePerTenant(t *testing.T) {
+	validator, v := newTestValidator(t, time.Hour)
+	images := []string{"registry.acme-rockets.io/software/net-monitor:v1"}
+	for _, tenantID := range []string{"team-a", "team-b", "team-a"} {
+		if decision := validator.Validate(verifier.WithTenant(context.Background(), tenantID), images); !decision.Allowed {
+			t.Fatalf("Validate() denied: %s", decision.Message())
+		}
+	}
+	if v.calls != 2 {
+		t.Fatalf("Validate() verified %d times, want 2 as the decisions are cached per tenant", v.calls)
+	}
+}
+
+func TestValidate_CacheDefinitiveOnly(t *testing.T) {
+	validator, v := newTestValidator(t, time.Hour)
+	ctx := context.Background()
+
+	// failed verifications are cached
+	for i := 0; i < 2; i++ {
+		if decision := validator.Validate(ctx, []string{"registry.acme-rockets.io/software/denied:v1"}); decision.Allowed {
+			t.Fatal("Validate() allowed a denied image")
+		}
+	}
+	if v.calls != 1 {
+		t.Fatalf("Validate() verified %d times, want 1 as the denial is cached", v.calls)
+	}
+
+	// errors are not cached
+	calls := v.calls
+	for i := 0; i < 2; i++ {
+		if decision := validator.Validate(ctx, []string{"registry.acme-rockets.io/software/flaky:v1"}); decision.Allowed {
+			t.Fatal("Validate() allowed an image failing verification")
+		}
+	}
+	if v.calls != calls+2 {
+		t.Fatalf("Validate() verified %d times, want 2 as errors are not cached", v.calls-calls)
+	}
+}
+
+func TestValidate_CacheMaxEntries(t *testing.T) {
+	v := &testVerifier{}
+	validator, err := New(Options{
+		Verifier: v,
+		RepositoryFunc: func(ctx context.Context, repository string) (registry.Repository, error) {
+			return mock.NewRepository(), nil
+		},
+		CacheTTL:        time.Hour,
+		CacheMaxEntries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	validator.Validate(ctx, []string{"registry.acme-rockets.io/software/a:v1"})
+	validator.Validate(ctx, []string{"registry.acme-rockets.io/software/b:v1"})
+	if len(validator.decisions) != 1 {
+		t.Fatalf("Validate() cached %d decisions, want 1", len(validator.decisions))
+	}
+	validator.Validate(ctx, []string{"registry.acme-rockets.io/software/b:v1"})
+	if v.calls != 2 {
+		t.Fatalf("Validate() verified %d times, want 2", v.calls)
+	}
+	validator.Validate(ctx, []string{"registry.acme-rockets.io/software/a:v1"})
+	if v.calls != 3 {
+		t.Fatalf("Validate() verified %d times, want 3 as the decision was evicted", v.calls)
+	}
+}
+
+func TestValidator_Repository(t *testing.T) {
+	release := make(chan struct{})
+	var failures int
+	validator, err := New(Options{
+		Verifier: &testVerifier{},
+		RepositoryFunc: func(ctx context.Context, repository string) (registry.Repository, error) {
+			switch repository {
+			case "slow":
+				<-release
+			case "unreachable":
+				failures++
+				return nil, errors.New("connection refused")
+			}
+			return mock.NewRepository(), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// creating a slow repository client does not block the others
+	done := make(chan error)
+	go func() {
+		_, err := validator.repository(ctx, "slow")
+		done <- err
+	}()
+	if _, err := validator.repository(ctx, "fast"); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// failures are retried
+	for i := 0; i < 2; i++ {
+		if _, err := validator.repository(ctx, "unreachable"); err == nil {
+			t.Fatal("expected error for unreachable repository")
+		}
+	}
+	if failures != 2 {
+		t.Fatalf("repository() created the unreachable repository %d times, want 2", failures)
+	}
+}
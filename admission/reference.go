@@ -0,0 +1,69 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"strings"
+
+	orasRegistry "oras.land/oras-go/v2/registry"
+)
+
+const (
+	// defaultRegistry is the registry of the images without registry.
+	defaultRegistry = "docker.io"
+
+	// defaultNamespace is the namespace of the single-component images of
+	// the default registry.
+	defaultNamespace = "library"
+
+	// defaultTag is the tag of the images without tag and digest.
+	defaultTag = "latest"
+)
+
+// NormalizeReference normalizes a container image reference the way the
+// container runtimes do, e.g. "nginx" becomes
+// "docker.io/library/nginx:latest" and "ghcr.io/org/app" becomes
+// "ghcr.io/org/app:latest".
+//
+// If the reference has both a tag and a digest, the tag is dropped.
+func NormalizeReference(image string) (orasRegistry.Reference, error) {
+	domain, remainder, found := strings.Cut(image, "/")
+	if !found || (!strings.ContainsAny(domain, ".:") && domain != "localhost") {
+		// no registry
+		domain, remainder = defaultRegistry, image
+	}
+	if domain == "index.docker.io" {
+		domain = defaultRegistry
+	}
+	if domain == defaultRegistry && !strings.Contains(remainder, "/") {
+		remainder = defaultNamespace + "/" + remainder
+	}
+	name := domain + "/" + remainder
+
+	// drop the tag of a reference with both a tag and a digest
+	if repo, dgst, ok := strings.Cut(name, "@"); ok {
+		if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+			name = repo[:i] + "@" + dgst
+		}
+	}
+
+	ref, err := orasRegistry.ParseReference(name)
+	if err != nil {
+		return orasRegistry.Reference{}, err
+	}
+	if ref.Reference == "" {
+		ref.Reference = defaultTag
+	}
+	return ref, nil
+}
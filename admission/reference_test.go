@@ -0,0 +1,56 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import "testing"
+
+func TestNormalizeReference(t *testing.T) {
+	dgst := "sha256:60043cf45eaebc4c0867fea485a039b598f52fd09fd5b07b0b2d2f88fad9d74e"
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"nginx", "docker.io/library/nginx:latest"},
+		{"nginx:1.25", "docker.io/library/nginx:1.25"},
+		{"library/nginx", "docker.io/library/nginx:latest"},
+		{"docker.io/nginx", "docker.io/library/nginx:latest"},
+		{"index.docker.io/org/app:v1", "docker.io/org/app:v1"},
+		{"org/app", "docker.io/org/app:latest"},
+		{"ghcr.io/org/app", "ghcr.io/org/app:latest"},
+		{"localhost/app:v1", "localhost/app:v1"},
+		{"localhost:5000/app", "localhost:5000/app:latest"},
+		{"nginx@" + dgst, "docker.io/library/nginx@" + dgst},
+		{"ghcr.io/org/app:v1@" + dgst, "ghcr.io/org/app@" + dgst},
+		{"localhost:5000/app:v1@" + dgst, "localhost:5000/app@" + dgst},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := NormalizeReference(tt.image)
+			if err != nil {
+				t.Fatalf("NormalizeReference() error = %v", err)
+			}
+			if got := ref.String(); got != tt.want {
+				t.Fatalf("NormalizeReference() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeReference_Error(t *testing.T) {
+	for _, image := range []string{"", "Invalid/Upper", "ghcr.io/org/app@sha256:invalid"} {
+		if _, err := NormalizeReference(image); err == nil {
+			t.Errorf("NormalizeReference(%q) expected error", image)
+		}
+	}
+}
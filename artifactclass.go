@@ -0,0 +1,149 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orasRegistry "oras.land/oras-go/v2/registry"
+)
+
+// ArtifactClass describes a class of non-container artifacts, e.g. Helm
+// charts, and the recommended settings to sign them with [SignArtifact].
+type ArtifactClass struct {
+	// Name is the name of the class, e.g. "helm chart".
+	Name string
+
+	// ArtifactTypes are the artifact types of the class. The artifact type
+	// of an image manifest without artifact type is the media type of its
+	// config. If empty, any artifact type but the container image config
+	// types is accepted.
+	ArtifactTypes []string
+
+	// Annotations are the manifest annotations of the artifact recorded in
+	// the user metadata of the signature, e.g. the name and the version of
+	// a Helm chart, so that they are signed. The annotations missing from
+	// the manifest are ignored.
+	Annotations []string
+}
+
+// Artifact classes with recommended signing settings.
+var (
+	// HelmChart is the class of the Helm charts pushed with "helm push".
+	HelmChart = ArtifactClass{
+		Name:          "helm chart",
+		ArtifactTypes: []string{"application/vnd.cncf.helm.config.v1+json"},
+		Annotations:   []string{ocispec.AnnotationTitle, ocispec.AnnotationVersion},
+	}
+
+	// WASMModule is the class of the WebAssembly modules, as specified by
+	// the CNCF TAG Runtime Wasm OCI artifact layout and by wasm-to-oci.
+	WASMModule = ArtifactClass{
+		Name:          "wasm module",
+		ArtifactTypes: []string{"application/vnd.wasm.config.v0+json", "application/vnd.wasm.config.v1+json"},
+		Annotations:   []string{ocispec.AnnotationTitle, ocispec.AnnotationVersion},
+	}
+
+	// ORASFile is the class of the files pushed with "oras push", with any
+	// artifact type.
+	ORASFile = ArtifactClass{
+		Name:        "oras file",
+		Annotations: []string{ocispec.AnnotationTitle, ocispec.AnnotationCreated},
+	}
+)
+
+// containerImageConfigTypes are the artifact types of container images.
+var containerImageConfigTypes = []string{
+	ocispec.MediaTypeImageConfig,
+	"application/vnd.docker.container.image.v1+json",
+}
+
+// accepts returns an error if the artifact type is not of the class.
+func (c ArtifactClass) accepts(artifactType string) error {
+	if len(c.ArtifactTypes) == 0 {
+		if slices.Contains(containerImageConfigTypes, artifactType) {
+			return fmt.Errorf("artifact is a container image, not a %s", c.Name)
+		}
+		return nil
+	}
+	if !slices.Contains(c.ArtifactTypes, artifactType) {
+		return fmt.Errorf("artifact type %q is not a %s artifact type %q", artifactType, c.Name, c.ArtifactTypes)
+	}
+	return nil
+}
+
+// SignArtifact signs the artifact signOpts.ArtifactReference of the artifact
+// class, e.g. [HelmChart], and pushes the signature to the Repository.
+//
+// The artifact reference is resolved to the digest of the artifact, whose
+// artifact type must be of the class. The manifest annotations of the
+// artifact listed by the class are recorded in the user metadata of the
+// signature, unless already set in signOpts.UserMetadata. The Repository
+// must be able to describe artifacts, as the repositories returned by
+// [registry.NewRepository] are.
+//
+// Both artifact and signature manifest descriptors are returned upon
+// successful signing, as in [SignOCI].
+func SignArtifact(ctx context.Context, signer Signer, repo registry.Repository, class ArtifactClass, signOpts SignOptions) (ocispec.Descriptor, ocispec.Descriptor, error) {
+	// sanity check
+	if err := validateSignArguments(signer, signOpts.SignerSignOptions); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+	if repo == nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, errors.New("repo cannot be nil")
+	}
+	describer, ok := repo.(artifactDescriber)
+	if !ok {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, errors.New("repo cannot describe artifacts")
+	}
+	ref, err := orasRegistry.ParseReference(signOpts.ArtifactReference)
+	if err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+	if ref.Reference == "" {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, errors.New("reference is missing digest or tag")
+	}
+	artifactManifestDesc, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, fmt.Errorf("failed to resolve %q: %w", signOpts.ArtifactReference, err)
+	}
+	described, err := describer.DescribeArtifact(ctx, artifactManifestDesc)
+	if err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, fmt.Errorf("failed to describe %q: %w", signOpts.ArtifactReference, err)
+	}
+	if err := class.accepts(described.ArtifactType); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+
+	userMetadata := make(map[string]string)
+	for _, key := range class.Annotations {
+		if value, ok := described.Annotations[key]; ok {
+			userMetadata[key] = value
+		}
+	}
+	maps.Copy(userMetadata, signOpts.UserMetadata)
+	if len(userMetadata) > 0 {
+		signOpts.UserMetadata = userMetadata
+	}
+	// pin the resolved artifact
+	ref.Reference = artifactManifestDesc.Digest.String()
+	signOpts.ArtifactReference = ref.String()
+	return SignDescriptor(ctx, signer, repo, artifactManifestDesc, signOpts)
+}
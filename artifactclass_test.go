@@ -0,0 +1,144 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// pushClassArtifact pushes an image manifest with the config media type and
+// the annotations to store, tagged with tag.
+func pushClassArtifact(t *testing.T, store *memory.Store, configMediaType, tag string, annotations map[string]string) ocispec.Descriptor {
+	t.Helper()
+	ctx := context.Background()
+	config := content.NewDescriptorFromBytes(configMediaType, []byte("{}"))
+	manifestJSON, err := json.Marshal(ocispec.Manifest{
+		Versioned:   specs.Versioned{SchemaVersion: 2},
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Config:      config,
+		Layers:      []ocispec.Descriptor{ocispec.DescriptorEmptyJSON},
+		Annotations: annotations,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifestJSON)
+	if err := store.Push(ctx, desc, bytes.NewReader(manifestJSON)); err != nil {
+		t.Fatal(err)
+	}
+	for _, reference := range []string{desc.Digest.String(), tag} {
+		if err := store.Tag(ctx, desc, reference); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return desc
+}
+
+func TestSignArtifact(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	repo := registry.NewRepository(store)
+	chartDesc := pushClassArtifact(t, store, "application/vnd.cncf.helm.config.v1+json", "0.1.0", map[string]string{
+		ocispec.AnnotationTitle:       "net-monitor",
+		ocispec.AnnotationVersion:     "0.1.0",
+		ocispec.AnnotationDescription: "not recorded",
+	})
+	imageDesc := pushClassArtifact(t, store, ocispec.MediaTypeImageConfig, "v1", nil)
+	signer, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: "registry.acme-rockets.io/charts/net-monitor:0.1.0",
+		UserMetadata:      map[string]string{ocispec.AnnotationVersion: "v0.1.0", "team": "rockets"},
+	}
+
+	artifactDesc, sigManifestDesc, err := notation.SignArtifact(ctx, signer, repo, notation.HelmChart, signOpts)
+	if err != nil {
+		t.Fatalf("SignArtifact() error = %v", err)
+	}
+	if artifactDesc.Digest != chartDesc.Digest {
+		t.Fatalf("SignArtifact() artifact = %v, want %v", artifactDesc.Digest, chartDesc.Digest)
+	}
+	sig, sigDesc, err := repo.FetchSignatureBlob(ctx, sigManifestDesc)
+	if err != nil {
+		t.Fatalf("FetchSignatureBlob() error = %v", err)
+	}
+	target, err := notation.CheckSignatureEnvelope(sigDesc.MediaType, sig)
+	if err != nil {
+		t.Fatalf("CheckSignatureEnvelope() error = %v", err)
+	}
+	want := map[string]string{
+		ocispec.AnnotationTitle:   "net-monitor",
+		ocispec.AnnotationVersion: "v0.1.0",
+		"team":                    "rockets",
+	}
+	if len(target.Annotations) != len(want) {
+		t.Fatalf("signed annotations = %v, want %v", target.Annotations, want)
+	}
+	for k, v := range want {
+		if target.Annotations[k] != v {
+			t.Fatalf("signed annotations = %v, want %v", target.Annotations, want)
+		}
+	}
+
+	// any artifact but container images is an oras file
+	if _, _, err := notation.SignArtifact(ctx, signer, repo, notation.ORASFile, signOpts); err != nil {
+		t.Fatalf("SignArtifact() oras file error = %v", err)
+	}
+	imageOpts := signOpts
+	imageOpts.ArtifactReference = "registry.acme-rockets.io/charts/net-monitor@" + imageDesc.Digest.String()
+	if _, _, err := notation.SignArtifact(ctx, signer, repo, notation.ORASFile, imageOpts); err == nil {
+		t.Fatal("SignArtifact() expects error for a container image signed as an oras file")
+	}
+	if _, _, err := notation.SignArtifact(ctx, signer, repo, notation.WASMModule, signOpts); err == nil {
+		t.Fatal("SignArtifact() expects error for a helm chart signed as a wasm module")
+	}
+}
+
+func TestSignArtifactErrors(t *testing.T) {
+	ctx := context.Background()
+	signer, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: "registry.acme-rockets.io/charts/net-monitor:0.1.0",
+	}
+	if _, _, err := notation.SignArtifact(ctx, signer, nil, notation.HelmChart, signOpts); err == nil {
+		t.Fatal("SignArtifact() expects error for nil repo")
+	}
+	repo := registry.NewRepository(memory.New())
+	for _, reference := range []string{"", "registry.acme-rockets.io/charts/net-monitor", "registry.acme-rockets.io/charts/net-monitor:0.1.0"} {
+		opts := signOpts
+		opts.ArtifactReference = reference
+		if _, _, err := notation.SignArtifact(ctx, signer, repo, notation.HelmChart, opts); err == nil {
+			t.Fatalf("SignArtifact(%q) expects error, got nil", reference)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/notaryproject/notation-go/log"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// VerificationSummaryPredicateType is the predicate type of the SLSA
+	// verification summary attestations created by
+	// [NewVerificationSummaryAttestation].
+	// See https://slsa.dev/spec/v1.0/verification_summary
+	VerificationSummaryPredicateType = "https://slsa.dev/verification_summary/v1"
+
+	// InTotoPayloadType is the DSSE payload type of in-toto statements.
+	InTotoPayloadType = "application/vnd.in-toto+json"
+)
+
+// Verification results of a [VerificationSummary].
+const (
+	VerificationPassed = "PASSED"
+	VerificationFailed = "FAILED"
+)
+
+// VerificationSummaryOptions contains parameters for
+// [NewVerificationSummary] and [NewVerificationSummaryAttestation].
+type VerificationSummaryOptions struct {
+	// ArtifactReference is the reference of the verified artifact, recorded
+	// as the resource URI of the summary.
+	ArtifactReference string
+
+	// ArtifactDescriptor is the descriptor of the verified artifact, as
+	// returned by [notation.Verify]. It is the subject of the attestation.
+	ArtifactDescriptor ocispec.Descriptor
+
+	// VerifierID is the URI identifying the verifier, e.g. the admission
+	// controller performing the verification. Required.
+	VerifierID string
+
+	// VerifierVersion is the version of the verifier components, e.g.
+	// {"notation-go": "v1.3.0"}.
+	VerifierVersion map[string]string
+
+	// PolicyURI is the URI of the trust policy the artifact is verified
+	// against. Required.
+	PolicyURI string
+
+	// PolicyDigest is the digest of the trust policy, e.g.
+	// {"sha256": "..."}.
+	PolicyDigest map[string]string
+
+	// VerifiedLevels are the levels verified for the artifact if the
+	// verification passed, e.g. "SLSA_BUILD_LEVEL_2".
+	VerifiedLevels []string
+
+	// TimeVerified is the time of the verification. If zero, the current
+	// time is used.
+	TimeVerified time.Time
+
+	// KeyID is the identifier of the signing key recorded in the signature
+	// of the attestation. It is optional.
+	KeyID string
+}
+
+// VerificationSummary is the predicate of a SLSA verification summary
+// attestation.
+type VerificationSummary struct {
+	// Verifier identifies the verifier.
+	Verifier VerificationSummaryVerifier `json:"verifier"`
+
+	// TimeVerified is the time of the verification.
+	TimeVerified time.Time `json:"timeVerified"`
+
+	// ResourceURI is the URI of the verified artifact.
+	ResourceURI string `json:"resourceUri"`
+
+	// Policy is the policy the artifact is verified against.
+	Policy ResourceDescriptor `json:"policy"`
+
+	// InputAttestations are the signatures verified, identified by the
+	// digest of their envelopes.
+	InputAttestations []ResourceDescriptor `json:"inputAttestations,omitempty"`
+
+	// VerificationResult is either [VerificationPassed] or
+	// [VerificationFailed].
+	VerificationResult string `json:"verificationResult"`
+
+	// VerifiedLevels are the levels verified for the artifact.
+	VerifiedLevels []string `json:"verifiedLevels"`
+}
+
+// VerificationSummaryVerifier identifies the verifier of a
+// [VerificationSummary].
+type VerificationSummaryVerifier struct {
+	// ID is the URI identifying the verifier.
+	ID string `json:"id"`
+
+	// Version is the version of the verifier components.
+	Version map[string]string `json:"version,omitempty"`
+}
+
+// ResourceDescriptor describes a resource of an in-toto attestation.
+type ResourceDescriptor struct {
+	// URI of the resource.
+	URI string `json:"uri,omitempty"`
+
+	// Digest of the resource, e.g. {"sha256": "..."}.
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// DSSEEnvelope is a DSSE envelope signing an in-toto statement.
+// See https://github.com/secure-systems-lab/dsse/blob/master/envelope.md
+type DSSEEnvelope struct {
+	// PayloadType is the type of the payload, [InTotoPayloadType].
+	PayloadType string `json:"payloadType"`
+
+	// Payload is the serialized in-toto statement.
+	Payload []byte `json:"payload"`
+
+	// Signatures are the signatures of the payload.
+	Signatures []DSSESignature `json:"signatures"`
+}
+
+// DSSESignature is a signature of a [DSSEEnvelope].
+type DSSESignature struct {
+	// KeyID identifies the signing key.
+	KeyID string `json:"keyid,omitempty"`
+
+	// Sig is the signature of the pre-authentication encoding of the
+	// payload.
+	Sig []byte `json:"sig"`
+}
+
+// NewVerificationSummary returns the SLSA verification summary of the
+// verification outcome of the artifact. The verification passed if the
+// outcome, and the outcome of its endorsement if any, have no error.
+func NewVerificationSummary(outcome *VerificationOutcome, opts VerificationSummaryOptions) (*VerificationSummary, error) {
+	if outcome == nil {
+		return nil, errors.New("verification outcome cannot be nil")
+	}
+	if opts.VerifierID == "" {
+		return nil, errors.New("verifier ID cannot be empty")
+	}
+	if opts.PolicyURI == "" {
+		return nil, errors.New("policy URI cannot be empty")
+	}
+	if err := opts.ArtifactDescriptor.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid artifact descriptor: %w", err)
+	}
+	timeVerified := opts.TimeVerified
+	if timeVerified.IsZero() {
+		timeVerified = time.Now()
+	}
+	summary := &VerificationSummary{
+		Verifier: VerificationSummaryVerifier{
+			ID:      opts.VerifierID,
+			Version: opts.VerifierVersion,
+		},
+		TimeVerified: timeVerified.UTC(),
+		ResourceURI:  opts.ArtifactReference,
+		Policy: ResourceDescriptor{
+			URI:    opts.PolicyURI,
+			Digest: opts.PolicyDigest,
+		},
+		VerificationResult: VerificationFailed,
+		VerifiedLevels:     []string{},
+	}
+	for o := outcome; o != nil; o = o.Endorsement {
+		if len(o.RawSignature) > 0 {
+			sum := sha256.Sum256(o.RawSignature)
+			summary.InputAttestations = append(summary.InputAttestations, ResourceDescriptor{
+				Digest: map[string]string{"sha256": hex.EncodeToString(sum[:])},
+			})
+		}
+	}
+	if verificationPassed(outcome) {
+		summary.VerificationResult = VerificationPassed
+		summary.VerifiedLevels = append(summary.VerifiedLevels, opts.VerifiedLevels...)
+	}
+	return summary, nil
+}
+
+// verificationPassed returns true if the outcome, and the outcome of its
+// endorsement if any, have no error.
+func verificationPassed(outcome *VerificationOutcome) bool {
+	for o := outcome; o != nil; o = o.Endorsement {
+		if o.Error != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// NewVerificationSummaryAttestation returns the SLSA verification summary
+// of the verification outcome of the artifact as an in-toto statement signed
+// by signer in a DSSE envelope, so that the verification result can be
+// consumed by the policy engines as supply chain evidence.
+//
+// The RSA, ECDSA and Ed25519 keys are supported. The RSA keys sign with
+// RSASSA-PSS.
+func NewVerificationSummaryAttestation(ctx context.Context, outcome *VerificationOutcome, signer crypto.Signer, opts VerificationSummaryOptions) (*DSSEEnvelope, error) {
+	if signer == nil {
+		return nil, errors.New("signer cannot be nil")
+	}
+	summary, err := NewVerificationSummary(outcome, opts)
+	if err != nil {
+		return nil, err
+	}
+	desc := opts.ArtifactDescriptor
+	name := opts.ArtifactReference
+	if name == "" {
+		name = desc.Digest.String()
+	}
+	payload, err := json.Marshal(inTotoStatement{
+		Type: InTotoStatementType,
+		Subject: []inTotoSubject{{
+			Name:   name,
+			Digest: map[string]string{desc.Digest.Algorithm().String(): desc.Digest.Encoded()},
+		}},
+		PredicateType: VerificationSummaryPredicateType,
+		Predicate:     summary,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the verification summary: %w", err)
+	}
+	sig, err := signDSSE(signer, InTotoPayloadType, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the verification summary: %w", err)
+	}
+	log.GetLogger(ctx).Debugf("Verification summary attestation of %s signed, result %s", name, summary.VerificationResult)
+	return &DSSEEnvelope{
+		PayloadType: InTotoPayloadType,
+		Payload:     payload,
+		Signatures: []DSSESignature{{
+			KeyID: opts.KeyID,
+			Sig:   sig,
+		}},
+	}, nil
+}
+
+// dssePAE returns the DSSE pre-authentication encoding of the payload.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// signDSSE signs the DSSE pre-authentication encoding of the payload.
+func signDSSE(signer crypto.Signer, payloadType string, payload []byte) ([]byte, error) {
+	pae := dssePAE(payloadType, payload)
+	hash, signerOpts, err := dsseSignerOpts(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	if hash == 0 {
+		return signer.Sign(rand.Reader, pae, signerOpts)
+	}
+	h := hash.New()
+	h.Write(pae)
+	return signer.Sign(rand.Reader, h.Sum(nil), signerOpts)
+}
+
+// dsseSignerOpts returns the hash and the signer options of the public key,
+// following the key specs of the Notary Project signatures. A zero hash
+// means that the message is signed without hashing.
+func dsseSignerOpts(publicKey crypto.PublicKey) (crypto.Hash, crypto.SignerOpts, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		var hash crypto.Hash
+		switch key.Size() * 8 {
+		case 2048:
+			hash = crypto.SHA256
+		case 3072:
+			hash = crypto.SHA384
+		case 4096:
+			hash = crypto.SHA512
+		default:
+			return 0, nil, fmt.Errorf("RSA key size %d bits is not supported", key.Size()*8)
+		}
+		return hash, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}, nil
+	case *ecdsa.PublicKey:
+		var hash crypto.Hash
+		switch key.Curve.Params().BitSize {
+		case 256:
+			hash = crypto.SHA256
+		case 384:
+			hash = crypto.SHA384
+		case 521:
+			hash = crypto.SHA512
+		default:
+			return 0, nil, fmt.Errorf("EC key size %d bits is not supported", key.Curve.Params().BitSize)
+		}
+		return hash, hash, nil
+	case ed25519.PublicKey:
+		return 0, crypto.Hash(0), nil
+	default:
+		return 0, nil, fmt.Errorf("key type %T is not supported", publicKey)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func dummyVerificationSummaryOptions() VerificationSummaryOptions {
+	return VerificationSummaryOptions{
+		ArtifactReference:  mock.SampleArtifactUri,
+		ArtifactDescriptor: mock.ImageDescriptor,
+		VerifierID:         "https://example.com/admission-controller",
+		VerifierVersion:    map[string]string{"notation-go": "v1.3.0"},
+		PolicyURI:          "https://example.com/trustpolicy.oci.json",
+		PolicyDigest:       map[string]string{"sha256": "0f7b2a"},
+		VerifiedLevels:     []string{"SLSA_BUILD_LEVEL_2"},
+		TimeVerified:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestNewVerificationSummary(t *testing.T) {
+	opts := dummyVerificationSummaryOptions()
+	outcome := &VerificationOutcome{RawSignature: []byte("signature")}
+	summary, err := NewVerificationSummary(outcome, opts)
+	if err != nil {
+		t.Fatalf("NewVerificationSummary() error = %v", err)
+	}
+	want := &VerificationSummary{
+		Verifier: VerificationSummaryVerifier{
+			ID:      opts.VerifierID,
+			Version: opts.VerifierVersion,
+		},
+		TimeVerified: opts.TimeVerified,
+		ResourceURI:  mock.SampleArtifactUri,
+		Policy: ResourceDescriptor{
+			URI:    opts.PolicyURI,
+			Digest: opts.PolicyDigest,
+		},
+		InputAttestations: []ResourceDescriptor{{
+			Digest: map[string]string{"sha256": digest.FromBytes([]byte("signature")).Encoded()},
+		}},
+		VerificationResult: VerificationPassed,
+		VerifiedLevels:     []string{"SLSA_BUILD_LEVEL_2"},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Fatalf("NewVerificationSummary() = %+v, want %+v", summary, want)
+	}
+
+	// failed endorsement
+	outcome.Endorsement = &VerificationOutcome{RawSignature: []byte("countersignature"), Error: errors.New("untrusted")}
+	summary, err = NewVerificationSummary(outcome, opts)
+	if err != nil {
+		t.Fatalf("NewVerificationSummary() error = %v", err)
+	}
+	if summary.VerificationResult != VerificationFailed || len(summary.VerifiedLevels) != 0 || len(summary.InputAttestations) != 2 {
+		t.Fatalf("NewVerificationSummary() = %+v, want failed summary", summary)
+	}
+
+	for _, modify := range []func(*VerificationSummaryOptions){
+		func(o *VerificationSummaryOptions) { o.VerifierID = "" },
+		func(o *VerificationSummaryOptions) { o.PolicyURI = "" },
+		func(o *VerificationSummaryOptions) { o.ArtifactDescriptor = ocispec.Descriptor{} },
+	} {
+		opts := dummyVerificationSummaryOptions()
+		modify(&opts)
+		if _, err := NewVerificationSummary(outcome, opts); err == nil {
+			t.Fatalf("NewVerificationSummary(%+v) expects error", opts)
+		}
+	}
+	if _, err := NewVerificationSummary(nil, opts); err == nil {
+		t.Fatal("NewVerificationSummary() expects error for nil outcome")
+	}
+}
+
+func TestNewVerificationSummaryAttestation(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		signer crypto.Signer
+		verify func(pae, sig []byte) bool
+	}{
+		{
+			name:   "ecdsa",
+			signer: ecKey,
+			verify: func(pae, sig []byte) bool {
+				h := crypto.SHA384.New()
+				h.Write(pae)
+				return ecdsa.VerifyASN1(&ecKey.PublicKey, h.Sum(nil), sig)
+			},
+		},
+		{
+			name:   "rsa",
+			signer: rsaKey,
+			verify: func(pae, sig []byte) bool {
+				h := crypto.SHA256.New()
+				h.Write(pae)
+				return rsa.VerifyPSS(&rsaKey.PublicKey, crypto.SHA256, h.Sum(nil), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+			},
+		},
+		{
+			name:   "ed25519",
+			signer: edKey,
+			verify: func(pae, sig []byte) bool {
+				return ed25519.Verify(edKey.Public().(ed25519.PublicKey), pae, sig)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := dummyVerificationSummaryOptions()
+			opts.KeyID = "attestor"
+			env, err := NewVerificationSummaryAttestation(context.Background(), &VerificationOutcome{RawSignature: []byte("signature")}, tt.signer, opts)
+			if err != nil {
+				t.Fatalf("NewVerificationSummaryAttestation() error = %v", err)
+			}
+			if env.PayloadType != InTotoPayloadType || len(env.Signatures) != 1 || env.Signatures[0].KeyID != "attestor" {
+				t.Fatalf("NewVerificationSummaryAttestation() = %+v", env)
+			}
+			if !tt.verify(dssePAE(env.PayloadType, env.Payload), env.Signatures[0].Sig) {
+				t.Fatal("NewVerificationSummaryAttestation() signature does not verify")
+			}
+
+			var statement struct {
+				Type          string              `json:"_type"`
+				Subject       []inTotoSubject     `json:"subject"`
+				PredicateType string              `json:"predicateType"`
+				Predicate     VerificationSummary `json:"predicate"`
+			}
+			if err := json.Unmarshal(env.Payload, &statement); err != nil {
+				t.Fatalf("failed to unmarshal the statement: %v", err)
+			}
+			if statement.Type != InTotoStatementType || statement.PredicateType != VerificationSummaryPredicateType {
+				t.Fatalf("statement = %+v", statement)
+			}
+			if len(statement.Subject) != 1 || statement.Subject[0].Name != mock.SampleArtifactUri || statement.Subject[0].Digest["sha256"] != mock.ImageDescriptor.Digest.Encoded() {
+				t.Fatalf("statement subject = %+v", statement.Subject)
+			}
+			if statement.Predicate.VerificationResult != VerificationPassed || !statement.Predicate.TimeVerified.Equal(opts.TimeVerified) {
+				t.Fatalf("statement predicate = %+v", statement.Predicate)
+			}
+		})
+	}
+
+	if _, err := NewVerificationSummaryAttestation(context.Background(), &VerificationOutcome{}, nil, dummyVerificationSummaryOptions()); err == nil {
+		t.Fatal("NewVerificationSummaryAttestation() expects error for nil signer")
+	}
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewVerificationSummaryAttestation(context.Background(), &VerificationOutcome{}, smallKey, dummyVerificationSummaryOptions()); err == nil {
+		t.Fatal("NewVerificationSummaryAttestation() expects error for unsupported key size")
+	}
+}
+
+func TestDSSEPAE(t *testing.T) {
+	// test vector of the DSSE specification
+	got := string(dssePAE("http://example.com/HelloWorld", []byte("hello world")))
+	want := "DSSEv1 29 http://example.com/HelloWorld 11 hello world"
+	if got != want {
+		t.Fatalf("dssePAE() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/audit"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// auditArtifactSigned emits the audit event of the signature of the artifact
+// pushed to the repository.
+func auditArtifactSigned(ctx context.Context, repository, reference string, artifactDesc, sigManifestDesc ocispec.Descriptor, signerInfo *signature.SignerInfo) {
+	if !audit.Enabled(ctx) {
+		return
+	}
+	event := audit.Event{
+		Type:     audit.EventArtifactSigned,
+		Artifact: artifactDesc.Digest.String(),
+		Attributes: map[string]string{
+			"reference": reference,
+		},
+	}
+	if sigManifestDesc.Digest != "" {
+		event.Attributes["signature"] = sigManifestDesc.Digest.String()
+	}
+	if repository != "" {
+		event.Attributes["repository"] = repository
+	}
+	if signerInfo != nil && len(signerInfo.CertificateChain) > 0 {
+		thumbprint := sha256.Sum256(signerInfo.CertificateChain[0].Raw)
+		event.Attributes["certificateThumbprint"] = hex.EncodeToString(thumbprint[:])
+	}
+	audit.Emit(ctx, event)
+}
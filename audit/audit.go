@@ -0,0 +1,151 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit provides a structured event stream of the security decisions
+// made by notation, such as accepting or rejecting a signature.
+// Users who want to receive the events should implement the audit.Sink
+// interface and include it in context by calling audit.WithSink.
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+type contextKey int
+
+// sinkKey is the associated key type for sink entry in context.
+const sinkKey contextKey = iota
+
+// EventType is the type of an audit event.
+type EventType string
+
+// Types of the audit events.
+const (
+	// EventKeyLoaded is emitted when a signing key is loaded to sign an
+	// artifact.
+	EventKeyLoaded EventType = "key.loaded"
+
+	// EventArtifactSigned is emitted when the signature of an artifact is
+	// pushed to the repository.
+	EventArtifactSigned EventType = "artifact.signed"
+
+	// EventPluginExecuted is emitted when a plugin command is executed.
+	EventPluginExecuted EventType = "plugin.executed"
+
+	// EventSignatureAccepted is emitted when a signature is accepted by the
+	// verifier.
+	EventSignatureAccepted EventType = "signature.accepted"
+
+	// EventSignatureRejected is emitted when a signature is rejected by the
+	// verifier. The reason is set.
+	EventSignatureRejected EventType = "signature.rejected"
+
+	// EventRevocationChecked is emitted when the revocation status of the
+	// certificate chain of a signature is checked.
+	EventRevocationChecked EventType = "revocation.checked"
+)
+
+// Results of the audit events.
+const (
+	// ResultSuccess is the result of a successful plugin execution.
+	ResultSuccess = "success"
+
+	// ResultFailure is the result of a failed plugin execution.
+	ResultFailure = "failure"
+
+	// ResultError is the result of a revocation check that could not be
+	// completed.
+	ResultError = "error"
+)
+
+// Event is an audit event.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType `json:"type"`
+
+	// Time is the time of the event.
+	Time time.Time `json:"time"`
+
+	// Artifact is the digest of the artifact, if known.
+	Artifact string `json:"artifact,omitempty"`
+
+	// Plugin is the name of the plugin, if any.
+	Plugin string `json:"plugin,omitempty"`
+
+	// Command is the plugin command of [EventPluginExecuted] events.
+	Command string `json:"command,omitempty"`
+
+	// KeyID is the ID of the signing key of a plugin, if any.
+	KeyID string `json:"keyId,omitempty"`
+
+	// Result is the result of the decision, e.g. the revocation status of
+	// [EventRevocationChecked] events, or the verification level of the
+	// signature verification events.
+	Result string `json:"result,omitempty"`
+
+	// Reason is the reason of a rejection or of a failure.
+	Reason string `json:"reason,omitempty"`
+
+	// Attributes are additional attributes of the event.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Sink receives the audit events. It is implemented by users, e.g. to
+// forward the events to a SIEM. Emit is called synchronously and must not
+// block.
+type Sink interface {
+	// Emit emits an audit event.
+	Emit(ctx context.Context, event Event)
+}
+
+// SinkFunc is an adapter to allow the use of ordinary functions as [Sink].
+type SinkFunc func(ctx context.Context, event Event)
+
+// Emit calls f(ctx, event).
+func (f SinkFunc) Emit(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+// WithSink is used by callers to set the Sink in the context.
+// It enables the audit events in notation.
+func WithSink(ctx context.Context, sink Sink) context.Context {
+	return context.WithValue(ctx, sinkKey, sink)
+}
+
+// GetSink returns the Sink in the context, or nil if the audit events are
+// not enabled.
+func GetSink(ctx context.Context) Sink {
+	sink, _ := ctx.Value(sinkKey).(Sink)
+	return sink
+}
+
+// Emit emits the event to the Sink in the context, if any. The time of the
+// event is set if it is zero.
+func Emit(ctx context.Context, event Event) {
+	sink, ok := ctx.Value(sinkKey).(Sink)
+	if !ok || sink == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	sink.Emit(ctx, event)
+}
+
+// Enabled reports whether a Sink is set in the context, so that costly
+// attributes are computed only if needed.
+func Enabled(ctx context.Context) bool {
+	sink, ok := ctx.Value(sinkKey).(Sink)
+	return ok && sink != nil
+}
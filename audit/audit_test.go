@@ -0,0 +1,71 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEmit(t *testing.T) {
+	ctx := context.Background()
+	if Enabled(ctx) {
+		t.Fatal("Enabled() = true without sink")
+	}
+	// no sink, no panic
+	Emit(ctx, Event{Type: EventKeyLoaded})
+
+	var events []Event
+	ctx = WithSink(ctx, SinkFunc(func(ctx context.Context, event Event) {
+		events = append(events, event)
+	}))
+	if !Enabled(ctx) {
+		t.Fatal("Enabled() = false with sink")
+	}
+	Emit(ctx, Event{Type: EventKeyLoaded, KeyID: "key"})
+	eventTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	Emit(ctx, Event{Type: EventPluginExecuted, Time: eventTime})
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if events[0].Type != EventKeyLoaded || events[0].KeyID != "key" || events[0].Time.IsZero() {
+		t.Fatalf("events[0] = %+v", events[0])
+	}
+	if !events[1].Time.Equal(eventTime) {
+		t.Fatalf("events[1].Time = %v, want %v", events[1].Time, eventTime)
+	}
+
+	if Enabled(WithSink(context.Background(), nil)) {
+		t.Fatal("Enabled() = true with nil sink")
+	}
+}
+
+func TestGetSink(t *testing.T) {
+	if sink := GetSink(context.Background()); sink != nil {
+		t.Fatalf("GetSink() = %v, want nil", sink)
+	}
+	var emitted bool
+	ctx := WithSink(context.Background(), SinkFunc(func(ctx context.Context, event Event) {
+		emitted = true
+	}))
+	sink := GetSink(ctx)
+	if sink == nil {
+		t.Fatal("GetSink() = nil")
+	}
+	sink.Emit(ctx, Event{Type: EventKeyLoaded})
+	if !emitted {
+		t.Fatal("GetSink() did not return the sink of the context")
+	}
+}
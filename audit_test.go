@@ -0,0 +1,60 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/internal/mock"
+)
+
+func TestSignAuditArtifactSigned(t *testing.T) {
+	var events []audit.Event
+	ctx := audit.WithSink(context.Background(), audit.SinkFunc(func(ctx context.Context, event audit.Event) {
+		events = append(events, event)
+	}))
+	opts := SignOptions{}
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+	opts.ArtifactReference = mock.SampleArtifactUri
+
+	if _, err := Sign(ctx, &dummySigner{}, mock.NewRepository(), opts); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Type != audit.EventArtifactSigned || event.Artifact != mock.SampleDigest.String() {
+		t.Fatalf("event = %+v", event)
+	}
+	if event.Attributes["repository"] != "registry.acme-rockets.io/software/net-monitor" ||
+		event.Attributes["reference"] != mock.SampleArtifactUri {
+		t.Fatalf("event attributes = %+v", event.Attributes)
+	}
+
+	// no event if the signature is not pushed
+	events = nil
+	repo := mock.NewRepository()
+	repo.PushSignatureError = errors.New("push failed")
+	if _, err := Sign(ctx, &dummySigner{}, repo, opts); err == nil {
+		t.Fatal("Sign() expects error, got nil")
+	}
+	if len(events) != 0 {
+		t.Fatalf("got %d events, want 0", len(events))
+	}
+}
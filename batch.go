@@ -0,0 +1,219 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBatchAborted is the error of the items of a batch not processed as the
+// batch was aborted by its [FailurePolicy].
+var ErrBatchAborted = errors.New("batch aborted by the failure policy")
+
+// FailureMode is the mode of a [FailurePolicy].
+type FailureMode int
+
+const (
+	// ContinueOnFailure processes all the items of the batch, and collects
+	// the errors of the failed items.
+	ContinueOnFailure FailureMode = iota
+
+	// FailFast aborts the batch at the first failed item.
+	FailFast
+
+	// AbortAfterFailures aborts the batch once MaxFailures items failed.
+	AbortAfterFailures
+)
+
+// FailurePolicy sets how the batch APIs, e.g. [notation.VerifyAll] and
+// [notation.SignAll], handle the failed items. When the batch is aborted,
+// the items in progress are cancelled and the items not started fail with
+// [ErrBatchAborted].
+type FailurePolicy struct {
+	// Mode is the failure mode.
+	Mode FailureMode
+
+	// MaxFailures is the number of failed items aborting the batch in the
+	// [AbortAfterFailures] mode. It must be positive in this mode.
+	MaxFailures int
+}
+
+// validate returns an error if the policy is invalid. A nil policy is
+// valid.
+func (p *FailurePolicy) validate() error {
+	if p == nil {
+		return nil
+	}
+	switch p.Mode {
+	case ContinueOnFailure, FailFast:
+		return nil
+	case AbortAfterFailures:
+		if p.MaxFailures <= 0 {
+			return fmt.Errorf("failurePolicy.MaxFailures expects a positive number, got %d", p.MaxFailures)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown failure mode %d", p.Mode)
+	}
+}
+
+// abort returns true if the batch is aborted after failures failed items.
+func (p *FailurePolicy) abort(failures int) bool {
+	if p == nil {
+		return false
+	}
+	switch p.Mode {
+	case FailFast:
+		return failures >= 1
+	case AbortAfterFailures:
+		return failures >= p.MaxFailures
+	default:
+		return false
+	}
+}
+
+// BatchItemError is the error of an item of a batch.
+type BatchItemError struct {
+	// Index is the index of the item in the batch.
+	Index int
+
+	// ArtifactReference is the artifact reference of the item.
+	ArtifactReference string
+
+	// Err is the error of the item.
+	Err error
+}
+
+func (e BatchItemError) Error() string {
+	return fmt.Sprintf("%s: %v", e.ArtifactReference, e.Err)
+}
+
+func (e BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is returned by the batch APIs with a [FailurePolicy] when items
+// of the batch failed. It preserves the errors of the failed items.
+type BatchError struct {
+	// Items are the errors of the failed items, in the order of the batch.
+	Items []BatchItemError
+
+	// Total is the number of items of the batch.
+	Total int
+
+	// Aborted is true if the batch was aborted by the failure policy.
+	Aborted bool
+}
+
+func (e *BatchError) Error() string {
+	msg := fmt.Sprintf("%d of %d items failed", len(e.Items), e.Total)
+	if e.Aborted {
+		msg = "batch aborted, " + msg
+	}
+	if len(e.Items) > 0 {
+		msg += ", first error: " + e.Items[0].Error()
+	}
+	return msg
+}
+
+// Unwrap returns the errors of the failed items.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Items))
+	for i, item := range e.Items {
+		errs[i] = item
+	}
+	return errs
+}
+
+// runBatch calls run for the n items of a batch with at most concurrency
+// concurrent calls, and applies the failure policy. It returns the error of
+// each item and whether the batch was aborted. The items not started fail
+// with the error of ctx or ErrBatchAborted.
+func runBatch(ctx context.Context, n, concurrency int, policy *FailurePolicy, run func(ctx context.Context, i int) error) ([]error, bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, n)
+	var mu sync.Mutex
+	var failures int
+	var aborted bool
+	isAborted := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return aborted
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		if isAborted() {
+			errs[i] = ErrBatchAborted
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			if isAborted() {
+				errs[i] = ErrBatchAborted
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := run(ctx, i)
+			if err == nil {
+				return
+			}
+			errs[i] = err
+			mu.Lock()
+			defer mu.Unlock()
+			failures++
+			if !aborted && policy.abort(failures) {
+				aborted = true
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs, aborted
+}
+
+// newBatchError returns the BatchError of the batch of the artifact
+// references with the errors errs, or nil if no item failed.
+func newBatchError(artifactRefs []string, errs []error, aborted bool) error {
+	batchErr := &BatchError{
+		Total:   len(artifactRefs),
+		Aborted: aborted,
+	}
+	for i, err := range errs {
+		if err != nil {
+			batchErr.Items = append(batchErr.Items, BatchItemError{
+				Index:             i,
+				ArtifactReference: artifactRefs[i],
+				Err:               err,
+			})
+		}
+	}
+	if len(batchErr.Items) == 0 {
+		return nil
+	}
+	return batchErr
+}
@@ -0,0 +1,126 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestRunBatch(t *testing.T) {
+	errFailed := errors.New("failed")
+	// items 1, 2 and 4 fail
+	run := func(ctx context.Context, i int) error {
+		if i == 1 || i == 2 || i == 4 {
+			return errFailed
+		}
+		return nil
+	}
+	tests := []struct {
+		name        string
+		policy      *FailurePolicy
+		wantErrs    []error
+		wantAborted bool
+	}{
+		{
+			name:     "nil policy",
+			wantErrs: []error{nil, errFailed, errFailed, nil, errFailed, nil},
+		},
+		{
+			name:     "continue on failure",
+			policy:   &FailurePolicy{Mode: ContinueOnFailure},
+			wantErrs: []error{nil, errFailed, errFailed, nil, errFailed, nil},
+		},
+		{
+			name:        "fail fast",
+			policy:      &FailurePolicy{Mode: FailFast},
+			wantErrs:    []error{nil, errFailed, ErrBatchAborted, ErrBatchAborted, ErrBatchAborted, ErrBatchAborted},
+			wantAborted: true,
+		},
+		{
+			name:        "abort after 2 failures",
+			policy:      &FailurePolicy{Mode: AbortAfterFailures, MaxFailures: 2},
+			wantErrs:    []error{nil, errFailed, errFailed, ErrBatchAborted, ErrBatchAborted, ErrBatchAborted},
+			wantAborted: true,
+		},
+		{
+			name:     "abort after 4 failures",
+			policy:   &FailurePolicy{Mode: AbortAfterFailures, MaxFailures: 4},
+			wantErrs: []error{nil, errFailed, errFailed, nil, errFailed, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, aborted := runBatch(context.Background(), 6, 1, tt.policy, run)
+			if aborted != tt.wantAborted {
+				t.Fatalf("runBatch() aborted = %v, want %v", aborted, tt.wantAborted)
+			}
+			for i, err := range errs {
+				if !errors.Is(err, tt.wantErrs[i]) || (err == nil) != (tt.wantErrs[i] == nil) {
+					t.Fatalf("runBatch() item %d error = %v, want %v", i, err, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFailurePolicyValidate(t *testing.T) {
+	for _, policy := range []*FailurePolicy{
+		nil,
+		{Mode: ContinueOnFailure},
+		{Mode: FailFast},
+		{Mode: AbortAfterFailures, MaxFailures: 1},
+	} {
+		if err := policy.validate(); err != nil {
+			t.Fatalf("validate(%+v) error = %v", policy, err)
+		}
+	}
+	for _, policy := range []*FailurePolicy{
+		{Mode: AbortAfterFailures},
+		{Mode: FailureMode(42)},
+	} {
+		if err := policy.validate(); err == nil {
+			t.Fatalf("validate(%+v) expects error", policy)
+		}
+	}
+}
+
+func TestBatchError(t *testing.T) {
+	errFailed := errors.New("failed")
+	refs := make([]string, 3)
+	for i := range refs {
+		refs[i] = "registry.example.com/app:" + strconv.Itoa(i)
+	}
+	if err := newBatchError(refs, make([]error, 3), false); err != nil {
+		t.Fatalf("newBatchError() = %v, want nil", err)
+	}
+
+	err := newBatchError(refs, []error{nil, errFailed, ErrBatchAborted}, true)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("newBatchError() = %T, want *BatchError", err)
+	}
+	if len(batchErr.Items) != 2 || batchErr.Items[0].Index != 1 || batchErr.Items[0].ArtifactReference != refs[1] || batchErr.Items[1].Index != 2 {
+		t.Fatalf("BatchError.Items = %+v", batchErr.Items)
+	}
+	if !errors.Is(err, errFailed) || !errors.Is(err, ErrBatchAborted) {
+		t.Fatalf("BatchError does not wrap the item errors: %v", err)
+	}
+	want := "batch aborted, 2 of 3 items failed, first error: registry.example.com/app:1: failed"
+	if err.Error() != want {
+		t.Fatalf("BatchError.Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clock provides the clock reading the current time for signing,
+// verification and revocation checks, so that the callers simulate the
+// expiry of signatures, certificates and CRLs without sleeping.
+package clock
+
+import "time"
+
+// Clock reads the current time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// Func is an adapter to allow the use of ordinary functions as [Clock].
+type Func func() time.Time
+
+// Now returns f().
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// System is the [Clock] reading the system time.
+var System Clock = Func(time.Now)
+
+// Now returns the current time read from c, or the system time if c is nil.
+func Now(c Clock) time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
@@ -0,0 +1,33 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNow(t *testing.T) {
+	fixed := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := Now(Func(func() time.Time { return fixed })); !got.Equal(fixed) {
+		t.Fatalf("Now() = %v, want %v", got, fixed)
+	}
+
+	before := time.Now()
+	for _, c := range []Clock{nil, System} {
+		if got := Now(c); got.Before(before) || got.After(time.Now()) {
+			t.Fatalf("Now(%v) = %v, want the system time", c, got)
+		}
+	}
+}
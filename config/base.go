@@ -23,16 +23,20 @@ import (
 	"github.com/notaryproject/notation-go/dir"
 )
 
-// save stores the cfg struct to file
+// save stores the cfg struct to file. It returns a ReadOnlyError in
+// read-only mode or on a read-only file system.
 func save(filePath string, cfg interface{}) error {
+	if err := checkWritable(filePath); err != nil {
+		return err
+	}
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0700); err != nil {
-		return err
+		return wrapReadOnly(filePath, err)
 	}
 
 	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
-		return err
+		return wrapReadOnly(filePath, err)
 	}
 	defer file.Close()
 
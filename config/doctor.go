@@ -0,0 +1,373 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/plugin"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// Check is the name of a check of [Doctor].
+type Check string
+
+// Checks of [Doctor].
+const (
+	// CheckConfig checks that the config.json file parses.
+	CheckConfig Check = "config"
+
+	// CheckSigningKeys checks the signing keys with [SigningKeys.Validate],
+	// e.g. that the local keys match their certificates.
+	CheckSigningKeys Check = "signingKeys"
+
+	// CheckRegistries checks that the registries.json file parses and is
+	// valid.
+	CheckRegistries Check = "registries"
+
+	// CheckTrustPolicy checks that the trust policy documents parse and are
+	// valid.
+	CheckTrustPolicy Check = "trustPolicy"
+
+	// CheckTrustStore checks that the trust stores contain valid
+	// certificates.
+	CheckTrustStore Check = "trustStore"
+
+	// CheckPlugin checks that the plugins execute and report their metadata.
+	CheckPlugin Check = "plugin"
+)
+
+// Severity is the severity of a [Finding].
+type Severity string
+
+// Severities of the findings.
+const (
+	// SeverityError reports a problem failing the signing or the
+	// verification.
+	SeverityError Severity = "error"
+
+	// SeverityWarning reports a problem that may fail some operations, e.g.
+	// a trust store not referenced by any trust policy statement.
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a problem found by [Doctor].
+type Finding struct {
+	// Check is the check that found the problem.
+	Check Check `json:"check"`
+
+	// Severity is the severity of the problem.
+	Severity Severity `json:"severity"`
+
+	// Subject is the file, the key, the trust store or the plugin with the
+	// problem, e.g. "trustpolicy.oci.json", "ca:acme-rockets" or "foo".
+	Subject string `json:"subject,omitempty"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// DoctorReport is the list of the findings of [Doctor], in the order of the
+// checks.
+type DoctorReport []Finding
+
+// Healthy returns true if no finding is an error.
+func (r DoctorReport) Healthy() bool {
+	for _, finding := range r {
+		if finding.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// DoctorOptions contains optional settings of [Doctor].
+type DoctorOptions struct {
+	// PluginManager lists and loads the installed plugins. If nil,
+	// plugin.NewCLIManager(dir.PluginFS()) is used. If it implements
+	// [PluginResolver], it resolves the plugins of the signing keys.
+	PluginManager plugin.Manager
+
+	// SkipPlugins skips the checks executing the plugins.
+	SkipPlugins bool
+}
+
+// Doctor checks the notation environment of the config directory and of the
+// plugin directory, and returns the problems found:
+//   - the config.json and registries.json files parse.
+//   - the signing keys are valid, see [SigningKeys.Validate].
+//   - the trust policy documents parse and are valid. A warning is reported
+//     if no trust policy document is found.
+//   - the trust stores referenced by the trust policy statements contain
+//     valid certificates. A warning is reported for the other trust stores of
+//     the config directory with invalid or no certificates.
+//   - the plugins execute and report their metadata.
+//
+// An empty report means that no problem was found.
+func Doctor(ctx context.Context, opts DoctorOptions) DoctorReport {
+	if opts.PluginManager == nil {
+		opts.PluginManager = plugin.NewCLIManager(dir.PluginFS())
+	}
+	var report DoctorReport
+	report = append(report, checkConfig()...)
+	report = append(report, checkSigningKeys(ctx, opts)...)
+	report = append(report, checkRegistries()...)
+	stores, findings := checkTrustPolicies()
+	report = append(report, findings...)
+	report = append(report, checkTrustStores(ctx, stores)...)
+	if !opts.SkipPlugins {
+		report = append(report, checkPlugins(ctx, opts.PluginManager)...)
+	}
+	return report
+}
+
+// checkConfig checks the config.json file.
+func checkConfig() []Finding {
+	if _, err := LoadConfig(); err != nil {
+		return []Finding{{
+			Check:    CheckConfig,
+			Severity: SeverityError,
+			Subject:  dir.PathConfigFile,
+			Message:  fmt.Sprintf("failed to load %s: %v", dir.PathConfigFile, err),
+		}}
+	}
+	return nil
+}
+
+// checkSigningKeys checks the signing keys.
+func checkSigningKeys(ctx context.Context, opts DoctorOptions) []Finding {
+	keys, err := LoadSigningKeys()
+	if err != nil {
+		return []Finding{{
+			Check:    CheckSigningKeys,
+			Severity: SeverityError,
+			Subject:  dir.PathSigningKeys,
+			Message:  fmt.Sprintf("failed to load %s: %v", dir.PathSigningKeys, err),
+		}}
+	}
+	var resolver PluginResolver
+	if r, ok := opts.PluginManager.(PluginResolver); ok && !opts.SkipPlugins {
+		resolver = r
+	}
+	validation, err := keys.Validate(ctx, resolver)
+	if err != nil {
+		return []Finding{{
+			Check:    CheckSigningKeys,
+			Severity: SeverityError,
+			Subject:  dir.PathSigningKeys,
+			Message:  err.Error(),
+		}}
+	}
+	var findings []Finding
+	for _, key := range validation {
+		if key.Err != nil {
+			findings = append(findings, Finding{
+				Check:    CheckSigningKeys,
+				Severity: SeverityError,
+				Subject:  key.Name,
+				Message:  key.Err.Error(),
+			})
+		}
+	}
+	return findings
+}
+
+// checkRegistries checks the registries.json file.
+func checkRegistries() []Finding {
+	if _, err := registry.LoadRegistriesConfig(); err != nil {
+		return []Finding{{
+			Check:    CheckRegistries,
+			Severity: SeverityError,
+			Subject:  dir.PathRegistries,
+			Message:  err.Error(),
+		}}
+	}
+	return nil
+}
+
+// checkTrustPolicies checks the trust policy documents. It returns the
+// trust stores referenced by the valid documents, keyed by trust store,
+// e.g. "ca:acme-rockets", with the names of the statements referencing
+// them.
+func checkTrustPolicies() (map[string][]string, []Finding) {
+	stores := make(map[string][]string)
+	reference := func(statement string, trustStores []string) {
+		for _, store := range trustStores {
+			stores[store] = append(stores[store], statement)
+		}
+	}
+	var findings []Finding
+	failed := func(path string, err error) {
+		findings = append(findings, Finding{
+			Check:    CheckTrustPolicy,
+			Severity: SeverityError,
+			Subject:  path,
+			Message:  err.Error(),
+		})
+	}
+
+	ociPath := dir.PathOCITrustPolicy
+	ociExists := configFileExists(ociPath)
+	if !ociExists && configFileExists(dir.PathTrustPolicy) {
+		ociPath, ociExists = dir.PathTrustPolicy, true
+	}
+	if ociExists {
+		doc, err := trustpolicy.LoadOCIDocument()
+		if err == nil {
+			err = doc.Validate()
+		}
+		if err != nil {
+			failed(ociPath, err)
+		} else {
+			for _, statement := range doc.TrustPolicies {
+				reference(statement.Name, statement.TrustStores)
+			}
+		}
+	}
+
+	blobExists := configFileExists(dir.PathBlobTrustPolicy)
+	if blobExists {
+		doc, err := trustpolicy.LoadBlobDocument()
+		if err == nil {
+			err = doc.Validate()
+		}
+		if err != nil {
+			failed(dir.PathBlobTrustPolicy, err)
+		} else {
+			for _, statement := range doc.TrustPolicies {
+				reference(statement.Name, statement.TrustStores)
+			}
+		}
+	}
+
+	if !ociExists && !blobExists {
+		findings = append(findings, Finding{
+			Check:    CheckTrustPolicy,
+			Severity: SeverityWarning,
+			Message:  "no trust policy document found, signatures cannot be verified",
+		})
+	}
+	return stores, findings
+}
+
+// checkTrustStores checks the trust stores referenced by the trust policy
+// statements and the other trust stores of the config directory.
+func checkTrustStores(ctx context.Context, referenced map[string][]string) []Finding {
+	stores := make(map[string]bool)
+	for store := range referenced {
+		stores[store] = true
+	}
+	for _, store := range listTrustStores() {
+		stores[store] = true
+	}
+	names := make([]string, 0, len(stores))
+	for store := range stores {
+		names = append(names, store)
+	}
+	sort.Strings(names)
+
+	x509TrustStore := truststore.NewX509TrustStore(dir.ConfigFS())
+	var findings []Finding
+	for _, store := range names {
+		storeType, namedStore, _ := strings.Cut(store, ":")
+		if strings.HasPrefix(namedStore, truststore.PluginStorePrefix) {
+			// the plugin trust stores are served by the plugins
+			continue
+		}
+		_, err := x509TrustStore.GetCertificates(ctx, truststore.Type(storeType), namedStore)
+		if err == nil {
+			continue
+		}
+		finding := Finding{
+			Check:    CheckTrustStore,
+			Severity: SeverityError,
+			Subject:  store,
+			Message:  err.Error(),
+		}
+		if statements, ok := referenced[store]; ok {
+			finding.Message = fmt.Sprintf("%s, referenced by trust policy statements %s", err, strings.Join(statements, ", "))
+		} else {
+			finding.Severity = SeverityWarning
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// listTrustStores returns the x509 trust stores of the config directory,
+// e.g. "ca:acme-rockets".
+func listTrustStores() []string {
+	var stores []string
+	for _, storeType := range truststore.Types {
+		names, _ := listTrustStoreNames(storeType)
+		for _, name := range names {
+			stores = append(stores, string(storeType)+":"+name)
+		}
+	}
+	return stores
+}
+
+// checkPlugins checks that the installed plugins execute and report their
+// metadata.
+func checkPlugins(ctx context.Context, manager plugin.Manager) []Finding {
+	names, err := manager.List(ctx)
+	if err != nil {
+		return []Finding{{
+			Check:    CheckPlugin,
+			Severity: SeverityError,
+			Message:  err.Error(),
+		}}
+	}
+	var findings []Finding
+	for _, name := range names {
+		if err := checkPlugin(ctx, manager, name); err != nil {
+			findings = append(findings, Finding{
+				Check:    CheckPlugin,
+				Severity: SeverityError,
+				Subject:  name,
+				Message:  err.Error(),
+			})
+		}
+	}
+	return findings
+}
+
+// checkPlugin checks that the named plugin executes and reports its
+// metadata.
+func checkPlugin(ctx context.Context, manager plugin.Manager, name string) error {
+	p, err := manager.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	// the CLI plugins validate the metadata
+	if _, err := p.GetMetadata(ctx, &pluginframework.GetMetadataRequest{}); err != nil {
+		return fmt.Errorf("failed to get metadata of plugin %s: %w", name, err)
+	}
+	return nil
+}
+
+// configFileExists returns true if the file of the config directory exists.
+func configFileExists(path string) bool {
+	_, err := fs.Stat(dir.ConfigFS(), path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
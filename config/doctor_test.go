@@ -0,0 +1,163 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// doctorPluginManager lists a plugin failing to load.
+type doctorPluginManager struct{}
+
+func (doctorPluginManager) Get(ctx context.Context, name string) (plugin.Plugin, error) {
+	return nil, errors.New("plugin executable file is missing")
+}
+
+func (doctorPluginManager) List(ctx context.Context) ([]string, error) {
+	return []string{"foo"}, nil
+}
+
+func writeConfigFile(t *testing.T, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir.UserConfigDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	leaf := testhelper.GetRSALeafCertificate()
+	root := testhelper.GetRSARootCertificate()
+	other := testhelper.GetECLeafCertificate()
+	validKeyPath, validCertPath := writeKeyPair(t, leaf.PrivateKey, leaf.Cert, root.Cert)
+	mismatchKeyPath, mismatchCertPath := writeKeyPair(t, other.PrivateKey, leaf.Cert, root.Cert)
+	keys := NewSigningKeys()
+	keys.Keys = []KeySuite{
+		{Name: "valid", X509KeyPair: &X509KeyPair{KeyPath: validKeyPath, CertificatePath: validCertPath}},
+		{Name: "mismatch", X509KeyPair: &X509KeyPair{KeyPath: mismatchKeyPath, CertificatePath: mismatchCertPath}},
+	}
+	if err := keys.Save(); err != nil {
+		t.Fatal(err)
+	}
+	writeConfigFile(t, dir.PathConfigFile, "{}")
+	writeConfigFile(t, dir.PathOCITrustPolicy, `{
+		"version": "1.0",
+		"trustPolicies": [{
+			"name": "acme-rockets",
+			"registryScopes": ["*"],
+			"signatureVerification": {"level": "strict"},
+			"trustStores": ["ca:acme-rockets", "ca:missing"],
+			"trustedIdentities": ["*"]
+		}]
+	}`)
+	writeConfigFile(t, dir.X509TrustStoreDir("ca", "acme-rockets", "root.crt"), string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Cert.Raw})))
+	if err := os.MkdirAll(filepath.Join(dir.UserConfigDir, dir.X509TrustStoreDir("ca", "unused")), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	report := Doctor(context.Background(), DoctorOptions{PluginManager: doctorPluginManager{}})
+	if report.Healthy() {
+		t.Fatal("Healthy() = true, want false")
+	}
+	want := []struct {
+		check    Check
+		severity Severity
+		subject  string
+		message  string
+	}{
+		{CheckSigningKeys, SeverityError, "mismatch", "signing key \"mismatch\""},
+		{CheckTrustStore, SeverityError, "ca:missing", "referenced by trust policy statements acme-rockets"},
+		{CheckTrustStore, SeverityWarning, "ca:unused", "no x509 certificates were found"},
+		{CheckPlugin, SeverityError, "foo", "plugin executable file is missing"},
+	}
+	if len(report) != len(want) {
+		t.Fatalf("report = %+v, want %d findings", report, len(want))
+	}
+	for i, finding := range report {
+		if finding.Check != want[i].check || finding.Severity != want[i].severity || finding.Subject != want[i].subject || !strings.Contains(finding.Message, want[i].message) {
+			t.Errorf("report[%d] = %+v, want %+v", i, finding, want[i])
+		}
+	}
+
+	// the plugins are not executed
+	report = Doctor(context.Background(), DoctorOptions{PluginManager: doctorPluginManager{}, SkipPlugins: true})
+	for _, finding := range report {
+		if finding.Check == CheckPlugin {
+			t.Fatalf("report = %+v, want no plugin findings", report)
+		}
+	}
+}
+
+func TestDoctorMalformed(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	writeConfigFile(t, dir.PathConfigFile, "{")
+	writeConfigFile(t, dir.PathSigningKeys, "{")
+	writeConfigFile(t, dir.PathRegistries, "{")
+	writeConfigFile(t, dir.PathOCITrustPolicy, "{")
+	writeConfigFile(t, dir.PathBlobTrustPolicy, `{"version": "1.0"}`)
+
+	report := Doctor(context.Background(), DoctorOptions{SkipPlugins: true})
+	want := []struct {
+		check   Check
+		subject string
+	}{
+		{CheckConfig, dir.PathConfigFile},
+		{CheckSigningKeys, dir.PathSigningKeys},
+		{CheckRegistries, dir.PathRegistries},
+		{CheckTrustPolicy, dir.PathOCITrustPolicy},
+		{CheckTrustPolicy, dir.PathBlobTrustPolicy},
+	}
+	if len(report) != len(want) {
+		t.Fatalf("report = %+v, want %d findings", report, len(want))
+	}
+	for i, finding := range report {
+		if finding.Check != want[i].check || finding.Subject != want[i].subject || finding.Severity != SeverityError {
+			t.Errorf("report[%d] = %+v, want %+v", i, finding, want[i])
+		}
+	}
+}
+
+func TestDoctorEmpty(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	report := Doctor(context.Background(), DoctorOptions{PluginManager: &emptyPluginManager{}})
+	if !report.Healthy() {
+		t.Fatalf("Healthy() = false, report = %+v", report)
+	}
+	if len(report) != 1 || report[0].Check != CheckTrustPolicy || report[0].Severity != SeverityWarning {
+		t.Fatalf("report = %+v, want the missing trust policy warning", report)
+	}
+}
+
+// emptyPluginManager lists no plugins.
+type emptyPluginManager struct {
+	doctorPluginManager
+}
+
+func (*emptyPluginManager) List(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
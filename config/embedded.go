@@ -0,0 +1,215 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/signer"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+// EmbeddedConfig is an in-memory snapshot of the configuration managed by
+// the notation CLI in the config directory, e.g. ~/.config/notation. It is
+// used to move the signing and the verification from the CLI to a service
+// embedding notation, which does not read the config directory:
+//
+//	cfg, err := config.LoadEmbeddedConfig(config.EmbeddedConfigOptions{})
+//	signer, err := cfg.Signer("")
+//	verifier, err := verifier.NewVerifierWithOptions(cfg.TrustStore, verifier.VerifierOptions{
+//		OCITrustPolicy:  cfg.OCITrustPolicy,
+//		BlobTrustPolicy: cfg.BlobTrustPolicy,
+//	})
+type EmbeddedConfig struct {
+	// Config is the content of the config.json file.
+	Config *Config
+
+	// SigningKeys is the content of the signingkeys.json file. The plugin
+	// based keys are resolved with [KeySuite.ResolvePlugin].
+	SigningKeys *SigningKeys
+
+	// Signers are the signers of the local signing keys, keyed by key name,
+	// with the private keys and the certificate chains loaded in memory.
+	Signers map[string]*signer.GenericSigner
+
+	// OCITrustPolicy is the trust policy document for OCI artifacts, or nil
+	// if not found.
+	OCITrustPolicy *trustpolicy.OCIDocument
+
+	// BlobTrustPolicy is the trust policy document for blobs, or nil if not
+	// found.
+	BlobTrustPolicy *trustpolicy.BlobDocument
+
+	// TrustStore contains the certificates of the x509 trust stores.
+	TrustStore EmbeddedTrustStore
+
+	// Registries is the content of the registries.json file.
+	Registries *registry.RegistriesConfig
+}
+
+// EmbeddedConfigOptions contains optional settings of
+// [LoadEmbeddedConfig].
+type EmbeddedConfigOptions struct {
+	// Passphrase returns the passphrase of the encrypted private key of the
+	// named local signing key.
+	Passphrase func(keyName string) ([]byte, error)
+}
+
+// LoadEmbeddedConfig loads the configuration of the config directory in
+// memory. It fails if a local signing key, a trust policy document or a
+// trust store cannot be loaded, so that the snapshot is complete. The files
+// are not modified.
+func LoadEmbeddedConfig(opts EmbeddedConfigOptions) (*EmbeddedConfig, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", dir.PathConfigFile, err)
+	}
+	keys, err := LoadSigningKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", dir.PathSigningKeys, err)
+	}
+	signers, err := loadSigners(keys, opts.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	registries, err := registry.LoadRegistriesConfig()
+	if err != nil {
+		return nil, err
+	}
+	embedded := &EmbeddedConfig{
+		Config:      config,
+		SigningKeys: keys,
+		Signers:     signers,
+		Registries:  registries,
+	}
+	if configFileExists(dir.PathOCITrustPolicy) || configFileExists(dir.PathTrustPolicy) {
+		if embedded.OCITrustPolicy, err = trustpolicy.LoadOCIDocument(); err != nil {
+			return nil, fmt.Errorf("failed to load OCI trust policy: %w", err)
+		}
+	}
+	if configFileExists(dir.PathBlobTrustPolicy) {
+		if embedded.BlobTrustPolicy, err = trustpolicy.LoadBlobDocument(); err != nil {
+			return nil, fmt.Errorf("failed to load blob trust policy: %w", err)
+		}
+	}
+	if embedded.TrustStore, err = loadEmbeddedTrustStore(); err != nil {
+		return nil, err
+	}
+	return embedded, nil
+}
+
+// Signer returns the signer of the named local signing key, or of the
+// default signing key if name is empty.
+func (c *EmbeddedConfig) Signer(name string) (*signer.GenericSigner, error) {
+	var key KeySuite
+	var err error
+	if name == "" {
+		key, err = c.SigningKeys.GetDefault()
+	} else {
+		key, err = c.SigningKeys.Get(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s, ok := c.Signers[key.Name]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not a local key", key.Name)
+	}
+	return s, nil
+}
+
+// loadSigners returns the signers of the local signing keys.
+func loadSigners(keys *SigningKeys, passphrase func(keyName string) ([]byte, error)) (map[string]*signer.GenericSigner, error) {
+	signers := make(map[string]*signer.GenericSigner)
+	for _, key := range keys.Keys {
+		if key.X509KeyPair == nil {
+			continue
+		}
+		var opts signer.FilesOptions
+		if passphrase != nil {
+			name := key.Name
+			opts.Passphrase = func() ([]byte, error) {
+				return passphrase(name)
+			}
+		}
+		s, err := signer.NewGenericSignerFromFilesWithOptions(key.KeyPath, key.CertificatePath, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load signing key %q: %w", key.Name, err)
+		}
+		signers[key.Name] = s
+	}
+	return signers, nil
+}
+
+// EmbeddedTrustStore is an in-memory truststore.X509TrustStore, containing
+// the certificates of the named stores keyed by store type and name.
+type EmbeddedTrustStore map[truststore.Type]map[string][]*x509.Certificate
+
+// GetCertificates returns the certificates of the named store namedStore of
+// type storeType.
+func (s EmbeddedTrustStore) GetCertificates(ctx context.Context, storeType truststore.Type, namedStore string) ([]*x509.Certificate, error) {
+	certs, ok := s[storeType][namedStore]
+	if !ok {
+		return nil, truststore.TrustStoreError{Msg: fmt.Sprintf("the trust store %q of type %q does not exist", namedStore, storeType)}
+	}
+	return append([]*x509.Certificate(nil), certs...), nil
+}
+
+// loadEmbeddedTrustStore loads the x509 trust stores of the config
+// directory.
+func loadEmbeddedTrustStore() (EmbeddedTrustStore, error) {
+	x509TrustStore := truststore.NewX509TrustStore(dir.ConfigFS())
+	store := make(EmbeddedTrustStore)
+	for _, storeType := range truststore.Types {
+		names, err := listTrustStoreNames(storeType)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			certs, err := x509TrustStore.GetCertificates(context.Background(), storeType, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load trust store %s:%s: %w", storeType, name, err)
+			}
+			if store[storeType] == nil {
+				store[storeType] = make(map[string][]*x509.Certificate)
+			}
+			store[storeType][name] = certs
+		}
+	}
+	return store, nil
+}
+
+// listTrustStoreNames returns the names of the x509 trust stores of type
+// storeType in the config directory.
+func listTrustStoreNames(storeType truststore.Type) ([]string, error) {
+	entries, err := fs.ReadDir(dir.ConfigFS(), dir.X509TrustStoreDir(string(storeType)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list trust stores of type %s: %w", storeType, err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
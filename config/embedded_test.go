@@ -0,0 +1,147 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestLoadEmbeddedConfig(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	leaf := testhelper.GetRSALeafCertificate()
+	root := testhelper.GetRSARootCertificate()
+	keyPath, certPath := writeKeyPair(t, leaf.PrivateKey, leaf.Cert, root.Cert)
+	defaultKey := "local"
+	keys := &SigningKeys{
+		Default: &defaultKey,
+		Keys: []KeySuite{
+			{Name: "local", X509KeyPair: &X509KeyPair{KeyPath: keyPath, CertificatePath: certPath}},
+			{Name: "kms", ExternalKey: &ExternalKey{ID: "key", PluginName: "kms"}},
+		},
+	}
+	if err := keys.Save(); err != nil {
+		t.Fatal(err)
+	}
+	writeConfigFile(t, dir.PathOCITrustPolicy, `{
+		"version": "1.0",
+		"trustPolicies": [{
+			"name": "acme-rockets",
+			"registryScopes": ["*"],
+			"signatureVerification": {"level": "strict", "override": {"revocation": "skip"}},
+			"trustStores": ["ca:acme-rockets"],
+			"trustedIdentities": ["*"]
+		}]
+	}`)
+	writeConfigFile(t, dir.X509TrustStoreDir("ca", "acme-rockets", "root.crt"), string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Cert.Raw})))
+
+	cfg, err := LoadEmbeddedConfig(EmbeddedConfigOptions{})
+	if err != nil {
+		t.Fatalf("LoadEmbeddedConfig() error = %v", err)
+	}
+	if cfg.Config == nil || cfg.Registries == nil || cfg.OCITrustPolicy == nil || cfg.BlobTrustPolicy != nil {
+		t.Fatalf("LoadEmbeddedConfig() = %+v", cfg)
+	}
+
+	// the embedded config is usable without the config directory
+	dir.UserConfigDir = t.TempDir()
+	ctx := context.Background()
+	s, err := cfg.Signer("")
+	if err != nil {
+		t.Fatalf("Signer() error = %v", err)
+	}
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("artifact"),
+		Size:      8,
+	}
+	signOpts := notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope}
+	sig, _, err := s.Sign(ctx, desc, signOpts)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	v, err := verifier.NewVerifierWithOptions(cfg.TrustStore, verifier.VerifierOptions{OCITrustPolicy: cfg.OCITrustPolicy})
+	if err != nil {
+		t.Fatalf("NewVerifierWithOptions() error = %v", err)
+	}
+	verifyOpts := notation.VerifierVerifyOptions{
+		ArtifactReference:  "registry.acme-rockets.io/software/net-monitor@" + desc.Digest.String(),
+		SignatureMediaType: jws.MediaTypeEnvelope,
+	}
+	if _, err := v.Verify(ctx, desc, sig, verifyOpts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if _, err := cfg.Signer("kms"); err == nil {
+		t.Fatal("Signer() expects error for a plugin based key, got nil")
+	}
+	var notFound KeyNotFoundError
+	if _, err := cfg.Signer("missing"); !errors.As(err, &notFound) {
+		t.Fatalf("Signer() error = %v, want KeyNotFoundError", err)
+	}
+}
+
+func TestLoadEmbeddedConfigError(t *testing.T) {
+	t.Run("invalid key", func(t *testing.T) {
+		dir.UserConfigDir = t.TempDir()
+		keys := NewSigningKeys()
+		keys.Keys = []KeySuite{{Name: "local", X509KeyPair: &X509KeyPair{KeyPath: "missing.key", CertificatePath: "missing.crt"}}}
+		if err := keys.Save(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadEmbeddedConfig(EmbeddedConfigOptions{}); err == nil {
+			t.Fatal("LoadEmbeddedConfig() expects error, got nil")
+		}
+	})
+
+	t.Run("malformed trust policy", func(t *testing.T) {
+		dir.UserConfigDir = t.TempDir()
+		writeConfigFile(t, dir.PathBlobTrustPolicy, "{")
+		if _, err := LoadEmbeddedConfig(EmbeddedConfigOptions{}); err == nil {
+			t.Fatal("LoadEmbeddedConfig() expects error, got nil")
+		}
+	})
+
+	t.Run("empty trust store", func(t *testing.T) {
+		dir.UserConfigDir = t.TempDir()
+		writeConfigFile(t, dir.X509TrustStoreDir("ca", "acme-rockets", "root.crt"), "")
+		if _, err := LoadEmbeddedConfig(EmbeddedConfigOptions{}); err == nil {
+			t.Fatal("LoadEmbeddedConfig() expects error, got nil")
+		}
+	})
+}
+
+func TestEmbeddedTrustStore(t *testing.T) {
+	root := testhelper.GetRSARootCertificate()
+	store := EmbeddedTrustStore{truststore.TypeCA: {"acme-rockets": {root.Cert}}}
+	certs, err := store.GetCertificates(context.Background(), truststore.TypeCA, "acme-rockets")
+	if err != nil || len(certs) != 1 || !certs[0].Equal(root.Cert) {
+		t.Fatalf("GetCertificates() = %v, %v", certs, err)
+	}
+	var storeErr truststore.TrustStoreError
+	if _, err := store.GetCertificates(context.Background(), truststore.TypeTSA, "acme-rockets"); !errors.As(err, &storeErr) {
+		t.Fatalf("GetCertificates() error = %v, want TrustStoreError", err)
+	}
+}
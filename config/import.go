@@ -0,0 +1,134 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/internal/file"
+)
+
+// ImportSigningKey imports the PEM encoded private key and certificate chain
+// of a signing key into the localkeys directory, and adds the key to the
+// signingkeys.json file, in one call. See [SigningKeys.Import].
+func ImportSigningKey(name string, keyPEM, certPEM []byte, markDefault bool) error {
+	var remove func()
+	err := LoadExecSaveSigningKeys(func(keys *SigningKeys) error {
+		var err error
+		remove, err = keys.importKey(name, keyPEM, certPEM, markDefault)
+		return err
+	})
+	if err != nil && remove != nil {
+		// the key is not saved
+		remove()
+	}
+	return err
+}
+
+// Import writes the PEM encoded private key and certificate chain of the
+// signing key name into the localkeys directory of the config directory, and
+// adds the key. The private key is only readable by the owner. The key files
+// are not overwritten if they exist. The private key must match the public
+// key of the leaf certificate, which is the first certificate of certPEM.
+//
+// The signing keys are not saved. Use [ImportSigningKey] or
+// [SigningKeysStore.Import] to also save them.
+func (s *SigningKeys) Import(name string, keyPEM, certPEM []byte, markDefault bool) error {
+	_, err := s.importKey(name, keyPEM, certPEM, markDefault)
+	return err
+}
+
+// importKey imports the key, and returns a function removing the key files
+// written.
+func (s *SigningKeys) importKey(name string, keyPEM, certPEM []byte, markDefault bool) (func(), error) {
+	if name == "" {
+		return nil, ErrKeyNameEmpty
+	}
+	if !file.IsValidFileName(name) {
+		return nil, fmt.Errorf("signing key name %q is not a valid file name", name)
+	}
+	if _, err := s.Get(name); err == nil {
+		return nil, fmt.Errorf("signing key with name %q already exists", name)
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, fmt.Errorf("invalid signing key %q: %w", name, err)
+	}
+
+	keyRelPath, certRelPath := dir.LocalKeyPath(name)
+	keyPath, err := dir.ConfigFS().SysPath(keyRelPath)
+	if err != nil {
+		return nil, err
+	}
+	certPath, err := dir.ConfigFS().SysPath(certRelPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(keyPath); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, wrapReadOnly(keyPath, err)
+	}
+	if err := writeNewFile(keyPath, keyPEM, 0600); err != nil {
+		return nil, err
+	}
+	if err := writeNewFile(certPath, certPEM, 0644); err != nil {
+		os.Remove(keyPath)
+		return nil, err
+	}
+	remove := func() {
+		os.Remove(keyPath)
+		os.Remove(certPath)
+	}
+
+	ks := KeySuite{
+		Name: name,
+		X509KeyPair: &X509KeyPair{
+			KeyPath:         keyPath,
+			CertificatePath: certPath,
+		},
+	}
+	if err := s.add(ks, markDefault); err != nil {
+		remove()
+		return nil, err
+	}
+	return remove, nil
+}
+
+// writeNewFile writes content to the new file at path with perm. It fails if
+// the file exists.
+func writeNewFile(path string, content []byte, perm os.FileMode) (writeErr error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("key file %s already exists", path)
+		}
+		return wrapReadOnly(path, err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil && writeErr == nil {
+			writeErr = err
+		}
+		if writeErr != nil {
+			os.Remove(path)
+		}
+	}()
+	_, err = f.Write(content)
+	return err
+}
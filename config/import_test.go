@@ -0,0 +1,163 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go/dir"
+)
+
+func keyPairPEM(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	leaf := testhelper.GetRSALeafCertificate()
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(leaf.PrivateKey)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Cert.Raw})
+	return keyPEM, certPEM
+}
+
+func TestImportSigningKey(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	keyPEM, certPEM := keyPairPEM(t)
+	if err := ImportSigningKey("imported", keyPEM, certPEM, true); err != nil {
+		t.Fatalf("ImportSigningKey() error = %v", err)
+	}
+
+	keys, err := LoadSigningKeys()
+	if err != nil {
+		t.Fatalf("LoadSigningKeys() error = %v", err)
+	}
+	key, err := keys.GetDefault()
+	if err != nil {
+		t.Fatalf("GetDefault() error = %v", err)
+	}
+	if key.Name != "imported" || key.KeyPath != filepath.Join(dir.UserConfigDir, "localkeys", "imported.key") || key.CertificatePath != filepath.Join(dir.UserConfigDir, "localkeys", "imported.crt") {
+		t.Fatalf("imported key = %+v", key)
+	}
+	data, err := os.ReadFile(key.KeyPath)
+	if err != nil || string(data) != string(keyPEM) {
+		t.Fatalf("key file content mismatch: %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(key.KeyPath)
+		if err != nil {
+			t.Fatalf("failed to stat key file: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("key file mode = %v, want 0600", info.Mode().Perm())
+		}
+	}
+
+	// the existing key is not overwritten
+	if err := ImportSigningKey("imported", keyPEM, certPEM, false); err == nil {
+		t.Fatal("ImportSigningKey() expects error for existing key")
+	}
+}
+
+func TestSigningKeysImport_Errors(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	keyPEM, certPEM := keyPairPEM(t)
+	otherKeyPEM := unmatchedKeyPEM(t)
+
+	tests := []struct {
+		name    string
+		keyName string
+		keyPEM  []byte
+		certPEM []byte
+	}{
+		{name: "empty name", keyName: "", keyPEM: keyPEM, certPEM: certPEM},
+		{name: "invalid name", keyName: "../escape", keyPEM: keyPEM, certPEM: certPEM},
+		{name: "invalid key", keyName: "key", keyPEM: []byte("invalid"), certPEM: certPEM},
+		{name: "key not matching certificate", keyName: "key", keyPEM: otherKeyPEM, certPEM: certPEM},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := NewSigningKeys()
+			if err := keys.Import(tt.keyName, tt.keyPEM, tt.certPEM, false); err == nil {
+				t.Fatal("Import() expects error, got nil")
+			}
+			if len(keys.Keys) != 0 {
+				t.Fatal("Import() added the key on error")
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir.UserConfigDir, "localkeys", "key.key")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal("Import() wrote the key file on error")
+	}
+
+	// key files left by another process are not overwritten
+	keyPath, _ := dir.LocalKeyPath("stale")
+	stalePath := filepath.Join(dir.UserConfigDir, keyPath)
+	if err := os.MkdirAll(filepath.Dir(stalePath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stalePath, []byte("stale"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSigningKeys().Import("stale", keyPEM, certPEM, false); err == nil {
+		t.Fatal("Import() expects error for existing key file")
+	}
+	if data, _ := os.ReadFile(stalePath); string(data) != "stale" {
+		t.Fatal("Import() overwrote the existing key file")
+	}
+}
+
+func TestSigningKeysStore_Import(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	keyPEM, certPEM := keyPairPEM(t)
+	store, err := NewSigningKeysStore()
+	if err != nil {
+		t.Fatalf("NewSigningKeysStore() error = %v", err)
+	}
+	if err := store.Import("imported", keyPEM, certPEM, false); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if keys := store.Keys(); len(keys.Keys) != 1 || keys.Keys[0].Name != "imported" {
+		t.Fatalf("Keys() = %+v", keys)
+	}
+
+	// no key files are written in read-only mode
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+	if err := store.Import("readonly", keyPEM, certPEM, false); !errors.As(err, &ReadOnlyError{}) {
+		t.Fatalf("Import() error = %v, want ReadOnlyError", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir.UserConfigDir, "localkeys", "readonly.key")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal("Import() wrote the key file in read-only mode")
+	}
+}
+
+// unmatchedKeyPEM returns a PEM encoded private key not matching the leaf
+// certificate.
+func unmatchedKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	root := testhelper.GetRSARootCertificate()
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(root.PrivateKey)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+}
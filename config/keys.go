@@ -26,6 +26,8 @@ import (
 
 	"github.com/notaryproject/notation-go/dir"
 	set "github.com/notaryproject/notation-go/internal/container"
+	"github.com/notaryproject/notation-go/internal/semver"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
 )
 
 // X509KeyPair contains the paths of a public/private key pair files.
@@ -37,9 +39,18 @@ type X509KeyPair struct {
 // ExternalKey contains the necessary information to delegate
 // the signing operation to the named plugin.
 type ExternalKey struct {
-	ID           string            `json:"id,omitempty"`
-	PluginName   string            `json:"pluginName,omitempty"`
+	ID         string `json:"id,omitempty"`
+	PluginName string `json:"pluginName,omitempty"`
+
+	// PluginConfig is the config passed to the plugin. Its values may
+	// reference secrets, e.g. "env://KMS_TOKEN", resolved at signing time
+	// with [SecretResolvers] so that they are not stored in plaintext.
 	PluginConfig map[string]string `json:"pluginConfig,omitempty"`
+
+	// PluginVersion is the optional semantic version constraint pinning the
+	// plugin version used with the key, e.g. "^1.2" or ">=1.0.0, <2.0.0".
+	// See [plugin.CLIManager.GetWithConstraint].
+	PluginVersion string `json:"pluginVersion,omitempty"`
 }
 
 // KeySuite is a named key suite.
@@ -110,7 +121,7 @@ func (s *SigningKeys) AddPlugin(ctx context.Context, keyName, id, pluginName str
 		logger.Error("Failed to add key with error: %v", err)
 		return err
 	}
-	logger.Debugf("Added key with name %s - {%+v}", keyName, ks)
+	logger.Debugf("Added key with name %s - {ID: %s, PluginName: %s, PluginConfig: %v}", keyName, id, pluginName, log.RedactMap(ctx, pluginConfig))
 	return nil
 }
 
@@ -215,6 +226,34 @@ func (k KeySuite) Is(name string) bool {
 	return k.Name == name
 }
 
+// PluginResolver resolves a plugin by name and semantic version constraint.
+// It is implemented by [plugin.CLIManager].
+type PluginResolver interface {
+	// GetWithConstraint returns the highest installed version of the named
+	// plugin satisfying the semantic version constraint. If constraint is
+	// empty, the default version is returned.
+	GetWithConstraint(ctx context.Context, name, constraint string) (pluginframework.Plugin, error)
+}
+
+// ResolvePlugin returns the plugin of the external key of the key suite,
+// honoring the PluginVersion constraint of the key.
+func (k KeySuite) ResolvePlugin(ctx context.Context, resolver PluginResolver) (pluginframework.Plugin, error) {
+	if k.ExternalKey == nil {
+		return nil, fmt.Errorf("signing key %q is not a plugin based key", k.Name)
+	}
+	if k.PluginName == "" {
+		return nil, fmt.Errorf("signing key %q has empty plugin name", k.Name)
+	}
+	if resolver == nil {
+		return nil, errors.New("plugin resolver cannot be nil")
+	}
+	p, err := resolver.GetWithConstraint(ctx, k.PluginName, k.PluginVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve plugin %s of signing key %q: %w", k.PluginName, k.Name, err)
+	}
+	return p, nil
+}
+
 func (s *SigningKeys) add(key KeySuite, markDefault bool) error {
 	if slices.ContainsIsser(s.Keys, key.Name) {
 		return fmt.Errorf("signing key with name %q already exists", key.Name)
@@ -237,6 +276,11 @@ func validateKeys(config *SigningKeys) error {
 			return fmt.Errorf("malformed %s: multiple keys with name '%s' found", dir.PathSigningKeys, key.Name)
 		}
 		uniqueKeyNames.Add(key.Name)
+		if key.ExternalKey != nil && key.PluginVersion != "" {
+			if err := semver.ValidateConstraint(key.PluginVersion); err != nil {
+				return fmt.Errorf("malformed %s: key '%s' has invalid plugin version: %w", dir.PathSigningKeys, key.Name, err)
+			}
+		}
 	}
 	if config.Default != nil {
 		defaultKey := *config.Default
@@ -25,6 +25,9 @@ import (
 
 	"github.com/notaryproject/notation-core-go/testhelper"
 	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/plugin"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
 )
 
 var sampleSigningKeysInfo = SigningKeys{
@@ -180,6 +183,29 @@ func TestSaveSigningKeys(t *testing.T) {
 			t.Errorf("Save signingkeys.json failed, error expected = \"%v\" but found = \"%v\"", expectedErr, err)
 		}
 	})
+
+	t.Run("InvalidPluginVersion", func(t *testing.T) {
+		expectedErr := "malformed signingkeys.json: key 'external-key' has invalid plugin version: invalid version constraint \"latest\": \"latest\" is not a valid semantic version"
+		dir.UserConfigDir = t.TempDir()
+		invalidPluginVersionSignKeysInfo := deepCopySigningKeys(sampleSigningKeysInfo)
+		invalidPluginVersionSignKeysInfo.Keys[2] = KeySuite{
+			Name: "external-key",
+			ExternalKey: &ExternalKey{
+				ID:            "id1",
+				PluginName:    "pluginX",
+				PluginVersion: "latest",
+			},
+		}
+		err := invalidPluginVersionSignKeysInfo.Save()
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Save signingkeys.json failed, error expected = \"%v\" but found = \"%v\"", expectedErr, err)
+		}
+
+		invalidPluginVersionSignKeysInfo.Keys[2].PluginVersion = "^1.2"
+		if err := invalidPluginVersionSignKeysInfo.Save(); err != nil {
+			t.Errorf("Save signingkeys.json failed, error = %v", err)
+		}
+	})
 }
 
 func TestAdd(t *testing.T) {
@@ -454,3 +480,69 @@ func createTempCertKey(t *testing.T) (string, string) {
 	}
 	return certPath, keyPath
 }
+
+type fakePluginResolver struct {
+	name       string
+	constraint string
+	err        error
+}
+
+func (r *fakePluginResolver) GetWithConstraint(_ context.Context, name, constraint string) (pluginframework.Plugin, error) {
+	r.name = name
+	r.constraint = constraint
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &mock.PluginMock{}, nil
+}
+
+func TestResolvePlugin(t *testing.T) {
+	// the CLI manager resolves the plugins of the keys
+	var _ PluginResolver = (*plugin.CLIManager)(nil)
+
+	key := KeySuite{
+		Name: "external-key",
+		ExternalKey: &ExternalKey{
+			ID:            "id1",
+			PluginName:    "pluginX",
+			PluginVersion: "^1.2",
+		},
+	}
+
+	t.Run("honors plugin version", func(t *testing.T) {
+		resolver := &fakePluginResolver{}
+		if _, err := key.ResolvePlugin(context.Background(), resolver); err != nil {
+			t.Fatalf("ResolvePlugin() failed: %v", err)
+		}
+		if resolver.name != "pluginX" || resolver.constraint != "^1.2" {
+			t.Fatalf("ResolvePlugin() resolved %s with constraint %q, want pluginX with constraint \"^1.2\"", resolver.name, resolver.constraint)
+		}
+	})
+
+	t.Run("no installed version", func(t *testing.T) {
+		mgr := plugin.NewCLIManager(dir.NewSysFS(t.TempDir()))
+		_, err := key.ResolvePlugin(context.Background(), mgr)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("ResolvePlugin() error = %v, want os.ErrNotExist", err)
+		}
+	})
+
+	t.Run("resolver error", func(t *testing.T) {
+		resolver := &fakePluginResolver{err: errors.New("resolver error")}
+		if _, err := key.ResolvePlugin(context.Background(), resolver); err == nil {
+			t.Fatal("expected ResolvePlugin() to fail")
+		}
+	})
+
+	t.Run("not a plugin key", func(t *testing.T) {
+		if _, err := sampleSigningKeysInfo.Keys[0].ResolvePlugin(context.Background(), &fakePluginResolver{}); err == nil {
+			t.Fatal("expected ResolvePlugin() to fail for a local key")
+		}
+	})
+
+	t.Run("nil resolver", func(t *testing.T) {
+		if _, err := key.ResolvePlugin(context.Background(), nil); err == nil {
+			t.Fatal("expected ResolvePlugin() to fail for nil resolver")
+		}
+	})
+}
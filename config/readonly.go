@@ -0,0 +1,80 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"syscall"
+)
+
+// readOnly is true if the config is in read-only mode.
+var readOnly atomic.Bool
+
+// SetReadOnly sets the read-only mode of the config. In read-only mode, the
+// Save operations return a [ReadOnlyError] without writing, while the Load
+// operations still work, e.g. for containers mounting the notation config
+// directory read-only.
+//
+// Saving to a read-only file system returns a [ReadOnlyError] as well,
+// whether or not the read-only mode is set.
+func SetReadOnly(enabled bool) {
+	readOnly.Store(enabled)
+}
+
+// IsReadOnly returns true if the config is in read-only mode.
+func IsReadOnly() bool {
+	return readOnly.Load()
+}
+
+// ReadOnlyError is used when saving a config file in read-only mode or to a
+// read-only file system.
+type ReadOnlyError struct {
+	// Path is the path of the config file.
+	Path string
+
+	// InnerError is the error of the file system, if any.
+	InnerError error
+}
+
+// Error returns the error message.
+func (e ReadOnlyError) Error() string {
+	if e.InnerError != nil {
+		return fmt.Sprintf("cannot save %s: config is on a read-only file system: %v", e.Path, e.InnerError)
+	}
+	return fmt.Sprintf("cannot save %s: config is in read-only mode", e.Path)
+}
+
+// Unwrap returns the inner error.
+func (e ReadOnlyError) Unwrap() error {
+	return e.InnerError
+}
+
+// checkWritable returns a ReadOnlyError if the config is in read-only mode.
+func checkWritable(path string) error {
+	if IsReadOnly() {
+		return ReadOnlyError{Path: path}
+	}
+	return nil
+}
+
+// wrapReadOnly returns a ReadOnlyError wrapping err if it is caused by a
+// read-only file system, or err otherwise.
+func wrapReadOnly(path string, err error) error {
+	if errors.Is(err, syscall.EROFS) {
+		return ReadOnlyError{Path: path, InnerError: err}
+	}
+	return err
+}
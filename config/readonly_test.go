@@ -0,0 +1,87 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/notaryproject/notation-go/dir"
+)
+
+func TestReadOnlyMode(t *testing.T) {
+	root := t.TempDir()
+	dir.UserConfigDir = root
+	if err := sampleConfig.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+	if !IsReadOnly() {
+		t.Fatal("IsReadOnly() = false after SetReadOnly(true)")
+	}
+
+	// saving fails
+	config := NewConfig()
+	var readOnlyErr ReadOnlyError
+	if err := config.Save(); !errors.As(err, &readOnlyErr) {
+		t.Fatalf("Config.Save() error = %v, want ReadOnlyError", err)
+	}
+	if readOnlyErr.Path != filepath.Join(root, dir.PathConfigFile) {
+		t.Fatalf("ReadOnlyError.Path = %q", readOnlyErr.Path)
+	}
+	if err := NewSigningKeys().Save(); !errors.As(err, &ReadOnlyError{}) {
+		t.Fatalf("SigningKeys.Save() error = %v, want ReadOnlyError", err)
+	}
+	if err := LoadExecSaveSigningKeys(func(keys *SigningKeys) error { return nil }); !errors.As(err, &ReadOnlyError{}) {
+		t.Fatalf("LoadExecSaveSigningKeys() error = %v, want ReadOnlyError", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, dir.PathSigningKeys)); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal("SigningKeys.Save() wrote in read-only mode")
+	}
+
+	// loading works
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, sampleConfig) {
+		t.Fatalf("LoadConfig() = %v, want %v", got, sampleConfig)
+	}
+	if _, err := LoadSigningKeys(); err != nil {
+		t.Fatalf("LoadSigningKeys() error = %v", err)
+	}
+}
+
+func TestWrapReadOnly(t *testing.T) {
+	roErr := &fs.PathError{Op: "open", Path: "config.json", Err: syscall.EROFS}
+	err := wrapReadOnly("config.json", roErr)
+	if !errors.As(err, &ReadOnlyError{}) || !errors.Is(err, syscall.EROFS) {
+		t.Fatalf("wrapReadOnly() = %v, want ReadOnlyError wrapping EROFS", err)
+	}
+	if want := "cannot save config.json: config is on a read-only file system: open config.json: read-only file system"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	permErr := &fs.PathError{Op: "open", Path: "config.json", Err: syscall.EACCES}
+	if err := wrapReadOnly("config.json", permErr); err != permErr {
+		t.Fatalf("wrapReadOnly() = %v, want %v", err, permErr)
+	}
+}
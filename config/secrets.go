@@ -0,0 +1,235 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Schemes of the secret references.
+const (
+	// SecretSchemeEnv references an environment variable, e.g.
+	// "env://KMS_TOKEN".
+	SecretSchemeEnv = "env"
+
+	// SecretSchemeFile references the content of a file, e.g.
+	// "file:///run/secrets/kms-token". The trailing newline is trimmed.
+	SecretSchemeFile = "file"
+
+	// SecretSchemeVault references a field of a HashiCorp Vault secret, e.g.
+	// "vault://secret/data/kms#token".
+	SecretSchemeVault = "vault"
+)
+
+// SecretResolver resolves the secret referenced by a URI.
+type SecretResolver interface {
+	// ResolveSecret returns the secret referenced by uri.
+	ResolveSecret(ctx context.Context, uri *url.URL) (string, error)
+}
+
+// SecretResolverFunc is an adapter to allow the use of ordinary functions as
+// [SecretResolver].
+type SecretResolverFunc func(ctx context.Context, uri *url.URL) (string, error)
+
+// ResolveSecret calls f(ctx, uri).
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, uri *url.URL) (string, error) {
+	return f(ctx, uri)
+}
+
+// SecretResolvers resolves the secret references by the scheme of their
+// URIs, so that the secrets in [ExternalKey].PluginConfig, such as the API
+// tokens of KMS plugins, are not stored in plaintext in signingkeys.json.
+type SecretResolvers map[string]SecretResolver
+
+// DefaultSecretResolvers returns the resolvers of the [SecretSchemeEnv] and
+// [SecretSchemeFile] schemes. The resolver of the [SecretSchemeVault] scheme
+// is added with [NewVaultSecretResolver].
+func DefaultSecretResolvers() SecretResolvers {
+	return SecretResolvers{
+		SecretSchemeEnv:  SecretResolverFunc(resolveEnvSecret),
+		SecretSchemeFile: SecretResolverFunc(resolveFileSecret),
+	}
+}
+
+// ResolvePluginConfig returns a copy of the plugin config with the secret
+// references resolved. The values that are not URIs of a scheme of r are
+// kept as is.
+func (r SecretResolvers) ResolvePluginConfig(ctx context.Context, config map[string]string) (map[string]string, error) {
+	if config == nil {
+		return nil, nil
+	}
+	resolved := make(map[string]string, len(config))
+	for key, value := range config {
+		uri, resolver, ok := r.lookup(value)
+		if !ok {
+			resolved[key] = value
+			continue
+		}
+		secret, err := resolver.ResolveSecret(ctx, uri)
+		if err != nil {
+			// the value may hold credentials, only the scheme is reported
+			return nil, fmt.Errorf("failed to resolve the %s secret of plugin config %q: %w", uri.Scheme, key, err)
+		}
+		resolved[key] = secret
+	}
+	return resolved, nil
+}
+
+// lookup returns the resolver of value if it is a secret reference.
+func (r SecretResolvers) lookup(value string) (*url.URL, SecretResolver, bool) {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok {
+		return nil, nil, false
+	}
+	resolver, ok := r[scheme]
+	if !ok || resolver == nil {
+		return nil, nil, false
+	}
+	uri, err := url.Parse(value)
+	if err != nil {
+		return nil, nil, false
+	}
+	return uri, resolver, true
+}
+
+// ResolvePluginConfig returns the plugin config of the external key of the
+// key suite with the secret references resolved by resolvers.
+func (k KeySuite) ResolvePluginConfig(ctx context.Context, resolvers SecretResolvers) (map[string]string, error) {
+	if k.ExternalKey == nil {
+		return nil, fmt.Errorf("signing key %q is not a plugin based key", k.Name)
+	}
+	return resolvers.ResolvePluginConfig(ctx, k.PluginConfig)
+}
+
+// resolveEnvSecret resolves "env://NAME" to the value of the environment
+// variable NAME.
+func resolveEnvSecret(_ context.Context, uri *url.URL) (string, error) {
+	name := uri.Host
+	if name == "" {
+		return "", errors.New("environment variable name is empty")
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// resolveFileSecret resolves "file:///path" to the content of the file at
+// path.
+func resolveFileSecret(_ context.Context, uri *url.URL) (string, error) {
+	if uri.Path == "" {
+		return "", errors.New("file path is empty")
+	}
+	data, err := os.ReadFile(uri.Path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// maxVaultResponseSize is the maximum size of a response of Vault.
+const maxVaultResponseSize = 1 << 20
+
+// VaultSecretResolver resolves "vault://path#field" to the field of the
+// HashiCorp Vault secret at path, read with the HTTP API of Vault. Both the
+// KV version 1 and version 2 secret engines are supported, e.g.
+// "vault://secret/data/kms#token" for the field "token" of the secret "kms"
+// in the KV version 2 engine mounted at "secret".
+type VaultSecretResolver struct {
+	address string
+	token   string
+	client  *http.Client
+}
+
+// NewVaultSecretResolver returns a VaultSecretResolver reading the secrets
+// from the Vault server at address, e.g. "https://vault.example.com:8200",
+// with token. If address or token is empty, the VAULT_ADDR or VAULT_TOKEN
+// environment variable is used respectively. If client is nil,
+// http.DefaultClient is used.
+func NewVaultSecretResolver(address, token string, client *http.Client) (*VaultSecretResolver, error) {
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if address == "" {
+		return nil, errors.New("vault address is not specified")
+	}
+	if token == "" {
+		return nil, errors.New("vault token is not specified")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &VaultSecretResolver{
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+		client:  client,
+	}, nil
+}
+
+// ResolveSecret returns the field of the Vault secret referenced by uri.
+func (v *VaultSecretResolver) ResolveSecret(ctx context.Context, uri *url.URL) (string, error) {
+	path := strings.Trim(uri.Host+uri.Path, "/")
+	if path == "" || uri.Fragment == "" {
+		return "", errors.New("vault secret reference requires the form vault://path#field")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.address+"/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %s for secret %s", resp.Status, path)
+	}
+
+	// the data of KV version 2 secrets is nested in the data of the response
+	var secret struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxVaultResponseSize)).Decode(&secret); err != nil {
+		return "", fmt.Errorf("failed to decode vault secret %s: %w", path, err)
+	}
+	data := secret.Data
+	if nested, ok := data["data"]; ok && data["metadata"] != nil {
+		data = nil
+		if err := json.Unmarshal(nested, &data); err != nil {
+			return "", fmt.Errorf("failed to decode vault secret %s: %w", path, err)
+		}
+	}
+	raw, ok := data[uri.Fragment]
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no field %q", path, uri.Fragment)
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("field %q of vault secret %s is not a string", uri.Fragment, path)
+	}
+	return value, nil
+}
@@ -0,0 +1,131 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSecretResolvers_ResolvePluginConfig(t *testing.T) {
+	t.Setenv("KMS_TOKEN", "env-secret")
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+
+	config := map[string]string{
+		"token":    "env://KMS_TOKEN",
+		"password": "file://" + secretFile,
+		"endpoint": "https://kms.example.com",
+		"region":   "us-west-2",
+	}
+	resolved, err := DefaultSecretResolvers().ResolvePluginConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("ResolvePluginConfig() error = %v", err)
+	}
+	want := map[string]string{
+		"token":    "env-secret",
+		"password": "file-secret",
+		"endpoint": "https://kms.example.com",
+		"region":   "us-west-2",
+	}
+	if !reflect.DeepEqual(resolved, want) {
+		t.Fatalf("ResolvePluginConfig() = %v, want %v", resolved, want)
+	}
+	if config["token"] != "env://KMS_TOKEN" {
+		t.Fatal("ResolvePluginConfig() modified the plugin config")
+	}
+
+	for _, value := range []string{"env://UNSET_KMS_TOKEN", "env://", "file:///non-existent/secret"} {
+		if _, err := DefaultSecretResolvers().ResolvePluginConfig(context.Background(), map[string]string{"token": value}); err == nil {
+			t.Fatalf("ResolvePluginConfig(%q) expects error, got nil", value)
+		}
+	}
+}
+
+func TestKeySuite_ResolvePluginConfig(t *testing.T) {
+	t.Setenv("KMS_TOKEN", "env-secret")
+	ks := KeySuite{
+		Name:        "kms",
+		ExternalKey: &ExternalKey{ID: "key", PluginName: "kms", PluginConfig: map[string]string{"token": "env://KMS_TOKEN"}},
+	}
+	resolved, err := ks.ResolvePluginConfig(context.Background(), DefaultSecretResolvers())
+	if err != nil || resolved["token"] != "env-secret" {
+		t.Fatalf("ResolvePluginConfig() = %v, %v", resolved, err)
+	}
+	if _, err := (KeySuite{Name: "local"}).ResolvePluginConfig(context.Background(), DefaultSecretResolvers()); err == nil {
+		t.Fatal("ResolvePluginConfig() expects error for a local key")
+	}
+}
+
+func TestVaultSecretResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/kms":
+			w.Write([]byte(`{"data": {"data": {"token": "kv2-secret"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/kms":
+			w.Write([]byte(`{"data": {"token": "kv1-secret", "ttl": 3600}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	vault, err := NewVaultSecretResolver(server.URL, "vault-token", server.Client())
+	if err != nil {
+		t.Fatalf("NewVaultSecretResolver() error = %v", err)
+	}
+	resolvers := DefaultSecretResolvers()
+	resolvers[SecretSchemeVault] = vault
+	resolved, err := resolvers.ResolvePluginConfig(context.Background(), map[string]string{
+		"kv2": "vault://secret/data/kms#token",
+		"kv1": "vault://kv/kms#token",
+	})
+	if err != nil {
+		t.Fatalf("ResolvePluginConfig() error = %v", err)
+	}
+	if want := map[string]string{"kv2": "kv2-secret", "kv1": "kv1-secret"}; !reflect.DeepEqual(resolved, want) {
+		t.Fatalf("ResolvePluginConfig() = %v, want %v", resolved, want)
+	}
+
+	for _, value := range []string{
+		"vault://secret/data/kms",         // no field
+		"vault://secret/data/kms#missing", // missing field
+		"vault://kv/kms#ttl",              // not a string
+		"vault://secret/data/unknown#token",
+	} {
+		if _, err := resolvers.ResolvePluginConfig(context.Background(), map[string]string{"token": value}); err == nil {
+			t.Fatalf("ResolvePluginConfig(%q) expects error, got nil", value)
+		}
+	}
+
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TOKEN", "")
+	if _, err := NewVaultSecretResolver("", "vault-token", nil); err == nil {
+		t.Fatal("NewVaultSecretResolver() expects error without address")
+	}
+	if _, err := NewVaultSecretResolver(server.URL, "", nil); err == nil {
+		t.Fatal("NewVaultSecretResolver() expects error without token")
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/log"
+)
+
+// SigningKeysStore manages the signingkeys.json file on behalf of a process,
+// e.g. a daemon managing the keys of its users.
+//
+// The changes made through the store are serialized within the process, and
+// applied to the latest content of the file, so that the changes made by
+// other processes are not overwritten. The subscribers are notified of the
+// changes made through the store and, with [SigningKeysStore.Reload] or
+// [SigningKeysStore.Watch], of the changes made to the file by other
+// processes.
+//
+// A SigningKeysStore is safe for concurrent use.
+type SigningKeysStore struct {
+	mu    sync.Mutex
+	keys  *SigningKeys
+	stamp fileStamp
+
+	subscribersMu sync.Mutex
+	subscribers   map[int]func(*SigningKeys)
+	nextID        int
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewSigningKeysStore creates a SigningKeysStore loading the signingkeys.json
+// file.
+func NewSigningKeysStore() (*SigningKeysStore, error) {
+	s := &SigningKeysStore{
+		subscribers: make(map[int]func(*SigningKeys)),
+	}
+	if _, err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Keys returns a copy of the signing keys.
+func (s *SigningKeysStore) Keys() *SigningKeys {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys.clone()
+}
+
+// Add adds a new signing key. See [SigningKeys.Add].
+func (s *SigningKeysStore) Add(name, keyPath, certPath string, markDefault bool) error {
+	return s.Update(func(keys *SigningKeys) error {
+		return keys.Add(name, keyPath, certPath, markDefault)
+	})
+}
+
+// AddPlugin adds a new plugin based signing key. See [SigningKeys.AddPlugin].
+func (s *SigningKeysStore) AddPlugin(ctx context.Context, keyName, id, pluginName string, pluginConfig map[string]string, markDefault bool) error {
+	return s.Update(func(keys *SigningKeys) error {
+		return keys.AddPlugin(ctx, keyName, id, pluginName, pluginConfig, markDefault)
+	})
+}
+
+// Import imports the PEM encoded private key and certificate chain of a
+// signing key into the localkeys directory and adds the key. See
+// [SigningKeys.Import].
+func (s *SigningKeysStore) Import(name string, keyPEM, certPEM []byte, markDefault bool) error {
+	var remove func()
+	err := s.Update(func(keys *SigningKeys) error {
+		var err error
+		remove, err = keys.importKey(name, keyPEM, certPEM, markDefault)
+		return err
+	})
+	if err != nil && remove != nil {
+		// the key is not saved
+		remove()
+	}
+	return err
+}
+
+// Remove deletes the signing keys and returns the names of the deleted keys.
+// See [SigningKeys.Remove].
+func (s *SigningKeysStore) Remove(keyName ...string) ([]string, error) {
+	var deletedNames []string
+	err := s.Update(func(keys *SigningKeys) error {
+		var err error
+		deletedNames, err = keys.Remove(keyName...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deletedNames, nil
+}
+
+// UpdateDefault updates the default signing key. See
+// [SigningKeys.UpdateDefault].
+func (s *SigningKeysStore) UpdateDefault(keyName string) error {
+	return s.Update(func(keys *SigningKeys) error {
+		return keys.UpdateDefault(keyName)
+	})
+}
+
+// Update reloads the signing keys if the file has changed, applies fn to a
+// copy of the keys and saves them. The keys are not changed if fn returns an
+// error. The subscribers are notified of the saved keys.
+func (s *SigningKeysStore) Update(fn func(keys *SigningKeys) error) error {
+	s.mu.Lock()
+	reloaded, err := s.reloadLocked()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	// s.keys is replaced, never modified, so it is notified after unlocking
+	var snapshot *SigningKeys
+	if reloaded {
+		snapshot = s.keys
+	}
+	keys := s.keys.clone()
+	err = fn(keys)
+	if err == nil {
+		err = s.saveLocked(keys)
+	}
+	if err == nil {
+		snapshot = s.keys
+	}
+	s.mu.Unlock()
+
+	if snapshot != nil {
+		s.notify(snapshot)
+	}
+	return err
+}
+
+// Reload reloads the signing keys if the file has been changed by another
+// process, and notifies the subscribers. It returns true if the keys were
+// reloaded.
+func (s *SigningKeysStore) Reload() (bool, error) {
+	return s.reload()
+}
+
+// Watch reloads the signing keys every interval with
+// [SigningKeysStore.Reload] until ctx is done. The reload errors, e.g. a
+// malformed file being written, are logged and the previous keys are kept.
+func (s *SigningKeysStore) Watch(ctx context.Context, interval time.Duration) {
+	logger := log.GetLogger(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.reload(); err != nil {
+				logger.Warnf("Failed to reload %s: %v", dir.PathSigningKeys, err)
+			}
+		}
+	}
+}
+
+// Subscribe registers fn to be called with a copy of the signing keys after
+// each change. fn is called synchronously and must not block. The returned
+// function unregisters fn.
+func (s *SigningKeysStore) Subscribe(fn func(keys *SigningKeys)) func() {
+	s.subscribersMu.Lock()
+	defer s.subscribersMu.Unlock()
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = fn
+	return func() {
+		s.subscribersMu.Lock()
+		defer s.subscribersMu.Unlock()
+		delete(s.subscribers, id)
+	}
+}
+
+// reload reloads the signing keys if the file has changed, and notifies the
+// subscribers.
+func (s *SigningKeysStore) reload() (bool, error) {
+	s.mu.Lock()
+	reloaded, err := s.reloadLocked()
+	var snapshot *SigningKeys
+	if reloaded {
+		snapshot = s.keys
+	}
+	s.mu.Unlock()
+	if snapshot != nil {
+		s.notify(snapshot)
+	}
+	return reloaded, err
+}
+
+// reloadLocked reloads the signing keys if the file has changed since it was
+// last loaded or saved. s.mu must be held.
+func (s *SigningKeysStore) reloadLocked() (bool, error) {
+	stamp, err := signingKeysStamp()
+	if err != nil {
+		return false, err
+	}
+	if s.keys != nil && stamp == s.stamp {
+		return false, nil
+	}
+	keys, err := LoadSigningKeys()
+	if err != nil {
+		return false, err
+	}
+	s.keys = keys
+	s.stamp = stamp
+	return true, nil
+}
+
+// saveLocked saves keys to the file. s.mu must be held.
+func (s *SigningKeysStore) saveLocked(keys *SigningKeys) error {
+	if err := keys.Save(); err != nil {
+		return err
+	}
+	stamp, err := signingKeysStamp()
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	s.stamp = stamp
+	return nil
+}
+
+// notify calls the subscribers with a copy of keys each.
+func (s *SigningKeysStore) notify(keys *SigningKeys) {
+	s.subscribersMu.Lock()
+	subscribers := make([]func(*SigningKeys), 0, len(s.subscribers))
+	for _, fn := range s.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	s.subscribersMu.Unlock()
+	for _, fn := range subscribers {
+		fn(keys.clone())
+	}
+}
+
+// signingKeysStamp returns the stamp of the signingkeys.json file, or a zero
+// stamp if it does not exist.
+func signingKeysStamp() (fileStamp, error) {
+	path, err := dir.ConfigFS().SysPath(dir.PathSigningKeys)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fileStamp{}, nil
+		}
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: fileInfo.ModTime(), size: fileInfo.Size()}, nil
+}
+
+// clone returns a deep copy of the signing keys.
+func (s *SigningKeys) clone() *SigningKeys {
+	if s == nil {
+		return nil
+	}
+	keys := &SigningKeys{Keys: make([]KeySuite, len(s.Keys))}
+	if s.Default != nil {
+		defaultKey := *s.Default
+		keys.Default = &defaultKey
+	}
+	for i, key := range s.Keys {
+		if key.X509KeyPair != nil {
+			keyPair := *key.X509KeyPair
+			key.X509KeyPair = &keyPair
+		}
+		if key.ExternalKey != nil {
+			externalKey := *key.ExternalKey
+			externalKey.PluginConfig = maps.Clone(externalKey.PluginConfig)
+			key.ExternalKey = &externalKey
+		}
+		keys.Keys[i] = key
+	}
+	return keys
+}
@@ -0,0 +1,170 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/dir"
+)
+
+func TestSigningKeysStore(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	certPath, keyPath := createTempCertKey(t)
+	store, err := NewSigningKeysStore()
+	if err != nil {
+		t.Fatalf("NewSigningKeysStore() error = %v", err)
+	}
+	var notified []*SigningKeys
+	unsubscribe := store.Subscribe(func(keys *SigningKeys) {
+		notified = append(notified, keys)
+	})
+
+	if err := store.Add("key1", keyPath, certPath, true); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := store.Add("key2", keyPath, certPath, false); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := store.Add("key2", keyPath, certPath, false); err == nil {
+		t.Fatal("Add() expects error for duplicate key name")
+	}
+	if err := store.UpdateDefault("key2"); err != nil {
+		t.Fatalf("UpdateDefault() error = %v", err)
+	}
+	deleted, err := store.Remove("key1")
+	if err != nil || len(deleted) != 1 || deleted[0] != "key1" {
+		t.Fatalf("Remove() = %v, %v", deleted, err)
+	}
+	if len(notified) != 4 {
+		t.Fatalf("notified %d times, want 4", len(notified))
+	}
+	if last := notified[3]; len(last.Keys) != 1 || *last.Default != "key2" {
+		t.Fatalf("notified keys = %+v", last)
+	}
+
+	// the keys are saved
+	saved, err := LoadSigningKeys()
+	if err != nil {
+		t.Fatalf("LoadSigningKeys() error = %v", err)
+	}
+	if len(saved.Keys) != 1 || saved.Keys[0].Name != "key2" || *saved.Default != "key2" {
+		t.Fatalf("saved keys = %+v", saved)
+	}
+
+	// the returned keys are copies
+	keys := store.Keys()
+	keys.Keys[0].KeyPath = "modified"
+	if store.Keys().Keys[0].KeyPath == "modified" {
+		t.Fatal("Keys() returned the keys of the store")
+	}
+
+	unsubscribe()
+	if err := store.UpdateDefault("key2"); err != nil {
+		t.Fatalf("UpdateDefault() error = %v", err)
+	}
+	if len(notified) != 4 {
+		t.Fatal("unsubscribed function was notified")
+	}
+}
+
+func TestSigningKeysStore_Reload(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	certPath, keyPath := createTempCertKey(t)
+	store, err := NewSigningKeysStore()
+	if err != nil {
+		t.Fatalf("NewSigningKeysStore() error = %v", err)
+	}
+	if reloaded, err := store.Reload(); err != nil || reloaded {
+		t.Fatalf("Reload() = %v, %v, want no reload", reloaded, err)
+	}
+	notified := make(chan *SigningKeys, 1)
+	store.Subscribe(func(keys *SigningKeys) {
+		notified <- keys
+	})
+
+	// another process adds a key
+	external := NewSigningKeys()
+	if err := external.Add("external", keyPath, certPath, true); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := external.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go store.Watch(ctx, time.Millisecond)
+	select {
+	case keys := <-notified:
+		if len(keys.Keys) != 1 || keys.Keys[0].Name != "external" {
+			t.Fatalf("notified keys = %+v", keys)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("external change was not notified")
+	}
+	cancel()
+
+	// the changes are applied to the latest keys
+	if err := store.Add("local", keyPath, certPath, false); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if keys := store.Keys(); len(keys.Keys) != 2 {
+		t.Fatalf("Keys() = %+v, want the external and the local keys", keys)
+	}
+
+	// a malformed file is not loaded
+	path := filepath.Join(dir.UserConfigDir, dir.PathSigningKeys)
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatalf("failed to write signing keys: %v", err)
+	}
+	if _, err := store.Reload(); err == nil {
+		t.Fatal("Reload() expects error for malformed file")
+	}
+	if keys := store.Keys(); len(keys.Keys) != 2 {
+		t.Fatalf("Keys() = %+v, want the previous keys", keys)
+	}
+}
+
+func TestSigningKeysStore_Concurrent(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	certPath, keyPath := createTempCertKey(t)
+	store, err := NewSigningKeysStore()
+	if err != nil {
+		t.Fatalf("NewSigningKeysStore() error = %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := store.Add(fmt.Sprintf("key%d", i), keyPath, certPath, false); err != nil {
+				t.Errorf("Add() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	saved, err := LoadSigningKeys()
+	if err != nil {
+		t.Fatalf("LoadSigningKeys() error = %v", err)
+	}
+	if len(saved.Keys) != 10 {
+		t.Fatalf("saved %d keys, want 10", len(saved.Keys))
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/log"
+)
+
+// KeyUsage is a record of the key usage audit trail: the signature of an
+// artifact with a signing key.
+type KeyUsage struct {
+	// Time is the time of the signature.
+	Time time.Time `json:"time"`
+
+	// Key is the name of the signing key.
+	Key string `json:"key"`
+
+	// Plugin is the name of the plugin of an external key.
+	Plugin string `json:"plugin,omitempty"`
+
+	// KeyID is the ID of an external key.
+	KeyID string `json:"keyId,omitempty"`
+
+	// CertificateThumbprint is the hex encoded SHA-256 thumbprint of the
+	// signing certificate.
+	CertificateThumbprint string `json:"certificateThumbprint,omitempty"`
+
+	// Artifact is the digest of the signed artifact.
+	Artifact string `json:"artifact"`
+
+	// Repository is the repository of the signed artifact, e.g.
+	// "registry.example/software/net-monitor", if known.
+	Repository string `json:"repository,omitempty"`
+
+	// Reference is the reference of the signed artifact as given to sign,
+	// e.g. a tag.
+	Reference string `json:"reference,omitempty"`
+
+	// Signature is the digest of the signature manifest.
+	Signature string `json:"signature,omitempty"`
+}
+
+// KeyUsageLog is an append-only JSON Lines audit trail of the usage of the
+// signing keys, so that the artifacts signed by a key can be listed after an
+// incident, e.g. a key compromise. Each line is a [KeyUsage] record.
+//
+// The records are written by the audit sink returned by [KeyUsageLog.Sink].
+// KeyUsageLog is safe for concurrent use.
+type KeyUsageLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewKeyUsageLog returns a KeyUsageLog appending the records to w, e.g. a
+// file or a log shipper.
+func NewKeyUsageLog(w io.Writer) *KeyUsageLog {
+	return &KeyUsageLog{w: w}
+}
+
+// OpenKeyUsageLog opens the keyusage.jsonl file in the config directory to
+// append the records, and creates it if it does not exist. The file is only
+// readable by the owner. The caller must close the KeyUsageLog.
+func OpenKeyUsageLog() (*KeyUsageLog, error) {
+	path, err := dir.ConfigFS().SysPath(dir.PathKeyUsageLog)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWritable(path); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, wrapReadOnly(path, err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, wrapReadOnly(path, err)
+	}
+	return &KeyUsageLog{w: f, closer: f}, nil
+}
+
+// Record appends the record usage to the log. The time of the record is set
+// if it is zero.
+func (l *KeyUsageLog) Record(usage KeyUsage) error {
+	if usage.Time.IsZero() {
+		usage.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// a record is written in a single write, so that concurrent writers
+	// appending to the same file do not interleave
+	if _, err := l.w.Write(line); err != nil {
+		return fmt.Errorf("failed to record key usage: %w", err)
+	}
+	return nil
+}
+
+// Sink returns an audit sink recording the signatures of artifacts with the
+// signing key key. The other audit events are ignored. A failure to record
+// is logged, but does not fail the signing.
+//
+// Example:
+//
+//	ctx = audit.WithSink(ctx, usageLog.Sink(key))
+//	_, err = notation.Sign(ctx, signer, repo, signOpts)
+func (l *KeyUsageLog) Sink(key KeySuite) audit.Sink {
+	return audit.SinkFunc(func(ctx context.Context, event audit.Event) {
+		if event.Type != audit.EventArtifactSigned {
+			return
+		}
+		usage := KeyUsage{
+			Time:                  event.Time,
+			Key:                   key.Name,
+			CertificateThumbprint: event.Attributes["certificateThumbprint"],
+			Artifact:              event.Artifact,
+			Repository:            event.Attributes["repository"],
+			Reference:             event.Attributes["reference"],
+			Signature:             event.Attributes["signature"],
+		}
+		if key.ExternalKey != nil {
+			usage.Plugin = key.PluginName
+			usage.KeyID = key.ID
+		}
+		if err := l.Record(usage); err != nil {
+			log.GetLogger(ctx).Warnf("Failed to record the usage of signing key %s: %v", key.Name, err)
+		}
+	})
+}
+
+// Close closes the file opened by [OpenKeyUsageLog]. It is a no-op for the
+// KeyUsageLog returned by [NewKeyUsageLog].
+func (l *KeyUsageLog) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// ReadKeyUsage reads the records of the key usage log from r. If key is not
+// empty, only the records of the signing key key are returned.
+func ReadKeyUsage(r io.Reader, key string) ([]KeyUsage, error) {
+	var usages []KeyUsage
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var usage KeyUsage
+		if err := json.Unmarshal(scanner.Bytes(), &usage); err != nil {
+			return nil, fmt.Errorf("malformed key usage record at line %d: %w", line, err)
+		}
+		if key == "" || usage.Key == key {
+			usages = append(usages, usage)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
+// LoadKeyUsage reads the records of the keyusage.jsonl file in the config
+// directory. If key is not empty, only the records of the signing key key are
+// returned. No record is returned if the file does not exist.
+func LoadKeyUsage(key string) ([]KeyUsage, error) {
+	f, err := dir.ConfigFS().Open(dir.PathKeyUsageLog)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return ReadKeyUsage(f, key)
+}
@@ -0,0 +1,120 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/dir"
+)
+
+func TestKeyUsageLog(t *testing.T) {
+	var buf bytes.Buffer
+	usageLog := NewKeyUsageLog(&buf)
+	localKey := KeySuite{Name: "local", X509KeyPair: &X509KeyPair{KeyPath: "key", CertificatePath: "cert"}}
+	externalKey := KeySuite{Name: "external", ExternalKey: &ExternalKey{ID: "id", PluginName: "plugin"}}
+
+	signedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := audit.WithSink(context.Background(), usageLog.Sink(localKey))
+	audit.Emit(ctx, audit.Event{Type: audit.EventKeyLoaded, Artifact: "sha256:a"})
+	audit.Emit(ctx, audit.Event{
+		Type:     audit.EventArtifactSigned,
+		Time:     signedAt,
+		Artifact: "sha256:a",
+		Attributes: map[string]string{
+			"repository":            "registry.example/app",
+			"reference":             "registry.example/app:v1",
+			"signature":             "sha256:b",
+			"certificateThumbprint": "abcd",
+		},
+	})
+	ctx = audit.WithSink(context.Background(), usageLog.Sink(externalKey))
+	audit.Emit(ctx, audit.Event{Type: audit.EventArtifactSigned, Artifact: "sha256:c"})
+	if err := usageLog.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Fatalf("got %d records, want 2", lines)
+	}
+	usages, err := ReadKeyUsage(bytes.NewReader(buf.Bytes()), "local")
+	if err != nil {
+		t.Fatalf("ReadKeyUsage() error = %v", err)
+	}
+	want := KeyUsage{
+		Time:                  signedAt,
+		Key:                   "local",
+		CertificateThumbprint: "abcd",
+		Artifact:              "sha256:a",
+		Repository:            "registry.example/app",
+		Reference:             "registry.example/app:v1",
+		Signature:             "sha256:b",
+	}
+	if len(usages) != 1 || usages[0] != want {
+		t.Fatalf("ReadKeyUsage() = %+v, want %+v", usages, want)
+	}
+
+	usages, err = ReadKeyUsage(bytes.NewReader(buf.Bytes()), "")
+	if err != nil {
+		t.Fatalf("ReadKeyUsage() error = %v", err)
+	}
+	if len(usages) != 2 || usages[1].Plugin != "plugin" || usages[1].KeyID != "id" || usages[1].Time.IsZero() {
+		t.Fatalf("ReadKeyUsage() = %+v", usages)
+	}
+
+	if _, err := ReadKeyUsage(strings.NewReader("{\n"), ""); err == nil {
+		t.Fatal("ReadKeyUsage() expects error for malformed record")
+	}
+}
+
+func TestOpenKeyUsageLog(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	usages, err := LoadKeyUsage("")
+	if err != nil || usages != nil {
+		t.Fatalf("LoadKeyUsage() = %v, %v, want no record", usages, err)
+	}
+
+	// records are appended across opens
+	for _, artifact := range []string{"sha256:a", "sha256:b"} {
+		usageLog, err := OpenKeyUsageLog()
+		if err != nil {
+			t.Fatalf("OpenKeyUsageLog() error = %v", err)
+		}
+		if err := usageLog.Record(KeyUsage{Key: "key", Artifact: artifact}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		if err := usageLog.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+	usages, err = LoadKeyUsage("key")
+	if err != nil {
+		t.Fatalf("LoadKeyUsage() error = %v", err)
+	}
+	if len(usages) != 2 || usages[0].Artifact != "sha256:a" || usages[1].Artifact != "sha256:b" {
+		t.Fatalf("LoadKeyUsage() = %+v", usages)
+	}
+
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+	if _, err := OpenKeyUsageLog(); !errors.As(err, &ReadOnlyError{}) {
+		t.Fatalf("OpenKeyUsageLog() error = %v, want ReadOnlyError", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	corex509 "github.com/notaryproject/notation-core-go/x509"
+	"github.com/notaryproject/notation-go/signer"
+)
+
+// KeySuiteReport is the result of checking a key suite with
+// [KeySuite.Check].
+type KeySuiteReport struct {
+	// Name is the name of the key suite.
+	Name string
+
+	// Err is the error of the check, joining all the problems found, or nil
+	// if the key suite is valid.
+	Err error
+}
+
+// ValidationReport is the per-key result of [SigningKeys.Validate], in the
+// order of the keys.
+type ValidationReport []KeySuiteReport
+
+// Valid returns true if all the key suites are valid.
+func (r ValidationReport) Valid() bool {
+	for _, report := range r {
+		if report.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks each key suite with [KeySuite.Check], so that problems
+// with the keys surface before signing. It returns an error if the signing
+// keys are malformed, e.g. with duplicate key names.
+func (s *SigningKeys) Validate(ctx context.Context, resolver PluginResolver) (ValidationReport, error) {
+	if err := validateKeys(s); err != nil {
+		return nil, err
+	}
+	report := make(ValidationReport, 0, len(s.Keys))
+	for _, key := range s.Keys {
+		report = append(report, KeySuiteReport{
+			Name: key.Name,
+			Err:  key.Check(ctx, resolver),
+		})
+	}
+	return report, nil
+}
+
+// Check checks that the key suite is usable for signing:
+//   - for a local key, the key and certificate files exist and parse, the
+//     private key matches the public key of the signing certificate, and the
+//     certificate chain satisfies the code signing requirements at the
+//     current time.
+//   - for a plugin based key, the plugin is resolved with resolver, honoring
+//     the PluginVersion constraint. The plugin is not checked if resolver is
+//     nil.
+//
+// It returns an error joining all the problems found, or nil if the key suite
+// is valid.
+func (k KeySuite) Check(ctx context.Context, resolver PluginResolver) error {
+	switch {
+	case k.X509KeyPair != nil && k.ExternalKey != nil:
+		return fmt.Errorf("signing key %q is both a local and a plugin based key", k.Name)
+	case k.X509KeyPair != nil:
+		return k.checkX509KeyPair()
+	case k.ExternalKey != nil:
+		if resolver == nil {
+			return nil
+		}
+		_, err := k.ResolvePlugin(ctx, resolver)
+		return err
+	default:
+		return fmt.Errorf("signing key %q has neither a key pair nor a plugin", k.Name)
+	}
+}
+
+// checkX509KeyPair checks the local key pair of the key suite.
+func (k KeySuite) checkX509KeyPair() error {
+	var errs []error
+	for _, path := range []string{k.KeyPath, k.CertificatePath} {
+		if path == "" {
+			errs = append(errs, fmt.Errorf("signing key %q has an empty file path", k.Name))
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("signing key %q: %w", k.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	privateKey, err := corex509.ReadPrivateKeyFile(k.KeyPath)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("signing key %q: failed to parse private key: %w", k.Name, err))
+	}
+	certs, err := corex509.ReadCertificateFile(k.CertificatePath)
+	if err == nil && len(certs) == 0 {
+		err = errors.New("no certificate found")
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("signing key %q: failed to parse certificate: %w", k.Name, err))
+		return errors.Join(errs...)
+	}
+
+	// the certificate chain is validated with the rules applied at signing
+	result := signer.ValidateCertificateChain(certs, signer.CertificateChainOptions{PrivateKey: privateKey})
+	for _, err := range result.Errors {
+		errs = append(errs, fmt.Errorf("signing key %q: %w", k.Name, err))
+	}
+	return errors.Join(errs...)
+}
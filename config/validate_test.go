@@ -0,0 +1,163 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+)
+
+// writeKeyPair writes the PEM encoded key and certificate chain, and returns
+// their paths.
+func writeKeyPair(t *testing.T, key any, certs ...*x509.Certificate) (string, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+	keyPath := filepath.Join(tempDir, "key.pem")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	var certData []byte
+	for _, cert := range certs {
+		certData = append(certData, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	certPath := filepath.Join(tempDir, "cert.pem")
+	if err := os.WriteFile(certPath, certData, 0600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	return keyPath, certPath
+}
+
+func TestKeySuiteCheck(t *testing.T) {
+	leaf := testhelper.GetRSALeafCertificate()
+	root := testhelper.GetRSARootCertificate()
+	other := testhelper.GetECLeafCertificate()
+
+	validKeyPath, validCertPath := writeKeyPair(t, leaf.PrivateKey, leaf.Cert, root.Cert)
+	mismatchKeyPath, mismatchCertPath := writeKeyPair(t, other.PrivateKey, leaf.Cert, root.Cert)
+	caKeyPath, caCertPath := writeKeyPair(t, root.PrivateKey, root.Cert)
+	invalidPath := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidPath, []byte("invalid"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		key     KeySuite
+		wantErr bool
+	}{
+		{
+			name: "valid local key",
+			key:  KeySuite{Name: "valid", X509KeyPair: &X509KeyPair{KeyPath: validKeyPath, CertificatePath: validCertPath}},
+		},
+		{
+			name:    "missing files",
+			key:     KeySuite{Name: "missing", X509KeyPair: &X509KeyPair{KeyPath: filepath.Join(t.TempDir(), "key"), CertificatePath: ""}},
+			wantErr: true,
+		},
+		{
+			name:    "unparsable files",
+			key:     KeySuite{Name: "unparsable", X509KeyPair: &X509KeyPair{KeyPath: invalidPath, CertificatePath: invalidPath}},
+			wantErr: true,
+		},
+		{
+			name:    "key not matching certificate",
+			key:     KeySuite{Name: "mismatch", X509KeyPair: &X509KeyPair{KeyPath: mismatchKeyPath, CertificatePath: mismatchCertPath}},
+			wantErr: true,
+		},
+		{
+			name:    "not a code signing certificate",
+			key:     KeySuite{Name: "ca", X509KeyPair: &X509KeyPair{KeyPath: caKeyPath, CertificatePath: caCertPath}},
+			wantErr: true,
+		},
+		{
+			name: "plugin key without resolver",
+			key:  KeySuite{Name: "plugin", ExternalKey: &ExternalKey{ID: "id", PluginName: "pluginX"}},
+		},
+		{
+			name:    "neither local nor plugin key",
+			key:     KeySuite{Name: "empty"},
+			wantErr: true,
+		},
+		{
+			name: "both local and plugin key",
+			key: KeySuite{
+				Name:        "both",
+				X509KeyPair: &X509KeyPair{KeyPath: validKeyPath, CertificatePath: validCertPath},
+				ExternalKey: &ExternalKey{ID: "id", PluginName: "pluginX"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.key.Check(context.Background(), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// the plugin is resolved
+	pluginKey := KeySuite{Name: "plugin", ExternalKey: &ExternalKey{ID: "id", PluginName: "pluginX"}}
+	if err := pluginKey.Check(context.Background(), &fakePluginResolver{err: errors.New("not installed")}); err == nil {
+		t.Fatal("Check() expects error for a plugin not installed")
+	}
+	if err := pluginKey.Check(context.Background(), &fakePluginResolver{}); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+}
+
+func TestSigningKeysValidate(t *testing.T) {
+	leaf := testhelper.GetRSALeafCertificate()
+	root := testhelper.GetRSARootCertificate()
+	keyPath, certPath := writeKeyPair(t, leaf.PrivateKey, leaf.Cert, root.Cert)
+	defaultKey := "valid"
+	keys := &SigningKeys{
+		Default: &defaultKey,
+		Keys: []KeySuite{
+			{Name: "valid", X509KeyPair: &X509KeyPair{KeyPath: keyPath, CertificatePath: certPath}},
+			{Name: "missing", X509KeyPair: &X509KeyPair{KeyPath: "missing.key", CertificatePath: "missing.crt"}},
+		},
+	}
+	report, err := keys.Validate(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(report) != 2 || report[0].Name != "valid" || report[0].Err != nil || report[1].Name != "missing" || report[1].Err == nil {
+		t.Fatalf("Validate() = %+v", report)
+	}
+	if report.Valid() {
+		t.Fatal("Valid() = true, want false")
+	}
+	if !report[:1].Valid() {
+		t.Fatal("Valid() = false, want true")
+	}
+
+	// malformed keys
+	keys.Keys = append(keys.Keys, keys.Keys[0])
+	if _, err := keys.Validate(context.Background(), nil); err == nil {
+		t.Fatal("Validate() expects error for duplicate key names")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+
+	"github.com/notaryproject/notation-go/internal/envelope"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// COSEProtectedHeader is a custom protected header of the COSE envelopes,
+// e.g. an organization-specific attestation. The header is signed with the
+// signature, as an extended signed attribute of the envelope.
+type COSEProtectedHeader struct {
+	// Label is the text label of the header, e.g.
+	// "com.example.buildAttestation". The labels prefixed with
+	// "io.cncf.notary." are reserved.
+	Label string
+
+	// Critical marks the header as critical: a verifier not knowing the
+	// header must reject the signature.
+	Critical bool
+
+	// Generate returns the value of the header of the signature of the
+	// artifact desc, encoded in CBOR. The header is not added if Generate
+	// returns a nil value. A Generate error fails the signing.
+	Generate func(ctx context.Context, desc ocispec.Descriptor) (any, error)
+
+	// Validate validates the value of the header of a signature of the
+	// artifact desc, as decoded from CBOR, e.g. a text string as a string
+	// and an unsigned integer as an uint64. A Validate error fails the
+	// verification.
+	Validate func(ctx context.Context, value any, desc ocispec.Descriptor) error
+}
+
+// RegisterCOSEProtectedHeader registers the custom protected header of COSE
+// envelopes. The header is added to the COSE signatures produced by the
+// signers of package signer, except for the plugins generating the envelope,
+// and validated by the verifier of package verifier if present in a COSE
+// signature. A registered header does not need to be processed by a
+// verification plugin.
+//
+// RegisterCOSEProtectedHeader is expected to be called at initialization. It
+// returns an error if the label is already registered.
+func RegisterCOSEProtectedHeader(header COSEProtectedHeader) error {
+	return envelope.RegisterProtectedHeader(envelope.ProtectedHeader(header))
+}
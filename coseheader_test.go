@@ -0,0 +1,137 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestRegisterCOSEProtectedHeader(t *testing.T) {
+	const (
+		label     = "com.example.buildAttestation"
+		mediaType = "application/vnd.example.attested"
+	)
+	var trustedBuild = "build-42"
+	header := notation.COSEProtectedHeader{
+		Label:    label,
+		Critical: true,
+		Generate: func(_ context.Context, desc ocispec.Descriptor) (any, error) {
+			// only the artifacts of this test are attested
+			if desc.MediaType != mediaType {
+				return nil, nil
+			}
+			return "build-42", nil
+		},
+		Validate: func(_ context.Context, value any, _ ocispec.Descriptor) error {
+			if value != trustedBuild {
+				return errors.New("build is not trusted")
+			}
+			return nil
+		},
+	}
+	if err := notation.RegisterCOSEProtectedHeader(header); err != nil {
+		t.Fatalf("RegisterCOSEProtectedHeader() error = %v", err)
+	}
+	if err := notation.RegisterCOSEProtectedHeader(header); err == nil {
+		t.Fatal("RegisterCOSEProtectedHeader() expects error for a registered label")
+	}
+	reserved := header
+	reserved.Label = "io.cncf.notary.buildAttestation"
+	if err := notation.RegisterCOSEProtectedHeader(reserved); err == nil {
+		t.Fatal("RegisterCOSEProtectedHeader() expects error for a reserved label")
+	}
+
+	ctx := context.Background()
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := verifier.NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "blob", s.Root()), verifier.VerifierOptions{
+		BlobTrustPolicy: &trustpolicy.BlobDocument{
+			Version: "1.0",
+			TrustPolicies: []trustpolicy.BlobTrustPolicy{
+				{
+					Name:                  "blob-policy",
+					SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: "strict"},
+					TrustStores:           []string{"ca:blob"},
+					TrustedIdentities:     []string{"*"},
+					GlobalPolicy:          true,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("firmware image")
+	sign := func(signatureMediaType string) []byte {
+		sig, _, err := notation.SignBlob(ctx, s, bytes.NewReader(content), notation.SignBlobOptions{
+			SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: signatureMediaType},
+			ContentMediaType:  mediaType,
+		})
+		if err != nil {
+			t.Fatalf("SignBlob() error = %v", err)
+		}
+		return sig
+	}
+	verify := func(signatureMediaType string, sig []byte) (*notation.VerificationOutcome, error) {
+		_, outcome, err := notation.VerifyBlob(ctx, v, bytes.NewReader(content), sig, notation.VerifyBlobOptions{
+			BlobVerifierVerifyOptions: notation.BlobVerifierVerifyOptions{SignatureMediaType: signatureMediaType},
+		})
+		return outcome, err
+	}
+
+	sig := sign(cose.MediaTypeEnvelope)
+	outcome, err := verify(cose.MediaTypeEnvelope, sig)
+	if err != nil {
+		t.Fatalf("VerifyBlob() error = %v", err)
+	}
+	var found bool
+	for _, attr := range outcome.EnvelopeContent.SignerInfo.SignedAttributes.ExtendedAttributes {
+		if attr.Key == label && attr.Value == "build-42" && attr.Critical {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("protected header %q is missing: %+v", label, outcome.EnvelopeContent.SignerInfo.SignedAttributes.ExtendedAttributes)
+	}
+
+	// the header is validated
+	trustedBuild = "build-43"
+	if _, err := verify(cose.MediaTypeEnvelope, sig); err == nil {
+		t.Fatal("VerifyBlob() expects error for an invalid protected header")
+	}
+
+	// JWS envelopes are not affected
+	sig = sign(jws.MediaTypeEnvelope)
+	outcome, err = verify(jws.MediaTypeEnvelope, sig)
+	if err != nil {
+		t.Fatalf("VerifyBlob() error = %v", err)
+	}
+	if len(outcome.EnvelopeContent.SignerInfo.SignedAttributes.ExtendedAttributes) != 0 {
+		t.Fatalf("JWS envelope has extended attributes: %+v", outcome.EnvelopeContent.SignerInfo.SignedAttributes.ExtendedAttributes)
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cosign migrates the cosign signatures of OCI artifacts to notation
+// signatures.
+//
+// [ListSignatures] enumerates the cosign signatures attached to an artifact
+// with the cosign tag scheme, i.e. the "sha256-<hex>.sig" tag. [Migrate]
+// validates them against the public key or the certificate identity of the
+// signer, and signs the artifact with notation for each valid cosign
+// signature, mapping the optional annotations of the cosign payload to the
+// user metadata of the notation signature.
+//
+// The transparency log entries of keyless cosign signatures are not
+// verified. The cosign signatures are left in place.
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// Cosign media types and annotations.
+const (
+	// MediaTypeSimpleSigning is the media type of the payload of a cosign
+	// signature.
+	MediaTypeSimpleSigning = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// AnnotationSignature is the annotation of the base64 encoded cosign
+	// signature of the payload.
+	AnnotationSignature = "dev.cosignproject.cosign/signature"
+
+	// AnnotationCertificate is the annotation of the PEM encoded signing
+	// certificate of a keyless cosign signature.
+	AnnotationCertificate = "dev.sigstore.cosign/certificate"
+
+	// AnnotationChain is the annotation of the PEM encoded certificate chain
+	// of the signing certificate of a keyless cosign signature.
+	AnnotationChain = "dev.sigstore.cosign/chain"
+)
+
+// payloadType is the type of the payload of a cosign container image
+// signature.
+const payloadType = "cosign container image signature"
+
+// maxPayloadSize is the maximum size of the payload of a cosign signature.
+const maxPayloadSize = 4 * 1024 * 1024
+
+// Signature is a cosign signature of an artifact.
+type Signature struct {
+	// Layer is the descriptor of the payload in the cosign signature
+	// manifest.
+	Layer ocispec.Descriptor
+
+	// Payload is the simple signing payload signed by the signature.
+	Payload []byte
+
+	// Signature is the signature of the payload.
+	Signature []byte
+
+	// Certificate is the signing certificate of a keyless signature, or nil.
+	Certificate *x509.Certificate
+
+	// Chain is the certificate chain of the signing certificate, if any.
+	Chain []*x509.Certificate
+}
+
+// simpleSigningPayload is the simple signing payload of a cosign signature.
+type simpleSigningPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]any `json:"optional"`
+}
+
+// SignatureTag returns the tag of the cosign signatures of the artifact desc,
+// e.g. "sha256-<hex>.sig".
+func SignatureTag(desc ocispec.Descriptor) string {
+	return strings.Replace(desc.Digest.String(), ":", "-", 1) + ".sig"
+}
+
+// ListSignatures returns the cosign signatures of the artifact desc stored in
+// target, typically the remote repository of the artifact. If the artifact
+// has no cosign signature, nil is returned.
+func ListSignatures(ctx context.Context, target oras.ReadOnlyTarget, desc ocispec.Descriptor) ([]Signature, error) {
+	if target == nil {
+		return nil, errors.New("target cannot be nil")
+	}
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid artifact digest: %w", err)
+	}
+	tag := SignatureTag(desc)
+	manifestDesc, err := target.Resolve(ctx, tag)
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve the cosign signatures %q: %w", tag, err)
+	}
+	if manifestDesc.MediaType != ocispec.MediaTypeImageManifest {
+		return nil, fmt.Errorf("cosign signatures %q have unsupported manifest media type %q", tag, manifestDesc.MediaType)
+	}
+	manifestJSON, err := content.FetchAll(ctx, target, manifestDesc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the cosign signatures %q: %w", tag, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the cosign signatures %q: %w", tag, err)
+	}
+	var signatures []Signature
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != MediaTypeSimpleSigning {
+			continue
+		}
+		sig, err := fetchSignature(ctx, target, layer)
+		if err != nil {
+			return nil, fmt.Errorf("cosign signature %s: %w", layer.Digest, err)
+		}
+		signatures = append(signatures, sig)
+	}
+	return signatures, nil
+}
+
+// fetchSignature fetches the cosign signature of the payload layer.
+func fetchSignature(ctx context.Context, target content.Fetcher, layer ocispec.Descriptor) (Signature, error) {
+	if layer.Size > maxPayloadSize {
+		return Signature{}, fmt.Errorf("payload of %d bytes exceeds %d bytes", layer.Size, maxPayloadSize)
+	}
+	encodedSig, ok := layer.Annotations[AnnotationSignature]
+	if !ok {
+		return Signature{}, fmt.Errorf("missing annotation %q", AnnotationSignature)
+	}
+	sigBytes, err := base64.StdEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return Signature{}, fmt.Errorf("invalid annotation %q: %w", AnnotationSignature, err)
+	}
+	sig := Signature{
+		Layer:     layer,
+		Signature: sigBytes,
+	}
+	if certPEM, ok := layer.Annotations[AnnotationCertificate]; ok {
+		certs, err := parseCertificates(certPEM)
+		if err != nil || len(certs) != 1 {
+			return Signature{}, fmt.Errorf("invalid annotation %q: %v", AnnotationCertificate, err)
+		}
+		sig.Certificate = certs[0]
+	}
+	if chainPEM, ok := layer.Annotations[AnnotationChain]; ok {
+		if sig.Chain, err = parseCertificates(chainPEM); err != nil {
+			return Signature{}, fmt.Errorf("invalid annotation %q: %w", AnnotationChain, err)
+		}
+	}
+	if sig.Payload, err = content.FetchAll(ctx, target, layer); err != nil {
+		return Signature{}, fmt.Errorf("failed to fetch the payload: %w", err)
+	}
+	return sig, nil
+}
+
+// parseCertificates parses the PEM encoded certificates.
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificate")
+	}
+	return certs, nil
+}
+
+// VerifyOptions contains parameters for [Signature.Verify].
+type VerifyOptions struct {
+	// PublicKey is the public key of the signer of a cosign signature
+	// signed with a key. If nil, the signing certificate of a keyless
+	// signature is verified against Roots.
+	PublicKey crypto.PublicKey
+
+	// Roots are the root certificates of the signing certificates of the
+	// keyless signatures, e.g. the Fulcio roots.
+	Roots *x509.CertPool
+
+	// Identities are the identities accepted for the signing certificates
+	// of the keyless signatures, i.e. their email address or URI subject
+	// alternative names. Required if PublicKey is nil.
+	Identities []string
+}
+
+// Verify verifies that the signature is a valid cosign signature of the
+// artifact desc. The signing certificate of a keyless signature is verified
+// at the start of its validity period, as the short-lived certificates are
+// expired and the signing time is attested by the transparency log, which is
+// not verified.
+func (s Signature) Verify(desc ocispec.Descriptor, opts VerifyOptions) error {
+	var payload simpleSigningPayload
+	if err := json.Unmarshal(s.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to parse the cosign payload: %w", err)
+	}
+	if payload.Critical.Type != payloadType {
+		return fmt.Errorf("cosign payload type %q is not %q", payload.Critical.Type, payloadType)
+	}
+	if payload.Critical.Image.DockerManifestDigest != desc.Digest.String() {
+		return fmt.Errorf("cosign payload signs digest %q, not the artifact digest %q", payload.Critical.Image.DockerManifestDigest, desc.Digest)
+	}
+	publicKey := opts.PublicKey
+	if publicKey == nil {
+		if err := s.verifyCertificate(opts); err != nil {
+			return err
+		}
+		publicKey = s.Certificate.PublicKey
+	}
+	return verifyPayloadSignature(publicKey, s.Payload, s.Signature)
+}
+
+// verifyCertificate verifies the signing certificate of a keyless signature
+// against the roots and the identities of opts.
+func (s Signature) verifyCertificate(opts VerifyOptions) error {
+	if s.Certificate == nil {
+		return errors.New("cosign signature has no signing certificate and no public key is provided")
+	}
+	if opts.Roots == nil {
+		return errors.New("roots are required to verify a keyless cosign signature")
+	}
+	if len(opts.Identities) == 0 {
+		return errors.New("identities are required to verify a keyless cosign signature")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range s.Chain {
+		intermediates.AddCert(cert)
+	}
+	if _, err := s.Certificate.Verify(x509.VerifyOptions{
+		Roots:         opts.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   s.Certificate.NotBefore.Add(time.Second),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("failed to verify the signing certificate: %w", err)
+	}
+	identities := append([]string(nil), s.Certificate.EmailAddresses...)
+	for _, uri := range s.Certificate.URIs {
+		identities = append(identities, uri.String())
+	}
+	for _, identity := range identities {
+		if slices.Contains(opts.Identities, identity) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signing certificate identities %q are not accepted", identities)
+}
+
+// verifyPayloadSignature verifies the signature sig of payload with
+// publicKey.
+func verifyPayloadSignature(publicKey crypto.PublicKey, payload, sig []byte) error {
+	hash := sha256.Sum256(payload)
+	switch pub := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, hash[:], sig) {
+			return errors.New("invalid cosign signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], sig); err != nil {
+			return fmt.Errorf("invalid cosign signature: %w", err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, payload, sig) {
+			return errors.New("invalid cosign signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
+
+// optionalAnnotations returns the optional annotations of the cosign
+// payload, with the values that are not strings JSON encoded.
+func (s Signature) optionalAnnotations() (map[string]string, error) {
+	var payload simpleSigningPayload
+	if err := json.Unmarshal(s.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse the cosign payload: %w", err)
+	}
+	annotations := make(map[string]string, len(payload.Optional))
+	for key, value := range payload.Optional {
+		if str, ok := value.(string); ok {
+			annotations[key] = str
+			continue
+		}
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		annotations[key] = string(valueJSON)
+	}
+	return annotations, nil
+}
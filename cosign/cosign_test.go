@@ -0,0 +1,306 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/errdef"
+)
+
+const testIdentity = "release@acme-rockets.io"
+
+// pushArtifact pushes an image manifest tagged with tag to store.
+func pushArtifact(t *testing.T, store *memory.Store, tag string) ocispec.Descriptor {
+	t.Helper()
+	return pushManifest(t, store, ocispec.Manifest{
+		Versioned:   specs.Versioned{SchemaVersion: 2},
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Config:      ocispec.DescriptorEmptyJSON,
+		Layers:      []ocispec.Descriptor{ocispec.DescriptorEmptyJSON},
+		Annotations: map[string]string{ocispec.AnnotationRefName: tag},
+	}, tag)
+}
+
+func pushManifest(t *testing.T, store *memory.Store, manifest ocispec.Manifest, tag string) ocispec.Descriptor {
+	t.Helper()
+	ctx := context.Background()
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifestJSON)
+	if err := store.Push(ctx, desc, bytes.NewReader(manifestJSON)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		t.Fatal(err)
+	}
+	for _, reference := range []string{desc.Digest.String(), tag} {
+		if err := store.Tag(ctx, desc, reference); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return desc
+}
+
+// testPayload returns the cosign payload of desc with the optional
+// annotations.
+func testPayload(t *testing.T, desc ocispec.Descriptor, optional map[string]any) []byte {
+	t.Helper()
+	var payload simpleSigningPayload
+	payload.Critical.Identity.DockerReference = "registry.acme-rockets.io/software/net-monitor"
+	payload.Critical.Image.DockerManifestDigest = desc.Digest.String()
+	payload.Critical.Type = payloadType
+	payload.Optional = optional
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payloadJSON
+}
+
+// testLayer is a cosign signature to push.
+type testLayer struct {
+	payload     []byte
+	key         crypto.Signer
+	annotations map[string]string
+}
+
+// pushSignatures pushes the cosign signatures of desc to store.
+func pushSignatures(t *testing.T, store *memory.Store, desc ocispec.Descriptor, layers ...testLayer) {
+	t.Helper()
+	ctx := context.Background()
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+	}
+	for _, layer := range layers {
+		hash := sha256.Sum256(layer.payload)
+		sig, err := layer.key.Sign(rand.Reader, hash[:], crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		layerDesc := content.NewDescriptorFromBytes(MediaTypeSimpleSigning, layer.payload)
+		layerDesc.Annotations = map[string]string{AnnotationSignature: base64.StdEncoding.EncodeToString(sig)}
+		for k, v := range layer.annotations {
+			layerDesc.Annotations[k] = v
+		}
+		if err := store.Push(ctx, layerDesc, bytes.NewReader(layer.payload)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+			t.Fatal(err)
+		}
+		manifest.Layers = append(manifest.Layers, layerDesc)
+	}
+	pushManifest(t, store, manifest, SignatureTag(desc))
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// newKeylessCertificates returns a root certificate and a short-lived code
+// signing certificate with the email address identity, issued by the root.
+func newKeylessCertificates(t *testing.T, key *ecdsa.PrivateKey, identity string) (*x509.Certificate, *x509.Certificate) {
+	t.Helper()
+	rootKey := newTestKey(t)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber:   big.NewInt(2),
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(-50 * time.Minute),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses: []string{identity},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, key.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root, leaf
+}
+
+func encodePEM(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+func TestListSignatures(t *testing.T) {
+	store := memory.New()
+	desc := pushArtifact(t, store, "v1")
+	ctx := context.Background()
+
+	sigs, err := ListSignatures(ctx, store, desc)
+	if err != nil || sigs != nil {
+		t.Fatalf("ListSignatures() = %v, %v, want no signature", sigs, err)
+	}
+
+	key := newTestKey(t)
+	_, leaf := newKeylessCertificates(t, key, testIdentity)
+	payload := testPayload(t, desc, nil)
+	pushSignatures(t, store, desc,
+		testLayer{payload: payload, key: key},
+		testLayer{payload: payload, key: key, annotations: map[string]string{AnnotationCertificate: encodePEM(leaf)}},
+	)
+	sigs, err = ListSignatures(ctx, store, desc)
+	if err != nil {
+		t.Fatalf("ListSignatures() error = %v", err)
+	}
+	if len(sigs) != 2 {
+		t.Fatalf("ListSignatures() returned %d signatures, want 2", len(sigs))
+	}
+	if !bytes.Equal(sigs[0].Payload, payload) || sigs[0].Certificate != nil {
+		t.Fatalf("ListSignatures() signature = %+v, want payload without certificate", sigs[0])
+	}
+	if sigs[1].Certificate == nil || !sigs[1].Certificate.Equal(leaf) {
+		t.Fatal("ListSignatures() signature expects the signing certificate")
+	}
+
+	if _, err := ListSignatures(ctx, nil, desc); err == nil {
+		t.Fatal("ListSignatures() expects error for nil target")
+	}
+	if _, err := ListSignatures(ctx, store, ocispec.Descriptor{}); err == nil {
+		t.Fatal("ListSignatures() expects error for invalid digest")
+	}
+}
+
+func TestListSignaturesInvalid(t *testing.T) {
+	store := memory.New()
+	desc := pushArtifact(t, store, "v1")
+	key := newTestKey(t)
+	pushSignatures(t, store, desc, testLayer{
+		payload:     testPayload(t, desc, nil),
+		key:         key,
+		annotations: map[string]string{AnnotationSignature: "not base64!"},
+	})
+	if _, err := ListSignatures(context.Background(), store, desc); err == nil {
+		t.Fatal("ListSignatures() expects error for invalid signature annotation")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	store := memory.New()
+	desc := pushArtifact(t, store, "v1")
+	otherDesc := pushArtifact(t, store, "v2")
+	key := newTestKey(t)
+	root, leaf := newKeylessCertificates(t, key, testIdentity)
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	pushSignatures(t, store, desc,
+		testLayer{payload: testPayload(t, desc, nil), key: key},
+		testLayer{payload: testPayload(t, desc, nil), key: key, annotations: map[string]string{AnnotationCertificate: encodePEM(leaf)}},
+		testLayer{payload: testPayload(t, otherDesc, nil), key: key},
+	)
+	sigs, err := ListSignatures(context.Background(), store, desc)
+	if err != nil {
+		t.Fatalf("ListSignatures() error = %v", err)
+	}
+	keySig, keylessSig, otherSig := sigs[0], sigs[1], sigs[2]
+
+	if err := keySig.Verify(desc, VerifyOptions{PublicKey: key.Public()}); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := keylessSig.Verify(desc, VerifyOptions{Roots: roots, Identities: []string{testIdentity}}); err != nil {
+		t.Fatalf("Verify() keyless error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		sig  Signature
+		opts VerifyOptions
+	}{
+		{
+			name: "wrong key",
+			sig:  keySig,
+			opts: VerifyOptions{PublicKey: newTestKey(t).Public()},
+		},
+		{
+			name: "other artifact",
+			sig:  otherSig,
+			opts: VerifyOptions{PublicKey: key.Public()},
+		},
+		{
+			name: "no key and no certificate",
+			sig:  keySig,
+			opts: VerifyOptions{Roots: roots, Identities: []string{testIdentity}},
+		},
+		{
+			name: "keyless without roots",
+			sig:  keylessSig,
+			opts: VerifyOptions{Identities: []string{testIdentity}},
+		},
+		{
+			name: "keyless without identities",
+			sig:  keylessSig,
+			opts: VerifyOptions{Roots: roots},
+		},
+		{
+			name: "keyless with other identity",
+			sig:  keylessSig,
+			opts: VerifyOptions{Roots: roots, Identities: []string{"dev@acme-rockets.io"}},
+		},
+		{
+			name: "keyless with other roots",
+			sig:  keylessSig,
+			opts: VerifyOptions{Roots: x509.NewCertPool(), Identities: []string{testIdentity}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sig.Verify(desc, tt.opts); err == nil {
+				t.Fatal("Verify() expects error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	orasRegistry "oras.land/oras-go/v2/registry"
+)
+
+// MetadataMigratedFrom is the user metadata of a notation signature migrated
+// from a cosign signature, set to the digest of the cosign payload.
+const MetadataMigratedFrom = "dev.sigstore.cosign/migrated-from"
+
+// MigrateOptions contains parameters for [Migrate].
+type MigrateOptions struct {
+	// VerifyOptions validates the cosign signatures.
+	VerifyOptions
+
+	// SignOptions signs the artifact with notation. ArtifactReference is
+	// required. The UserMetadata are added to the optional annotations of
+	// each cosign payload, and take precedence.
+	SignOptions notation.SignOptions
+}
+
+// MigratedSignature is the result of the migration of a cosign signature.
+type MigratedSignature struct {
+	// Cosign is the cosign signature.
+	Cosign Signature
+
+	// UserMetadata are the user metadata of the notation signature.
+	UserMetadata map[string]string
+
+	// Err is the error that occurred while validating or migrating the
+	// cosign signature, or nil if it is migrated.
+	Err error
+}
+
+// Migrate signs the artifact opts.ArtifactReference with notation for each of
+// its valid cosign signatures, listed in target, typically the remote
+// repository of the artifact, and pushes the notation signatures to repo.
+// The optional annotations of the cosign payload are mapped to the user
+// metadata of the notation signature. The cosign signatures failing
+// validation are reported, not migrated.
+//
+// The descriptor of the artifact and the results of the migration of its
+// cosign signatures are returned. If the artifact has no cosign signature,
+// no result is returned.
+func Migrate(ctx context.Context, signer notation.Signer, repo registry.Repository, target oras.ReadOnlyTarget, opts MigrateOptions) (ocispec.Descriptor, []MigratedSignature, error) {
+	if signer == nil {
+		return ocispec.Descriptor{}, nil, errors.New("signer cannot be nil")
+	}
+	if repo == nil {
+		return ocispec.Descriptor{}, nil, errors.New("repo cannot be nil")
+	}
+	ref, err := orasRegistry.ParseReference(opts.SignOptions.ArtifactReference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("invalid artifact reference: %w", err)
+	}
+	if ref.Reference == "" {
+		return ocispec.Descriptor{}, nil, errors.New("artifact reference is missing digest or tag")
+	}
+	desc, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to resolve %q: %w", opts.SignOptions.ArtifactReference, err)
+	}
+	signatures, err := ListSignatures(ctx, target, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	// pin the artifact signed with cosign
+	ref.Reference = desc.Digest.String()
+	signOpts := opts.SignOptions
+	signOpts.ArtifactReference = ref.String()
+	var results []MigratedSignature
+	for _, sig := range signatures {
+		results = append(results, migrateSignature(ctx, signer, repo, desc, sig, opts.VerifyOptions, signOpts))
+	}
+	return desc, results, nil
+}
+
+// migrateSignature validates the cosign signature sig of the artifact desc,
+// and signs the artifact with notation.
+func migrateSignature(ctx context.Context, signer notation.Signer, repo registry.Repository, desc ocispec.Descriptor, sig Signature, verifyOpts VerifyOptions, signOpts notation.SignOptions) MigratedSignature {
+	result := MigratedSignature{Cosign: sig}
+	if err := sig.Verify(desc, verifyOpts); err != nil {
+		result.Err = err
+		return result
+	}
+	userMetadata, err := sig.optionalAnnotations()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	userMetadata[MetadataMigratedFrom] = sig.Layer.Digest.String()
+	maps.Copy(userMetadata, signOpts.UserMetadata)
+	signOpts.UserMetadata = userMetadata
+	result.UserMetadata = userMetadata
+	if _, err := notation.Sign(ctx, signer, repo, signOpts); err != nil {
+		result.Err = fmt.Errorf("failed to sign with notation: %w", err)
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cosign
+
+import (
+	"context"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	repo := registry.NewRepository(store)
+	desc := pushArtifact(t, store, "v1")
+	key := newTestKey(t)
+	pushSignatures(t, store, desc,
+		testLayer{payload: testPayload(t, desc, map[string]any{"env": "prod", "build": 3}), key: key},
+		testLayer{payload: testPayload(t, desc, nil), key: newTestKey(t)},
+	)
+	signer, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	opts := MigrateOptions{
+		VerifyOptions: VerifyOptions{PublicKey: key.Public()},
+		SignOptions: notation.SignOptions{
+			SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+			ArtifactReference: "registry.acme-rockets.io/software/net-monitor:v1",
+			UserMetadata:      map[string]string{"env": "production"},
+		},
+	}
+	gotDesc, results, err := Migrate(ctx, signer, repo, store, opts)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if gotDesc.Digest != desc.Digest {
+		t.Fatalf("Migrate() descriptor = %v, want %v", gotDesc, desc)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Migrate() returned %d results, want 2", len(results))
+	}
+	if results[0].Err != nil {
+		t.Fatalf("Migrate() result error = %v", results[0].Err)
+	}
+	want := map[string]string{
+		"env":                "production",
+		"build":              "3",
+		MetadataMigratedFrom: results[0].Cosign.Layer.Digest.String(),
+	}
+	if len(results[0].UserMetadata) != len(want) {
+		t.Fatalf("Migrate() user metadata = %v, want %v", results[0].UserMetadata, want)
+	}
+	for k, v := range want {
+		if results[0].UserMetadata[k] != v {
+			t.Fatalf("Migrate() user metadata = %v, want %v", results[0].UserMetadata, want)
+		}
+	}
+	if results[1].Err == nil {
+		t.Fatal("Migrate() expects error for the signature of another key")
+	}
+
+	var notationSignatures []ocispec.Descriptor
+	if err := repo.ListSignatures(ctx, desc, func(signatureManifests []ocispec.Descriptor) error {
+		notationSignatures = append(notationSignatures, signatureManifests...)
+		return nil
+	}); err != nil {
+		t.Fatalf("ListSignatures() error = %v", err)
+	}
+	if len(notationSignatures) != 1 {
+		t.Fatalf("artifact has %d notation signatures, want 1", len(notationSignatures))
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	repo := registry.NewRepository(store)
+	pushArtifact(t, store, "v1")
+	signer, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	// no cosign signature
+	opts := MigrateOptions{SignOptions: notation.SignOptions{ArtifactReference: "registry.acme-rockets.io/software/net-monitor:v1"}}
+	_, results, err := Migrate(ctx, signer, repo, store, opts)
+	if err != nil || results != nil {
+		t.Fatalf("Migrate() = %v, %v, want no result", results, err)
+	}
+
+	for _, reference := range []string{"", "registry.acme-rockets.io/software/net-monitor", "registry.acme-rockets.io/software/net-monitor:v2"} {
+		opts := MigrateOptions{SignOptions: notation.SignOptions{ArtifactReference: reference}}
+		if _, _, err := Migrate(ctx, signer, repo, store, opts); err == nil {
+			t.Fatalf("Migrate(%q) expects error, got nil", reference)
+		}
+	}
+	if _, _, err := Migrate(ctx, nil, repo, store, opts); err == nil {
+		t.Fatal("Migrate() expects error for nil signer")
+	}
+	if _, _, err := Migrate(ctx, signer, nil, store, opts); err == nil {
+		t.Fatal("Migrate() expects error for nil repo")
+	}
+}
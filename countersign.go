@@ -0,0 +1,117 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// endorsementVerifier is implemented by verifiers verifying the
+// countersignatures endorsing the signatures, when required by the trust
+// policy.
+type endorsementVerifier interface {
+	// EndorsementRequired returns true if the trust policy applicable to the
+	// artifact requires the signatures to be endorsed.
+	EndorsementRequired(ctx context.Context, opts VerifierVerifyOptions) (bool, error)
+
+	// VerifyEndorsement verifies the countersignature of the signature
+	// manifest sigManifestDesc against the endorsement of the trust policy
+	// applicable to the artifact, and returns the outcome.
+	VerifyEndorsement(ctx context.Context, sigManifestDesc ocispec.Descriptor, countersignature []byte, opts VerifierVerifyOptions) (*VerificationOutcome, error)
+}
+
+// Countersign signs the signature manifest sigManifestDesc of a signature in
+// the Repository with signer, and pushes the countersignature to the
+// Repository with the signature manifest as subject, e.g. to endorse the
+// signature of a developer by a security team. Trust policies require an
+// endorsement with [trustpolicy.Endorsement].
+//
+// signOpts.ArtifactReference is optional. If set, it must reference the
+// signature manifest by digest, see [SignDescriptor].
+//
+// The descriptor of the countersignature manifest is returned upon
+// successful signing.
+func Countersign(ctx context.Context, signer Signer, repo registry.Repository, sigManifestDesc ocispec.Descriptor, signOpts SignOptions) (ocispec.Descriptor, error) {
+	if repo == nil {
+		return ocispec.Descriptor{}, errors.New("repo cannot be nil")
+	}
+	// only the signatures in the repository are countersigned
+	if _, _, err := repo.FetchSignatureBlob(ctx, sigManifestDesc); err != nil {
+		return ocispec.Descriptor{}, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve the signature with digest %q to countersign from the Repository, error : %v", sigManifestDesc.Digest, err.Error()), InnerError: err}
+	}
+
+	// the annotations of the signature manifest are not signed, as they
+	// differ between the listed and the fetched descriptors
+	subject := ocispec.Descriptor{
+		MediaType: sigManifestDesc.MediaType,
+		Digest:    sigManifestDesc.Digest,
+		Size:      sigManifestDesc.Size,
+	}
+	_, countersigManifestDesc, err := SignDescriptor(ctx, signer, repo, subject, signOpts)
+	return countersigManifestDesc, err
+}
+
+// verifyEndorsement verifies the countersignatures of the signature manifest
+// sigManifestDesc until one passes verification, and returns its outcome. At
+// most maxAttempts countersignatures are verified.
+func verifyEndorsement(ctx context.Context, verifier endorsementVerifier, repo registry.Repository, sigManifestDesc ocispec.Descriptor, opts VerifierVerifyOptions, maxAttempts int) (*VerificationOutcome, error) {
+	logger := log.GetLogger(ctx)
+	// the user metadata are required from the endorsed signature only
+	opts.UserMetadata = nil
+
+	var endorsement *VerificationOutcome
+	var errs []error
+	numOfCountersignatureProcessed := 0
+	err := repo.ListSignatures(ctx, sigManifestDesc, func(countersigManifests []ocispec.Descriptor) error {
+		for _, countersigManifestDesc := range countersigManifests {
+			if numOfCountersignatureProcessed >= maxAttempts {
+				return errDoneVerification
+			}
+			numOfCountersignatureProcessed++
+			countersigBlob, countersigDesc, err := repo.FetchSignatureBlob(ctx, countersigManifestDesc)
+			if err != nil {
+				return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve countersignature with digest %q of signature %q from the Repository, error : %v", countersigManifestDesc.Digest, sigManifestDesc.Digest, err.Error()), InnerError: err}
+			}
+			opts.SignatureMediaType = countersigDesc.MediaType
+			opts.SignatureManifestAnnotations = countersigManifestDesc.Annotations
+			outcome, err := verifier.VerifyEndorsement(ctx, sigManifestDesc, countersigBlob, opts)
+			if err != nil {
+				logger.Warnf("Countersignature %v of signature %v failed verification with error: %v", countersigManifestDesc.Digest, sigManifestDesc.Digest, err)
+				errs = append(errs, fmt.Errorf("countersignature with digest %v: %w", countersigManifestDesc.Digest, err))
+				continue
+			}
+			endorsement = outcome
+			return errDoneVerification
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDoneVerification) {
+		var retrievalErr ErrorSignatureRetrievalFailed
+		if errors.As(err, &retrievalErr) {
+			return nil, err
+		}
+		return nil, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to list the countersignatures of signature %q from the Repository, error : %v", sigManifestDesc.Digest, err.Error()), InnerError: err}
+	}
+	if endorsement == nil {
+		errs = append([]error{ErrorVerificationFailed{Msg: fmt.Sprintf("signature is not endorsed: no countersignature of the %d inspected passed verification", numOfCountersignatureProcessed)}}, errs...)
+		return nil, errors.Join(errs...)
+	}
+	return endorsement, nil
+}
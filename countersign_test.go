@@ -0,0 +1,117 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// endorsingVerifier verifies the countersignatures of the signatures.
+type endorsingVerifier struct {
+	dummyVerifier
+	required        bool
+	failEndorsement bool
+	endorsed        []ocispec.Descriptor
+}
+
+func (v *endorsingVerifier) EndorsementRequired(_ context.Context, _ VerifierVerifyOptions) (bool, error) {
+	return v.required, nil
+}
+
+func (v *endorsingVerifier) VerifyEndorsement(_ context.Context, sigManifestDesc ocispec.Descriptor, _ []byte, _ VerifierVerifyOptions) (*VerificationOutcome, error) {
+	v.endorsed = append(v.endorsed, sigManifestDesc)
+	if v.failEndorsement {
+		return &VerificationOutcome{}, errors.New("endorser is not trusted")
+	}
+	return &VerificationOutcome{VerificationLevel: trustpolicy.LevelStrict}, nil
+}
+
+func TestCountersign(t *testing.T) {
+	repo := subjectRecordingRepository{Repository: mock.NewRepository(), subject: &ocispec.Descriptor{}}
+	opts := SignOptions{}
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+	if _, err := Countersign(context.Background(), &dummySigner{}, repo, mock.SigManfiestDescriptor, opts); err != nil {
+		t.Fatalf("Countersign() error = %v", err)
+	}
+	want := ocispec.Descriptor{
+		MediaType: mock.SigManfiestDescriptor.MediaType,
+		Digest:    mock.SigManfiestDescriptor.Digest,
+		Size:      mock.SigManfiestDescriptor.Size,
+	}
+	if repo.subject.Digest != want.Digest || repo.subject.Annotations != nil {
+		t.Fatalf("countersignature subject = %+v, want %+v", repo.subject, want)
+	}
+
+	// the signature is not in the repository
+	repo.FetchSignatureBlobError = errors.New("not found")
+	if _, err := Countersign(context.Background(), &dummySigner{}, repo, mock.SigManfiestDescriptor, opts); err == nil {
+		t.Fatal("Countersign() expects error for a missing signature")
+	}
+	if _, err := Countersign(context.Background(), &dummySigner{}, nil, mock.SigManfiestDescriptor, opts); err == nil {
+		t.Fatal("Countersign() expects error for nil repository")
+	}
+}
+
+func TestVerifyEndorsement(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	opts := VerifyOptions{ArtifactReference: mock.SampleArtifactUri, MaxSignatureAttempts: 50}
+	newVerifier := func() *endorsingVerifier {
+		return &endorsingVerifier{dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}}
+	}
+
+	// no endorsement required
+	verifier := newVerifier()
+	_, outcomes, err := Verify(context.Background(), verifier, mock.NewRepository(), opts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if outcomes[0].Endorsement != nil || len(verifier.endorsed) != 0 {
+		t.Fatalf("Verify() verified an endorsement not required: %+v", outcomes[0])
+	}
+
+	// endorsed signature
+	verifier = newVerifier()
+	verifier.required = true
+	_, outcomes, err = Verify(context.Background(), verifier, mock.NewRepository(), opts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if outcomes[0].Endorsement == nil || len(verifier.endorsed) != 1 || verifier.endorsed[0].Digest != mock.SigManfiestDescriptor.Digest {
+		t.Fatalf("Verify() did not verify the endorsement: %+v", outcomes[0])
+	}
+
+	// signature not endorsed
+	verifier = newVerifier()
+	verifier.required = true
+	verifier.failEndorsement = true
+	if _, _, err := Verify(context.Background(), verifier, mock.NewRepository(), opts); err == nil {
+		t.Fatal("Verify() expects error for a signature not endorsed")
+	}
+
+	// countersignatures not listed
+	verifier = newVerifier()
+	verifier.required = true
+	repo := mock.NewRepository()
+	repo.ListSignaturesResponse = nil
+	if _, err := verifyEndorsement(context.Background(), verifier, repo, mock.SigManfiestDescriptor, VerifierVerifyOptions{}, 50); err == nil {
+		t.Fatal("verifyEndorsement() expects error without countersignature")
+	}
+}
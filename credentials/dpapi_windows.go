@@ -0,0 +1,95 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package credentials
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// cryptProtectUIForbidden prevents DPAPI from prompting the user.
+const cryptProtectUIForbidden = 0x1
+
+var (
+	modcrypt32             = syscall.NewLazyDLL("crypt32.dll")
+	modkernel32            = syscall.NewLazyDLL("kernel32.dll")
+	procCryptProtectData   = modcrypt32.NewProc("CryptProtectData")
+	procCryptUnprotectData = modcrypt32.NewProc("CryptUnprotectData")
+	procLocalFree          = modkernel32.NewProc("LocalFree")
+)
+
+// dataBlob is the DATA_BLOB structure of DPAPI.
+type dataBlob struct {
+	cbData uint32
+	pbData *byte
+}
+
+func newDataBlob(data []byte) *dataBlob {
+	if len(data) == 0 {
+		return &dataBlob{}
+	}
+	return &dataBlob{
+		cbData: uint32(len(data)),
+		pbData: &data[0],
+	}
+}
+
+// bytes copies the data of the blob and frees the blob.
+func (b *dataBlob) bytes() []byte {
+	defer procLocalFree.Call(uintptr(unsafe.Pointer(b.pbData)))
+	data := make([]byte, b.cbData)
+	copy(data, unsafe.Slice(b.pbData, b.cbData))
+	return data
+}
+
+// dpapiProvider encrypts with the Windows Data Protection API, under the
+// credentials of the current user.
+type dpapiProvider struct{}
+
+// NewDPAPIProvider returns an EncryptionProvider using the Windows Data
+// Protection API.
+func NewDPAPIProvider() (EncryptionProvider, error) {
+	if err := procCryptProtectData.Find(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	return dpapiProvider{}, nil
+}
+
+// Name returns [ProviderDPAPI].
+func (dpapiProvider) Name() string {
+	return ProviderDPAPI
+}
+
+// Encrypt encrypts the plaintext with CryptProtectData.
+func (dpapiProvider) Encrypt(plaintext []byte) ([]byte, error) {
+	var out dataBlob
+	r, _, err := procCryptProtectData.Call(uintptr(unsafe.Pointer(newDataBlob(plaintext))), 0, 0, 0, 0, cryptProtectUIForbidden, uintptr(unsafe.Pointer(&out)))
+	if r == 0 {
+		return nil, fmt.Errorf("CryptProtectData failed: %w", err)
+	}
+	return out.bytes(), nil
+}
+
+// Decrypt decrypts the ciphertext with CryptUnprotectData.
+func (dpapiProvider) Decrypt(ciphertext []byte) ([]byte, error) {
+	var out dataBlob
+	r, _, err := procCryptUnprotectData.Call(uintptr(unsafe.Pointer(newDataBlob(ciphertext))), 0, 0, 0, 0, cryptProtectUIForbidden, uintptr(unsafe.Pointer(&out)))
+	if r == 0 {
+		return nil, fmt.Errorf("CryptUnprotectData failed: %w", err)
+	}
+	return out.bytes(), nil
+}
@@ -0,0 +1,65 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"errors"
+)
+
+// keychainService is the service name of the passphrases stored in the OS
+// keychain.
+const keychainService = "notation"
+
+// ErrPassphraseNotFound is returned when no passphrase is stored for a key.
+var ErrPassphraseNotFound = errors.New("passphrase not found")
+
+// PassphraseStore stores the passphrases of the encrypted local signing keys
+// referenced in signingkeys.json, by key name.
+type PassphraseStore interface {
+	// Get retrieves the passphrase of the key. [ErrPassphraseNotFound] is
+	// returned if there is none.
+	Get(ctx context.Context, keyName string) ([]byte, error)
+
+	// Put saves the passphrase of the key.
+	Put(ctx context.Context, keyName string, passphrase []byte) error
+
+	// Delete removes the passphrase of the key. It is a no-op if there is
+	// none.
+	Delete(ctx context.Context, keyName string) error
+}
+
+// NewKeychainPassphraseStore returns a PassphraseStore backed by the OS
+// keychain: the macOS Keychain, the Windows Credential Manager or the Secret
+// Service on Linux. [ErrProviderUnavailable] is returned if the keychain is
+// not available.
+func NewKeychainPassphraseStore() (PassphraseStore, error) {
+	return newKeychainPassphraseStore()
+}
+
+// PassphraseFunc returns a function retrieving the passphrase of the key
+// from the store, e.g. for signer.FilesOptions.
+func PassphraseFunc(ctx context.Context, store PassphraseStore, keyName string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return store.Get(ctx, keyName)
+	}
+}
+
+// validateKeyName validates the name of a key used as keychain account.
+func validateKeyName(keyName string) error {
+	if keyName == "" {
+		return errors.New("key name cannot be empty")
+	}
+	return nil
+}
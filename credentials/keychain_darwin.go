@@ -0,0 +1,100 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// securityItemNotFound is the exit code of the security tool when the item
+// is not found.
+const securityItemNotFound = 44
+
+// macKeychainStore stores the passphrases in the login keychain with the
+// security tool. The passphrases are stored base64 encoded.
+type macKeychainStore struct {
+	path string
+}
+
+func newKeychainPassphraseStore() (PassphraseStore, error) {
+	path, err := exec.LookPath("security")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	return &macKeychainStore{path: path}, nil
+}
+
+// Get retrieves the passphrase of the key.
+func (s *macKeychainStore) Get(ctx context.Context, keyName string) ([]byte, error) {
+	if err := validateKeyName(keyName); err != nil {
+		return nil, err
+	}
+	out, err := s.run(ctx, nil, "find-generic-password", "-s", keychainService, "-a", keyName, "-w")
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+}
+
+// Put saves the passphrase of the key. The command is passed on stdin, so
+// that the passphrase is not visible in the process list.
+func (s *macKeychainStore) Put(ctx context.Context, keyName string, passphrase []byte) error {
+	if err := validateKeyName(keyName); err != nil {
+		return err
+	}
+	if strings.ContainsAny(keyName, "\"\\\n") {
+		return fmt.Errorf("key name %q contains characters not supported by the keychain", keyName)
+	}
+	command := fmt.Sprintf("add-generic-password -U -s %q -a \"%s\" -w %q\n", keychainService, keyName, base64.StdEncoding.EncodeToString(passphrase))
+	_, err := s.run(ctx, strings.NewReader(command), "-i")
+	return err
+}
+
+// Delete removes the passphrase of the key.
+func (s *macKeychainStore) Delete(ctx context.Context, keyName string) error {
+	if err := validateKeyName(keyName); err != nil {
+		return err
+	}
+	_, err := s.run(ctx, nil, "delete-generic-password", "-s", keychainService, "-a", keyName)
+	if errors.Is(err, ErrPassphraseNotFound) {
+		return nil
+	}
+	return err
+}
+
+// run runs the security tool.
+func (s *macKeychainStore) run(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, s.path, args...)
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+			return nil, ErrPassphraseNotFound
+		}
+		return nil, fmt.Errorf("keychain operation failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,98 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// secretServiceStore stores the passphrases in the Secret Service with the
+// secret-tool of libsecret. The passphrases are stored base64 encoded.
+type secretServiceStore struct {
+	path string
+}
+
+func newKeychainPassphraseStore() (PassphraseStore, error) {
+	path, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	return &secretServiceStore{path: path}, nil
+}
+
+// Get retrieves the passphrase of the key.
+func (s *secretServiceStore) Get(ctx context.Context, keyName string) ([]byte, error) {
+	if err := validateKeyName(keyName); err != nil {
+		return nil, err
+	}
+	out, err := s.run(ctx, nil, "lookup", "service", keychainService, "key", keyName)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, ErrPassphraseNotFound
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+}
+
+// Put saves the passphrase of the key. The passphrase is passed on stdin.
+func (s *secretServiceStore) Put(ctx context.Context, keyName string, passphrase []byte) error {
+	if err := validateKeyName(keyName); err != nil {
+		return err
+	}
+	label := fmt.Sprintf("--label=notation signing key %s", keyName)
+	secret := strings.NewReader(base64.StdEncoding.EncodeToString(passphrase))
+	_, err := s.run(ctx, secret, "store", label, "service", keychainService, "key", keyName)
+	return err
+}
+
+// Delete removes the passphrase of the key.
+func (s *secretServiceStore) Delete(ctx context.Context, keyName string) error {
+	if err := validateKeyName(keyName); err != nil {
+		return err
+	}
+	_, err := s.run(ctx, nil, "clear", "service", keychainService, "key", keyName)
+	if errors.Is(err, ErrPassphraseNotFound) {
+		return nil
+	}
+	return err
+}
+
+// run runs secret-tool.
+func (s *secretServiceStore) run(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, s.path, args...)
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 && stdout.Len() == 0 {
+			// secret-tool exits with 1 without output if the secret is not
+			// found
+			return nil, ErrPassphraseNotFound
+		}
+		return nil, fmt.Errorf("secret service operation failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSecretTool is a secret-tool storing a single secret in a file.
+const fakeSecretTool = `#!/bin/sh
+secret="$(dirname "$0")/secret"
+case "$1" in
+store) cat > "$secret" ;;
+lookup) [ -f "$secret" ] || exit 1; cat "$secret" ;;
+clear) [ -f "$secret" ] || exit 1; rm "$secret" ;;
+*) echo "unknown command $1" >&2; exit 2 ;;
+esac
+`
+
+func TestSecretServiceStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret-tool")
+	if err := os.WriteFile(path, []byte(fakeSecretTool), 0700); err != nil {
+		t.Fatal(err)
+	}
+	store := &secretServiceStore{path: path}
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "key"); !errors.Is(err, ErrPassphraseNotFound) {
+		t.Fatalf("Get() error = %v, want %v", err, ErrPassphraseNotFound)
+	}
+	if err := store.Put(ctx, "key", []byte("pass\nphrase")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	passphrase, err := store.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !bytes.Equal(passphrase, []byte("pass\nphrase")) {
+		t.Fatalf("Get() = %q, want %q", passphrase, "pass\nphrase")
+	}
+	if err := store.Delete(ctx, "key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(ctx, "key"); err != nil {
+		t.Fatalf("Delete() of missing passphrase error = %v", err)
+	}
+	if err := store.Put(ctx, "", nil); err == nil {
+		t.Fatal("Put() with empty key name expected error")
+	}
+}
+
+func TestNewKeychainPassphraseStoreUnavailable(t *testing.T) {
+	if _, err := exec.LookPath("secret-tool"); err == nil {
+		t.Skip("secret-tool is installed")
+	}
+	if _, err := NewKeychainPassphraseStore(); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("NewKeychainPassphraseStore() error = %v, want %v", err, ErrProviderUnavailable)
+	}
+}
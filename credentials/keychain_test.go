@@ -0,0 +1,57 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+// memoryPassphraseStore is a PassphraseStore in memory.
+type memoryPassphraseStore map[string][]byte
+
+func (s memoryPassphraseStore) Get(_ context.Context, keyName string) ([]byte, error) {
+	passphrase, ok := s[keyName]
+	if !ok {
+		return nil, ErrPassphraseNotFound
+	}
+	return passphrase, nil
+}
+
+func (s memoryPassphraseStore) Put(_ context.Context, keyName string, passphrase []byte) error {
+	s[keyName] = passphrase
+	return nil
+}
+
+func (s memoryPassphraseStore) Delete(_ context.Context, keyName string) error {
+	delete(s, keyName)
+	return nil
+}
+
+func TestPassphraseFunc(t *testing.T) {
+	store := memoryPassphraseStore{"key": []byte("passphrase")}
+	ctx := context.Background()
+	passphrase, err := PassphraseFunc(ctx, store, "key")()
+	if err != nil {
+		t.Fatalf("PassphraseFunc() error = %v", err)
+	}
+	if !bytes.Equal(passphrase, []byte("passphrase")) {
+		t.Fatalf("PassphraseFunc() = %q, want %q", passphrase, "passphrase")
+	}
+	if _, err := PassphraseFunc(ctx, store, "missing")(); !errors.Is(err, ErrPassphraseNotFound) {
+		t.Fatalf("PassphraseFunc() error = %v, want %v", err, ErrPassphraseNotFound)
+	}
+}
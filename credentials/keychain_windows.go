@@ -0,0 +1,142 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+var (
+	modadvapi32    = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = modadvapi32.NewProc("CredReadW")
+	procCredWriteW = modadvapi32.NewProc("CredWriteW")
+	procCredDelete = modadvapi32.NewProc("CredDeleteW")
+	procCredFree   = modadvapi32.NewProc("CredFree")
+)
+
+// credential is the CREDENTIALW structure of the Credential Manager.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialManagerStore stores the passphrases as generic credentials of
+// the Windows Credential Manager.
+type credentialManagerStore struct{}
+
+func newKeychainPassphraseStore() (PassphraseStore, error) {
+	if err := procCredReadW.Find(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	return credentialManagerStore{}, nil
+}
+
+// targetName returns the target name of the credential of the key.
+func targetName(keyName string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(keychainService + ":" + keyName)
+}
+
+// Get retrieves the passphrase of the key.
+func (credentialManagerStore) Get(_ context.Context, keyName string) ([]byte, error) {
+	if err := validateKeyName(keyName); err != nil {
+		return nil, err
+	}
+	target, err := targetName(keyName)
+	if err != nil {
+		return nil, err
+	}
+	var cred *credential
+	r, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return nil, ErrPassphraseNotFound
+		}
+		return nil, fmt.Errorf("CredReadW failed: %w", err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	passphrase := make([]byte, cred.CredentialBlobSize)
+	if cred.CredentialBlobSize > 0 {
+		copy(passphrase, unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize))
+	}
+	return passphrase, nil
+}
+
+// Put saves the passphrase of the key.
+func (credentialManagerStore) Put(_ context.Context, keyName string, passphrase []byte) error {
+	if err := validateKeyName(keyName); err != nil {
+		return err
+	}
+	target, err := targetName(keyName)
+	if err != nil {
+		return err
+	}
+	userName, err := syscall.UTF16PtrFromString(keyName)
+	if err != nil {
+		return err
+	}
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(passphrase)),
+		Persist:            credPersistLocalMachine,
+		UserName:           userName,
+	}
+	if len(passphrase) > 0 {
+		cred.CredentialBlob = &passphrase[0]
+	}
+	if r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return fmt.Errorf("CredWriteW failed: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the passphrase of the key.
+func (credentialManagerStore) Delete(_ context.Context, keyName string) error {
+	if err := validateKeyName(keyName); err != nil {
+		return err
+	}
+	target, err := targetName(keyName)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return nil
+		}
+		return fmt.Errorf("CredDeleteW failed: %w", err)
+	}
+	return nil
+}
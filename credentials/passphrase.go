@@ -0,0 +1,111 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters recommended for interactive logins.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+const (
+	saltSize = 16
+	keySize  = 32
+)
+
+// passphraseProvider encrypts with AES-256-GCM under a key derived from a
+// passphrase with scrypt. A new salt is used for every encryption.
+type passphraseProvider struct {
+	passphrase []byte
+}
+
+// NewPassphraseProvider returns an EncryptionProvider deriving the
+// encryption key from the passphrase.
+func NewPassphraseProvider(passphrase []byte) (EncryptionProvider, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase cannot be empty")
+	}
+	return &passphraseProvider{
+		passphrase: append([]byte(nil), passphrase...),
+	}, nil
+}
+
+// Name returns [ProviderPassphrase].
+func (p *passphraseProvider) Name() string {
+	return ProviderPassphrase
+}
+
+// Encrypt encrypts the plaintext. The ciphertext is the salt, the nonce and
+// the sealed plaintext.
+func (p *passphraseProvider) Encrypt(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := p.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := append(salt, nonce...)
+	return aead.Seal(ciphertext, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts the ciphertext returned by Encrypt.
+func (p *passphraseProvider) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < saltSize {
+		return nil, errors.New("ciphertext is too short")
+	}
+	salt, ciphertext := ciphertext[:saltSize], ciphertext[saltSize:]
+	aead, err := p.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt with passphrase: %w", err)
+	}
+	return plaintext, nil
+}
+
+// aead returns the AES-256-GCM cipher keyed with the passphrase and salt.
+func (p *passphraseProvider) aead(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(p.passphrase, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
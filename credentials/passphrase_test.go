@@ -0,0 +1,61 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPassphraseProvider(t *testing.T) {
+	provider, err := NewPassphraseProvider([]byte("passphrase"))
+	if err != nil {
+		t.Fatalf("NewPassphraseProvider() error = %v", err)
+	}
+	if name := provider.Name(); name != ProviderPassphrase {
+		t.Fatalf("Name() = %q, want %q", name, ProviderPassphrase)
+	}
+	plaintext := []byte("secret")
+	ciphertext, err := provider.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatal("ciphertext contains the plaintext")
+	}
+	got, err := provider.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("Decrypt() = %q, want %q", got, plaintext)
+	}
+
+	other, err := NewPassphraseProvider([]byte("other"))
+	if err != nil {
+		t.Fatalf("NewPassphraseProvider() error = %v", err)
+	}
+	if _, err := other.Decrypt(ciphertext); err == nil {
+		t.Fatal("Decrypt() with wrong passphrase expected error")
+	}
+	if _, err := provider.Decrypt(ciphertext[:saltSize+1]); err == nil {
+		t.Fatal("Decrypt() with truncated ciphertext expected error")
+	}
+}
+
+func TestNewPassphraseProviderEmpty(t *testing.T) {
+	if _, err := NewPassphraseProvider(nil); err == nil {
+		t.Fatal("NewPassphraseProvider() with empty passphrase expected error")
+	}
+}
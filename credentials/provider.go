@@ -0,0 +1,54 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credentials provides an encrypted on-disk cache of registry
+// credentials, so that cached auth material is never stored in plaintext
+// under the notation config directory.
+//
+// The credentials are encrypted with an EncryptionProvider. A passphrase
+// provider is available on all platforms and a DPAPI provider on Windows.
+// Other providers, such as the macOS Keychain or the Secret Service, can be
+// plugged in by implementing EncryptionProvider.
+//
+// The passphrases of the encrypted local signing keys can be stored in the OS
+// keychain with the PassphraseStore returned by NewKeychainPassphraseStore.
+package credentials
+
+import "errors"
+
+// Names of the encryption providers.
+const (
+	// ProviderPassphrase is the name of the passphrase provider.
+	ProviderPassphrase = "passphrase"
+
+	// ProviderDPAPI is the name of the Windows DPAPI provider.
+	ProviderDPAPI = "dpapi"
+)
+
+// ErrProviderUnavailable is returned when an encryption provider is not
+// available on the platform.
+var ErrProviderUnavailable = errors.New("encryption provider is not available on this platform")
+
+// EncryptionProvider encrypts and decrypts the cached credentials.
+type EncryptionProvider interface {
+	// Name returns the name of the provider. It is stored with the
+	// ciphertext, so that the credentials are not decrypted with another
+	// provider.
+	Name() string
+
+	// Encrypt encrypts the plaintext.
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt decrypts the ciphertext returned by Encrypt.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
@@ -0,0 +1,180 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/internal/file"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// fileVersion is the version of the layout of the credentials file.
+const fileVersion = "1.0"
+
+// encryptedFile is the layout of the credentials file.
+type encryptedFile struct {
+	// Version is the version of the layout, fileVersion.
+	Version string `json:"version"`
+
+	// Provider is the name of the encryption provider.
+	Provider string `json:"provider"`
+
+	// Ciphertext is the encrypted JSON map of the server addresses to the
+	// credentials.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// FileStore is a credentials store caching the credentials in a file
+// encrypted with an EncryptionProvider. It implements the Store interface of
+// oras.land/oras-go/v2/registry/remote/credentials.
+type FileStore struct {
+	path     string
+	provider EncryptionProvider
+	mu       sync.Mutex
+}
+
+var _ credentials.Store = (*FileStore)(nil)
+
+// NewFileStore returns a FileStore caching the credentials in the file at
+// path, encrypted with the provider.
+func NewFileStore(path string, provider EncryptionProvider) (*FileStore, error) {
+	if path == "" {
+		return nil, errors.New("credentials file path cannot be empty")
+	}
+	if provider == nil {
+		return nil, errors.New("encryption provider cannot be nil")
+	}
+	return &FileStore{
+		path:     path,
+		provider: provider,
+	}, nil
+}
+
+// NewDefaultFileStore returns a FileStore caching the credentials in
+// {NOTATION_CONFIG}/credentials.enc.json, encrypted with the provider.
+func NewDefaultFileStore(provider EncryptionProvider) (*FileStore, error) {
+	path, err := dir.ConfigFS().SysPath(dir.PathCredentials)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileStore(path, provider)
+}
+
+// Get retrieves the credential of the server address. An empty credential is
+// returned if there is none.
+func (s *FileStore) Get(_ context.Context, serverAddress string) (auth.Credential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	creds, err := s.load()
+	if err != nil {
+		return auth.EmptyCredential, err
+	}
+	return creds[serverAddress], nil
+}
+
+// Put saves the credential of the server address.
+func (s *FileStore) Put(_ context.Context, serverAddress string, cred auth.Credential) error {
+	if serverAddress == "" {
+		return errors.New("server address cannot be empty")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	creds, err := s.load()
+	if err != nil {
+		return err
+	}
+	creds[serverAddress] = cred
+	return s.save(creds)
+}
+
+// Delete removes the credential of the server address.
+func (s *FileStore) Delete(_ context.Context, serverAddress string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	creds, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := creds[serverAddress]; !ok {
+		return nil
+	}
+	delete(creds, serverAddress)
+	return s.save(creds)
+}
+
+// load reads and decrypts the credentials file. An empty map is returned if
+// the file does not exist.
+func (s *FileStore) load() (map[string]auth.Credential, error) {
+	creds := make(map[string]auth.Credential)
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return creds, nil
+		}
+		return nil, err
+	}
+	var encrypted encryptedFile
+	if err := json.Unmarshal(content, &encrypted); err != nil {
+		return nil, fmt.Errorf("malformed credentials file %q: %w", s.path, err)
+	}
+	if encrypted.Version != fileVersion {
+		return nil, fmt.Errorf("credentials file %q has unsupported version %q", s.path, encrypted.Version)
+	}
+	if encrypted.Provider != s.provider.Name() {
+		return nil, fmt.Errorf("credentials file %q is encrypted with provider %q, but got provider %q", s.path, encrypted.Provider, s.provider.Name())
+	}
+	plaintext, err := s.provider.Decrypt(encrypted.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials file %q: %w", s.path, err)
+	}
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, fmt.Errorf("malformed credentials in file %q: %w", s.path, err)
+	}
+	return creds, nil
+}
+
+// save encrypts and writes the credentials file atomically.
+func (s *FileStore) save(creds map[string]auth.Credential) error {
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := s.provider.Encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+	content, err := json.Marshal(encryptedFile{
+		Version:    fileVersion,
+		Provider:   s.provider.Name(),
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return err
+	}
+	parent := filepath.Dir(s.path)
+	if err := os.MkdirAll(parent, 0700); err != nil {
+		return err
+	}
+	return file.WriteFile(parent, s.path, content)
+}
@@ -0,0 +1,118 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestFileStore(t *testing.T) {
+	provider, err := NewPassphraseProvider([]byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "notation", "credentials.enc.json")
+	store, err := NewFileStore(path, provider)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	ctx := context.Background()
+
+	cred, err := store.Get(ctx, "localhost:5000")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if cred != auth.EmptyCredential {
+		t.Fatalf("Get() = %v, want empty credential", cred)
+	}
+
+	want := auth.Credential{Username: "user", Password: "hunter2", AccessToken: "token"}
+	if err := store.Put(ctx, "localhost:5000", want); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(content, []byte("hunter2")) || bytes.Contains(content, []byte("user")) {
+		t.Fatalf("credentials stored in plaintext: %s", content)
+	}
+	if cred, err := store.Get(ctx, "localhost:5000"); err != nil || cred != want {
+		t.Fatalf("Get() = %v, %v, want %v", cred, err, want)
+	}
+
+	if err := store.Delete(ctx, "localhost:5000"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if cred, err := store.Get(ctx, "localhost:5000"); err != nil || cred != auth.EmptyCredential {
+		t.Fatalf("Get() = %v, %v, want empty credential", cred, err)
+	}
+	if err := store.Delete(ctx, "localhost:5000"); err != nil {
+		t.Fatalf("Delete() of missing credential error = %v", err)
+	}
+}
+
+func TestFileStoreProviderMismatch(t *testing.T) {
+	provider, err := NewPassphraseProvider([]byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "credentials.enc.json")
+	store, err := NewFileStore(path, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := store.Put(ctx, "localhost:5000", auth.Credential{RefreshToken: "token"}); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := NewFileStore(path, namedProvider{provider})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = other.Get(ctx, "localhost:5000")
+	if err == nil || !strings.Contains(err.Error(), `is encrypted with provider "passphrase"`) {
+		t.Fatalf("Get() error = %v, want provider mismatch", err)
+	}
+}
+
+func TestNewFileStoreErrors(t *testing.T) {
+	provider, err := NewPassphraseProvider([]byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore("", provider); err == nil {
+		t.Fatal("NewFileStore() with empty path expected error")
+	}
+	if _, err := NewFileStore("credentials.enc.json", nil); err == nil {
+		t.Fatal("NewFileStore() with nil provider expected error")
+	}
+}
+
+// namedProvider renames an EncryptionProvider.
+type namedProvider struct {
+	EncryptionProvider
+}
+
+func (namedProvider) Name() string {
+	return "other"
+}
@@ -0,0 +1,48 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// deadlineReached returns the error of ctx if it is done, or
+// context.DeadlineExceeded if the deadline of ctx is within margin.
+func deadlineReached(ctx context.Context, margin time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if margin > 0 {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < margin {
+			return context.DeadlineExceeded
+		}
+	}
+	return nil
+}
+
+// deadlineSkippedSignatures returns the skipped signatures of the signature
+// manifests left unprocessed due to the deadline.
+func deadlineSkippedSignatures(signatureManifests []ocispec.Descriptor) []SkippedSignature {
+	skipped := make([]SkippedSignature, 0, len(signatureManifests))
+	for _, sigManifestDesc := range signatureManifests {
+		skipped = append(skipped, SkippedSignature{
+			Digest: sigManifestDesc.Digest,
+			Reason: "verification deadline exceeded",
+		})
+	}
+	return skipped
+}
@@ -0,0 +1,107 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// slowVerifier fails the verifications after delay, or when the context is
+// done if delay is zero.
+type slowVerifier struct {
+	dummyVerifier
+	delay time.Duration
+}
+
+func (v *slowVerifier) Verify(ctx context.Context, desc ocispec.Descriptor, signature []byte, opts VerifierVerifyOptions) (*VerificationOutcome, error) {
+	if v.delay > 0 {
+		time.Sleep(v.delay)
+	} else {
+		<-ctx.Done()
+	}
+	return v.dummyVerifier.Verify(ctx, desc, signature, opts)
+}
+
+func TestVerifyDeadline(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	repo := mock.NewRepository()
+	repo.ListSignaturesResponse = signatureManifests(5)
+	opts := VerifyOptions{
+		ArtifactReference:    mock.SampleArtifactUri,
+		MaxSignatureAttempts: 10,
+	}
+
+	t.Run("deadline margin", func(t *testing.T) {
+		verifier := &slowVerifier{
+			dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false},
+			delay:         100 * time.Millisecond,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		opts := opts
+		opts.DeadlineMargin = 120 * time.Millisecond
+		_, outcomes, err := Verify(ctx, verifier, repo, opts)
+		var deadlineErr DeadlineExceededError
+		if !errors.As(err, &deadlineErr) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Verify() error = %v, want DeadlineExceededError", err)
+		}
+		if ctx.Err() != nil {
+			t.Fatal("Verify() did not stop before the deadline")
+		}
+		if deadlineErr.Processed != 1 || len(deadlineErr.Skipped) != 4 {
+			t.Fatalf("DeadlineExceededError = %+v, want 1 processed and 4 skipped signatures", deadlineErr)
+		}
+		if deadlineErr.Skipped[0].Digest != repo.ListSignaturesResponse[1].Digest {
+			t.Fatalf("Skipped[0].Digest = %v, want %v", deadlineErr.Skipped[0].Digest, repo.ListSignaturesResponse[1].Digest)
+		}
+		if len(outcomes) != 1 || len(outcomes[0].SkippedSignatures) != 4 || len(outcomes[0].Warnings) != 4 {
+			t.Fatalf("Verify() outcomes = %+v, want the failed outcome with the skipped signatures", outcomes)
+		}
+	})
+
+	t.Run("deadline reached", func(t *testing.T) {
+		verifier := &slowVerifier{
+			dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, outcomes, err := Verify(ctx, verifier, repo, opts)
+		var deadlineErr DeadlineExceededError
+		if !errors.As(err, &deadlineErr) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Verify() error = %v, want DeadlineExceededError", err)
+		}
+		if deadlineErr.Processed != 1 || len(deadlineErr.Skipped) != 4 || len(outcomes) != 1 {
+			t.Fatalf("Verify() = %+v, %v, want 1 processed and 4 skipped signatures", outcomes, deadlineErr)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		verifier := &slowVerifier{
+			dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false},
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, _, err := Verify(ctx, verifier, repo, opts)
+		if !errors.Is(err, context.Canceled) || errors.As(err, &DeadlineExceededError{}) {
+			t.Fatalf("Verify() error = %v, want context.Canceled", err)
+		}
+	})
+}
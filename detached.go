@@ -0,0 +1,259 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// DetachedSignatureMetadataVersion is the version of the metadata file
+// layout written by [WriteDetachedSignature].
+const DetachedSignatureMetadataVersion = "1.0"
+
+// detachedSignatureExtensions maps the envelope media types to the file
+// extensions of the detached signatures.
+var detachedSignatureExtensions = map[string]string{
+	jws.MediaTypeEnvelope:  "jws",
+	cose.MediaTypeEnvelope: "cose",
+}
+
+// DetachedSignatureMetadata describes a detached blob signature. It is
+// stored next to the signature file as JSON.
+//
+// The metadata is not signed: the digest, the size and the media type of the
+// blob are cross-checked against the signed payload, and the file name
+// against the [ocispec.AnnotationTitle] annotation of the signed payload if
+// present.
+type DetachedSignatureMetadata struct {
+	// Version is the version of the metadata layout,
+	// [DetachedSignatureMetadataVersion].
+	Version string `json:"version"`
+
+	// SignatureMediaType is the envelope media type of the signature.
+	SignatureMediaType string `json:"signatureMediaType"`
+
+	// FileName is the base name of the signed blob.
+	FileName string `json:"fileName"`
+
+	// MediaType is the media type of the signed blob.
+	MediaType string `json:"mediaType"`
+
+	// Digest is the digest of the signed blob.
+	Digest digest.Digest `json:"digest"`
+
+	// Size is the size in bytes of the signed blob.
+	Size int64 `json:"size"`
+}
+
+// DetachedSignature is a blob signature read from the detached signature
+// file layout.
+type DetachedSignature struct {
+	// Signature is the signature envelope.
+	Signature []byte
+
+	// Metadata is the metadata of the signature.
+	Metadata DetachedSignatureMetadata
+}
+
+// DetachedSignaturePath returns the path of the detached signature file of
+// the blob at blobPath, i.e. "<blobPath>.jws.sig" or "<blobPath>.cose.sig".
+// The metadata file is "<signature path>.json".
+func DetachedSignaturePath(blobPath, signatureMediaType string) (string, error) {
+	ext, ok := detachedSignatureExtensions[signatureMediaType]
+	if !ok {
+		return "", &signature.UnsupportedSignatureFormatError{MediaType: signatureMediaType}
+	}
+	return blobPath + "." + ext + ".sig", nil
+}
+
+// detachedSignatureMetadataPath returns the path of the metadata file of the
+// detached signature file at sigPath.
+func detachedSignatureMetadataPath(sigPath string) string {
+	return sigPath + ".json"
+}
+
+// WriteDetachedSignature writes the signature sig of the blob at blobPath in
+// the detached signature file layout, and returns the path of the signature
+// file.
+//
+// The metadata is derived from the signed payload, which is not verified.
+func WriteDetachedSignature(blobPath, signatureMediaType string, sig []byte) (string, error) {
+	sigPath, err := DetachedSignaturePath(blobPath, signatureMediaType)
+	if err != nil {
+		return "", err
+	}
+	sigEnv, err := signature.ParseEnvelope(signatureMediaType, sig)
+	if err != nil {
+		return "", err
+	}
+	envContent, err := sigEnv.Content()
+	if err != nil {
+		return "", err
+	}
+	desc, err := envelope.ParsePayload(&envContent.Payload)
+	if err != nil {
+		return "", err
+	}
+	metadata, err := json.MarshalIndent(DetachedSignatureMetadata{
+		Version:            DetachedSignatureMetadataVersion,
+		SignatureMediaType: signatureMediaType,
+		FileName:           filepath.Base(blobPath),
+		MediaType:          desc.MediaType,
+		Digest:             desc.Digest,
+		Size:               desc.Size,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(sigPath, sig, 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(detachedSignatureMetadataPath(sigPath), metadata, 0644); err != nil {
+		return "", err
+	}
+	return sigPath, nil
+}
+
+// ReadDetachedSignature reads the detached signature file at sigPath and its
+// metadata file.
+func ReadDetachedSignature(sigPath string) (*DetachedSignature, error) {
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) == 0 {
+		return nil, fmt.Errorf("signature file %q is empty", sigPath)
+	}
+	metadataPath := detachedSignatureMetadataPath(sigPath)
+	content, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return nil, err
+	}
+	var metadata DetachedSignatureMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return nil, fmt.Errorf("malformed signature metadata file %q: %w", metadataPath, err)
+	}
+	if metadata.Version != DetachedSignatureMetadataVersion {
+		return nil, fmt.Errorf("signature metadata file %q has unsupported version %q", metadataPath, metadata.Version)
+	}
+	if _, ok := detachedSignatureExtensions[metadata.SignatureMediaType]; !ok {
+		return nil, &signature.UnsupportedSignatureFormatError{MediaType: metadata.SignatureMediaType}
+	}
+	if metadata.FileName == "" {
+		return nil, fmt.Errorf("signature metadata file %q is missing the file name", metadataPath)
+	}
+	if err := metadata.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("signature metadata file %q has invalid digest: %w", metadataPath, err)
+	}
+	return &DetachedSignature{
+		Signature: sig,
+		Metadata:  metadata,
+	}, nil
+}
+
+// VerifyDetachedSignature verifies the blob at blobPath against the detached
+// signature file at sigPath.
+//
+// The file name, the size and the digest of the blob are checked against
+// the metadata of the signature, and the metadata against the signed
+// payload, before the signature is verified with [VerifyBlob]. If
+// verifyBlobOpts has no signature media type or content media type, the ones
+// of the metadata are used.
+func VerifyDetachedSignature(ctx context.Context, blobVerifier BlobVerifier, blobPath, sigPath string, verifyBlobOpts VerifyBlobOptions) (ocispec.Descriptor, *VerificationOutcome, error) {
+	detached, err := ReadDetachedSignature(sigPath)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	metadata := detached.Metadata
+	if name := filepath.Base(blobPath); name != metadata.FileName {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("signature %q is for file %q, but got file %q", sigPath, metadata.FileName, name)
+	}
+	if err := checkDetachedBlob(blobPath, metadata); err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	if verifyBlobOpts.SignatureMediaType == "" {
+		verifyBlobOpts.SignatureMediaType = metadata.SignatureMediaType
+	}
+	if verifyBlobOpts.ContentMediaType == "" {
+		verifyBlobOpts.ContentMediaType = metadata.MediaType
+	}
+	blob, err := os.Open(blobPath)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	defer blob.Close()
+	_, outcome, err := VerifyBlob(ctx, blobVerifier, blob, detached.Signature, verifyBlobOpts)
+	if err != nil {
+		return ocispec.Descriptor{}, outcome, err
+	}
+	if outcome.EnvelopeContent == nil {
+		// signature verification is skipped
+		return ocispec.Descriptor{}, outcome, nil
+	}
+
+	// bind the metadata to the signed payload
+	desc, err := envelope.ParsePayload(&outcome.EnvelopeContent.Payload)
+	if err != nil {
+		return ocispec.Descriptor{}, outcome, err
+	}
+	if desc.Digest != metadata.Digest || desc.Size != metadata.Size || desc.MediaType != metadata.MediaType {
+		return ocispec.Descriptor{}, outcome, errors.New("signature metadata does not match the signed payload")
+	}
+	if title, ok := desc.Annotations[ocispec.AnnotationTitle]; ok && title != metadata.FileName {
+		return ocispec.Descriptor{}, outcome, fmt.Errorf("signature is for file %q, but got file %q", title, metadata.FileName)
+	}
+	return desc, outcome, nil
+}
+
+// checkDetachedBlob checks the size and the digest of the blob at blobPath
+// against the metadata.
+func checkDetachedBlob(blobPath string, metadata DetachedSignatureMetadata) error {
+	blob, err := os.Open(blobPath)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+	fi, err := blob.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() != metadata.Size {
+		return fmt.Errorf("size of file %q is %d bytes, but the signature is for %d bytes", blobPath, fi.Size(), metadata.Size)
+	}
+	if !metadata.Digest.Algorithm().Available() {
+		return fmt.Errorf("unsupported digest algorithm %q", metadata.Digest.Algorithm())
+	}
+	digester := metadata.Digest.Algorithm().Digester()
+	if _, err := io.Copy(digester.Hash(), blob); err != nil {
+		return err
+	}
+	if got := digester.Digest(); got != metadata.Digest {
+		return fmt.Errorf("digest of file %q is %s, but the signature is for %s", blobPath, got, metadata.Digest)
+	}
+	return nil
+}
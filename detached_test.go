@@ -0,0 +1,184 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestDetachedSignaturePath(t *testing.T) {
+	got, err := notation.DetachedSignaturePath("dir/blob.tar", jws.MediaTypeEnvelope)
+	if err != nil || got != "dir/blob.tar.jws.sig" {
+		t.Fatalf("DetachedSignaturePath() = %q, %v", got, err)
+	}
+	got, err = notation.DetachedSignaturePath("dir/blob.tar", cose.MediaTypeEnvelope)
+	if err != nil || got != "dir/blob.tar.cose.sig" {
+		t.Fatalf("DetachedSignaturePath() = %q, %v", got, err)
+	}
+	if _, err := notation.DetachedSignaturePath("dir/blob.tar", "application/unknown"); err == nil {
+		t.Fatal("DetachedSignaturePath() expected error")
+	}
+}
+
+func TestDetachedSignature(t *testing.T) {
+	ctx := context.Background()
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := verifier.NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "blob", s.Root()), verifier.VerifierOptions{
+		BlobTrustPolicy: &trustpolicy.BlobDocument{
+			Version: "1.0",
+			TrustPolicies: []trustpolicy.BlobTrustPolicy{
+				{
+					Name:                  "blob-policy",
+					SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: "strict"},
+					TrustStores:           []string{"ca:blob"},
+					TrustedIdentities:     []string{"*"},
+					GlobalPolicy:          true,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	content := []byte("firmware image")
+	blobPath := filepath.Join(dir, "firmware.bin")
+	if err := os.WriteFile(blobPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sig, _, err := notation.SignBlob(ctx, s, bytes.NewReader(content), notation.SignBlobOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: cose.MediaTypeEnvelope},
+		ContentMediaType:  "application/octet-stream",
+		UserMetadata:      map[string]string{ocispec.AnnotationTitle: "firmware.bin"},
+	})
+	if err != nil {
+		t.Fatalf("SignBlob() error = %v", err)
+	}
+	sigPath, err := notation.WriteDetachedSignature(blobPath, cose.MediaTypeEnvelope, sig)
+	if err != nil {
+		t.Fatalf("WriteDetachedSignature() error = %v", err)
+	}
+	if sigPath != blobPath+".cose.sig" {
+		t.Fatalf("WriteDetachedSignature() = %q", sigPath)
+	}
+
+	detached, err := notation.ReadDetachedSignature(sigPath)
+	if err != nil {
+		t.Fatalf("ReadDetachedSignature() error = %v", err)
+	}
+	want := notation.DetachedSignatureMetadata{
+		Version:            notation.DetachedSignatureMetadataVersion,
+		SignatureMediaType: cose.MediaTypeEnvelope,
+		FileName:           "firmware.bin",
+		MediaType:          "application/octet-stream",
+		Digest:             digest.SHA384.FromBytes(content),
+		Size:               int64(len(content)),
+	}
+	if detached.Metadata != want {
+		t.Fatalf("ReadDetachedSignature() metadata = %+v, want %+v", detached.Metadata, want)
+	}
+	if !bytes.Equal(detached.Signature, sig) {
+		t.Fatal("ReadDetachedSignature() signature mismatch")
+	}
+
+	desc, _, err := notation.VerifyDetachedSignature(ctx, v, blobPath, sigPath, notation.VerifyBlobOptions{})
+	if err != nil {
+		t.Fatalf("VerifyDetachedSignature() error = %v", err)
+	}
+	if desc.Digest != want.Digest {
+		t.Fatalf("VerifyDetachedSignature() = %+v", desc)
+	}
+
+	t.Run("renamed file", func(t *testing.T) {
+		renamed := filepath.Join(dir, "other.bin")
+		if err := os.WriteFile(renamed, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := notation.VerifyDetachedSignature(ctx, v, renamed, sigPath, notation.VerifyBlobOptions{})
+		if err == nil || !strings.Contains(err.Error(), `is for file "firmware.bin"`) {
+			t.Fatalf("VerifyDetachedSignature() error = %v", err)
+		}
+	})
+
+	t.Run("tampered file", func(t *testing.T) {
+		tamperedDir := t.TempDir()
+		tampered := filepath.Join(tamperedDir, "firmware.bin")
+		if err := os.WriteFile(tampered, []byte("firmware imagf"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := notation.VerifyDetachedSignature(ctx, v, tampered, sigPath, notation.VerifyBlobOptions{})
+		if err == nil || !strings.Contains(err.Error(), "digest of file") {
+			t.Fatalf("VerifyDetachedSignature() error = %v", err)
+		}
+	})
+
+	t.Run("tampered metadata", func(t *testing.T) {
+		tamperedDir := t.TempDir()
+		tampered := filepath.Join(tamperedDir, "firmware.bin")
+		other := []byte("other firmware")
+		if err := os.WriteFile(tampered, other, 0644); err != nil {
+			t.Fatal(err)
+		}
+		tamperedSigPath := tampered + ".cose.sig"
+		if err := os.WriteFile(tamperedSigPath, sig, 0644); err != nil {
+			t.Fatal(err)
+		}
+		metadata := strings.NewReplacer(
+			want.Digest.String(), digest.SHA384.FromBytes(other).String(),
+		).Replace(readFile(t, sigPath+".json"))
+		if err := os.WriteFile(tamperedSigPath+".json", []byte(metadata), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := notation.VerifyDetachedSignature(ctx, v, tampered, tamperedSigPath, notation.VerifyBlobOptions{}); err == nil {
+			t.Fatal("VerifyDetachedSignature() expected error")
+		}
+	})
+
+	t.Run("missing metadata", func(t *testing.T) {
+		if err := os.Remove(sigPath + ".json"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := notation.ReadDetachedSignature(sigPath); err == nil {
+			t.Fatal("ReadDetachedSignature() expected error")
+		}
+	})
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
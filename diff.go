@@ -0,0 +1,195 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/tspclient-go"
+)
+
+// SignatureDifference is a difference between two signatures.
+type SignatureDifference struct {
+	// Field is the name of the differing field, e.g. "SigningTime" or
+	// "ExtendedAttribute[io.cncf.notary.verificationPlugin]".
+	Field string
+
+	// A is the value of the field in the first signature, empty if the field
+	// is absent.
+	A string
+
+	// B is the value of the field in the second signature, empty if the field
+	// is absent.
+	B string
+}
+
+// DiffSignatures compares the signature envelopes sigA of media type
+// mediaTypeA and sigB of media type mediaTypeB, and returns their
+// differences in envelope type, payload, signer identity, signed attributes,
+// signing agent and timestamp, in this order.
+//
+// The signatures are parsed but not verified: DiffSignatures is meant for
+// auditing why two signatures over the same artifact are treated differently,
+// not for trusting either of them.
+func DiffSignatures(mediaTypeA string, sigA []byte, mediaTypeB string, sigB []byte) ([]SignatureDifference, error) {
+	fieldsA, err := signatureFields(mediaTypeA, sigA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the first signature: %w", err)
+	}
+	fieldsB, err := signatureFields(mediaTypeB, sigB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the second signature: %w", err)
+	}
+
+	names := make(map[string]string, len(fieldsA)+len(fieldsB))
+	for name := range fieldsA {
+		names[name] = ""
+	}
+	for name := range fieldsB {
+		names[name] = ""
+	}
+	var differences []SignatureDifference
+	for _, name := range sortedFieldNames(names) {
+		if fieldsA[name] != fieldsB[name] {
+			differences = append(differences, SignatureDifference{
+				Field: name,
+				A:     fieldsA[name],
+				B:     fieldsB[name],
+			})
+		}
+	}
+	return differences, nil
+}
+
+// signatureFieldNames are the names of the compared fields of a signature, in
+// the order of the differences.
+var signatureFieldNames = []string{
+	"EnvelopeType",
+	"PayloadContentType",
+	"Payload",
+	"SignerIdentity",
+	"SignerIssuer",
+	"CertificateChain",
+	"SignatureAlgorithm",
+	"SigningScheme",
+	"SigningTime",
+	"Expiry",
+	"SigningAgent",
+	"Timestamp",
+}
+
+// extendedAttributeField is the prefix of the field names of the extended
+// attributes.
+const extendedAttributeField = "ExtendedAttribute"
+
+// fieldOrder returns the order of the differences on the field. The extended
+// attributes go right after the expiry.
+func fieldOrder(name string) int {
+	if strings.HasPrefix(name, extendedAttributeField+"[") {
+		name = "Expiry"
+	}
+	for i, fieldName := range signatureFieldNames {
+		if name == fieldName {
+			return i
+		}
+	}
+	return len(signatureFieldNames)
+}
+
+// sortedFieldNames returns the field names sorted by order then by name.
+func sortedFieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if oi, oj := fieldOrder(names[i]), fieldOrder(names[j]); oi != oj {
+			return oi < oj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// signatureFields parses the signature envelope and returns the string values
+// of its compared fields. The absent fields are omitted.
+func signatureFields(mediaType string, sig []byte) (map[string]string, error) {
+	sigEnv, err := signature.ParseEnvelope(mediaType, sig)
+	if err != nil {
+		return nil, err
+	}
+	content, err := sigEnv.Content()
+	if err != nil {
+		return nil, err
+	}
+	signerInfo := content.SignerInfo
+	fields := map[string]string{
+		"EnvelopeType":       mediaType,
+		"PayloadContentType": content.Payload.ContentType,
+		"Payload":            string(content.Payload.Content),
+		"SignatureAlgorithm": fmt.Sprint(signerInfo.SignatureAlgorithm),
+		"SigningScheme":      string(signerInfo.SignedAttributes.SigningScheme),
+	}
+	if len(signerInfo.CertificateChain) > 0 {
+		signingCert := signerInfo.CertificateChain[0]
+		fields["SignerIdentity"] = signingCert.Subject.String()
+		fields["SignerIssuer"] = signingCert.Issuer.String()
+		var thumbprints []string
+		for _, cert := range signerInfo.CertificateChain {
+			checkSum := sha256.Sum256(cert.Raw)
+			thumbprints = append(thumbprints, hex.EncodeToString(checkSum[:]))
+		}
+		fields["CertificateChain"] = strings.Join(thumbprints, ",")
+	}
+	if t := signerInfo.SignedAttributes.SigningTime; !t.IsZero() {
+		fields["SigningTime"] = t.UTC().Format(time.RFC3339)
+	}
+	if t := signerInfo.SignedAttributes.Expiry; !t.IsZero() {
+		fields["Expiry"] = t.UTC().Format(time.RFC3339)
+	}
+	for _, attr := range signerInfo.SignedAttributes.ExtendedAttributes {
+		value := fmt.Sprint(attr.Value)
+		if attr.Critical {
+			value += " (critical)"
+		}
+		fields[fmt.Sprintf("%s[%v]", extendedAttributeField, attr.Key)] = value
+	}
+	if agent := signerInfo.UnsignedAttributes.SigningAgent; agent != "" {
+		fields["SigningAgent"] = agent
+	}
+	if ts := signerInfo.UnsignedAttributes.TimestampSignature; len(ts) > 0 {
+		fields["Timestamp"] = timestampField(ts)
+	}
+	return fields, nil
+}
+
+// timestampField returns the time of the timestamp countersignature, or its
+// digest if it cannot be parsed.
+func timestampField(ts []byte) string {
+	token, err := tspclient.ParseSignedToken(ts)
+	if err == nil {
+		var info *tspclient.TSTInfo
+		if info, err = token.Info(); err == nil {
+			return info.GenTime.UTC().Format(time.RFC3339)
+		}
+	}
+	checkSum := sha256.Sum256(ts)
+	return "unparsable timestamp sha256:" + hex.EncodeToString(checkSum[:])
+}
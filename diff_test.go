@@ -0,0 +1,83 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/internal/mock"
+)
+
+func TestDiffSignatures(t *testing.T) {
+	t.Run("identical signatures", func(t *testing.T) {
+		differences, err := DiffSignatures(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv, jws.MediaTypeEnvelope, mock.MockCaValidSigEnv)
+		if err != nil {
+			t.Fatalf("DiffSignatures() error = %v", err)
+		}
+		if len(differences) != 0 {
+			t.Fatalf("DiffSignatures() = %+v, want no difference", differences)
+		}
+	})
+
+	t.Run("signing scheme", func(t *testing.T) {
+		differences, err := DiffSignatures(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv, jws.MediaTypeEnvelope, mock.MockSaValidSigEnv)
+		if err != nil {
+			t.Fatalf("DiffSignatures() error = %v", err)
+		}
+		want := SignatureDifference{
+			Field: "SigningScheme",
+			A:     string(signature.SigningSchemeX509),
+			B:     string(signature.SigningSchemeX509SigningAuthority),
+		}
+		if !containsDifference(differences, want) {
+			t.Fatalf("DiffSignatures() = %+v, want %+v", differences, want)
+		}
+		for i := 1; i < len(differences); i++ {
+			if fieldOrder(differences[i-1].Field) > fieldOrder(differences[i].Field) {
+				t.Fatalf("DiffSignatures() differences are not ordered: %+v", differences)
+			}
+		}
+	})
+
+	t.Run("extended attributes", func(t *testing.T) {
+		differences, err := DiffSignatures(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv, jws.MediaTypeEnvelope, mock.MockCaPluginSigEnv)
+		if err != nil {
+			t.Fatalf("DiffSignatures() error = %v", err)
+		}
+		want := SignatureDifference{
+			Field: "ExtendedAttribute[io.cncf.notary.verificationPlugin]",
+			B:     "plugin-name (critical)",
+		}
+		if !containsDifference(differences, want) {
+			t.Fatalf("DiffSignatures() = %+v, want %+v", differences, want)
+		}
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		if _, err := DiffSignatures(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv, "application/unknown", mock.MockCaValidSigEnv); err == nil {
+			t.Fatal("DiffSignatures() expected error")
+		}
+	})
+}
+
+func containsDifference(differences []SignatureDifference, want SignatureDifference) bool {
+	for _, difference := range differences {
+		if difference == want {
+			return true
+		}
+	}
+	return false
+}
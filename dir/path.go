@@ -75,6 +75,17 @@ const (
 	LocalKeyExtension = ".key"
 	// TrustStoreDir is the directory name of trust store.
 	TrustStoreDir = "truststore"
+	// PathPluginLockFile is the plugin lock file relative path.
+	PathPluginLockFile = "plugins.lock.json"
+	// PathCredentials is the encrypted registry credentials cache file
+	// relative path.
+	PathCredentials = "credentials.enc.json"
+	// PathKeyUsageLog is the append-only signing key usage log file relative
+	// path.
+	PathKeyUsageLog = "keyusage.jsonl"
+	// PathRegistries is the per-host registries configuration file relative
+	// path.
+	PathRegistries = "registries.json"
 )
 
 // The relative path to {NOTATION_LIBEXEC}
@@ -87,6 +98,9 @@ const (
 const (
 	// PathCRLCache is the crl file cache directory relative path.
 	PathCRLCache = "crl"
+	// PathContentCache is the registry content file cache directory relative
+	// path.
+	PathContentCache = "content"
 )
 
 // for unit tests
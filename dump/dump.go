@@ -0,0 +1,189 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dump provides an opt-in debug mode recording the HTTP exchanges of
+// the registry, timestamping and revocation clients, and the requests and
+// responses of the plugins, to diagnose the interoperability issues with
+// registries and plugins without packet captures.
+// Users who want to enable the debug mode should create a dump.Dumper
+// writing to an io.Writer and include it in context by calling
+// dump.WithDumper. The credentials and the sensitive values are redacted
+// from the records.
+// The HTTP exchanges are recorded for the clients created by notation: the
+// default revocation validators of the verifiers, the timestampers and HTTP
+// clients of network.Config, and the remote repositories configured by
+// registry.NewRepositoryWithOptions, e.g. with a network configuration.
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type contextKey int
+
+// dumperKey is the associated key type for dumper entry in context.
+const dumperKey contextKey = iota
+
+// DefaultMaxBodySize is the default maximum size in bytes of a body recorded
+// in a [Record].
+const DefaultMaxBodySize = 16 * 1024 // 16 KiB
+
+// RecordType is the type of a record.
+type RecordType string
+
+// Types of the records.
+const (
+	// RecordHTTP is the type of the records of the HTTP exchanges.
+	RecordHTTP RecordType = "http"
+
+	// RecordPlugin is the type of the records of the plugin executions.
+	RecordPlugin RecordType = "plugin"
+)
+
+// Record is a recorded exchange, written as a JSON line.
+type Record struct {
+	// Type is the type of the record.
+	Type RecordType `json:"type"`
+
+	// Time is the start time of the exchange.
+	Time time.Time `json:"time"`
+
+	// Duration is the duration of the exchange.
+	Duration time.Duration `json:"duration"`
+
+	// Component is the component of an HTTP exchange, e.g. "registry",
+	// "timestamping" or "revocation", if known.
+	Component string `json:"component,omitempty"`
+
+	// Request is the request of an HTTP exchange.
+	Request *HTTPMessage `json:"request,omitempty"`
+
+	// Response is the response of an HTTP exchange, if received.
+	Response *HTTPMessage `json:"response,omitempty"`
+
+	// Plugin is the name of the plugin of a plugin execution.
+	Plugin string `json:"plugin,omitempty"`
+
+	// Command is the plugin command of a plugin execution.
+	Command string `json:"command,omitempty"`
+
+	// PluginRequest is the JSON request of a plugin execution.
+	PluginRequest json.RawMessage `json:"pluginRequest,omitempty"`
+
+	// PluginResponse is the JSON response of a successful plugin execution.
+	PluginResponse json.RawMessage `json:"pluginResponse,omitempty"`
+
+	// Stderr is the standard error of a plugin execution, if any.
+	Stderr string `json:"stderr,omitempty"`
+
+	// Truncated reports whether the plugin request, response or standard
+	// error are omitted or truncated, as larger than the maximum body size
+	// or not redactable.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Error is the error of the exchange, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// HTTPMessage is a recorded HTTP request or response.
+type HTTPMessage struct {
+	// Method is the method of a request.
+	Method string `json:"method,omitempty"`
+
+	// URL is the URL of a request.
+	URL string `json:"url,omitempty"`
+
+	// StatusCode is the status code of a response.
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Header is the header of the message.
+	Header map[string][]string `json:"header,omitempty"`
+
+	// Body is the body of a text message, e.g. a JSON document.
+	Body string `json:"body,omitempty"`
+
+	// BinaryBody is the body of a binary message, e.g. an OCSP response.
+	BinaryBody []byte `json:"binaryBody,omitempty"`
+
+	// BodySize is the size in bytes of the body read.
+	BodySize int64 `json:"bodySize,omitempty"`
+
+	// Truncated reports whether the recorded body is truncated, or omitted
+	// as it cannot be redacted, e.g. a truncated JSON document or a text
+	// body.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// DumperOptions contains optional settings of a [Dumper].
+type DumperOptions struct {
+	// MaxBodySize is the maximum size in bytes of a recorded body. Larger
+	// bodies are truncated. If set to less than or equals to zero,
+	// [DefaultMaxBodySize] is used.
+	MaxBodySize int64
+}
+
+// Dumper writes the records to a writer, one JSON document per line. It is
+// safe for concurrent use.
+type Dumper struct {
+	mu          sync.Mutex
+	encoder     *json.Encoder
+	maxBodySize int64
+}
+
+// NewDumper returns a [Dumper] writing to w.
+func NewDumper(w io.Writer) *Dumper {
+	return NewDumperWithOptions(w, DumperOptions{})
+}
+
+// NewDumperWithOptions returns a [Dumper] writing to w with user specified
+// options.
+func NewDumperWithOptions(w io.Writer, opts DumperOptions) *Dumper {
+	maxBodySize := opts.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+	return &Dumper{
+		encoder:     json.NewEncoder(w),
+		maxBodySize: maxBodySize,
+	}
+}
+
+// Write writes record. The errors of the writer are ignored, so that the
+// debug mode does not fail the operations.
+func (d *Dumper) Write(record Record) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_ = d.encoder.Encode(record)
+}
+
+// WithDumper is used by callers to set the Dumper in the context.
+// It enables the debug mode in notation.
+func WithDumper(ctx context.Context, dumper *Dumper) context.Context {
+	return context.WithValue(ctx, dumperKey, dumper)
+}
+
+// FromContext returns the Dumper in the context, or nil if the debug mode is
+// not enabled.
+func FromContext(ctx context.Context) *Dumper {
+	dumper, _ := ctx.Value(dumperKey).(*Dumper)
+	return dumper
+}
+
+// Enabled reports whether a Dumper is set in the context.
+func Enabled(ctx context.Context) bool {
+	return FromContext(ctx) != nil
+}
@@ -0,0 +1,65 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dump
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// decodeRecords decodes the JSON lines written by a Dumper.
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []Record {
+	t.Helper()
+	var records []Record
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestDumper(t *testing.T) {
+	ctx := context.Background()
+	if Enabled(ctx) || FromContext(ctx) != nil {
+		t.Fatal("debug mode enabled without dumper")
+	}
+
+	var buf bytes.Buffer
+	dumper := NewDumper(&buf)
+	if dumper.maxBodySize != DefaultMaxBodySize {
+		t.Fatalf("maxBodySize = %d, want %d", dumper.maxBodySize, DefaultMaxBodySize)
+	}
+	ctx = WithDumper(ctx, dumper)
+	if !Enabled(ctx) || FromContext(ctx) != dumper {
+		t.Fatal("debug mode not enabled with dumper")
+	}
+
+	dumper.Write(Record{Type: RecordHTTP, Component: "registry"})
+	dumper.Write(Record{Type: RecordPlugin, Plugin: "foo"})
+	records := decodeRecords(t, &buf)
+	if len(records) != 2 || records[0].Component != "registry" || records[1].Plugin != "foo" {
+		t.Fatalf("records = %+v", records)
+	}
+
+	if dumper := NewDumperWithOptions(&buf, DumperOptions{MaxBodySize: 10}); dumper.maxBodySize != 10 {
+		t.Fatalf("maxBodySize = %d, want 10", dumper.maxBodySize)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-go/log"
+)
+
+// Transport is an http.RoundTripper recording the HTTP exchanges to the
+// Dumper in the context of the requests, if any. The exchanges are recorded
+// when the response bodies are closed.
+type Transport struct {
+	// Component is the component of the exchanges, e.g. "registry".
+	Component string
+
+	// Base is the transport sending the requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip sends the request with the base transport, and records the
+// exchange if the debug mode is enabled in the context of the request.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx := req.Context()
+	dumper := FromContext(ctx)
+	if dumper == nil {
+		return base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	record := Record{
+		Type:      RecordHTTP,
+		Time:      start.UTC(),
+		Component: t.Component,
+		Request: &HTTPMessage{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: redactHeader(req.Header),
+		},
+	}
+	var requestBody *capture
+	if req.Body != nil && req.Body != http.NoBody {
+		requestBody = newCapture(req.Body, dumper.maxBodySize)
+		req = req.Clone(ctx)
+		req.Body = requestBody
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		requestBody.fill(ctx, record.Request, req.Header.Get("Content-Type"))
+		record.Duration = time.Since(start)
+		record.Error = err.Error()
+		dumper.Write(record)
+		return nil, err
+	}
+	responseBody := newCapture(resp.Body, dumper.maxBodySize)
+	var once sync.Once
+	resp.Body = &recordingBody{
+		capture: responseBody,
+		record: func() {
+			once.Do(func() {
+				requestBody.fill(ctx, record.Request, req.Header.Get("Content-Type"))
+				record.Response = &HTTPMessage{
+					StatusCode: resp.StatusCode,
+					Header:     redactHeader(resp.Header),
+				}
+				responseBody.fill(ctx, record.Response, resp.Header.Get("Content-Type"))
+				record.Duration = time.Since(start)
+				dumper.Write(record)
+			})
+		},
+	}
+	return resp, nil
+}
+
+// recordingBody is a response body recording the exchange when closed.
+type recordingBody struct {
+	*capture
+	record func()
+}
+
+// Close closes the body and records the exchange.
+func (b *recordingBody) Close() error {
+	err := b.capture.Close()
+	b.record()
+	return err
+}
+
+// capture is a body capturing up to limit bytes read.
+type capture struct {
+	io.ReadCloser
+	limit int64
+
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	size int64
+}
+
+func newCapture(body io.ReadCloser, limit int64) *capture {
+	return &capture{
+		ReadCloser: body,
+		limit:      limit,
+	}
+}
+
+// Read reads from the body and captures the bytes read up to the limit.
+func (c *capture) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size += int64(n)
+	if remaining := c.limit - int64(c.buf.Len()); remaining > 0 {
+		c.buf.Write(p[:min(int64(n), remaining)])
+	}
+	return n, err
+}
+
+// fill sets the body of m of media type contentType to the bytes captured,
+// redacted. It does nothing if c is nil.
+func (c *capture) fill(ctx context.Context, m *HTTPMessage, contentType string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m.BodySize = c.size
+	data := c.buf.Bytes()
+	truncated := c.size > int64(len(data))
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if truncated || !json.Valid(data) {
+			// the values of a partial document cannot be redacted
+			m.Truncated = true
+			return
+		}
+		m.Body = string(log.RedactJSON(ctx, data))
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(data))
+		if truncated || err != nil {
+			m.Truncated = true
+			return
+		}
+		m.Body = redactValues(values).Encode()
+	case strings.HasPrefix(mediaType, "text/"):
+		// the sensitive values of free-form text cannot be located, so that
+		// only the size is recorded
+		m.Truncated = true
+	default:
+		m.BinaryBody = bytes.Clone(data)
+		m.Truncated = truncated
+	}
+}
+
+// redactURL returns u with the password and the sensitive query values
+// redacted.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.RawQuery != "" {
+		if values, err := url.ParseQuery(redacted.RawQuery); err == nil {
+			redacted.RawQuery = redactValues(values).Encode()
+		} else {
+			redacted.RawQuery = log.Redacted
+		}
+	}
+	return redacted.Redacted()
+}
+
+// redactValues returns a copy of values with the sensitive values redacted.
+func redactValues(values url.Values) url.Values {
+	redacted := make(url.Values, len(values))
+	for key, vs := range values {
+		if log.IsSensitiveKey(key) {
+			vs = []string{log.Redacted}
+		}
+		redacted[key] = vs
+	}
+	return redacted
+}
+
+// redactHeader returns a copy of header with the credentials, the cookies
+// and the sensitive values redacted.
+func redactHeader(header http.Header) map[string][]string {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := make(map[string][]string, len(header))
+	for key, values := range header {
+		if log.IsSensitiveKey(key) || strings.EqualFold(key, "Cookie") || strings.EqualFold(key, "Set-Cookie") {
+			values = []string{log.Redacted}
+		}
+		redacted[key] = values
+	}
+	return redacted
+}
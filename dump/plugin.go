@@ -0,0 +1,67 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/notaryproject/notation-go/log"
+)
+
+// WritePlugin records the execution of the command of the plugin started at
+// start, with the JSON request, and the response or the error, to the Dumper
+// in the context, if any.
+func WritePlugin(ctx context.Context, pluginName, command string, start time.Time, request, response, stderr []byte, err error) {
+	dumper := FromContext(ctx)
+	if dumper == nil {
+		return
+	}
+	record := Record{
+		Type:     RecordPlugin,
+		Time:     start.UTC(),
+		Duration: time.Since(start),
+		Plugin:   pluginName,
+		Command:  command,
+	}
+	var ok bool
+	if record.PluginRequest, ok = dumper.redactJSON(ctx, request); !ok {
+		record.Truncated = true
+	}
+	if err != nil {
+		record.Error = err.Error()
+	} else if record.PluginResponse, ok = dumper.redactJSON(ctx, response); !ok {
+		record.Truncated = true
+	}
+	if len(stderr) > 0 {
+		if redacted, ok := dumper.redactJSON(ctx, stderr); ok {
+			record.Stderr = string(redacted)
+		} else {
+			// the standard error may be free text, e.g. a panic
+			record.Stderr = string(stderr[:min(int64(len(stderr)), dumper.maxBodySize)])
+			record.Truncated = record.Truncated || int64(len(stderr)) > dumper.maxBodySize
+		}
+	}
+	dumper.Write(record)
+}
+
+// redactJSON returns the JSON document data redacted. It returns false if
+// data is larger than the maximum body size or is not a JSON document.
+func (d *Dumper) redactJSON(ctx context.Context, data []byte) (json.RawMessage, bool) {
+	if int64(len(data)) > d.maxBodySize || !json.Valid(data) {
+		return nil, false
+	}
+	return log.RedactJSON(ctx, data), true
+}
@@ -0,0 +1,56 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePlugin(t *testing.T) {
+	// no dumper in the context
+	WritePlugin(context.Background(), "foo", "describe-key", time.Now(), []byte(`{}`), []byte(`{}`), nil, nil)
+
+	var buf bytes.Buffer
+	ctx := WithDumper(context.Background(), NewDumperWithOptions(&buf, DumperOptions{MaxBodySize: 128}))
+	request := []byte(`{"contractVersion":"1.0","keyId":"key","pluginConfig":{"password":"secret","region":"us"}}`)
+	WritePlugin(ctx, "foo", "describe-key", time.Now(), request, []byte(`{"keyId":"key","keySpec":"EC-256"}`), nil, nil)
+	WritePlugin(ctx, "foo", "generate-signature", time.Now(), request, nil, []byte(`{"errorCode":"ERROR","errorMessage":"failed","metadata":{"token":"secret"}}`), errors.New("exit status 1"))
+	WritePlugin(ctx, "foo", "generate-envelope", time.Now(), []byte(`{"payload":"`+strings.Repeat("a", 200)+`"}`), []byte("panic: oops"), []byte("panic: oops"), nil)
+
+	dumped := buf.String()
+	if strings.Contains(dumped, "secret") {
+		t.Fatalf("records contain secrets: %s", dumped)
+	}
+	records := decodeRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	success := records[0]
+	if success.Type != RecordPlugin || success.Plugin != "foo" || success.Command != "describe-key" || !strings.Contains(string(success.PluginRequest), `"region":"us"`) || !strings.Contains(string(success.PluginResponse), "EC-256") || success.Truncated {
+		t.Fatalf("records[0] = %+v", success)
+	}
+	failure := records[1]
+	if failure.Error != "exit status 1" || failure.PluginResponse != nil || !strings.Contains(failure.Stderr, "failed") {
+		t.Fatalf("records[1] = %+v", failure)
+	}
+	malformed := records[2]
+	if !malformed.Truncated || malformed.PluginRequest != nil || malformed.PluginResponse != nil || malformed.Stderr != "panic: oops" {
+		t.Fatalf("records[2] = %+v", malformed)
+	}
+}
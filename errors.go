@@ -13,6 +13,12 @@
 
 package notation
 
+import (
+	"fmt"
+
+	"github.com/opencontainers/go-digest"
+)
+
 // ErrorPushSignatureFailed is used when failed to push signature to the
 // target registry.
 //
@@ -22,7 +28,8 @@ type ErrorPushSignatureFailed = PushSignatureFailedError
 // PushSignatureFailedError is used when failed to push signature to the
 // target registry.
 type PushSignatureFailedError struct {
-	Msg string
+	Msg        string
+	InnerError error
 }
 
 func (e PushSignatureFailedError) Error() string {
@@ -32,6 +39,17 @@ func (e PushSignatureFailedError) Error() string {
 	return "failed to push signature to registry"
 }
 
+func (e PushSignatureFailedError) Unwrap() error {
+	return e.InnerError
+}
+
+// Is returns true if target is a PushSignatureFailedError with the same message,
+// regardless of the inner error.
+func (e PushSignatureFailedError) Is(target error) bool {
+	t, ok := target.(PushSignatureFailedError)
+	return ok && t.Msg == e.Msg
+}
+
 // ErrorVerificationInconclusive is used when signature verification fails due
 // to a runtime error (e.g. a network error)
 //
@@ -41,7 +59,8 @@ type ErrorVerificationInconclusive = VerificationInconclusiveError
 // VerificationInconclusiveError is used when signature verification fails due
 // to a runtime error (e.g. a network error)
 type VerificationInconclusiveError struct {
-	Msg string
+	Msg        string
+	InnerError error
 }
 
 func (e VerificationInconclusiveError) Error() string {
@@ -51,6 +70,17 @@ func (e VerificationInconclusiveError) Error() string {
 	return "signature verification was inclusive due to an unexpected error"
 }
 
+func (e VerificationInconclusiveError) Unwrap() error {
+	return e.InnerError
+}
+
+// Is returns true if target is a VerificationInconclusiveError with the same message,
+// regardless of the inner error.
+func (e VerificationInconclusiveError) Is(target error) bool {
+	t, ok := target.(VerificationInconclusiveError)
+	return ok && t.Msg == e.Msg
+}
+
 // ErrorNoApplicableTrustPolicy is used when there is no trust policy that
 // applies to the given artifact
 //
@@ -79,7 +109,8 @@ type ErrorSignatureRetrievalFailed = SignatureRetrievalFailedError
 // SignatureRetrievalFailedError is used when notation is unable to retrieve the
 // digital signature/s for the given artifact
 type SignatureRetrievalFailedError struct {
-	Msg string
+	Msg        string
+	InnerError error
 }
 
 func (e SignatureRetrievalFailedError) Error() string {
@@ -89,6 +120,17 @@ func (e SignatureRetrievalFailedError) Error() string {
 	return "unable to retrieve the digital signature from the registry"
 }
 
+func (e SignatureRetrievalFailedError) Unwrap() error {
+	return e.InnerError
+}
+
+// Is returns true if target is a SignatureRetrievalFailedError with the same message,
+// regardless of the inner error.
+func (e SignatureRetrievalFailedError) Is(target error) bool {
+	t, ok := target.(SignatureRetrievalFailedError)
+	return ok && t.Msg == e.Msg
+}
+
 // ErrorVerificationFailed is used when it is determined that the digital
 // signature/s is not valid for the given artifact
 //
@@ -126,3 +168,73 @@ func (e UserMetadataVerificationFailedError) Error() string {
 	}
 	return "unable to find specified metadata in the signature"
 }
+
+// SubjectMismatchError is used when the subject descriptor of a signature
+// manifest does not match the artifact being verified, e.g. when a registry
+// rewrites the manifests.
+type SubjectMismatchError struct {
+	Msg string
+}
+
+func (e SubjectMismatchError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return "signature manifest subject does not match the artifact"
+}
+
+// MalformedSignatureError is used when a signature envelope or its payload
+// is malformed, e.g. when the payload is not canonically encoded.
+type MalformedSignatureError struct {
+	Msg string
+}
+
+func (e MalformedSignatureError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return "signature is malformed"
+}
+
+// TagMutatedError is used when a tag has moved to another digest since it
+// was resolved with [ResolveTag].
+type TagMutatedError struct {
+	// Reference is the tag reference, e.g. "localhost:5000/net-monitor:v1".
+	Reference string
+
+	// Resolved is the digest the tag was resolved to.
+	Resolved digest.Digest
+
+	// Current is the digest the tag points to now.
+	Current digest.Digest
+}
+
+func (e TagMutatedError) Error() string {
+	return fmt.Sprintf("tag %s has moved from digest %s to %s", e.Reference, e.Resolved, e.Current)
+}
+
+// DeadlineExceededError is used when [Verify] stops at the deadline of the
+// context, or within VerifyOptions.DeadlineMargin of it, before a signature
+// is verified successfully. It reports the partial result of the
+// verification, so that the caller decides whether to fail open or closed.
+type DeadlineExceededError struct {
+	// Processed is the number of signatures processed before the deadline,
+	// none of which was verified successfully.
+	Processed int
+
+	// Skipped are the signatures left unprocessed due to the deadline, among
+	// the signature manifests listed before the deadline.
+	Skipped []SkippedSignature
+
+	// InnerError is the error the verification stopped with, wrapping
+	// context.DeadlineExceeded.
+	InnerError error
+}
+
+func (e DeadlineExceededError) Error() string {
+	return fmt.Sprintf("signature verification stopped at the deadline after processing %d signature(s), %d signature(s) skipped", e.Processed, len(e.Skipped))
+}
+
+func (e DeadlineExceededError) Unwrap() error {
+	return e.InnerError
+}
@@ -81,6 +81,21 @@ func TestErrorMessages(t *testing.T) {
 			err:  ErrorUserMetadataVerificationFailed{},
 			want: "unable to find specified metadata in the signature",
 		},
+		{
+			name: "SubjectMismatchError with message",
+			err:  SubjectMismatchError{Msg: "test message"},
+			want: "test message",
+		},
+		{
+			name: "SubjectMismatchError without message",
+			err:  SubjectMismatchError{},
+			want: "signature manifest subject does not match the artifact",
+		},
+		{
+			name: "TagMutatedError",
+			err:  TagMutatedError{Reference: "localhost:5000/net-monitor:v1", Resolved: "sha256:aaa", Current: "sha256:bbb"},
+			want: "tag localhost:5000/net-monitor:v1 has moved from digest sha256:aaa to sha256:bbb",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,143 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// fetchedSignature is a signature envelope blob being fetched.
+type fetchedSignature struct {
+	done chan struct{}
+	blob []byte
+	desc ocispec.Descriptor
+	err  error
+
+	// reserved is the size of the blob added to the working set.
+	reserved int64
+
+	// spilled is true if the blob was dropped to fit in the working set. It
+	// must be fetched again.
+	spilled bool
+}
+
+// wait waits for the signature envelope blob to be fetched.
+func (f *fetchedSignature) wait() ([]byte, ocispec.Descriptor, error) {
+	<-f.done
+	return f.blob, f.desc, f.err
+}
+
+// take returns the size of the blob added to the working set, which is now
+// held by the caller.
+func (f *fetchedSignature) take() int64 {
+	reserved := f.reserved
+	f.reserved = 0
+	return reserved
+}
+
+// spill drops the fetched blob from the working set, and returns false if
+// the blob is not fetched yet or not held in the working set.
+func (f *fetchedSignature) spill(w *workingSet) bool {
+	select {
+	case <-f.done:
+	default:
+		return false
+	}
+	if f.reserved == 0 {
+		return false
+	}
+	w.remove(f.take())
+	f.blob = nil
+	f.spilled = true
+	return true
+}
+
+// prefetchSignatureBlobs starts fetching the signature envelope blobs of the
+// signature manifests, with at most concurrency fetches in flight, so that
+// the blobs are fetched while the earlier signatures are verified.
+//
+// Each fetch is bounded by timeout, if positive. The fetched blobs are added
+// to the working set ws; the blobs not fitting in it are spilled. The fetches
+// are returned in the order of the signature manifests along with a function
+// cancelling the outstanding fetches and removing the blobs not taken from
+// the working set, which must be called once the blobs are no longer needed.
+func prefetchSignatureBlobs(ctx context.Context, repo registry.Repository, signatureManifests []ocispec.Descriptor, concurrency int, timeout time.Duration, ws *workingSet) ([]*fetchedSignature, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	fetches := make([]*fetchedSignature, len(signatureManifests))
+	for i := range fetches {
+		fetches[i] = &fetchedSignature{done: make(chan struct{})}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sem := make(chan struct{}, concurrency)
+		for i, sigManifestDesc := range signatureManifests {
+			fetch := fetches[i]
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				fetch.err = ctx.Err()
+				close(fetch.done)
+				continue
+			}
+			wg.Add(1)
+			go func(sigManifestDesc ocispec.Descriptor) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				fetch.blob, fetch.desc, fetch.err = fetchSignatureBlob(ctx, repo, sigManifestDesc, timeout)
+				if fetch.err == nil && ws != nil {
+					if size := int64(len(fetch.blob)); ws.tryAdd(size) {
+						fetch.reserved = size
+					} else {
+						fetch.blob = nil
+						fetch.spilled = true
+					}
+				}
+				close(fetch.done)
+			}(sigManifestDesc)
+		}
+	}()
+	return fetches, func() {
+		cancel()
+		wg.Wait()
+		for _, fetch := range fetches {
+			ws.remove(fetch.take())
+		}
+	}
+}
+
+// fetchSignatureBlob fetches the signature envelope blob of the signature
+// manifest within timeout, if positive.
+func fetchSignatureBlob(ctx context.Context, repo registry.Repository, sigManifestDesc ocispec.Descriptor, timeout time.Duration) ([]byte, ocispec.Descriptor, error) {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+	return repo.FetchSignatureBlob(ctx, sigManifestDesc)
+}
+
+// withTimeout returns a copy of ctx with timeout, if positive.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
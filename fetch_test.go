@@ -0,0 +1,187 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// slowRepository fetches the signature blobs after a delay, recording the
+// maximum number of concurrent fetches.
+type slowRepository struct {
+	mock.Repository
+	delay time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	fetched     int
+}
+
+func (r *slowRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	r.mu.Lock()
+	r.inFlight++
+	r.fetched++
+	r.maxInFlight = max(r.maxInFlight, r.inFlight)
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.inFlight--
+		r.mu.Unlock()
+	}()
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+		return nil, ocispec.Descriptor{}, ctx.Err()
+	}
+	return []byte(desc.Digest), mock.JwsSigEnvDescriptor, nil
+}
+
+func signatureManifests(n int) []ocispec.Descriptor {
+	manifests := make([]ocispec.Descriptor, n)
+	for i := range manifests {
+		manifests[i] = ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    digest.FromBytes([]byte{byte(i)}),
+		}
+	}
+	return manifests
+}
+
+func TestPrefetchSignatureBlobs(t *testing.T) {
+	repo := &slowRepository{delay: 10 * time.Millisecond}
+	manifests := signatureManifests(8)
+	fetches, stop := prefetchSignatureBlobs(context.Background(), repo, manifests, 3, 0, nil)
+	defer stop()
+	for i, fetch := range fetches {
+		blob, desc, err := fetch.wait()
+		if err != nil {
+			t.Fatalf("fetch %d failed: %v", i, err)
+		}
+		if string(blob) != string(manifests[i].Digest) || desc.MediaType != mock.JwsSigEnvDescriptor.MediaType {
+			t.Fatalf("fetch %d returned the blob of another signature", i)
+		}
+	}
+	if repo.maxInFlight > 3 {
+		t.Fatalf("prefetchSignatureBlobs() fetched %d blobs concurrently, want at most 3", repo.maxInFlight)
+	}
+	if repo.maxInFlight < 2 {
+		t.Fatalf("prefetchSignatureBlobs() did not fetch the blobs concurrently")
+	}
+}
+
+func TestPrefetchSignatureBlobs_Stop(t *testing.T) {
+	repo := &slowRepository{delay: time.Hour}
+	fetches, stop := prefetchSignatureBlobs(context.Background(), repo, signatureManifests(4), 2, 0, nil)
+	stop()
+	for i, fetch := range fetches {
+		if _, _, err := fetch.wait(); !errors.Is(err, context.Canceled) {
+			t.Fatalf("fetch %d error = %v, want context.Canceled", i, err)
+		}
+	}
+}
+
+func TestVerifySignatureFetchConcurrency(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false}
+	repo := &slowRepository{Repository: mock.NewRepository(), delay: 10 * time.Millisecond}
+	repo.ListSignaturesResponse = signatureManifests(10)
+	opts := VerifyOptions{
+		ArtifactReference:         mock.SampleArtifactUri,
+		MaxSignatureAttempts:      6,
+		SignatureFetchConcurrency: 4,
+	}
+	_, outcomes, err := Verify(context.Background(), &verifier, repo, opts)
+	if !errors.As(err, &ErrorVerificationFailed{}) {
+		t.Fatalf("Verify() error = %v, want ErrorVerificationFailed", err)
+	}
+	if len(outcomes) != 0 {
+		t.Fatalf("Verify() returned %d outcomes", len(outcomes))
+	}
+	if repo.fetched != 6 {
+		t.Fatalf("Verify() fetched %d signature blobs, want MaxSignatureAttempts", repo.fetched)
+	}
+	if repo.maxInFlight < 2 || repo.maxInFlight > 4 {
+		t.Fatalf("Verify() fetched %d signature blobs concurrently, want 2 to 4", repo.maxInFlight)
+	}
+
+	// the outstanding fetches are cancelled on success
+	verifier.FailVerify = false
+	repo = &slowRepository{Repository: mock.NewRepository(), delay: 10 * time.Millisecond}
+	repo.ListSignaturesResponse = signatureManifests(10)
+	if _, outcomes, err := Verify(context.Background(), &verifier, repo, opts); err != nil || len(outcomes) != 1 {
+		t.Fatalf("Verify() = %v, %v", outcomes, err)
+	}
+	if repo.inFlight != 0 {
+		t.Fatalf("Verify() left %d fetches in flight", repo.inFlight)
+	}
+}
+
+// deadlineVerifier blocks the verification until the context is done.
+type deadlineVerifier struct {
+	dummyVerifier
+}
+
+func (v *deadlineVerifier) Verify(ctx context.Context, _ ocispec.Descriptor, _ []byte, _ VerifierVerifyOptions) (*VerificationOutcome, error) {
+	<-ctx.Done()
+	outcome := &VerificationOutcome{Error: ctx.Err()}
+	return outcome, outcome.Error
+}
+
+func TestVerifyTimeouts(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}
+	opts := VerifyOptions{
+		ArtifactReference:    mock.SampleArtifactUri,
+		MaxSignatureAttempts: 50,
+	}
+
+	t.Run("fetch timeout", func(t *testing.T) {
+		repo := &slowRepository{Repository: mock.NewRepository(), delay: time.Hour}
+		opts := opts
+		opts.FetchTimeout = 10 * time.Millisecond
+		_, _, err := Verify(context.Background(), &verifier, repo, opts)
+		if !errors.As(err, &ErrorSignatureRetrievalFailed{}) || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+			t.Fatalf("Verify() error = %v, want ErrorSignatureRetrievalFailed on deadline", err)
+		}
+	})
+
+	t.Run("verify timeout", func(t *testing.T) {
+		opts := opts
+		opts.VerifyTimeout = 10 * time.Millisecond
+		_, _, err := Verify(context.Background(), &deadlineVerifier{verifier}, mock.NewRepository(), opts)
+		if !errors.As(err, &ErrorVerificationFailed{}) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Verify() error = %v, want ErrorVerificationFailed on deadline", err)
+		}
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err := Verify(ctx, &verifier, mock.NewRepository(), opts)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Verify() error = %v, want context.Canceled", err)
+		}
+	})
+}
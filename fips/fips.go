@@ -0,0 +1,149 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fips restricts the cryptographic material used by notation to the
+// FIPS-approved algorithms.
+//
+// The FIPS mode is enabled when notation-go is built with the fips build tag,
+// or when the FIPS 140-3 mode of the Go cryptographic module is enabled at
+// runtime, e.g. with GODEBUG=fips140=on. In FIPS mode, signing and
+// verification fail closed when a key, a certificate or a signature algorithm
+// is not approved.
+package fips
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/notaryproject/notation-core-go/signature"
+)
+
+// Modes reported by [Mode].
+const (
+	// ModeFIPS is the mode where only FIPS-approved algorithms are allowed.
+	ModeFIPS = "fips"
+
+	// ModeDefault is the mode where all the algorithms supported by notation
+	// are allowed.
+	ModeDefault = "default"
+)
+
+// minRSAKeySize is the minimum size in bits of approved RSA keys.
+const minRSAKeySize = 2048
+
+// NotApprovedError is used when a key, a certificate or a signature algorithm
+// is not FIPS-approved.
+type NotApprovedError struct {
+	Msg string
+}
+
+func (e NotApprovedError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return "cryptographic material is not FIPS-approved"
+}
+
+// Enabled reports whether the FIPS mode is enabled.
+func Enabled() bool {
+	return buildTagEnabled || runtimeEnabled()
+}
+
+// Mode returns the active mode, [ModeFIPS] or [ModeDefault].
+func Mode() string {
+	if Enabled() {
+		return ModeFIPS
+	}
+	return ModeDefault
+}
+
+// ValidateKeySpec returns [NotApprovedError] if the key spec is not
+// FIPS-approved.
+func ValidateKeySpec(ks signature.KeySpec) error {
+	switch ks.Type {
+	case signature.KeyTypeRSA:
+		if ks.Size < minRSAKeySize {
+			return NotApprovedError{Msg: fmt.Sprintf("RSA key size %d is not FIPS-approved", ks.Size)}
+		}
+	case signature.KeyTypeEC:
+		switch ks.Size {
+		case 256, 384, 521:
+		default:
+			return NotApprovedError{Msg: fmt.Sprintf("EC key size %d is not FIPS-approved", ks.Size)}
+		}
+	default:
+		return NotApprovedError{Msg: fmt.Sprintf("key type %v is not FIPS-approved", ks.Type)}
+	}
+	return nil
+}
+
+// ValidateSignatureAlgorithm returns [NotApprovedError] if the signature
+// algorithm is not FIPS-approved.
+func ValidateSignatureAlgorithm(alg signature.Algorithm) error {
+	switch alg {
+	case signature.AlgorithmPS256, signature.AlgorithmPS384, signature.AlgorithmPS512,
+		signature.AlgorithmES256, signature.AlgorithmES384, signature.AlgorithmES512:
+		return nil
+	}
+	return NotApprovedError{Msg: fmt.Sprintf("signature algorithm %d is not FIPS-approved", alg)}
+}
+
+// ValidateCertificate returns [NotApprovedError] if the public key or the
+// signature algorithm of the certificate is not FIPS-approved.
+func ValidateCertificate(cert *x509.Certificate) error {
+	switch cert.SignatureAlgorithm {
+	case x509.SHA256WithRSA, x509.SHA384WithRSA, x509.SHA512WithRSA,
+		x509.SHA256WithRSAPSS, x509.SHA384WithRSAPSS, x509.SHA512WithRSAPSS,
+		x509.ECDSAWithSHA256, x509.ECDSAWithSHA384, x509.ECDSAWithSHA512:
+	default:
+		return NotApprovedError{Msg: fmt.Sprintf("certificate %q: signature algorithm %v is not FIPS-approved", cert.Subject, cert.SignatureAlgorithm)}
+	}
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if size := key.N.BitLen(); size < minRSAKeySize {
+			return NotApprovedError{Msg: fmt.Sprintf("certificate %q: RSA key size %d is not FIPS-approved", cert.Subject, size)}
+		}
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return NotApprovedError{Msg: fmt.Sprintf("certificate %q: elliptic curve %s is not FIPS-approved", cert.Subject, key.Curve.Params().Name)}
+		}
+	default:
+		return NotApprovedError{Msg: fmt.Sprintf("certificate %q: public key algorithm %v is not FIPS-approved", cert.Subject, cert.PublicKeyAlgorithm)}
+	}
+	return nil
+}
+
+// ValidateCertificateChain returns [NotApprovedError] if any certificate of
+// the chain is not FIPS-approved.
+func ValidateCertificateChain(certChain []*x509.Certificate) error {
+	for _, cert := range certChain {
+		if err := ValidateCertificate(cert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateSignerInfo returns [NotApprovedError] if the signature algorithm or
+// the certificate chain of the signer info is not FIPS-approved.
+func ValidateSignerInfo(signerInfo *signature.SignerInfo) error {
+	if err := ValidateSignatureAlgorithm(signerInfo.SignatureAlgorithm); err != nil {
+		return err
+	}
+	return ValidateCertificateChain(signerInfo.CertificateChain)
+}
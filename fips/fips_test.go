@@ -0,0 +1,164 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fips
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/testhelper"
+)
+
+func TestMode(t *testing.T) {
+	want := ModeDefault
+	if buildTagEnabled || runtimeEnabled() {
+		want = ModeFIPS
+	}
+	if got := Mode(); got != want {
+		t.Fatalf("Mode() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateKeySpec(t *testing.T) {
+	tests := []struct {
+		ks      signature.KeySpec
+		wantErr bool
+	}{
+		{ks: signature.KeySpec{Type: signature.KeyTypeRSA, Size: 2048}},
+		{ks: signature.KeySpec{Type: signature.KeyTypeRSA, Size: 3072}},
+		{ks: signature.KeySpec{Type: signature.KeyTypeRSA, Size: 4096}},
+		{ks: signature.KeySpec{Type: signature.KeyTypeEC, Size: 256}},
+		{ks: signature.KeySpec{Type: signature.KeyTypeEC, Size: 384}},
+		{ks: signature.KeySpec{Type: signature.KeyTypeEC, Size: 521}},
+		{ks: signature.KeySpec{Type: signature.KeyTypeRSA, Size: 1024}, wantErr: true},
+		{ks: signature.KeySpec{Type: signature.KeyTypeEC, Size: 224}, wantErr: true},
+		{ks: signature.KeySpec{Type: 42, Size: 256}, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := ValidateKeySpec(tt.ks)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateKeySpec(%+v) error = %v, wantErr %v", tt.ks, err, tt.wantErr)
+		}
+		var notApproved NotApprovedError
+		if err != nil && !errors.As(err, &notApproved) {
+			t.Errorf("ValidateKeySpec(%+v) error = %T, want NotApprovedError", tt.ks, err)
+		}
+	}
+}
+
+func TestValidateSignatureAlgorithm(t *testing.T) {
+	for _, alg := range []signature.Algorithm{
+		signature.AlgorithmPS256, signature.AlgorithmPS384, signature.AlgorithmPS512,
+		signature.AlgorithmES256, signature.AlgorithmES384, signature.AlgorithmES512,
+	} {
+		if err := ValidateSignatureAlgorithm(alg); err != nil {
+			t.Errorf("ValidateSignatureAlgorithm(%d) error = %v", alg, err)
+		}
+	}
+	if err := ValidateSignatureAlgorithm(0); err == nil {
+		t.Error("ValidateSignatureAlgorithm(0) expected error")
+	}
+}
+
+func TestValidateCertificate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := testhelper.GetRSALeafCertificate().Cert
+	root := testhelper.GetRSARootCertificate().Cert
+	ecLeaf := testhelper.GetECLeafCertificate().Cert
+
+	tests := []struct {
+		name    string
+		cert    *x509.Certificate
+		wantErr bool
+	}{
+		{name: "RSA leaf", cert: leaf},
+		{name: "RSA root", cert: root},
+		{name: "EC leaf", cert: ecLeaf},
+		{
+			name:    "SHA-1 signature",
+			cert:    &x509.Certificate{SignatureAlgorithm: x509.SHA1WithRSA, PublicKey: leaf.PublicKey},
+			wantErr: true,
+		},
+		{
+			name:    "Ed25519 signature",
+			cert:    &x509.Certificate{SignatureAlgorithm: x509.PureEd25519, PublicKey: edKey},
+			wantErr: true,
+		},
+		{
+			name:    "weak RSA key",
+			cert:    &x509.Certificate{SignatureAlgorithm: x509.SHA256WithRSA, PublicKey: &rsaKey.PublicKey},
+			wantErr: true,
+		},
+		{
+			name:    "P-224 key",
+			cert:    &x509.Certificate{SignatureAlgorithm: x509.ECDSAWithSHA256, PublicKey: &p224Key.PublicKey},
+			wantErr: true,
+		},
+		{
+			name:    "Ed25519 key",
+			cert:    &x509.Certificate{SignatureAlgorithm: x509.SHA256WithRSA, PublicKey: edKey},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCertificate(tt.cert)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := ValidateCertificateChain([]*x509.Certificate{leaf, root}); err != nil {
+		t.Fatalf("ValidateCertificateChain() error = %v", err)
+	}
+	weak := &x509.Certificate{SignatureAlgorithm: x509.SHA1WithRSA, PublicKey: leaf.PublicKey}
+	if err := ValidateCertificateChain([]*x509.Certificate{leaf, weak}); err == nil {
+		t.Fatal("ValidateCertificateChain() expected error")
+	}
+}
+
+func TestValidateSignerInfo(t *testing.T) {
+	leaf := testhelper.GetRSALeafCertificate().Cert
+	root := testhelper.GetRSARootCertificate().Cert
+	signerInfo := &signature.SignerInfo{
+		SignatureAlgorithm: signature.AlgorithmPS384,
+		CertificateChain:   []*x509.Certificate{leaf, root},
+	}
+	if err := ValidateSignerInfo(signerInfo); err != nil {
+		t.Fatalf("ValidateSignerInfo() error = %v", err)
+	}
+	signerInfo.SignatureAlgorithm = 0
+	if err := ValidateSignerInfo(signerInfo); err == nil {
+		t.Fatal("ValidateSignerInfo() expected error")
+	}
+}
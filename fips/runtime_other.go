@@ -0,0 +1,23 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !go1.24
+// +build !go1.24
+
+package fips
+
+// runtimeEnabled returns false as the FIPS 140-3 mode of the Go cryptographic
+// module is only available since Go 1.24.
+func runtimeEnabled() bool {
+	return false
+}
@@ -0,0 +1,66 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+)
+
+// transportWarner is implemented by a repository reporting the insecure
+// transport used to access the registry, e.g. plain HTTP.
+type transportWarner interface {
+	TransportWarnings() []string
+}
+
+// transportWarnings returns the warnings of the insecure transport used to
+// access repo, if any.
+func transportWarnings(repo registry.Repository) []string {
+	if warner, ok := repo.(transportWarner); ok {
+		return warner.TransportWarnings()
+	}
+	return nil
+}
+
+// logTransportWarnings logs the warnings of the insecure transport used to
+// access repo.
+func logTransportWarnings(ctx context.Context, repo registry.Repository) []string {
+	warnings := transportWarnings(repo)
+	logger := log.GetLogger(ctx)
+	for _, warning := range warnings {
+		logger.Warn(warning)
+	}
+	return warnings
+}
+
+// withTransportWarnings logs the warnings of the insecure transport used to
+// access repo, and reports them in the verification outcomes. The outcomes
+// are copied, as they may be shared with the verification cache.
+func withTransportWarnings(ctx context.Context, repo registry.Repository, outcomes []*VerificationOutcome) []*VerificationOutcome {
+	warnings := logTransportWarnings(ctx, repo)
+	if len(warnings) == 0 {
+		return outcomes
+	}
+	for i, outcome := range outcomes {
+		if outcome == nil {
+			continue
+		}
+		outcomeCopy := *outcome
+		outcomeCopy.Warnings = append(append([]string(nil), outcome.Warnings...), warnings...)
+		outcomes[i] = &outcomeCopy
+	}
+	return outcomes
+}
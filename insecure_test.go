@@ -0,0 +1,77 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type insecureRepository struct {
+	mock.Repository
+	warnings []string
+}
+
+func (r insecureRepository) TransportWarnings() []string {
+	return r.warnings
+}
+
+// sharedOutcomeVerifier returns the same outcome for every verification,
+// as a caching verifier does.
+type sharedOutcomeVerifier struct {
+	dummyVerifier
+	outcome *VerificationOutcome
+}
+
+func (v *sharedOutcomeVerifier) Verify(_ context.Context, _ ocispec.Descriptor, _ []byte, _ VerifierVerifyOptions) (*VerificationOutcome, error) {
+	return v.outcome, nil
+}
+
+func TestVerifyTransportWarnings(t *testing.T) {
+	warnings := []string{`registry "localhost:5000" is accessed with plain HTTP`}
+	repo := insecureRepository{Repository: mock.NewRepository(), warnings: warnings}
+	policyDocument := dummyPolicyDocument()
+	verifier := sharedOutcomeVerifier{
+		dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false},
+		outcome:       &VerificationOutcome{VerificationLevel: trustpolicy.LevelStrict},
+	}
+	opts := VerifyOptions{ArtifactReference: mock.SampleArtifactUri, MaxSignatureAttempts: 50}
+
+	for i := 0; i < 2; i++ {
+		_, outcomes, err := Verify(context.Background(), &verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if len(outcomes) != 1 || !reflect.DeepEqual(outcomes[0].Warnings, warnings) {
+			t.Fatalf("Verify() outcomes = %+v, want warnings %v", outcomes, warnings)
+		}
+	}
+	if verifier.outcome.Warnings != nil {
+		t.Fatalf("Verify() modified the outcome of the verifier: %v", verifier.outcome.Warnings)
+	}
+
+	// no warning
+	_, outcomes, err := Verify(context.Background(), &verifier, mock.NewRepository(), opts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(outcomes) != 1 || outcomes[0].Warnings != nil {
+		t.Fatalf("Verify() outcomes = %+v, want no warning", outcomes)
+	}
+}
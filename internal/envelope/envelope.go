@@ -33,14 +33,16 @@ type Payload struct {
 	TargetArtifact ocispec.Descriptor `json:"targetArtifact"`
 }
 
-// ValidatePayloadContentType validates signature payload's content type.
+// ValidatePayloadContentType validates signature payload's content type
+// against [MediaTypePayloadV1] and the registered payload types.
 func ValidatePayloadContentType(payload *signature.Payload) error {
-	switch payload.ContentType {
-	case MediaTypePayloadV1:
-		return nil
-	default:
+	payloadParsersMu.RLock()
+	_, ok := payloadParsers[payload.ContentType]
+	payloadParsersMu.RUnlock()
+	if !ok {
 		return fmt.Errorf("payload content type %q not supported", payload.ContentType)
 	}
+	return nil
 }
 
 // SanitizeTargetArtifact filters out unrelated ocispec.Descriptor fields based
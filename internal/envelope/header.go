@@ -0,0 +1,138 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ProtectedHeader is a custom protected header of COSE envelopes.
+type ProtectedHeader struct {
+	// Label is the text label of the header.
+	Label string
+
+	// Critical marks the header as critical.
+	Critical bool
+
+	// Generate returns the value of the header for the artifact desc. The
+	// header is not added if Generate returns nil.
+	Generate func(ctx context.Context, desc ocispec.Descriptor) (any, error)
+
+	// Validate validates the value of the header of a signature of the
+	// artifact desc.
+	Validate func(ctx context.Context, value any, desc ocispec.Descriptor) error
+}
+
+var (
+	protectedHeadersMu sync.RWMutex
+	protectedHeaders   = map[string]ProtectedHeader{}
+)
+
+// RegisterProtectedHeader registers the custom protected header of COSE
+// envelopes. The labels cannot be registered twice.
+func RegisterProtectedHeader(header ProtectedHeader) error {
+	if header.Label == "" {
+		return errors.New("protected header label cannot be empty")
+	}
+	if strings.HasPrefix(header.Label, reservedHeaderPrefix) {
+		return fmt.Errorf("protected header label %q is reserved", header.Label)
+	}
+	if header.Generate == nil || header.Validate == nil {
+		return fmt.Errorf("protected header %q must have both Generate and Validate functions", header.Label)
+	}
+	protectedHeadersMu.Lock()
+	defer protectedHeadersMu.Unlock()
+	if _, ok := protectedHeaders[header.Label]; ok {
+		return fmt.Errorf("protected header %q is already registered", header.Label)
+	}
+	protectedHeaders[header.Label] = header
+	return nil
+}
+
+// IsRegisteredProtectedHeader returns true if label is the label of a
+// registered custom protected header.
+func IsRegisteredProtectedHeader(label any) bool {
+	name, ok := label.(string)
+	if !ok {
+		return false
+	}
+	protectedHeadersMu.RLock()
+	defer protectedHeadersMu.RUnlock()
+	_, ok = protectedHeaders[name]
+	return ok
+}
+
+// GenerateProtectedHeaders returns the registered custom protected headers
+// of the signature of the artifact desc as extended signed attributes,
+// ordered by label.
+func GenerateProtectedHeaders(ctx context.Context, desc ocispec.Descriptor) ([]signature.Attribute, error) {
+	protectedHeadersMu.RLock()
+	headers := make([]ProtectedHeader, 0, len(protectedHeaders))
+	for _, header := range protectedHeaders {
+		headers = append(headers, header)
+	}
+	protectedHeadersMu.RUnlock()
+	sort.Slice(headers, func(i, j int) bool {
+		return headers[i].Label < headers[j].Label
+	})
+
+	var attrs []signature.Attribute
+	for _, header := range headers {
+		value, err := header.Generate(ctx, desc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate protected header %q: %w", header.Label, err)
+		}
+		if value == nil {
+			continue
+		}
+		attrs = append(attrs, signature.Attribute{
+			Key:      header.Label,
+			Critical: header.Critical,
+			Value:    value,
+		})
+	}
+	return attrs, nil
+}
+
+// ValidateProtectedHeaders validates the registered custom protected headers
+// in the extended signed attributes of a signature of the artifact desc.
+func ValidateProtectedHeaders(ctx context.Context, signerInfo *signature.SignerInfo, desc ocispec.Descriptor) error {
+	for _, attr := range signerInfo.SignedAttributes.ExtendedAttributes {
+		label, ok := attr.Key.(string)
+		if !ok {
+			continue
+		}
+		protectedHeadersMu.RLock()
+		header, ok := protectedHeaders[label]
+		protectedHeadersMu.RUnlock()
+		if !ok {
+			continue
+		}
+		if attr.Critical != header.Critical {
+			return fmt.Errorf("protected header %q has critical %t, but is registered with critical %t", label, attr.Critical, header.Critical)
+		}
+		if err := header.Validate(ctx, attr.Value, desc); err != nil {
+			return fmt.Errorf("protected header %q is invalid: %w", label, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func resetProtectedHeaders(t *testing.T) {
+	t.Cleanup(func() {
+		protectedHeadersMu.Lock()
+		defer protectedHeadersMu.Unlock()
+		protectedHeaders = map[string]ProtectedHeader{}
+	})
+}
+
+func TestRegisterProtectedHeader(t *testing.T) {
+	resetProtectedHeaders(t)
+	generate := func(context.Context, ocispec.Descriptor) (any, error) { return "value", nil }
+	validate := func(context.Context, any, ocispec.Descriptor) error { return nil }
+
+	for _, header := range []ProtectedHeader{
+		{Label: "", Generate: generate, Validate: validate},
+		{Label: "io.cncf.notary.custom", Generate: generate, Validate: validate},
+		{Label: "com.example.custom", Validate: validate},
+		{Label: "com.example.custom", Generate: generate},
+	} {
+		if err := RegisterProtectedHeader(header); err == nil {
+			t.Fatalf("RegisterProtectedHeader(%q) expects error", header.Label)
+		}
+	}
+	header := ProtectedHeader{Label: "com.example.custom", Generate: generate, Validate: validate}
+	if err := RegisterProtectedHeader(header); err != nil {
+		t.Fatalf("RegisterProtectedHeader() error = %v", err)
+	}
+	if err := RegisterProtectedHeader(header); err == nil {
+		t.Fatal("RegisterProtectedHeader() expects error for a registered label")
+	}
+	if !IsRegisteredProtectedHeader("com.example.custom") || IsRegisteredProtectedHeader(int64(1)) {
+		t.Fatal("IsRegisteredProtectedHeader() mismatch")
+	}
+}
+
+func TestGenerateAndValidateProtectedHeaders(t *testing.T) {
+	resetProtectedHeaders(t)
+	desc := ocispec.Descriptor{MediaType: "application/vnd.test"}
+	errInvalid := errors.New("invalid")
+	headers := []ProtectedHeader{
+		{
+			Label:    "com.example.b",
+			Critical: true,
+			Generate: func(context.Context, ocispec.Descriptor) (any, error) { return "b", nil },
+			Validate: func(_ context.Context, value any, _ ocispec.Descriptor) error {
+				if value != "b" {
+					return errInvalid
+				}
+				return nil
+			},
+		},
+		{
+			Label:    "com.example.a",
+			Generate: func(context.Context, ocispec.Descriptor) (any, error) { return []byte("a"), nil },
+			Validate: func(context.Context, any, ocispec.Descriptor) error { return nil },
+		},
+		{
+			Label:    "com.example.skipped",
+			Generate: func(context.Context, ocispec.Descriptor) (any, error) { return nil, nil },
+			Validate: func(context.Context, any, ocispec.Descriptor) error { return errInvalid },
+		},
+	}
+	for _, header := range headers {
+		if err := RegisterProtectedHeader(header); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	attrs, err := GenerateProtectedHeaders(context.Background(), desc)
+	if err != nil {
+		t.Fatalf("GenerateProtectedHeaders() error = %v", err)
+	}
+	if len(attrs) != 2 || attrs[0].Key != "com.example.a" || attrs[1].Key != "com.example.b" || !attrs[1].Critical {
+		t.Fatalf("GenerateProtectedHeaders() = %+v", attrs)
+	}
+
+	signerInfo := &signature.SignerInfo{}
+	signerInfo.SignedAttributes.ExtendedAttributes = append(attrs, signature.Attribute{Key: int64(1), Value: "ignored"})
+	if err := ValidateProtectedHeaders(context.Background(), signerInfo, desc); err != nil {
+		t.Fatalf("ValidateProtectedHeaders() error = %v", err)
+	}
+
+	// invalid value
+	signerInfo.SignedAttributes.ExtendedAttributes[1].Value = "c"
+	if err := ValidateProtectedHeaders(context.Background(), signerInfo, desc); !errors.Is(err, errInvalid) {
+		t.Fatalf("ValidateProtectedHeaders() error = %v, want %v", err, errInvalid)
+	}
+
+	// critical mismatch
+	signerInfo.SignedAttributes.ExtendedAttributes[1] = signature.Attribute{Key: "com.example.b", Value: "b"}
+	if err := ValidateProtectedHeaders(context.Background(), signerInfo, desc); err == nil {
+		t.Fatal("ValidateProtectedHeaders() expects error for a non-critical header registered as critical")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// PayloadParser parses the content of a signature payload and returns the
+// descriptor of the target artifact.
+type PayloadParser func(content []byte) (ocispec.Descriptor, error)
+
+var (
+	payloadParsersMu sync.RWMutex
+	payloadParsers   = map[string]PayloadParser{
+		MediaTypePayloadV1: parsePayloadV1,
+	}
+)
+
+// RegisterPayloadType registers the parser of the signature payloads of
+// content type contentType. The content types cannot be registered twice.
+func RegisterPayloadType(contentType string, parser PayloadParser) error {
+	if contentType == "" {
+		return errors.New("payload content type cannot be empty")
+	}
+	if parser == nil {
+		return fmt.Errorf("payload parser of content type %q cannot be nil", contentType)
+	}
+	payloadParsersMu.Lock()
+	defer payloadParsersMu.Unlock()
+	if _, ok := payloadParsers[contentType]; ok {
+		return fmt.Errorf("payload content type %q is already registered", contentType)
+	}
+	payloadParsers[contentType] = parser
+	return nil
+}
+
+// ParsePayload validates the signature payload against the schema of its
+// content type and returns the descriptor of the target artifact.
+func ParsePayload(payload *signature.Payload) (ocispec.Descriptor, error) {
+	payloadParsersMu.RLock()
+	parser, ok := payloadParsers[payload.ContentType]
+	payloadParsersMu.RUnlock()
+	if !ok {
+		return ocispec.Descriptor{}, fmt.Errorf("payload content type %q not supported", payload.ContentType)
+	}
+	desc, err := parser(payload.Content)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("invalid payload of content type %q: %w", payload.ContentType, err)
+	}
+	return desc, nil
+}
+
+// parsePayloadV1 parses the payload of content type [MediaTypePayloadV1].
+func parsePayloadV1(content []byte) (ocispec.Descriptor, error) {
+	var payload struct {
+		TargetArtifact *ocispec.Descriptor `json:"targetArtifact"`
+	}
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if payload.TargetArtifact == nil {
+		return ocispec.Descriptor{}, errors.New("targetArtifact is missing")
+	}
+	desc := *payload.TargetArtifact
+	if desc.MediaType == "" {
+		return ocispec.Descriptor{}, errors.New("targetArtifact.mediaType is missing")
+	}
+	if err := desc.Digest.Validate(); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("targetArtifact.digest is invalid: %w", err)
+	}
+	if desc.Size < 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("targetArtifact.size must not be negative, but got %d", desc.Size)
+	}
+	return desc, nil
+}
@@ -0,0 +1,119 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestParsePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: `{"targetArtifact":{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855","size":0}}`,
+		},
+		{
+			name:    "not json",
+			content: `payload`,
+			wantErr: "invalid payload",
+		},
+		{
+			name:    "missing target artifact",
+			content: `{}`,
+			wantErr: "targetArtifact is missing",
+		},
+		{
+			name:    "missing media type",
+			content: `{"targetArtifact":{"digest":"sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855","size":0}}`,
+			wantErr: "targetArtifact.mediaType is missing",
+		},
+		{
+			name:    "invalid digest",
+			content: `{"targetArtifact":{"mediaType":"application/octet-stream","digest":"sha256:abc","size":0}}`,
+			wantErr: "targetArtifact.digest is invalid",
+		},
+		{
+			name:    "negative size",
+			content: `{"targetArtifact":{"mediaType":"application/octet-stream","digest":"sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855","size":-1}}`,
+			wantErr: "targetArtifact.size must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, err := ParsePayload(&signature.Payload{ContentType: MediaTypePayloadV1, Content: []byte(tt.content)})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParsePayload() error = %v", err)
+				}
+				if desc.MediaType != "application/vnd.oci.image.manifest.v1+json" {
+					t.Fatalf("ParsePayload() = %+v", desc)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParsePayload() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := ParsePayload(&signature.Payload{ContentType: "application/unknown", Content: []byte("{}")}); err == nil {
+		t.Fatal("ParsePayload() of an unknown content type expected error")
+	}
+}
+
+func TestRegisterPayloadType(t *testing.T) {
+	const contentType = "application/vnd.example.envelope-test+json"
+	parserErr := errors.New("parser error")
+	parser := func(content []byte) (ocispec.Descriptor, error) {
+		if string(content) != "firmware" {
+			return ocispec.Descriptor{}, parserErr
+		}
+		return ocispec.Descriptor{MediaType: "application/vnd.example.firmware"}, nil
+	}
+
+	if err := RegisterPayloadType("", parser); err == nil {
+		t.Fatal("RegisterPayloadType() with empty content type expected error")
+	}
+	if err := RegisterPayloadType(contentType, nil); err == nil {
+		t.Fatal("RegisterPayloadType() with nil parser expected error")
+	}
+	if err := RegisterPayloadType(MediaTypePayloadV1, parser); err == nil {
+		t.Fatal("RegisterPayloadType() of MediaTypePayloadV1 expected error")
+	}
+	if err := ValidatePayloadContentType(&signature.Payload{ContentType: contentType}); err == nil {
+		t.Fatal("ValidatePayloadContentType() of an unregistered content type expected error")
+	}
+	if err := RegisterPayloadType(contentType, parser); err != nil {
+		t.Fatalf("RegisterPayloadType() error = %v", err)
+	}
+	if err := ValidatePayloadContentType(&signature.Payload{ContentType: contentType}); err != nil {
+		t.Fatalf("ValidatePayloadContentType() error = %v", err)
+	}
+	desc, err := ParsePayload(&signature.Payload{ContentType: contentType, Content: []byte("firmware")})
+	if err != nil || desc.MediaType != "application/vnd.example.firmware" {
+		t.Fatalf("ParsePayload() = %+v, %v", desc, err)
+	}
+	if _, err := ParsePayload(&signature.Payload{ContentType: contentType, Content: []byte("other")}); !errors.Is(err, parserErr) {
+		t.Fatalf("ParsePayload() error = %v, want %v", err, parserErr)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	gcose "github.com/veraison/go-cose"
+)
+
+// reservedHeaderPrefix is the prefix of the header names reserved by the
+// Notary Project signature specification.
+const reservedHeaderPrefix = "io.cncf.notary."
+
+// reservedJWSHeaders are the header parameters registered by RFC 7515, which
+// cannot be set as unprotected headers.
+var reservedJWSHeaders = map[string]bool{
+	"alg":      true,
+	"jku":      true,
+	"jwk":      true,
+	"kid":      true,
+	"x5u":      true,
+	"x5c":      true,
+	"x5t":      true,
+	"x5t#S256": true,
+	"typ":      true,
+	"cty":      true,
+	"crit":     true,
+}
+
+// ValidateUnprotectedHeaderName validates that name can be set as an
+// unprotected header of the JWS and the COSE envelopes.
+func ValidateUnprotectedHeaderName(name string) error {
+	if name == "" {
+		return errors.New("unprotected header name cannot be empty")
+	}
+	if strings.HasPrefix(name, reservedHeaderPrefix) || reservedJWSHeaders[name] {
+		return fmt.Errorf("unprotected header name %q is reserved", name)
+	}
+	return nil
+}
+
+// SetUnprotectedHeaders sets the headers in the unprotected header of the
+// signature envelope sig of type envelopeMediaType, and returns the
+// encoded envelope. The protected header, the payload and the signature of
+// the envelope are preserved as is.
+func SetUnprotectedHeaders(envelopeMediaType string, sig []byte, headers map[string][]byte) ([]byte, error) {
+	for name := range headers {
+		if err := ValidateUnprotectedHeaderName(name); err != nil {
+			return nil, err
+		}
+	}
+	switch envelopeMediaType {
+	case jws.MediaTypeEnvelope:
+		return setJWSUnprotectedHeaders(sig, headers)
+	case cose.MediaTypeEnvelope:
+		return setCOSEUnprotectedHeaders(sig, headers)
+	default:
+		return nil, fmt.Errorf("envelope media type %q not supported", envelopeMediaType)
+	}
+}
+
+// UnprotectedHeaders returns the unprotected headers of the signature
+// envelope sig of type envelopeMediaType that can be set with
+// [SetUnprotectedHeaders]. The headers defined by the signature
+// specification, and the headers not encoded as bytes, are not returned.
+func UnprotectedHeaders(envelopeMediaType string, sig []byte) (map[string][]byte, error) {
+	switch envelopeMediaType {
+	case jws.MediaTypeEnvelope:
+		return jwsUnprotectedHeaders(sig)
+	case cose.MediaTypeEnvelope:
+		return coseUnprotectedHeaders(sig)
+	default:
+		return nil, fmt.Errorf("envelope media type %q not supported", envelopeMediaType)
+	}
+}
+
+// DetectMediaType returns the envelope media type of the signature envelope
+// sig from its encoding: a JWS envelope is a JSON object, and a COSE envelope
+// is a tagged COSE_Sign1 message.
+func DetectMediaType(sig []byte) (string, error) {
+	trimmed := bytes.TrimLeft(sig, " \t\r\n")
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		return jws.MediaTypeEnvelope, nil
+	case len(sig) > 0 && sig[0] == 0xd2: // CBOR tag 18, COSE_Sign1_Tagged
+		return cose.MediaTypeEnvelope, nil
+	default:
+		return "", errors.New("unknown signature envelope encoding")
+	}
+}
+
+// setJWSUnprotectedHeaders sets the headers in the "header" member of the
+// JWS JSON serialization sig. The values are base64 encoded.
+func setJWSUnprotectedHeaders(sig []byte, headers map[string][]byte) ([]byte, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(sig, &members); err != nil {
+		return nil, fmt.Errorf("malformed jws envelope: %w", err)
+	}
+	header := make(map[string]json.RawMessage)
+	if raw, ok := members["header"]; ok {
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return nil, fmt.Errorf("malformed jws unprotected header: %w", err)
+		}
+	}
+	for name, value := range headers {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		header[name] = encoded
+	}
+	encodedHeader, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	members["header"] = encodedHeader
+	return json.Marshal(members)
+}
+
+// jwsUnprotectedHeaders returns the base64 encoded headers of the "header"
+// member of the JWS JSON serialization sig.
+func jwsUnprotectedHeaders(sig []byte) (map[string][]byte, error) {
+	var envelope struct {
+		Header map[string]json.RawMessage `json:"header"`
+	}
+	if err := json.Unmarshal(sig, &envelope); err != nil {
+		return nil, fmt.Errorf("malformed jws envelope: %w", err)
+	}
+	headers := make(map[string][]byte)
+	for name, raw := range envelope.Header {
+		if ValidateUnprotectedHeaderName(name) != nil {
+			continue
+		}
+		var value []byte
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue
+		}
+		headers[name] = value
+	}
+	return headers, nil
+}
+
+// setCOSEUnprotectedHeaders sets the headers in the unprotected header of the
+// COSE_Sign1 message sig. The values are encoded as byte strings.
+func setCOSEUnprotectedHeaders(sig []byte, headers map[string][]byte) ([]byte, error) {
+	var msg gcose.Sign1Message
+	if err := msg.UnmarshalCBOR(sig); err != nil {
+		return nil, fmt.Errorf("malformed cose envelope: %w", err)
+	}
+	if msg.Headers.Unprotected == nil {
+		msg.Headers.Unprotected = make(gcose.UnprotectedHeader)
+	}
+	for name, value := range headers {
+		msg.Headers.Unprotected[name] = value
+	}
+	// the unprotected header is encoded from the updated map, while the
+	// protected header is kept in its signed encoding
+	msg.Headers.RawUnprotected = nil
+	return msg.MarshalCBOR()
+}
+
+// coseUnprotectedHeaders returns the byte string headers with text labels of
+// the unprotected header of the COSE_Sign1 message sig.
+func coseUnprotectedHeaders(sig []byte) (map[string][]byte, error) {
+	var msg gcose.Sign1Message
+	if err := msg.UnmarshalCBOR(sig); err != nil {
+		return nil, fmt.Errorf("malformed cose envelope: %w", err)
+	}
+	headers := make(map[string][]byte)
+	for label, value := range msg.Headers.Unprotected {
+		name, ok := label.(string)
+		if !ok || ValidateUnprotectedHeaderName(name) != nil {
+			continue
+		}
+		if value, ok := value.([]byte); ok {
+			headers[name] = value
+		}
+	}
+	return headers, nil
+}
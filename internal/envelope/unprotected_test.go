@@ -0,0 +1,60 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+)
+
+func TestSetUnprotectedHeaders(t *testing.T) {
+	jwsEnvelope := []byte(`{"payload":"e30","protected":"e30","header":{"x5c":["YQ=="],"io.cncf.notary.signingAgent":"agent","com.example.count":1},"signature":"c2ln"}`)
+	headers := map[string][]byte{"com.example.hint": []byte("hint")}
+	for mediaType, sig := range map[string][]byte{
+		jws.MediaTypeEnvelope:  jwsEnvelope,
+		cose.MediaTypeEnvelope: validCoseSignatureEnvelope,
+	} {
+		updated, err := SetUnprotectedHeaders(mediaType, sig, headers)
+		if err != nil {
+			t.Fatalf("SetUnprotectedHeaders(%s) error = %v", mediaType, err)
+		}
+		got, err := UnprotectedHeaders(mediaType, updated)
+		if err != nil {
+			t.Fatalf("UnprotectedHeaders(%s) error = %v", mediaType, err)
+		}
+		if !reflect.DeepEqual(got, headers) {
+			t.Fatalf("UnprotectedHeaders(%s) = %v, want %v", mediaType, got, headers)
+		}
+		detected, err := DetectMediaType(updated)
+		if err != nil || detected != mediaType {
+			t.Fatalf("DetectMediaType() = %q, %v, want %q", detected, err, mediaType)
+		}
+	}
+
+	if _, err := SetUnprotectedHeaders(jws.MediaTypeEnvelope, jwsEnvelope, map[string][]byte{"io.cncf.notary.expiry": nil}); err == nil {
+		t.Fatal("SetUnprotectedHeaders() expects error for a reserved header")
+	}
+	if _, err := SetUnprotectedHeaders(invalidMediaType, jwsEnvelope, headers); err == nil {
+		t.Fatal("SetUnprotectedHeaders() expects error for an invalid media type")
+	}
+	if _, err := SetUnprotectedHeaders(cose.MediaTypeEnvelope, jwsEnvelope, headers); err == nil {
+		t.Fatal("SetUnprotectedHeaders() expects error for a malformed envelope")
+	}
+	if _, err := DetectMediaType([]byte("signature")); err == nil {
+		t.Fatal("DetectMediaType() expects error for an unknown encoding")
+	}
+}
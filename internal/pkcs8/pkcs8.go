@@ -0,0 +1,197 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pkcs8 decrypts the encrypted PKCS #8 private keys of RFC 5958,
+// encrypted with PBES2 of RFC 8018 using PBKDF2 and AES-CBC.
+package pkcs8
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// PEMBlockType is the PEM block type of the encrypted PKCS #8 private keys.
+const PEMBlockType = "ENCRYPTED PRIVATE KEY"
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// maxIterationCount is the maximum PBKDF2 iteration count accepted, which
+// bounds the cost of deriving the key of a crafted private key.
+const maxIterationCount = 10_000_000
+
+// ErrIncorrectPassphrase is returned when the private key cannot be
+// decrypted with the passphrase.
+var ErrIncorrectPassphrase = errors.New("incorrect passphrase for the encrypted private key")
+
+// encryptedPrivateKeyInfo is the EncryptedPrivateKeyInfo of RFC 5958.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params is the PBES2-params of RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params is the PBKDF2-params of RFC 8018.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// DecryptPrivateKey decrypts the DER encoded encrypted PKCS #8 private key
+// with the passphrase.
+func DecryptPrivateKey(der, passphrase []byte) (crypto.PrivateKey, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("malformed encrypted private key: %w", err)
+	} else if len(rest) > 0 {
+		return nil, errors.New("malformed encrypted private key: trailing data")
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption algorithm %v, only PBES2 is supported", info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("malformed PBES2 parameters: %w", err)
+	}
+	block, iv, err := newCipher(params, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	data := info.EncryptedData
+	if len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, errors.New("malformed encrypted private key: invalid data length")
+	}
+	plaintext := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, data)
+	plaintext, err = unpad(plaintext, block.BlockSize())
+	if err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+	key, err := x509.ParsePKCS8PrivateKey(plaintext)
+	if err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+	return key, nil
+}
+
+// newCipher returns the AES cipher with the key derived from the passphrase
+// and the IV.
+func newCipher(params pbes2Params, passphrase []byte) (cipher.Block, []byte, error) {
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, nil, fmt.Errorf("unsupported key derivation function %v, only PBKDF2 is supported", params.KeyDerivationFunc.Algorithm)
+	}
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, nil, fmt.Errorf("malformed PBKDF2 parameters: %w", err)
+	}
+	if kdfParams.IterationCount <= 0 {
+		return nil, nil, errors.New("malformed PBKDF2 parameters: invalid iteration count")
+	}
+	if kdfParams.IterationCount > maxIterationCount {
+		return nil, nil, fmt.Errorf("PBKDF2 iteration count %d exceeds the maximum of %d", kdfParams.IterationCount, maxIterationCount)
+	}
+	prf, err := prfHash(kdfParams.PRF.Algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keySize int
+	switch scheme := params.EncryptionScheme.Algorithm; {
+	case scheme.Equal(oidAES128CBC):
+		keySize = 16
+	case scheme.Equal(oidAES192CBC):
+		keySize = 24
+	case scheme.Equal(oidAES256CBC):
+		keySize = 32
+	default:
+		return nil, nil, fmt.Errorf("unsupported encryption scheme %v, only AES-CBC is supported", scheme)
+	}
+	if kdfParams.KeyLength != 0 && kdfParams.KeyLength != keySize {
+		return nil, nil, fmt.Errorf("malformed PBKDF2 parameters: key length %d does not match the encryption scheme", kdfParams.KeyLength)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, nil, fmt.Errorf("malformed encryption scheme parameters: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, nil, errors.New("malformed encryption scheme parameters: invalid IV length")
+	}
+
+	key := pbkdf2.Key(passphrase, kdfParams.Salt, kdfParams.IterationCount, keySize, prf)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, iv, nil
+}
+
+// prfHash returns the hash function of the PBKDF2 pseudorandom function.
+func prfHash(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(oid) == 0, oid.Equal(oidHMACWithSHA1):
+		// hmacWithSHA1 is the default
+		return sha1.New, nil
+	case oid.Equal(oidHMACWithSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidHMACWithSHA384):
+		return sha512.New384, nil
+	case oid.Equal(oidHMACWithSHA512):
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 pseudorandom function %v", oid)
+	}
+}
+
+// unpad removes the PKCS #7 padding.
+func unpad(data []byte, blockSize int) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize || n > len(data) {
+		return nil, errors.New("invalid padding")
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, errors.New("invalid padding")
+		}
+	}
+	return data[:len(data)-n], nil
+}
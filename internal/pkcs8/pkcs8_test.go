@@ -0,0 +1,104 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs8
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readPEM(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("%s is not PEM", path)
+	}
+	return block.Bytes
+}
+
+func TestDecryptPrivateKey(t *testing.T) {
+	want, err := x509.ParsePKCS8PrivateKey(readPEM(t, "testdata/ec.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{
+		"testdata/ec_aes256_sha256.key",
+		"testdata/ec_aes128_sha1.key",
+	} {
+		t.Run(path, func(t *testing.T) {
+			key, err := DecryptPrivateKey(readPEM(t, path), []byte("notation"))
+			if err != nil {
+				t.Fatalf("DecryptPrivateKey() error = %v", err)
+			}
+			if !reflect.DeepEqual(key, want) {
+				t.Fatal("DecryptPrivateKey() returned a different key")
+			}
+		})
+	}
+}
+
+func TestDecryptPrivateKeyErrors(t *testing.T) {
+	_, err := DecryptPrivateKey(readPEM(t, "testdata/ec_aes256_sha256.key"), []byte("wrong"))
+	if !errors.Is(err, ErrIncorrectPassphrase) {
+		t.Fatalf("DecryptPrivateKey() with wrong passphrase error = %v, want %v", err, ErrIncorrectPassphrase)
+	}
+	if _, err := DecryptPrivateKey(readPEM(t, "testdata/ec_pbes1.key"), []byte("notation")); err == nil {
+		t.Fatal("DecryptPrivateKey() with PBES1 expected error")
+	}
+	if _, err := DecryptPrivateKey(readPEM(t, "testdata/ec.key"), []byte("notation")); err == nil {
+		t.Fatal("DecryptPrivateKey() with unencrypted key expected error")
+	}
+}
+
+func TestDecryptPrivateKeyIterationCount(t *testing.T) {
+	der := readPEM(t, "testdata/ec_aes256_sha256.key")
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		t.Fatal(err)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		t.Fatal(err)
+	}
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		t.Fatal(err)
+	}
+	kdfParams.IterationCount = maxIterationCount + 1
+
+	var err error
+	if params.KeyDerivationFunc.Parameters.FullBytes, err = asn1.Marshal(kdfParams); err != nil {
+		t.Fatal(err)
+	}
+	if info.Algorithm.Parameters.FullBytes, err = asn1.Marshal(params); err != nil {
+		t.Fatal(err)
+	}
+	if der, err = asn1.Marshal(info); err != nil {
+		t.Fatal(err)
+	}
+	_, err = DecryptPrivateKey(der, []byte("notation"))
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum") {
+		t.Fatalf("DecryptPrivateKey() error = %v, want iteration count error", err)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// clause is a single comparison of a version constraint.
+type clause struct {
+	op      string
+	version string
+}
+
+// constraintOperators are the supported clause operators. Two-character
+// operators are listed first so that they are matched before their prefixes.
+var constraintOperators = []string{">=", "<=", ">", "<", "=", "^", "~"}
+
+// ValidateConstraint checks if constraint is a valid version constraint.
+// See [MatchConstraint] for the constraint syntax.
+func ValidateConstraint(constraint string) error {
+	_, err := parseConstraint(constraint)
+	return err
+}
+
+// MatchConstraint returns true if version satisfies constraint.
+//
+// A constraint is a comma-separated list of clauses that must all be
+// satisfied. Supported clauses are:
+//
+//   - "1.2.3" or "=1.2.3": exactly 1.2.3
+//   - ">1.2.3", ">=1.2.3", "<1.2.3", "<=1.2.3": comparison with 1.2.3
+//   - "~1.2.3": >=1.2.3 and <1.3.0
+//   - "^1.2.3": >=1.2.3 and <2.0.0, or <0.3.0 if the major version is 0
+//   - "*": any version
+//
+// Missing minor and patch versions are treated as 0, e.g. "^1" is "^1.0.0".
+func MatchConstraint(version, constraint string) (bool, error) {
+	if !IsValid(version) {
+		return false, fmt.Errorf("%s is not a valid semantic version", version)
+	}
+	clauses, err := parseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range clauses {
+		if !c.match(version) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func parseConstraint(constraint string) ([]clause, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return nil, errors.New("version constraint cannot be empty")
+	}
+	var clauses []clause
+	for _, c := range strings.Split(constraint, ",") {
+		c = strings.TrimSpace(c)
+		if c == "*" {
+			continue
+		}
+		op := ""
+		for _, candidate := range constraintOperators {
+			if strings.HasPrefix(c, candidate) {
+				op = candidate
+				break
+			}
+		}
+		version := padVersion(strings.TrimSpace(strings.TrimPrefix(c, op)))
+		if !IsValid(version) {
+			return nil, fmt.Errorf("invalid version constraint %q: %q is not a valid semantic version", constraint, c)
+		}
+		if op == "" {
+			op = "="
+		}
+		clauses = append(clauses, clause{op: op, version: version})
+	}
+	return clauses, nil
+}
+
+// padVersion appends missing minor and patch versions to a numeric version,
+// e.g. "1" becomes "1.0.0".
+func padVersion(version string) string {
+	core, suffix := version, ""
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		core, suffix = version[:i], version[i:]
+	}
+	parts := strings.Split(core, ".")
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return strings.Join(parts, ".") + suffix
+}
+
+func (c clause) match(version string) bool {
+	// versions are validated before matching
+	comp, _ := ComparePluginVersion(version, c.version)
+	switch c.op {
+	case "=":
+		return comp == 0
+	case ">":
+		return comp > 0
+	case ">=":
+		return comp >= 0
+	case "<":
+		return comp < 0
+	case "<=":
+		return comp <= 0
+	case "~":
+		major, minor, _ := versionNumbers(c.version)
+		upper, _ := ComparePluginVersion(version, fmt.Sprintf("%d.%d.0", major, minor+1))
+		return comp >= 0 && upper < 0
+	case "^":
+		major, minor, patch := versionNumbers(c.version)
+		var bound string
+		switch {
+		case major > 0:
+			bound = fmt.Sprintf("%d.0.0", major+1)
+		case minor > 0:
+			bound = fmt.Sprintf("0.%d.0", minor+1)
+		default:
+			bound = fmt.Sprintf("0.0.%d", patch+1)
+		}
+		upper, _ := ComparePluginVersion(version, bound)
+		return comp >= 0 && upper < 0
+	}
+	return false
+}
+
+// versionNumbers returns the major, minor and patch numbers of a valid
+// semantic version.
+func versionNumbers(version string) (major, minor, patch int) {
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.SplitN(version, ".", 3)
+	major, _ = strconv.Atoi(parts[0])
+	minor, _ = strconv.Atoi(parts[1])
+	patch, _ = strconv.Atoi(parts[2])
+	return major, minor, patch
+}
@@ -0,0 +1,61 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semver
+
+import "testing"
+
+func TestMatchConstraint(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"1.2.4", "=1.2.3", false},
+		{"1.2.3", "*", true},
+		{"1.2.3", ">=1.2.0, <2", true},
+		{"2.0.0", ">=1.2.0, <2", false},
+		{"1.2.0", ">1.2.0", false},
+		{"1.2.0", "<=1.2", true},
+		{"1.9.0", "^1.2", true},
+		{"2.0.0", "^1.2", false},
+		{"1.1.0", "^1.2", false},
+		{"0.2.5", "^0.2.1", true},
+		{"0.3.0", "^0.2.1", false},
+		{"0.0.3", "^0.0.2", false},
+		{"1.2.9", "~1.2.3", true},
+		{"1.3.0", "~1.2.3", false},
+		{"1.3.0-rc.1", "<1.3.0", true},
+	}
+	for _, tt := range tests {
+		got, err := MatchConstraint(tt.version, tt.constraint)
+		if err != nil {
+			t.Fatalf("MatchConstraint(%q, %q) error = %v", tt.version, tt.constraint, err)
+		}
+		if got != tt.want {
+			t.Errorf("MatchConstraint(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestMatchConstraint_Error(t *testing.T) {
+	if _, err := MatchConstraint("v1.0.0", "1.0.0"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+	for _, constraint := range []string{"", ">=a.b", "1.0.0,", "!1.0.0"} {
+		if err := ValidateConstraint(constraint); err == nil {
+			t.Errorf("ValidateConstraint(%q) expected error", constraint)
+		}
+	}
+}
@@ -16,4 +16,5 @@ package trustpolicy
 const (
 	Wildcard    = "*"
 	X509Subject = "x509.subject"
+	OrgIdentity = "org.identity"
 )
@@ -0,0 +1,316 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/tspclient-go"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// InTotoStatementType is the type of the in-toto statements written by
+	// [SignatureInventory.WriteInTotoStatement] and
+	// [NewVerificationSummaryAttestation].
+	InTotoStatementType = "https://in-toto.io/Statement/v1"
+
+	// InventoryPredicateType is the predicate type of the in-toto statements
+	// written by [SignatureInventory.WriteInTotoStatement].
+	InventoryPredicateType = "https://notaryproject.dev/attestations/signature-inventory/v1"
+)
+
+// inventoryCSVHeader is the header of the CSV inventory.
+var inventoryCSVHeader = []string{
+	"artifact",
+	"tags",
+	"signature",
+	"envelopeType",
+	"signerIdentity",
+	"signerIssuer",
+	"certificateThumbprint",
+	"certificateExpiry",
+	"signingTime",
+	"signatureExpiry",
+	"timestamp",
+	"error",
+}
+
+// SignatureInventoryOptions contains parameters for
+// [notation.CollectSignatureInventory].
+type SignatureInventoryOptions struct {
+	// TagFilter, if set, selects the tags of the artifacts whose signatures
+	// are collected. If nil, the artifacts of all tags are collected.
+	TagFilter func(tag string) bool
+
+	// MaxSignaturesPerArtifact is the maximum number of signatures collected
+	// per artifact. If set to less than or equals to zero, all signatures are
+	// collected.
+	MaxSignaturesPerArtifact int
+}
+
+// SignatureRecord describes a signature of a [SignatureInventory]. The
+// signature is parsed but not verified.
+type SignatureRecord struct {
+	// Artifact is the manifest digest of the signed artifact.
+	Artifact digest.Digest `json:"artifact"`
+
+	// Tags are the tags of the signed artifact.
+	Tags []string `json:"tags,omitempty"`
+
+	// Signature is the digest of the signature manifest.
+	Signature digest.Digest `json:"signature"`
+
+	// EnvelopeType is the media type of the signature envelope.
+	EnvelopeType string `json:"envelopeType,omitempty"`
+
+	// SignerIdentity is the subject of the signing certificate.
+	SignerIdentity string `json:"signerIdentity,omitempty"`
+
+	// SignerIssuer is the issuer of the signing certificate.
+	SignerIssuer string `json:"signerIssuer,omitempty"`
+
+	// CertificateThumbprint is the hex-encoded SHA-256 thumbprint of the
+	// signing certificate.
+	CertificateThumbprint string `json:"certificateThumbprint,omitempty"`
+
+	// CertificateExpiry is the expiry of the signing certificate.
+	CertificateExpiry *time.Time `json:"certificateExpiry,omitempty"`
+
+	// SigningTime is the signing time claimed by the signer.
+	SigningTime *time.Time `json:"signingTime,omitempty"`
+
+	// SignatureExpiry is the expiry of the signature, if set by the signer.
+	SignatureExpiry *time.Time `json:"signatureExpiry,omitempty"`
+
+	// Timestamp is the time of the timestamp countersignature, if any.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+
+	// Error is the error fetching or parsing the signature, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// SignatureInventory is the inventory of the signatures of a repository
+// collected by [notation.CollectSignatureInventory], e.g. for audit evidence.
+type SignatureInventory struct {
+	// Repository is the name of the repository, e.g.
+	// "registry.example.com/software/net-monitor".
+	Repository string `json:"repository"`
+
+	// CollectedAt is the time the inventory was collected.
+	CollectedAt time.Time `json:"collectedAt"`
+
+	// Signatures are the records of the signatures, grouped by artifact in
+	// the lexical order of their first tag.
+	Signatures []SignatureRecord `json:"signatures"`
+}
+
+// CollectSignatureInventory walks the signatures of the artifacts of the tags
+// of repo, the repository named repository, and returns their inventory with
+// the signer identities, expiry dates and timestamps. The signatures are
+// parsed but not verified. A signature failing to be fetched or parsed is
+// recorded with its error; a tag failing to resolve fails the collection.
+//
+// repo must be able to list its tags, e.g. a repository returned by
+// registry.NewRepository for a remote repository.
+func CollectSignatureInventory(ctx context.Context, repo registry.Repository, repository string, opts SignatureInventoryOptions) (*SignatureInventory, error) {
+	if repo == nil {
+		return nil, errors.New("repo cannot be nil")
+	}
+	artifacts, err := resolveTags(ctx, repo, opts.TagFilter)
+	if err != nil {
+		return nil, err
+	}
+	inventory := &SignatureInventory{
+		Repository:  repository,
+		CollectedAt: time.Now().UTC(),
+		Signatures:  []SignatureRecord{},
+	}
+	for _, artifact := range artifacts {
+		if artifact.Error != nil {
+			return nil, artifact.Error
+		}
+		var count int
+		err := repo.ListSignatures(ctx, artifact.Descriptor, func(signatureManifests []ocispec.Descriptor) error {
+			for _, sigManifestDesc := range signatureManifests {
+				if opts.MaxSignaturesPerArtifact > 0 && count >= opts.MaxSignaturesPerArtifact {
+					return errDoneListing
+				}
+				count++
+				record := SignatureRecord{
+					Artifact:  artifact.Descriptor.Digest,
+					Tags:      artifact.Tags,
+					Signature: sigManifestDesc.Digest,
+				}
+				sigBlob, sigDesc, err := repo.FetchSignatureBlob(ctx, sigManifestDesc)
+				if err != nil {
+					record.Error = fmt.Sprintf("failed to fetch the signature: %v", err)
+				} else if err := record.parse(sigDesc.MediaType, sigBlob); err != nil {
+					record.Error = fmt.Sprintf("failed to parse the signature: %v", err)
+				}
+				inventory.Signatures = append(inventory.Signatures, record)
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errDoneListing) {
+			return nil, fmt.Errorf("failed to list the signatures of %v: %w", artifact.Descriptor.Digest, err)
+		}
+	}
+	return inventory, nil
+}
+
+// parse sets the fields of the record from the signature envelope sigBlob of
+// media type envelopeMediaType.
+func (r *SignatureRecord) parse(envelopeMediaType string, sigBlob []byte) error {
+	r.EnvelopeType = envelopeMediaType
+	sigEnv, err := signature.ParseEnvelope(envelopeMediaType, sigBlob)
+	if err != nil {
+		return err
+	}
+	content, err := sigEnv.Content()
+	if err != nil {
+		return err
+	}
+	signerInfo := content.SignerInfo
+	if len(signerInfo.CertificateChain) > 0 {
+		signingCert := signerInfo.CertificateChain[0]
+		r.SignerIdentity = signingCert.Subject.String()
+		r.SignerIssuer = signingCert.Issuer.String()
+		checkSum := sha256.Sum256(signingCert.Raw)
+		r.CertificateThumbprint = hex.EncodeToString(checkSum[:])
+		r.CertificateExpiry = utcTime(signingCert.NotAfter)
+	}
+	r.SigningTime = utcTime(signerInfo.SignedAttributes.SigningTime)
+	r.SignatureExpiry = utcTime(signerInfo.SignedAttributes.Expiry)
+	if ts := signerInfo.UnsignedAttributes.TimestampSignature; len(ts) > 0 {
+		token, err := tspclient.ParseSignedToken(ts)
+		if err != nil {
+			return fmt.Errorf("failed to parse the timestamp: %w", err)
+		}
+		info, err := token.Info()
+		if err != nil {
+			return fmt.Errorf("failed to parse the timestamp: %w", err)
+		}
+		r.Timestamp = utcTime(info.GenTime)
+	}
+	return nil
+}
+
+// utcTime returns t in UTC, or nil if t is zero.
+func utcTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// WriteJSON writes the inventory to w as JSON.
+func (inv *SignatureInventory) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(inv)
+}
+
+// WriteCSV writes the signature records of the inventory to w as CSV, with a
+// header row. The tags of an artifact are separated by spaces, and the times
+// are formatted in RFC 3339.
+func (inv *SignatureInventory) WriteCSV(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(inventoryCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range inv.Signatures {
+		if err := csvWriter.Write([]string{
+			r.Artifact.String(),
+			strings.Join(r.Tags, " "),
+			r.Signature.String(),
+			r.EnvelopeType,
+			r.SignerIdentity,
+			r.SignerIssuer,
+			r.CertificateThumbprint,
+			formatTime(r.CertificateExpiry),
+			formatTime(r.SigningTime),
+			formatTime(r.SignatureExpiry),
+			formatTime(r.Timestamp),
+			r.Error,
+		}); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// formatTime formats t in RFC 3339, or returns an empty string if t is nil.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// inTotoStatement is an in-toto statement.
+// See https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	Subject       []inTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     any             `json:"predicate"`
+}
+
+// inTotoSubject is a subject of an in-toto statement.
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// WriteInTotoStatement writes the inventory to w as the predicate of an
+// in-toto statement of type [InventoryPredicateType], whose subjects are the
+// artifacts of the inventory. The statement is not signed; it may be signed
+// as a blob, e.g. with [notation.SignBlob], to be used as an attestation.
+func (inv *SignatureInventory) WriteInTotoStatement(w io.Writer) error {
+	statement := inTotoStatement{
+		Type:          InTotoStatementType,
+		Subject:       []inTotoSubject{},
+		PredicateType: InventoryPredicateType,
+		Predicate:     inv,
+	}
+	seen := make(map[digest.Digest]bool)
+	for _, r := range inv.Signatures {
+		if seen[r.Artifact] {
+			continue
+		}
+		seen[r.Artifact] = true
+		statement.Subject = append(statement.Subject, inTotoSubject{
+			Name:   inv.Repository + "@" + r.Artifact.String(),
+			Digest: map[string]string{r.Artifact.Algorithm().String(): r.Artifact.Encoded()},
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statement)
+}
@@ -0,0 +1,127 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+)
+
+func TestCollectSignatureInventory(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	signedDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.PushArtifact(ctx, "v2", map[string]string{"unsigned": "true"}); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := notation.Sign(ctx, signer, repo, notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: "localhost:5000/net-monitor:v1",
+	}); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	inventory, err := notation.CollectSignatureInventory(ctx, repo, "localhost:5000/net-monitor", notation.SignatureInventoryOptions{})
+	if err != nil {
+		t.Fatalf("CollectSignatureInventory() error = %v", err)
+	}
+	if len(inventory.Signatures) != 1 {
+		t.Fatalf("CollectSignatureInventory() returned %d signatures, want 1", len(inventory.Signatures))
+	}
+	record := inventory.Signatures[0]
+	leaf := signer.CertificateChain[0]
+	thumbprint := sha256.Sum256(leaf.Raw)
+	switch {
+	case record.Error != "":
+		t.Fatalf("record error = %s", record.Error)
+	case record.Artifact != signedDesc.Digest || len(record.Tags) != 1 || record.Tags[0] != "v1":
+		t.Fatalf("record artifact = %v %v, want %v [v1]", record.Artifact, record.Tags, signedDesc.Digest)
+	case record.EnvelopeType != jws.MediaTypeEnvelope:
+		t.Fatalf("record envelope type = %s, want %s", record.EnvelopeType, jws.MediaTypeEnvelope)
+	case record.SignerIdentity != leaf.Subject.String():
+		t.Fatalf("record signer = %s, want %s", record.SignerIdentity, leaf.Subject)
+	case record.CertificateThumbprint != hex.EncodeToString(thumbprint[:]):
+		t.Fatalf("record thumbprint = %s", record.CertificateThumbprint)
+	case record.CertificateExpiry == nil || !record.CertificateExpiry.Equal(leaf.NotAfter):
+		t.Fatalf("record certificate expiry = %v, want %v", record.CertificateExpiry, leaf.NotAfter)
+	case record.SigningTime == nil || record.Timestamp != nil:
+		t.Fatalf("record signing time = %v, timestamp = %v, want signing time without timestamp", record.SigningTime, record.Timestamp)
+	}
+
+	// csv
+	var buf bytes.Buffer
+	if err := inventory.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read csv: %v", err)
+	}
+	if len(rows) != 2 || rows[0][0] != "artifact" || rows[1][0] != signedDesc.Digest.String() || rows[1][4] != leaf.Subject.String() {
+		t.Fatalf("WriteCSV() = %v", rows)
+	}
+
+	// json
+	buf.Reset()
+	if err := inventory.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded notation.SignatureInventory
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode json: %v", err)
+	}
+	if decoded.Repository != inventory.Repository || len(decoded.Signatures) != 1 || decoded.Signatures[0].SignerIdentity != record.SignerIdentity {
+		t.Fatalf("WriteJSON() = %s", buf.String())
+	}
+
+	// in-toto statement
+	buf.Reset()
+	if err := inventory.WriteInTotoStatement(&buf); err != nil {
+		t.Fatalf("WriteInTotoStatement() error = %v", err)
+	}
+	var statement struct {
+		Type    string `json:"_type"`
+		Subject []struct {
+			Name   string            `json:"name"`
+			Digest map[string]string `json:"digest"`
+		} `json:"subject"`
+		PredicateType string                      `json:"predicateType"`
+		Predicate     notation.SignatureInventory `json:"predicate"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &statement); err != nil {
+		t.Fatalf("failed to decode in-toto statement: %v", err)
+	}
+	if statement.Type != notation.InTotoStatementType || statement.PredicateType != notation.InventoryPredicateType {
+		t.Fatalf("WriteInTotoStatement() type = %s, predicate type = %s", statement.Type, statement.PredicateType)
+	}
+	if len(statement.Subject) != 1 || statement.Subject[0].Digest["sha256"] != signedDesc.Digest.Encoded() || len(statement.Predicate.Signatures) != 1 {
+		t.Fatalf("WriteInTotoStatement() = %s", buf.String())
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fields set by notation on the logger of the Sign and Verify calls.
+const (
+	// FieldOperation is the name of the operation, e.g. "sign" or "verify".
+	FieldOperation = "operation"
+
+	// FieldOperationID is the random ID of the operation.
+	FieldOperationID = "operationId"
+
+	// FieldArtifact is the digest of the artifact.
+	FieldArtifact = "artifact"
+
+	// FieldRepository is the repository of the artifact.
+	FieldRepository = "repository"
+)
+
+// Fields are structured fields added to every message of a Logger.
+type Fields map[string]any
+
+// FieldLogger is implemented by loggers supporting structured fields. If the
+// Logger in the context implements FieldLogger, the fields are passed to it.
+// Otherwise, the fields are prepended to the messages as "[key=value ...]".
+type FieldLogger interface {
+	Logger
+
+	// WithFields returns a Logger adding fields to every message.
+	WithFields(fields Fields) Logger
+}
+
+// WithFields adds the fields to the Logger in the context, so that every
+// message logged with the returned context includes them. The fields
+// override the ones previously added with the same keys. The context is
+// returned as is if it has no Logger.
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	logger := GetLogger(ctx)
+	if logger == Discard || len(fields) == 0 {
+		return ctx
+	}
+	switch l := logger.(type) {
+	case *fieldsLogger:
+		merged := make(Fields, len(l.fields)+len(fields))
+		for k, v := range l.fields {
+			merged[k] = v
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+		return WithLogger(ctx, newFieldsLogger(l.base, merged))
+	case FieldLogger:
+		return WithLogger(ctx, l.WithFields(fields))
+	default:
+		return WithLogger(ctx, newFieldsLogger(logger, fields))
+	}
+}
+
+// WithValues adds the alternating keys and values to the Logger in the
+// context, like [WithFields]. A key without value is ignored.
+func WithValues(ctx context.Context, keysAndValues ...any) context.Context {
+	fields := make(Fields, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	return WithFields(ctx, fields)
+}
+
+// fieldsLogger prepends the fields to the messages of the base Logger.
+type fieldsLogger struct {
+	base   Logger
+	fields Fields
+	prefix string
+}
+
+func newFieldsLogger(base Logger, fields Fields) *fieldsLogger {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, fields[k])
+	}
+	return &fieldsLogger{
+		base:   base,
+		fields: fields,
+		prefix: "[" + strings.Join(pairs, " ") + "]",
+	}
+}
+
+// format returns the format with the escaped prefix.
+func (l *fieldsLogger) format(format string) string {
+	return strings.ReplaceAll(l.prefix, "%", "%%") + " " + format
+}
+
+// args returns the args with the prefix. fmt.Sprint does not add spaces
+// between strings.
+func (l *fieldsLogger) args(args []interface{}) []interface{} {
+	return append([]interface{}{l.prefix + " "}, args...)
+}
+
+// argsln returns the args with the prefix. fmt.Sprintln always adds spaces
+// between operands.
+func (l *fieldsLogger) argsln(args []interface{}) []interface{} {
+	return append([]interface{}{l.prefix}, args...)
+}
+
+func (l *fieldsLogger) Debug(args ...interface{}) {
+	l.base.Debug(l.args(args)...)
+}
+
+func (l *fieldsLogger) Debugf(format string, args ...interface{}) {
+	l.base.Debugf(l.format(format), args...)
+}
+
+func (l *fieldsLogger) Debugln(args ...interface{}) {
+	l.base.Debugln(l.argsln(args)...)
+}
+
+func (l *fieldsLogger) Info(args ...interface{}) {
+	l.base.Info(l.args(args)...)
+}
+
+func (l *fieldsLogger) Infof(format string, args ...interface{}) {
+	l.base.Infof(l.format(format), args...)
+}
+
+func (l *fieldsLogger) Infoln(args ...interface{}) {
+	l.base.Infoln(l.argsln(args)...)
+}
+
+func (l *fieldsLogger) Warn(args ...interface{}) {
+	l.base.Warn(l.args(args)...)
+}
+
+func (l *fieldsLogger) Warnf(format string, args ...interface{}) {
+	l.base.Warnf(l.format(format), args...)
+}
+
+func (l *fieldsLogger) Warnln(args ...interface{}) {
+	l.base.Warnln(l.argsln(args)...)
+}
+
+func (l *fieldsLogger) Error(args ...interface{}) {
+	l.base.Error(l.args(args)...)
+}
+
+func (l *fieldsLogger) Errorf(format string, args ...interface{}) {
+	l.base.Errorf(l.format(format), args...)
+}
+
+func (l *fieldsLogger) Errorln(args ...interface{}) {
+	l.base.Errorln(l.argsln(args)...)
+}
@@ -0,0 +1,92 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// recordingLogger records the messages.
+type recordingLogger struct {
+	discardLogger
+	messages []string
+}
+
+func (l *recordingLogger) Info(args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprint(args...))
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Infoln(args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintln(args...))
+}
+
+// structuredLogger records the fields passed to WithFields.
+type structuredLogger struct {
+	discardLogger
+	fields Fields
+}
+
+func (l *structuredLogger) WithFields(fields Fields) Logger {
+	return &structuredLogger{fields: fields}
+}
+
+func TestWithFields(t *testing.T) {
+	logger := &recordingLogger{}
+	ctx := WithLogger(context.Background(), logger)
+	ctx = WithFields(ctx, Fields{FieldOperation: "sign", FieldRepository: "localhost/test"})
+	ctx = WithValues(ctx, FieldOperation, "verify", FieldArtifact, "sha256:abc", "dangling")
+
+	l := GetLogger(ctx)
+	l.Info("message ", 100)
+	l.Infof("%d%% done", 100)
+	l.Infoln("message", 100)
+	want := []string{
+		"[artifact=sha256:abc operation=verify repository=localhost/test] message 100",
+		"[artifact=sha256:abc operation=verify repository=localhost/test] 100% done",
+		"[artifact=sha256:abc operation=verify repository=localhost/test] message 100\n",
+	}
+	if !reflect.DeepEqual(logger.messages, want) {
+		t.Fatalf("messages = %q, want %q", logger.messages, want)
+	}
+}
+
+func TestWithFieldsFieldLogger(t *testing.T) {
+	ctx := WithLogger(context.Background(), &structuredLogger{})
+	ctx = WithFields(ctx, Fields{FieldOperationID: "1"})
+
+	l, ok := GetLogger(ctx).(*structuredLogger)
+	if !ok {
+		t.Fatalf("GetLogger() = %T, want *structuredLogger", GetLogger(ctx))
+	}
+	if want := (Fields{FieldOperationID: "1"}); !reflect.DeepEqual(l.fields, want) {
+		t.Fatalf("fields = %v, want %v", l.fields, want)
+	}
+}
+
+func TestWithFieldsNoLogger(t *testing.T) {
+	ctx := context.Background()
+	if got := WithFields(ctx, Fields{FieldOperation: "sign"}); got != ctx {
+		t.Fatal("WithFields() changed the context without logger")
+	}
+	if got := GetLogger(WithValues(ctx, FieldOperation, "sign")); got != Discard {
+		t.Fatalf("GetLogger() = %v, want Discard", got)
+	}
+}
@@ -16,6 +16,10 @@
 // log.Logger interface and include it in context by calling log.WithLogger.
 // 3rd party loggers that implement log.Logger: github.com/uber-go/zap.SugaredLogger
 // and github.com/sirupsen/logrus.Logger.
+// Sensitive values, such as the plugin config values flagged with
+// log.MarkSensitiveKeys, are redacted from the messages logged by notation.
+// The messages logged within a Sign or Verify call carry the operation ID, the
+// artifact digest and the repository, added with log.WithFields.
 package log
 
 import "context"
@@ -0,0 +1,172 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the sensitive values in the log messages.
+const Redacted = "[REDACTED]"
+
+// redactionOptionsKey is the associated key type for redaction options entry
+// in context.
+const redactionOptionsKey contextKey = loggerKey + 1
+
+// defaultSensitiveKeyPatterns are the case-insensitive substrings of the keys
+// whose values are always redacted.
+var defaultSensitiveKeyPatterns = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"credential",
+	"authorization",
+	"apikey",
+	"api_key",
+	"privatekey",
+	"private_key",
+}
+
+var (
+	sensitiveKeysMu sync.RWMutex
+	sensitiveKeys   = map[string]struct{}{}
+)
+
+// Sensitive marks a value as sensitive. It is redacted when formatted or
+// marshaled to JSON, so that it can be passed to a Logger safely.
+type Sensitive string
+
+// String returns [Redacted].
+func (s Sensitive) String() string {
+	return Redacted
+}
+
+// GoString returns [Redacted].
+func (s Sensitive) GoString() string {
+	return Redacted
+}
+
+// MarshalJSON marshals [Redacted].
+func (s Sensitive) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// RedactionOptions contains optional redactions in addition to the values of
+// the sensitive keys.
+type RedactionOptions struct {
+	// KeyPaths redacts the values of the keys ending with "keyPath", such as
+	// the paths of the signing keys.
+	KeyPaths bool
+}
+
+// WithRedactionOptions is used by callers to set the RedactionOptions in the
+// context.
+func WithRedactionOptions(ctx context.Context, opts RedactionOptions) context.Context {
+	return context.WithValue(ctx, redactionOptionsKey, opts)
+}
+
+// getRedactionOptions retrieves the RedactionOptions from the context.
+func getRedactionOptions(ctx context.Context) RedactionOptions {
+	opts, _ := ctx.Value(redactionOptionsKey).(RedactionOptions)
+	return opts
+}
+
+// MarkSensitiveKeys flags the keys, e.g. plugin config keys, whose values are
+// sensitive. The keys are matched case-insensitively. The keys containing
+// "password", "secret", "token", "credential" and the like are always
+// sensitive.
+func MarkSensitiveKeys(keys ...string) {
+	sensitiveKeysMu.Lock()
+	defer sensitiveKeysMu.Unlock()
+	for _, key := range keys {
+		sensitiveKeys[strings.ToLower(key)] = struct{}{}
+	}
+}
+
+// IsSensitiveKey reports whether the value of key is sensitive.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range defaultSensitiveKeyPatterns {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	sensitiveKeysMu.RLock()
+	defer sensitiveKeysMu.RUnlock()
+	_, ok := sensitiveKeys[key]
+	return ok
+}
+
+// isRedactedKey reports whether the value of key is redacted with the
+// redaction options.
+func isRedactedKey(key string, opts RedactionOptions) bool {
+	if opts.KeyPaths && strings.HasSuffix(strings.ToLower(key), "keypath") {
+		return true
+	}
+	return IsSensitiveKey(key)
+}
+
+// RedactMap returns a copy of m with the sensitive values replaced with
+// [Redacted].
+func RedactMap(ctx context.Context, m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	opts := getRedactionOptions(ctx)
+	redacted := make(map[string]string, len(m))
+	for k, v := range m {
+		if isRedactedKey(k, opts) {
+			v = Redacted
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// RedactJSON returns a copy of the JSON document data with the sensitive
+// values of the objects, at any depth, replaced with [Redacted]. data is
+// returned as is if it is not a JSON document.
+func RedactJSON(ctx context.Context, data []byte) []byte {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return data
+	}
+	redacted, err := json.Marshal(redactValue(doc, getRedactionOptions(ctx)))
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+func redactValue(value any, opts RedactionOptions) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if isRedactedKey(key, opts) {
+				v[key] = Redacted
+			} else {
+				v[key] = redactValue(child, opts)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = redactValue(child, opts)
+		}
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSensitive(t *testing.T) {
+	s := Sensitive("hunter2")
+	for _, format := range []string{"%s", "%v", "%+v", "%#v"} {
+		if got := fmt.Sprintf(format, s); got != Redacted {
+			t.Errorf("Sprintf(%q) = %q, want %q", format, got, Redacted)
+		}
+	}
+	data, err := json.Marshal(map[string]any{"password": s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"password":"[REDACTED]"}`; got != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+	if string(s) != "hunter2" {
+		t.Errorf("string(s) = %q", string(s))
+	}
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	MarkSensitiveKeys("vault.Role")
+	tests := map[string]bool{
+		"password":        true,
+		"AUTH_TOKEN":      true,
+		"clientSecret":    true,
+		"aws.credentials": true,
+		"Authorization":   true,
+		"vault.role":      true,
+		"region":          false,
+		"keyPath":         false,
+	}
+	for key, want := range tests {
+		if got := IsSensitiveKey(key); got != want {
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestRedactMap(t *testing.T) {
+	ctx := context.Background()
+	if RedactMap(ctx, nil) != nil {
+		t.Fatal("RedactMap(nil) != nil")
+	}
+	m := map[string]string{"token": "abc", "region": "us", "keyPath": "/keys/key.pem"}
+	want := map[string]string{"token": Redacted, "region": "us", "keyPath": "/keys/key.pem"}
+	if got := RedactMap(ctx, m); !reflect.DeepEqual(got, want) {
+		t.Fatalf("RedactMap() = %v, want %v", got, want)
+	}
+	if m["token"] != "abc" {
+		t.Fatal("RedactMap() modified its input")
+	}
+
+	ctx = WithRedactionOptions(ctx, RedactionOptions{KeyPaths: true})
+	want["keyPath"] = Redacted
+	if got := RedactMap(ctx, m); !reflect.DeepEqual(got, want) {
+		t.Fatalf("RedactMap() with key paths = %v, want %v", got, want)
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	ctx := context.Background()
+	data := []byte(`{"keyId":"key","pluginConfig":{"password":"hunter2","region":"us"},"list":[{"apiKey":"abc"}],"secret":{"nested":"value"}}`)
+	want := `{"keyId":"key","list":[{"apiKey":"[REDACTED]"}],"pluginConfig":{"password":"[REDACTED]","region":"us"},"secret":"[REDACTED]"}`
+	if got := string(RedactJSON(ctx, data)); got != want {
+		t.Fatalf("RedactJSON() = %s, want %s", got, want)
+	}
+	notJSON := []byte("not json")
+	if got := RedactJSON(ctx, notJSON); string(got) != string(notJSON) {
+		t.Fatalf("RedactJSON() = %s, want %s", got, notJSON)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package network configures the outbound connections of the registry,
+// timestamping and revocation clients, e.g. for verifiers in restricted
+// networks controlling the name resolution and the egress.
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/notaryproject/notation-go/dump"
+	"github.com/notaryproject/tspclient-go"
+)
+
+// Defaults of the dialer, as of http.DefaultTransport.
+const (
+	DefaultConnectTimeout = 30 * time.Second
+	DefaultKeepAlive      = 30 * time.Second
+)
+
+// DefaultTimestampingTimeout bounds the requests of the timestampers, as of
+// tspclient.NewHTTPTimestamper without client.
+const DefaultTimestampingTimeout = 5 * time.Second
+
+// Config configures the outbound connections. The zero value uses the
+// defaults of http.DefaultTransport. A Config is shared by the registry
+// clients, with the Network of registry.RepositoryOptions, the revocation
+// clients, with the Network of verifier.VerifierOptions, and the
+// timestamping clients, with the timestamper returned by
+// [Config.NewTimestamper].
+type Config struct {
+	// Resolver resolves the host names. If nil, and Nameservers is empty,
+	// net.DefaultResolver is used.
+	Resolver *net.Resolver
+
+	// Nameservers are the addresses, e.g. "10.0.0.53:53", of the DNS
+	// servers resolving the host names instead of the system configuration.
+	// They are tried in order. It cannot be set with Resolver.
+	Nameservers []string
+
+	// DisableFallback disables the RFC 6555 fast fallback, also known as
+	// Happy Eyeballs, of the dual-stack hosts: the IPv6 and IPv4 addresses
+	// are tried sequentially instead of racing.
+	DisableFallback bool
+
+	// FallbackDelay is the delay before the fast fallback to IPv4 of the
+	// dual-stack hosts. If set to less than or equals to zero, the default
+	// of net.Dialer is used. It has no effect if DisableFallback is set.
+	FallbackDelay time.Duration
+
+	// ConnectTimeout bounds each connection establishment, including the
+	// name resolution. If set to less than or equals to zero,
+	// [DefaultConnectTimeout] is used.
+	ConnectTimeout time.Duration
+
+	// KeepAlive is the interval of the TCP keep-alive probes. If set to
+	// zero, [DefaultKeepAlive] is used. If negative, the keep-alive probes
+	// are disabled.
+	KeepAlive time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshakes. If set to less than or
+	// equals to zero, the default of http.DefaultTransport is used.
+	TLSHandshakeTimeout time.Duration
+
+	// Proxy is the proxy of the outbound connections. If nil, the proxy of
+	// the transport is kept, which is read from the HTTP_PROXY, HTTPS_PROXY
+	// and NO_PROXY environment variables for http.DefaultTransport. An
+	// empty Proxy disables the proxy.
+	Proxy *Proxy
+
+	// ComponentProxies override Proxy for the connections of components,
+	// e.g. for environments where only the registry traffic may use the
+	// proxy. See [Config.ForComponent].
+	ComponentProxies map[Component]*Proxy
+
+	// component is the component of the configuration returned by
+	// [Config.ForComponent], if any.
+	component Component
+}
+
+// Validate validates the configuration.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Resolver != nil && len(c.Nameservers) > 0 {
+		return errors.New("network config cannot set both resolver and nameservers")
+	}
+	for _, nameserver := range c.Nameservers {
+		if _, _, err := net.SplitHostPort(nameserver); err != nil {
+			return fmt.Errorf("network config has invalid nameserver %q: %w", nameserver, err)
+		}
+	}
+	if err := c.Proxy.Validate(); err != nil {
+		return fmt.Errorf("network config has invalid proxy: %w", err)
+	}
+	for component, proxy := range c.ComponentProxies {
+		switch component {
+		case ComponentRegistry, ComponentRevocation, ComponentTimestamping:
+		default:
+			return fmt.Errorf("network config has proxy of unknown component %q", component)
+		}
+		if err := proxy.Validate(); err != nil {
+			return fmt.Errorf("network config has invalid proxy of component %q: %w", component, err)
+		}
+	}
+	return nil
+}
+
+// ForComponent returns a copy of the configuration for the connections of
+// component, with the proxy of component in ComponentProxies, if any,
+// overriding Proxy. It returns nil if c is nil.
+func (c *Config) ForComponent(component Component) *Config {
+	if c == nil {
+		return nil
+	}
+	config := *c
+	if proxy, ok := c.ComponentProxies[component]; ok {
+		config.Proxy = proxy
+	}
+	config.ComponentProxies = nil
+	config.component = component
+	return &config
+}
+
+// Dialer returns the dialer of the configuration.
+func (c *Config) Dialer() (*net.Dialer, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{
+		Timeout:   DefaultConnectTimeout,
+		KeepAlive: DefaultKeepAlive,
+	}
+	if c == nil {
+		return dialer, nil
+	}
+	if c.ConnectTimeout > 0 {
+		dialer.Timeout = c.ConnectTimeout
+	}
+	if c.KeepAlive != 0 {
+		dialer.KeepAlive = c.KeepAlive
+	}
+	switch {
+	case c.DisableFallback:
+		dialer.FallbackDelay = -1
+	case c.FallbackDelay > 0:
+		dialer.FallbackDelay = c.FallbackDelay
+	}
+	dialer.Resolver = c.resolver()
+	return dialer, nil
+}
+
+// resolver returns the resolver of the configuration, or nil for the
+// default resolver.
+func (c *Config) resolver() *net.Resolver {
+	if len(c.Nameservers) == 0 {
+		return c.Resolver
+	}
+	nameservers := c.Nameservers
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			var errs []error
+			for _, nameserver := range nameservers {
+				conn, err := dialer.DialContext(ctx, network, nameserver)
+				if err == nil {
+					return conn, nil
+				}
+				errs = append(errs, err)
+			}
+			return nil, errors.Join(errs...)
+		},
+	}
+}
+
+// ConfigureTransport applies the configuration to transport. The proxies of
+// ComponentProxies are not applied, see [Config.ForComponent].
+func (c *Config) ConfigureTransport(transport *http.Transport) error {
+	dialer, err := c.Dialer()
+	if err != nil {
+		return err
+	}
+	transport.DialContext = dialer.DialContext
+	if c == nil {
+		return nil
+	}
+	if c.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = c.TLSHandshakeTimeout
+	}
+	if c.Proxy != nil {
+		proxy, err := c.Proxy.proxyFunc()
+		if err != nil {
+			return err
+		}
+		transport.Proxy = proxy
+	}
+	return nil
+}
+
+// Transport returns a clone of http.DefaultTransport with the configuration
+// applied.
+func (c *Config) Transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if err := c.ConfigureTransport(transport); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// HTTPClient returns an HTTP client with the configuration applied, and
+// bounding each request to timeout. If timeout is less than or equals to
+// zero, the requests are only bounded by their context. The exchanges are
+// recorded to the dump.Dumper in the context of the requests, if any.
+func (c *Config) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	transport, err := c.Transport()
+	if err != nil {
+		return nil, err
+	}
+	var component Component
+	if c != nil {
+		component = c.component
+	}
+	client := &http.Client{
+		Transport: &dump.Transport{
+			Component: string(component),
+			Base:      transport,
+		},
+	}
+	if timeout > 0 {
+		client.Timeout = timeout
+	}
+	return client, nil
+}
+
+// NewTimestamper returns an RFC 3161 timestamper requesting the timestamps
+// from the Timestamping Authority at endpoint, with the configuration for
+// [ComponentTimestamping] applied. The requests are bounded by
+// [DefaultTimestampingTimeout].
+func (c *Config) NewTimestamper(endpoint string) (tspclient.Timestamper, error) {
+	client, err := c.ForComponent(ComponentTimestamping).HTTPClient(DefaultTimestampingTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return tspclient.NewHTTPTimestamper(client, endpoint)
+}
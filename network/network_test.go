@@ -0,0 +1,225 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/dump"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:   "zero",
+			config: &Config{},
+		},
+		{
+			name:   "nameservers",
+			config: &Config{Nameservers: []string{"10.0.0.53:53", "[2001:db8::53]:53"}},
+		},
+		{
+			name:    "invalid nameserver",
+			config:  &Config{Nameservers: []string{"10.0.0.53"}},
+			wantErr: true,
+		},
+		{
+			name:    "resolver and nameservers",
+			config:  &Config{Resolver: &net.Resolver{}, Nameservers: []string{"10.0.0.53:53"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigDialer(t *testing.T) {
+	var config *Config
+	dialer, err := config.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer() error = %v", err)
+	}
+	if dialer.Timeout != DefaultConnectTimeout || dialer.KeepAlive != DefaultKeepAlive || dialer.FallbackDelay != 0 || dialer.Resolver != nil {
+		t.Fatalf("Dialer() = %+v, want the defaults", dialer)
+	}
+
+	resolver := &net.Resolver{}
+	config = &Config{
+		Resolver:       resolver,
+		FallbackDelay:  100 * time.Millisecond,
+		ConnectTimeout: 5 * time.Second,
+		KeepAlive:      -1,
+	}
+	dialer, err = config.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer() error = %v", err)
+	}
+	if dialer.Timeout != 5*time.Second || dialer.KeepAlive != -1 || dialer.FallbackDelay != 100*time.Millisecond || dialer.Resolver != resolver {
+		t.Fatalf("Dialer() = %+v, want the configured dialer", dialer)
+	}
+
+	config.DisableFallback = true
+	dialer, err = config.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer() error = %v", err)
+	}
+	if dialer.FallbackDelay >= 0 {
+		t.Fatalf("Dialer().FallbackDelay = %v, want fast fallback disabled", dialer.FallbackDelay)
+	}
+
+	config.Nameservers = []string{"10.0.0.53:53"}
+	if _, err := config.Dialer(); err == nil {
+		t.Fatal("Dialer() expects error for invalid config")
+	}
+}
+
+func TestConfigNameservers(t *testing.T) {
+	// the first nameserver is not listening: the resolver falls back to the
+	// second one
+	var dials atomic.Int64
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			dials.Add(1)
+			conn.Close()
+		}
+	}()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	config := &Config{Nameservers: []string{closed.Addr().String(), listener.Addr().String()}}
+	dialer, err := config.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer() error = %v", err)
+	}
+	conn, err := dialer.Resolver.Dial(context.Background(), "tcp", "ignored:53")
+	if err != nil {
+		t.Fatalf("Resolver.Dial() error = %v", err)
+	}
+	conn.Close()
+	if !waitFor(func() bool { return dials.Load() == 1 }) {
+		t.Fatalf("nameserver dials = %d, want 1", dials.Load())
+	}
+
+	config.Nameservers = []string{closed.Addr().String()}
+	dialer, err = config.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer() error = %v", err)
+	}
+	if _, err := dialer.Resolver.Dial(context.Background(), "tcp", "ignored:53"); err == nil {
+		t.Fatal("Resolver.Dial() expects error for unreachable nameservers")
+	}
+}
+
+func TestConfigHTTPClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	var config *Config
+	client, err := config.HTTPClient(0)
+	if err != nil {
+		t.Fatalf("HTTPClient() error = %v", err)
+	}
+	if client.Timeout != 0 {
+		t.Fatalf("HTTPClient().Timeout = %v, want 0", client.Timeout)
+	}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	config = &Config{TLSHandshakeTimeout: time.Second}
+	client, err = config.HTTPClient(2 * time.Second)
+	if err != nil {
+		t.Fatalf("HTTPClient() error = %v", err)
+	}
+	if client.Timeout != 2*time.Second {
+		t.Fatalf("HTTPClient().Timeout = %v, want 2s", client.Timeout)
+	}
+	if transport := client.Transport.(*dump.Transport).Base.(*http.Transport); transport.TLSHandshakeTimeout != time.Second {
+		t.Fatalf("TLSHandshakeTimeout = %v, want 1s", transport.TLSHandshakeTimeout)
+	}
+
+	// the exchanges are recorded with the component of the config
+	client, err = config.ForComponent(ComponentTimestamping).HTTPClient(0)
+	if err != nil {
+		t.Fatalf("HTTPClient() error = %v", err)
+	}
+	if got := client.Transport.(*dump.Transport).Component; got != string(ComponentTimestamping) {
+		t.Fatalf("Transport.Component = %q, want %q", got, ComponentTimestamping)
+	}
+
+	// the connections are dialed with the resolver of the config
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("no nameserver")
+		},
+	}
+	config = &Config{Resolver: resolver}
+	client, err = config.HTTPClient(0)
+	if err != nil {
+		t.Fatalf("HTTPClient() error = %v", err)
+	}
+	if _, err := client.Get("http://registry.notation.invalid"); err == nil {
+		t.Fatal("Get() expects error for unresolved host")
+	}
+
+	config = &Config{Resolver: resolver, Nameservers: []string{"10.0.0.53:53"}}
+	if _, err := config.HTTPClient(0); err == nil {
+		t.Fatal("HTTPClient() expects error for invalid config")
+	}
+}
+
+// waitFor polls cond for up to a second.
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return cond()
+}
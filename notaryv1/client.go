@@ -0,0 +1,285 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notaryv1 provides a read-only adapter for the legacy Notary v1
+// trust data of Docker Content Trust (DCT), to audit the migration of
+// repositories from DCT to notation.
+//
+// The adapter enumerates the signed tags of a repository from the TUF
+// metadata served by a Notary v1 server, and validates the signatures and
+// the expiry of the root, targets and delegated targets roles. It never
+// writes trust data. [Audit] reports the tags signed with DCT alongside
+// their notation signatures:
+//
+//	client, err := notaryv1.NewClient("https://notary.docker.io", notaryv1.ClientOptions{})
+//	...
+//	report, err := notaryv1.Audit(ctx, client, "docker.io/library/alpine", repo)
+//
+// As in DCT, the root role is trusted on first use: its signatures are
+// verified with its own keys. The timestamp and snapshot roles, which only
+// protect the freshness of the trust data, are not validated.
+package notaryv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// maxMetadataSize is the maximum size of a TUF metadata file.
+const maxMetadataSize = 8 * 1024 * 1024
+
+// ErrNoTrustData is returned when the repository has no Notary v1 trust data,
+// i.e. it is not signed with DCT.
+var ErrNoTrustData = errors.New("repository has no notary v1 trust data")
+
+// ClientOptions contains parameters for [NewClient].
+type ClientOptions struct {
+	// HTTPClient is the client used to fetch the trust data. If nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Time returns the time the expiry of the trust data is validated at.
+	// If nil, time.Now is used.
+	Time func() time.Time
+}
+
+// Client fetches and validates the Notary v1 trust data of repositories from
+// a Notary v1 server.
+type Client struct {
+	serverURL  *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient returns a Client of the Notary v1 server at serverURL, e.g.
+// "https://notary.docker.io".
+func NewClient(serverURL string, opts ClientOptions) (*Client, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notary server URL %q: %w", serverURL, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("invalid notary server URL %q: scheme must be https or http", serverURL)
+	}
+	client := &Client{
+		serverURL:  u,
+		httpClient: opts.HTTPClient,
+		now:        opts.Time,
+	}
+	if client.httpClient == nil {
+		client.httpClient = http.DefaultClient
+	}
+	if client.now == nil {
+		client.now = time.Now
+	}
+	return client, nil
+}
+
+// TrustData is the Notary v1 trust data of a repository.
+type TrustData struct {
+	// GUN is the globally unique name of the repository, e.g.
+	// "docker.io/library/alpine".
+	GUN string
+
+	// Roles are the roles of the trust data that were validated: the root,
+	// the targets and the delegated targets roles.
+	Roles []Role
+
+	// Targets are the signed tags of the repository, sorted by name and
+	// role.
+	Targets []Target
+}
+
+// Valid returns true if all roles of the trust data are valid.
+func (d *TrustData) Valid() bool {
+	for _, role := range d.Roles {
+		if role.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Role is the validation result of a role of the trust data.
+type Role struct {
+	// Name is the name of the role, e.g. "targets/releases".
+	Name string
+
+	// Version is the version of the metadata of the role.
+	Version int
+
+	// Expires is the expiry of the metadata of the role.
+	Expires time.Time
+
+	// Err is the validation error of the role, or nil if the role is valid.
+	Err error
+}
+
+// Target is a tag signed with DCT.
+type Target struct {
+	// Name is the tag.
+	Name string
+
+	// Role is the role signing the tag, e.g. "targets/releases".
+	Role string
+
+	// Digest is the digest of the manifest of the tag.
+	Digest digest.Digest
+
+	// Size is the size of the manifest of the tag.
+	Size int64
+
+	// Valid is true if the role signing the tag is valid.
+	Valid bool
+}
+
+// TrustData fetches and validates the trust data of the repository with the
+// globally unique name gun, e.g. "docker.io/library/alpine". Validation
+// failures are reported in the returned trust data. If the repository has no
+// trust data, [ErrNoTrustData] is returned.
+func (c *Client) TrustData(ctx context.Context, gun string) (*TrustData, error) {
+	if gun == "" {
+		return nil, errors.New("gun cannot be empty")
+	}
+	now := c.now()
+	trustData := &TrustData{GUN: gun}
+
+	// root, trusted on first use
+	rootJSON, err := c.fetchMetadata(ctx, gun, roleRoot)
+	if err != nil {
+		return nil, err
+	}
+	var root rootMetadata
+	rootSigned, err := parseMetadata(rootJSON, &root)
+	if err != nil {
+		return nil, fmt.Errorf("role %q: %w", roleRoot, err)
+	}
+	rootRole := Role{Name: roleRoot, Version: root.Version, Expires: root.Expires}
+	if err := verifyMetadata(rootSigned, root.Keys, root.Roles[roleRoot], root.Expires, now); err != nil {
+		rootRole.Err = fmt.Errorf("role %q: %w", roleRoot, err)
+	}
+	trustData.Roles = append(trustData.Roles, rootRole)
+
+	// targets
+	targetsJSON, err := c.fetchMetadata(ctx, gun, roleTargets)
+	if err != nil {
+		return nil, err
+	}
+	var targets targetsMetadata
+	targetsSigned, err := parseMetadata(targetsJSON, &targets)
+	if err != nil {
+		return nil, fmt.Errorf("role %q: %w", roleTargets, err)
+	}
+	targetsRole := Role{Name: roleTargets, Version: targets.Version, Expires: targets.Expires, Err: rootRole.Err}
+	if targetsRole.Err == nil {
+		if err := verifyMetadata(targetsSigned, root.Keys, root.Roles[roleTargets], targets.Expires, now); err != nil {
+			targetsRole.Err = fmt.Errorf("role %q: %w", roleTargets, err)
+		}
+	}
+	trustData.Roles = append(trustData.Roles, targetsRole)
+	trustData.Targets = appendTargets(trustData.Targets, targetsRole, targets.Targets)
+
+	// delegated targets, e.g. "targets/releases"
+	for _, delegation := range targets.Delegations.Roles {
+		if !strings.HasPrefix(delegation.Name, roleTargets+"/") {
+			continue
+		}
+		delegationJSON, err := c.fetchMetadata(ctx, gun, delegation.Name)
+		if errors.Is(err, ErrNoTrustData) {
+			// the delegation has not signed any tag
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var delegated targetsMetadata
+		delegatedSigned, err := parseMetadata(delegationJSON, &delegated)
+		if err != nil {
+			return nil, fmt.Errorf("role %q: %w", delegation.Name, err)
+		}
+		delegatedRole := Role{Name: delegation.Name, Version: delegated.Version, Expires: delegated.Expires, Err: targetsRole.Err}
+		if delegatedRole.Err == nil {
+			if err := verifyMetadata(delegatedSigned, targets.Delegations.Keys, delegation.tufRole, delegated.Expires, now); err != nil {
+				delegatedRole.Err = fmt.Errorf("role %q: %w", delegation.Name, err)
+			}
+		}
+		trustData.Roles = append(trustData.Roles, delegatedRole)
+		trustData.Targets = appendTargets(trustData.Targets, delegatedRole, delegated.Targets)
+	}
+	sort.Slice(trustData.Targets, func(i, j int) bool {
+		if trustData.Targets[i].Name != trustData.Targets[j].Name {
+			return trustData.Targets[i].Name < trustData.Targets[j].Name
+		}
+		return trustData.Targets[i].Role < trustData.Targets[j].Role
+	})
+	return trustData, nil
+}
+
+// appendTargets appends the targets signed by role to targets. The targets
+// without a SHA-256 hash are ignored, as they cannot be matched to a
+// manifest.
+func appendTargets(targets []Target, role Role, files map[string]targetFile) []Target {
+	for name, file := range files {
+		hash, ok := file.Hashes["sha256"]
+		if !ok {
+			continue
+		}
+		targets = append(targets, Target{
+			Name:   name,
+			Role:   role.Name,
+			Digest: digest.NewDigestFromBytes(digest.SHA256, hash),
+			Size:   file.Length,
+			Valid:  role.Err == nil,
+		})
+	}
+	return targets
+}
+
+// fetchMetadata fetches the TUF metadata file of role of the repository with
+// the globally unique name gun.
+func (c *Client) fetchMetadata(ctx context.Context, gun, role string) ([]byte, error) {
+	u := c.serverURL.JoinPath("v2", gun, "_trust", "tuf", role+".json")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trust data of role %q: %w", role, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("role %q: %w", role, ErrNoTrustData)
+	default:
+		return nil, fmt.Errorf("failed to fetch trust data of role %q: %s", role, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust data of role %q: %w", role, err)
+	}
+	if len(data) > maxMetadataSize {
+		return nil, fmt.Errorf("trust data of role %q exceeds %d bytes", role, maxMetadataSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,203 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notaryv1
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+const testGUN = "registry.acme-rockets.io/software/net-monitor"
+
+// testTrustData is the trust data of a repository with the tag "v1" signed
+// by the targets role and the tag "latest" signed by the "targets/releases"
+// delegated role.
+type testTrustData struct {
+	root, targets, releases testKey
+	v1Digest, latestDigest  digest.Digest
+	metadata                map[string][]byte
+}
+
+func newTestTrustData(t *testing.T, expires time.Time) *testTrustData {
+	td := &testTrustData{
+		root:         newTestKey(t, "root-key"),
+		targets:      newTestKey(t, "targets-key"),
+		releases:     newTestKey(t, "releases-key"),
+		v1Digest:     digest.FromString("v1"),
+		latestDigest: digest.FromString("latest"),
+		metadata:     make(map[string][]byte),
+	}
+	root := rootMetadata{
+		Type:    "Root",
+		Version: 1,
+		Expires: expires,
+		Keys:    map[string]tufKey{td.root.id: td.root.key, td.targets.id: td.targets.key},
+		Roles: map[string]tufRole{
+			roleRoot:    {KeyIDs: []string{td.root.id}, Threshold: 1},
+			roleTargets: {KeyIDs: []string{td.targets.id}, Threshold: 1},
+		},
+	}
+	td.metadata[roleRoot] = signMetadata(t, root, td.root)
+
+	targets := targetsMetadata{
+		Type:    "Targets",
+		Version: 2,
+		Expires: expires,
+		Targets: map[string]targetFile{
+			"v1": {Hashes: map[string][]byte{"sha256": hexBytes(td.v1Digest)}, Length: 100},
+		},
+	}
+	targets.Delegations.Keys = map[string]tufKey{td.releases.id: td.releases.key}
+	targets.Delegations.Roles = []delegatedRole{
+		{Name: "targets/releases", tufRole: tufRole{KeyIDs: []string{td.releases.id}, Threshold: 1}},
+	}
+	td.metadata[roleTargets] = signMetadata(t, targets, td.targets)
+
+	releases := targetsMetadata{
+		Type:    "Targets",
+		Version: 3,
+		Expires: expires,
+		Targets: map[string]targetFile{
+			"latest": {Hashes: map[string][]byte{"sha256": hexBytes(td.latestDigest)}, Length: 200},
+		},
+	}
+	td.metadata["targets/releases"] = signMetadata(t, releases, td.releases)
+	return td
+}
+
+// hexBytes returns the hash bytes of d.
+func hexBytes(d digest.Digest) []byte {
+	b, _ := hex.DecodeString(d.Encoded())
+	return b
+}
+
+// serve serves the trust data of testGUN.
+func (td *testTrustData) serve(t *testing.T) *Client {
+	t.Helper()
+	prefix := "/v2/" + testGUN + "/_trust/tuf/"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data, ok := td.metadata[strings.TrimSuffix(role, ".json")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewClient(server.URL, ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	for _, serverURL := range []string{"ftp://notary.example", "://notary.example"} {
+		if _, err := NewClient(serverURL, ClientOptions{}); err == nil {
+			t.Fatalf("NewClient(%q) expects error, got nil", serverURL)
+		}
+	}
+}
+
+func TestTrustData(t *testing.T) {
+	td := newTestTrustData(t, time.Now().Add(time.Hour))
+	client := td.serve(t)
+
+	trustData, err := client.TrustData(context.Background(), testGUN)
+	if err != nil {
+		t.Fatalf("TrustData() error = %v", err)
+	}
+	if !trustData.Valid() {
+		t.Fatalf("TrustData() roles = %+v, want valid", trustData.Roles)
+	}
+	if len(trustData.Roles) != 3 {
+		t.Fatalf("TrustData() returned %d roles, want 3", len(trustData.Roles))
+	}
+	want := []Target{
+		{Name: "latest", Role: "targets/releases", Digest: td.latestDigest, Size: 200, Valid: true},
+		{Name: "v1", Role: roleTargets, Digest: td.v1Digest, Size: 100, Valid: true},
+	}
+	if len(trustData.Targets) != len(want) {
+		t.Fatalf("TrustData() targets = %+v, want %+v", trustData.Targets, want)
+	}
+	for i := range want {
+		if trustData.Targets[i] != want[i] {
+			t.Fatalf("TrustData() target = %+v, want %+v", trustData.Targets[i], want[i])
+		}
+	}
+}
+
+func TestTrustDataInvalid(t *testing.T) {
+	// delegation signed by the wrong key
+	td := newTestTrustData(t, time.Now().Add(time.Hour))
+	td.metadata["targets/releases"] = signMetadata(t, targetsMetadata{Type: "Targets", Expires: time.Now().Add(time.Hour)}, td.targets)
+	trustData, err := td.serve(t).TrustData(context.Background(), testGUN)
+	if err != nil {
+		t.Fatalf("TrustData() error = %v", err)
+	}
+	if trustData.Valid() || trustData.Roles[2].Err == nil || trustData.Roles[1].Err != nil {
+		t.Fatalf("TrustData() roles = %+v, want invalid delegated role", trustData.Roles)
+	}
+
+	// expired
+	td = newTestTrustData(t, time.Now().Add(-time.Hour))
+	trustData, err = td.serve(t).TrustData(context.Background(), testGUN)
+	if err != nil {
+		t.Fatalf("TrustData() error = %v", err)
+	}
+	for _, role := range trustData.Roles {
+		if role.Err == nil {
+			t.Fatalf("role %q expects error for expired trust data", role.Name)
+		}
+	}
+	for _, target := range trustData.Targets {
+		if target.Valid {
+			t.Fatalf("target %q expects to be invalid", target.Name)
+		}
+	}
+}
+
+func TestTrustDataNotFound(t *testing.T) {
+	td := newTestTrustData(t, time.Now().Add(time.Hour))
+	client := td.serve(t)
+	if _, err := client.TrustData(context.Background(), "registry.acme-rockets.io/unsigned"); !errors.Is(err, ErrNoTrustData) {
+		t.Fatalf("TrustData() error = %v, want ErrNoTrustData", err)
+	}
+	if _, err := client.TrustData(context.Background(), ""); err == nil {
+		t.Fatal("TrustData() expects error for empty gun")
+	}
+
+	// unsigned delegation
+	delete(td.metadata, "targets/releases")
+	trustData, err := client.TrustData(context.Background(), testGUN)
+	if err != nil {
+		t.Fatalf("TrustData() error = %v", err)
+	}
+	if len(trustData.Targets) != 1 || !trustData.Valid() {
+		t.Fatalf("TrustData() = %+v, want the valid target v1", trustData)
+	}
+}
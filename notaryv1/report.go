@@ -0,0 +1,127 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notaryv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Report is the DCT trust data of a repository alongside its notation
+// signatures.
+type Report struct {
+	// TrustData is the DCT trust data of the repository.
+	TrustData *TrustData
+
+	// Tags are the tags signed with DCT, in the order of
+	// TrustData.Targets.
+	Tags []TagReport
+}
+
+// TagReport reports a tag signed with DCT alongside the notation signatures
+// of the signed manifest.
+type TagReport struct {
+	// Target is the tag signed with DCT.
+	Target Target
+
+	// Descriptor is the descriptor of the manifest the tag resolves to in
+	// the registry.
+	Descriptor ocispec.Descriptor
+
+	// Current is true if the tag still resolves to the manifest signed with
+	// DCT.
+	Current bool
+
+	// NotationSignatures are the notation signature manifests of the
+	// manifest signed with DCT. The signatures are listed, not verified.
+	NotationSignatures []ocispec.Descriptor
+
+	// Err is the error that occurred while resolving the tag or listing the
+	// notation signatures, if any.
+	Err error
+}
+
+// Migrated returns true if the tag is signed with notation.
+func (r TagReport) Migrated() bool {
+	return r.Err == nil && len(r.NotationSignatures) > 0
+}
+
+// Migrated returns true if every valid tag signed with DCT that still
+// resolves to its signed manifest is signed with notation.
+func (r *Report) Migrated() bool {
+	for _, tag := range r.Tags {
+		if tag.Target.Valid && tag.Current && !tag.Migrated() {
+			return false
+		}
+	}
+	return true
+}
+
+// Audit fetches and validates the DCT trust data of the repository with the
+// globally unique name gun from client, and reports each tag signed with DCT
+// alongside the notation signatures listed in repo, the repository of gun.
+// Registry failures on a tag are reported in the tag report.
+func Audit(ctx context.Context, client *Client, gun string, repo registry.Repository) (*Report, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+	if repo == nil {
+		return nil, errors.New("repo cannot be nil")
+	}
+	trustData, err := client.TrustData(ctx, gun)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{
+		TrustData: trustData,
+		Tags:      make([]TagReport, 0, len(trustData.Targets)),
+	}
+	for _, target := range trustData.Targets {
+		report.Tags = append(report.Tags, auditTag(ctx, repo, target))
+	}
+	return report, nil
+}
+
+// auditTag reports the tag target alongside its notation signatures listed
+// in repo.
+func auditTag(ctx context.Context, repo registry.Repository, target Target) TagReport {
+	tagReport := TagReport{Target: target}
+	desc, err := repo.Resolve(ctx, target.Name)
+	if err != nil {
+		tagReport.Err = fmt.Errorf("failed to resolve tag %q: %w", target.Name, err)
+		return tagReport
+	}
+	tagReport.Descriptor = desc
+	tagReport.Current = desc.Digest == target.Digest
+	signedDesc := desc
+	if !tagReport.Current {
+		signedDesc = ocispec.Descriptor{
+			MediaType: desc.MediaType,
+			Digest:    target.Digest,
+			Size:      target.Size,
+		}
+	}
+	err = repo.ListSignatures(ctx, signedDesc, func(signatureManifests []ocispec.Descriptor) error {
+		tagReport.NotationSignatures = append(tagReport.NotationSignatures, signatureManifests...)
+		return nil
+	})
+	if err != nil {
+		tagReport.Err = fmt.Errorf("failed to list the notation signatures of tag %q: %w", target.Name, err)
+	}
+	return tagReport
+}
@@ -0,0 +1,117 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notaryv1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// tagRepository resolves the tags to their descriptors, and lists the
+// signatures of the signed digests.
+type tagRepository struct {
+	mock.Repository
+	tags       map[string]ocispec.Descriptor
+	signatures map[digest.Digest][]ocispec.Descriptor
+}
+
+func (r tagRepository) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	desc, ok := r.tags[reference]
+	if !ok {
+		return ocispec.Descriptor{}, errors.New("not found")
+	}
+	return desc, nil
+}
+
+func (r tagRepository) ListSignatures(ctx context.Context, desc ocispec.Descriptor, fn func(signatureManifests []ocispec.Descriptor) error) error {
+	return fn(r.signatures[desc.Digest])
+}
+
+func TestAudit(t *testing.T) {
+	td := newTestTrustData(t, time.Now().Add(time.Hour))
+	client := td.serve(t)
+	repo := tagRepository{
+		tags: map[string]ocispec.Descriptor{
+			"latest": {MediaType: ocispec.MediaTypeImageManifest, Digest: td.latestDigest, Size: 200},
+			"v1":     {MediaType: ocispec.MediaTypeImageManifest, Digest: td.v1Digest, Size: 100},
+		},
+		signatures: map[digest.Digest][]ocispec.Descriptor{
+			td.latestDigest: {mock.SigManfiestDescriptor},
+		},
+	}
+
+	report, err := Audit(context.Background(), client, testGUN, repo)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if len(report.Tags) != 2 {
+		t.Fatalf("Audit() returned %d tags, want 2", len(report.Tags))
+	}
+	latest, v1 := report.Tags[0], report.Tags[1]
+	if !latest.Current || !latest.Migrated() || len(latest.NotationSignatures) != 1 {
+		t.Fatalf("tag latest = %+v, want migrated", latest)
+	}
+	if !v1.Current || v1.Migrated() {
+		t.Fatalf("tag v1 = %+v, want not migrated", v1)
+	}
+	if report.Migrated() {
+		t.Fatal("Migrated() = true, want false")
+	}
+
+	// v1 moved to a manifest signed with notation, the DCT signed manifest
+	// is not signed with notation
+	movedDigest := digest.FromString("moved")
+	repo.tags["v1"] = ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: movedDigest, Size: 300}
+	repo.signatures[movedDigest] = []ocispec.Descriptor{mock.SigManfiestDescriptor}
+	report, err = Audit(context.Background(), client, testGUN, repo)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if v1 := report.Tags[1]; v1.Current || v1.Migrated() {
+		t.Fatalf("tag v1 = %+v, want moved and not migrated", v1)
+	}
+	if !report.Migrated() {
+		t.Fatal("Migrated() = false, want true")
+	}
+
+	// tag deleted
+	delete(repo.tags, "latest")
+	report, err = Audit(context.Background(), client, testGUN, repo)
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+	if report.Tags[0].Err == nil {
+		t.Fatal("tag latest expects error for deleted tag")
+	}
+}
+
+func TestAuditErrors(t *testing.T) {
+	td := newTestTrustData(t, time.Now().Add(time.Hour))
+	client := td.serve(t)
+	if _, err := Audit(context.Background(), nil, testGUN, mock.NewRepository()); err == nil {
+		t.Fatal("Audit() expects error for nil client")
+	}
+	if _, err := Audit(context.Background(), client, testGUN, nil); err == nil {
+		t.Fatal("Audit() expects error for nil repo")
+	}
+	if _, err := Audit(context.Background(), client, "registry.acme-rockets.io/unsigned", mock.NewRepository()); !errors.Is(err, ErrNoTrustData) {
+		t.Fatalf("Audit() error = %v, want ErrNoTrustData", err)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notaryv1
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// TUF role names of the trust data of a repository.
+const (
+	roleRoot    = "root"
+	roleTargets = "targets"
+)
+
+// signedMetadata is a signed TUF metadata file.
+type signedMetadata struct {
+	Signed     json.RawMessage `json:"signed"`
+	Signatures []tufSignature  `json:"signatures"`
+}
+
+// tufSignature is a signature of a TUF metadata file.
+type tufSignature struct {
+	KeyID     string `json:"keyid"`
+	Method    string `json:"method"`
+	Signature []byte `json:"sig"`
+}
+
+// tufKey is a public key of the trust data.
+type tufKey struct {
+	Type  string `json:"keytype"`
+	Value struct {
+		Public []byte `json:"public"`
+	} `json:"keyval"`
+}
+
+// tufRole lists the keys of a role and the number of signatures required.
+type tufRole struct {
+	KeyIDs    []string `json:"keyids"`
+	Threshold int      `json:"threshold"`
+}
+
+// rootMetadata is the signed content of the root role.
+type rootMetadata struct {
+	Type    string             `json:"_type"`
+	Version int                `json:"version"`
+	Expires time.Time          `json:"expires"`
+	Keys    map[string]tufKey  `json:"keys"`
+	Roles   map[string]tufRole `json:"roles"`
+}
+
+// delegatedRole is a role delegated by the targets role, e.g.
+// "targets/releases".
+type delegatedRole struct {
+	tufRole
+	Name string `json:"name"`
+}
+
+// targetFile is a target of the trust data, i.e. a signed tag.
+type targetFile struct {
+	Hashes map[string][]byte `json:"hashes"`
+	Length int64             `json:"length"`
+}
+
+// targetsMetadata is the signed content of the targets role and of its
+// delegated roles.
+type targetsMetadata struct {
+	Type        string                `json:"_type"`
+	Version     int                   `json:"version"`
+	Expires     time.Time             `json:"expires"`
+	Targets     map[string]targetFile `json:"targets"`
+	Delegations struct {
+		Keys  map[string]tufKey `json:"keys"`
+		Roles []delegatedRole   `json:"roles"`
+	} `json:"delegations"`
+}
+
+// parseMetadata parses the signed TUF metadata file data, and decodes its
+// signed content into v.
+func parseMetadata(data []byte, v any) (*signedMetadata, error) {
+	var metadata signedMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse trust data: %w", err)
+	}
+	if len(metadata.Signed) == 0 {
+		return nil, errors.New("trust data has no signed content")
+	}
+	if err := json.Unmarshal(metadata.Signed, v); err != nil {
+		return nil, fmt.Errorf("failed to parse signed content of trust data: %w", err)
+	}
+	return &metadata, nil
+}
+
+// verifyMetadata verifies that the metadata is signed by at least threshold
+// keys of role, and is not expired at t.
+func verifyMetadata(metadata *signedMetadata, keys map[string]tufKey, role tufRole, expires, t time.Time) error {
+	if role.Threshold < 1 {
+		return fmt.Errorf("role threshold %d is less than 1", role.Threshold)
+	}
+	message, err := canonicalJSON(metadata.Signed)
+	if err != nil {
+		return err
+	}
+	roleKeys := make(map[string]bool, len(role.KeyIDs))
+	for _, keyID := range role.KeyIDs {
+		roleKeys[keyID] = true
+	}
+	valid := make(map[string]bool)
+	for _, sig := range metadata.Signatures {
+		if !roleKeys[sig.KeyID] || valid[sig.KeyID] {
+			continue
+		}
+		key, ok := keys[sig.KeyID]
+		if !ok {
+			continue
+		}
+		if err := verifySignature(key, sig, message); err == nil {
+			valid[sig.KeyID] = true
+		}
+	}
+	if len(valid) < role.Threshold {
+		return fmt.Errorf("trust data has %d valid signatures, %d required", len(valid), role.Threshold)
+	}
+	if !expires.After(t) {
+		return fmt.Errorf("trust data expired at %s", expires.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// verifySignature verifies the signature sig of message with key.
+func verifySignature(key tufKey, sig tufSignature, message []byte) error {
+	publicKey, err := key.publicKey()
+	if err != nil {
+		return err
+	}
+	switch sig.Method {
+	case "ecdsa":
+		pub, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key type %q cannot verify %q signatures", key.Type, sig.Method)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig.Signature) != 2*size {
+			return errors.New("invalid ecdsa signature size")
+		}
+		r := new(big.Int).SetBytes(sig.Signature[:size])
+		s := new(big.Int).SetBytes(sig.Signature[size:])
+		hash := sha256.Sum256(message)
+		if !ecdsa.Verify(pub, hash[:], r, s) {
+			return errors.New("invalid ecdsa signature")
+		}
+	case "rsapss", "rsapkcs1v15":
+		pub, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key type %q cannot verify %q signatures", key.Type, sig.Method)
+		}
+		hash := sha256.Sum256(message)
+		if sig.Method == "rsapss" {
+			return rsa.VerifyPSS(pub, crypto.SHA256, hash[:], sig.Signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], sig.Signature)
+	case "ed25519":
+		pub, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("key type %q cannot verify %q signatures", key.Type, sig.Method)
+		}
+		if !ed25519.Verify(pub, message, sig.Signature) {
+			return errors.New("invalid ed25519 signature")
+		}
+	default:
+		return fmt.Errorf("signature method %q is not supported", sig.Method)
+	}
+	return nil
+}
+
+// publicKey returns the public key of the TUF key.
+func (k tufKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Type {
+	case "ecdsa", "rsa":
+		return x509.ParsePKIXPublicKey(k.Value.Public)
+	case "ecdsa-x509", "rsa-x509":
+		block, _ := pem.Decode(k.Value.Public)
+		if block == nil {
+			return nil, fmt.Errorf("key type %q has no PEM certificate", k.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	case "ed25519":
+		if len(k.Value.Public) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 public key size")
+		}
+		return ed25519.PublicKey(k.Value.Public), nil
+	default:
+		return nil, fmt.Errorf("key type %q is not supported", k.Type)
+	}
+}
+
+// canonicalJSON returns the canonical JSON encoding of data signed by the
+// TUF signatures of Notary v1: sorted object keys, no insignificant
+// whitespace and numbers as they are.
+func canonicalJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to canonicalize trust data: %w", err)
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,202 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notaryv1
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// testKey is an ECDSA P-256 key of test trust data.
+type testKey struct {
+	id         string
+	key        tufKey
+	privateKey *ecdsa.PrivateKey
+}
+
+func newTestKey(t *testing.T, id string) testKey {
+	t.Helper()
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := tufKey{Type: "ecdsa"}
+	key.Value.Public = der
+	return testKey{id: id, key: key, privateKey: privateKey}
+}
+
+// signMetadata returns the TUF metadata file of signed, signed by keys.
+func signMetadata(t *testing.T, signed any, keys ...testKey) []byte {
+	t.Helper()
+	signedJSON, err := json.Marshal(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, err := canonicalJSON(signedJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256(message)
+	metadata := signedMetadata{Signed: signedJSON}
+	for _, key := range keys {
+		r, s, err := ecdsa.Sign(rand.Reader, key.privateKey, hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		metadata.Signatures = append(metadata.Signatures, tufSignature{KeyID: key.id, Method: "ecdsa", Signature: sig})
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	got, err := canonicalJSON([]byte(`{ "b": 1, "a": {"d": [1, 2.50, "x"], "c": null}, "e": 12345678901234567890 }`))
+	if err != nil {
+		t.Fatalf("canonicalJSON() error = %v", err)
+	}
+	want := `{"a":{"c":null,"d":[1,2.50,"x"]},"b":1,"e":12345678901234567890}`
+	if string(got) != want {
+		t.Fatalf("canonicalJSON() = %s, want %s", got, want)
+	}
+	if _, err := canonicalJSON([]byte(`{`)); err == nil {
+		t.Fatal("canonicalJSON() expects error for invalid JSON")
+	}
+}
+
+func TestVerifyMetadata(t *testing.T) {
+	now := time.Now()
+	key1 := newTestKey(t, "key1")
+	key2 := newTestKey(t, "key2")
+	keys := map[string]tufKey{key1.id: key1.key, key2.id: key2.key}
+	signed := map[string]any{"_type": "Targets", "version": 1}
+	expires := now.Add(time.Hour)
+
+	var metadata signedMetadata
+	if err := json.Unmarshal(signMetadata(t, signed, key1, key2), &metadata); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyMetadata(&metadata, keys, tufRole{KeyIDs: []string{"key1", "key2"}, Threshold: 2}, expires, now); err != nil {
+		t.Fatalf("verifyMetadata() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		role    tufRole
+		expires time.Time
+	}{
+		{
+			name:    "threshold not met",
+			role:    tufRole{KeyIDs: []string{"key1"}, Threshold: 2},
+			expires: expires,
+		},
+		{
+			name:    "zero threshold",
+			role:    tufRole{KeyIDs: []string{"key1"}},
+			expires: expires,
+		},
+		{
+			name:    "key not in role",
+			role:    tufRole{KeyIDs: []string{"key3"}, Threshold: 1},
+			expires: expires,
+		},
+		{
+			name:    "expired",
+			role:    tufRole{KeyIDs: []string{"key1"}, Threshold: 1},
+			expires: now.Add(-time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyMetadata(&metadata, keys, tt.role, tt.expires, now); err == nil {
+				t.Fatal("verifyMetadata() expects error, got nil")
+			}
+		})
+	}
+
+	// tampered content
+	tampered := metadata
+	tampered.Signed = json.RawMessage(`{"_type":"Targets","version":2}`)
+	if err := verifyMetadata(&tampered, keys, tufRole{KeyIDs: []string{"key1"}, Threshold: 1}, expires, now); err == nil {
+		t.Fatal("verifyMetadata() expects error for tampered content")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	message := []byte(`{"_type":"Targets"}`)
+	hash := sha256.Sum256(message)
+
+	// ed25519
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey := tufKey{Type: "ed25519"}
+	edKey.Value.Public = edPublic
+	edSig := tufSignature{Method: "ed25519", Signature: ed25519.Sign(edPrivate, message)}
+	if err := verifySignature(edKey, edSig, message); err != nil {
+		t.Fatalf("verifySignature() ed25519 error = %v", err)
+	}
+
+	// rsapss
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(rsaPrivate.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey := tufKey{Type: "rsa"}
+	rsaKey.Value.Public = der
+	pssSig, err := rsa.SignPSS(rand.Reader, rsaPrivate, crypto.SHA256, hash[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifySignature(rsaKey, tufSignature{Method: "rsapss", Signature: pssSig}, message); err != nil {
+		t.Fatalf("verifySignature() rsapss error = %v", err)
+	}
+
+	// mismatches
+	if err := verifySignature(rsaKey, edSig, message); err == nil {
+		t.Fatal("verifySignature() expects error for key type mismatch")
+	}
+	if err := verifySignature(edKey, tufSignature{Method: "hmac"}, message); err == nil {
+		t.Fatal("verifySignature() expects error for unsupported method")
+	}
+	if err := verifySignature(tufKey{Type: "dsa"}, edSig, message); err == nil {
+		t.Fatal("verifySignature() expects error for unsupported key type")
+	}
+	if err := verifySignature(tufKey{Type: "ecdsa-x509"}, edSig, message); err == nil {
+		t.Fatal("verifySignature() expects error for missing certificate")
+	}
+}
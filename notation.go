@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"oras.land/oras-go/v2/errdef"
 	orasRegistry "oras.land/oras-go/v2/registry"
 	"oras.land/oras-go/v2/registry/remote"
 
@@ -35,10 +36,12 @@ import (
 	"github.com/notaryproject/notation-core-go/signature"
 	"github.com/notaryproject/notation-core-go/signature/cose"
 	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/clock"
 	"github.com/notaryproject/notation-go/internal/envelope"
 	"github.com/notaryproject/notation-go/log"
 	"github.com/notaryproject/notation-go/registry"
 	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
 	"github.com/notaryproject/tspclient-go"
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -75,6 +78,10 @@ type SignerSignOptions struct {
 	// timestamping certificate chain with context during signing.
 	// When present, only used when timestamping is performed.
 	TSARevocationValidator revocation.Validator
+
+	// Clock reads the signing time, from which the expiry of the signature
+	// is computed. If nil, the system time is used.
+	Clock clock.Clock
 }
 
 // Signer is a generic interface for signing an OCI artifact.
@@ -96,6 +103,17 @@ type SignBlobOptions struct {
 	// UserMetadata contains key-value pairs that are added to the signature
 	// payload
 	UserMetadata map[string]string
+
+	// DigestAlgorithm is the digest algorithm of the blob descriptor, one of
+	// SHA-256, SHA-384 and SHA-512. It must not be weaker than the hash
+	// algorithm of the signing key. If empty, the hash algorithm of the
+	// signing key is used.
+	DigestAlgorithm digest.Algorithm
+
+	// UnprotectedHeaders are added to the unprotected header of the
+	// signature envelope, e.g. a distribution hint. They are not covered by
+	// the signature. See [AddUnprotectedHeaders].
+	UnprotectedHeaders map[string][]byte
 }
 
 // BlobDescriptorGenerator creates descriptor using the digest Algorithm.
@@ -136,6 +154,14 @@ type SignOptions struct {
 	// UserMetadata contains key-value pairs that are added to the signature
 	// payload
 	UserMetadata map[string]string
+
+	// Progress, if set, is called with the progress events of the signing.
+	Progress ProgressFunc
+
+	// UnprotectedHeaders are added to the unprotected header of the
+	// signature envelope, e.g. a distribution hint. They are not covered by
+	// the signature. See [AddUnprotectedHeaders].
+	UnprotectedHeaders map[string][]byte
 }
 
 // Sign signs the OCI artifact and push the signature to the Repository.
@@ -158,6 +184,9 @@ func Sign(ctx context.Context, signer Signer, repo registry.Repository, signOpts
 // failed. In this case, the artifact and signature manifest descriptors are
 // returned with the error.
 func SignOCI(ctx context.Context, signer Signer, repo registry.Repository, signOpts SignOptions) (artifactManifestDesc, sigManifestDesc ocispec.Descriptor, err error) {
+	ctx = withOperation(ctx, operationSign)
+	defer func() { endOperation(ctx, err) }()
+
 	// sanity check
 	if err := validateSignArguments(signer, signOpts.SignerSignOptions); err != nil {
 		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
@@ -166,12 +195,16 @@ func SignOCI(ctx context.Context, signer Signer, repo registry.Repository, signO
 		return ocispec.Descriptor{}, ocispec.Descriptor{}, errors.New("repo cannot be nil")
 	}
 
-	logger := log.GetLogger(ctx)
 	artifactRef := signOpts.ArtifactReference
+	var repository string
 	if ref, err := orasRegistry.ParseReference(artifactRef); err == nil {
 		// artifactRef is a valid full reference
 		artifactRef = ref.Reference
+		repository = ref.Registry + "/" + ref.Repository
+		ctx = log.WithFields(ctx, log.Fields{log.FieldRepository: repository})
 	}
+	logger := log.GetLogger(ctx)
+	logTransportWarnings(ctx, repo)
 	artifactManifestDesc, err = repo.Resolve(ctx, artifactRef)
 	if err != nil {
 		return ocispec.Descriptor{}, ocispec.Descriptor{}, fmt.Errorf("failed to resolve reference: %w", err)
@@ -189,6 +222,17 @@ func SignOCI(ctx context.Context, signer Signer, repo registry.Repository, signO
 		logger.Warnf("Always sign the artifact using digest(`@sha256:...`) rather than a tag(`:%s`) because tags are mutable and a tag reference can point to a different artifact than the one signed", artifactRef)
 		logger.Infof("Resolved artifact tag `%s` to digest `%v` before signing", artifactRef, artifactManifestDesc.Digest)
 	}
+	return signArtifact(ctx, signer, repo, repository, artifactManifestDesc, signOpts)
+}
+
+// signArtifact signs the artifact manifest descriptor artifactManifestDesc
+// of repository and pushes the signature to repo.
+func signArtifact(ctx context.Context, signer Signer, repo registry.Repository, repository string, artifactManifestDesc ocispec.Descriptor, signOpts SignOptions) (ocispec.Descriptor, ocispec.Descriptor, error) {
+	ctx = log.WithFields(ctx, log.Fields{log.FieldArtifact: artifactManifestDesc.Digest})
+	logger := log.GetLogger(ctx)
+	if err := validateUnprotectedHeaders(signOpts.UnprotectedHeaders); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
 	descToSign, err := addUserMetadataToDescriptor(ctx, artifactManifestDesc, signOpts.UserMetadata)
 	if err != nil {
 		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
@@ -197,6 +241,10 @@ func SignOCI(ctx context.Context, signer Signer, repo registry.Repository, signO
 	if err != nil {
 		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
 	}
+	if sig, err = AddUnprotectedHeaders(signOpts.SignatureMediaType, sig, signOpts.UnprotectedHeaders); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+	signOpts.Progress.report(ProgressEvent{Type: ProgressSignatureGenerated, Artifact: artifactManifestDesc})
 
 	var pluginAnnotations map[string]string
 	if signerAnts, ok := signer.(signerAnnotation); ok {
@@ -209,23 +257,29 @@ func SignOCI(ctx context.Context, signer Signer, repo registry.Repository, signO
 	}
 	logger.Debugf("Generated annotations: %+v", annotations)
 	logger.Debugf("Pushing signature of artifact descriptor: %+v, signature media type: %v", artifactManifestDesc, signOpts.SignatureMediaType)
-	_, sigManifestDesc, err = repo.PushSignature(ctx, signOpts.SignatureMediaType, sig, artifactManifestDesc, annotations)
+	_, sigManifestDesc, err := repo.PushSignature(ctx, signOpts.SignatureMediaType, sig, artifactManifestDesc, annotations)
 	if err != nil {
 		var referrerError *remote.ReferrersError
 		if errors.As(err, &referrerError) && referrerError.IsReferrersIndexDelete() {
 			// return the descriptors for referrersIndexDelete error as
 			// the signature is successfully pushed to the repository
+			auditArtifactSigned(ctx, repository, signOpts.ArtifactReference, artifactManifestDesc, sigManifestDesc, signerInfo)
 			return artifactManifestDesc, sigManifestDesc, err
 		}
 		logger.Error("Failed to push the signature")
-		return ocispec.Descriptor{}, ocispec.Descriptor{}, ErrorPushSignatureFailed{Msg: err.Error()}
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, ErrorPushSignatureFailed{Msg: err.Error(), InnerError: err}
 	}
+	auditArtifactSigned(ctx, repository, signOpts.ArtifactReference, artifactManifestDesc, sigManifestDesc, signerInfo)
+	signOpts.Progress.report(ProgressEvent{Type: ProgressSignaturePushed, Artifact: artifactManifestDesc, Signature: sigManifestDesc})
 	return artifactManifestDesc, sigManifestDesc, nil
 }
 
 // SignBlob signs the arbitrary data from blobReader and returns
 // the signature and SignerInfo.
-func SignBlob(ctx context.Context, signer BlobSigner, blobReader io.Reader, signBlobOpts SignBlobOptions) ([]byte, *signature.SignerInfo, error) {
+func SignBlob(ctx context.Context, signer BlobSigner, blobReader io.Reader, signBlobOpts SignBlobOptions) (_ []byte, _ *signature.SignerInfo, err error) {
+	ctx = withOperation(ctx, operationSignBlob)
+	defer func() { endOperation(ctx, err) }()
+
 	// sanity checks
 	if err := validateSignArguments(signer, signBlobOpts.SignerSignOptions); err != nil {
 		return nil, nil, err
@@ -239,9 +293,43 @@ func SignBlob(ctx context.Context, signer BlobSigner, blobReader io.Reader, sign
 	if err := validateContentMediaType(signBlobOpts.ContentMediaType); err != nil {
 		return nil, nil, err
 	}
+	if err := validateUnprotectedHeaders(signBlobOpts.UnprotectedHeaders); err != nil {
+		return nil, nil, err
+	}
+
+	if signBlobOpts.DigestAlgorithm != "" && digestAlgorithmStrength(signBlobOpts.DigestAlgorithm) == 0 {
+		return nil, nil, fmt.Errorf("unsupported digest algorithm %q", signBlobOpts.DigestAlgorithm)
+	}
 
 	getDescFunc := getDescriptorFunc(ctx, blobReader, signBlobOpts.ContentMediaType, signBlobOpts.UserMetadata)
-	return signer.SignBlob(ctx, getDescFunc, signBlobOpts.SignerSignOptions)
+	if digestAlgo := signBlobOpts.DigestAlgorithm; digestAlgo != "" {
+		genDesc := getDescFunc
+		getDescFunc = func(keyDigestAlgo digest.Algorithm) (ocispec.Descriptor, error) {
+			if digestAlgorithmStrength(digestAlgo) < digestAlgorithmStrength(keyDigestAlgo) {
+				return ocispec.Descriptor{}, fmt.Errorf("digest algorithm %q is weaker than the hash algorithm %q of the signing key", digestAlgo, keyDigestAlgo)
+			}
+			return genDesc(digestAlgo)
+		}
+	}
+	sig, signerInfo, err := signer.SignBlob(ctx, getDescFunc, signBlobOpts.SignerSignOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sig, err = AddUnprotectedHeaders(signBlobOpts.SignatureMediaType, sig, signBlobOpts.UnprotectedHeaders); err != nil {
+		return nil, nil, err
+	}
+	return sig, signerInfo, nil
+}
+
+// digestAlgorithmStrength returns the strength of the digest algorithm
+// supported by notation, 0 if the algorithm is not supported.
+func digestAlgorithmStrength(algo digest.Algorithm) int {
+	switch algo {
+	case digest.SHA256, digest.SHA384, digest.SHA512:
+		return algo.Size()
+	default:
+		return 0
+	}
 }
 
 func validateSignArguments(signer any, signOpts SignerSignOptions) error {
@@ -319,6 +407,68 @@ type VerificationOutcome struct {
 
 	// Error that caused the verification to fail (if it fails)
 	Error error
+
+	// PluginFallback records the fallback action taken when the verification
+	// plugin mandated by the signature was not installed. It is nil if no
+	// fallback action was taken.
+	PluginFallback *PluginFallback
+
+	// ActionOverrides records the actions of the verification level
+	// overridden by the caller with the ActionOverrides of the verify
+	// options, in the order of [trustpolicy.ValidationTypes].
+	ActionOverrides []ActionOverride
+
+	// Warnings reports the insecure settings the verification relied on,
+	// e.g. a registry accessed with plain HTTP. See
+	// [registry.RepositoryOptions].PlainHTTP.
+	Warnings []string
+
+	// Endorsement is the outcome of the countersignature endorsing the
+	// signature, if required by the trust policy. See [Countersign].
+	Endorsement *VerificationOutcome
+
+	// SkippedSignatures are the signatures of the artifact skipped by the
+	// verification before this signature, as their envelopes are not
+	// supported. See [VerifyOptions].WarnUnsupportedEnvelopes.
+	SkippedSignatures []SkippedSignature
+
+	// ShadowedError is the error the verification failed with, reported as
+	// a warning by a verifier in shadow mode instead of failing the
+	// verification. It is nil if the verification succeeded.
+	ShadowedError error
+
+	// ResolvedIdentities are the organizational identities of the signing
+	// certificate resolved by the identity resolver plugin of the verifier,
+	// e.g. team names, if the trust policy has "org.identity" trusted
+	// identities that were resolved.
+	ResolvedIdentities []string
+}
+
+// ActionOverride describes an action of the verification level of the trust
+// policy overridden by the caller for a verification.
+type ActionOverride struct {
+	// Type is the validation type, e.g. "expiry".
+	Type trustpolicy.ValidationType
+
+	// PolicyAction is the action set by the trust policy.
+	PolicyAction trustpolicy.ValidationAction
+
+	// Action is the action taken.
+	Action trustpolicy.ValidationAction
+}
+
+// PluginFallback describes the fallback action taken by the verifier when the
+// verification plugin mandated by a signature was not installed.
+type PluginFallback struct {
+	// PluginName is the name of the missing verification plugin.
+	PluginName string
+
+	// Action is the action taken, i.e. "warn" if the verification continued
+	// without the plugin, or "install" if the plugin was installed.
+	Action string
+
+	// Reason is the error encountered when locating the plugin.
+	Reason error
 }
 
 // UserMetadata returns the user metadata from the signature envelope.
@@ -345,6 +495,12 @@ type VerifierVerifyOptions struct {
 	// verified against to. It must be a full reference.
 	ArtifactReference string
 
+	// ArtifactType is the artifact type of the artifact that is being
+	// verified, e.g. "application/spdx+json". It selects the trust policy
+	// statements restricted to artifact types. If empty, the artifact type of
+	// the descriptor passed to [Verifier.Verify] is used.
+	ArtifactType string
+
 	// SignatureMediaType is the envelope type of the signature.
 	// Currently only `application/jose+json` and `application/cose` are
 	// supported.
@@ -356,8 +512,51 @@ type VerifierVerifyOptions struct {
 	// UserMetadata contains key-value pairs that must be present in the
 	// signature.
 	UserMetadata map[string]string
+
+	// SignatureManifestAnnotations are the annotations of the signature
+	// manifest. They are passed to the verification plugin, if any.
+	SignatureManifestAnnotations map[string]string
+
+	// ActionOverrides overrides the actions of the verification level of the
+	// applicable trust policy for this verification. See
+	// [VerifyOptions].ActionOverrides.
+	ActionOverrides map[trustpolicy.ValidationType]trustpolicy.ValidationAction
+
+	// SignatureAlgorithms, if set, restricts the signature algorithms
+	// accepted for this verification. See [VerifyOptions].SignatureAlgorithms.
+	SignatureAlgorithms []signature.Algorithm
+
+	// TrustStore, if set, overrides or supplements the trust store of the
+	// verifier for this verification, as set by TrustStoreMode. See
+	// [VerifyOptions].TrustStore.
+	TrustStore truststore.X509TrustStore
+
+	// TrustStoreMode sets how TrustStore is combined with the trust store of
+	// the verifier.
+	TrustStoreMode TrustStoreMode
+
+	// Progress, if set, is called with the progress events of the
+	// verification, such as [ProgressRevocationCheckStarted].
+	Progress ProgressFunc
 }
 
+// TrustStoreMode sets how the trust store of a verification, e.g.
+// [VerifyOptions].TrustStore, is combined with the trust store of the
+// verifier.
+type TrustStoreMode int
+
+const (
+	// TrustStoreOverride uses the trust store of the verification in place
+	// of the trust store of the verifier.
+	TrustStoreOverride TrustStoreMode = iota
+
+	// TrustStoreSupplement uses the certificates of the trust store of the
+	// verification in addition to the certificates of the trust store of the
+	// verifier. A named store missing from one of the trust stores is read
+	// from the other.
+	TrustStoreSupplement
+)
+
 // Verifier is a generic interface for verifying an OCI artifact.
 type Verifier interface {
 	// Verify verifies the `signature` associated with the target OCI artifact
@@ -385,6 +584,15 @@ type BlobVerifierVerifyOptions struct {
 	// TrustPolicyName is the name of trust policy picked by caller.
 	// If empty, the global trust policy will be applied.
 	TrustPolicyName string
+
+	// ActionOverrides overrides the actions of the verification level of the
+	// applicable trust policy for this verification. See
+	// [VerifyOptions].ActionOverrides.
+	ActionOverrides map[trustpolicy.ValidationType]trustpolicy.ValidationAction
+
+	// SignatureAlgorithms, if set, restricts the signature algorithms
+	// accepted for this verification. See [VerifyOptions].SignatureAlgorithms.
+	SignatureAlgorithms []signature.Algorithm
 }
 
 // BlobVerifier is a generic interface for verifying a blob.
@@ -400,6 +608,43 @@ type verifySkipper interface {
 	SkipVerify(ctx context.Context, opts VerifierVerifyOptions) (bool, *trustpolicy.VerificationLevel, error)
 }
 
+// verificationRequirements returns whether the verification of the artifact
+// referenced in opts is skipped with its verification level, and verifier as
+// an endorsementVerifier if the signatures of the artifact must be endorsed.
+func verificationRequirements(ctx context.Context, verifier Verifier, opts VerifierVerifyOptions) (bool, *trustpolicy.VerificationLevel, endorsementVerifier, error) {
+	logger := log.GetLogger(ctx)
+	if skipChecker, ok := verifier.(verifySkipper); ok {
+		logger.Info("Checking whether signature verification should be skipped or not")
+		skip, verificationLevel, err := skipChecker.SkipVerify(ctx, opts)
+		if err != nil {
+			return false, nil, nil, err
+		}
+		if skip {
+			return true, verificationLevel, nil, nil
+		}
+		logger.Info("Check over. The signature verification level is not set to 'skip' in the trust policy.")
+	}
+	endorser, _ := verifier.(endorsementVerifier)
+	if endorser != nil {
+		required, err := endorser.EndorsementRequired(ctx, opts)
+		if err != nil {
+			return false, nil, nil, err
+		}
+		if !required {
+			endorser = nil
+		}
+	}
+	return false, nil, endorser, nil
+}
+
+// artifactDescriber is implemented by repositories able to describe an
+// artifact with the artifact type and the annotations of its manifest.
+type artifactDescriber interface {
+	// DescribeArtifact returns the manifest descriptor desc with the artifact
+	// type and the annotations of the manifest.
+	DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error)
+}
+
 // VerifyOptions contains parameters for [notation.Verify].
 type VerifyOptions struct {
 	// ArtifactReference is the reference of the artifact that is being
@@ -417,6 +662,129 @@ type VerifyOptions struct {
 	// UserMetadata contains key-value pairs that must be present in the
 	// signature
 	UserMetadata map[string]string
+
+	// PrioritizeSignatures pre-filters and orders the signatures of the
+	// artifact by cheap criteria from their manifests before verifying them,
+	// so that the signatures most likely to be valid are verified first. The
+	// signatures are ordered by whether the certificate thumbprints in the
+	// annotations of their manifests match a trusted certificate, then by
+	// creation time, newest first. At most MaxSignatureCandidates signature
+	// manifests are listed, and only the signatures passing the pre-filter
+	// count against MaxSignatureAttempts.
+	PrioritizeSignatures bool
+
+	// MaxSignatureCandidates is the maximum number of signature manifests
+	// listed when PrioritizeSignatures is set. If set to less than or equals
+	// to zero, ten times MaxSignatureAttempts is used.
+	MaxSignatureCandidates int
+
+	// SignatureMediaTypes are the accepted signature envelope media types
+	// when PrioritizeSignatures is set, e.g. "application/jose+json". The
+	// signatures with other envelope types are skipped before fetching their
+	// envelopes, if supported by the repository. If empty, all the envelope
+	// types are accepted.
+	SignatureMediaTypes []string
+
+	// TrustedThumbprints are the hex-encoded SHA-256 thumbprints of the
+	// trusted certificates used to prioritize the signatures. If empty, the
+	// thumbprints reported by the verifier are used, if supported.
+	TrustedThumbprints []string
+
+	// SignatureFetchConcurrency is the maximum number of signature envelope
+	// blobs fetched concurrently ahead of the verification of the earlier
+	// signatures of a page of signature manifests. No more than
+	// MaxSignatureAttempts blobs are fetched. If set to less than or equals
+	// to one, the blobs are fetched sequentially. It has no effect when
+	// PrioritizeSignatures is set.
+	SignatureFetchConcurrency int
+
+	// ResolveTimeout bounds the resolution of ArtifactReference to the
+	// manifest descriptor of the artifact, including the retrieval of the
+	// manifest. If set to less than or equals to zero, the resolution is only
+	// bounded by the context.
+	ResolveTimeout time.Duration
+
+	// FetchTimeout bounds each retrieval of a signature envelope blob. If set
+	// to less than or equals to zero, the retrievals are only bounded by the
+	// context.
+	FetchTimeout time.Duration
+
+	// VerifyTimeout bounds each verification of a signature by the
+	// verifier, including the verification plugin and the revocation checks.
+	// If set to less than or equals to zero, the verifications are only
+	// bounded by the context.
+	VerifyTimeout time.Duration
+
+	// DeadlineMargin stops the verification before the deadline of the
+	// context, once the deadline is within DeadlineMargin, instead of
+	// starting the verification of another signature. If the verification
+	// stops at the deadline before a signature is verified successfully,
+	// [DeadlineExceededError] is returned with the outcomes of the signatures
+	// processed so far, and the signatures left unprocessed are reported in
+	// its Skipped and in the SkippedSignatures of the outcomes. If set to
+	// less than or equals to zero, the verification stops when the deadline
+	// is reached.
+	DeadlineMargin time.Duration
+
+	// MaxWorkingSetSize caps the memory in bytes held by the signature
+	// envelopes during the verification, including the prefetched envelopes
+	// and, for the signature being verified, the payload and the certificate
+	// chain decoded from its envelope, estimated at the size of the envelope.
+	// The prefetched envelopes exceeding the budget are dropped and fetched
+	// again when verified. A signature whose verification alone exceeds the
+	// budget fails verification. If set to less than or equals to zero, the
+	// memory is not capped.
+	MaxWorkingSetSize int64
+
+	// StrictSubject validates the subject descriptor of each signature
+	// manifest against the resolved artifact before fetching its signature
+	// envelope. A signature whose subject has a different media type, digest
+	// or size, or annotations not matching the artifact, fails verification
+	// with [SubjectMismatchError]. The repository must be able to fetch the
+	// subjects of the signature manifests, as the repositories returned by
+	// [registry.NewRepository] are.
+	StrictSubject bool
+
+	// ActionOverrides overrides the actions of the verification level of the
+	// applicable trust policy for this verification, e.g. to log instead of
+	// enforcing the expiry check for a forensic run, without crafting a trust
+	// policy. The same rules as the override of a trust policy statement
+	// apply. Every override is recorded in the ActionOverrides of the
+	// verification outcomes and in the audit events. The overrides do not
+	// apply to the "skip" verification level.
+	ActionOverrides map[trustpolicy.ValidationType]trustpolicy.ValidationAction
+
+	// SignatureAlgorithms, if set, restricts the signature algorithms
+	// accepted for this verification, in addition to the key requirements of
+	// the trust policy, e.g. [signature.AlgorithmES384] only for a
+	// high-security caller. A signature with another algorithm fails
+	// verification, whatever the verification level of the trust policy,
+	// except for the "skip" verification level.
+	SignatureAlgorithms []signature.Algorithm
+
+	// WarnUnsupportedEnvelopes reports the signatures skipped for an
+	// unsupported envelope media type, e.g. an envelope version released
+	// after this library, as warnings in the verification outcomes and the
+	// logs, to monitor the adoption of new envelope versions. The skipped
+	// signatures are reported in the SkippedSignatures of the verification
+	// outcomes regardless, and do not fail the verification.
+	WarnUnsupportedEnvelopes bool
+
+	// TrustStore, if set, is used for this verification in place of, or in
+	// addition to, the trust store of the verifier, as set by
+	// TrustStoreMode, e.g. to pin the trusted certificates of a test or of an
+	// emergency operation without editing the trust stores on disk. The
+	// named stores of the trust policy are read from it. Verifiers not
+	// supporting per-call trust stores ignore it.
+	TrustStore truststore.X509TrustStore
+
+	// TrustStoreMode sets how TrustStore is combined with the trust store of
+	// the verifier. It defaults to [TrustStoreOverride].
+	TrustStoreMode TrustStoreMode
+
+	// Progress, if set, is called with the progress events of the
+	// verification, such as [ProgressSignatureFetched].
+	Progress ProgressFunc
 }
 
 // VerifyBlobOptions contains parameters for [notation.VerifyBlob].
@@ -433,7 +801,10 @@ type VerifyBlobOptions struct {
 // and upon successful verification, it returns the descriptor of the blob.
 // For more details on signature verification, see
 // https://github.com/notaryproject/notaryproject/blob/main/specs/trust-store-trust-policy.md#signature-verification
-func VerifyBlob(ctx context.Context, blobVerifier BlobVerifier, blobReader io.Reader, signature []byte, verifyBlobOpts VerifyBlobOptions) (ocispec.Descriptor, *VerificationOutcome, error) {
+func VerifyBlob(ctx context.Context, blobVerifier BlobVerifier, blobReader io.Reader, signature []byte, verifyBlobOpts VerifyBlobOptions) (_ ocispec.Descriptor, outcome *VerificationOutcome, err error) {
+	ctx = withOperation(ctx, operationVerifyBlob)
+	defer func() { endOperation(ctx, err, outcome) }()
+
 	if blobVerifier == nil {
 		return ocispec.Descriptor{}, nil, errors.New("blobVerifier cannot be nil")
 	}
@@ -467,7 +838,12 @@ func VerifyBlob(ctx context.Context, blobVerifier BlobVerifier, blobReader io.Re
 // successful signature verification outcome.
 // For more details on signature verification, see
 // https://github.com/notaryproject/notaryproject/blob/main/specs/trust-store-trust-policy.md#signature-verification
-func Verify(ctx context.Context, verifier Verifier, repo registry.Repository, verifyOpts VerifyOptions) (ocispec.Descriptor, []*VerificationOutcome, error) {
+func Verify(ctx context.Context, verifier Verifier, repo registry.Repository, verifyOpts VerifyOptions) (_ ocispec.Descriptor, outcomes []*VerificationOutcome, err error) {
+	ctx = withOperation(ctx, operationVerify)
+	defer func() { endOperation(ctx, err, outcomes...) }()
+	if ref, err := orasRegistry.ParseReference(verifyOpts.ArtifactReference); err == nil {
+		ctx = log.WithFields(ctx, log.Fields{log.FieldRepository: ref.Registry + "/" + ref.Repository})
+	}
 	logger := log.GetLogger(ctx)
 
 	// sanity check
@@ -480,38 +856,45 @@ func Verify(ctx context.Context, verifier Verifier, repo registry.Repository, ve
 	if verifyOpts.MaxSignatureAttempts <= 0 {
 		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("verifyOptions.MaxSignatureAttempts expects a positive number, got %d", verifyOpts.MaxSignatureAttempts)}
 	}
+	subjectFetcher, ok := repo.(signatureSubjectFetcher)
+	if verifyOpts.StrictSubject && !ok {
+		return ocispec.Descriptor{}, nil, errors.New("verifyOptions.StrictSubject requires a repo able to fetch signature subjects")
+	}
 
 	// opts to be passed in verifier.Verify()
 	opts := VerifierVerifyOptions{
-		ArtifactReference: verifyOpts.ArtifactReference,
-		PluginConfig:      verifyOpts.PluginConfig,
-		UserMetadata:      verifyOpts.UserMetadata,
+		ArtifactReference:   verifyOpts.ArtifactReference,
+		PluginConfig:        verifyOpts.PluginConfig,
+		UserMetadata:        verifyOpts.UserMetadata,
+		ActionOverrides:     verifyOpts.ActionOverrides,
+		SignatureAlgorithms: verifyOpts.SignatureAlgorithms,
+		TrustStore:          verifyOpts.TrustStore,
+		TrustStoreMode:      verifyOpts.TrustStoreMode,
+		Progress:            verifyOpts.Progress,
 	}
-	if skipChecker, ok := verifier.(verifySkipper); ok {
-		logger.Info("Checking whether signature verification should be skipped or not")
-		skip, verificationLevel, err := skipChecker.SkipVerify(ctx, opts)
-		if err != nil {
-			return ocispec.Descriptor{}, nil, err
-		}
-		if skip {
-			logger.Infoln("Signature verification skipped for", verifyOpts.ArtifactReference)
-			return ocispec.Descriptor{}, []*VerificationOutcome{{VerificationLevel: verificationLevel}}, nil
-		}
-		logger.Info("Check over. The signature verification level is not set to 'skip' in the trust policy.")
+	skip, verificationLevel, endorser, err := verificationRequirements(ctx, verifier, opts)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	if skip {
+		logger.Infoln("Signature verification skipped for", verifyOpts.ArtifactReference)
+		return ocispec.Descriptor{}, []*VerificationOutcome{{VerificationLevel: verificationLevel}}, nil
 	}
 
 	// get artifact descriptor
 	artifactRef := verifyOpts.ArtifactReference
 	ref, err := orasRegistry.ParseReference(artifactRef)
 	if err != nil {
-		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: err.Error()}
+		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: err.Error(), InnerError: err}
 	}
 	if ref.Reference == "" {
 		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: "reference is missing digest or tag"}
 	}
-	artifactDescriptor, err := repo.Resolve(ctx, ref.Reference)
+	resolveCtx, cancelResolve := withTimeout(ctx, verifyOpts.ResolveTimeout)
+	defer cancelResolve()
+	artifactDescriptor, err := repo.Resolve(resolveCtx, ref.Reference)
 	if err != nil {
-		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: err.Error()}
+		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: err.Error(), InnerError: err}
 	}
 	if ref.ValidateReferenceAsDigest() != nil {
 		// artifactRef is not a digest reference
@@ -520,34 +903,152 @@ func Verify(ctx context.Context, verifier Verifier, repo registry.Repository, ve
 	} else if ref.Reference != artifactDescriptor.Digest.String() {
 		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("user input digest %s does not match the resolved digest %s", ref.Reference, artifactDescriptor.Digest.String())}
 	}
+	ctx = log.WithFields(ctx, log.Fields{log.FieldArtifact: artifactDescriptor.Digest})
+	logger = log.GetLogger(ctx)
+
+	// the resolved descriptor lacks the artifact type and the annotations of
+	// the manifest, which verification plugins may enforce policies on
+	subjectDescriptor := artifactDescriptor
+	if describer, ok := repo.(artifactDescriber); ok {
+		subjectDescriptor, err = describer.DescribeArtifact(resolveCtx, artifactDescriptor)
+		if errors.Is(err, errdef.ErrUnsupported) {
+			// a wrapped repository may not describe artifacts
+			subjectDescriptor, err = artifactDescriptor, nil
+		}
+		if err != nil {
+			return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve the manifest of %q from the Repository, error : %v", artifactRef, err.Error()), InnerError: err}
+		}
+	}
+	if subjectDescriptor.ArtifactType != "" {
+		// the trust policy statements restricted to the artifact type take
+		// precedence
+		opts.ArtifactType = subjectDescriptor.ArtifactType
+		skip, verificationLevel, endorser, err = verificationRequirements(ctx, verifier, opts)
+		if err != nil {
+			return ocispec.Descriptor{}, nil, err
+		}
+		if skip {
+			logger.Infof("Signature verification skipped for %s of artifact type %s", verifyOpts.ArtifactReference, subjectDescriptor.ArtifactType)
+			return artifactDescriptor, []*VerificationOutcome{{VerificationLevel: verificationLevel}}, nil
+		}
+	}
 
 	var verificationSucceeded bool
 	var verificationOutcomes []*VerificationOutcome
 	var verificationFailedErrorArray = []error{ErrorVerificationFailed{}}
 	errExceededMaxVerificationLimit := ErrorVerificationFailed{Msg: fmt.Sprintf("signature evaluation stopped. The configured limit of %d signatures to verify per artifact exceeded", verifyOpts.MaxSignatureAttempts)}
 	numOfSignatureProcessed := 0
-
-	// get signature manifests
-	logger.Debug("Fetching signature manifests")
-	err = repo.ListSignatures(ctx, artifactDescriptor, func(signatureManifests []ocispec.Descriptor) error {
-		// process signatures
-		for _, sigManifestDesc := range signatureManifests {
+	ws := newWorkingSet(verifyOpts.MaxWorkingSetSize)
+	var skippedSignatures []SkippedSignature
+	var failedOutcomes []*VerificationOutcome
+	var deadlineSkipped []SkippedSignature
+
+	// process signatures
+	processSignatures := func(signatureManifests []ocispec.Descriptor) error {
+		var fetches []*fetchedSignature
+		if verifyOpts.SignatureFetchConcurrency > 1 {
+			toFetch := signatureManifests
+			if remaining := verifyOpts.MaxSignatureAttempts - numOfSignatureProcessed; len(toFetch) > remaining {
+				toFetch = toFetch[:max(remaining, 0)]
+			}
+			if len(toFetch) > 1 {
+				var stop func()
+				fetches, stop = prefetchSignatureBlobs(ctx, repo, toFetch, verifyOpts.SignatureFetchConcurrency, verifyOpts.FetchTimeout, ws)
+				defer stop()
+			}
+		}
+		for i, sigManifestDesc := range signatureManifests {
 			if numOfSignatureProcessed >= verifyOpts.MaxSignatureAttempts {
 				break
 			}
+			if err := deadlineReached(ctx, verifyOpts.DeadlineMargin); err != nil {
+				if errors.Is(err, context.DeadlineExceeded) {
+					unprocessed := signatureManifests[i:]
+					if remaining := verifyOpts.MaxSignatureAttempts - numOfSignatureProcessed; len(unprocessed) > remaining {
+						unprocessed = unprocessed[:remaining]
+					}
+					deadlineSkipped = deadlineSkippedSignatures(unprocessed)
+				}
+				return err
+			}
 			numOfSignatureProcessed++
 			logger.Infof("Processing signature with manifest mediaType: %v and digest: %v", sigManifestDesc.MediaType, sigManifestDesc.Digest)
+			if verifyOpts.StrictSubject {
+				err := checkSignatureSubject(ctx, subjectFetcher, sigManifestDesc, artifactDescriptor, verifyOpts.FetchTimeout)
+				var mismatchErr SubjectMismatchError
+				if errors.As(err, &mismatchErr) {
+					if fetches != nil {
+						ws.remove(fetches[i].take())
+					}
+					logger.Warnf("Signature %v failed verification with error: %v", sigManifestDesc.Digest, err)
+					verificationFailedErrorArray = append(verificationFailedErrorArray, fmt.Errorf("failed to verify signature with digest %v, %w", sigManifestDesc.Digest, err))
+					continue
+				}
+				if err != nil {
+					return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve the subject of the signature with digest %q associated with %q from the Repository, error : %v", sigManifestDesc.Digest, artifactRef, err.Error()), InnerError: err}
+				}
+			}
 			// get signature envelope
-			sigBlob, sigDesc, err := repo.FetchSignatureBlob(ctx, sigManifestDesc)
+			var sigBlob []byte
+			var sigDesc ocispec.Descriptor
+			var err error
+			var held int64
+			var pending []*fetchedSignature
+			if fetches != nil {
+				sigBlob, sigDesc, err = fetches[i].wait()
+				held = fetches[i].take()
+				pending = fetches[i+1:]
+			}
+			if fetches == nil || fetches[i].spilled {
+				sigBlob, sigDesc, err = fetchSignatureBlob(ctx, repo, sigManifestDesc, verifyOpts.FetchTimeout)
+			}
+			ws.remove(held)
 			if err != nil {
-				return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve digital signature with digest %q associated with %q from the Repository, error : %v", sigManifestDesc.Digest, artifactRef, err.Error())}
+				return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve digital signature with digest %q associated with %q from the Repository, error : %v", sigManifestDesc.Digest, artifactRef, err.Error()), InnerError: err}
+			}
+			verifyOpts.Progress.report(ProgressEvent{
+				Type:      ProgressSignatureFetched,
+				Artifact:  artifactDescriptor,
+				Signature: sigManifestDesc,
+				Count:     numOfSignatureProcessed,
+				Total:     verifyOpts.MaxSignatureAttempts,
+			})
+
+			// skip the signatures with an unsupported envelope, e.g. of a
+			// future envelope version
+			if skipped, ok := unsupportedEnvelope(sigManifestDesc.Digest, sigDesc.MediaType); ok {
+				logSkippedSignature(ctx, skipped, verifyOpts.WarnUnsupportedEnvelopes)
+				skippedSignatures = append(skippedSignatures, skipped)
+				continue
 			}
 
 			// using signature media type fetched from registry
 			opts.SignatureMediaType = sigDesc.MediaType
+			opts.SignatureManifestAnnotations = sigManifestDesc.Annotations
+
+			// bound the memory held by the signatures
+			cost, err := ws.signatureCost(sigBlob)
+			if err != nil {
+				logger.Warnf("Signature %v failed verification with error: %v", sigManifestDesc.Digest, err)
+				verificationFailedErrorArray = append(verificationFailedErrorArray, fmt.Errorf("failed to verify signature with digest %v, %w", sigManifestDesc.Digest, err))
+				continue
+			}
+			ws.admit(cost, pending)
 
 			// verify each signature
-			outcome, err := verifier.Verify(ctx, artifactDescriptor, sigBlob, opts)
+			verifyCtx, cancelVerify := withTimeout(ctx, verifyOpts.VerifyTimeout)
+			outcome, err := verifier.Verify(verifyCtx, subjectDescriptor, sigBlob, opts)
+			cancelVerify()
+			ws.remove(cost)
+			verifyOpts.Progress.report(ProgressEvent{
+				Type:      ProgressSignatureVerified,
+				Artifact:  artifactDescriptor,
+				Signature: sigManifestDesc,
+				Count:     numOfSignatureProcessed,
+				Total:     verifyOpts.MaxSignatureAttempts,
+				Error:     err,
+				Outcome:   outcome,
+			})
 			if err != nil {
 				logger.Warnf("Signature %v failed verification with error: %v", sigManifestDesc.Digest, err)
 				if outcome == nil {
@@ -556,8 +1057,25 @@ func Verify(ctx context.Context, verifier Verifier, repo registry.Repository, ve
 				}
 				outcome.Error = fmt.Errorf("failed to verify signature with digest %v, %w", sigManifestDesc.Digest, outcome.Error)
 				verificationFailedErrorArray = append(verificationFailedErrorArray, outcome.Error)
+				failedOutcomes = append(failedOutcomes, outcome)
 				continue
 			}
+			if endorser != nil {
+				endorsement, err := verifyEndorsement(ctx, endorser, repo, sigManifestDesc, opts, verifyOpts.MaxSignatureAttempts)
+				if err != nil {
+					var retrievalErr ErrorSignatureRetrievalFailed
+					if errors.As(err, &retrievalErr) {
+						return err
+					}
+					logger.Warnf("Signature %v failed verification with error: %v", sigManifestDesc.Digest, err)
+					verificationFailedErrorArray = append(verificationFailedErrorArray, fmt.Errorf("failed to verify signature with digest %v, %w", sigManifestDesc.Digest, err))
+					continue
+				}
+				// the outcome may be shared with the verification cache
+				endorsedOutcome := *outcome
+				endorsedOutcome.Endorsement = endorsement
+				outcome = &endorsedOutcome
+			}
 			// at this point, the signature is verified successfully
 			verificationSucceeded = true
 
@@ -573,11 +1091,30 @@ func Verify(ctx context.Context, verifier Verifier, repo registry.Repository, ve
 			return errExceededMaxVerificationLimit
 		}
 		return nil
-	})
+	}
+
+	// get signature manifests
+	logger.Debug("Fetching signature manifests")
+	if verifyOpts.PrioritizeSignatures {
+		err = verifyPrioritizedSignatures(ctx, verifier, repo, artifactDescriptor, verifyOpts, opts, processSignatures)
+	} else {
+		err = repo.ListSignatures(ctx, artifactDescriptor, processSignatures)
+	}
 	if err != nil && !errors.Is(err, errDoneVerification) {
 		if errors.Is(err, errExceededMaxVerificationLimit) {
 			return ocispec.Descriptor{}, verificationOutcomes, err
 		}
+		if errors.Is(err, context.DeadlineExceeded) && (deadlineSkipped != nil || ctx.Err() != nil) {
+			// partial result of the verification stopped at the deadline
+			logger.Warnf("Signature verification stopped at the deadline for artifact %v", artifactDescriptor.Digest)
+			outcomes := withSkippedSignatures(failedOutcomes, skippedSignatures, verifyOpts.WarnUnsupportedEnvelopes)
+			outcomes = withSkippedSignatures(outcomes, deadlineSkipped, true)
+			return ocispec.Descriptor{}, outcomes, DeadlineExceededError{
+				Processed:  numOfSignatureProcessed,
+				Skipped:    deadlineSkipped,
+				InnerError: err,
+			}
+		}
 		return ocispec.Descriptor{}, nil, err
 	}
 
@@ -589,11 +1126,16 @@ func Verify(ctx context.Context, verifier Verifier, repo registry.Repository, ve
 	// Verification Failed
 	if !verificationSucceeded {
 		logger.Debugf("Signature verification failed for all the signatures associated with artifact %v", artifactDescriptor.Digest)
-		return ocispec.Descriptor{}, verificationOutcomes, errors.Join(verificationFailedErrorArray...)
+		for _, skipped := range skippedSignatures {
+			verificationFailedErrorArray = append(verificationFailedErrorArray, errors.New(skipped.String()))
+		}
+		verificationOutcomes = withSkippedSignatures(verificationOutcomes, skippedSignatures, verifyOpts.WarnUnsupportedEnvelopes)
+		return ocispec.Descriptor{}, withTransportWarnings(ctx, repo, verificationOutcomes), errors.Join(verificationFailedErrorArray...)
 	}
 
 	// Verification Succeeded
-	return artifactDescriptor, verificationOutcomes, nil
+	verificationOutcomes = withSkippedSignatures(verificationOutcomes, skippedSignatures, verifyOpts.WarnUnsupportedEnvelopes)
+	return artifactDescriptor, withTransportWarnings(ctx, repo, verificationOutcomes), nil
 }
 
 func generateAnnotations(signerInfo *signature.SignerInfo, annotations map[string]string) (map[string]string, error) {
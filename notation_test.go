@@ -22,6 +22,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -371,6 +372,34 @@ func TestVerifyDigestNotMatchResolve(t *testing.T) {
 	}
 }
 
+func TestVerifyRegistryErrors(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}
+	opts := VerifyOptions{ArtifactReference: mock.SampleArtifactUri, MaxSignatureAttempts: 50}
+
+	// resolve failure
+	repo := mock.NewRepository()
+	repo.ResolveError = registry.AuthenticationError{InnerError: errors.New("unauthorized")}
+	_, _, err := Verify(context.Background(), &verifier, repo, opts)
+	var retrievalErr SignatureRetrievalFailedError
+	if !errors.As(err, &retrievalErr) {
+		t.Fatalf("Verify() error = %v, want SignatureRetrievalFailedError", err)
+	}
+	var authErr registry.AuthenticationError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("Verify() error = %v, want registry.AuthenticationError", err)
+	}
+
+	// fetch signature failure
+	repo = mock.NewRepository()
+	repo.FetchSignatureBlobError = registry.RateLimitedError{InnerError: errors.New("too many requests")}
+	_, _, err = Verify(context.Background(), &verifier, repo, opts)
+	var rateLimitedErr registry.RateLimitedError
+	if !errors.As(err, &rateLimitedErr) {
+		t.Fatalf("Verify() error = %v, want registry.RateLimitedError", err)
+	}
+}
+
 func TestSignDigestNotMatchResolve(t *testing.T) {
 	repo := mock.NewRepository()
 	repo.MissMatchDigest = true
@@ -451,6 +480,31 @@ func TestVerifyValid(t *testing.T) {
 	}
 }
 
+func TestVerifyWrappedRepository(t *testing.T) {
+	var calls []string
+	repo := registry.Wrap(mock.NewRepository(), registry.MetricsMiddleware(registry.MetricsRecorderFunc(func(ctx context.Context, method string, duration time.Duration, err error) {
+		calls = append(calls, method)
+	})))
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}
+
+	// the wrapped mock repository describes neither artifacts nor signature
+	// envelope blobs
+	opts := VerifyOptions{
+		ArtifactReference:    mock.SampleArtifactUri,
+		MaxSignatureAttempts: 50,
+		PrioritizeSignatures: true,
+		SignatureMediaTypes:  []string{"application/jose+json"},
+	}
+	if _, _, err := Verify(context.Background(), &verifier, repo, opts); err != nil {
+		t.Fatalf("expected nil error, but got: %v", err)
+	}
+	want := []string{"Resolve", "DescribeArtifact", "ListSignatures", "FetchSignatureBlobDescriptor", "FetchSignatureBlob"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("Verify() called %v, want %v", calls, want)
+	}
+}
+
 func TestVerifySkip(t *testing.T) {
 	repo := mock.NewRepository()
 	policyDocument := dummyPolicyDocument()
@@ -838,3 +892,57 @@ func TestUserMetadata(t *testing.T) {
 		}
 	})
 }
+
+// keyDigestSigner is a BlobSigner with a signing key of hash algorithm
+// keyDigestAlgo. It records the descriptor it signs.
+type keyDigestSigner struct {
+	dummySigner
+	keyDigestAlgo digest.Algorithm
+	desc          ocispec.Descriptor
+}
+
+func (s *keyDigestSigner) SignBlob(ctx context.Context, genDesc BlobDescriptorGenerator, opts SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	desc, err := genDesc(s.keyDigestAlgo)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.desc = desc
+	return s.Sign(ctx, desc, opts)
+}
+
+func TestSignBlobDigestAlgorithm(t *testing.T) {
+	testCases := []struct {
+		name       string
+		digestAlgo digest.Algorithm
+		want       digest.Algorithm
+		errMsg     string
+	}{
+		{name: "key hash algorithm", want: digest.SHA384},
+		{name: "stronger algorithm", digestAlgo: digest.SHA512, want: digest.SHA512},
+		{name: "same algorithm", digestAlgo: digest.SHA384, want: digest.SHA384},
+		{name: "weaker algorithm", digestAlgo: digest.SHA256, errMsg: `digest algorithm "sha256" is weaker than the hash algorithm "sha384" of the signing key`},
+		{name: "unsupported algorithm", digestAlgo: "md5", errMsg: `unsupported digest algorithm "md5"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &keyDigestSigner{keyDigestAlgo: digest.SHA384}
+			_, _, err := SignBlob(context.Background(), s, strings.NewReader("some content"), SignBlobOptions{
+				SignerSignOptions: SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+				ContentMediaType:  "text/plain",
+				DigestAlgorithm:   tc.digestAlgo,
+			})
+			if tc.errMsg != "" {
+				if err == nil || err.Error() != tc.errMsg {
+					t.Fatalf("SignBlob() error = %v, want %s", err, tc.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SignBlob() error = %v", err)
+			}
+			if got := s.desc.Digest.Algorithm(); got != tc.want {
+				t.Fatalf("signed digest algorithm = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
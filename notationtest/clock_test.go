@@ -0,0 +1,106 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestClock(t *testing.T) {
+	now := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := notationtest.NewClock(now)
+	if got := c.Now(); !got.Equal(now) {
+		t.Fatalf("Now() = %v, want %v", got, now)
+	}
+	c.Advance(time.Hour)
+	if got, want := c.Now(), now.Add(time.Hour); !got.Equal(want) {
+		t.Fatalf("Now() = %v, want %v", got, want)
+	}
+	c.Set(now)
+	if got := c.Now(); !got.Equal(now) {
+		t.Fatalf("Now() = %v, want %v", got, now)
+	}
+}
+
+func TestSignAndVerifyWithClock(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	signer, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := notationtest.NewClock(time.Now())
+	trustStore := notationtest.NewTrustStore().Add(truststore.TypeCA, "test", signer.Root())
+	v, err := verifier.NewVerifierWithOptions(trustStore, verifier.VerifierOptions{
+		OCITrustPolicy: notationtest.TrustPolicy("test"),
+		Clock:          c,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(t *testing.T, tag string, expiry time.Duration) notation.VerifyOptions {
+		t.Helper()
+		artifactDesc, err := repo.PushArtifact(ctx, tag, map[string]string{"tag": tag})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := notation.Sign(ctx, signer, repo, notation.SignOptions{
+			SignerSignOptions: notation.SignerSignOptions{
+				SignatureMediaType: jws.MediaTypeEnvelope,
+				ExpiryDuration:     expiry,
+				Clock:              c,
+			},
+			ArtifactReference: "localhost:5000/notationtest:" + tag,
+		}); err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return notation.VerifyOptions{
+			ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+			MaxSignatureAttempts: 10,
+		}
+	}
+
+	t.Run("signature expiry", func(t *testing.T) {
+		c.Set(time.Now())
+		verifyOpts := sign(t, "expiry", time.Hour)
+		if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		c.Advance(2 * time.Hour)
+		if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); !errors.As(err, &notation.ErrorVerificationFailed{}) {
+			t.Fatalf("Verify() error = %v, want ErrorVerificationFailed", err)
+		}
+	})
+
+	t.Run("certificate validity period", func(t *testing.T) {
+		c.Set(time.Now())
+		verifyOpts := sign(t, "validity", 0)
+		if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		c.Set(signer.CertificateChain[0].NotAfter.Add(time.Hour))
+		if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); !errors.As(err, &notation.ErrorVerificationFailed{}) {
+			t.Fatalf("Verify() error = %v, want ErrorVerificationFailed", err)
+		}
+	})
+}
@@ -0,0 +1,386 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+var (
+	// FixtureKeySpecs are the key specs supported by notation, for which
+	// fixtures are generated by default.
+	FixtureKeySpecs = []signature.KeySpec{
+		{Type: signature.KeyTypeRSA, Size: 2048},
+		{Type: signature.KeyTypeRSA, Size: 3072},
+		{Type: signature.KeyTypeRSA, Size: 4096},
+		{Type: signature.KeyTypeEC, Size: 256},
+		{Type: signature.KeyTypeEC, Size: 384},
+		{Type: signature.KeyTypeEC, Size: 521},
+	}
+
+	// FixtureEnvelopeMediaTypes are the envelope media types supported by
+	// notation, for which fixtures are generated by default.
+	FixtureEnvelopeMediaTypes = []string{jws.MediaTypeEnvelope, cose.MediaTypeEnvelope}
+
+	// FixtureSigningTime is the default signing time of the fixtures.
+	FixtureSigningTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// FixtureSubject is the default subject of the fixtures.
+	FixtureSubject = ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("notation golden fixture"),
+		Size:      23,
+	}
+)
+
+// fixtureSigningAgent is the signing agent of the fixtures.
+const fixtureSigningAgent = "notation-go golden fixture"
+
+// FixtureOptions contains parameters for [GenerateFixtures].
+type FixtureOptions struct {
+	// KeySpecs are the key specs of the fixtures. If empty,
+	// [FixtureKeySpecs] are used.
+	KeySpecs []signature.KeySpec
+
+	// EnvelopeMediaTypes are the envelope media types of the fixtures. If
+	// empty, [FixtureEnvelopeMediaTypes] are used.
+	EnvelopeMediaTypes []string
+
+	// SigningTime is the signing time of the fixtures. If zero,
+	// [FixtureSigningTime] is used.
+	SigningTime time.Time
+
+	// Subject is the descriptor of the artifact signed by the fixtures. If
+	// its digest is empty, [FixtureSubject] is used.
+	Subject ocispec.Descriptor
+}
+
+// Fixture is a golden signature fixture.
+type Fixture struct {
+	// Name identifies the fixture by envelope type and key spec, e.g.
+	// "jws-rsa-2048".
+	Name string
+
+	// KeySpec is the key spec of the signing key.
+	KeySpec signature.KeySpec
+
+	// EnvelopeMediaType is the media type of the signature envelope.
+	EnvelopeMediaType string
+
+	// Subject is the descriptor of the signed artifact.
+	Subject ocispec.Descriptor
+
+	// Envelope is the signature envelope.
+	Envelope []byte
+
+	// Manifest is the signature manifest referencing the signature envelope
+	// and the subject, as pushed to a registry.
+	Manifest []byte
+
+	// ManifestDescriptor is the descriptor of the signature manifest.
+	ManifestDescriptor ocispec.Descriptor
+
+	// CertificateChain is the certificate chain of the signature, from the
+	// signing certificate to the root certificate.
+	CertificateChain []*x509.Certificate
+}
+
+// GenerateFixtures generates a fixture for each key spec and envelope media
+// type. The fixtures of a key spec share a certificate chain.
+//
+// The fixtures are reproducible in everything but the keys and the signature
+// values, which are random: the subject, the signing time, the subjects, the
+// serial numbers and the validity periods of the certificates are fixed, so
+// fixtures generated with the same options differ only by their keys and
+// signatures.
+func GenerateFixtures(ctx context.Context, opts FixtureOptions) ([]Fixture, error) {
+	keySpecs := opts.KeySpecs
+	if len(keySpecs) == 0 {
+		keySpecs = FixtureKeySpecs
+	}
+	mediaTypes := opts.EnvelopeMediaTypes
+	if len(mediaTypes) == 0 {
+		mediaTypes = FixtureEnvelopeMediaTypes
+	}
+	signingTime := opts.SigningTime
+	if signingTime.IsZero() {
+		signingTime = FixtureSigningTime
+	}
+	subject := opts.Subject
+	if subject.Digest == "" {
+		subject = FixtureSubject
+	}
+	payload, err := json.Marshal(envelope.Payload{TargetArtifact: envelope.SanitizeTargetArtifact(subject)})
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []Fixture
+	for _, keySpec := range keySpecs {
+		key, certChain, err := generateFixtureCertificateChain(keySpec, signingTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate the certificate chain of key spec %s: %w", keySpecName(keySpec), err)
+		}
+		localSigner, err := signature.NewLocalSigner(certChain, key)
+		if err != nil {
+			return nil, err
+		}
+		for _, mediaType := range mediaTypes {
+			fixture, err := generateFixture(ctx, localSigner, certChain, keySpec, mediaType, subject, payload, signingTime)
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, fixture)
+		}
+	}
+	return fixtures, nil
+}
+
+// generateFixture signs the payload and generates the signature manifest.
+func generateFixture(ctx context.Context, localSigner signature.Signer, certChain []*x509.Certificate, keySpec signature.KeySpec, mediaType string, subject ocispec.Descriptor, payload []byte, signingTime time.Time) (Fixture, error) {
+	name, err := fixtureName(keySpec, mediaType)
+	if err != nil {
+		return Fixture{}, err
+	}
+	sigEnv, err := signature.NewEnvelope(mediaType)
+	if err != nil {
+		return Fixture{}, err
+	}
+	sig, err := sigEnv.Sign(&signature.SignRequest{
+		Payload: signature.Payload{
+			ContentType: envelope.MediaTypePayloadV1,
+			Content:     payload,
+		},
+		Signer:        localSigner,
+		SigningTime:   signingTime,
+		SigningScheme: signature.SigningSchemeX509,
+		SigningAgent:  fixtureSigningAgent,
+	})
+	if err != nil {
+		return Fixture{}, fmt.Errorf("failed to sign fixture %s: %w", name, err)
+	}
+
+	// the annotations are the ones generated by notation.Sign
+	var thumbprints []string
+	for _, cert := range certChain {
+		checkSum := sha256.Sum256(cert.Raw)
+		thumbprints = append(thumbprints, hex.EncodeToString(checkSum[:]))
+	}
+	thumbprintsJSON, err := json.Marshal(thumbprints)
+	if err != nil {
+		return Fixture{}, err
+	}
+	annotations := map[string]string{
+		envelope.AnnotationX509ChainThumbprint: string(thumbprintsJSON),
+		ocispec.AnnotationCreated:              signingTime.UTC().Format(time.RFC3339),
+	}
+
+	store := memory.New()
+	_, manifestDesc, err := registry.NewRepository(store).PushSignature(ctx, mediaType, sig, subject, annotations)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("failed to generate the signature manifest of fixture %s: %w", name, err)
+	}
+	manifest, err := content.FetchAll(ctx, store, manifestDesc)
+	if err != nil {
+		return Fixture{}, err
+	}
+	return Fixture{
+		Name:               name,
+		KeySpec:            keySpec,
+		EnvelopeMediaType:  mediaType,
+		Subject:            subject,
+		Envelope:           sig,
+		Manifest:           manifest,
+		ManifestDescriptor: manifestDesc,
+		CertificateChain:   certChain,
+	}, nil
+}
+
+// fixtureName returns the name of the fixture of the key spec and envelope
+// media type.
+func fixtureName(keySpec signature.KeySpec, mediaType string) (string, error) {
+	var envelopeType string
+	switch mediaType {
+	case jws.MediaTypeEnvelope:
+		envelopeType = "jws"
+	case cose.MediaTypeEnvelope:
+		envelopeType = "cose"
+	default:
+		return "", fmt.Errorf("unsupported envelope media type %q", mediaType)
+	}
+	return envelopeType + "-" + keySpecName(keySpec), nil
+}
+
+// keySpecName returns the name of the key spec, e.g. "rsa-2048".
+func keySpecName(keySpec signature.KeySpec) string {
+	keyType := "rsa"
+	if keySpec.Type == signature.KeyTypeEC {
+		keyType = "ec"
+	}
+	return fmt.Sprintf("%s-%d", keyType, keySpec.Size)
+}
+
+// generateFixtureCertificateChain generates a signing key and its
+// certificate chain of the key spec, valid from a day before the signing time
+// for 10 years.
+func generateFixtureCertificateChain(keySpec signature.KeySpec, signingTime time.Time) (crypto.Signer, []*x509.Certificate, error) {
+	rootKey, err := generateFixtureKey(keySpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	leafKey, err := generateFixtureKey(keySpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	notBefore := signingTime.Add(-24 * time.Hour)
+	notAfter := notBefore.AddDate(10, 0, 0)
+	name := func(cn string) pkix.Name {
+		return pkix.Name{
+			Organization: []string{"Notary"},
+			Country:      []string{"US"},
+			Province:     []string{"WA"},
+			Locality:     []string{"Seattle"},
+			CommonName:   cn,
+		}
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               name("Notation Fixture Root " + keySpecName(keySpec)),
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            1,
+	}
+	root, err := createFixtureCertificate(rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               name("Notation Fixture Leaf " + keySpecName(keySpec)),
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		BasicConstraintsValid: true,
+	}
+	leaf, err := createFixtureCertificate(leafTemplate, root, leafKey.Public(), rootKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return leafKey, []*x509.Certificate{leaf, root}, nil
+}
+
+// generateFixtureKey generates a key of the key spec.
+func generateFixtureKey(keySpec signature.KeySpec) (crypto.Signer, error) {
+	switch keySpec.Type {
+	case signature.KeyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, keySpec.Size)
+	case signature.KeyTypeEC:
+		var curve elliptic.Curve
+		switch keySpec.Size {
+		case 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC key size %d", keySpec.Size)
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key type %v", keySpec.Type)
+	}
+}
+
+// createFixtureCertificate creates a certificate from the template, signed by
+// the parent.
+func createFixtureCertificate(template, parent *x509.Certificate, pub crypto.PublicKey, parentKey crypto.Signer) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, parentKey)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// WriteFixtures writes each fixture to a directory named after the fixture in
+// dir, with the files:
+//   - envelope.jws or envelope.cose: the signature envelope
+//   - manifest.json: the signature manifest
+//   - subject.json: the descriptor of the signed artifact
+//   - certchain.pem: the certificate chain, from the signing certificate to
+//     the root certificate
+//   - root.pem: the root certificate, to add to a trust store
+func WriteFixtures(dir string, fixtures []Fixture) error {
+	for _, fixture := range fixtures {
+		fixtureDir := filepath.Join(dir, fixture.Name)
+		if err := os.MkdirAll(fixtureDir, 0700); err != nil {
+			return err
+		}
+		subject, err := json.Marshal(fixture.Subject)
+		if err != nil {
+			return err
+		}
+		var certChain []byte
+		for _, cert := range fixture.CertificateChain {
+			certChain = append(certChain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+		}
+		root := fixture.CertificateChain[len(fixture.CertificateChain)-1]
+		envelopeName := "envelope.jws"
+		if fixture.EnvelopeMediaType == cose.MediaTypeEnvelope {
+			envelopeName = "envelope.cose"
+		}
+		for name, data := range map[string][]byte{
+			envelopeName:    fixture.Envelope,
+			"manifest.json": fixture.Manifest,
+			"subject.json":  subject,
+			"certchain.pem": certChain,
+			"root.pem":      pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw}),
+		} {
+			if err := os.WriteFile(filepath.Join(fixtureDir, name), data, 0600); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
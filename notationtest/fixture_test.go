@@ -0,0 +1,89 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestGenerateFixtures(t *testing.T) {
+	ctx := context.Background()
+	fixtures, err := notationtest.GenerateFixtures(ctx, notationtest.FixtureOptions{
+		KeySpecs: []signature.KeySpec{
+			{Type: signature.KeyTypeRSA, Size: 2048},
+			{Type: signature.KeyTypeEC, Size: 256},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateFixtures() error = %v", err)
+	}
+	wantNames := []string{"jws-rsa-2048", "cose-rsa-2048", "jws-ec-256", "cose-ec-256"}
+	if len(fixtures) != len(wantNames) {
+		t.Fatalf("GenerateFixtures() returned %d fixtures, want %d", len(fixtures), len(wantNames))
+	}
+
+	for i, fixture := range fixtures {
+		t.Run(fixture.Name, func(t *testing.T) {
+			if fixture.Name != wantNames[i] {
+				t.Fatalf("Name = %s, want %s", fixture.Name, wantNames[i])
+			}
+			var manifest ocispec.Manifest
+			if err := json.Unmarshal(fixture.Manifest, &manifest); err != nil {
+				t.Fatal(err)
+			}
+			if manifest.Subject == nil || manifest.Subject.Digest != notationtest.FixtureSubject.Digest || len(manifest.Layers) != 1 {
+				t.Fatalf("Manifest = %s", fixture.Manifest)
+			}
+
+			root := fixture.CertificateChain[len(fixture.CertificateChain)-1]
+			v, err := verifier.NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "fixture", root), verifier.VerifierOptions{
+				OCITrustPolicy: notationtest.TrustPolicy("fixture"),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			outcome, err := v.Verify(ctx, fixture.Subject, fixture.Envelope, notation.VerifierVerifyOptions{
+				ArtifactReference:  "localhost:5000/fixture@" + fixture.Subject.Digest.String(),
+				SignatureMediaType: fixture.EnvelopeMediaType,
+			})
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if got := outcome.EnvelopeContent.SignerInfo.SignedAttributes.SigningTime; !got.Equal(notationtest.FixtureSigningTime) {
+				t.Fatalf("signing time = %v, want %v", got, notationtest.FixtureSigningTime)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	if err := notationtest.WriteFixtures(dir, fixtures); err != nil {
+		t.Fatalf("WriteFixtures() error = %v", err)
+	}
+	for _, name := range []string{"envelope.cose", "manifest.json", "subject.json", "certchain.pem", "root.pem"} {
+		if _, err := os.Stat(filepath.Join(dir, "cose-ec-256", name)); err != nil {
+			t.Fatalf("WriteFixtures() did not write %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", map[string]string{"key": "value"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedDesc, err := notation.Sign(ctx, signer, repo, notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: "localhost:5000/notationtest:v1",
+	})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if signedDesc.Digest != artifactDesc.Digest {
+		t.Fatalf("Sign() = %v, want %v", signedDesc.Digest, artifactDesc.Digest)
+	}
+
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+	}
+	t.Run("trusted", func(t *testing.T) {
+		trustStore := notationtest.NewTrustStore().Add(truststore.TypeCA, "test", signer.Root())
+		v, err := verifier.NewVerifierWithOptions(trustStore, verifier.VerifierOptions{
+			OCITrustPolicy: notationtest.TrustPolicy("test"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, outcomes, err := notation.Verify(ctx, v, repo, verifyOpts); err != nil || len(outcomes) != 1 {
+			t.Fatalf("Verify() = %v, %v", outcomes, err)
+		}
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		trustStore := notationtest.NewTrustStore().Add(truststore.TypeCA, "test")
+		v, err := verifier.NewVerifierWithOptions(trustStore, verifier.VerifierOptions{
+			OCITrustPolicy: notationtest.TrustPolicy("test"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); !errors.As(err, &notation.ErrorVerificationFailed{}) {
+			t.Fatalf("Verify() error = %v, want ErrorVerificationFailed", err)
+		}
+	})
+}
+
+func TestRepositoryTags(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	for i, tag := range []string{"v2", "v1", "", "latest", "v1"} {
+		if _, err := repo.PushArtifact(ctx, tag, map[string]string{"index": strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var tags []string
+	if err := repo.Tags(ctx, "latest", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	}); err != nil {
+		t.Fatalf("Tags() error = %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "v1" || tags[1] != "v2" {
+		t.Fatalf("Tags() = %v, want [v1 v2]", tags)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// OCSPResponder is a RFC 6960 OCSP responder served over HTTP, for testing
+// purpose ONLY. The responses are signed by the issuer of the certificates.
+type OCSPResponder struct {
+	// Server is the HTTP server of the OCSP responder. Certificates checked
+	// with the responder have Server.URL as OCSP server.
+	*httptest.Server
+
+	issuer    *x509.Certificate
+	issuerKey crypto.Signer
+
+	mu          sync.Mutex
+	failureMode FailureMode
+	statuses    map[string]ocsp.Response
+}
+
+// NewOCSPResponder starts and returns a new [OCSPResponder] for the
+// certificates issued by issuer. The certificates are good unless set
+// otherwise with SetStatus. The caller should call Close when finished, to
+// shut it down.
+func NewOCSPResponder(issuer *x509.Certificate, issuerKey crypto.Signer) *OCSPResponder {
+	responder := &OCSPResponder{
+		issuer:    issuer,
+		issuerKey: issuerKey,
+		statuses:  make(map[string]ocsp.Response),
+	}
+	responder.Server = httptest.NewServer(http.HandlerFunc(responder.serveHTTP))
+	return responder
+}
+
+// SetFailureMode sets the failure mode of the OCSP responder.
+func (o *OCSPResponder) SetFailureMode(mode FailureMode) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.failureMode = mode
+}
+
+// SetStatus sets the status of the certificate with the serial number to
+// status, one of [ocsp.Good], [ocsp.Revoked] and [ocsp.Unknown]. revokedAt is
+// the revocation time of a revoked certificate.
+func (o *OCSPResponder) SetStatus(serialNumber *big.Int, status int, revokedAt time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.statuses[serialNumber.String()] = ocsp.Response{
+		Status:           status,
+		RevokedAt:        revokedAt,
+		RevocationReason: ocsp.Unspecified,
+	}
+}
+
+func (o *OCSPResponder) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	mode := o.failureMode
+	o.mu.Unlock()
+
+	if failTransport(w, r, mode) {
+		return
+	}
+	var reqBytes []byte
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		var encoded string
+		if encoded, err = url.PathUnescape(strings.TrimPrefix(r.URL.Path, "/")); err == nil {
+			reqBytes, err = base64.StdEncoding.DecodeString(encoded)
+		}
+	case http.MethodPost:
+		reqBytes, err = io.ReadAll(io.LimitReader(r.Body, 1024*1024))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	if err != nil {
+		w.Write(ocsp.MalformedRequestErrorResponse)
+		return
+	}
+	if mode == FailureRejection {
+		w.Write(ocsp.InternalErrorErrorResponse)
+		return
+	}
+	req, err := ocsp.ParseRequest(reqBytes)
+	if err != nil {
+		w.Write(ocsp.MalformedRequestErrorResponse)
+		return
+	}
+
+	o.mu.Lock()
+	template, ok := o.statuses[req.SerialNumber.String()]
+	o.mu.Unlock()
+	if !ok {
+		template = ocsp.Response{Status: ocsp.Good}
+	}
+	now := time.Now()
+	template.SerialNumber = req.SerialNumber
+	template.ThisUpdate = now.Add(-time.Minute)
+	template.NextUpdate = now.Add(time.Hour)
+	respBytes, err := ocsp.CreateResponse(o.issuer, o.issuer, template, o.issuerKey)
+	if err != nil {
+		w.Write(ocsp.InternalErrorErrorResponse)
+		return
+	}
+	w.Write(respBytes)
+}
@@ -0,0 +1,72 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestOCSPResponder(t *testing.T) {
+	root := testhelper.GetRSARootCertificate()
+	leaf := testhelper.GetRSALeafCertificate().Cert
+	responder := NewOCSPResponder(root.Cert, root.PrivateKey)
+	defer responder.Close()
+
+	reqBytes, err := ocsp.CreateRequest(leaf, root.Cert, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(t *testing.T) (*ocsp.Response, error) {
+		t.Helper()
+		client := &http.Client{Timeout: 100 * time.Millisecond}
+		httpResp, err := client.Post(responder.URL, "application/ocsp-request", bytes.NewReader(reqBytes))
+		if err != nil {
+			return nil, err
+		}
+		defer httpResp.Body.Close()
+		respBytes, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return ocsp.ParseResponseForCert(respBytes, leaf, root.Cert)
+	}
+
+	resp, err := check(t)
+	if err != nil || resp.Status != ocsp.Good {
+		t.Fatalf("status = %v, %v, want good", resp, err)
+	}
+
+	revokedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	responder.SetStatus(leaf.SerialNumber, ocsp.Revoked, revokedAt)
+	resp, err = check(t)
+	if err != nil || resp.Status != ocsp.Revoked || !resp.RevokedAt.Equal(revokedAt) {
+		t.Fatalf("status = %v, %v, want revoked at %v", resp, err, revokedAt)
+	}
+
+	responder.SetFailureMode(FailureRejection)
+	if _, err := check(t); err == nil {
+		t.Fatal("expected error with the rejection failure mode")
+	}
+	responder.SetFailureMode(FailureHang)
+	if _, err := check(t); err == nil {
+		t.Fatal("expected error with the hang failure mode")
+	}
+}
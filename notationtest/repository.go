@@ -0,0 +1,108 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notationtest provides test doubles to unit test signing and
+// verification flows without a registry: an in-memory repository, a test
+// signer, an in-memory trust store, and local TSA and OCSP servers with
+// controllable failure modes.
+//
+// The test doubles are for testing purpose ONLY, the keys and certificates
+// they use must never be trusted in production.
+package notationtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// Repository is an in-memory [registry.Repository]. It lists the tags of the
+// artifacts pushed with [Repository.PushArtifact].
+type Repository struct {
+	registry.Repository
+
+	store *memory.Store
+
+	mu   sync.Mutex
+	tags []string
+}
+
+// NewRepository returns an empty in-memory [Repository].
+func NewRepository() *Repository {
+	store := memory.New()
+	return &Repository{
+		Repository: registry.NewRepository(store),
+		store:      store,
+	}
+}
+
+// PushArtifact pushes an image manifest with the annotations to the
+// repository and returns its descriptor. The artifact can be resolved by
+// digest, and by tag if tag is not empty.
+func (r *Repository) PushArtifact(ctx context.Context, tag string, annotations map[string]string) (ocispec.Descriptor, error) {
+	manifestJSON, err := json.Marshal(ocispec.Manifest{
+		Versioned:   specs.Versioned{SchemaVersion: 2},
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Config:      ocispec.DescriptorEmptyJSON,
+		Layers:      []ocispec.Descriptor{ocispec.DescriptorEmptyJSON},
+		Annotations: annotations,
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to marshal the artifact manifest: %w", err)
+	}
+	desc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifestJSON)
+	if err := r.store.Push(ctx, desc, bytes.NewReader(manifestJSON)); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push the artifact manifest: %w", err)
+	}
+	// the memory store resolves tags only, the digest is tagged so that the
+	// artifact can be resolved by digest
+	references := []string{desc.Digest.String()}
+	if tag != "" {
+		references = append(references, tag)
+	}
+	for _, reference := range references {
+		if err := r.store.Tag(ctx, desc, reference); err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to tag the artifact manifest: %w", err)
+		}
+	}
+	if tag != "" {
+		r.mu.Lock()
+		if i := sort.SearchStrings(r.tags, tag); i == len(r.tags) || r.tags[i] != tag {
+			r.tags = append(r.tags[:i], append([]string{tag}, r.tags[i:]...)...)
+		}
+		r.mu.Unlock()
+	}
+	return desc, nil
+}
+
+// Tags lists the tags of the artifacts pushed with [Repository.PushArtifact]
+// in lexical order. If last is not empty, the tags start after last.
+func (r *Repository) Tags(ctx context.Context, last string, fn func(tags []string) error) error {
+	r.mu.Lock()
+	tags := r.tags[sort.Search(len(r.tags), func(i int) bool { return r.tags[i] > last }):]
+	tags = append([]string(nil), tags...)
+	r.mu.Unlock()
+	if len(tags) == 0 {
+		return nil
+	}
+	return fn(tags)
+}
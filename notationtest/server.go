@@ -0,0 +1,61 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"io"
+	"net/http"
+)
+
+// FailureMode is the failure mode of a test server.
+type FailureMode int
+
+const (
+	// FailureNone makes the server respond normally.
+	FailureNone FailureMode = iota
+
+	// FailureRejection makes the server reject the requests at the protocol
+	// level: the TSA responds with the rejection status and the OCSP
+	// responder with the internal error status.
+	FailureRejection
+
+	// FailureServerError makes the server respond with HTTP status 500.
+	FailureServerError
+
+	// FailureMalformed makes the server respond with a malformed body.
+	FailureMalformed
+
+	// FailureHang makes the server hold the requests until the client gives
+	// up or the server is closed.
+	FailureHang
+)
+
+// failTransport applies the failure modes not specific to a protocol and
+// reports whether the request is handled.
+func failTransport(w http.ResponseWriter, r *http.Request, mode FailureMode) bool {
+	switch mode {
+	case FailureServerError:
+		http.Error(w, "test server error", http.StatusInternalServerError)
+	case FailureMalformed:
+		w.Write([]byte("malformed response"))
+	case FailureHang:
+		// the request context is canceled on client disconnection only once
+		// the request body is consumed
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"crypto/x509"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go/signer"
+)
+
+// Signer is a signer using a test RSA certificate chain. All the signers
+// created in a process share the same keys and certificates.
+type Signer struct {
+	*signer.GenericSigner
+
+	// CertificateChain is the certificate chain of the signer, from the
+	// signing certificate to the root certificate.
+	CertificateChain []*x509.Certificate
+}
+
+// NewSigner returns a [Signer] signing with the test RSA leaf certificate
+// issued by the test RSA root certificate.
+func NewSigner() (*Signer, error) {
+	leaf := testhelper.GetRSALeafCertificate()
+	certChain := []*x509.Certificate{leaf.Cert, testhelper.GetRSARootCertificate().Cert}
+	genericSigner, err := signer.NewGenericSigner(leaf.PrivateKey, certChain)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{
+		GenericSigner:    genericSigner,
+		CertificateChain: certChain,
+	}, nil
+}
+
+// Root returns the root certificate of the certificate chain of the signer.
+func (s *Signer) Root() *x509.Certificate {
+	return s.CertificateChain[len(s.CertificateChain)-1]
+}
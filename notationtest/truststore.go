@@ -0,0 +1,82 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+// TrustStore is an in-memory [truststore.X509TrustStore].
+type TrustStore struct {
+	mu    sync.RWMutex
+	certs map[truststore.Type]map[string][]*x509.Certificate
+}
+
+// NewTrustStore returns an empty in-memory [TrustStore].
+func NewTrustStore() *TrustStore {
+	return &TrustStore{
+		certs: make(map[truststore.Type]map[string][]*x509.Certificate),
+	}
+}
+
+// Add adds the certificates to the named store namedStore of type storeType
+// and returns the trust store.
+func (s *TrustStore) Add(storeType truststore.Type, namedStore string, certs ...*x509.Certificate) *TrustStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.certs[storeType] == nil {
+		s.certs[storeType] = make(map[string][]*x509.Certificate)
+	}
+	s.certs[storeType][namedStore] = append(s.certs[storeType][namedStore], certs...)
+	return s
+}
+
+// GetCertificates returns the certificates of the named store namedStore of
+// type storeType.
+func (s *TrustStore) GetCertificates(ctx context.Context, storeType truststore.Type, namedStore string) ([]*x509.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	certs, ok := s.certs[storeType][namedStore]
+	if !ok {
+		return nil, truststore.TrustStoreError{Msg: fmt.Sprintf("the trust store %q of type %q does not exist", namedStore, storeType)}
+	}
+	return append([]*x509.Certificate(nil), certs...), nil
+}
+
+// TrustPolicy returns an OCI trust policy document with a single policy
+// statement, verifying all the artifacts at the strict level with the
+// certificates of the named trust store namedStore of type "ca", and trusting
+// any identity.
+func TrustPolicy(namedStore string) *trustpolicy.OCIDocument {
+	return &trustpolicy.OCIDocument{
+		Version: "1.0",
+		TrustPolicies: []trustpolicy.OCITrustPolicy{
+			{
+				Name:                  "notationtest",
+				RegistryScopes:        []string{"*"},
+				SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: trustpolicy.LevelStrict.Name},
+				TrustStores:           []string{string(truststore.TypeCA) + ":" + namedStore},
+				TrustedIdentities:     []string{"*"},
+			},
+		},
+	}
+}
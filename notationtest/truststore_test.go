@@ -0,0 +1,40 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestTrustStore(t *testing.T) {
+	ctx := context.Background()
+	root := testhelper.GetRSARootCertificate().Cert
+	store := NewTrustStore().Add(truststore.TypeCA, "test", root)
+
+	certs, err := store.GetCertificates(ctx, truststore.TypeCA, "test")
+	if err != nil || len(certs) != 1 || !certs[0].Equal(root) {
+		t.Fatalf("GetCertificates() = %v, %v", certs, err)
+	}
+	if _, err := store.GetCertificates(ctx, truststore.TypeSigningAuthority, "test"); !errors.As(err, &truststore.TrustStoreError{}) {
+		t.Fatalf("GetCertificates() error = %v, want TrustStoreError", err)
+	}
+	if err := TrustPolicy("test").Validate(); err != nil {
+		t.Fatalf("TrustPolicy().Validate() error = %v", err)
+	}
+}
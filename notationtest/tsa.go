@@ -0,0 +1,344 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/tspclient-go"
+	"github.com/notaryproject/tspclient-go/pki"
+)
+
+// object identifiers used by the TSA
+var (
+	oidSHA256               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	oidSHA256WithRSA        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSignedData           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidTSTInfo              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	oidSigningCertificateV2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+	oidExtKeyUsage          = asn1.ObjectIdentifier{2, 5, 29, 37}
+	oidTimestamping         = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 8}
+
+	// oidTestTSAPolicy is the TSA policy of the test TSA.
+	oidTestTSAPolicy = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}
+)
+
+// CMS structures of RFC 5652 and RFC 5035 signing a timestamp token.
+type (
+	contentInfo struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue `asn1:"explicit,tag:0"`
+	}
+
+	signedData struct {
+		Version                    int
+		DigestAlgorithmIdentifiers []pkix.AlgorithmIdentifier `asn1:"set"`
+		EncapsulatedContentInfo    encapsulatedContentInfo
+		Certificates               asn1.RawValue `asn1:"optional,tag:0"`
+		SignerInfos                []signerInfo  `asn1:"set"`
+	}
+
+	encapsulatedContentInfo struct {
+		ContentType asn1.ObjectIdentifier
+		Content     []byte `asn1:"explicit,optional,tag:0"`
+	}
+
+	signerInfo struct {
+		Version            int
+		SignerIdentifier   issuerAndSerialNumber
+		DigestAlgorithm    pkix.AlgorithmIdentifier
+		SignedAttributes   []attribute `asn1:"optional,tag:0"`
+		SignatureAlgorithm pkix.AlgorithmIdentifier
+		Signature          []byte
+	}
+
+	issuerAndSerialNumber struct {
+		Issuer       asn1.RawValue
+		SerialNumber *big.Int
+	}
+
+	attribute struct {
+		Type   asn1.ObjectIdentifier
+		Values asn1.RawValue `asn1:"set"`
+	}
+
+	signingCertificateV2 struct {
+		Certificates []essCertIDv2
+	}
+
+	essCertIDv2 struct {
+		CertHash []byte
+	}
+)
+
+// TSA is a RFC 3161 Time Stamping Authority served over HTTP, for testing
+// purpose ONLY.
+type TSA struct {
+	// Server is the HTTP server of the TSA. Timestamp requests are sent to
+	// Server.URL.
+	*httptest.Server
+
+	// Certificate is the self-signed timestamping certificate of the TSA.
+	Certificate *x509.Certificate
+
+	key *rsa.PrivateKey
+
+	mu          sync.Mutex
+	failureMode FailureMode
+	now         func() time.Time
+}
+
+// NewTSA starts and returns a new [TSA] with a freshly generated key and
+// certificate. The caller should call Close when finished, to shut it down.
+func NewTSA() (*TSA, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	serialNumber, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+	if err != nil {
+		return nil, err
+	}
+	// the timestamping extended key usage must be critical, RFC 3161 2.3
+	ekuValue, err := asn1.Marshal([]asn1.ObjectIdentifier{oidTimestamping})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: "Notation Test TSA"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtraExtensions: []pkix.Extension{
+			{
+				Id:       oidExtKeyUsage,
+				Critical: true,
+				Value:    ekuValue,
+			},
+		},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	tsa := &TSA{
+		Certificate: cert,
+		key:         key,
+		now:         time.Now,
+	}
+	tsa.Server = httptest.NewServer(http.HandlerFunc(tsa.serveHTTP))
+	return tsa, nil
+}
+
+// SetFailureMode sets the failure mode of the TSA.
+func (t *TSA) SetFailureMode(mode FailureMode) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failureMode = mode
+}
+
+// SetNow sets the function returning the time stamped by the TSA. If nil,
+// time.Now is used.
+func (t *TSA) SetNow(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.now = now
+}
+
+func (t *TSA) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	t.mu.Lock()
+	mode, now := t.failureMode, t.now
+	t.mu.Unlock()
+
+	if failTransport(w, r, mode) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	reqBytes, err := io.ReadAll(io.LimitReader(r.Body, 1024*1024))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := &tspclient.Response{
+		Status: pki.StatusInfo{Status: pki.StatusRejection},
+	}
+	if mode != FailureRejection {
+		var req tspclient.Request
+		if err := req.UnmarshalBinary(reqBytes); err == nil {
+			if token, err := t.timestamp(&req, now()); err == nil {
+				resp = &tspclient.Response{
+					Status:         pki.StatusInfo{Status: pki.StatusGranted},
+					TimestampToken: token,
+				}
+			}
+		}
+	}
+	respBytes, err := resp.MarshalBinary()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/timestamp-reply")
+	w.Write(respBytes)
+}
+
+// timestamp returns the timestamp token of the request at time now.
+func (t *TSA) timestamp(req *tspclient.Request, now time.Time) (asn1.RawValue, error) {
+	if req.Version != 1 {
+		return asn1.RawValue{}, fmt.Errorf("unsupported request version %d", req.Version)
+	}
+	hashAlgorithm := req.MessageImprint.HashAlgorithm.Algorithm
+	if !hashAlgorithm.Equal(oidSHA256) && !hashAlgorithm.Equal(oidSHA384) && !hashAlgorithm.Equal(oidSHA512) {
+		return asn1.RawValue{}, fmt.Errorf("unsupported hash algorithm %v", hashAlgorithm)
+	}
+	serialNumber, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+	if err != nil {
+		return asn1.RawValue{}, err
+	}
+	info, err := asn1.Marshal(tspclient.TSTInfo{
+		Version:        1,
+		Policy:         oidTestTSAPolicy,
+		MessageImprint: req.MessageImprint,
+		SerialNumber:   serialNumber,
+		GenTime:        now.UTC().Truncate(time.Second),
+		Accuracy:       tspclient.Accuracy{Seconds: 1},
+		Nonce:          req.Nonce,
+	})
+	if err != nil {
+		return asn1.RawValue{}, err
+	}
+	signed, err := t.sign(info, req.CertReq, now)
+	if err != nil {
+		return asn1.RawValue{}, err
+	}
+	content, err := rawASN1(signed, "explicit,tag:0")
+	if err != nil {
+		return asn1.RawValue{}, err
+	}
+	return rawASN1(contentInfo{
+		ContentType: oidSignedData,
+		Content:     content,
+	}, "")
+}
+
+// sign returns the CMS signed data of the timestamp token info.
+func (t *TSA) sign(info []byte, includeCertificate bool, now time.Time) (signedData, error) {
+	var issuer asn1.RawValue
+	if _, err := asn1.Unmarshal(t.Certificate.RawIssuer, &issuer); err != nil {
+		return signedData{}, err
+	}
+	infoDigest := sha256.Sum256(info)
+	certHash := sha256.Sum256(t.Certificate.Raw)
+	var attributes []attribute
+	for _, attr := range []struct {
+		oid   asn1.ObjectIdentifier
+		value interface{}
+	}{
+		{oidContentType, oidTSTInfo},
+		{oidMessageDigest, infoDigest[:]},
+		{oidSigningTime, now.UTC()},
+		{oidSigningCertificateV2, signingCertificateV2{Certificates: []essCertIDv2{{CertHash: certHash[:]}}}},
+	} {
+		values, err := rawASN1([]interface{}{attr.value}, "set")
+		if err != nil {
+			return signedData{}, err
+		}
+		attributes = append(attributes, attribute{Type: attr.oid, Values: values})
+	}
+	encodedAttributes, err := asn1.MarshalWithParams(attributes, "set")
+	if err != nil {
+		return signedData{}, err
+	}
+	attributesDigest := sha256.Sum256(encodedAttributes)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, t.key, crypto.SHA256, attributesDigest[:])
+	if err != nil {
+		return signedData{}, err
+	}
+
+	signed := signedData{
+		Version:                    3,
+		DigestAlgorithmIdentifiers: []pkix.AlgorithmIdentifier{{Algorithm: oidSHA256}},
+		EncapsulatedContentInfo: encapsulatedContentInfo{
+			ContentType: oidTSTInfo,
+			Content:     info,
+		},
+		SignerInfos: []signerInfo{
+			{
+				Version: 1,
+				SignerIdentifier: issuerAndSerialNumber{
+					Issuer:       issuer,
+					SerialNumber: t.Certificate.SerialNumber,
+				},
+				DigestAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: oidSHA256},
+				SignedAttributes:   attributes,
+				SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256WithRSA},
+				Signature:          signature,
+			},
+		},
+	}
+	if includeCertificate {
+		if signed.Certificates, err = rawASN1(t.Certificate.Raw, "tag:0"); err != nil {
+			return signedData{}, err
+		}
+	}
+	return signed, nil
+}
+
+// rawASN1 returns the ASN.1 encoding of val with the params as a raw value.
+func rawASN1(val interface{}, params string) (asn1.RawValue, error) {
+	b, err := asn1.MarshalWithParams(val, params)
+	if err != nil {
+		return asn1.RawValue{}, err
+	}
+	var raw asn1.RawValue
+	if _, err := asn1.UnmarshalWithParams(b, &raw, params); err != nil {
+		return asn1.RawValue{}, err
+	}
+	return raw, nil
+}
@@ -0,0 +1,84 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notationtest
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/tspclient-go"
+)
+
+func TestTSA(t *testing.T) {
+	tsa, err := NewTSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tsa.Close()
+	timestamper, err := tspclient.NewHTTPTimestamper(nil, tsa.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("notation")
+	req, err := tspclient.NewRequest(tspclient.RequestOptions{
+		Content:       content,
+		HashAlgorithm: crypto.SHA256,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("granted", func(t *testing.T) {
+		genTime := time.Now().UTC().Truncate(time.Second).Add(-time.Minute)
+		tsa.SetNow(func() time.Time { return genTime })
+		defer tsa.SetNow(nil)
+
+		resp, err := timestamper.Timestamp(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Timestamp() error = %v", err)
+		}
+		token, err := resp.SignedToken()
+		if err != nil {
+			t.Fatalf("SignedToken() error = %v", err)
+		}
+		roots := x509.NewCertPool()
+		roots.AddCert(tsa.Certificate)
+		if _, err := token.Verify(context.Background(), x509.VerifyOptions{Roots: roots, CurrentTime: genTime}); err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		info, err := token.Info()
+		if err != nil {
+			t.Fatalf("Info() error = %v", err)
+		}
+		timestamp, err := info.Validate(content)
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if !timestamp.Value.Equal(genTime) {
+			t.Fatalf("timestamp = %v, want %v", timestamp.Value, genTime)
+		}
+	})
+
+	for _, mode := range []FailureMode{FailureRejection, FailureServerError, FailureMalformed, FailureHang} {
+		tsa.SetFailureMode(mode)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		if _, err := timestamper.Timestamp(ctx, req); err == nil {
+			t.Fatalf("Timestamp() with failure mode %d expected error", mode)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/trace"
+	"github.com/opencontainers/go-digest"
+)
+
+// operation names logged with [log.FieldOperation].
+const (
+	operationSign       = "sign"
+	operationSignBlob   = "signBlob"
+	operationVerify     = "verify"
+	operationVerifyBlob = "verifyBlob"
+)
+
+// withOperation adds the name and a new random ID of the operation to the
+// logger in the context, and starts the trace of the operation if a
+// trace.Recorder is set in the context. The operation must be ended with
+// [endOperation].
+func withOperation(ctx context.Context, operation string) context.Context {
+	operationID := newOperationID()
+	ctx = trace.Start(ctx, operation, operationID)
+	return log.WithFields(ctx, log.Fields{
+		log.FieldOperation:   operation,
+		log.FieldOperationID: operationID,
+	})
+}
+
+// endOperation ends the trace of the operation started by [withOperation]
+// with ctx, if any.
+func endOperation(ctx context.Context, err error, outcomes ...*VerificationOutcome) {
+	if !trace.Enabled(ctx) {
+		return
+	}
+	var traced []trace.Outcome
+	for _, outcome := range outcomes {
+		if outcome != nil {
+			traced = append(traced, traceOutcome(outcome))
+		}
+	}
+	trace.End(ctx, err, traced...)
+}
+
+// traceOutcome returns the redacted outcome recorded in the trace. The
+// signature is recorded by its digest.
+func traceOutcome(outcome *VerificationOutcome) trace.Outcome {
+	traced := trace.Outcome{
+		Warnings: outcome.Warnings,
+	}
+	if len(outcome.RawSignature) > 0 {
+		traced.Signature = digest.FromBytes(outcome.RawSignature).String()
+	}
+	if outcome.VerificationLevel != nil {
+		traced.VerificationLevel = outcome.VerificationLevel.Name
+	}
+	for _, result := range outcome.VerificationResults {
+		if result == nil {
+			continue
+		}
+		tracedResult := trace.Result{
+			Type:   string(result.Type),
+			Action: string(result.Action),
+		}
+		if result.Error != nil {
+			tracedResult.Error = result.Error.Error()
+		}
+		traced.Results = append(traced.Results, tracedResult)
+	}
+	if outcome.Error != nil {
+		traced.Error = outcome.Error.Error()
+	}
+	return traced
+}
+
+// newOperationID returns a random ID of an operation.
+func newOperationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,188 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/trace"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// recordingLogger records the info messages.
+type recordingLogger struct {
+	log.Logger
+	messages []string
+}
+
+func (l *recordingLogger) Info(args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprint(args...))
+}
+
+// loggingSigner logs a message with the logger of the context.
+type loggingSigner struct {
+	dummySigner
+}
+
+func (s *loggingSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	log.GetLogger(ctx).Info("signing")
+	return s.dummySigner.Sign(ctx, desc, opts)
+}
+
+func TestSignOperationLogFields(t *testing.T) {
+	logger := &recordingLogger{Logger: log.Discard}
+	ctx := log.WithLogger(context.Background(), logger)
+	opts := SignOptions{ArtifactReference: mock.SampleArtifactUri}
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+	if _, _, err := SignOCI(ctx, &loggingSigner{}, mock.NewRepository(), opts); err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+	if len(logger.messages) != 1 {
+		t.Fatalf("messages = %q, want 1 message", logger.messages)
+	}
+	message := logger.messages[0]
+	for _, want := range []string{
+		"artifact=" + mock.SampleDigest.String(),
+		"operation=sign",
+		"operationId=",
+		"repository=registry.acme-rockets.io/software/net-monitor",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message %q does not contain %q", message, want)
+		}
+	}
+}
+
+func TestNewOperationID(t *testing.T) {
+	if newOperationID() == newOperationID() {
+		t.Fatal("newOperationID() returned the same ID twice")
+	}
+}
+
+func TestSignOperationTrace(t *testing.T) {
+	configDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(configDir, dir.PathOCITrustPolicy), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, dir.PathCredentials), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	recorder := trace.NewRecorderWithOptions(trace.RecorderOptions{
+		ConfigFS: dir.NewSysFS(configDir),
+		PluginFS: dir.NewSysFS(t.TempDir()),
+	})
+	logger := &recordingLogger{Logger: log.Discard}
+	ctx := trace.WithRecorder(log.WithLogger(context.Background(), logger), recorder)
+	opts := SignOptions{ArtifactReference: mock.SampleArtifactUri}
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+	if _, _, err := SignOCI(ctx, &loggingSigner{}, mock.NewRepository(), opts); err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+
+	// the messages are still passed to the logger of the context
+	if len(logger.messages) != 1 {
+		t.Fatalf("messages = %q, want 1 message", logger.messages)
+	}
+	bundle := recorder.Bundle()
+	if bundle.Operation != operationSign || bundle.OperationID == "" || !bundle.Completed || bundle.Error != "" {
+		t.Fatalf("bundle = %+v", bundle)
+	}
+	if len(bundle.Config) != 1 || bundle.Config[0].Path != dir.PathOCITrustPolicy {
+		t.Fatalf("bundle.Config = %+v, want the trust policy only", bundle.Config)
+	}
+	var found bool
+	for _, event := range bundle.Events {
+		if event.Type == trace.EventLog && event.Message == "signing" {
+			found = true
+			if event.Fields[log.FieldOperationID] != bundle.OperationID {
+				t.Errorf("event.Fields = %v, want operation ID %s", event.Fields, bundle.OperationID)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("bundle.Events = %+v, want the signing message", bundle.Events)
+	}
+}
+
+func TestVerifyOperationTrace(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	tests := []struct {
+		name       string
+		failVerify bool
+		wantError  bool
+	}{
+		{name: "verified"},
+		{name: "failed", failVerify: true, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := trace.NewRecorderWithOptions(trace.RecorderOptions{
+				ConfigFS: dir.NewSysFS(t.TempDir()),
+				PluginFS: dir.NewSysFS(t.TempDir()),
+			})
+			ctx := trace.WithRecorder(context.Background(), recorder)
+			verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, tt.failVerify, *trustpolicy.LevelStrict, false}
+			opts := VerifyOptions{ArtifactReference: mock.SampleArtifactUri, MaxSignatureAttempts: 50}
+			_, _, err := Verify(ctx, &verifier, mock.NewRepository(), opts)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("Verify() error = %v, wantError %v", err, tt.wantError)
+			}
+
+			bundle := recorder.Bundle()
+			if bundle.Operation != operationVerify || !bundle.Completed || (bundle.Error != "") != tt.wantError {
+				t.Fatalf("bundle = %+v", bundle)
+			}
+			if tt.wantError {
+				if !strings.Contains(bundle.Error, "signature verification failed") {
+					t.Fatalf("bundle.Error = %q, want the verification failure", bundle.Error)
+				}
+				return
+			}
+			if len(bundle.Outcomes) != 1 || bundle.Outcomes[0].VerificationLevel != trustpolicy.LevelStrict.Name {
+				t.Fatalf("bundle.Outcomes = %+v", bundle.Outcomes)
+			}
+		})
+	}
+}
+
+func TestVerifyOperationTraceNested(t *testing.T) {
+	recorder := trace.NewRecorderWithOptions(trace.RecorderOptions{
+		ConfigFS: dir.NewSysFS(t.TempDir()),
+		PluginFS: dir.NewSysFS(t.TempDir()),
+	})
+	ctx := trace.WithRecorder(context.Background(), recorder)
+	ctx = withOperation(ctx, operationVerify)
+
+	// an operation within the traced one does not end the trace
+	nested := withOperation(ctx, operationVerifyBlob)
+	endOperation(nested, nil)
+	if bundle := recorder.Bundle(); bundle.Operation != operationVerify || bundle.Completed {
+		t.Fatalf("bundle = %+v, want the verify operation in progress", bundle)
+	}
+	endOperation(ctx, nil)
+	if bundle := recorder.Bundle(); !bundle.Completed {
+		t.Fatalf("bundle = %+v, want the verify operation completed", bundle)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/internal/envelope"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// MediaTypePayloadV1 is the content type of the signature payloads of
+// notation, describing an OCI descriptor.
+const MediaTypePayloadV1 = envelope.MediaTypePayloadV1
+
+// PayloadParser parses the content of a signature payload and returns the
+// descriptor of the target artifact. It returns an error if the content does
+// not conform to the schema of the payload type.
+type PayloadParser func(content []byte) (ocispec.Descriptor, error)
+
+// RegisterPayloadType registers an additional signature payload content type
+// with its parser, e.g. for firmware descriptors. Signatures with a
+// registered payload content type are accepted by the verifier, which
+// verifies the descriptor returned by the parser.
+//
+// RegisterPayloadType is expected to be called at initialization. It returns
+// an error if the content type is already registered, including
+// [MediaTypePayloadV1].
+func RegisterPayloadType(contentType string, parser PayloadParser) error {
+	var p envelope.PayloadParser
+	if parser != nil {
+		p = envelope.PayloadParser(parser)
+	}
+	return envelope.RegisterPayloadType(contentType, p)
+}
+
+// ValidatePayload validates the signature payload against the schema of its
+// content type, and returns the descriptor of the target artifact.
+//
+// The payloads of content type [MediaTypePayloadV1] must have a target
+// artifact with a media type, a valid digest and a non-negative size.
+func ValidatePayload(payload *signature.Payload) (ocispec.Descriptor, error) {
+	return envelope.ParsePayload(payload)
+}
+
+// CheckPayload checks that the signature payload is well-formed, independent
+// of any trust evaluation, e.g. for a registry or a gateway to reject
+// malformed signatures at upload time. The payload must conform to the schema
+// of its content type as validated by [ValidatePayload], and the descriptor
+// of the target artifact must have a valid media type, a digest of a
+// supported algorithm, i.e. SHA-256, SHA-384 or SHA-512, and non-empty
+// annotation keys.
+//
+// The payloads of content type [MediaTypePayloadV1] must also be canonically
+// encoded, as produced by notation: compact JSON, with the descriptor members
+// mediaType, digest, size and annotations only, either in this order or
+// sorted by name as in JWS envelopes, whose payloads are encoded as JWT
+// claims, and the annotations sorted by key. The canonical encoding of the
+// registered payload types is not checked.
+//
+// A [MalformedSignatureError] is returned if the payload is malformed.
+func CheckPayload(payload *signature.Payload) (ocispec.Descriptor, error) {
+	if payload == nil {
+		return ocispec.Descriptor{}, MalformedSignatureError{Msg: "signature payload cannot be nil"}
+	}
+	desc, err := envelope.ParsePayload(payload)
+	if err != nil {
+		return ocispec.Descriptor{}, MalformedSignatureError{Msg: err.Error()}
+	}
+	if err := checkTargetArtifact(desc); err != nil {
+		return ocispec.Descriptor{}, MalformedSignatureError{Msg: fmt.Sprintf("invalid payload of content type %q: %v", payload.ContentType, err)}
+	}
+	if payload.ContentType != MediaTypePayloadV1 {
+		return desc, nil
+	}
+	canonical, err := json.Marshal(envelope.Payload{TargetArtifact: envelope.SanitizeTargetArtifact(desc)})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if bytes.Equal(payload.Content, canonical) {
+		return desc, nil
+	}
+	// JWS envelopes encode the payload as JWT claims, sorted by name
+	decoder := json.NewDecoder(bytes.NewReader(canonical))
+	decoder.UseNumber()
+	var claims map[string]any
+	if err := decoder.Decode(&claims); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	sortedCanonical, err := json.Marshal(claims)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if !bytes.Equal(payload.Content, sortedCanonical) {
+		return ocispec.Descriptor{}, MalformedSignatureError{Msg: fmt.Sprintf("payload of content type %q is not canonically encoded", payload.ContentType)}
+	}
+	return desc, nil
+}
+
+// CheckSignatureEnvelope checks that the signature envelope sig of type
+// envelopeMediaType is well-formed, independent of any trust evaluation:
+// the envelope must be parsed, its signature must be valid against the
+// signing certificate embedded in the envelope, and its payload must pass
+// [CheckPayload]. The trust in the signing certificate is not evaluated.
+//
+// The descriptor of the target artifact is returned. A
+// [MalformedSignatureError] is returned if the envelope is malformed.
+func CheckSignatureEnvelope(envelopeMediaType string, sig []byte) (ocispec.Descriptor, error) {
+	if err := validateSigMediaType(envelopeMediaType); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	sigEnv, err := signature.ParseEnvelope(envelopeMediaType, sig)
+	if err != nil {
+		return ocispec.Descriptor{}, MalformedSignatureError{Msg: fmt.Sprintf("malformed signature envelope: %v", err)}
+	}
+	envContent, err := sigEnv.Verify()
+	if err != nil {
+		return ocispec.Descriptor{}, MalformedSignatureError{Msg: fmt.Sprintf("invalid signature envelope: %v", err)}
+	}
+	return CheckPayload(&envContent.Payload)
+}
+
+// checkTargetArtifact checks that the descriptor of the target artifact desc
+// is well-formed.
+func checkTargetArtifact(desc ocispec.Descriptor) error {
+	if _, _, err := mime.ParseMediaType(desc.MediaType); err != nil {
+		return fmt.Errorf("targetArtifact.mediaType %q is invalid: %v", desc.MediaType, err)
+	}
+	if digestAlgorithmStrength(desc.Digest.Algorithm()) == 0 {
+		return fmt.Errorf("targetArtifact.digest algorithm %q is not supported", desc.Digest.Algorithm())
+	}
+	for key := range desc.Annotations {
+		if key == "" {
+			return errors.New("targetArtifact.annotations cannot have an empty key")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestValidatePayload(t *testing.T) {
+	want := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("manifest"),
+		Size:      8,
+	}
+	content, err := json.Marshal(map[string]any{"targetArtifact": want})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ValidatePayload(&signature.Payload{ContentType: MediaTypePayloadV1, Content: content})
+	if err != nil {
+		t.Fatalf("ValidatePayload() error = %v", err)
+	}
+	if got.Digest != want.Digest || got.Size != want.Size || got.MediaType != want.MediaType {
+		t.Fatalf("ValidatePayload() = %+v, want %+v", got, want)
+	}
+	if _, err := ValidatePayload(&signature.Payload{ContentType: MediaTypePayloadV1, Content: []byte(`{"targetArtifact":{}}`)}); err == nil {
+		t.Fatal("ValidatePayload() of an invalid payload expected error")
+	}
+}
+
+func TestRegisterPayloadType(t *testing.T) {
+	const contentType = "application/vnd.example.notation-test+json"
+	if err := RegisterPayloadType(contentType, nil); err == nil {
+		t.Fatal("RegisterPayloadType() with nil parser expected error")
+	}
+	err := RegisterPayloadType(contentType, func(content []byte) (ocispec.Descriptor, error) {
+		return ocispec.Descriptor{MediaType: "application/vnd.example.firmware", Digest: digest.FromBytes(content)}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterPayloadType() error = %v", err)
+	}
+	got, err := ValidatePayload(&signature.Payload{ContentType: contentType, Content: []byte("firmware")})
+	if err != nil {
+		t.Fatalf("ValidatePayload() error = %v", err)
+	}
+	if got.Digest != digest.FromString("firmware") {
+		t.Fatalf("ValidatePayload() = %+v", got)
+	}
+}
+
+func TestCheckPayload(t *testing.T) {
+	desc := ocispec.Descriptor{
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Digest:      digest.FromString("manifest"),
+		Size:        8,
+		Annotations: map[string]string{"b": "2", "a": "1"},
+	}
+	canonical := `{"targetArtifact":{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"` + desc.Digest.String() + `","size":8,"annotations":{"a":"1","b":"2"}}}`
+	got, err := CheckPayload(&signature.Payload{ContentType: MediaTypePayloadV1, Content: []byte(canonical)})
+	if err != nil {
+		t.Fatalf("CheckPayload() error = %v", err)
+	}
+	if got.Digest != desc.Digest {
+		t.Fatalf("CheckPayload() = %+v, want %+v", got, desc)
+	}
+
+	// JWS payloads are sorted by name
+	sortedCanonical := `{"targetArtifact":{"annotations":{"a":"1","b":"2"},"digest":"` + desc.Digest.String() + `","mediaType":"application/vnd.oci.image.manifest.v1+json","size":8}}`
+	if _, err := CheckPayload(&signature.Payload{ContentType: MediaTypePayloadV1, Content: []byte(sortedCanonical)}); err != nil {
+		t.Fatalf("CheckPayload() sorted error = %v", err)
+	}
+
+	tests := map[string]string{
+		"whitespace":         `{"targetArtifact": {"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"` + desc.Digest.String() + `","size":8}}`,
+		"member order":       `{"targetArtifact":{"mediaType":"application/vnd.oci.image.manifest.v1+json","size":8,"digest":"` + desc.Digest.String() + `"}}`,
+		"unsorted keys":      `{"targetArtifact":{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"` + desc.Digest.String() + `","size":8,"annotations":{"b":"2","a":"1"}}}`,
+		"unknown member":     `{"targetArtifact":{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"` + desc.Digest.String() + `","size":8,"artifactType":"application/vnd.test"}}`,
+		"invalid media type": `{"targetArtifact":{"mediaType":"manifest/","digest":"` + desc.Digest.String() + `","size":8}}`,
+		"unsupported digest": `{"targetArtifact":{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"sha256:` + desc.Digest.Encoded()[:10] + `","size":8}}`,
+		"empty annotation":   `{"targetArtifact":{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"` + desc.Digest.String() + `","size":8,"annotations":{"":"1"}}}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := CheckPayload(&signature.Payload{ContentType: MediaTypePayloadV1, Content: []byte(content)})
+			var malformedErr MalformedSignatureError
+			if !errors.As(err, &malformedErr) {
+				t.Fatalf("CheckPayload() error = %v, want MalformedSignatureError", err)
+			}
+		})
+	}
+}
+
+func TestCheckSignatureEnvelope(t *testing.T) {
+	desc, err := CheckSignatureEnvelope(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv)
+	if err != nil {
+		t.Fatalf("CheckSignatureEnvelope() error = %v", err)
+	}
+	if desc.Digest != mock.SampleDigest {
+		t.Fatalf("CheckSignatureEnvelope() = %+v", desc)
+	}
+	var malformedErr MalformedSignatureError
+	if _, err := CheckSignatureEnvelope(jws.MediaTypeEnvelope, []byte("{}")); !errors.As(err, &malformedErr) {
+		t.Fatalf("CheckSignatureEnvelope() error = %v, want MalformedSignatureError", err)
+	}
+	if _, err := CheckSignatureEnvelope("application/unknown", mock.MockCaValidSigEnv); err == nil {
+		t.Fatal("CheckSignatureEnvelope() expects error for an unknown envelope type")
+	}
+}
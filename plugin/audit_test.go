@@ -0,0 +1,62 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+func TestAuditExecution(t *testing.T) {
+	defer func(oldExecutor commander) {
+		executor = oldExecutor
+	}(executor)
+
+	var events []audit.Event
+	ctx := audit.WithSink(context.Background(), audit.SinkFunc(func(ctx context.Context, event audit.Event) {
+		events = append(events, event)
+	}))
+	output, err := json.Marshal(proto.DescribeKeyResponse{KeyID: "1", KeySpec: "RSA-4096"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := CLIPlugin{name: "foo"}
+
+	executor = testCommander{stdout: output}
+	if _, err := p.DescribeKey(ctx, &proto.DescribeKeyRequest{}); err != nil {
+		t.Fatalf("DescribeKey() error = %v", err)
+	}
+	executor = testCommander{err: errors.New("failed")}
+	if _, err := p.DescribeKey(ctx, &proto.DescribeKeyRequest{}); err == nil {
+		t.Fatal("DescribeKey() expected error")
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	for i, want := range []string{audit.ResultSuccess, audit.ResultFailure} {
+		event := events[i]
+		if event.Type != audit.EventPluginExecuted || event.Plugin != "foo" || event.Command != string(proto.CommandDescribeKey) || event.Result != want {
+			t.Fatalf("events[%d] = %+v", i, event)
+		}
+	}
+	if events[1].Reason == "" {
+		t.Fatal("events[1].Reason is empty")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-go/dump"
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+func TestDumpExecution(t *testing.T) {
+	defer func(oldExecutor commander) {
+		executor = oldExecutor
+	}(executor)
+
+	var buf bytes.Buffer
+	ctx := dump.WithDumper(context.Background(), dump.NewDumper(&buf))
+	output, err := json.Marshal(proto.DescribeKeyResponse{KeyID: "1", KeySpec: "RSA-4096"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := CLIPlugin{name: "foo"}
+	executor = testCommander{stdout: output}
+	if _, err := p.DescribeKey(ctx, &proto.DescribeKeyRequest{KeyID: "1", PluginConfig: map[string]string{"apiKey": "secret"}}); err != nil {
+		t.Fatalf("DescribeKey() error = %v", err)
+	}
+	// the response is recorded whether or not it is valid
+	p.GenerateSignatureStream(ctx, &proto.GenerateSignatureStreamRequest{KeyID: "1"}, strings.NewReader("payload"))
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Fatalf("records contain secrets: %s", buf.String())
+	}
+	var records []dump.Record
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record dump.Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].Plugin != "foo" || records[0].Command != string(proto.CommandDescribeKey) || !strings.Contains(string(records[0].PluginResponse), "RSA-4096") {
+		t.Fatalf("records[0] = %+v", records[0])
+	}
+	if records[1].Command != string(proto.CommandGenerateSignatureStream) || !strings.Contains(string(records[1].PluginRequest), `"keyId":"1"`) {
+		t.Fatalf("records[1] = %+v", records[1])
+	}
+}
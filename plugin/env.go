@@ -0,0 +1,123 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+type contextKey int
+
+// extraEnvKey is the associated key type for the extra environment variables
+// in context.
+const extraEnvKey contextKey = iota
+
+// Environment controls the environment variables passed to the plugin
+// process.
+//
+// Variable names in Allow and Deny are matched exactly, or by prefix if they
+// end with "*", e.g. "AWS_*". Names are matched case-insensitively on
+// Windows.
+type Environment struct {
+	// Allow is the list of the environment variables of the current process
+	// that are passed to the plugin process. If empty, all environment
+	// variables not matching Deny are passed.
+	Allow []string
+
+	// Deny is the list of the environment variables of the current process
+	// that are never passed to the plugin process. Deny takes precedence over
+	// Allow.
+	Deny []string
+
+	// Extra is the set of environment variables explicitly passed to the
+	// plugin process regardless of Allow and Deny.
+	Extra map[string]string
+}
+
+// isZero returns true if the plugin process inherits the full environment of
+// the current process.
+func (e Environment) isZero() bool {
+	return len(e.Allow) == 0 && len(e.Deny) == 0 && len(e.Extra) == 0
+}
+
+// WithExtraEnv returns a context carrying extra environment variables that
+// are passed to the plugin processes executed with the context, e.g. a
+// per-invocation credential. They take precedence over
+// [Environment].Extra.
+func WithExtraEnv(ctx context.Context, env map[string]string) context.Context {
+	return context.WithValue(ctx, extraEnvKey, env)
+}
+
+// extraEnv returns the extra environment variables carried by ctx.
+func extraEnv(ctx context.Context) map[string]string {
+	env, _ := ctx.Value(extraEnvKey).(map[string]string)
+	return env
+}
+
+// build returns the environment of the plugin process in the "key=value"
+// form given the environment of the current process and the per-invocation
+// extra environment variables. It returns nil if the plugin process inherits
+// the full environment of the current process.
+func (e Environment) build(environ []string, invocationEnv map[string]string) []string {
+	if e.isZero() && len(invocationEnv) == 0 {
+		return nil
+	}
+	env := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if len(e.Allow) > 0 && !matchEnv(e.Allow, name) {
+			continue
+		}
+		if matchEnv(e.Deny, name) {
+			continue
+		}
+		env = append(env, kv)
+	}
+	// exec.Cmd takes the last value for duplicated keys
+	env = appendEnv(env, e.Extra)
+	return appendEnv(env, invocationEnv)
+}
+
+// appendEnv appends the variables in the "key=value" form sorted by key.
+func appendEnv(env []string, vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+vars[k])
+	}
+	return env
+}
+
+// matchEnv returns true if name matches any of the patterns.
+func matchEnv(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if runtime.GOOS == "windows" {
+			pattern, name = strings.ToUpper(pattern), strings.ToUpper(name)
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if pattern == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/slices"
+)
+
+func TestEnvironment_Build(t *testing.T) {
+	environ := []string{"HOME=/home/user", "PATH=/usr/bin", "AWS_SECRET_ACCESS_KEY=secret", "AWS_REGION=us-west-2", "TOKEN=abc"}
+	tests := []struct {
+		name          string
+		env           Environment
+		invocationEnv map[string]string
+		want          []string
+	}{
+		{
+			name: "inherit",
+			env:  Environment{},
+			want: nil,
+		},
+		{
+			name: "deny",
+			env:  Environment{Deny: []string{"AWS_SECRET_*", "TOKEN"}},
+			want: []string{"HOME=/home/user", "PATH=/usr/bin", "AWS_REGION=us-west-2"},
+		},
+		{
+			name: "allow",
+			env:  Environment{Allow: []string{"PATH", "AWS_*"}},
+			want: []string{"PATH=/usr/bin", "AWS_SECRET_ACCESS_KEY=secret", "AWS_REGION=us-west-2"},
+		},
+		{
+			name: "deny takes precedence over allow",
+			env:  Environment{Allow: []string{"AWS_*"}, Deny: []string{"AWS_SECRET_ACCESS_KEY"}},
+			want: []string{"AWS_REGION=us-west-2"},
+		},
+		{
+			name:          "extra",
+			env:           Environment{Allow: []string{"PATH"}, Extra: map[string]string{"B": "2", "A": "1"}},
+			invocationEnv: map[string]string{"A": "3"},
+			want:          []string{"PATH=/usr/bin", "A=1", "B=2", "A=3"},
+		},
+		{
+			name:          "invocation only",
+			invocationEnv: map[string]string{"A": "1"},
+			want:          append(append([]string{}, environ...), "A=1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.env.build(environ, tt.invocationEnv); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCLIPlugin_Environment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+	t.Setenv("NOTATION_TEST_SECRET", "secret")
+	t.Setenv("NOTATION_TEST_VISIBLE", "visible")
+	p, err := NewCLIPluginWithOptions(context.Background(), "env", "./testdata/plugins/foo/notation-foo", CLIPluginOptions{
+		Environment: Environment{
+			Deny:  []string{"NOTATION_TEST_SECRET"},
+			Extra: map[string]string{"NOTATION_TEST_EXTRA": "extra"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor = &execCommander{}
+	ctx := WithExtraEnv(context.Background(), map[string]string{"NOTATION_TEST_INVOCATION": "invocation"})
+	stdout, _, err := p.commander(ctx).Output(ctx, "env", "-0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := strings.Split(string(stdout), "\x00")
+	for _, want := range []string{"NOTATION_TEST_VISIBLE=visible", "NOTATION_TEST_EXTRA=extra", "NOTATION_TEST_INVOCATION=invocation"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("plugin environment does not contain %q", want)
+		}
+	}
+	if slices.Contains(env, "NOTATION_TEST_SECRET=secret") {
+		t.Error("plugin environment contains denied variable NOTATION_TEST_SECRET")
+	}
+}
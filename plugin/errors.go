@@ -22,6 +22,19 @@ var ErrNotCompliant = errors.New("plugin not compliant")
 // ErrNotRegularFile is returned when the plugin file is not an regular file.
 var ErrNotRegularFile = errors.New("plugin executable file is not a regular file")
 
+// ErrHealthCheckNotSupported is returned when the plugin does not have the
+// HEALTH_CHECK capability.
+var ErrHealthCheckNotSupported = errors.New("plugin does not support health check")
+
+// ErrResponseTooLarge is returned when the plugin output exceeds the
+// maximum response size.
+var ErrResponseTooLarge = errors.New("plugin response exceeds the maximum size")
+
+// ErrPluginNotSigned is returned, wrapped in a [PluginIntegrityError], when a
+// signature verifier is configured and the signature file of an installed
+// plugin is not found.
+var ErrPluginNotSigned = errors.New("plugin is not signed")
+
 // PluginDowngradeError is returned when installing a plugin with version
 // lower than the exisiting plugin version.
 type PluginDowngradeError struct {
@@ -94,3 +107,26 @@ func (e PluginExecutableFileError) Error() string {
 func (e PluginExecutableFileError) Unwrap() error {
 	return e.InnerError
 }
+
+// PluginIntegrityError is used when the plugin executable file does not match
+// its recorded digest and therefore must not be executed.
+type PluginIntegrityError struct {
+	Msg        string
+	InnerError error
+}
+
+// Error returns the error message.
+func (e PluginIntegrityError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.InnerError != nil {
+		return e.InnerError.Error()
+	}
+	return "plugin executable file failed integrity verification"
+}
+
+// Unwrap returns the inner error.
+func (e PluginIntegrityError) Unwrap() error {
+	return e.InnerError
+}
@@ -0,0 +1,94 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/notaryproject/notation-go/internal/mock/mockfs"
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+// testCommandCommander responds based on the plugin command.
+type testCommandCommander map[proto.Command][]byte
+
+func (t testCommandCommander) Output(ctx context.Context, path string, command proto.Command, req []byte) ([]byte, []byte, error) {
+	stdout, ok := t[command]
+	if !ok {
+		return nil, []byte(`{"errorCode":"VALIDATION_ERROR","errorMessage":"unsupported command"}`), errors.New("exit status 1")
+	}
+	return stdout, nil, nil
+}
+
+func TestCLIManager_HealthCheck(t *testing.T) {
+	defer func(old commander) { executor = old }(executor)
+	ctx := context.Background()
+	mgr := NewCLIManager(mockfs.NewSysFSWithRootMock(fstest.MapFS{}, "./testdata/plugins"))
+	healthCheckMetadata := validMetadata
+	healthCheckMetadata.Capabilities = append([]proto.Capability{proto.CapabilityHealthCheck}, validMetadata.Capabilities...)
+
+	t.Run("not supported", func(t *testing.T) {
+		executor = testCommandCommander{proto.CommandGetMetadata: metadataJSON(validMetadata)}
+		if _, err := mgr.HealthCheck(ctx, "foo", &proto.CheckHealthRequest{}); !errors.Is(err, ErrHealthCheckNotSupported) {
+			t.Fatalf("HealthCheck() error = %v, want ErrHealthCheckNotSupported", err)
+		}
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		executor = testCommandCommander{
+			proto.CommandGetMetadata: metadataJSON(healthCheckMetadata),
+			proto.CommandCheckHealth: healthJSON(t, proto.CheckHealthResponse{Healthy: true}),
+		}
+		resp, err := mgr.HealthCheck(ctx, "foo", &proto.CheckHealthRequest{KeyID: "key"})
+		if err != nil {
+			t.Fatalf("HealthCheck() error = %v", err)
+		}
+		if !resp.Healthy {
+			t.Fatal("HealthCheck() expected healthy response")
+		}
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		executor = testCommandCommander{
+			proto.CommandGetMetadata: metadataJSON(healthCheckMetadata),
+			proto.CommandCheckHealth: healthJSON(t, proto.CheckHealthResponse{
+				Checks: []proto.HealthCheck{
+					{Name: "credentials", Healthy: true},
+					{Name: "kms", Message: "dial tcp: connection refused"},
+				},
+			}),
+		}
+		resp, err := mgr.HealthCheck(ctx, "foo", &proto.CheckHealthRequest{})
+		wantErr := "plugin foo is unhealthy: kms: dial tcp: connection refused"
+		if err == nil || err.Error() != wantErr {
+			t.Fatalf("HealthCheck() error = %v, want %s", err, wantErr)
+		}
+		if resp == nil || len(resp.Checks) != 2 {
+			t.Fatalf("HealthCheck() response = %+v, want the plugin response", resp)
+		}
+	})
+}
+
+func healthJSON(t *testing.T, resp proto.CheckHealthResponse) []byte {
+	t.Helper()
+	d, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
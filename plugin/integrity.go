@@ -0,0 +1,124 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/notaryproject/notation-go/internal/file"
+)
+
+// LockFile reflects the plugins.lock.json file. It records the expected
+// SHA-256 digests of the installed plugin executable files, keyed by plugin
+// name.
+type LockFile struct {
+	Plugins map[string]LockedPlugin `json:"plugins"`
+}
+
+// LockedPlugin is a plugin entry of the [LockFile].
+type LockedPlugin struct {
+	// Version is the plugin version at the time the entry was recorded.
+	Version string `json:"version,omitempty"`
+
+	// SHA256 is the hex-encoded SHA-256 digest of the plugin executable file.
+	SHA256 string `json:"sha256"`
+}
+
+// NewLockFile creates a new empty plugin lock file.
+func NewLockFile() *LockFile {
+	return &LockFile{Plugins: map[string]LockedPlugin{}}
+}
+
+// LoadLockFile reads the plugin lock file at path or returns an empty lock
+// file if not found.
+func LoadLockFile(path string) (*LockFile, error) {
+	fileInfo, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return NewLockFile(), nil
+		}
+		return nil, err
+	}
+	mode := fileInfo.Mode()
+	if mode.IsDir() || mode&fs.ModeSymlink != 0 {
+		return nil, fmt.Errorf("%q is not a regular file (symlinks are not supported)", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lockFile LockFile
+	if err := json.Unmarshal(data, &lockFile); err != nil {
+		return nil, fmt.Errorf("malformed plugin lock file %q: %w", path, err)
+	}
+	if lockFile.Plugins == nil {
+		lockFile.Plugins = map[string]LockedPlugin{}
+	}
+	return &lockFile, nil
+}
+
+// Save atomically stores the lock file to path.
+func (l *LockFile) Save(path string) error {
+	data, err := json.MarshalIndent(l, "", "    ")
+	if err != nil {
+		return err
+	}
+	lockFileDir := filepath.Dir(path)
+	if err := os.MkdirAll(lockFileDir, 0700); err != nil {
+		return err
+	}
+	return file.WriteFile(lockFileDir, path, data)
+}
+
+// PluginDigest returns the hex-encoded SHA-256 digest of the plugin
+// executable file at path.
+func PluginDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifyPluginDigest checks that the digest of the plugin executable file at
+// path matches the expected hex-encoded SHA-256 digest.
+func verifyPluginDigest(name, path, expected string) error {
+	actual, err := PluginDigest(path)
+	if err != nil {
+		return &PluginIntegrityError{
+			Msg:        fmt.Sprintf("failed to compute the digest of plugin %s: %v", name, err),
+			InnerError: err,
+		}
+	}
+	if !strings.EqualFold(actual, expected) {
+		return &PluginIntegrityError{
+			Msg: fmt.Sprintf("plugin %s executable file has been modified: expected sha256 digest %s, got %s", name, expected, actual),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/notaryproject/notation-go/internal/mock/mockfs"
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+// emptyFileDigest is the SHA-256 digest of an empty file.
+const emptyFileDigest = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestLockFile_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "plugins.lock.json")
+	lockFile, err := LoadLockFile(path)
+	if err != nil {
+		t.Fatalf("LoadLockFile() on missing file error = %v", err)
+	}
+	if len(lockFile.Plugins) != 0 {
+		t.Fatalf("expected empty lock file, got %v", lockFile.Plugins)
+	}
+	lockFile.Plugins["foo"] = LockedPlugin{Version: "1.0.0", SHA256: emptyFileDigest}
+	if err := lockFile.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadLockFile(path)
+	if err != nil {
+		t.Fatalf("LoadLockFile() error = %v", err)
+	}
+	if got := loaded.Plugins["foo"]; got.SHA256 != emptyFileDigest || got.Version != "1.0.0" {
+		t.Fatalf("LoadLockFile() got entry %+v", got)
+	}
+}
+
+func TestLoadLockFile_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plugins.lock.json")
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLockFile(path); err == nil {
+		t.Fatal("expected error for malformed lock file")
+	}
+}
+
+func TestCLIPlugin_IntegrityCheck(t *testing.T) {
+	ctx := context.Background()
+	executor = testCommander{stdout: metadataJSON(validMetadata)}
+
+	t.Run("matching digest", func(t *testing.T) {
+		p, err := NewCLIPluginWithOptions(ctx, "foo", "./testdata/plugins/foo/notation-foo", CLIPluginOptions{SHA256: emptyFileDigest})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.GetMetadata(ctx, &proto.GetMetadataRequest{}); err != nil {
+			t.Fatalf("GetMetadata() error = %v", err)
+		}
+	})
+
+	t.Run("modified executable", func(t *testing.T) {
+		p, err := NewCLIPluginWithOptions(ctx, "foo", "./testdata/plugins/foo/notation-foo", CLIPluginOptions{SHA256: "0000"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = p.GetMetadata(ctx, &proto.GetMetadataRequest{})
+		var integrityErr *PluginIntegrityError
+		if !errors.As(err, &integrityErr) {
+			t.Fatalf("GetMetadata() error = %v, want PluginIntegrityError", err)
+		}
+	})
+}
+
+func TestManager_GetWithLockFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+	ctx := context.Background()
+	executor = testCommander{stdout: metadataJSON(validMetadata)}
+	lockFilePath := filepath.Join(t.TempDir(), "plugins.lock.json")
+	mgr := NewCLIManagerWithOptions(mockfs.NewSysFSWithRootMock(fstest.MapFS{}, "./testdata/plugins"), CLIManagerOptions{
+		LockFilePath: lockFilePath,
+	})
+
+	var integrityErr *PluginIntegrityError
+	if _, err := mgr.Get(ctx, "foo"); !errors.As(err, &integrityErr) {
+		t.Fatalf("Get() of unrecorded plugin error = %v, want PluginIntegrityError", err)
+	}
+
+	if err := mgr.Lock(ctx, "foo"); err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if _, err := mgr.Get(ctx, "foo"); err != nil {
+		t.Fatalf("Get() of recorded plugin error = %v", err)
+	}
+
+	lockFile := &LockFile{Plugins: map[string]LockedPlugin{"foo": {SHA256: "0000"}}}
+	if err := lockFile.Save(lockFilePath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Get(ctx, "foo"); !errors.As(err, &integrityErr) {
+		t.Fatalf("Get() of modified plugin error = %v, want PluginIntegrityError", err)
+	}
+}
+
+type testSignatureVerifier struct {
+	err error
+}
+
+func (v testSignatureVerifier) VerifyPluginSignature(ctx context.Context, name string, executable io.Reader, signature []byte) error {
+	return v.err
+}
+
+func TestManager_GetWithSignatureVerifier(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+	ctx := context.Background()
+	executor = testCommander{stdout: metadataJSON(validMetadata)}
+	pluginDir := t.TempDir()
+	executablePath := filepath.Join(pluginDir, "foo", "notation-foo")
+	if err := os.MkdirAll(filepath.Dir(executablePath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(executablePath, nil, 0700); err != nil {
+		t.Fatal(err)
+	}
+	pluginFS := mockfs.NewSysFSWithRootMock(os.DirFS(pluginDir), pluginDir)
+
+	var integrityErr *PluginIntegrityError
+	mgr := NewCLIManagerWithOptions(pluginFS, CLIManagerOptions{SignatureVerifier: testSignatureVerifier{}})
+	_, err := mgr.Get(ctx, "foo")
+	if !errors.As(err, &integrityErr) || !errors.Is(err, ErrPluginNotSigned) {
+		t.Fatalf("Get() of unsigned plugin error = %v, want PluginIntegrityError with ErrPluginNotSigned", err)
+	}
+	// an unsigned plugin is installed
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Get() of unsigned plugin error = %v, want not fs.ErrNotExist", err)
+	}
+
+	if err := os.WriteFile(executablePath+SignatureFileExtension, []byte("sig"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.Get(ctx, "foo"); err != nil {
+		t.Fatalf("Get() of signed plugin error = %v", err)
+	}
+
+	mgr = NewCLIManagerWithOptions(pluginFS, CLIManagerOptions{SignatureVerifier: testSignatureVerifier{err: errors.New("invalid signature")}})
+	if _, err := mgr.Get(ctx, "foo"); !errors.As(err, &integrityErr) {
+		t.Fatalf("Get() of plugin with invalid signature error = %v, want PluginIntegrityError", err)
+	}
+}
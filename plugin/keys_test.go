@@ -0,0 +1,76 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+func TestListKeys(t *testing.T) {
+	defer func(old commander) { executor = old }(executor)
+	want := proto.ListKeysResponse{Keys: []proto.KeyInfo{
+		{KeyID: "key1", KeySpec: "RSA-2048", Description: "release key"},
+		{KeyID: "key2"},
+	}}
+	output, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor = testCommander{stdout: output}
+	p := CLIPlugin{name: "foo"}
+	req := &proto.ListKeysRequest{}
+	resp, err := p.ListKeys(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ListKeys() error = %v", err)
+	}
+	if !reflect.DeepEqual(*resp, want) {
+		t.Fatalf("ListKeys() = %+v, want %+v", *resp, want)
+	}
+	if req.ContractVersion != proto.ContractVersion {
+		t.Fatalf("ListKeys() contract version = %q, want %q", req.ContractVersion, proto.ContractVersion)
+	}
+}
+
+func TestDescribeKeys(t *testing.T) {
+	defer func(old commander) { executor = old }(executor)
+	output, err := json.Marshal(proto.DescribeKeysResponse{Keys: []proto.DescribeKeyResponse{
+		{KeyID: "key1", KeySpec: "RSA-2048"},
+		{KeyID: "key2", KeySpec: "EC-384"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor = testCommander{stdout: output}
+	p := CLIPlugin{name: "foo"}
+
+	resp, err := p.DescribeKeys(context.Background(), &proto.DescribeKeysRequest{KeyIDs: []string{"key1", "key2"}})
+	if err != nil {
+		t.Fatalf("DescribeKeys() error = %v", err)
+	}
+	if len(resp.Keys) != 2 {
+		t.Fatalf("DescribeKeys() got %d keys, want 2", len(resp.Keys))
+	}
+
+	_, err = p.DescribeKeys(context.Background(), &proto.DescribeKeysRequest{KeyIDs: []string{"key1", "key3"}})
+	var malformedErr *PluginMalformedError
+	if !errors.As(err, &malformedErr) {
+		t.Fatalf("DescribeKeys() error = %v, want PluginMalformedError", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// startWithResourceLimits starts cmd with the memory and CPU limits applied
+// before the plugin executes any instruction.
+//
+// The child process is started traced, so that it stops right after execve.
+// The limits are set on the stopped process with the prlimit system call,
+// then the process is detached and resumed.
+func startWithResourceLimits(cmd *exec.Cmd, limits ExecutionLimits) error {
+	// ptrace requests must be issued by the thread that started the process
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Ptrace = true
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	pid := cmd.Process.Pid
+	abort := func(err error) error {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	// wait for the SIGTRAP stop after execve
+	var status syscall.WaitStatus
+	for {
+		_, err := syscall.Wait4(pid, &status, syscall.WALL, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return abort(fmt.Errorf("failed to wait for the plugin process: %w", err))
+		}
+		break
+	}
+	if !status.Stopped() {
+		return abort(fmt.Errorf("plugin process exited before its resource limits were set: %v", status))
+	}
+	if err := setResourceLimits(pid, limits); err != nil {
+		return abort(err)
+	}
+	if err := syscall.PtraceDetach(pid); err != nil {
+		return abort(fmt.Errorf("failed to resume the plugin process: %w", err))
+	}
+	return nil
+}
+
+// setResourceLimits sets the memory and CPU limits of the process pid with
+// the prlimit system call.
+func setResourceLimits(pid int, limits ExecutionLimits) error {
+	if limits.MaxMemory > 0 {
+		if err := prlimit(pid, syscall.RLIMIT_AS, limits.MaxMemory); err != nil {
+			return err
+		}
+	}
+	if limits.MaxCPUTime > 0 {
+		seconds := uint64(math.Ceil(limits.MaxCPUTime.Seconds()))
+		if err := prlimit(pid, syscall.RLIMIT_CPU, seconds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func prlimit(pid int, resource int, value uint64) error {
+	limit := syscall.Rlimit{Cur: value, Max: value}
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(&limit)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
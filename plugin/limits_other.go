@@ -0,0 +1,30 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package plugin
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// startWithResourceLimits returns error as memory and CPU limits are only
+// supported on Linux. The plugin is not executed without the requested
+// limits.
+func startWithResourceLimits(cmd *exec.Cmd, limits ExecutionLimits) error {
+	return fmt.Errorf("memory and CPU limits are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,127 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+// writeScript writes an executable shell script to a temp directory and
+// returns its path.
+func writeScript(t *testing.T, name, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExecCommander_MaxResponseSize(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+	_, _, err := execCommander{limits: ExecutionLimits{MaxResponseSize: 4}}.Output(context.Background(), "echo", "hello world", nil)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Output() error = %v, want %v", err, ErrResponseTooLarge)
+	}
+	// output of exactly the maximum size is accepted
+	stdout, _, err := execCommander{limits: ExecutionLimits{MaxResponseSize: 12}}.Output(context.Background(), "echo", "hello world", nil)
+	if err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+	if string(stdout) != "hello world\n" {
+		t.Fatalf("Output() stdout = %q", stdout)
+	}
+	stdout, _, err = execCommander{limits: ExecutionLimits{MaxResponseSize: 64}}.Output(context.Background(), "echo", "hello world", nil)
+	if err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+	if string(stdout) != "hello world\n" {
+		t.Fatalf("Output() stdout = %q", stdout)
+	}
+}
+
+func TestCLIPlugin_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+	executor = &execCommander{}
+	path := writeScript(t, "notation-slow", "sleep 5")
+	p, err := NewCLIPluginWithOptions(context.Background(), "slow", path, CLIPluginOptions{
+		Limits: ExecutionLimits{Timeout: 100 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = p.GetMetadata(context.Background(), &proto.GetMetadataRequest{})
+	var execErr *PluginExecutableFileError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("GetMetadata() error = %v, want PluginExecutableFileError", err)
+	}
+	if !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("GetMetadata() error = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Fatalf("GetMetadata() took %v, expected the plugin to be killed on timeout", elapsed)
+	}
+}
+
+func TestExecCommander_ResourceLimits(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are only supported on Linux")
+	}
+	// the limits are read as soon as the plugin starts
+	path := writeScript(t, "notation-limits", "ulimit -t; ulimit -v")
+	limits := ExecutionLimits{MaxCPUTime: 1500 * time.Millisecond, MaxMemory: 512 * 1024 * 1024}
+	stdout, _, err := execCommander{limits: limits}.Output(context.Background(), path, "get-plugin-metadata", nil)
+	if err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+	if got := strings.Fields(string(stdout)); !reflect.DeepEqual(got, []string{"2", "524288"}) {
+		t.Fatalf("CPU time and memory limits = %v, want [2 524288]", got)
+	}
+
+	// a plugin exceeding the memory limit cannot start
+	_, _, err = execCommander{limits: ExecutionLimits{MaxMemory: 1024}}.Output(context.Background(), path, "get-plugin-metadata", nil)
+	if err == nil {
+		t.Fatal("Output() should fail for a plugin exceeding the memory limit")
+	}
+}
+
+func TestCLIManager_ExecutionLimits(t *testing.T) {
+	defaultLimits := ExecutionLimits{Timeout: time.Minute}
+	fooLimits := ExecutionLimits{Timeout: time.Second, MaxResponseSize: 1024}
+	mgr := NewCLIManagerWithOptions(nil, CLIManagerOptions{
+		Limits:       defaultLimits,
+		PluginLimits: map[string]ExecutionLimits{"foo": fooLimits},
+	})
+	if got := mgr.executionLimits("foo"); got != fooLimits {
+		t.Fatalf("executionLimits(foo) = %+v, want %+v", got, fooLimits)
+	}
+	if got := mgr.executionLimits("bar"); got != defaultLimits {
+		t.Fatalf("executionLimits(bar) = %+v, want %+v", got, defaultLimits)
+	}
+}
@@ -21,11 +21,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/notaryproject/notation-go/dir"
 	"github.com/notaryproject/notation-go/internal/file"
 	"github.com/notaryproject/notation-go/internal/semver"
 	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin/proto"
 	"github.com/notaryproject/notation-plugin-framework-go/plugin"
 )
 
@@ -38,6 +40,38 @@ type Manager interface {
 // CLIManager implements [Manager]
 type CLIManager struct {
 	pluginFS dir.SysFS
+	opts     CLIManagerOptions
+}
+
+// CLIManagerOptions provides user options when creating a [CLIManager].
+type CLIManagerOptions struct {
+	// LockFilePath is the system path of the plugin lock file, e.g.
+	// dir.ConfigFS().SysPath(dir.PathPluginLockFile).
+	//
+	// If set, Install and Uninstall record the SHA-256 digests of the
+	// plugin executable files in the lock file, and Get only returns plugins
+	// whose executable file matches the recorded digest. The digest is
+	// verified again before every plugin execution.
+	LockFilePath string
+
+	// SignatureVerifier verifies the detached signatures of the plugin
+	// executable files.
+	//
+	// If set, Install and Get only accept plugins whose executable file
+	// notation-{plugin-name} is accompanied by a signature file
+	// notation-{plugin-name}.sig that passes the verification.
+	SignatureVerifier SignatureVerifier
+
+	// Limits restricts the execution of the plugins returned by Get and of
+	// the plugins being installed.
+	Limits ExecutionLimits
+
+	// PluginLimits overrides Limits for the plugins keyed by plugin name.
+	PluginLimits map[string]ExecutionLimits
+
+	// Environment controls the environment variables passed to the plugin
+	// processes.
+	Environment Environment
 }
 
 // NewCLIManager returns CLIManager for named pluginFS.
@@ -45,18 +79,124 @@ func NewCLIManager(pluginFS dir.SysFS) *CLIManager {
 	return &CLIManager{pluginFS: pluginFS}
 }
 
+// NewCLIManagerWithOptions returns CLIManager for named pluginFS with user
+// specified options.
+func NewCLIManagerWithOptions(pluginFS dir.SysFS, opts CLIManagerOptions) *CLIManager {
+	return &CLIManager{
+		pluginFS: pluginFS,
+		opts:     opts,
+	}
+}
+
 // Get returns a plugin on the system by its name.
 //
 // If the plugin is not found, the error is of type os.ErrNotExist.
+// If a lock file is configured and the plugin has no recorded digest or its
+// executable file does not match the recorded digest, the error is of type
+// *PluginIntegrityError. The same applies if a signature verifier is
+// configured and the plugin signature is missing or invalid.
 func (m *CLIManager) Get(ctx context.Context, name string) (plugin.Plugin, error) {
-	pluginPath := path.Join(name, binName(name))
-	path, err := m.pluginFS.SysPath(pluginPath)
+	p, err := m.get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// get returns the named CLI plugin.
+func (m *CLIManager) get(ctx context.Context, name string) (*CLIPlugin, error) {
+	path, err := m.executablePath(name)
 	if err != nil {
 		return nil, err
 	}
+	return m.load(ctx, name, name, path)
+}
 
+// load validates and creates the named CLI plugin from the executable file at
+// path. lockKey is the key of the plugin in the plugin lock file.
+func (m *CLIManager) load(ctx context.Context, name, lockKey, path string) (*CLIPlugin, error) {
 	// validate and create plugin
-	return NewCLIPlugin(ctx, name, path)
+	p, err := NewCLIPluginWithOptions(ctx, name, path, m.pluginOptions(name))
+	if err != nil {
+		return nil, err
+	}
+	if m.opts.LockFilePath != "" {
+		lockFile, err := LoadLockFile(m.opts.LockFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load plugin lock file: %w", err)
+		}
+		entry, ok := lockFile.Plugins[lockKey]
+		if !ok {
+			return nil, &PluginIntegrityError{Msg: fmt.Sprintf("plugin %s has no recorded digest in the plugin lock file %s", lockKey, m.opts.LockFilePath)}
+		}
+		if err := verifyPluginDigest(name, path, entry.SHA256); err != nil {
+			return nil, err
+		}
+		p.opts.SHA256 = entry.SHA256
+	}
+	if m.opts.SignatureVerifier != nil {
+		if err := verifyPluginSignature(ctx, m.opts.SignatureVerifier, name, path); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Lock records the digest of the currently installed executable file of the
+// named plugin in the plugin lock file. It is used to trust plugins that were
+// installed before the lock file was configured.
+func (m *CLIManager) Lock(ctx context.Context, name string) error {
+	if m.opts.LockFilePath == "" {
+		return errors.New("plugin lock file is not configured")
+	}
+	path, err := m.executablePath(name)
+	if err != nil {
+		return err
+	}
+	p, err := NewCLIPluginWithOptions(ctx, name, path, m.pluginOptions(name))
+	if err != nil {
+		return err
+	}
+	metadata, err := p.GetMetadata(ctx, &plugin.GetMetadataRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get metadata of plugin %s: %w", name, err)
+	}
+	return m.recordDigest(name, path, metadata.Version)
+}
+
+// HealthCheck runs the check-health command of the named plugin, validating
+// that the plugin is correctly configured and can reach its backend before
+// signing starts.
+//
+// If the plugin does not have the HEALTH_CHECK capability, the error is
+// ErrHealthCheckNotSupported. If the plugin reports itself unhealthy, the
+// response is returned along with an error.
+func (m *CLIManager) HealthCheck(ctx context.Context, name string, req *proto.CheckHealthRequest) (*proto.CheckHealthResponse, error) {
+	p, err := m.get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := p.GetMetadata(ctx, &plugin.GetMetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata of plugin %s: %w", name, err)
+	}
+	if !metadata.HasCapability(proto.CapabilityHealthCheck) {
+		return nil, ErrHealthCheckNotSupported
+	}
+	resp, err := p.CheckHealth(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Healthy {
+		var failed []string
+		for _, check := range resp.Checks {
+			if !check.Healthy {
+				failed = append(failed, fmt.Sprintf("%s: %s", check.Name, check.Message))
+			}
+		}
+		return resp, fmt.Errorf("plugin %s is unhealthy: %s", name, strings.Join(failed, "; "))
+	}
+	return resp, nil
 }
 
 // List produces a list of the plugin names on the system.
@@ -103,6 +243,13 @@ type CLIInstallOptions struct {
 
 	// Overwrite is a boolean flag. When set, always install the new plugin.
 	Overwrite bool
+
+	// SideBySide is a boolean flag. When set, the plugin is installed as a
+	// side-by-side version next to the other installed versions of the
+	// plugin, instead of replacing the default version. Side-by-side
+	// versions are selected with [CLIManager.GetVersion] and
+	// [CLIManager.GetWithConstraint].
+	SideBySide bool
 }
 
 // Install installs a plugin to the system. It returns existing
@@ -148,8 +295,14 @@ func (m *CLIManager) Install(ctx context.Context, installOpts CLIInstallOptions)
 			return nil, nil, fmt.Errorf("input file %s is not executable", pluginExecutableFileName)
 		}
 	}
+	// verify the signature of the new plugin before executing it
+	if m.opts.SignatureVerifier != nil {
+		if err := verifyPluginSignature(ctx, m.opts.SignatureVerifier, pluginName, pluginExecutableFile); err != nil {
+			return nil, nil, err
+		}
+	}
 	// validate and get new plugin metadata
-	newPlugin, err := NewCLIPlugin(ctx, pluginName, pluginExecutableFile)
+	newPlugin, err := NewCLIPluginWithOptions(ctx, pluginName, pluginExecutableFile, m.pluginOptions(pluginName))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -157,6 +310,12 @@ func (m *CLIManager) Install(ctx context.Context, installOpts CLIInstallOptions)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get metadata of new plugin: %w", err)
 	}
+	if installOpts.SideBySide {
+		if err := m.installVersion(pluginName, newPluginMetadata.Version, pluginExecutableFile, installOpts, installFromNonDir); err != nil {
+			return nil, nil, err
+		}
+		return nil, newPluginMetadata, nil
+	}
 	// check plugin existence and get existing plugin metadata
 	var existingPluginMetadata *plugin.GetMetadataResponse
 	existingPlugin, err := m.Get(ctx, pluginName)
@@ -184,8 +343,9 @@ func (m *CLIManager) Install(ctx context.Context, installOpts CLIInstallOptions)
 			}
 		}
 	}
-	// clean up before installation, this guarantees idempotent for install
-	if err := m.Uninstall(ctx, pluginName); err != nil {
+	// clean up before installation, this guarantees idempotent for install.
+	// Side-by-side installed versions are kept.
+	if err := m.uninstallDefault(pluginName); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			return nil, nil, fmt.Errorf("failed to clean up plugin %s before installation: %w", pluginName, err)
 		}
@@ -195,13 +355,16 @@ func (m *CLIManager) Install(ctx context.Context, installOpts CLIInstallOptions)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get the system path of plugin %s: %w", pluginName, err)
 	}
-	if installFromNonDir {
-		if err := file.CopyToDir(pluginExecutableFile, pluginDirPath); err != nil {
-			return nil, nil, fmt.Errorf("failed to copy plugin executable file from %s to %s: %w", pluginExecutableFile, pluginDirPath, err)
+	if err := m.copyPluginFiles(pluginExecutableFile, installOpts.PluginPath, installFromNonDir, pluginDirPath); err != nil {
+		return nil, nil, err
+	}
+	if m.opts.LockFilePath != "" {
+		executablePath, err := m.executablePath(pluginName)
+		if err != nil {
+			return nil, nil, err
 		}
-	} else {
-		if err := file.CopyDirToDir(installOpts.PluginPath, pluginDirPath); err != nil {
-			return nil, nil, fmt.Errorf("failed to copy plugin files from %s to %s: %w", installOpts.PluginPath, pluginDirPath, err)
+		if err := m.recordDigest(pluginName, executablePath, newPluginMetadata.Version); err != nil {
+			return nil, nil, fmt.Errorf("failed to record digest of plugin %s: %w", pluginName, err)
 		}
 	}
 	return existingPluginMetadata, newPluginMetadata, nil
@@ -217,7 +380,120 @@ func (m *CLIManager) Uninstall(ctx context.Context, name string) error {
 	if _, err := os.Stat(pluginDirPath); err != nil {
 		return err
 	}
-	return os.RemoveAll(pluginDirPath)
+	if err := os.RemoveAll(pluginDirPath); err != nil {
+		return err
+	}
+	return m.removeLockEntries(func(key string) bool {
+		return key == name || strings.HasPrefix(key, name+"@")
+	})
+}
+
+// uninstallDefault removes the files of the default version of the named
+// plugin, keeping the side-by-side installed versions.
+// If the plugin dir does not exist, os.ErrNotExist is returned.
+func (m *CLIManager) uninstallDefault(name string) error {
+	pluginDirPath, err := m.pluginFS.SysPath(name)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(pluginDirPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() == versionsDir {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(pluginDirPath, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return m.removeLockEntries(func(key string) bool {
+		return key == name
+	})
+}
+
+// copyPluginFiles copies the plugin executable file, or the plugin files in
+// pluginPath if the plugin is not installed from a single file, to dst.
+func (m *CLIManager) copyPluginFiles(pluginExecutableFile, pluginPath string, installFromNonDir bool, dst string) error {
+	if !installFromNonDir {
+		if err := file.CopyDirToDir(pluginPath, dst); err != nil {
+			return fmt.Errorf("failed to copy plugin files from %s to %s: %w", pluginPath, dst, err)
+		}
+		return nil
+	}
+	if err := file.CopyToDir(pluginExecutableFile, dst); err != nil {
+		return fmt.Errorf("failed to copy plugin executable file from %s to %s: %w", pluginExecutableFile, dst, err)
+	}
+	if m.opts.SignatureVerifier != nil {
+		sigFile := signatureFilePath(pluginExecutableFile)
+		if err := file.CopyToDir(sigFile, dst); err != nil {
+			return fmt.Errorf("failed to copy plugin signature file from %s to %s: %w", sigFile, dst, err)
+		}
+	}
+	return nil
+}
+
+// removeLockEntries removes the plugin lock file entries whose keys match.
+func (m *CLIManager) removeLockEntries(match func(key string) bool) error {
+	if m.opts.LockFilePath == "" {
+		return nil
+	}
+	lockFile, err := LoadLockFile(m.opts.LockFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to load plugin lock file: %w", err)
+	}
+	var removed bool
+	for key := range lockFile.Plugins {
+		if match(key) {
+			delete(lockFile.Plugins, key)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return lockFile.Save(m.opts.LockFilePath)
+}
+
+// executablePath returns the system path of the executable file of the named
+// plugin.
+func (m *CLIManager) executablePath(name string) (string, error) {
+	return m.pluginFS.SysPath(path.Join(name, binName(name)))
+}
+
+// pluginOptions returns the options of the named plugin.
+func (m *CLIManager) pluginOptions(name string) CLIPluginOptions {
+	return CLIPluginOptions{
+		Limits:      m.executionLimits(name),
+		Environment: m.opts.Environment,
+	}
+}
+
+// executionLimits returns the execution limits of the named plugin.
+func (m *CLIManager) executionLimits(name string) ExecutionLimits {
+	if limits, ok := m.opts.PluginLimits[name]; ok {
+		return limits
+	}
+	return m.opts.Limits
+}
+
+// recordDigest computes the digest of the installed plugin executable file at
+// path and records it in the plugin lock file with lockKey.
+func (m *CLIManager) recordDigest(lockKey, path, version string) error {
+	digest, err := PluginDigest(path)
+	if err != nil {
+		return err
+	}
+	lockFile, err := LoadLockFile(m.opts.LockFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to load plugin lock file: %w", err)
+	}
+	lockFile.Plugins[lockKey] = LockedPlugin{
+		Version: version,
+		SHA256:  digest,
+	}
+	return lockFile.Save(m.opts.LockFilePath)
 }
 
 // parsePluginFromDir checks if a dir is a valid plugin dir which contains
@@ -253,8 +529,9 @@ func parsePluginFromDir(ctx context.Context, path string) (string, string, error
 		if err != nil {
 			return err
 		}
-		// only take regular files
-		if info.Mode().IsRegular() {
+		// only take regular files, detached plugin signature files are not
+		// plugin executable file candidates
+		if info.Mode().IsRegular() && filepath.Ext(d.Name()) != SignatureFileExtension {
 			if candidatePluginName, err = parsePluginName(d.Name()); err != nil {
 				// file name does not follow the notation-{plugin-name} format,
 				// continue
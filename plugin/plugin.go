@@ -22,12 +22,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/notaryproject/notation-go/internal/io"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/dump"
+	notationio "github.com/notaryproject/notation-go/internal/io"
 	"github.com/notaryproject/notation-go/internal/slices"
 	"github.com/notaryproject/notation-go/log"
 	"github.com/notaryproject/notation-go/plugin/proto"
@@ -37,6 +41,11 @@ import (
 // maxPluginOutputSize is the maximum size of the plugin output.
 const maxPluginOutputSize = 64 * 1024 * 1024 // 64 MiB
 
+// pluginWaitDelay is the maximum duration to wait for the plugin output to be
+// closed after the plugin process is killed, e.g. on timeout. It prevents
+// child processes of the plugin holding the output from stalling the caller.
+const pluginWaitDelay = 2 * time.Second
+
 var executor commander = &execCommander{} // for unit test
 
 // GenericPlugin is the base requirement to be a plugin.
@@ -67,10 +76,63 @@ type Plugin = plugin.Plugin
 type CLIPlugin struct {
 	name string
 	path string
+	opts CLIPluginOptions
+}
+
+// CLIPluginOptions provides user options when creating a [CLIPlugin].
+type CLIPluginOptions struct {
+	// SHA256 is the expected hex-encoded SHA-256 digest of the plugin
+	// executable file. If set, the digest of the executable file is verified
+	// before every execution and a modified plugin is not executed.
+	SHA256 string
+
+	// Limits restricts the execution of the plugin.
+	Limits ExecutionLimits
+
+	// Environment controls the environment variables passed to the plugin
+	// process. By default, the plugin process inherits the full environment
+	// of the current process.
+	Environment Environment
+}
+
+// ExecutionLimits restricts the execution of a CLI plugin. Zero values mean
+// no limit, except for MaxResponseSize which defaults to 64 MiB.
+type ExecutionLimits struct {
+	// Timeout is the maximum duration of a single plugin command execution.
+	// The plugin process is killed once the timeout is exceeded.
+	Timeout time.Duration
+
+	// MaxResponseSize is the maximum size in bytes of the plugin stdout and
+	// stderr output of a single plugin command execution.
+	MaxResponseSize int64
+
+	// MaxMemory is the maximum size in bytes of the virtual memory of the
+	// plugin process. The limit is applied before the plugin executes any
+	// instruction, which requires the plugin process to be traced with
+	// ptrace during its start.
+	//
+	// MaxMemory is only supported on Linux.
+	MaxMemory uint64
+
+	// MaxCPUTime is the maximum CPU time consumed by the plugin process,
+	// rounded up to seconds.
+	//
+	// MaxCPUTime is only supported on Linux.
+	MaxCPUTime time.Duration
+}
+
+// hasResourceLimits returns true if memory or CPU limits are set.
+func (l ExecutionLimits) hasResourceLimits() bool {
+	return l.MaxMemory > 0 || l.MaxCPUTime > 0
 }
 
 // NewCLIPlugin returns a *CLIPlugin.
 func NewCLIPlugin(ctx context.Context, name, path string) (*CLIPlugin, error) {
+	return NewCLIPluginWithOptions(ctx, name, path, CLIPluginOptions{})
+}
+
+// NewCLIPluginWithOptions returns a *CLIPlugin with user specified options.
+func NewCLIPluginWithOptions(ctx context.Context, name, path string, opts CLIPluginOptions) (*CLIPlugin, error) {
 	// validate file existence
 	fi, err := os.Stat(path)
 	if err != nil {
@@ -87,13 +149,14 @@ func NewCLIPlugin(ctx context.Context, name, path string) (*CLIPlugin, error) {
 	return &CLIPlugin{
 		name: name,
 		path: path,
+		opts: opts,
 	}, nil
 }
 
 // GetMetadata returns the metadata information of the plugin.
 func (p *CLIPlugin) GetMetadata(ctx context.Context, req *plugin.GetMetadataRequest) (*plugin.GetMetadataResponse, error) {
 	var metadata plugin.GetMetadataResponse
-	err := run(ctx, p.name, p.path, req, &metadata)
+	err := p.execute(ctx, req, &metadata)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +182,7 @@ func (p *CLIPlugin) DescribeKey(ctx context.Context, req *plugin.DescribeKeyRequ
 	}
 
 	var resp plugin.DescribeKeyResponse
-	err := run(ctx, p.name, p.path, req, &resp)
+	err := p.execute(ctx, req, &resp)
 	return &resp, err
 }
 
@@ -132,7 +195,7 @@ func (p *CLIPlugin) GenerateSignature(ctx context.Context, req *plugin.GenerateS
 	}
 
 	var resp plugin.GenerateSignatureResponse
-	err := run(ctx, p.name, p.path, req, &resp)
+	err := p.execute(ctx, req, &resp)
 	return &resp, err
 }
 
@@ -145,7 +208,7 @@ func (p *CLIPlugin) GenerateEnvelope(ctx context.Context, req *plugin.GenerateEn
 	}
 
 	var resp plugin.GenerateEnvelopeResponse
-	err := run(ctx, p.name, p.path, req, &resp)
+	err := p.execute(ctx, req, &resp)
 	return &resp, err
 }
 
@@ -158,11 +221,174 @@ func (p *CLIPlugin) VerifySignature(ctx context.Context, req *plugin.VerifySigna
 	}
 
 	var resp plugin.VerifySignatureResponse
-	err := run(ctx, p.name, p.path, req, &resp)
+	err := p.execute(ctx, req, &resp)
+	return &resp, err
+}
+
+// VerifyArtifactSignature validates the signature of an artifact like
+// VerifySignature, passing the artifact descriptor and the signature manifest
+// annotations to the plugin.
+//
+// if ContractVersion is not set, it will be set by the function.
+func (p *CLIPlugin) VerifyArtifactSignature(ctx context.Context, req *proto.VerifyArtifactSignatureRequest) (*plugin.VerifySignatureResponse, error) {
+	if req.ContractVersion == "" {
+		req.ContractVersion = plugin.ContractVersion
+	}
+
+	var resp plugin.VerifySignatureResponse
+	err := p.execute(ctx, req, &resp)
+	return &resp, err
+}
+
+// CheckHealth validates that the plugin is correctly configured and can reach
+// its backend. The plugin must have the HEALTH_CHECK capability.
+//
+// if ContractVersion is not set, it will be set by the function.
+func (p *CLIPlugin) CheckHealth(ctx context.Context, req *proto.CheckHealthRequest) (*proto.CheckHealthResponse, error) {
+	if req.ContractVersion == "" {
+		req.ContractVersion = plugin.ContractVersion
+	}
+
+	var resp proto.CheckHealthResponse
+	err := p.execute(ctx, req, &resp)
 	return &resp, err
 }
 
-func run(ctx context.Context, pluginName string, pluginPath string, req plugin.Request, resp interface{}) error {
+// ListKeys enumerates the keys available from the plugin. The plugin must
+// have the KEY_LISTING capability.
+//
+// if ContractVersion is not set, it will be set by the function.
+func (p *CLIPlugin) ListKeys(ctx context.Context, req *proto.ListKeysRequest) (*proto.ListKeysResponse, error) {
+	if req.ContractVersion == "" {
+		req.ContractVersion = plugin.ContractVersion
+	}
+
+	var resp proto.ListKeysResponse
+	err := p.execute(ctx, req, &resp)
+	return &resp, err
+}
+
+// DescribeKeys returns the KeySpecs of multiple keys in a single plugin
+// execution. The plugin must have the KEY_LISTING capability.
+//
+// if ContractVersion is not set, it will be set by the function.
+func (p *CLIPlugin) DescribeKeys(ctx context.Context, req *proto.DescribeKeysRequest) (*proto.DescribeKeysResponse, error) {
+	if req.ContractVersion == "" {
+		req.ContractVersion = plugin.ContractVersion
+	}
+
+	var resp proto.DescribeKeysResponse
+	if err := p.execute(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	described := make(map[string]bool, len(resp.Keys))
+	for _, key := range resp.Keys {
+		described[key.KeyID] = true
+	}
+	for _, keyID := range req.KeyIDs {
+		if !described[keyID] {
+			return nil, &PluginMalformedError{
+				Msg: fmt.Sprintf("the describe-keys response of plugin %s does not contain key %q", p.name, keyID),
+			}
+		}
+	}
+	return &resp, nil
+}
+
+// GetTrustStore returns the certificates of a named trust store provided by
+// the plugin. The plugin must have the TRUST_STORE capability.
+//
+// if ContractVersion is not set, it will be set by the function.
+func (p *CLIPlugin) GetTrustStore(ctx context.Context, req *proto.GetTrustStoreRequest) (*proto.GetTrustStoreResponse, error) {
+	if req.ContractVersion == "" {
+		req.ContractVersion = plugin.ContractVersion
+	}
+
+	var resp proto.GetTrustStoreResponse
+	if err := p.execute(ctx, req, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Certificates) == 0 {
+		return nil, &PluginMalformedError{
+			Msg: fmt.Sprintf("the get-trust-store response of plugin %s does not contain certificates", p.name),
+		}
+	}
+	return &resp, nil
+}
+
+// ResolveIdentity resolves the organizational identities of a signing
+// certificate. The plugin must have the IDENTITY_RESOLVER capability.
+//
+// if ContractVersion is not set, it will be set by the function.
+func (p *CLIPlugin) ResolveIdentity(ctx context.Context, req *proto.ResolveIdentityRequest) (*proto.ResolveIdentityResponse, error) {
+	if req.ContractVersion == "" {
+		req.ContractVersion = plugin.ContractVersion
+	}
+
+	var resp proto.ResolveIdentityResponse
+	err := p.execute(ctx, req, &resp)
+	return &resp, err
+}
+
+// execute runs the plugin command within the execution limits.
+func (p *CLIPlugin) execute(ctx context.Context, req plugin.Request, resp interface{}) (err error) {
+	defer func(ctx context.Context) {
+		auditExecution(ctx, p.name, req.Command(), err)
+	}(ctx)
+	ctx, cancel, err := p.prepare(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return run(ctx, p.commander(ctx), p.name, p.path, req, resp)
+}
+
+// auditExecution emits the audit event of the execution of the plugin
+// command, including the plugins refused to be executed.
+func auditExecution(ctx context.Context, pluginName string, command plugin.Command, err error) {
+	event := audit.Event{
+		Type:    audit.EventPluginExecuted,
+		Plugin:  pluginName,
+		Command: string(command),
+		Result:  audit.ResultSuccess,
+	}
+	if err != nil {
+		event.Result = audit.ResultFailure
+		event.Reason = err.Error()
+	}
+	audit.Emit(ctx, event)
+}
+
+// prepare verifies the integrity of the plugin executable file if a digest is
+// pinned, and returns the context of the plugin execution with the timeout
+// applied.
+func (p *CLIPlugin) prepare(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if p.opts.SHA256 != "" {
+		if err := verifyPluginDigest(p.name, p.path, p.opts.SHA256); err != nil {
+			log.GetLogger(ctx).Errorf("Refusing to execute plugin %s: %v", p.name, err)
+			return nil, nil, err
+		}
+	}
+	if p.opts.Limits.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, p.opts.Limits.Timeout)
+		return ctx, cancel, nil
+	}
+	return ctx, func() {}, nil
+}
+
+// commander returns the commander executing the plugin with the execution
+// limits and the environment applied. A mocked executor is returned as is.
+func (p *CLIPlugin) commander(ctx context.Context) commander {
+	if _, ok := executor.(*execCommander); ok {
+		return &execCommander{
+			limits: p.opts.Limits,
+			env:    p.opts.Environment.build(os.Environ(), extraEnv(ctx)),
+		}
+	}
+	return executor
+}
+
+func run(ctx context.Context, executor commander, pluginName string, pluginPath string, req plugin.Request, resp interface{}) error {
 	logger := log.GetLogger(ctx)
 
 	// serialize request
@@ -172,16 +398,26 @@ func run(ctx context.Context, pluginName string, pluginPath string, req plugin.R
 		return fmt.Errorf("failed to marshal request object: %w", err)
 	}
 
-	logger.Debugf("Plugin %s request: %s", req.Command(), string(data))
+	requestID := newRequestID()
+	logger.Debugf("Plugin %s request %s: %s", req.Command(), requestID, string(log.RedactJSON(ctx, data)))
 	// execute request
+	start := time.Now()
 	stdout, stderr, err := executor.Output(ctx, pluginPath, req.Command(), data)
+	dump.WritePlugin(ctx, pluginName, string(req.Command()), start, data, stdout, stderr, err)
+	return parseOutput(ctx, pluginName, req.Command(), requestID, stdout, stderr, err, resp)
+}
+
+// parseOutput parses the output of a plugin command execution into resp.
+func parseOutput(ctx context.Context, pluginName string, command plugin.Command, requestID string, stdout, stderr []byte, err error, resp interface{}) error {
+	logger := log.GetLogger(ctx)
+	logStderr(logger, pluginName, requestID, stderr)
 	if err != nil {
-		logger.Errorf("plugin %s execution status: %v", req.Command(), err)
+		logger.Errorf("plugin %s execution status: %v", command, err)
 
 		if len(stderr) == 0 {
 			// if stderr is empty, it is possible that the plugin is not
 			// running properly.
-			logger.Errorf("failed to execute the %s command for plugin %s: %s", req.Command(), pluginName, err)
+			logger.Errorf("failed to execute the %s command for plugin %s: %s", command, pluginName, err)
 			return &PluginExecutableFileError{
 				InnerError: err,
 			}
@@ -189,22 +425,22 @@ func run(ctx context.Context, pluginName string, pluginPath string, req plugin.R
 			var re proto.RequestError
 			jsonErr := json.Unmarshal(stderr, &re)
 			if jsonErr != nil {
-				logger.Errorf("failed to execute the %s command for plugin %s: %s", req.Command(), pluginName, strings.TrimSuffix(string(stderr), "\n"))
+				logger.Errorf("failed to execute the %s command for plugin %s: %s", command, pluginName, strings.TrimSuffix(string(stderr), "\n"))
 				return &PluginMalformedError{
 					InnerError: jsonErr,
 				}
 			}
-			logger.Errorf("failed to execute the %s command for plugin %s: %s: %w", req.Command(), pluginName, re.Code, re)
+			logger.Errorf("failed to execute the %s command for plugin %s: %s: %w", command, pluginName, re.Code, re)
 			return re
 		}
 	}
 
-	logger.Debugf("Plugin %s response: %s", req.Command(), string(stdout))
+	logger.Debugf("Plugin %s request %s response: %s", command, requestID, string(log.RedactJSON(ctx, stdout)))
 	// deserialize response
 	if err = json.Unmarshal(stdout, resp); err != nil {
-		logger.Errorf("failed to unmarshal plugin %s response: %w", req.Command(), err)
+		logger.Errorf("failed to unmarshal plugin %s response: %w", command, err)
 		return &PluginMalformedError{
-			Msg:        fmt.Sprintf("failed to unmarshal the response of %s command for plugin %s", req.Command(), pluginName),
+			Msg:        fmt.Sprintf("failed to unmarshal the response of %s command for plugin %s", command, pluginName),
 			InnerError: err,
 		}
 	}
@@ -215,29 +451,58 @@ func run(ctx context.Context, pluginName string, pluginPath string, req plugin.R
 type commander interface {
 	// Output runs the command, passing req to the stdin.
 	// It only returns an error if the binary can't be executed.
-	// Returns stdout if err is nil, stderr if err is not nil.
+	// Returns stdout if err is nil. stderr is always returned for
+	// diagnostics.
 	Output(ctx context.Context, path string, command plugin.Command, req []byte) (stdout []byte, stderr []byte, err error)
 }
 
 // execCommander implements the commander interface using exec.Command().
-type execCommander struct{}
+type execCommander struct {
+	limits ExecutionLimits
+
+	// env is the environment of the plugin process. If nil, the plugin
+	// process inherits the environment of the current process.
+	env []string
+}
 
 func (c execCommander) Output(ctx context.Context, name string, command plugin.Command, req []byte) ([]byte, []byte, error) {
+	return c.OutputStream(ctx, name, command, bytes.NewReader(req))
+}
+
+// OutputStream runs the command, streaming stdin to the command stdin.
+func (c execCommander) OutputStream(ctx context.Context, name string, command plugin.Command, stdin io.Reader) ([]byte, []byte, error) {
 	var stdout, stderr bytes.Buffer
 	cmd := exec.CommandContext(ctx, name, string(command))
-	cmd.Stdin = bytes.NewReader(req)
-	// The limit writer will be handled by the caller in run() by comparing the
-	// bytes written with the expected length of the bytes.
-	cmd.Stderr = io.LimitWriter(&stderr, maxPluginOutputSize)
-	cmd.Stdout = io.LimitWriter(&stdout, maxPluginOutputSize)
-	err := cmd.Run()
+	cmd.Stdin = stdin
+	cmd.WaitDelay = pluginWaitDelay
+	cmd.Env = c.env
+	maxOutputSize := c.limits.MaxResponseSize
+	if maxOutputSize <= 0 {
+		maxOutputSize = maxPluginOutputSize
+	}
+	// one extra byte is allowed so that output exceeding the limit can be told
+	// apart from output of exactly the maximum size.
+	cmd.Stderr = notationio.LimitWriter(&stderr, maxOutputSize+1)
+	cmd.Stdout = notationio.LimitWriter(&stdout, maxOutputSize+1)
+	if c.limits.hasResourceLimits() {
+		// the limits are applied before the plugin executes any instruction
+		if err := startWithResourceLimits(cmd, c.limits); err != nil {
+			return nil, nil, fmt.Errorf("failed to start '%s %s' with resource limits: %w", name, string(command), err)
+		}
+	} else if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	err := cmd.Wait()
+	if int64(stdout.Len()) > maxOutputSize || int64(stderr.Len()) > maxOutputSize {
+		return nil, nil, fmt.Errorf("'%s %s' command output exceeds the maximum size of %d bytes: %w", name, string(command), maxOutputSize, ErrResponseTooLarge)
+	}
 	if err != nil {
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			return nil, stderr.Bytes(), fmt.Errorf("'%s %s' command execution timeout: %w", name, string(command), err)
 		}
 		return nil, stderr.Bytes(), err
 	}
-	return stdout.Bytes(), nil, nil
+	return stdout.Bytes(), stderr.Bytes(), nil
 }
 
 // validate checks if the metadata is correctly populated.
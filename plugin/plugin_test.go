@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/notaryproject/notation-go/plugin/proto"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 func TestGetMetadata(t *testing.T) {
@@ -287,3 +288,51 @@ func TestNewCLIPlugin_ValidError(t *testing.T) {
 		}
 	})
 }
+
+// testRequestCommander records the request passed to the plugin.
+type testRequestCommander struct {
+	stdout []byte
+	req    []byte
+}
+
+func (t *testRequestCommander) Output(ctx context.Context, path string, command proto.Command, req []byte) ([]byte, []byte, error) {
+	t.req = req
+	return t.stdout, nil, nil
+}
+
+func TestVerifyArtifactSignature(t *testing.T) {
+	defer func(old commander) { executor = old }(executor)
+	cmd := &testRequestCommander{stdout: []byte(`{"verificationResults":{},"processedAttributes":[]}`)}
+	executor = cmd
+
+	subject := &ocispec.Descriptor{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/vnd.example.sbom",
+		Annotations:  map[string]string{"org.example.pipeline": "x"},
+	}
+	p := CLIPlugin{}
+	_, err := p.VerifyArtifactSignature(context.Background(), &proto.VerifyArtifactSignatureRequest{
+		Subject:                      subject,
+		SignatureManifestAnnotations: map[string]string{"io.cncf.notary.x509chain.thumbprint#S256": "[]"},
+	})
+	if err != nil {
+		t.Fatalf("VerifyArtifactSignature() error = %v", err)
+	}
+
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(cmd.req, &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"contractVersion", "signature", "trustPolicy", "subject", "signatureManifestAnnotations"} {
+		if _, ok := got[field]; !ok {
+			t.Errorf("VerifyArtifactSignature() request is missing field %q", field)
+		}
+	}
+	var gotSubject ocispec.Descriptor
+	if err := json.Unmarshal(got["subject"], &gotSubject); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&gotSubject, subject) {
+		t.Fatalf("VerifyArtifactSignature() subject = %+v, want %+v", gotSubject, subject)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// VerifyArtifactSignatureRequest is a verify-signature request carrying the
+// context of the verified artifact, so that verification plugins can enforce
+// policies based on the artifact, e.g. "only images built by pipeline X".
+//
+// The additional fields are optional and are ignored by plugins that do not
+// support them.
+type VerifyArtifactSignatureRequest struct {
+	plugin.VerifySignatureRequest
+
+	// Subject is the descriptor of the artifact the signature is associated
+	// with, including its artifact type and annotations.
+	Subject *ocispec.Descriptor `json:"subject,omitempty"`
+
+	// SignatureManifestAnnotations are the annotations of the signature
+	// manifest.
+	SignatureManifestAnnotations map[string]string `json:"signatureManifestAnnotations,omitempty"`
+}
@@ -0,0 +1,76 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"errors"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// CommandCheckHealth is the name of the plugin command which must be
+// supported by every plugin that has the HEALTH_CHECK capability. It
+// validates that the plugin is correctly configured and can reach its
+// backend, e.g. a KMS or an HSM.
+const CommandCheckHealth plugin.Command = "check-health"
+
+// CapabilityHealthCheck is the name of the capability for a plugin to
+// support the check-health command.
+const CapabilityHealthCheck plugin.Capability = "HEALTH_CHECK"
+
+// CheckHealthRequest contains the parameters passed in a check-health request.
+type CheckHealthRequest struct {
+	ContractVersion string `json:"contractVersion"`
+
+	// KeyID is the optional key to check the access to.
+	KeyID string `json:"keyId,omitempty"`
+
+	PluginConfig map[string]string `json:"pluginConfig,omitempty"`
+}
+
+// Command returns the check-health command.
+func (CheckHealthRequest) Command() plugin.Command {
+	return CommandCheckHealth
+}
+
+// Validate validates CheckHealthRequest struct.
+func (r CheckHealthRequest) Validate() error {
+	if r.ContractVersion == "" {
+		return errors.New("contractVersion cannot be empty")
+	}
+	return nil
+}
+
+// CheckHealthResponse is the response of a check-health request.
+type CheckHealthResponse struct {
+	// Healthy is true if the plugin is ready for use.
+	Healthy bool `json:"healthy"`
+
+	// Checks are the results of the individual checks performed by the
+	// plugin.
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck is the result of an individual check performed by the plugin,
+// e.g. reaching the KMS endpoint.
+type HealthCheck struct {
+	// Name of the check.
+	Name string `json:"name"`
+
+	// Healthy is true if the check succeeded.
+	Healthy bool `json:"healthy"`
+
+	// Message is the optional human-readable diagnostics of the check.
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,69 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"errors"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// CommandResolveIdentity is the name of the plugin command which must be
+// supported by every plugin that has the IDENTITY_RESOLVER capability. It
+// maps the identity of a signing certificate to organizational identities.
+const CommandResolveIdentity plugin.Command = "resolve-identity"
+
+// CapabilityIdentityResolver is the name of the capability for a plugin to
+// resolve the organizational identities of signing certificates, e.g. from
+// LDAP or an identity provider, with the resolve-identity command.
+const CapabilityIdentityResolver plugin.Capability = "IDENTITY_RESOLVER"
+
+// ResolveIdentityRequest contains the parameters passed in a resolve-identity
+// request.
+type ResolveIdentityRequest struct {
+	ContractVersion string `json:"contractVersion"`
+
+	// Subject is the subject of the signing certificate, in RFC 4514 format.
+	Subject string `json:"subject"`
+
+	// CertificateChain is the DER encoded certificate chain of the
+	// signature, starting with the signing certificate.
+	CertificateChain [][]byte `json:"certificateChain"`
+
+	PluginConfig map[string]string `json:"pluginConfig,omitempty"`
+}
+
+// Command returns the resolve-identity command.
+func (ResolveIdentityRequest) Command() plugin.Command {
+	return CommandResolveIdentity
+}
+
+// Validate validates ResolveIdentityRequest struct.
+func (r ResolveIdentityRequest) Validate() error {
+	if r.ContractVersion == "" {
+		return errors.New("contractVersion cannot be empty")
+	}
+	if len(r.CertificateChain) == 0 {
+		return errors.New("certificateChain cannot be empty")
+	}
+	return nil
+}
+
+// ResolveIdentityResponse is the response of a resolve-identity request.
+type ResolveIdentityResponse struct {
+	// Identities are the organizational identities of the signing
+	// certificate, e.g. "payments-team". It is empty if the certificate has
+	// no organizational identity.
+	Identities []string `json:"identities"`
+}
@@ -0,0 +1,34 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import "testing"
+
+func TestResolveIdentityRequest(t *testing.T) {
+	req := ResolveIdentityRequest{ContractVersion: "1.0"}
+	if req.Command() != CommandResolveIdentity {
+		t.Fatalf("Command() = %s, want %s", req.Command(), CommandResolveIdentity)
+	}
+	if err := req.Validate(); err == nil {
+		t.Fatal("expected error for empty certificate chain")
+	}
+	req.CertificateChain = [][]byte{[]byte("cert")}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	req.ContractVersion = ""
+	if err := req.Validate(); err == nil {
+		t.Fatal("expected error for empty contract version")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"errors"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+const (
+	// CommandListKeys is the name of the plugin command which must be
+	// supported by every plugin that has the KEY_LISTING capability. It
+	// enumerates the keys available from the plugin.
+	CommandListKeys plugin.Command = "list-keys"
+
+	// CommandDescribeKeys is the name of the plugin command which must be
+	// supported by every plugin that has the KEY_LISTING capability. It is
+	// the batched form of the describe-key command.
+	CommandDescribeKeys plugin.Command = "describe-keys"
+)
+
+// CapabilityKeyListing is the name of the capability for a plugin to support
+// the list-keys and describe-keys commands.
+const CapabilityKeyListing plugin.Capability = "KEY_LISTING"
+
+// ListKeysRequest contains the parameters passed in a list-keys request.
+type ListKeysRequest struct {
+	ContractVersion string            `json:"contractVersion"`
+	PluginConfig    map[string]string `json:"pluginConfig,omitempty"`
+}
+
+// Command returns the list-keys command.
+func (ListKeysRequest) Command() plugin.Command {
+	return CommandListKeys
+}
+
+// Validate validates ListKeysRequest struct.
+func (r ListKeysRequest) Validate() error {
+	if r.ContractVersion == "" {
+		return errors.New("contractVersion cannot be empty")
+	}
+	return nil
+}
+
+// ListKeysResponse is the response of a list-keys request.
+type ListKeysResponse struct {
+	Keys []KeyInfo `json:"keys"`
+}
+
+// KeyInfo describes a key available from the plugin.
+type KeyInfo struct {
+	KeyID string `json:"keyId"`
+
+	// KeySpec is the optional key type of the key.
+	KeySpec plugin.KeySpec `json:"keySpec,omitempty"`
+
+	// Description is the optional human-readable description of the key.
+	Description string `json:"description,omitempty"`
+}
+
+// DescribeKeysRequest contains the parameters passed in a describe-keys
+// request.
+type DescribeKeysRequest struct {
+	ContractVersion string            `json:"contractVersion"`
+	KeyIDs          []string          `json:"keyIds"`
+	PluginConfig    map[string]string `json:"pluginConfig,omitempty"`
+}
+
+// Command returns the describe-keys command.
+func (DescribeKeysRequest) Command() plugin.Command {
+	return CommandDescribeKeys
+}
+
+// Validate validates DescribeKeysRequest struct.
+func (r DescribeKeysRequest) Validate() error {
+	if r.ContractVersion == "" {
+		return errors.New("contractVersion cannot be empty")
+	}
+	if len(r.KeyIDs) == 0 {
+		return errors.New("keyIds cannot be empty")
+	}
+	return nil
+}
+
+// DescribeKeysResponse is the response of a describe-keys request.
+type DescribeKeysResponse struct {
+	Keys []plugin.DescribeKeyResponse `json:"keys"`
+}
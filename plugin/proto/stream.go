@@ -0,0 +1,176 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// CommandGenerateSignatureStream is the name of the plugin command which must
+// be supported by every plugin that has the SIGNATURE_GENERATOR.STREAM
+// capability. It is the streaming form of the generate-signature command.
+//
+// The stdin of the plugin is a sequence of frames, each prefixed by its
+// length as a 4-byte big-endian unsigned integer. The first frame is the
+// JSON-encoded [GenerateSignatureStreamRequest], followed by the payload
+// split into chunks, followed by an empty frame marking the end of the
+// payload. The response is a [plugin.GenerateSignatureResponse].
+const CommandGenerateSignatureStream plugin.Command = "generate-signature-stream"
+
+// CapabilityStreamingSignatureGenerator is the name of the capability for a
+// plugin to support the generate-signature-stream command.
+const CapabilityStreamingSignatureGenerator plugin.Capability = "SIGNATURE_GENERATOR.STREAM"
+
+// MaxFrameSize is the maximum size of a single frame of the streaming
+// protocol.
+const MaxFrameSize = 1024 * 1024 // 1 MiB
+
+// GenerateSignatureStreamRequest contains the parameters passed in a
+// generate-signature-stream request. The payload is streamed in the frames
+// following the request.
+type GenerateSignatureStreamRequest struct {
+	ContractVersion string               `json:"contractVersion"`
+	KeyID           string               `json:"keyId"`
+	KeySpec         plugin.KeySpec       `json:"keySpec"`
+	Hash            plugin.HashAlgorithm `json:"hashAlgorithm"`
+	PluginConfig    map[string]string    `json:"pluginConfig,omitempty"`
+}
+
+// Command returns the generate-signature-stream command.
+func (GenerateSignatureStreamRequest) Command() plugin.Command {
+	return CommandGenerateSignatureStream
+}
+
+// Validate validates GenerateSignatureStreamRequest struct.
+func (r GenerateSignatureStreamRequest) Validate() error {
+	if r.ContractVersion == "" {
+		return errors.New("contractVersion cannot be empty")
+	}
+	if r.KeyID == "" {
+		return errors.New("keyId cannot be empty")
+	}
+	if r.KeySpec == "" {
+		return errors.New("keySpec cannot be empty")
+	}
+	if r.Hash == "" {
+		return errors.New("hashAlgorithm cannot be empty")
+	}
+	return nil
+}
+
+// WriteStream writes the header frame, the payload read from payload in
+// frames of at most MaxFrameSize bytes, and the terminating empty frame to w.
+func WriteStream(w io.Writer, header []byte, payload io.Reader) error {
+	if len(header) == 0 {
+		return errors.New("stream header cannot be empty")
+	}
+	if err := writeFrame(w, header); err != nil {
+		return err
+	}
+	buf := make([]byte, MaxFrameSize)
+	for {
+		n, err := payload.Read(buf)
+		if n > 0 {
+			if err := writeFrame(w, buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return writeFrame(w, nil)
+}
+
+// ReadStream reads the header frame from r and returns it along with a reader
+// of the payload frames. The payload reader returns io.ErrUnexpectedEOF if
+// the stream ends before the terminating empty frame.
+func ReadStream(r io.Reader) ([]byte, io.Reader, error) {
+	header, err := readFrame(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read stream header: %w", err)
+	}
+	if len(header) == 0 {
+		return nil, nil, errors.New("stream header cannot be empty")
+	}
+	return header, &frameReader{r: r}, nil
+}
+
+func writeFrame(w io.Writer, frame []byte) error {
+	if len(frame) > MaxFrameSize {
+		return fmt.Errorf("frame size %d exceeds the maximum frame size %d", len(frame), MaxFrameSize)
+	}
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(frame)))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > MaxFrameSize {
+		return nil, fmt.Errorf("frame size %d exceeds the maximum frame size %d", size, MaxFrameSize)
+	}
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// frameReader reads the payload frames of a stream.
+type frameReader struct {
+	r    io.Reader
+	buf  []byte
+	done bool
+}
+
+// Read implements io.Reader.
+func (f *frameReader) Read(p []byte) (int, error) {
+	for len(f.buf) == 0 {
+		if f.done {
+			return 0, io.EOF
+		}
+		frame, err := readFrame(f.r)
+		if err != nil {
+			return 0, err
+		}
+		if len(frame) == 0 {
+			f.done = true
+			continue
+		}
+		f.buf = frame
+	}
+	n := copy(p, f.buf)
+	f.buf = f.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,91 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), MaxFrameSize/4)
+	var buf bytes.Buffer
+	if err := WriteStream(&buf, []byte(`{"keyId":"key"}`), bytes.NewReader(payload)); err != nil {
+		t.Fatalf("WriteStream() error = %v", err)
+	}
+
+	header, payloadReader, err := ReadStream(&buf)
+	if err != nil {
+		t.Fatalf("ReadStream() error = %v", err)
+	}
+	if string(header) != `{"keyId":"key"}` {
+		t.Fatalf("ReadStream() header = %s", header)
+	}
+	got, err := io.ReadAll(payloadReader)
+	if err != nil {
+		t.Fatalf("reading payload error = %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("ReadStream() payload of %d bytes, want %d bytes", len(got), len(payload))
+	}
+}
+
+func TestStream_Error(t *testing.T) {
+	t.Run("empty header", func(t *testing.T) {
+		if err := WriteStream(io.Discard, nil, bytes.NewReader(nil)); err == nil {
+			t.Fatal("expected error for empty header")
+		}
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteStream(&buf, []byte("{}"), bytes.NewReader([]byte("payload"))); err != nil {
+			t.Fatal(err)
+		}
+		// drop the terminating empty frame
+		truncated := bytes.NewReader(buf.Bytes()[:buf.Len()-4])
+		_, payloadReader, err := ReadStream(truncated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.ReadAll(payloadReader); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("reading truncated payload error = %v, want io.ErrUnexpectedEOF", err)
+		}
+	})
+
+	t.Run("oversized frame", func(t *testing.T) {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], MaxFrameSize+1)
+		if _, _, err := ReadStream(bytes.NewReader(length[:])); err == nil {
+			t.Fatal("expected error for oversized frame")
+		}
+	})
+}
+
+func TestGenerateSignatureStreamRequest(t *testing.T) {
+	req := GenerateSignatureStreamRequest{ContractVersion: "1.0", KeyID: "key", KeySpec: "RSA-2048"}
+	if req.Command() != CommandGenerateSignatureStream {
+		t.Fatalf("Command() = %s, want %s", req.Command(), CommandGenerateSignatureStream)
+	}
+	if err := req.Validate(); err == nil {
+		t.Fatal("expected error for empty hash algorithm")
+	}
+	req.Hash = "SHA-256"
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"errors"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// CommandGetTrustStore is the name of the plugin command which must be
+// supported by every plugin that has the TRUST_STORE capability. It returns
+// the certificates of a named trust store provided by the plugin.
+const CommandGetTrustStore plugin.Command = "get-trust-store"
+
+// CapabilityTrustStore is the name of the capability for a plugin to supply
+// the contents of named trust stores, e.g. fetched from a corporate PKI
+// service, with the get-trust-store command.
+const CapabilityTrustStore plugin.Capability = "TRUST_STORE"
+
+// GetTrustStoreRequest contains the parameters passed in a get-trust-store
+// request.
+type GetTrustStoreRequest struct {
+	ContractVersion string `json:"contractVersion"`
+
+	// StoreType is the type of the trust store, e.g. "ca".
+	StoreType string `json:"storeType"`
+
+	// StoreName is the name of the trust store in the plugin.
+	StoreName string `json:"storeName"`
+
+	PluginConfig map[string]string `json:"pluginConfig,omitempty"`
+}
+
+// Command returns the get-trust-store command.
+func (GetTrustStoreRequest) Command() plugin.Command {
+	return CommandGetTrustStore
+}
+
+// Validate validates GetTrustStoreRequest struct.
+func (r GetTrustStoreRequest) Validate() error {
+	if r.ContractVersion == "" {
+		return errors.New("contractVersion cannot be empty")
+	}
+	if r.StoreType == "" {
+		return errors.New("storeType cannot be empty")
+	}
+	if r.StoreName == "" {
+		return errors.New("storeName cannot be empty")
+	}
+	return nil
+}
+
+// GetTrustStoreResponse is the response of a get-trust-store request.
+type GetTrustStoreResponse struct {
+	// Certificates are the DER encoded certificates of the trust store.
+	Certificates [][]byte `json:"certificates"`
+}
@@ -0,0 +1,84 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+// recordingLogger records the debug messages.
+type recordingLogger struct {
+	log.Logger
+	messages []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func TestPluginRequestRedaction(t *testing.T) {
+	defer func(oldExecutor commander) {
+		executor = oldExecutor
+	}(executor)
+
+	output, err := json.Marshal(proto.DescribeKeyResponse{KeyID: "1", KeySpec: "RSA-4096"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor = testCommander{stdout: output}
+	logger := &recordingLogger{Logger: log.Discard}
+	ctx := log.WithLogger(context.Background(), logger)
+	p := CLIPlugin{name: "foo"}
+	if _, err := p.DescribeKey(ctx, &proto.DescribeKeyRequest{
+		KeyID:        "1",
+		PluginConfig: map[string]string{"accessToken": "hunter2", "region": "us"},
+	}); err != nil {
+		t.Fatalf("DescribeKey() error = %v", err)
+	}
+	logged := strings.Join(logger.messages, "\n")
+	if strings.Contains(logged, "hunter2") {
+		t.Fatalf("sensitive value logged: %s", logged)
+	}
+	if !strings.Contains(logged, `"accessToken":"[REDACTED]"`) || !strings.Contains(logged, `"region":"us"`) {
+		t.Fatalf("unexpected request log: %s", logged)
+	}
+}
+
+func TestPluginResponseRedaction(t *testing.T) {
+	defer func(oldExecutor commander) {
+		executor = oldExecutor
+	}(executor)
+
+	executor = testCommander{stdout: []byte(`{"keyId":"1","keySpec":"RSA-4096","clientSecret":"hunter2"}`)}
+	logger := &recordingLogger{Logger: log.Discard}
+	ctx := log.WithLogger(context.Background(), logger)
+	p := CLIPlugin{name: "foo"}
+	if _, err := p.DescribeKey(ctx, &proto.DescribeKeyRequest{KeyID: "1"}); err != nil {
+		t.Fatalf("DescribeKey() error = %v", err)
+	}
+	logged := strings.Join(logger.messages, "\n")
+	if strings.Contains(logged, "hunter2") {
+		t.Fatalf("sensitive value logged: %s", logged)
+	}
+	if !strings.Contains(logged, `"clientSecret":"[REDACTED]"`) || !strings.Contains(logged, `"keySpec":"RSA-4096"`) {
+		t.Fatalf("unexpected response log: %s", logged)
+	}
+}
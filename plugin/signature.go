@@ -0,0 +1,73 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// SignatureFileExtension is the extension of the detached signature file of
+// a plugin executable file. The signature of the executable file
+// notation-{plugin-name} is stored as notation-{plugin-name}.sig in the same
+// directory.
+const SignatureFileExtension = ".sig"
+
+// SignatureVerifier verifies the notation signature of a plugin executable
+// file.
+type SignatureVerifier interface {
+	// VerifyPluginSignature verifies the detached signature against the
+	// content of the plugin executable file read from executable.
+	VerifyPluginSignature(ctx context.Context, name string, executable io.Reader, signature []byte) error
+}
+
+// signatureFilePath returns the path of the detached signature file of the
+// plugin executable file at executablePath.
+func signatureFilePath(executablePath string) string {
+	return executablePath + SignatureFileExtension
+}
+
+// verifyPluginSignature verifies the detached signature of the plugin
+// executable file at path using verifier.
+func verifyPluginSignature(ctx context.Context, verifier SignatureVerifier, name, path string) error {
+	sig, err := os.ReadFile(signatureFilePath(path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &PluginIntegrityError{
+				Msg:        fmt.Sprintf("plugin %s is not signed: signature file %s is not found", name, signatureFilePath(path)),
+				InnerError: ErrPluginNotSigned,
+			}
+		}
+		return &PluginIntegrityError{
+			Msg:        fmt.Sprintf("failed to read the signature of plugin %s: %v", name, err),
+			InnerError: err,
+		}
+	}
+	executable, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer executable.Close()
+	if err := verifier.VerifyPluginSignature(ctx, name, executable, sig); err != nil {
+		return &PluginIntegrityError{
+			Msg:        fmt.Sprintf("signature verification failed for plugin %s: %v", name, err),
+			InnerError: err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/notaryproject/notation-go/log"
+)
+
+// newRequestID returns a random identifier correlating the log entries of a
+// single plugin command execution.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// logStderr routes the stderr output of the plugin process to the logger at
+// debug level, one entry per line, tagged with the plugin name and the
+// request ID.
+func logStderr(logger log.Logger, pluginName, requestID string, stderr []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(stderr))
+	scanner.Buffer(nil, len(stderr)+1)
+	for scanner.Scan() {
+		if line := bytes.TrimRight(scanner.Bytes(), "\r"); len(line) > 0 {
+			logger.Debugf("[plugin %s] [request %s] stderr: %s", pluginName, requestID, line)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+// recordLogger records the debug log entries.
+type recordLogger struct {
+	log.Logger
+	debug []string
+}
+
+func (l *recordLogger) Debugf(format string, args ...interface{}) {
+	l.debug = append(l.debug, fmt.Sprintf(format, args...))
+}
+
+func TestRun_LogStderr(t *testing.T) {
+	logger := &recordLogger{Logger: log.Discard}
+	ctx := log.WithLogger(context.Background(), logger)
+	executor = testCommander{
+		stdout: metadataJSON(validMetadata),
+		stderr: []byte("connecting to kms\r\n\nkey loaded\n"),
+	}
+	p := CLIPlugin{name: "foo"}
+	if _, err := p.GetMetadata(ctx, &proto.GetMetadataRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stderrEntries []string
+	for _, entry := range logger.debug {
+		if strings.Contains(entry, "stderr:") {
+			stderrEntries = append(stderrEntries, entry)
+		}
+	}
+	if len(stderrEntries) != 2 {
+		t.Fatalf("got %d stderr log entries, want 2: %v", len(stderrEntries), stderrEntries)
+	}
+	for i, want := range []string{"connecting to kms", "key loaded"} {
+		if !strings.HasPrefix(stderrEntries[i], "[plugin foo] [request ") || !strings.HasSuffix(stderrEntries[i], "stderr: "+want) {
+			t.Errorf("stderr log entry = %q, want tagged entry for %q", stderrEntries[i], want)
+		}
+	}
+	if stderrEntries[0][:40] != stderrEntries[1][:40] {
+		t.Errorf("stderr log entries of a single execution have different request IDs: %v", stderrEntries)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/notaryproject/notation-go/dump"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// streamCommander is implemented by commanders supporting streaming the
+// stdin of the command.
+type streamCommander interface {
+	// OutputStream runs the command, streaming stdin to the command stdin.
+	OutputStream(ctx context.Context, path string, command plugin.Command, stdin io.Reader) (stdout []byte, stderr []byte, err error)
+}
+
+// GenerateSignatureStream generates the raw signature of the payload read
+// from payload, streaming the payload to the plugin instead of buffering it
+// in memory. The plugin must have the SIGNATURE_GENERATOR.STREAM capability.
+//
+// if ContractVersion is not set, it will be set by the function.
+func (p *CLIPlugin) GenerateSignatureStream(ctx context.Context, req *proto.GenerateSignatureStreamRequest, payload io.Reader) (_ *plugin.GenerateSignatureResponse, err error) {
+	if req.ContractVersion == "" {
+		req.ContractVersion = plugin.ContractVersion
+	}
+	if payload == nil {
+		return nil, errors.New("payload cannot be nil")
+	}
+	defer func(ctx context.Context) {
+		auditExecution(ctx, p.name, req.Command(), err)
+	}(ctx)
+	ctx, cancel, err := p.prepare(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var resp plugin.GenerateSignatureResponse
+	if err := runStream(ctx, p.commander(ctx), p.name, p.path, req, payload, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// runStream runs the plugin command with req as the header frame followed by
+// the payload frames.
+func runStream(ctx context.Context, executor commander, pluginName string, pluginPath string, req plugin.Request, payload io.Reader, resp interface{}) error {
+	logger := log.GetLogger(ctx)
+
+	// serialize request header
+	header, err := json.Marshal(req)
+	if err != nil {
+		logger.Errorf("Failed to marshal request object: %+v", req)
+		return fmt.Errorf("failed to marshal request object: %w", err)
+	}
+
+	requestID := newRequestID()
+	logger.Debugf("Plugin %s request %s: %s", req.Command(), requestID, string(log.RedactJSON(ctx, header)))
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(proto.WriteStream(pw, header, payload))
+	}()
+	// unblock the writer if the plugin exits before reading the whole stream
+	defer pr.CloseWithError(io.ErrClosedPipe)
+
+	start := time.Now()
+	var stdout, stderr []byte
+	if sc, ok := executor.(streamCommander); ok {
+		stdout, stderr, err = sc.OutputStream(ctx, pluginPath, req.Command(), pr)
+	} else {
+		// the commander does not support streaming, e.g. in unit tests
+		var data []byte
+		if data, err = io.ReadAll(pr); err != nil {
+			return fmt.Errorf("failed to read payload: %w", err)
+		}
+		stdout, stderr, err = executor.Output(ctx, pluginPath, req.Command(), data)
+	}
+	dump.WritePlugin(ctx, pluginName, string(req.Command()), start, header, stdout, stderr, err)
+	return parseOutput(ctx, pluginName, req.Command(), requestID, stdout, stderr, err, resp)
+}
@@ -0,0 +1,94 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+// testStreamCommander decodes the stream and signs the payload by hashing it.
+type testStreamCommander struct {
+	header *proto.GenerateSignatureStreamRequest
+}
+
+func (t *testStreamCommander) Output(ctx context.Context, path string, command proto.Command, req []byte) ([]byte, []byte, error) {
+	header, payload, err := proto.ReadStream(bytes.NewReader(req))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(header, &t.header); err != nil {
+		return nil, nil, err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, payload); err != nil {
+		return nil, nil, err
+	}
+	stdout, err := json.Marshal(proto.GenerateSignatureResponse{KeyID: t.header.KeyID, Signature: h.Sum(nil)})
+	return stdout, nil, err
+}
+
+func TestGenerateSignatureStream(t *testing.T) {
+	defer func(old commander) { executor = old }(executor)
+	cmd := &testStreamCommander{}
+	executor = cmd
+	payload := bytes.Repeat([]byte("a"), 3*proto.MaxFrameSize+1)
+	p := CLIPlugin{name: "foo"}
+	resp, err := p.GenerateSignatureStream(context.Background(), &proto.GenerateSignatureStreamRequest{
+		KeyID:   "key",
+		KeySpec: "RSA-2048",
+		Hash:    "SHA-256",
+	}, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("GenerateSignatureStream() error = %v", err)
+	}
+	want := sha256.Sum256(payload)
+	if !bytes.Equal(resp.Signature, want[:]) {
+		t.Fatal("GenerateSignatureStream() the plugin did not receive the full payload")
+	}
+	if cmd.header.ContractVersion != proto.ContractVersion || cmd.header.KeyID != "key" {
+		t.Fatalf("GenerateSignatureStream() header = %+v", cmd.header)
+	}
+}
+
+func TestGenerateSignatureStream_PluginExitsEarly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+	defer func(old commander) { executor = old }(executor)
+	executor = &execCommander{}
+	path := writeScript(t, "notation-early", `echo '{"keyId":"key"}'`)
+	p, err := NewCLIPlugin(context.Background(), "early", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.GenerateSignatureStream(context.Background(), &proto.GenerateSignatureStreamRequest{}, bytes.NewReader(make([]byte, 8*proto.MaxFrameSize)))
+		done <- err
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("GenerateSignatureStream() did not return after the plugin exited")
+	}
+}
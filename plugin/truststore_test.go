@@ -0,0 +1,53 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+func TestGetTrustStore(t *testing.T) {
+	defer func(old commander) { executor = old }(executor)
+	want := proto.GetTrustStoreResponse{Certificates: [][]byte{[]byte("cert1"), []byte("cert2")}}
+	output, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor = testCommander{stdout: output}
+	p := CLIPlugin{name: "foo"}
+	req := &proto.GetTrustStoreRequest{StoreType: "ca", StoreName: "corp-roots"}
+	resp, err := p.GetTrustStore(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetTrustStore() error = %v", err)
+	}
+	if !reflect.DeepEqual(*resp, want) {
+		t.Fatalf("GetTrustStore() = %+v, want %+v", *resp, want)
+	}
+	if req.ContractVersion != proto.ContractVersion {
+		t.Fatalf("GetTrustStore() contract version = %q, want %q", req.ContractVersion, proto.ContractVersion)
+	}
+
+	executor = testCommander{stdout: []byte(`{"certificates":[]}`)}
+	_, err = p.GetTrustStore(context.Background(), &proto.GetTrustStoreRequest{StoreType: "ca", StoreName: "corp-roots"})
+	var malformedErr *PluginMalformedError
+	if !errors.As(err, &malformedErr) {
+		t.Fatalf("GetTrustStore() error = %v, want PluginMalformedError", err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/notaryproject/notation-go/internal/semver"
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// versionsDir is the sub-directory of a plugin directory holding the
+// side-by-side installed versions of the plugin, i.e.
+// {plugin-name}/versions/{version}/notation-{plugin-name}.
+const versionsDir = "versions"
+
+// ListVersions returns the side-by-side installed versions of the named
+// plugin in ascending order. The default version of the plugin is not
+// included.
+func (m *CLIManager) ListVersions(ctx context.Context, name string) ([]string, error) {
+	entries, err := fs.ReadDir(m.pluginFS, path.Join(name, versionsDir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, PluginDirectoryWalkError(fmt.Errorf("failed to list versions of plugin %s: %w", name, err))
+	}
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() && semver.IsValid(entry.Name()) {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		comp, _ := semver.ComparePluginVersion(versions[i], versions[j])
+		return comp < 0
+	})
+	return versions, nil
+}
+
+// GetVersion returns the side-by-side installed version of the named plugin.
+//
+// If the version is not installed, the error is of type os.ErrNotExist.
+func (m *CLIManager) GetVersion(ctx context.Context, name, version string) (plugin.Plugin, error) {
+	if !semver.IsValid(version) {
+		return nil, fmt.Errorf("%s is not a valid semantic version", version)
+	}
+	executablePath, err := m.versionExecutablePath(name, version)
+	if err != nil {
+		return nil, err
+	}
+	p, err := m.load(ctx, name, versionLockKey(name, version), executablePath)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetWithConstraint returns the highest installed version of the named plugin
+// satisfying the semantic version constraint, e.g. "^1.2" or
+// ">=1.0.0, <2.0.0". Side-by-side installed versions are preferred over the
+// default version. If constraint is empty, the default version is returned.
+//
+// If no installed version satisfies the constraint, the error is of type
+// os.ErrNotExist.
+func (m *CLIManager) GetWithConstraint(ctx context.Context, name, constraint string) (plugin.Plugin, error) {
+	if constraint == "" {
+		return m.Get(ctx, name)
+	}
+	if err := semver.ValidateConstraint(constraint); err != nil {
+		return nil, err
+	}
+	versions, err := m.ListVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if ok, _ := semver.MatchConstraint(versions[i], constraint); ok {
+			return m.GetVersion(ctx, name, versions[i])
+		}
+	}
+
+	// fall back to the default version
+	p, err := m.get(ctx, name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no installed version of plugin %s satisfies %q: %w", name, constraint, os.ErrNotExist)
+		}
+		return nil, err
+	}
+	metadata, err := p.GetMetadata(ctx, &plugin.GetMetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata of plugin %s: %w", name, err)
+	}
+	ok, err := semver.MatchConstraint(metadata.Version, constraint)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no installed version of plugin %s satisfies %q: %w", name, constraint, os.ErrNotExist)
+	}
+	return p, nil
+}
+
+// UninstallVersion uninstalls a side-by-side installed version of the named
+// plugin. If the version is not installed, os.ErrNotExist is returned.
+func (m *CLIManager) UninstallVersion(ctx context.Context, name, version string) error {
+	if !semver.IsValid(version) {
+		return fmt.Errorf("%s is not a valid semantic version", version)
+	}
+	versionDirPath, err := m.pluginFS.SysPath(path.Join(name, versionsDir, version))
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(versionDirPath); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(versionDirPath); err != nil {
+		return err
+	}
+	lockKey := versionLockKey(name, version)
+	return m.removeLockEntries(func(key string) bool {
+		return key == lockKey
+	})
+}
+
+// installVersion installs the plugin as a side-by-side version.
+func (m *CLIManager) installVersion(name, version, pluginExecutableFile string, installOpts CLIInstallOptions, installFromNonDir bool) error {
+	if !semver.IsValid(version) {
+		return fmt.Errorf("failed to install plugin %s side-by-side: %s is not a valid semantic version", name, version)
+	}
+	versionDirPath, err := m.pluginFS.SysPath(path.Join(name, versionsDir, version))
+	if err != nil {
+		return fmt.Errorf("failed to get the system path of plugin %s version %s: %w", name, version, err)
+	}
+	if _, err := os.Stat(versionDirPath); err == nil {
+		if !installOpts.Overwrite {
+			return InstallEqualVersionError{Msg: fmt.Sprintf("plugin %s with version %s already exists", name, version)}
+		}
+		if err := os.RemoveAll(versionDirPath); err != nil {
+			return fmt.Errorf("failed to clean up plugin %s version %s before installation: %w", name, version, err)
+		}
+	}
+	if err := m.copyPluginFiles(pluginExecutableFile, installOpts.PluginPath, installFromNonDir, versionDirPath); err != nil {
+		return err
+	}
+	if m.opts.LockFilePath != "" {
+		executablePath, err := m.versionExecutablePath(name, version)
+		if err != nil {
+			return err
+		}
+		if err := m.recordDigest(versionLockKey(name, version), executablePath, version); err != nil {
+			return fmt.Errorf("failed to record digest of plugin %s version %s: %w", name, version, err)
+		}
+	}
+	return nil
+}
+
+// versionExecutablePath returns the system path of the executable file of
+// the side-by-side installed version of the named plugin.
+func (m *CLIManager) versionExecutablePath(name, version string) (string, error) {
+	return m.pluginFS.SysPath(path.Join(name, versionsDir, version, binName(name)))
+}
+
+// versionLockKey returns the plugin lock file key of a side-by-side installed
+// plugin version.
+func versionLockKey(name, version string) string {
+	return name + "@" + version
+}
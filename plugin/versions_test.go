@@ -0,0 +1,132 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock/mockfs"
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+// testFileCommander returns the content of the plugin executable file as the
+// plugin response, so that installed copies respond like their source.
+type testFileCommander struct{}
+
+func (testFileCommander) Output(ctx context.Context, path string, command proto.Command, req []byte) ([]byte, []byte, error) {
+	stdout, err := os.ReadFile(path)
+	return stdout, nil, err
+}
+
+// writeTestPlugin writes an executable file responding with metadata of the
+// given version and returns its path.
+func writeTestPlugin(t *testing.T, version string) string {
+	t.Helper()
+	metadata := validMetadata
+	metadata.Version = version
+	path := filepath.Join(t.TempDir(), "notation-foo")
+	if err := os.WriteFile(path, metadataJSON(metadata), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCLIManager_SideBySide(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+	defer func(old commander) { executor = old }(executor)
+	executor = testFileCommander{}
+	ctx := context.Background()
+	pluginDir := t.TempDir()
+	mgr := NewCLIManagerWithOptions(mockfs.NewSysFSWithRootMock(os.DirFS(pluginDir), pluginDir), CLIManagerOptions{
+		LockFilePath: filepath.Join(t.TempDir(), "plugins.lock.json"),
+	})
+
+	install := func(version string, sideBySide bool) {
+		t.Helper()
+		if _, _, err := mgr.Install(ctx, CLIInstallOptions{PluginPath: writeTestPlugin(t, version), SideBySide: sideBySide}); err != nil {
+			t.Fatalf("Install(%s) error = %v", version, err)
+		}
+	}
+	version := func(p Plugin) string {
+		t.Helper()
+		metadata, err := p.GetMetadata(ctx, &proto.GetMetadataRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return metadata.Version
+	}
+
+	install("1.0.0", false)
+	install("1.2.0", true)
+	install("2.1.0", true)
+	install("1.10.0", true)
+	if _, _, err := mgr.Install(ctx, CLIInstallOptions{PluginPath: writeTestPlugin(t, "1.2.0"), SideBySide: true}); !errors.As(err, &InstallEqualVersionError{}) {
+		t.Fatalf("Install() of existing side-by-side version error = %v, want InstallEqualVersionError", err)
+	}
+	// upgrading the default version keeps the side-by-side versions
+	install("3.0.0", false)
+
+	versions, err := mgr.ListVersions(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1.2.0", "1.10.0", "2.1.0"}; !reflect.DeepEqual(versions, want) {
+		t.Fatalf("ListVersions() = %v, want %v", versions, want)
+	}
+
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"", "3.0.0"},
+		{"^1.2", "1.10.0"},
+		{"~1.2.0", "1.2.0"},
+		{">=2", "2.1.0"},
+		{"^3", "3.0.0"},
+	}
+	for _, tt := range tests {
+		p, err := mgr.GetWithConstraint(ctx, "foo", tt.constraint)
+		if err != nil {
+			t.Fatalf("GetWithConstraint(%q) error = %v", tt.constraint, err)
+		}
+		if got := version(p); got != tt.want {
+			t.Errorf("GetWithConstraint(%q) got version %s, want %s", tt.constraint, got, tt.want)
+		}
+	}
+	if _, err := mgr.GetWithConstraint(ctx, "foo", "^4"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("GetWithConstraint(^4) error = %v, want os.ErrNotExist", err)
+	}
+
+	if err := mgr.UninstallVersion(ctx, "foo", "1.10.0"); err != nil {
+		t.Fatalf("UninstallVersion() error = %v", err)
+	}
+	p, err := mgr.GetWithConstraint(ctx, "foo", "^1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := version(p); got != "1.2.0" {
+		t.Errorf("GetWithConstraint(^1.2) after uninstall got version %s, want 1.2.0", got)
+	}
+	if _, err := mgr.GetVersion(ctx, "foo", "1.10.0"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("GetVersion() of uninstalled version error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	orasRegistry "oras.land/oras-go/v2/registry"
+
+	"github.com/notaryproject/notation-go/log"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// errDonePrefetch stops the listing of the signatures once enough signatures
+// are prefetched.
+var errDonePrefetch = errors.New("done prefetch")
+
+// RevocationPrefetcher is a [Verifier] fetching the revocation data of the
+// certificate chain of a signature in advance, so that its revocation
+// validators cache them, e.g. the CRLs and the OCSP responses.
+type RevocationPrefetcher interface {
+	// PrefetchRevocation fetches the revocation data of the certificate
+	// chain of the signature envelope of media type signatureMediaType. The
+	// signature is not verified against a trust policy, and the revocation
+	// status is not reported.
+	PrefetchRevocation(ctx context.Context, signature []byte, signatureMediaType string) error
+}
+
+// PrefetchOptions contains parameters for [notation.Prefetch].
+type PrefetchOptions struct {
+	// MaxSignatureAttempts is the maximum number of signature envelopes
+	// prefetched for each artifact, as set for the verifications. If set to
+	// less than or equals to zero, an error will be returned.
+	MaxSignatureAttempts int
+
+	// Concurrency is the maximum number of artifact references prefetched
+	// concurrently. If set to less than or equals to zero, 4 is used.
+	Concurrency int
+}
+
+// PrefetchResult is the prefetch result of an artifact reference prefetched
+// by [notation.Prefetch].
+type PrefetchResult struct {
+	// ArtifactReference is the prefetched artifact reference.
+	ArtifactReference string
+
+	// Descriptor is the descriptor of the prefetched artifact.
+	Descriptor ocispec.Descriptor
+
+	// Signatures is the number of signature envelopes prefetched.
+	Signatures int
+
+	// Error is the error that caused the prefetch to fail (if it fails). The
+	// failures to prefetch the revocation data of the signatures are joined.
+	Error error
+}
+
+// Prefetch warms the caches used by the verification of the artifacts
+// referenced by artifactRefs before they are verified, e.g. ahead of a
+// deployment window, so that the verifications do not depend on slow
+// registries and revocation services. For each reference, it resolves the
+// artifact, lists its signature manifests, fetches up to
+// opts.MaxSignatureAttempts signature envelopes, and, if verifier is a
+// [RevocationPrefetcher], fetches the revocation data of their certificate
+// chains.
+//
+// The registry calls are cached only if the repository clients returned by
+// repoFunc cache them, e.g. with registry.CachingMiddleware, and are
+// reused for the verifications. The revocation data are cached only if the
+// verifier caches them and is reused for the verifications, e.g. a verifier
+// created by verifier.NewServerVerifier. If verifier is nil, the revocation
+// data are not prefetched.
+//
+// The results are returned in the order of artifactRefs. A failed prefetch is
+// reported in its result and does not stop the prefetch of the other
+// references. An error is returned only if the arguments are invalid.
+func Prefetch(ctx context.Context, verifier Verifier, repoFunc RepositoryFunc, artifactRefs []string, opts PrefetchOptions) ([]*PrefetchResult, error) {
+	// sanity check
+	if repoFunc == nil {
+		return nil, errors.New("repoFunc cannot be nil")
+	}
+	if opts.MaxSignatureAttempts <= 0 {
+		return nil, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("prefetchOptions.MaxSignatureAttempts expects a positive number, got %d", opts.MaxSignatureAttempts)}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultVerifyAllConcurrency
+	}
+	prefetcher, _ := verifier.(RevocationPrefetcher)
+
+	repos := &repositoryCache{
+		repoFunc: repoFunc,
+		entries:  make(map[string]*repositoryCacheEntry),
+	}
+	results := make([]*PrefetchResult, len(artifactRefs))
+	for i, artifactRef := range artifactRefs {
+		results[i] = &PrefetchResult{ArtifactReference: artifactRef}
+	}
+	errs, _ := runBatch(ctx, len(artifactRefs), concurrency, nil, func(ctx context.Context, i int) error {
+		result := results[i]
+		result.Error = prefetchReference(ctx, prefetcher, repos, result, opts)
+		return result.Error
+	})
+	for i, err := range errs {
+		results[i].Error = err
+	}
+	return results, nil
+}
+
+// prefetchReference prefetches the artifact referenced by
+// result.ArtifactReference, and records the prefetched artifact in result.
+func prefetchReference(ctx context.Context, prefetcher RevocationPrefetcher, repos *repositoryCache, result *PrefetchResult, opts PrefetchOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	artifactRef := result.ArtifactReference
+	ref, err := orasRegistry.ParseReference(artifactRef)
+	if err != nil {
+		return ErrorSignatureRetrievalFailed{Msg: err.Error(), InnerError: err}
+	}
+	if ref.Reference == "" {
+		return ErrorSignatureRetrievalFailed{Msg: "reference is missing digest or tag"}
+	}
+	repo, err := repos.get(ctx, ref.Registry+"/"+ref.Repository)
+	if err != nil {
+		return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("failed to create the repository client of %q: %v", artifactRef, err), InnerError: err}
+	}
+	logger := log.GetLogger(ctx)
+
+	artifactDescriptor, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return ErrorSignatureRetrievalFailed{Msg: err.Error(), InnerError: err}
+	}
+	result.Descriptor = artifactDescriptor
+	if describer, ok := repo.(artifactDescriber); ok {
+		if _, err := describer.DescribeArtifact(ctx, artifactDescriptor); err != nil && !errors.Is(err, errdef.ErrUnsupported) {
+			return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve the manifest of %q from the Repository, error : %v", artifactRef, err.Error()), InnerError: err}
+		}
+	}
+
+	var revocationErrs []error
+	err = repo.ListSignatures(ctx, artifactDescriptor, func(signatureManifests []ocispec.Descriptor) error {
+		for _, sigManifestDesc := range signatureManifests {
+			if result.Signatures >= opts.MaxSignatureAttempts {
+				return errDonePrefetch
+			}
+			sigBlob, sigDesc, err := fetchSignatureBlob(ctx, repo, sigManifestDesc, 0)
+			if err != nil {
+				return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve digital signature with digest %q associated with %q from the Repository, error : %v", sigManifestDesc.Digest, artifactRef, err.Error()), InnerError: err}
+			}
+			result.Signatures++
+			if prefetcher == nil {
+				continue
+			}
+			if skipped, ok := unsupportedEnvelope(sigManifestDesc.Digest, sigDesc.MediaType); ok {
+				logSkippedSignature(ctx, skipped, false)
+				continue
+			}
+			if err := prefetcher.PrefetchRevocation(ctx, sigBlob, sigDesc.MediaType); err != nil {
+				logger.Warnf("Failed to prefetch the revocation data of signature %v: %v", sigManifestDesc.Digest, err)
+				revocationErrs = append(revocationErrs, fmt.Errorf("failed to prefetch the revocation data of signature with digest %v: %w", sigManifestDesc.Digest, err))
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDonePrefetch) {
+		return err
+	}
+	logger.Debugf("Prefetched %d signature(s) of %s", result.Signatures, artifactRef)
+	return errors.Join(revocationErrs...)
+}
@@ -0,0 +1,101 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+)
+
+// prefetchingVerifier records the signatures prefetched.
+type prefetchingVerifier struct {
+	dummyVerifier
+	err error
+
+	mu         sync.Mutex
+	prefetched int
+}
+
+func (v *prefetchingVerifier) PrefetchRevocation(ctx context.Context, signature []byte, signatureMediaType string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.prefetched++
+	return v.err
+}
+
+func TestPrefetch(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	repo := &slowRepository{Repository: mock.NewRepository()}
+	repo.ListSignaturesResponse = signatureManifests(3)
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		if repository != "registry.acme-rockets.io/software/net-monitor" {
+			return nil, errors.New("unknown repository")
+		}
+		return repo, nil
+	}
+	artifactRefs := []string{mock.SampleArtifactUri, "registry.acme-rockets.io/software/other@" + mock.SampleDigest.String(), "invalid reference"}
+
+	verifier := &prefetchingVerifier{dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}}
+	results, err := Prefetch(context.Background(), verifier, repoFunc, artifactRefs, PrefetchOptions{MaxSignatureAttempts: 2})
+	if err != nil {
+		t.Fatalf("Prefetch() error = %v", err)
+	}
+	if len(results) != len(artifactRefs) {
+		t.Fatalf("Prefetch() returned %d results, want %d", len(results), len(artifactRefs))
+	}
+	if result := results[0]; result.Error != nil || result.Descriptor.Digest != mock.ImageDescriptor.Digest || result.Signatures != 2 {
+		t.Fatalf("Prefetch() result = %+v, want 2 signatures prefetched", result)
+	}
+	if repo.fetched != 2 || verifier.prefetched != 2 {
+		t.Fatalf("Prefetch() fetched %d signatures and prefetched the revocation data of %d, want 2", repo.fetched, verifier.prefetched)
+	}
+	for _, result := range results[1:] {
+		if !errors.As(result.Error, &ErrorSignatureRetrievalFailed{}) {
+			t.Fatalf("Prefetch() result error = %v, want ErrorSignatureRetrievalFailed", result.Error)
+		}
+	}
+
+	t.Run("revocation prefetch failure", func(t *testing.T) {
+		verifier := &prefetchingVerifier{err: errors.New("ocsp responder unavailable")}
+		results, err := Prefetch(context.Background(), verifier, repoFunc, artifactRefs[:1], PrefetchOptions{MaxSignatureAttempts: 10})
+		if err != nil {
+			t.Fatalf("Prefetch() error = %v", err)
+		}
+		if result := results[0]; !errors.Is(result.Error, verifier.err) || result.Signatures != 3 || verifier.prefetched != 3 {
+			t.Fatalf("Prefetch() result = %+v, want the revocation prefetch failures of 3 signatures", result)
+		}
+	})
+
+	t.Run("without verifier", func(t *testing.T) {
+		results, err := Prefetch(context.Background(), nil, repoFunc, artifactRefs[:1], PrefetchOptions{MaxSignatureAttempts: 10})
+		if err != nil || results[0].Error != nil || results[0].Signatures != 3 {
+			t.Fatalf("Prefetch() = %+v, %v, want 3 signatures prefetched", results[0], err)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		if _, err := Prefetch(context.Background(), verifier, nil, artifactRefs, PrefetchOptions{MaxSignatureAttempts: 1}); err == nil {
+			t.Fatal("Prefetch() expects error for nil repoFunc")
+		}
+		if _, err := Prefetch(context.Background(), verifier, repoFunc, artifactRefs, PrefetchOptions{}); err == nil {
+			t.Fatal("Prefetch() expects error for non-positive MaxSignatureAttempts")
+		}
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/internal/slices"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// thumbprintReporter is implemented by verifiers able to report the
+// certificates trusted for an artifact.
+type thumbprintReporter interface {
+	// TrustedThumbprints returns the hex-encoded SHA-256 thumbprints of the
+	// certificates trusted for the artifact.
+	TrustedThumbprints(ctx context.Context, opts VerifierVerifyOptions) ([]string, error)
+}
+
+// signature priorities, lower is verified first
+const (
+	// priorityTrusted is the priority of a signature whose certificate chain
+	// includes a trusted certificate according to its manifest annotations.
+	priorityTrusted = iota
+
+	// priorityUnknown is the priority of a signature without certificate
+	// thumbprints in its manifest annotations.
+	priorityUnknown
+
+	// priorityUntrusted is the priority of a signature whose certificate
+	// chain includes no trusted certificate according to its manifest
+	// annotations.
+	priorityUntrusted
+)
+
+// signatureBlobDescriber is implemented by repositories able to describe the
+// signature envelope blob of a signature manifest without fetching the blob.
+type signatureBlobDescriber interface {
+	// FetchSignatureBlobDescriptor returns the descriptor of the signature
+	// envelope blob of the signature manifest.
+	FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error)
+}
+
+// defaultSignatureCandidatesFactor is the factor of MaxSignatureAttempts
+// giving the default maximum number of signature manifests listed for
+// prioritization.
+const defaultSignatureCandidatesFactor = 10
+
+// errDoneListing stops listing the signature manifests.
+var errDoneListing = errors.New("done listing")
+
+// verifyPrioritizedSignatures lists at most verifyOpts.MaxSignatureCandidates
+// signature manifests of the artifact, pre-filters them by envelope type and
+// processes them in the order of priority. The signatures filtered out are
+// not passed to processSignatures, so they do not count against
+// MaxSignatureAttempts.
+func verifyPrioritizedSignatures(ctx context.Context, verifier Verifier, repo registry.Repository, artifactDescriptor ocispec.Descriptor, verifyOpts VerifyOptions, opts VerifierVerifyOptions, processSignatures func([]ocispec.Descriptor) error) error {
+	logger := log.GetLogger(ctx)
+
+	maxCandidates := verifyOpts.MaxSignatureCandidates
+	if maxCandidates <= 0 {
+		maxCandidates = defaultSignatureCandidatesFactor * verifyOpts.MaxSignatureAttempts
+	}
+	var signatureManifests []ocispec.Descriptor
+	err := repo.ListSignatures(ctx, artifactDescriptor, func(manifests []ocispec.Descriptor) error {
+		for _, manifest := range manifests {
+			if len(signatureManifests) >= maxCandidates {
+				return errDoneListing
+			}
+			signatureManifests = append(signatureManifests, manifest)
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, errDoneListing) {
+			return err
+		}
+		logger.Warnf("Listing of signature manifests stopped. The limit of %d signature candidates per artifact exceeded", maxCandidates)
+	}
+
+	trustedThumbprints := verifyOpts.TrustedThumbprints
+	if len(trustedThumbprints) == 0 {
+		if reporter, ok := verifier.(thumbprintReporter); ok {
+			thumbprints, err := reporter.TrustedThumbprints(ctx, opts)
+			if err != nil {
+				// the thumbprints are only used to order the signatures
+				logger.Warnf("Failed to get the trusted certificate thumbprints, signatures are ordered by creation time only: %v", err)
+			}
+			trustedThumbprints = thumbprints
+		}
+	}
+
+	var describer signatureBlobDescriber
+	if len(verifyOpts.SignatureMediaTypes) > 0 {
+		var ok bool
+		if describer, ok = repo.(signatureBlobDescriber); !ok {
+			logger.Warn("The repository cannot describe signature envelope blobs, signatures are not filtered by envelope type")
+		}
+	}
+	logger.Debugf("Prioritizing %d signatures", len(signatureManifests))
+	numOfSignatureAccepted := 0
+	for _, sigManifestDesc := range prioritizeSignatures(signatureManifests, trustedThumbprints) {
+		if describer != nil {
+			sigBlobDesc, err := describer.FetchSignatureBlobDescriptor(ctx, sigManifestDesc)
+			if errors.Is(err, errdef.ErrUnsupported) {
+				// a wrapped repository may not describe signature envelope
+				// blobs
+				logger.Warn("The repository cannot describe signature envelope blobs, signatures are not filtered by envelope type")
+				describer = nil
+				err = nil
+			}
+			if err != nil {
+				return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve the signature manifest with digest %q associated with %q from the Repository, error : %v", sigManifestDesc.Digest, verifyOpts.ArtifactReference, err.Error()), InnerError: err}
+			}
+			if describer != nil && !slices.Contains(verifyOpts.SignatureMediaTypes, sigBlobDesc.MediaType) {
+				logger.Infof("Skipping signature %v with envelope type %v", sigManifestDesc.Digest, sigBlobDesc.MediaType)
+				continue
+			}
+		}
+		numOfSignatureAccepted++
+		if err := processSignatures([]ocispec.Descriptor{sigManifestDesc}); err != nil {
+			return err
+		}
+	}
+	if numOfSignatureAccepted == 0 && len(signatureManifests) > 0 {
+		return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("no signature associated with %q has an envelope type in %q", verifyOpts.ArtifactReference, verifyOpts.SignatureMediaTypes)}
+	}
+	return nil
+}
+
+// prioritizeSignatures returns the signature manifests ordered by priority,
+// then by creation time, newest first. The signatures with the same priority
+// and creation time keep their order.
+func prioritizeSignatures(signatureManifests []ocispec.Descriptor, trustedThumbprints []string) []ocispec.Descriptor {
+	trusted := make(map[string]struct{}, len(trustedThumbprints))
+	for _, thumbprint := range trustedThumbprints {
+		trusted[strings.ToLower(thumbprint)] = struct{}{}
+	}
+
+	type candidate struct {
+		desc      ocispec.Descriptor
+		priority  int
+		createdAt time.Time
+	}
+	candidates := make([]candidate, len(signatureManifests))
+	for i, desc := range signatureManifests {
+		candidates[i] = candidate{
+			desc:     desc,
+			priority: signaturePriority(desc.Annotations, trusted),
+		}
+		if created, err := time.Parse(time.RFC3339, desc.Annotations[ocispec.AnnotationCreated]); err == nil {
+			candidates[i].createdAt = created
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].createdAt.After(candidates[j].createdAt)
+	})
+
+	prioritized := make([]ocispec.Descriptor, len(candidates))
+	for i, c := range candidates {
+		prioritized[i] = c.desc
+	}
+	return prioritized
+}
+
+// signaturePriority returns the priority of a signature from the certificate
+// thumbprints in the annotations of its manifest. The annotations are not
+// signed, so they are only used to order the signatures.
+func signaturePriority(annotations map[string]string, trusted map[string]struct{}) int {
+	if len(trusted) == 0 {
+		return priorityUnknown
+	}
+	value, ok := annotations[envelope.AnnotationX509ChainThumbprint]
+	if !ok {
+		return priorityUnknown
+	}
+	var thumbprints []string
+	if err := json.Unmarshal([]byte(value), &thumbprints); err != nil || len(thumbprints) == 0 {
+		return priorityUnknown
+	}
+	for _, thumbprint := range thumbprints {
+		if _, ok := trusted[strings.ToLower(thumbprint)]; ok {
+			return priorityTrusted
+		}
+	}
+	return priorityUntrusted
+}
@@ -0,0 +1,267 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func signatureManifest(name, thumbprints, created string) ocispec.Descriptor {
+	annotations := map[string]string{}
+	if thumbprints != "" {
+		annotations[envelope.AnnotationX509ChainThumbprint] = thumbprints
+	}
+	if created != "" {
+		annotations[ocispec.AnnotationCreated] = created
+	}
+	return ocispec.Descriptor{
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Digest:      digest.FromString(name),
+		Annotations: annotations,
+	}
+}
+
+func TestPrioritizeSignatures(t *testing.T) {
+	untrustedOld := signatureManifest("untrusted-old", `["ccc"]`, "2023-01-01T00:00:00Z")
+	unknown := signatureManifest("unknown", "", "2024-01-01T00:00:00Z")
+	trustedOld := signatureManifest("trusted-old", `["aaa","bbb"]`, "2023-01-01T00:00:00Z")
+	malformed := signatureManifest("malformed", "not json", "")
+	trustedNew := signatureManifest("trusted-new", `["AAA"]`, "2024-01-01T00:00:00Z")
+	untrustedNew := signatureManifest("untrusted-new", `["ccc"]`, "2024-06-01T00:00:00Z")
+	manifests := []ocispec.Descriptor{untrustedOld, unknown, trustedOld, malformed, trustedNew, untrustedNew}
+
+	t.Run("with trusted thumbprints", func(t *testing.T) {
+		got := prioritizeSignatures(manifests, []string{"aaa"})
+		want := []ocispec.Descriptor{trustedNew, trustedOld, unknown, malformed, untrustedNew, untrustedOld}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("prioritizeSignatures() = %v, want %v", digests(got), digests(want))
+		}
+	})
+
+	t.Run("without trusted thumbprints", func(t *testing.T) {
+		got := prioritizeSignatures(manifests, nil)
+		want := []ocispec.Descriptor{untrustedNew, unknown, trustedNew, untrustedOld, trustedOld, malformed}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("prioritizeSignatures() = %v, want %v", digests(got), digests(want))
+		}
+	})
+}
+
+func digests(descs []ocispec.Descriptor) []digest.Digest {
+	var result []digest.Digest
+	for _, desc := range descs {
+		result = append(result, desc.Digest)
+	}
+	return result
+}
+
+type prioritizeRepository struct {
+	mock.Repository
+	fetched []digest.Digest
+
+	// envelopeTypes maps the signature manifest digests to the media types
+	// of their envelopes
+	envelopeTypes map[digest.Digest]string
+}
+
+func (r *prioritizeRepository) FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	mediaType, ok := r.envelopeTypes[desc.Digest]
+	if !ok {
+		return ocispec.Descriptor{}, errors.New("manifest not found")
+	}
+	return ocispec.Descriptor{MediaType: mediaType}, nil
+}
+
+func (r *prioritizeRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	r.fetched = append(r.fetched, desc.Digest)
+	return r.Repository.FetchSignatureBlob(ctx, desc)
+}
+
+type thumbprintVerifier struct {
+	dummyVerifier
+	thumbprints []string
+	err         error
+}
+
+func (v *thumbprintVerifier) TrustedThumbprints(_ context.Context, _ VerifierVerifyOptions) ([]string, error) {
+	return v.thumbprints, v.err
+}
+
+func TestVerifyPrioritizeSignatures(t *testing.T) {
+	untrusted := signatureManifest("untrusted", `["ccc"]`, "2024-06-01T00:00:00Z")
+	trusted := signatureManifest("trusted", `["aaa"]`, "2023-01-01T00:00:00Z")
+	policyDocument := dummyPolicyDocument()
+
+	tests := []struct {
+		name     string
+		verifier Verifier
+		opts     VerifyOptions
+		want     []digest.Digest
+	}{
+		{
+			name:     "not prioritized",
+			verifier: &thumbprintVerifier{dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false}, thumbprints: []string{"aaa"}},
+			opts:     VerifyOptions{},
+			want:     []digest.Digest{untrusted.Digest, trusted.Digest},
+		},
+		{
+			name:     "thumbprints from verifier",
+			verifier: &thumbprintVerifier{dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false}, thumbprints: []string{"aaa"}},
+			opts:     VerifyOptions{PrioritizeSignatures: true},
+			want:     []digest.Digest{trusted.Digest, untrusted.Digest},
+		},
+		{
+			name:     "thumbprints from options",
+			verifier: &dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false},
+			opts:     VerifyOptions{PrioritizeSignatures: true, TrustedThumbprints: []string{"aaa"}},
+			want:     []digest.Digest{trusted.Digest, untrusted.Digest},
+		},
+		{
+			name:     "verifier thumbprints error",
+			verifier: &thumbprintVerifier{dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false}, err: errors.New("trust store error")},
+			opts:     VerifyOptions{PrioritizeSignatures: true},
+			want:     []digest.Digest{untrusted.Digest, trusted.Digest},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &prioritizeRepository{Repository: mock.NewRepository()}
+			repo.ListSignaturesResponse = []ocispec.Descriptor{untrusted, trusted}
+			opts := tt.opts
+			opts.ArtifactReference = mock.SampleArtifactUri
+			opts.MaxSignatureAttempts = 50
+			// all the signatures fail verification, so all of them are
+			// fetched in the order of verification
+			if _, _, err := Verify(context.Background(), tt.verifier, repo, opts); err == nil {
+				t.Fatal("expected verification failure")
+			}
+			if !reflect.DeepEqual(repo.fetched, tt.want) {
+				t.Fatalf("fetched %v, want %v", repo.fetched, tt.want)
+			}
+		})
+	}
+
+	t.Run("max signature attempts", func(t *testing.T) {
+		repo := &prioritizeRepository{Repository: mock.NewRepository()}
+		repo.ListSignaturesResponse = []ocispec.Descriptor{untrusted, untrusted, trusted}
+		verifier := &thumbprintVerifier{dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}, thumbprints: []string{"aaa"}}
+		opts := VerifyOptions{
+			ArtifactReference:    mock.SampleArtifactUri,
+			MaxSignatureAttempts: 1,
+			PrioritizeSignatures: true,
+		}
+		if _, _, err := Verify(context.Background(), verifier, repo, opts); err != nil {
+			t.Fatalf("Verify() failed: %v", err)
+		}
+		if !reflect.DeepEqual(repo.fetched, []digest.Digest{trusted.Digest}) {
+			t.Fatalf("fetched %v, want the trusted signature only", repo.fetched)
+		}
+	})
+
+	t.Run("list signatures error", func(t *testing.T) {
+		repo := &prioritizeRepository{Repository: mock.NewRepository()}
+		repo.ListSignaturesError = errors.New("list error")
+		opts := VerifyOptions{
+			ArtifactReference:    mock.SampleArtifactUri,
+			MaxSignatureAttempts: 50,
+			PrioritizeSignatures: true,
+		}
+		if _, _, err := Verify(context.Background(), &dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}, repo, opts); err == nil || err.Error() != "list error" {
+			t.Fatalf("expected list error, got %v", err)
+		}
+		if len(repo.fetched) != 0 {
+			t.Fatalf("no signature should be fetched, got %v", repo.fetched)
+		}
+	})
+	t.Run("envelope type filter", func(t *testing.T) {
+		cose := signatureManifest("cose", `["aaa"]`, "2024-06-01T00:00:00Z")
+		jose := signatureManifest("jose", `["ccc"]`, "2023-01-01T00:00:00Z")
+		repo := &prioritizeRepository{
+			Repository: mock.NewRepository(),
+			envelopeTypes: map[digest.Digest]string{
+				cose.Digest: "application/cose",
+				jose.Digest: "application/jose+json",
+			},
+		}
+		repo.ListSignaturesResponse = []ocispec.Descriptor{cose, jose}
+		opts := VerifyOptions{
+			ArtifactReference:    mock.SampleArtifactUri,
+			MaxSignatureAttempts: 1,
+			PrioritizeSignatures: true,
+			SignatureMediaTypes:  []string{"application/jose+json"},
+			TrustedThumbprints:   []string{"aaa"},
+		}
+		// the filtered signature does not count against the attempts
+		if _, _, err := Verify(context.Background(), &dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}, repo, opts); err != nil {
+			t.Fatalf("Verify() failed: %v", err)
+		}
+		if !reflect.DeepEqual(repo.fetched, []digest.Digest{jose.Digest}) {
+			t.Fatalf("fetched %v, want the jose signature only", repo.fetched)
+		}
+
+		// no signature has an accepted envelope type
+		repo = &prioritizeRepository{
+			Repository:    mock.NewRepository(),
+			envelopeTypes: map[digest.Digest]string{cose.Digest: "application/cose"},
+		}
+		repo.ListSignaturesResponse = []ocispec.Descriptor{cose}
+		_, _, err := Verify(context.Background(), &dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}, repo, opts)
+		var retrievalErr ErrorSignatureRetrievalFailed
+		if !errors.As(err, &retrievalErr) {
+			t.Fatalf("expected ErrorSignatureRetrievalFailed, got %v", err)
+		}
+		if len(repo.fetched) != 0 {
+			t.Fatalf("no signature should be fetched, got %v", repo.fetched)
+		}
+	})
+
+	t.Run("max signature candidates", func(t *testing.T) {
+		var manifests []ocispec.Descriptor
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			manifests = append(manifests, signatureManifest(name, "", ""))
+		}
+		for _, tt := range []struct {
+			maxAttempts   int
+			maxCandidates int
+			want          int
+		}{
+			{maxAttempts: 50, maxCandidates: 2, want: 2},
+			{maxAttempts: 50, maxCandidates: 0, want: 5},
+		} {
+			repo := &prioritizeRepository{Repository: mock.NewRepository()}
+			repo.ListSignaturesResponse = manifests
+			opts := VerifyOptions{
+				ArtifactReference:      mock.SampleArtifactUri,
+				MaxSignatureAttempts:   tt.maxAttempts,
+				MaxSignatureCandidates: tt.maxCandidates,
+				PrioritizeSignatures:   true,
+			}
+			if _, _, err := Verify(context.Background(), &dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false}, repo, opts); err == nil {
+				t.Fatal("expected verification failure")
+			}
+			if len(repo.fetched) != tt.want {
+				t.Fatalf("fetched %d signatures, want %d", len(repo.fetched), tt.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+// ProgressEventType is the type of a [ProgressEvent].
+type ProgressEventType string
+
+// Types of the progress events.
+const (
+	// ProgressSignatureGenerated is reported when the signature of the
+	// artifact is generated by the signer, before it is pushed.
+	ProgressSignatureGenerated ProgressEventType = "signatureGenerated"
+
+	// ProgressSignaturePushed is reported when the signature is pushed to
+	// the repository.
+	ProgressSignaturePushed ProgressEventType = "signaturePushed"
+
+	// ProgressSignatureFetched is reported when a signature envelope is
+	// fetched from the repository, before it is verified.
+	ProgressSignatureFetched ProgressEventType = "signatureFetched"
+
+	// ProgressRevocationCheckStarted is reported by the verifier when the
+	// revocation check of the certificate chain of a signature starts.
+	ProgressRevocationCheckStarted ProgressEventType = "revocationCheckStarted"
+
+	// ProgressSignatureVerified is reported when the verification of a
+	// signature completes, successfully or not.
+	ProgressSignatureVerified ProgressEventType = "signatureVerified"
+)
+
+// ProgressEvent reports the progress of a signing or verification operation.
+type ProgressEvent struct {
+	// Type is the type of the event.
+	Type ProgressEventType
+
+	// Artifact is the manifest descriptor of the artifact.
+	Artifact ocispec.Descriptor
+
+	// Signature is the descriptor of the signature manifest, if known.
+	Signature ocispec.Descriptor
+
+	// Count is the number of the signatures fetched so far, starting at 1,
+	// for the events of a verification.
+	Count int
+
+	// Total is the maximum number of the signatures to verify, i.e.
+	// VerifyOptions.MaxSignatureAttempts, for the events of a verification.
+	// The number of the signatures of the artifact is not known in advance.
+	Total int
+
+	// Error is the verification error of the signature for
+	// [ProgressSignatureVerified] events, nil if the signature is verified
+	// successfully.
+	Error error
+
+	// Outcome is the verification outcome of the signature for
+	// [ProgressSignatureVerified] events, if any. It may be shared with the
+	// verification cache and must not be modified.
+	Outcome *VerificationOutcome
+}
+
+// ProgressFunc is called synchronously with the progress events of an
+// operation. It must not block.
+type ProgressFunc func(event ProgressEvent)
+
+// report calls f with event if f is not nil.
+func (f ProgressFunc) report(event ProgressEvent) {
+	if f != nil {
+		f(event)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestProgress(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var signEvents []notation.ProgressEvent
+	_, sigManifestDesc, err := notation.SignOCI(ctx, s, repo, notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+		Progress: func(event notation.ProgressEvent) {
+			signEvents = append(signEvents, event)
+		},
+	})
+	if err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+	wantSignEvents := []notation.ProgressEvent{
+		{Type: notation.ProgressSignatureGenerated, Artifact: artifactDesc},
+		{Type: notation.ProgressSignaturePushed, Artifact: artifactDesc, Signature: sigManifestDesc},
+	}
+	if !reflect.DeepEqual(signEvents, wantSignEvents) {
+		t.Fatalf("sign progress events = %+v, want %+v", signEvents, wantSignEvents)
+	}
+
+	v, err := verifier.NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "test", s.Root()), verifier.VerifierOptions{
+		OCITrustPolicy: notationtest.TrustPolicy("test"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var verifyEvents []notation.ProgressEventType
+	_, _, err = notation.Verify(ctx, v, repo, notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 5,
+		Progress: func(event notation.ProgressEvent) {
+			if event.Artifact.Digest != artifactDesc.Digest {
+				t.Errorf("progress event artifact = %v, want %v", event.Artifact.Digest, artifactDesc.Digest)
+			}
+			if event.Type != notation.ProgressRevocationCheckStarted && (event.Count != 1 || event.Total != 5 || event.Signature.Digest != sigManifestDesc.Digest) {
+				t.Errorf("progress event = %+v", event)
+			}
+			verifyEvents = append(verifyEvents, event.Type)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	wantVerifyEvents := []notation.ProgressEventType{
+		notation.ProgressSignatureFetched,
+		notation.ProgressRevocationCheckStarted,
+		notation.ProgressSignatureVerified,
+	}
+	if !reflect.DeepEqual(verifyEvents, wantVerifyEvents) {
+		t.Fatalf("verify progress events = %v, want %v", verifyEvents, wantVerifyEvents)
+	}
+}
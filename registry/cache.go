@@ -0,0 +1,176 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// CachingMiddleware returns a [Middleware] caching the results of the calls
+// to the repository in memory for ttl:
+//   - Resolve caches the descriptors of the references. The descriptor of a
+//     tag is stale for up to ttl after the tag is moved.
+//   - FetchSignatureBlob, FetchSignatureBlobDescriptor, FetchSignatureSubject
+//     and DescribeArtifact cache the results for the manifest digests, which
+//     are content addressed.
+//
+// ListSignatures is not cached, so that the new signatures are listed.
+// DeleteSignature evicts the results cached for the signature manifest.
+// Failed calls are not cached.
+//
+// If ttl is not positive, the results do not expire. At most maxEntries
+// results are cached per repository; if maxEntries is not positive, the
+// number of results is not limited. The cached signature blobs are shared
+// among the callers and must not be modified.
+func CachingMiddleware(ttl time.Duration, maxEntries int) Middleware {
+	return func(repo Repository) Repository {
+		return &cachingRepository{
+			Wrapper:    Wrapper{Repository: repo},
+			ttl:        ttl,
+			maxEntries: maxEntries,
+			entries:    make(map[cacheKey]cacheEntry),
+		}
+	}
+}
+
+// cacheKey is the key of a result cached by a cachingRepository.
+type cacheKey struct {
+	method string
+	key    string
+}
+
+type cacheEntry struct {
+	value  any
+	expiry time.Time
+}
+
+// signatureBlob is the cached result of FetchSignatureBlob.
+type signatureBlob struct {
+	blob []byte
+	desc ocispec.Descriptor
+}
+
+// cachingRepository caches the results of the wrapped repository.
+type cachingRepository struct {
+	Wrapper
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+}
+
+func (r *cachingRepository) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	return cached(r, cacheKey{"Resolve", reference}, func() (ocispec.Descriptor, error) {
+		return r.Wrapper.Resolve(ctx, reference)
+	})
+}
+
+func (r *cachingRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	result, err := cached(r, cacheKey{"FetchSignatureBlob", desc.Digest.String()}, func() (signatureBlob, error) {
+		blob, blobDesc, err := r.Wrapper.FetchSignatureBlob(ctx, desc)
+		return signatureBlob{blob: blob, desc: blobDesc}, err
+	})
+	if err != nil {
+		return nil, ocispec.Descriptor{}, err
+	}
+	return result.blob, result.desc, nil
+}
+
+func (r *cachingRepository) FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return cached(r, cacheKey{"FetchSignatureBlobDescriptor", desc.Digest.String()}, func() (ocispec.Descriptor, error) {
+		return r.Wrapper.FetchSignatureBlobDescriptor(ctx, desc)
+	})
+}
+
+func (r *cachingRepository) FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return cached(r, cacheKey{"FetchSignatureSubject", desc.Digest.String()}, func() (ocispec.Descriptor, error) {
+		return r.Wrapper.FetchSignatureSubject(ctx, desc)
+	})
+}
+
+func (r *cachingRepository) DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return cached(r, cacheKey{"DescribeArtifact", desc.Digest.String()}, func() (ocispec.Descriptor, error) {
+		return r.Wrapper.DescribeArtifact(ctx, desc)
+	})
+}
+
+func (r *cachingRepository) DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error {
+	r.mu.Lock()
+	for _, method := range []string{"FetchSignatureBlob", "FetchSignatureBlobDescriptor", "FetchSignatureSubject", "DescribeArtifact"} {
+		delete(r.entries, cacheKey{method, desc.Digest.String()})
+	}
+	r.mu.Unlock()
+	return r.Wrapper.DeleteSignature(ctx, desc)
+}
+
+// cached returns the value cached with key, or calls fetch and caches the
+// value it returns on success.
+func cached[T any](r *cachingRepository, key cacheKey, fetch func() (T, error)) (T, error) {
+	if value, ok := r.get(key); ok {
+		return value.(T), nil
+	}
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	r.set(key, value)
+	return value, nil
+}
+
+// get returns the value cached with key, or false if there is none or it has
+// expired.
+func (r *cachingRepository) get(key cacheKey) (any, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !entry.expiry.IsZero() && time.Now().After(entry.expiry) {
+		delete(r.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// set caches value with key. If the cache is full, the expired values are
+// evicted first, followed by arbitrary values if needed.
+func (r *cachingRepository) set(key cacheKey, value any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if _, ok := r.entries[key]; !ok && r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+		for k, entry := range r.entries {
+			if !entry.expiry.IsZero() && now.After(entry.expiry) {
+				delete(r.entries, k)
+			}
+		}
+		for k := range r.entries {
+			if len(r.entries) < r.maxEntries {
+				break
+			}
+			delete(r.entries, k)
+		}
+	}
+	var expiry time.Time
+	if r.ttl > 0 {
+		expiry = now.Add(r.ttl)
+	}
+	r.entries[key] = cacheEntry{value: value, expiry: expiry}
+}
@@ -0,0 +1,90 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestCachingMiddleware(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepository()
+	repo := Wrap(inner, CachingMiddleware(time.Hour, 0))
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.Resolve(ctx, "latest"); err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if _, _, err := repo.FetchSignatureBlob(ctx, mock.SigManfiestDescriptor); err != nil {
+			t.Fatalf("FetchSignatureBlob() error = %v", err)
+		}
+	}
+	if inner.calls["Resolve"] != 1 || inner.calls["FetchSignatureBlob"] != 1 {
+		t.Fatalf("calls = %v, want cached results", inner.calls)
+	}
+
+	// deleting the signature evicts its blob
+	deleter := repo.(interface {
+		DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error
+	})
+	if err := deleter.DeleteSignature(ctx, mock.SigManfiestDescriptor); err != nil {
+		t.Fatalf("DeleteSignature() error = %v", err)
+	}
+	repo.FetchSignatureBlob(ctx, mock.SigManfiestDescriptor)
+	if inner.calls["FetchSignatureBlob"] != 2 {
+		t.Fatalf("FetchSignatureBlob() was not evicted on deletion")
+	}
+}
+
+func TestCachingMiddleware_Expiry(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepository()
+	repo := Wrap(inner, CachingMiddleware(time.Nanosecond, 0))
+	repo.Resolve(ctx, "latest")
+	time.Sleep(time.Millisecond)
+	repo.Resolve(ctx, "latest")
+	if inner.calls["Resolve"] != 2 {
+		t.Fatalf("Resolve() called %d times, want the expired result fetched again", inner.calls["Resolve"])
+	}
+}
+
+func TestCachingMiddleware_Errors(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepository()
+	inner.ResolveError = errors.New("resolve failed")
+	repo := Wrap(inner, CachingMiddleware(time.Hour, 0))
+	repo.Resolve(ctx, "latest")
+	repo.Resolve(ctx, "latest")
+	if inner.calls["Resolve"] != 2 {
+		t.Fatalf("Resolve() called %d times, want failures not cached", inner.calls["Resolve"])
+	}
+}
+
+func TestCachingMiddleware_MaxEntries(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepository()
+	repo := Wrap(inner, CachingMiddleware(time.Hour, 2)).(*cachingRepository)
+	for _, reference := range []string{"v1", "v2", "v3"} {
+		repo.Resolve(ctx, reference)
+	}
+	if len(repo.entries) != 2 {
+		t.Fatalf("cached %d results, want 2", len(repo.entries))
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/notaryproject/notation-go/internal/file"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ContentStore stores the content addressed results of the calls to
+// repositories. A ContentStore is shared among repositories and Verify
+// calls, e.g. by a scanner verifying the same digests repeatedly, see
+// [ContentCachingMiddleware].
+//
+// Implementations must be safe for concurrent use.
+type ContentStore interface {
+	// Get returns the content stored with key, or false if there is none.
+	Get(ctx context.Context, key string) ([]byte, bool)
+
+	// Set stores content with key.
+	Set(ctx context.Context, key string, content []byte) error
+}
+
+// MemoryContentStore is an in-memory [ContentStore].
+type MemoryContentStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+// NewMemoryContentStore returns a MemoryContentStore storing at most
+// maxEntries contents. Arbitrary contents are evicted when the store is full.
+// If maxEntries is not positive, the number of contents is not limited.
+func NewMemoryContentStore(maxEntries int) *MemoryContentStore {
+	return &MemoryContentStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string][]byte),
+	}
+}
+
+// Get returns the content stored with key, or false if there is none.
+func (s *MemoryContentStore) Get(_ context.Context, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.entries[key]
+	return content, ok
+}
+
+// Set stores content with key.
+func (s *MemoryContentStore) Set(_ context.Context, key string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[key]; !ok && s.maxEntries > 0 {
+		for k := range s.entries {
+			if len(s.entries) < s.maxEntries {
+				break
+			}
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = content
+	return nil
+}
+
+// FileContentStore is a [ContentStore] storing the contents as files in a
+// directory, so that they are shared among processes.
+//
+// The contents are written to a temporary file and renamed, so that a
+// content is never read partially written.
+type FileContentStore struct {
+	// root is the root directory of the store
+	root string
+}
+
+// NewFileContentStore creates a FileContentStore with root as the root
+// directory.
+//
+// An example for root is `dir.CacheFS().SysPath(dir.PathContentCache)`
+func NewFileContentStore(root string) (*FileContentStore, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create content file store: %w", err)
+	}
+	return &FileContentStore{
+		root: root,
+	}, nil
+}
+
+// Get returns the content stored with key, or false if there is none or it
+// cannot be read.
+func (s *FileContentStore) Get(ctx context.Context, key string) ([]byte, bool) {
+	content, err := os.ReadFile(filepath.Join(s.root, s.fileName(key)))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.GetLogger(ctx).Warnf("Failed to read content %q from file store: %v", key, err)
+		}
+		return nil, false
+	}
+	return content, true
+}
+
+// Set stores content with key.
+func (s *FileContentStore) Set(_ context.Context, key string, content []byte) error {
+	if err := file.WriteFile(s.root, filepath.Join(s.root, s.fileName(key)), content); err != nil {
+		return fmt.Errorf("failed to store content %q in file store: %w", key, err)
+	}
+	return nil
+}
+
+// fileName returns the filename of the content stored with key
+func (s *FileContentStore) fileName(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// ContentCachingMiddleware returns a [Middleware] caching the content
+// addressed results of the calls to the repository in store:
+//   - Resolve caches the descriptors of the digest references. The tags are
+//     resolved by the repository.
+//   - FetchSignatureBlob, FetchSignatureBlobDescriptor, FetchSignatureSubject
+//     and DescribeArtifact cache the results for the manifest digests.
+//
+// ListSignatures is not cached, so that the new signatures are listed.
+// Failed calls are not cached, and the failures to store a result are
+// logged and ignored.
+//
+// Unlike [CachingMiddleware], the results do not expire, and are shared
+// among the repositories wrapped with the same store. The cached signature
+// blobs are checked against their digest when loaded, so that a corrupted
+// store is not trusted.
+func ContentCachingMiddleware(store ContentStore) Middleware {
+	return func(repo Repository) Repository {
+		return &contentCachingRepository{
+			Wrapper: Wrapper{Repository: repo},
+			store:   store,
+		}
+	}
+}
+
+// contentCachingRepository caches the content addressed results of the
+// wrapped repository in a ContentStore.
+type contentCachingRepository struct {
+	Wrapper
+	store ContentStore
+}
+
+// contentSignatureBlob is the stored result of FetchSignatureBlob.
+type contentSignatureBlob struct {
+	Blob       []byte             `json:"blob"`
+	Descriptor ocispec.Descriptor `json:"descriptor"`
+}
+
+func (r *contentCachingRepository) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	if _, err := digest.Parse(reference); err != nil {
+		return r.Wrapper.Resolve(ctx, reference)
+	}
+	return storedContent(ctx, r.store, "Resolve/"+reference, func(desc ocispec.Descriptor) bool {
+		return desc.Digest.String() == reference
+	}, func() (ocispec.Descriptor, error) {
+		return r.Wrapper.Resolve(ctx, reference)
+	})
+}
+
+func (r *contentCachingRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	result, err := storedContent(ctx, r.store, "FetchSignatureBlob/"+desc.Digest.String(), func(result contentSignatureBlob) bool {
+		return result.Descriptor.Digest.Validate() == nil &&
+			result.Descriptor.Size == int64(len(result.Blob)) &&
+			result.Descriptor.Digest.Algorithm().FromBytes(result.Blob) == result.Descriptor.Digest
+	}, func() (contentSignatureBlob, error) {
+		blob, blobDesc, err := r.Wrapper.FetchSignatureBlob(ctx, desc)
+		return contentSignatureBlob{Blob: blob, Descriptor: blobDesc}, err
+	})
+	if err != nil {
+		return nil, ocispec.Descriptor{}, err
+	}
+	return result.Blob, result.Descriptor, nil
+}
+
+func (r *contentCachingRepository) FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return storedContent(ctx, r.store, "FetchSignatureBlobDescriptor/"+desc.Digest.String(), nil, func() (ocispec.Descriptor, error) {
+		return r.Wrapper.FetchSignatureBlobDescriptor(ctx, desc)
+	})
+}
+
+func (r *contentCachingRepository) FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return storedContent(ctx, r.store, "FetchSignatureSubject/"+desc.Digest.String(), nil, func() (ocispec.Descriptor, error) {
+		return r.Wrapper.FetchSignatureSubject(ctx, desc)
+	})
+}
+
+func (r *contentCachingRepository) DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return storedContent(ctx, r.store, "DescribeArtifact/"+desc.Digest.String(), nil, func() (ocispec.Descriptor, error) {
+		return r.Wrapper.DescribeArtifact(ctx, desc)
+	})
+}
+
+// storedContent returns the value stored with key, or calls fetch and stores
+// the value it returns on success. The stored value, or the fetched one
+// before it is stored, is ignored if valid is set and returns false.
+func storedContent[T any](ctx context.Context, store ContentStore, key string, valid func(T) bool, fetch func() (T, error)) (T, error) {
+	logger := log.GetLogger(ctx)
+	if content, ok := store.Get(ctx, key); ok {
+		var value T
+		if err := json.Unmarshal(content, &value); err == nil && (valid == nil || valid(value)) {
+			return value, nil
+		}
+		logger.Warnf("Ignoring invalid content %q in the content store", key)
+	}
+	value, err := fetch()
+	if err != nil || (valid != nil && !valid(value)) {
+		return value, err
+	}
+	content, err := json.Marshal(value)
+	if err == nil {
+		err = store.Set(ctx, key, content)
+	}
+	if err != nil {
+		logger.Warnf("Failed to store content %q: %v", key, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,155 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// blobRepository is a countingRepository serving sigBlob with its
+// descriptor.
+type blobRepository struct {
+	*countingRepository
+}
+
+func (r blobRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	r.calls["FetchSignatureBlob"]++
+	blob := r.FetchSignatureBlobResponse
+	return blob, ocispec.Descriptor{
+		MediaType: "application/jose+json",
+		Digest:    digest.FromBytes(blob),
+		Size:      int64(len(blob)),
+	}, nil
+}
+
+func TestContentCachingMiddleware(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryContentStore(0)
+	reference := mock.ImageDescriptor.Digest.String()
+
+	var inners []blobRepository
+	var blobs [][]byte
+	for i := 0; i < 2; i++ {
+		// the results are shared among repositories
+		inner := blobRepository{newCountingRepository()}
+		inners = append(inners, inner)
+		repo := Wrap(inner, ContentCachingMiddleware(store))
+		desc, err := repo.Resolve(ctx, reference)
+		if err != nil || desc.Digest != mock.ImageDescriptor.Digest {
+			t.Fatalf("Resolve() = %v, %v", desc, err)
+		}
+		if _, err := repo.Resolve(ctx, "latest"); err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		blob, _, err := repo.FetchSignatureBlob(ctx, mock.SigManfiestDescriptor)
+		if err != nil {
+			t.Fatalf("FetchSignatureBlob() error = %v", err)
+		}
+		blobs = append(blobs, blob)
+	}
+	if !reflect.DeepEqual(blobs[0], blobs[1]) {
+		t.Fatal("FetchSignatureBlob() returned a different cached blob")
+	}
+	if inners[0].calls["Resolve"] != 2 || inners[0].calls["FetchSignatureBlob"] != 1 {
+		t.Fatalf("first repository calls = %v", inners[0].calls)
+	}
+	// only the tag is resolved by the second repository
+	if inners[1].calls["Resolve"] != 1 || inners[1].calls["FetchSignatureBlob"] != 0 {
+		t.Fatalf("second repository calls = %v, want cached results", inners[1].calls)
+	}
+}
+
+func TestContentCachingMiddleware_InvalidContent(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryContentStore(0)
+	key := "FetchSignatureBlob/" + mock.SigManfiestDescriptor.Digest.String()
+	for _, content := range []string{`{`, `{"blob":"dGFtcGVyZWQ=","descriptor":{"digest":"sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855","size":8}}`} {
+		store.Set(ctx, key, []byte(content))
+		inner := blobRepository{newCountingRepository()}
+		repo := Wrap(inner, ContentCachingMiddleware(store))
+		blob, _, err := repo.FetchSignatureBlob(ctx, mock.SigManfiestDescriptor)
+		if err != nil {
+			t.Fatalf("FetchSignatureBlob() error = %v", err)
+		}
+		if inner.calls["FetchSignatureBlob"] != 1 || !reflect.DeepEqual(blob, inner.FetchSignatureBlobResponse) {
+			t.Fatalf("FetchSignatureBlob() did not ignore invalid content %s", content)
+		}
+	}
+
+	// a blob not matching its descriptor is not cached
+	inner := newCountingRepository()
+	repo := Wrap(inner, ContentCachingMiddleware(NewMemoryContentStore(0)))
+	for i := 0; i < 2; i++ {
+		repo.FetchSignatureBlob(ctx, mock.SigManfiestDescriptor)
+	}
+	if inner.calls["FetchSignatureBlob"] != 2 {
+		t.Fatalf("FetchSignatureBlob() called %d times, want invalid blob not cached", inner.calls["FetchSignatureBlob"])
+	}
+}
+
+func TestMemoryContentStore_MaxEntries(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryContentStore(2)
+	for _, key := range []string{"a", "b", "c"} {
+		if err := store.Set(ctx, key, []byte(key)); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if len(store.entries) != 2 {
+		t.Fatalf("stored %d contents, want 2", len(store.entries))
+	}
+	if content, ok := store.Get(ctx, "c"); !ok || string(content) != "c" {
+		t.Fatalf("Get() = %s, %v", content, ok)
+	}
+}
+
+func TestFileContentStore(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "content")
+	store, err := NewFileContentStore(root)
+	if err != nil {
+		t.Fatalf("NewFileContentStore() error = %v", err)
+	}
+	if _, ok := store.Get(ctx, "missing"); ok {
+		t.Fatal("Get() expects no content for missing key")
+	}
+	if err := store.Set(ctx, "key", []byte("content")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// the contents are shared among stores with the same root
+	store, err = NewFileContentStore(root)
+	if err != nil {
+		t.Fatalf("NewFileContentStore() error = %v", err)
+	}
+	if content, ok := store.Get(ctx, "key"); !ok || string(content) != "content" {
+		t.Fatalf("Get() = %s, %v", content, ok)
+	}
+
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileContentStore(notDir); err == nil {
+		t.Fatal("NewFileContentStore() expects error for a file root")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Schemes of the registry endpoints.
+const (
+	endpointSchemeHTTP  = "http"
+	endpointSchemeHTTPS = "https"
+	endpointSchemeUnix  = "unix"
+)
+
+// parseEndpoint parses the endpoint of a registry host, e.g.
+// "unix:///run/registry.sock", "http://registry-proxy.svc:8080/prefix" or
+// "https://registry-proxy.example.com".
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid endpoint %q: user info, query and fragment are not supported", endpoint)
+	}
+	switch u.Scheme {
+	case endpointSchemeHTTP, endpointSchemeHTTPS:
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+		}
+	case endpointSchemeUnix:
+		if u.Host != "" || u.Path == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: want unix:///path/to/socket", endpoint)
+		}
+	default:
+		return nil, fmt.Errorf("invalid endpoint %q: unsupported scheme %q, supported schemes are %q, %q and %q", endpoint, u.Scheme, endpointSchemeHTTP, endpointSchemeHTTPS, endpointSchemeUnix)
+	}
+	return u, nil
+}
+
+// endpointTransport sends the requests to a registry host to its endpoint
+// instead. The other requests, e.g. to the token servers, are sent by the
+// base transport as is.
+type endpointTransport struct {
+	host     string
+	endpoint *url.URL
+	base     http.RoundTripper
+
+	// transport sends the requests to the endpoint. It dials the unix
+	// socket of a unix endpoint.
+	transport http.RoundTripper
+}
+
+// newEndpointTransport returns a transport sending the requests to host to
+// endpoint, and the other requests to base.
+func newEndpointTransport(host string, endpoint *url.URL, base *http.Transport) *endpointTransport {
+	t := &endpointTransport{
+		host:      host,
+		endpoint:  endpoint,
+		base:      base,
+		transport: base,
+	}
+	if endpoint.Scheme == endpointSchemeUnix {
+		transport := base.Clone()
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		socket := endpoint.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dial(ctx, "unix", socket)
+		}
+		transport.DialTLSContext = func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("TLS is not supported over a unix socket")
+		}
+		t.transport = transport
+	}
+	return t
+}
+
+// RoundTrip sends the request to the endpoint if it is sent to the registry
+// host.
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	switch t.endpoint.Scheme {
+	case endpointSchemeUnix:
+		// the host of the URL is kept, so that the Host header names the
+		// registry host
+		req.URL.Scheme = endpointSchemeHTTP
+	default:
+		req.URL.Scheme = t.endpoint.Scheme
+		req.URL.Host = t.endpoint.Host
+		req.Host = ""
+		if prefix := strings.TrimSuffix(t.endpoint.Path, "/"); prefix != "" {
+			req.URL.Path = prefix + req.URL.Path
+			req.URL.RawPath = ""
+		}
+	}
+	return t.transport.RoundTrip(req)
+}
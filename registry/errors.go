@@ -0,0 +1,175 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// AuthenticationError is used when the registry rejects the credentials of
+// the client, or the client is not authorized to access the repository.
+type AuthenticationError struct {
+	Msg        string
+	InnerError error
+}
+
+func (e AuthenticationError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.InnerError != nil {
+		return e.InnerError.Error()
+	}
+	return "registry authentication failed"
+}
+
+func (e AuthenticationError) Unwrap() error {
+	return e.InnerError
+}
+
+// NotFoundError is used when the artifact, the signature or the repository
+// is not found in the registry.
+type NotFoundError struct {
+	Msg        string
+	InnerError error
+}
+
+func (e NotFoundError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.InnerError != nil {
+		return e.InnerError.Error()
+	}
+	return "not found in the registry"
+}
+
+func (e NotFoundError) Unwrap() error {
+	return e.InnerError
+}
+
+// RateLimitedError is used when the registry rejects the requests of the
+// client for exceeding its rate limit. The request may be retried later.
+type RateLimitedError struct {
+	Msg        string
+	InnerError error
+}
+
+func (e RateLimitedError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.InnerError != nil {
+		return e.InnerError.Error()
+	}
+	return "registry rate limit exceeded"
+}
+
+func (e RateLimitedError) Unwrap() error {
+	return e.InnerError
+}
+
+// ReferrersUnsupportedError is used when the registry does not support the
+// referrers API, while the repository is configured to use it. See
+// [ReferrersAPI].
+type ReferrersUnsupportedError struct {
+	Msg        string
+	InnerError error
+}
+
+func (e ReferrersUnsupportedError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.InnerError != nil {
+		return e.InnerError.Error()
+	}
+	return "registry does not support the referrers API"
+}
+
+func (e ReferrersUnsupportedError) Unwrap() error {
+	return e.InnerError
+}
+
+// TimeoutError is used when a request to the registry timed out. The
+// request may be retried.
+type TimeoutError struct {
+	Msg        string
+	InnerError error
+}
+
+func (e TimeoutError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.InnerError != nil {
+		return e.InnerError.Error()
+	}
+	return "registry request timed out"
+}
+
+func (e TimeoutError) Unwrap() error {
+	return e.InnerError
+}
+
+// wrapError wraps the error err of a registry interaction into the typed
+// error of its failure, if known, preserving its message. The errors of the
+// referrers index of a pushed signature are not wrapped, as the signature is
+// pushed.
+func wrapError(err error) error {
+	switch err.(type) {
+	case nil, AuthenticationError, NotFoundError, RateLimitedError, ReferrersUnsupportedError, TimeoutError:
+		return err
+	}
+	var referrersErr *remote.ReferrersError
+	if errors.As(err, &referrersErr) {
+		return err
+	}
+	var errResp *errcode.ErrorResponse
+	if errors.As(err, &errResp) {
+		switch errResp.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return AuthenticationError{InnerError: err}
+		case http.StatusNotFound:
+			return NotFoundError{InnerError: err}
+		case http.StatusTooManyRequests:
+			return RateLimitedError{InnerError: err}
+		}
+	}
+	if errors.Is(err, errdef.ErrNotFound) {
+		return NotFoundError{InnerError: err}
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return TimeoutError{InnerError: err}
+	}
+	return err
+}
+
+// wrapReferrersError wraps the error err of listing the referrers like
+// wrapError, and the referrers API not supported by the registry into a
+// [ReferrersUnsupportedError].
+func wrapReferrersError(err error) error {
+	if errors.Is(err, errdef.ErrUnsupported) {
+		return ReferrersUnsupportedError{InnerError: err}
+	}
+	return wrapError(err)
+}
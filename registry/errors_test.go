@@ -0,0 +1,134 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// statusRemoteClient responds to every request with its status code, or
+// fails with its error.
+type statusRemoteClient struct {
+	statusCode int
+	err        error
+}
+
+func (c statusRemoteClient) Do(req *http.Request) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &http.Response{
+		Request:    req,
+		StatusCode: c.statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"errors":[]}`)),
+	}, nil
+}
+
+func TestResolveTypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		client statusRemoteClient
+		as     func(error) bool
+	}{
+		{
+			name:   "unauthorized",
+			client: statusRemoteClient{statusCode: http.StatusUnauthorized},
+			as:     func(err error) bool { var e AuthenticationError; return errors.As(err, &e) },
+		},
+		{
+			name:   "forbidden",
+			client: statusRemoteClient{statusCode: http.StatusForbidden},
+			as:     func(err error) bool { var e AuthenticationError; return errors.As(err, &e) },
+		},
+		{
+			name:   "not found",
+			client: statusRemoteClient{statusCode: http.StatusNotFound},
+			as:     func(err error) bool { var e NotFoundError; return errors.As(err, &e) },
+		},
+		{
+			name:   "too many requests",
+			client: statusRemoteClient{statusCode: http.StatusTooManyRequests},
+			as:     func(err error) bool { var e RateLimitedError; return errors.As(err, &e) },
+		},
+		{
+			name:   "timeout",
+			client: statusRemoteClient{err: context.DeadlineExceeded},
+			as:     func(err error) bool { var e TimeoutError; return errors.As(err, &e) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, _ := registry.ParseReference("localhost:5000/test:v1")
+			client := newRepositoryClient(tt.client, ref, true)
+			_, err := client.Resolve(context.Background(), "v1")
+			if err == nil {
+				t.Fatal("Resolve() expects error, got nil")
+			}
+			if !tt.as(err) {
+				t.Fatalf("Resolve() error = %v (%T), unexpected error type", err, err)
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	if err := wrapError(nil); err != nil {
+		t.Fatalf("wrapError(nil) = %v, want nil", err)
+	}
+
+	innerErr := errors.New("unexpected error")
+	if err := wrapError(innerErr); err != innerErr {
+		t.Fatalf("wrapError() = %v, want %v", err, innerErr)
+	}
+
+	notFoundErr := fmt.Errorf("manifest: %w", errdef.ErrNotFound)
+	err := wrapError(notFoundErr)
+	var nfErr NotFoundError
+	if !errors.As(err, &nfErr) || !errors.Is(err, errdef.ErrNotFound) {
+		t.Fatalf("wrapError() = %v, want NotFoundError wrapping errdef.ErrNotFound", err)
+	}
+	if err.Error() != notFoundErr.Error() {
+		t.Fatalf("wrapError() message = %q, want %q", err.Error(), notFoundErr.Error())
+	}
+	if wrapped := wrapError(err); wrapped != err {
+		t.Fatalf("wrapError() wraps a typed error again: %v", wrapped)
+	}
+
+	referrersErr := &remote.ReferrersError{Op: "DeleteReferrersIndex", Err: innerErr}
+	if err := wrapError(referrersErr); err != referrersErr {
+		t.Fatalf("wrapError() = %v, want %v", err, referrersErr)
+	}
+}
+
+func TestWrapReferrersError(t *testing.T) {
+	err := wrapReferrersError(fmt.Errorf("referrers: %w", errdef.ErrUnsupported))
+	var unsupportedErr ReferrersUnsupportedError
+	if !errors.As(err, &unsupportedErr) {
+		t.Fatalf("wrapReferrersError() = %v, want ReferrersUnsupportedError", err)
+	}
+	if err := wrapReferrersError(nil); err != nil {
+		t.Fatalf("wrapReferrersError(nil) = %v, want nil", err)
+	}
+}
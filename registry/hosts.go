@@ -0,0 +1,343 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/dump"
+	"github.com/notaryproject/notation-go/network"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// ReferrersCapability is the referrers capability of a registry host.
+type ReferrersCapability string
+
+// Referrers capabilities of the registry hosts.
+const (
+	// ReferrersAuto detects whether the registry host supports the Referrers
+	// API. It is the default.
+	ReferrersAuto ReferrersCapability = ""
+
+	// ReferrersAPI uses the Referrers API of the registry host.
+	ReferrersAPI ReferrersCapability = "api"
+
+	// ReferrersTagSchema uses the referrers tag schema, for registry hosts
+	// not supporting the Referrers API.
+	ReferrersTagSchema ReferrersCapability = "tag"
+)
+
+// RegistriesConfig is the per-host registries configuration, stored in the
+// registries.json file of the config directory, e.g.
+//
+//	{
+//	  "registries": {
+//	    "localhost:5000": {"plainHTTP": true},
+//	    "registry.internal": {"endpoint": "unix:///run/registry.sock"},
+//	    "registry.example.com": {
+//	      "caBundle": "/etc/ssl/example-ca.pem",
+//	      "credentialHelper": "ecr-login",
+//	      "mirrors": ["mirror.example.com"],
+//	      "referrers": "tag"
+//	    }
+//	  }
+//	}
+//
+// The configuration of the host of a remote repository is applied by
+// [NewRepository] and [NewRepositoryWithOptions].
+type RegistriesConfig struct {
+	// Registries maps the registry hosts, e.g. "registry.example.com:5000",
+	// to their configuration.
+	Registries map[string]HostConfig `json:"registries"`
+}
+
+// HostConfig is the configuration of a registry host.
+type HostConfig struct {
+	// PlainHTTP accesses the registry host with plain HTTP instead of
+	// HTTPS.
+	PlainHTTP bool `json:"plainHTTP,omitempty"`
+
+	// CABundle is the path of a PEM file of CA certificates trusted, in
+	// addition to the system roots, to verify the TLS certificate of the
+	// registry host.
+	CABundle string `json:"caBundle,omitempty"`
+
+	// CredentialHelper is the name of the docker credential helper of the
+	// registry host, e.g. "ecr-login" for the docker-credential-ecr-login
+	// executable.
+	CredentialHelper string `json:"credentialHelper,omitempty"`
+
+	// Mirrors are the hosts mirroring the repositories of the registry
+	// host. The artifacts and their signatures are read from the first
+	// mirror able to serve them, and from the registry host otherwise. The
+	// signatures are always pushed to and deleted from the registry host.
+	Mirrors []string `json:"mirrors,omitempty"`
+
+	// Referrers sets the referrers capability of the registry host instead
+	// of detecting it.
+	Referrers ReferrersCapability `json:"referrers,omitempty"`
+
+	// Endpoint is the endpoint serving the registry host instead of the
+	// host itself, e.g. a registry proxy in the cluster. It is either an
+	// HTTP or HTTPS URL, e.g. "http://registry-proxy.svc:8080/prefix", with
+	// an optional path prefix of the registry API, or a unix domain socket,
+	// e.g. "unix:///run/registry.sock", accessed with plain HTTP. The
+	// requests to the endpoint are authenticated as requests to the
+	// registry host.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// LoadRegistriesConfig loads the registries configuration from the
+// registries.json file of the config directory. An empty configuration is
+// returned if the file does not exist.
+func LoadRegistriesConfig() (*RegistriesConfig, error) {
+	f, err := dir.ConfigFS().Open(dir.PathRegistries)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &RegistriesConfig{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var config RegistriesConfig
+	if err := json.NewDecoder(f).Decode(&config); err != nil {
+		return nil, fmt.Errorf("malformed %s: %w", dir.PathRegistries, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", dir.PathRegistries, err)
+	}
+	return &config, nil
+}
+
+// Validate validates the registries configuration.
+func (c *RegistriesConfig) Validate() error {
+	for host, hostConfig := range c.Registries {
+		if host == "" {
+			return errors.New("registry host cannot be empty")
+		}
+		switch hostConfig.Referrers {
+		case ReferrersAuto, ReferrersAPI, ReferrersTagSchema:
+		default:
+			return fmt.Errorf("registry %q has invalid referrers capability %q, supported values are %q and %q", host, hostConfig.Referrers, ReferrersAPI, ReferrersTagSchema)
+		}
+		if hostConfig.Endpoint != "" {
+			if _, err := parseEndpoint(hostConfig.Endpoint); err != nil {
+				return fmt.Errorf("registry %q has %w", host, err)
+			}
+		}
+		for _, mirror := range hostConfig.Mirrors {
+			if mirror == "" || mirror == host {
+				return fmt.Errorf("registry %q has invalid mirror %q", host, mirror)
+			}
+		}
+	}
+	return nil
+}
+
+// Host returns the configuration of the registry host, and whether the host
+// is configured.
+func (c *RegistriesConfig) Host(host string) (HostConfig, bool) {
+	if c == nil {
+		return HostConfig{}, false
+	}
+	hostConfig, ok := c.Registries[host]
+	return hostConfig, ok
+}
+
+// configure applies the configuration of the host of the remote repository,
+// the network configuration, the endpoint overriding the one of the host
+// configuration, if any, and the insecure transport options, to repo. It
+// returns the warnings of the insecure transport used to access the
+// repository.
+func (c *RegistriesConfig) configure(repo *remote.Repository, netConfig *network.Config, endpoint string, plainHTTP, insecureSkipTLSVerify bool) ([]string, error) {
+	host := repo.Reference.Host()
+	hostConfig, _ := c.Host(host)
+	if endpoint != "" {
+		hostConfig.Endpoint = endpoint
+	}
+	if hostConfig.PlainHTTP || plainHTTP {
+		repo.PlainHTTP = true
+	}
+	if hostConfig.CABundle != "" || hostConfig.CredentialHelper != "" || hostConfig.Endpoint != "" || netConfig != nil || insecureSkipTLSVerify {
+		client, err := configureClient(repo.Client, host, hostConfig, netConfig, insecureSkipTLSVerify)
+		if err != nil {
+			return nil, fmt.Errorf("registry %q: %w", host, err)
+		}
+		repo.Client = client
+	}
+	switch hostConfig.Referrers {
+	case ReferrersAPI:
+		if err := repo.SetReferrersCapability(true); err != nil {
+			return nil, err
+		}
+	case ReferrersTagSchema:
+		if err := repo.SetReferrersCapability(false); err != nil {
+			return nil, err
+		}
+	}
+
+	var warnings []string
+	if hostConfig.Endpoint != "" {
+		// the scheme of the endpoint takes precedence
+		switch u, _ := url.Parse(hostConfig.Endpoint); u.Scheme {
+		case endpointSchemeHTTP:
+			warnings = append(warnings, fmt.Sprintf("registry %q is accessed with plain HTTP through endpoint %q: the traffic is not encrypted and the registry is not authenticated", host, hostConfig.Endpoint))
+		case endpointSchemeHTTPS:
+			if insecureSkipTLSVerify {
+				warnings = append(warnings, fmt.Sprintf("TLS certificate verification of registry %q is skipped: the registry is not authenticated", host))
+			}
+		}
+	} else if repo.PlainHTTP {
+		warnings = append(warnings, fmt.Sprintf("registry %q is accessed with plain HTTP: the traffic is not encrypted and the registry is not authenticated", host))
+	} else if insecureSkipTLSVerify {
+		warnings = append(warnings, fmt.Sprintf("TLS certificate verification of registry %q is skipped: the registry is not authenticated", host))
+	}
+	return warnings, nil
+}
+
+// configureClient returns a copy of the client of a remote repository of
+// host with the CA bundle, the credential helper and the endpoint of the
+// host configuration, the network configuration, and skipping the TLS
+// certificate verification if insecureSkipTLSVerify is set.
+func configureClient(client remote.Client, host string, hostConfig HostConfig, netConfig *network.Config, insecureSkipTLSVerify bool) (*auth.Client, error) {
+	var authClient auth.Client
+	switch c := client.(type) {
+	case nil:
+		authClient.Cache = auth.NewCache()
+	case *auth.Client:
+		authClient = *c
+	default:
+		return nil, fmt.Errorf("cannot apply the TLS configuration or the credential helper to a client of type %T", client)
+	}
+	if hostConfig.CABundle != "" || hostConfig.Endpoint != "" || netConfig != nil || insecureSkipTLSVerify {
+		httpClient, err := newHTTPClient(authClient.Client, hostConfig.CABundle, netConfig, insecureSkipTLSVerify)
+		if err != nil {
+			return nil, err
+		}
+		if hostConfig.Endpoint != "" {
+			endpoint, err := parseEndpoint(hostConfig.Endpoint)
+			if err != nil {
+				return nil, err
+			}
+			httpClient.Transport = newEndpointTransport(host, endpoint, httpClient.Transport.(*http.Transport))
+		}
+		httpClient.Transport = &dump.Transport{
+			Component: string(network.ComponentRegistry),
+			Base:      httpClient.Transport,
+		}
+		authClient.Client = httpClient
+	}
+	if hostConfig.CredentialHelper != "" {
+		authClient.Credential = credentials.Credential(credentials.NewNativeStore(hostConfig.CredentialHelper))
+	}
+	return &authClient, nil
+}
+
+// newHTTPClient returns a copy of client trusting the CA certificates in the
+// PEM file caBundle, if any, in addition to the system roots, dialing with
+// the network configuration netConfig, if any, and skipping the TLS
+// certificate verification if insecureSkipTLSVerify is set.
+func newHTTPClient(client *http.Client, caBundle string, netConfig *network.Config, insecureSkipTLSVerify bool) (*http.Client, error) {
+	var transport *http.Transport
+	switch {
+	case client == nil || client.Transport == nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	default:
+		base := client.Transport
+		if t, ok := base.(*dump.Transport); ok {
+			base = t.Base
+		}
+		if t, ok := base.(*endpointTransport); ok {
+			// the endpoint of another host, e.g. of the upstream registry of
+			// a mirror, does not apply
+			base = t.base
+		}
+		t, ok := base.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("cannot apply the TLS configuration to a transport of type %T", base)
+		}
+		transport = t.Clone()
+	}
+	if netConfig != nil {
+		if err := netConfig.ConfigureTransport(transport); err != nil {
+			return nil, err
+		}
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if caBundle != "" {
+		pemCerts, err := os.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(pemCerts) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificate", caBundle)
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+	if insecureSkipTLSVerify {
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	httpClient := &http.Client{}
+	if client != nil {
+		*httpClient = *client
+	}
+	httpClient.Transport = transport
+	return httpClient, nil
+}
+
+// failedRepository is a Repository whose registries configuration could not
+// be loaded or applied. Its methods fail with the error.
+type failedRepository struct {
+	err error
+}
+
+// Resolve returns the error of the registries configuration.
+func (r failedRepository) Resolve(context.Context, string) (ocispec.Descriptor, error) {
+	return ocispec.Descriptor{}, r.err
+}
+
+// ListSignatures returns the error of the registries configuration.
+func (r failedRepository) ListSignatures(context.Context, ocispec.Descriptor, func([]ocispec.Descriptor) error) error {
+	return r.err
+}
+
+// FetchSignatureBlob returns the error of the registries configuration.
+func (r failedRepository) FetchSignatureBlob(context.Context, ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	return nil, ocispec.Descriptor{}, r.err
+}
+
+// PushSignature returns the error of the registries configuration.
+func (r failedRepository) PushSignature(context.Context, string, []byte, ocispec.Descriptor, map[string]string) (ocispec.Descriptor, ocispec.Descriptor, error) {
+	return ocispec.Descriptor{}, ocispec.Descriptor{}, r.err
+}
@@ -0,0 +1,394 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/dump"
+	"github.com/notaryproject/notation-go/network"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+var testManifest = []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2},"layers":[]}`)
+
+// manifestHandler serves testManifest as the manifest of the tag "v1" of the
+// repository "test", and counts the requests.
+func manifestHandler(requests *atomic.Int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/v2/test/manifests/v1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(testManifest).String())
+		w.Header().Set("Content-Length", strconv.Itoa(len(testManifest)))
+		if r.Method == http.MethodGet {
+			w.Write(testManifest)
+		}
+	}
+}
+
+func newTestRemoteRepository(t *testing.T, serverURL string) *remote.Repository {
+	t.Helper()
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := remote.NewRepository(u.Host + "/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestLoadRegistriesConfig(t *testing.T) {
+	dir.UserConfigDir = t.TempDir()
+	config, err := LoadRegistriesConfig()
+	if err != nil {
+		t.Fatalf("LoadRegistriesConfig() error = %v", err)
+	}
+	if len(config.Registries) != 0 {
+		t.Fatalf("LoadRegistriesConfig() = %+v, want empty config", config)
+	}
+
+	path := filepath.Join(dir.UserConfigDir, dir.PathRegistries)
+	if err := os.WriteFile(path, []byte(`{"registries": {"localhost:5000": {"plainHTTP": true, "mirrors": ["localhost:5001"], "referrers": "tag"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err = LoadRegistriesConfig()
+	if err != nil {
+		t.Fatalf("LoadRegistriesConfig() error = %v", err)
+	}
+	hostConfig, ok := config.Host("localhost:5000")
+	if !ok || !hostConfig.PlainHTTP || hostConfig.Referrers != ReferrersTagSchema || len(hostConfig.Mirrors) != 1 {
+		t.Fatalf("Host() = %+v, %v", hostConfig, ok)
+	}
+	if _, ok := config.Host("localhost:5001"); ok {
+		t.Fatal("Host() found an unconfigured host")
+	}
+
+	for _, data := range []string{
+		`{`,
+		`{"registries": {"": {}}}`,
+		`{"registries": {"localhost:5000": {"referrers": "index"}}}`,
+		`{"registries": {"localhost:5000": {"mirrors": [""]}}}`,
+		`{"registries": {"localhost:5000": {"mirrors": ["localhost:5000"]}}}`,
+	} {
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadRegistriesConfig(); err == nil {
+			t.Errorf("LoadRegistriesConfig() expects error for %s", data)
+		}
+	}
+
+	// the repositories fail with the error of the configuration
+	repo := NewRepository(newTestRemoteRepository(t, "http://localhost:5000"))
+	if _, err := repo.Resolve(context.Background(), "v1"); err == nil {
+		t.Fatal("Resolve() expects error for invalid registries configuration")
+	}
+}
+
+func TestNewRepositoryPlainHTTP(t *testing.T) {
+	var requests atomic.Int64
+	ts := httptest.NewServer(manifestHandler(&requests))
+	defer ts.Close()
+	ctx := context.Background()
+	dir.UserConfigDir = t.TempDir()
+
+	// HTTPS is used by default
+	repo := NewRepository(newTestRemoteRepository(t, ts.URL))
+	if _, err := repo.Resolve(ctx, "v1"); err == nil {
+		t.Fatal("Resolve() expects error for HTTPS request to plain HTTP server")
+	}
+
+	target := newTestRemoteRepository(t, ts.URL)
+	config := &RegistriesConfig{Registries: map[string]HostConfig{
+		target.Reference.Host(): {PlainHTTP: true, Referrers: ReferrersTagSchema},
+	}}
+	repo = NewRepositoryWithOptions(target, RepositoryOptions{RegistriesConfig: config})
+	desc, err := repo.Resolve(ctx, "v1")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if desc.Digest != digest.FromBytes(testManifest) {
+		t.Fatalf("Resolve() = %v", desc)
+	}
+	if !target.PlainHTTP {
+		t.Fatal("PlainHTTP is not set")
+	}
+	if err := target.SetReferrersCapability(true); err == nil {
+		t.Fatal("referrers capability is not set")
+	}
+}
+
+func TestNewRepositoryCABundle(t *testing.T) {
+	var requests atomic.Int64
+	ts := httptest.NewTLSServer(manifestHandler(&requests))
+	defer ts.Close()
+	ctx := context.Background()
+	dir.UserConfigDir = t.TempDir()
+
+	// the certificate of the server is not trusted by default
+	repo := NewRepository(newTestRemoteRepository(t, ts.URL))
+	if _, err := repo.Resolve(ctx, "v1"); err == nil {
+		t.Fatal("Resolve() expects error for untrusted server certificate")
+	}
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	target := newTestRemoteRepository(t, ts.URL)
+	config := &RegistriesConfig{Registries: map[string]HostConfig{
+		target.Reference.Host(): {CABundle: caBundle},
+	}}
+	repo = NewRepositoryWithOptions(target, RepositoryOptions{RegistriesConfig: config})
+	if _, err := repo.Resolve(ctx, "v1"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	config.Registries[target.Reference.Host()] = HostConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")}
+	repo = NewRepositoryWithOptions(newTestRemoteRepository(t, ts.URL), RepositoryOptions{RegistriesConfig: config})
+	if _, err := repo.Resolve(ctx, "v1"); err == nil {
+		t.Fatal("Resolve() expects error for missing CA bundle")
+	}
+}
+
+func TestNewRepositoryInsecureOptions(t *testing.T) {
+	var requests atomic.Int64
+	plainServer := httptest.NewServer(manifestHandler(&requests))
+	defer plainServer.Close()
+	tlsServer := httptest.NewTLSServer(manifestHandler(&requests))
+	defer tlsServer.Close()
+	ctx := context.Background()
+	dir.UserConfigDir = t.TempDir()
+
+	tests := []struct {
+		name      string
+		serverURL string
+		opts      RepositoryOptions
+		warning   string
+	}{
+		{
+			name:      "plain HTTP",
+			serverURL: plainServer.URL,
+			opts:      RepositoryOptions{PlainHTTP: true},
+			warning:   "plain HTTP",
+		},
+		{
+			name:      "insecure skip TLS verify",
+			serverURL: tlsServer.URL,
+			opts:      RepositoryOptions{InsecureSkipTLSVerify: true},
+			warning:   "TLS certificate verification",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewRepositoryWithOptions(newTestRemoteRepository(t, tt.serverURL), tt.opts)
+			if _, err := repo.Resolve(ctx, "v1"); err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			warnings := repo.(interface{ TransportWarnings() []string }).TransportWarnings()
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.warning) {
+				t.Fatalf("TransportWarnings() = %v, want a warning on %s", warnings, tt.warning)
+			}
+			if warnings := (Wrapper{Repository: repo}).TransportWarnings(); len(warnings) != 1 {
+				t.Fatalf("Wrapper.TransportWarnings() = %v, want 1 warning", warnings)
+			}
+		})
+	}
+
+	repo := NewRepository(newTestRemoteRepository(t, tlsServer.URL))
+	if warnings := repo.(interface{ TransportWarnings() []string }).TransportWarnings(); len(warnings) != 0 {
+		t.Fatalf("TransportWarnings() = %v, want no warning", warnings)
+	}
+}
+
+func TestNewRepositoryMirrors(t *testing.T) {
+	var upstreamRequests, mirrorRequests, failingRequests atomic.Int64
+	upstream := httptest.NewServer(manifestHandler(&upstreamRequests))
+	defer upstream.Close()
+	mirror := httptest.NewServer(manifestHandler(&mirrorRequests))
+	defer mirror.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failingRequests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer failing.Close()
+	ctx := context.Background()
+
+	target := newTestRemoteRepository(t, upstream.URL)
+	mirrorHost := newTestRemoteRepository(t, mirror.URL).Reference.Host()
+	failingHost := newTestRemoteRepository(t, failing.URL).Reference.Host()
+	config := &RegistriesConfig{Registries: map[string]HostConfig{
+		target.Reference.Host(): {PlainHTTP: true, Mirrors: []string{failingHost, mirrorHost}},
+		mirrorHost:              {PlainHTTP: true},
+		failingHost:             {PlainHTTP: true},
+	}}
+	repo := NewRepositoryWithOptions(target, RepositoryOptions{RegistriesConfig: config})
+	if _, err := repo.Resolve(ctx, "v1"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if failingRequests.Load() == 0 || mirrorRequests.Load() == 0 || upstreamRequests.Load() != 0 {
+		t.Fatalf("requests: failing mirror %d, mirror %d, upstream %d", failingRequests.Load(), mirrorRequests.Load(), upstreamRequests.Load())
+	}
+
+	// the upstream registry is used if no mirror serves the artifact
+	if _, err := repo.Resolve(ctx, "v2"); err == nil {
+		t.Fatal("Resolve() expects error for unknown tag")
+	}
+	if upstreamRequests.Load() == 0 {
+		t.Fatal("upstream registry is not used")
+	}
+}
+
+func TestNewRepositoryNetwork(t *testing.T) {
+	var requests atomic.Int64
+	ts := httptest.NewServer(manifestHandler(&requests))
+	defer ts.Close()
+	ctx := context.Background()
+	dir.UserConfigDir = t.TempDir()
+
+	repo := NewRepositoryWithOptions(newTestRemoteRepository(t, ts.URL), RepositoryOptions{
+		PlainHTTP: true,
+		Network:   &network.Config{ConnectTimeout: 5 * time.Second, DisableFallback: true},
+	})
+	if _, err := repo.Resolve(ctx, "v1"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// the host names are resolved by the resolver of the network config
+	var dials atomic.Int64
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			dials.Add(1)
+			return nil, errors.New("no nameserver")
+		},
+	}
+	target, err := remote.NewRepository("registry.notation.invalid/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo = NewRepositoryWithOptions(target, RepositoryOptions{
+		PlainHTTP: true,
+		Network:   &network.Config{Resolver: resolver},
+	})
+	if _, err := repo.Resolve(ctx, "v1"); err == nil {
+		t.Fatal("Resolve() expects error for unresolved host")
+	}
+	if dials.Load() == 0 {
+		t.Fatal("Resolve() did not use the resolver of the network config")
+	}
+
+	repo = NewRepositoryWithOptions(newTestRemoteRepository(t, ts.URL), RepositoryOptions{
+		PlainHTTP: true,
+		Network:   &network.Config{Resolver: resolver, Nameservers: []string{"10.0.0.53:53"}},
+	})
+	if _, err := repo.Resolve(ctx, "v1"); err == nil {
+		t.Fatal("Resolve() expects error for invalid network config")
+	}
+}
+
+func TestNewRepositoryProxy(t *testing.T) {
+	var requests atomic.Int64
+	// the proxy serves the manifest of the requested registry
+	proxyServer := httptest.NewServer(manifestHandler(&requests))
+	defer proxyServer.Close()
+	ctx := context.Background()
+	dir.UserConfigDir = t.TempDir()
+
+	newTarget := func() *remote.Repository {
+		target, err := remote.NewRepository("registry.notation.invalid/test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return target
+	}
+	proxy := &network.Proxy{URL: proxyServer.URL}
+	repo := NewRepositoryWithOptions(newTarget(), RepositoryOptions{
+		PlainHTTP: true,
+		Network: &network.Config{
+			Proxy:            &network.Proxy{URL: "http://127.0.0.1:1"},
+			ComponentProxies: map[network.Component]*network.Proxy{network.ComponentRegistry: proxy},
+		},
+	})
+	if _, err := repo.Resolve(ctx, "v1"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if requests.Load() == 0 {
+		t.Fatal("the proxy of the registry component is not used")
+	}
+
+	// the proxy of another component does not apply
+	requests.Store(0)
+	repo = NewRepositoryWithOptions(newTarget(), RepositoryOptions{
+		PlainHTTP: true,
+		Network: &network.Config{
+			Proxy:            &network.Proxy{},
+			ComponentProxies: map[network.Component]*network.Proxy{network.ComponentRevocation: proxy},
+		},
+	})
+	if _, err := repo.Resolve(ctx, "v1"); err == nil {
+		t.Fatal("Resolve() expects error for unresolved host")
+	}
+	if requests.Load() != 0 {
+		t.Fatal("the proxy of the revocation component is used")
+	}
+}
+
+func TestNewRepositoryDump(t *testing.T) {
+	var requests atomic.Int64
+	ts := httptest.NewServer(manifestHandler(&requests))
+	defer ts.Close()
+	dir.UserConfigDir = t.TempDir()
+
+	var buf bytes.Buffer
+	ctx := dump.WithDumper(context.Background(), dump.NewDumper(&buf))
+	repo := NewRepositoryWithOptions(newTestRemoteRepository(t, ts.URL), RepositoryOptions{
+		PlainHTTP: true,
+		Network:   &network.Config{},
+	})
+	if _, err := repo.Resolve(ctx, "v1"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	var record dump.Record
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid record %q: %v", buf.String(), err)
+	}
+	if record.Component != string(network.ComponentRegistry) || record.Request == nil || !strings.HasSuffix(record.Request.URL, "/v2/test/manifests/v1") || record.Response == nil || record.Response.StatusCode != http.StatusOK {
+		t.Fatalf("record = %+v", record)
+	}
+}
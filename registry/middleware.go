@@ -0,0 +1,226 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/notaryproject/notation-go/log"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// Middleware wraps a [Repository] to add cross-cutting behavior, such as
+// logging, metrics or caching, to its calls.
+type Middleware func(Repository) Repository
+
+// Wrap wraps repo with the middlewares. The first middleware is the
+// outermost one, i.e. it intercepts the calls first.
+func Wrap(repo Repository, middlewares ...Middleware) Repository {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		repo = middlewares[i](repo)
+	}
+	return repo
+}
+
+// Wrapper is a pass-through [Repository] forwarding the calls to the wrapped
+// Repository. Middlewares embed it and override the methods they intercept.
+//
+// Besides the methods of [Repository], Wrapper forwards the optional methods
+// implemented by the repositories returned by [NewRepository]:
+// FetchSignatureBlobDescriptor, FetchSignatureSubject, DescribeArtifact,
+// DeleteSignature and Tags. If the
+// wrapped Repository does not implement one of them, the method returns an
+// error wrapping errdef.ErrUnsupported. TransportWarnings is forwarded as
+// well, and returns no warning if not implemented.
+type Wrapper struct {
+	Repository
+}
+
+// Unwrap returns the wrapped Repository.
+func (w Wrapper) Unwrap() Repository {
+	return w.Repository
+}
+
+// FetchSignatureBlobDescriptor returns the descriptor of the signature
+// envelope blob of the signature manifest desc.
+func (w Wrapper) FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	describer, ok := w.Repository.(interface {
+		FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error)
+	})
+	if !ok {
+		return ocispec.Descriptor{}, fmt.Errorf("describing signature envelope blobs: %w", errdef.ErrUnsupported)
+	}
+	return describer.FetchSignatureBlobDescriptor(ctx, desc)
+}
+
+// TransportWarnings returns the warnings of the insecure transport used to
+// access the wrapped Repository, if any.
+func (w Wrapper) TransportWarnings() []string {
+	if warner, ok := w.Repository.(interface{ TransportWarnings() []string }); ok {
+		return warner.TransportWarnings()
+	}
+	return nil
+}
+
+// FetchSignatureSubject returns the subject descriptor of the signature
+// manifest desc.
+func (w Wrapper) FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	fetcher, ok := w.Repository.(interface {
+		FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error)
+	})
+	if !ok {
+		return ocispec.Descriptor{}, fmt.Errorf("fetching signature subjects: %w", errdef.ErrUnsupported)
+	}
+	return fetcher.FetchSignatureSubject(ctx, desc)
+}
+
+// DescribeArtifact returns the manifest descriptor desc with the artifact
+// type and the annotations of the manifest.
+func (w Wrapper) DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	describer, ok := w.Repository.(interface {
+		DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error)
+	})
+	if !ok {
+		return ocispec.Descriptor{}, fmt.Errorf("describing artifacts: %w", errdef.ErrUnsupported)
+	}
+	return describer.DescribeArtifact(ctx, desc)
+}
+
+// DeleteSignature deletes the signature manifest desc.
+func (w Wrapper) DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error {
+	deleter, ok := w.Repository.(interface {
+		DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error
+	})
+	if !ok {
+		return fmt.Errorf("deleting signatures: %w", errdef.ErrUnsupported)
+	}
+	return deleter.DeleteSignature(ctx, desc)
+}
+
+// Tags lists the tags of the repository in lexical order.
+func (w Wrapper) Tags(ctx context.Context, last string, fn func(tags []string) error) error {
+	lister, ok := w.Repository.(interface {
+		Tags(ctx context.Context, last string, fn func(tags []string) error) error
+	})
+	if !ok {
+		return fmt.Errorf("listing tags: %w", errdef.ErrUnsupported)
+	}
+	return lister.Tags(ctx, last, fn)
+}
+
+// LoggingMiddleware returns a [Middleware] logging the calls to the
+// repository, with their duration and error, at debug level with the logger
+// in the context.
+func LoggingMiddleware() Middleware {
+	return func(repo Repository) Repository {
+		return &observedRepository{
+			Wrapper: Wrapper{Repository: repo},
+			observe: func(ctx context.Context, method string, duration time.Duration, err error) {
+				logger := log.GetLogger(ctx)
+				if err != nil {
+					logger.Debugf("Repository %s failed after %v: %v", method, duration, err)
+					return
+				}
+				logger.Debugf("Repository %s took %v", method, duration)
+			},
+		}
+	}
+}
+
+// MetricsRecorder records the metrics of the calls to a repository. It is
+// implemented by users, e.g. to export the metrics to Prometheus.
+// RecordCall is called synchronously and must not block.
+type MetricsRecorder interface {
+	// RecordCall records a call to the method of the repository, e.g.
+	// "FetchSignatureBlob", with its duration and error, if any.
+	RecordCall(ctx context.Context, method string, duration time.Duration, err error)
+}
+
+// MetricsRecorderFunc is an adapter to allow the use of ordinary functions
+// as [MetricsRecorder].
+type MetricsRecorderFunc func(ctx context.Context, method string, duration time.Duration, err error)
+
+// RecordCall calls f(ctx, method, duration, err).
+func (f MetricsRecorderFunc) RecordCall(ctx context.Context, method string, duration time.Duration, err error) {
+	f(ctx, method, duration, err)
+}
+
+// MetricsMiddleware returns a [Middleware] recording the calls to the
+// repository with recorder.
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(repo Repository) Repository {
+		return &observedRepository{
+			Wrapper: Wrapper{Repository: repo},
+			observe: recorder.RecordCall,
+		}
+	}
+}
+
+// observedRepository calls observe after each call to the wrapped
+// repository.
+type observedRepository struct {
+	Wrapper
+	observe func(ctx context.Context, method string, duration time.Duration, err error)
+}
+
+func (r *observedRepository) Resolve(ctx context.Context, reference string) (desc ocispec.Descriptor, err error) {
+	start := time.Now()
+	defer func() { r.observe(ctx, "Resolve", time.Since(start), err) }()
+	return r.Wrapper.Resolve(ctx, reference)
+}
+
+func (r *observedRepository) ListSignatures(ctx context.Context, desc ocispec.Descriptor, fn func(signatureManifests []ocispec.Descriptor) error) (err error) {
+	start := time.Now()
+	defer func() { r.observe(ctx, "ListSignatures", time.Since(start), err) }()
+	return r.Wrapper.ListSignatures(ctx, desc, fn)
+}
+
+func (r *observedRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) (blob []byte, blobDesc ocispec.Descriptor, err error) {
+	start := time.Now()
+	defer func() { r.observe(ctx, "FetchSignatureBlob", time.Since(start), err) }()
+	return r.Wrapper.FetchSignatureBlob(ctx, desc)
+}
+
+func (r *observedRepository) PushSignature(ctx context.Context, mediaType string, blob []byte, subject ocispec.Descriptor, annotations map[string]string) (blobDesc, manifestDesc ocispec.Descriptor, err error) {
+	start := time.Now()
+	defer func() { r.observe(ctx, "PushSignature", time.Since(start), err) }()
+	return r.Wrapper.PushSignature(ctx, mediaType, blob, subject, annotations)
+}
+
+func (r *observedRepository) FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (blobDesc ocispec.Descriptor, err error) {
+	start := time.Now()
+	defer func() { r.observe(ctx, "FetchSignatureBlobDescriptor", time.Since(start), err) }()
+	return r.Wrapper.FetchSignatureBlobDescriptor(ctx, desc)
+}
+
+func (r *observedRepository) FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (subject ocispec.Descriptor, err error) {
+	start := time.Now()
+	defer func() { r.observe(ctx, "FetchSignatureSubject", time.Since(start), err) }()
+	return r.Wrapper.FetchSignatureSubject(ctx, desc)
+}
+
+func (r *observedRepository) DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (artifactDesc ocispec.Descriptor, err error) {
+	start := time.Now()
+	defer func() { r.observe(ctx, "DescribeArtifact", time.Since(start), err) }()
+	return r.Wrapper.DescribeArtifact(ctx, desc)
+}
+
+func (r *observedRepository) DeleteSignature(ctx context.Context, desc ocispec.Descriptor) (err error) {
+	start := time.Now()
+	defer func() { r.observe(ctx, "DeleteSignature", time.Since(start), err) }()
+	return r.Wrapper.DeleteSignature(ctx, desc)
+}
@@ -0,0 +1,148 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// countingRepository counts the calls to the mock repository.
+type countingRepository struct {
+	mock.Repository
+	calls map[string]int
+}
+
+func newCountingRepository() *countingRepository {
+	return &countingRepository{
+		Repository: mock.NewRepository(),
+		calls:      make(map[string]int),
+	}
+}
+
+func (r *countingRepository) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	r.calls["Resolve"]++
+	return r.Repository.Resolve(ctx, reference)
+}
+
+func (r *countingRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	r.calls["FetchSignatureBlob"]++
+	return r.Repository.FetchSignatureBlob(ctx, desc)
+}
+
+func (r *countingRepository) DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error {
+	r.calls["DeleteSignature"]++
+	return nil
+}
+
+func TestWrap(t *testing.T) {
+	var order []string
+	tracing := func(name string) Middleware {
+		return MetricsMiddleware(MetricsRecorderFunc(func(ctx context.Context, method string, duration time.Duration, err error) {
+			order = append(order, name+":"+method)
+		}))
+	}
+	repo := Wrap(newCountingRepository(), tracing("outer"), tracing("inner"))
+	if _, err := repo.Resolve(context.Background(), "latest"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	// the outer middleware observes the call last
+	if want := []string{"inner:Resolve", "outer:Resolve"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("middlewares called in order %v, want %v", order, want)
+	}
+	if Wrap(mock.NewRepository()) == nil {
+		t.Fatal("Wrap() without middlewares returned nil")
+	}
+}
+
+func TestWrapper(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepository()
+	w := Wrapper{Repository: inner}
+	if w.Unwrap() != Repository(inner) {
+		t.Fatal("Unwrap() did not return the wrapped repository")
+	}
+	if err := w.DeleteSignature(ctx, mock.SigManfiestDescriptor); err != nil || inner.calls["DeleteSignature"] != 1 {
+		t.Fatalf("DeleteSignature() was not forwarded: %v", err)
+	}
+	if _, err := w.DescribeArtifact(ctx, mock.ImageDescriptor); !errors.Is(err, errdef.ErrUnsupported) {
+		t.Fatalf("DescribeArtifact() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := w.FetchSignatureBlobDescriptor(ctx, mock.SigManfiestDescriptor); !errors.Is(err, errdef.ErrUnsupported) {
+		t.Fatalf("FetchSignatureBlobDescriptor() error = %v, want ErrUnsupported", err)
+	}
+	if err := w.Tags(ctx, "", func([]string) error { return nil }); !errors.Is(err, errdef.ErrUnsupported) {
+		t.Fatalf("Tags() error = %v, want ErrUnsupported", err)
+	}
+	if err := (Wrapper{Repository: mock.NewRepository()}).DeleteSignature(ctx, mock.SigManfiestDescriptor); !errors.Is(err, errdef.ErrUnsupported) {
+		t.Fatalf("DeleteSignature() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	type call struct {
+		method string
+		err    error
+	}
+	var calls []call
+	recorder := MetricsRecorderFunc(func(ctx context.Context, method string, duration time.Duration, err error) {
+		calls = append(calls, call{method, err})
+	})
+	inner := mock.NewRepository()
+	inner.FetchSignatureBlobError = errors.New("fetch failed")
+	repo := Wrap(inner, MetricsMiddleware(recorder))
+
+	ctx := context.Background()
+	repo.Resolve(ctx, "latest")
+	repo.ListSignatures(ctx, mock.ImageDescriptor, func([]ocispec.Descriptor) error { return nil })
+	repo.FetchSignatureBlob(ctx, mock.SigManfiestDescriptor)
+	repo.PushSignature(ctx, mock.JwsSigEnvDescriptor.MediaType, nil, mock.ImageDescriptor, nil)
+	want := []call{
+		{"Resolve", nil},
+		{"ListSignatures", nil},
+		{"FetchSignatureBlob", inner.FetchSignatureBlobError},
+		{"PushSignature", nil},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("recorded calls %v, want %v", calls, want)
+	}
+
+	// the optional methods are observed as well
+	calls = nil
+	repo.(interface {
+		DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error)
+	}).DescribeArtifact(ctx, mock.ImageDescriptor)
+	if len(calls) != 1 || calls[0].method != "DescribeArtifact" || !errors.Is(calls[0].err, errdef.ErrUnsupported) {
+		t.Fatalf("recorded calls %v, want DescribeArtifact unsupported", calls)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	inner := mock.NewRepository()
+	inner.ResolveError = errors.New("resolve failed")
+	repo := Wrap(inner, LoggingMiddleware())
+	if _, err := repo.Resolve(context.Background(), "latest"); !errors.Is(err, inner.ResolveError) {
+		t.Fatalf("Resolve() error = %v, want %v", err, inner.ResolveError)
+	}
+	if _, _, err := repo.FetchSignatureBlob(context.Background(), mock.SigManfiestDescriptor); err != nil {
+		t.Fatalf("FetchSignatureBlob() error = %v", err)
+	}
+}
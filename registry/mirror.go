@@ -0,0 +1,99 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+
+	"github.com/notaryproject/notation-go/log"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// mirroredRepository reads the artifacts and their signatures from the first
+// mirror able to serve them, and from the upstream Repository otherwise. The
+// other calls are forwarded to the upstream Repository.
+type mirroredRepository struct {
+	Wrapper
+	mirrors []Repository
+}
+
+// withMirrors returns upstream reading from the mirrors first.
+func withMirrors(upstream Repository, mirrors []Repository) Repository {
+	if len(mirrors) == 0 {
+		return upstream
+	}
+	return &mirroredRepository{
+		Wrapper: Wrapper{Repository: upstream},
+		mirrors: mirrors,
+	}
+}
+
+// Resolve resolves the reference with the first mirror able to, or with the
+// upstream repository.
+func (r *mirroredRepository) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	for i, mirror := range r.mirrors {
+		desc, err := mirror.Resolve(ctx, reference)
+		if err == nil || ctx.Err() != nil {
+			return desc, err
+		}
+		log.GetLogger(ctx).Debugf("Failed to resolve %s with mirror %d: %v", reference, i, err)
+	}
+	return r.Repository.Resolve(ctx, reference)
+}
+
+// ListSignatures lists the signatures with the first mirror able to, or with
+// the upstream repository. A mirror failing after listing a page of
+// signatures is not fallen back from.
+func (r *mirroredRepository) ListSignatures(ctx context.Context, desc ocispec.Descriptor, fn func(signatureManifests []ocispec.Descriptor) error) error {
+	for i, mirror := range r.mirrors {
+		var listed bool
+		err := mirror.ListSignatures(ctx, desc, func(signatureManifests []ocispec.Descriptor) error {
+			listed = true
+			return fn(signatureManifests)
+		})
+		if err == nil || listed || ctx.Err() != nil {
+			return err
+		}
+		log.GetLogger(ctx).Debugf("Failed to list the signatures of %s with mirror %d: %v", desc.Digest, i, err)
+	}
+	return r.Repository.ListSignatures(ctx, desc, fn)
+}
+
+// FetchSignatureBlob fetches the signature envelope blob with the first
+// mirror able to, or with the upstream repository.
+func (r *mirroredRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	for i, mirror := range r.mirrors {
+		blob, blobDesc, err := mirror.FetchSignatureBlob(ctx, desc)
+		if err == nil {
+			return blob, blobDesc, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ocispec.Descriptor{}, err
+		}
+		log.GetLogger(ctx).Debugf("Failed to fetch the signature %s with mirror %d: %v", desc.Digest, i, err)
+	}
+	return r.Repository.FetchSignatureBlob(ctx, desc)
+}
+
+// TransportWarnings returns the warnings of the insecure transport used to
+// access the upstream repository and the mirrors.
+func (r *mirroredRepository) TransportWarnings() []string {
+	warnings := r.Wrapper.TransportWarnings()
+	for _, mirror := range r.mirrors {
+		if warner, ok := mirror.(interface{ TransportWarnings() []string }); ok {
+			warnings = append(warnings, warner.TransportWarnings()...)
+		}
+	}
+	return warnings
+}
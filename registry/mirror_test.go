@@ -0,0 +1,89 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestMirroredRepository(t *testing.T) {
+	ctx := context.Background()
+	failing := newCountingRepository()
+	failing.ResolveError = errors.New("resolve failed")
+	failing.FetchSignatureBlobError = errors.New("fetch failed")
+	mirror := newCountingRepository()
+	upstream := newCountingRepository()
+	repo := withMirrors(upstream, []Repository{failing, mirror})
+
+	if _, err := repo.Resolve(ctx, "v1"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if _, _, err := repo.FetchSignatureBlob(ctx, mock.SigManfiestDescriptor); err != nil {
+		t.Fatalf("FetchSignatureBlob() error = %v", err)
+	}
+	if failing.calls["Resolve"] != 1 || mirror.calls["Resolve"] != 1 || upstream.calls["Resolve"] != 0 {
+		t.Fatalf("Resolve() calls: failing %d, mirror %d, upstream %d", failing.calls["Resolve"], mirror.calls["Resolve"], upstream.calls["Resolve"])
+	}
+	if mirror.calls["FetchSignatureBlob"] != 1 || upstream.calls["FetchSignatureBlob"] != 0 {
+		t.Fatalf("FetchSignatureBlob() calls: mirror %d, upstream %d", mirror.calls["FetchSignatureBlob"], upstream.calls["FetchSignatureBlob"])
+	}
+
+	// the listing falls back to the upstream repository
+	repo = withMirrors(upstream, []Repository{unlistableRepository{failing}})
+	var listed int
+	if err := repo.ListSignatures(ctx, mock.ImageDescriptor, func(signatureManifests []ocispec.Descriptor) error {
+		listed += len(signatureManifests)
+		return nil
+	}); err != nil {
+		t.Fatalf("ListSignatures() error = %v", err)
+	}
+	if listed == 0 {
+		t.Fatal("ListSignatures() listed no signature")
+	}
+
+	// the errors of fn are not fallen back from
+	errStop := errors.New("stop")
+	repo = withMirrors(upstream, []Repository{mirror})
+	if err := repo.ListSignatures(ctx, mock.ImageDescriptor, func([]ocispec.Descriptor) error {
+		return errStop
+	}); !errors.Is(err, errStop) {
+		t.Fatalf("ListSignatures() error = %v, want %v", err, errStop)
+	}
+
+	// writes go to the upstream repository
+	if err := repo.(*mirroredRepository).DeleteSignature(ctx, mock.SigManfiestDescriptor); err != nil {
+		t.Fatalf("DeleteSignature() error = %v", err)
+	}
+	if mirror.calls["DeleteSignature"] != 0 || upstream.calls["DeleteSignature"] != 1 {
+		t.Fatal("DeleteSignature() is not forwarded to the upstream repository")
+	}
+
+	if withMirrors(upstream, nil) != Repository(upstream) {
+		t.Fatal("withMirrors() wraps the repository without mirrors")
+	}
+}
+
+// unlistableRepository fails to list signatures before listing any page.
+type unlistableRepository struct {
+	Repository
+}
+
+func (unlistableRepository) ListSignatures(context.Context, ocispec.Descriptor, func([]ocispec.Descriptor) error) error {
+	return errors.New("list failed")
+}
@@ -0,0 +1,150 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// RateLimiter spaces the calls to a registry evenly to a maximum rate. A
+// RateLimiter is safe for concurrent use, and is shared by the repositories
+// of a registry host to rate limit the host.
+type RateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewRateLimiter returns a [RateLimiter] allowing requestsPerSecond calls per
+// second, or nil if requestsPerSecond is not positive. A nil RateLimiter does
+// not limit the calls.
+func NewRateLimiter(requestsPerSecond float64) *RateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// Wait blocks until the next call is allowed, or returns the error of ctx if
+// it is done before.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RateLimitMiddleware returns a [Middleware] waiting for limiter before each
+// call to the repository. A paginated call, e.g. ListSignatures, waits once.
+func RateLimitMiddleware(limiter *RateLimiter) Middleware {
+	return func(repo Repository) Repository {
+		return &rateLimitedRepository{
+			Wrapper: Wrapper{Repository: repo},
+			limiter: limiter,
+		}
+	}
+}
+
+// rateLimitedRepository waits for limiter before each call to the wrapped
+// repository.
+type rateLimitedRepository struct {
+	Wrapper
+	limiter *RateLimiter
+}
+
+func (r *rateLimitedRepository) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return r.Wrapper.Resolve(ctx, reference)
+}
+
+func (r *rateLimitedRepository) ListSignatures(ctx context.Context, desc ocispec.Descriptor, fn func(signatureManifests []ocispec.Descriptor) error) error {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return r.Wrapper.ListSignatures(ctx, desc, fn)
+}
+
+func (r *rateLimitedRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, ocispec.Descriptor{}, err
+	}
+	return r.Wrapper.FetchSignatureBlob(ctx, desc)
+}
+
+func (r *rateLimitedRepository) PushSignature(ctx context.Context, mediaType string, blob []byte, subject ocispec.Descriptor, annotations map[string]string) (blobDesc, manifestDesc ocispec.Descriptor, err error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+	return r.Wrapper.PushSignature(ctx, mediaType, blob, subject, annotations)
+}
+
+func (r *rateLimitedRepository) FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return r.Wrapper.FetchSignatureBlobDescriptor(ctx, desc)
+}
+
+func (r *rateLimitedRepository) FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return r.Wrapper.FetchSignatureSubject(ctx, desc)
+}
+
+func (r *rateLimitedRepository) DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return r.Wrapper.DescribeArtifact(ctx, desc)
+}
+
+func (r *rateLimitedRepository) DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return r.Wrapper.DeleteSignature(ctx, desc)
+}
+
+func (r *rateLimitedRepository) Tags(ctx context.Context, last string, fn func(tags []string) error) error {
+	if err := r.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return r.Wrapper.Tags(ctx, last, fn)
+}
@@ -0,0 +1,78 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+)
+
+func TestRateLimiter(t *testing.T) {
+	if NewRateLimiter(0) != nil {
+		t.Fatal("NewRateLimiter(0) != nil, want nil")
+	}
+	var unlimited *RateLimiter
+	if err := unlimited.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	limiter := NewRateLimiter(100)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	// the first call is not delayed
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Fatalf("4 calls took %v, want at least 30ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewRateLimiter(0.001).Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	inner := newCountingRepository()
+	repo := Wrap(inner, RateLimitMiddleware(NewRateLimiter(1000)))
+	ctx := context.Background()
+	if _, err := repo.Resolve(ctx, "v1"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if _, _, err := repo.FetchSignatureBlob(ctx, mock.SigManfiestDescriptor); err != nil {
+		t.Fatalf("FetchSignatureBlob() error = %v", err)
+	}
+	if inner.calls["Resolve"] != 1 || inner.calls["FetchSignatureBlob"] != 1 {
+		t.Fatalf("calls = %v, want one Resolve and one FetchSignatureBlob", inner.calls)
+	}
+
+	// the calls are not forwarded once the context is done
+	limited := Wrap(inner, RateLimitMiddleware(NewRateLimiter(0.001)))
+	limited.Resolve(ctx, "v1")
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := limited.Resolve(cancelled, "v1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Resolve() error = %v, want context.Canceled", err)
+	}
+	if inner.calls["Resolve"] != 2 {
+		t.Fatalf("Resolve() was called %d times, want 2", inner.calls["Resolve"])
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/notaryproject/notation-go/network"
 	"github.com/notaryproject/notation-go/registry/internal/artifactspec"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2"
@@ -28,6 +29,7 @@ import (
 	"oras.land/oras-go/v2/content/oci"
 	"oras.land/oras-go/v2/errdef"
 	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
 )
 
 const (
@@ -49,32 +51,129 @@ var (
 )
 
 // RepositoryOptions provides user options when creating a [Repository]
-// it is kept for future extensibility
-type RepositoryOptions struct{}
+type RepositoryOptions struct {
+	// MaxSignatureBlobSize is the maximum size in bytes of a signature
+	// envelope blob fetched from the repository. Larger blobs are rejected
+	// before being fetched. If set to less than or equals to zero, the
+	// default limit of 32 MiB is used.
+	MaxSignatureBlobSize int64
+
+	// RegistriesConfig is the per-host registries configuration applied to
+	// a remote repository target. If nil, the configuration is loaded from
+	// the registries.json file of the config directory, if any.
+	RegistriesConfig *RegistriesConfig
+
+	// PlainHTTP accesses a remote repository target with plain HTTP instead
+	// of HTTPS, e.g. for a local test registry. The traffic is not encrypted
+	// and the registry is not authenticated, so a warning is reported in the
+	// verification outcomes.
+	PlainHTTP bool
+
+	// InsecureSkipTLSVerify skips the verification of the TLS certificate of
+	// a remote repository target, e.g. for a local test registry with a
+	// self-signed certificate. The registry is not authenticated, so a
+	// warning is reported in the verification outcomes.
+	InsecureSkipTLSVerify bool
+
+	// Network configures the name resolution, the dialing and the proxy of
+	// the connections to a remote repository target and its mirrors, with
+	// the proxy of [network.ComponentRegistry], if any. If nil, the
+	// transport of the target client is used as is.
+	Network *network.Config
+
+	// Endpoint is the endpoint serving a remote repository target instead
+	// of its registry host, overriding the endpoint of the registries
+	// configuration. See [HostConfig.Endpoint]. The mirrors of the registry
+	// host keep their endpoints.
+	Endpoint string
+}
 
 // repositoryClient implements [Repository]
 type repositoryClient struct {
 	oras.GraphTarget
 	RepositoryOptions
+
+	// warnings are the warnings of the insecure transport used to access
+	// the repository.
+	warnings []string
 }
 
 // NewRepository returns a new [Repository].
 // Known implementations of oras.GraphTarget:
 // - [remote.Repository](https://pkg.go.dev/oras.land/oras-go/v2/registry/remote#Repository)
 // - [oci.Store](https://pkg.go.dev/oras.land/oras-go/v2/content/oci#Store)
+//
+// The configuration of the registry host of a remote.Repository target in
+// the registries.json file of the config directory is applied to target. See
+// [RegistriesConfig].
 func NewRepository(target oras.GraphTarget) Repository {
-	return &repositoryClient{
-		GraphTarget: target,
-	}
+	return NewRepositoryWithOptions(target, RepositoryOptions{})
 }
 
 // NewRepositoryWithOptions returns a new [Repository] with user specified
 // options.
+//
+// The configuration of the registry host of a remote.Repository target in
+// opts.RegistriesConfig, or in the registries.json file of the config
+// directory, is applied to target. If the configuration cannot be loaded or
+// applied, the methods of the returned Repository fail with the error.
 func NewRepositoryWithOptions(target oras.GraphTarget, opts RepositoryOptions) Repository {
-	return &repositoryClient{
+	remoteRepo, ok := target.(*remote.Repository)
+	if !ok {
+		return &repositoryClient{
+			GraphTarget:       target,
+			RepositoryOptions: opts,
+		}
+	}
+	repo, err := newRemoteRepository(remoteRepo, opts)
+	if err != nil {
+		return failedRepository{err: err}
+	}
+	return repo
+}
+
+// newRemoteRepository returns a new [Repository] of the remote repository
+// with the configuration of its registry host applied.
+func newRemoteRepository(target *remote.Repository, opts RepositoryOptions) (Repository, error) {
+	config := opts.RegistriesConfig
+	if config == nil {
+		var err error
+		config, err = LoadRegistriesConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+	netConfig := opts.Network.ForComponent(network.ComponentRegistry)
+	warnings, err := config.configure(target, netConfig, opts.Endpoint, opts.PlainHTTP, opts.InsecureSkipTLSVerify)
+	if err != nil {
+		return nil, err
+	}
+	upstream := &repositoryClient{
 		GraphTarget:       target,
 		RepositoryOptions: opts,
+		warnings:          warnings,
 	}
+	hostConfig, _ := config.Host(target.Reference.Host())
+	var mirrors []Repository
+	for _, mirror := range hostConfig.Mirrors {
+		mirrorRepo := &remote.Repository{
+			Client: target.Client,
+			Reference: registry.Reference{
+				Registry:   mirror,
+				Repository: target.Reference.Repository,
+			},
+		}
+		mirrorWarnings, err := config.configure(mirrorRepo, netConfig, "", false, false)
+		if err != nil {
+			return nil, err
+		}
+		mirrors = append(mirrors, &repositoryClient{
+			GraphTarget:       mirrorRepo,
+			RepositoryOptions: opts,
+			warnings:          mirrorWarnings,
+		})
+	}
+	return withMirrors(upstream, mirrors), nil
 }
 
 // NewOCIRepository returns a new [Repository] with oci.Store as
@@ -94,28 +193,48 @@ func NewOCIRepository(path string, opts RepositoryOptions) (Repository, error) {
 	return NewRepositoryWithOptions(ociStore, opts), nil
 }
 
+// TransportWarnings returns the warnings of the insecure transport used to
+// access the repository, e.g. plain HTTP.
+func (c *repositoryClient) TransportWarnings() []string {
+	return c.warnings
+}
+
 // Resolve resolves a reference(tag or digest) to a manifest descriptor
 func (c *repositoryClient) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	var desc ocispec.Descriptor
+	var err error
 	if repo, ok := c.GraphTarget.(registry.Repository); ok {
-		return repo.Manifests().Resolve(ctx, reference)
+		desc, err = repo.Manifests().Resolve(ctx, reference)
+	} else {
+		desc, err = c.GraphTarget.Resolve(ctx, reference)
 	}
-	return c.GraphTarget.Resolve(ctx, reference)
+	return desc, wrapError(err)
 }
 
 // ListSignatures returns signature manifests filtered by fn given the
 // target artifact's manifest descriptor
 func (c *repositoryClient) ListSignatures(ctx context.Context, desc ocispec.Descriptor, fn func(signatureManifests []ocispec.Descriptor) error) error {
 	if repo, ok := c.GraphTarget.(registry.ReferrerLister); ok {
-		return repo.Referrers(ctx, desc, ArtifactTypeNotation, fn)
+		return wrapReferrersError(repo.Referrers(ctx, desc, ArtifactTypeNotation, fn))
 	}
 
 	signatureManifests, err := signatureReferrers(ctx, c.GraphTarget, desc)
 	if err != nil {
-		return fmt.Errorf("failed to get referrers during ListSignatures due to %w", err)
+		return wrapError(fmt.Errorf("failed to get referrers during ListSignatures due to %w", err))
 	}
 	return fn(signatureManifests)
 }
 
+// Tags lists the tags of the repository in lexical order, calling fn with
+// each page of tags. If last is not empty, the tags start after last.
+func (c *repositoryClient) Tags(ctx context.Context, last string, fn func(tags []string) error) error {
+	lister, ok := c.GraphTarget.(registry.TagLister)
+	if !ok {
+		return fmt.Errorf("listing tags: %w", errdef.ErrUnsupported)
+	}
+	return wrapError(lister.Tags(ctx, last, fn))
+}
+
 // FetchSignatureBlob returns signature envelope blob and descriptor given
 // signature manifest descriptor
 func (c *repositoryClient) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
@@ -123,8 +242,8 @@ func (c *repositoryClient) FetchSignatureBlob(ctx context.Context, desc ocispec.
 	if err != nil {
 		return nil, ocispec.Descriptor{}, err
 	}
-	if sigBlobDesc.Size > maxBlobSizeLimit {
-		return nil, ocispec.Descriptor{}, fmt.Errorf("signature blob too large: %d bytes", sigBlobDesc.Size)
+	if maxSize := c.maxSignatureBlobSize(); sigBlobDesc.Size > maxSize {
+		return nil, ocispec.Descriptor{}, fmt.Errorf("signature blob too large: %d bytes exceeds the limit of %d bytes", sigBlobDesc.Size, maxSize)
 	}
 
 	var fetcher content.Fetcher = c.GraphTarget
@@ -133,11 +252,66 @@ func (c *repositoryClient) FetchSignatureBlob(ctx context.Context, desc ocispec.
 	}
 	sigBlob, err := content.FetchAll(ctx, fetcher, sigBlobDesc)
 	if err != nil {
-		return nil, ocispec.Descriptor{}, err
+		return nil, ocispec.Descriptor{}, wrapError(err)
 	}
 	return sigBlob, sigBlobDesc, nil
 }
 
+// maxSignatureBlobSize returns the maximum size of a signature envelope blob.
+func (c *repositoryClient) maxSignatureBlobSize() int64 {
+	if c.MaxSignatureBlobSize > 0 {
+		return c.MaxSignatureBlobSize
+	}
+	return maxBlobSizeLimit
+}
+
+// FetchSignatureBlobDescriptor returns the descriptor of the signature
+// envelope blob given signature manifest descriptor, without fetching the
+// blob.
+func (c *repositoryClient) FetchSignatureBlobDescriptor(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return c.getSignatureBlobDesc(ctx, desc)
+}
+
+// DescribeArtifact returns the manifest descriptor desc of an artifact with
+// the artifact type and the annotations of the manifest.
+func (c *repositoryClient) DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex, artifactspec.MediaTypeArtifactManifest:
+	default:
+		// not a manifest known to carry an artifact type and annotations
+		return desc, nil
+	}
+	if desc.Size > maxManifestSizeLimit {
+		return ocispec.Descriptor{}, fmt.Errorf("manifest too large: %d bytes", desc.Size)
+	}
+	var fetcher content.Fetcher = c.GraphTarget
+	if repo, ok := c.GraphTarget.(registry.Repository); ok {
+		fetcher = repo.Manifests()
+	}
+	manifestJSON, err := content.FetchAll(ctx, fetcher, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, wrapError(err)
+	}
+	var manifest struct {
+		ArtifactType string              `json:"artifactType,omitempty"`
+		Config       *ocispec.Descriptor `json:"config,omitempty"`
+		Annotations  map[string]string   `json:"annotations,omitempty"`
+	}
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	described := desc
+	described.ArtifactType = manifest.ArtifactType
+	if described.ArtifactType == "" && desc.MediaType == ocispec.MediaTypeImageManifest && manifest.Config != nil {
+		// the artifact type of an image manifest defaults to the media type
+		// of its config
+		described.ArtifactType = manifest.Config.MediaType
+	}
+	described.Annotations = manifest.Annotations
+	return described, nil
+}
+
 // PushSignature creates and uploads an signature manifest along with its
 // linked signature envelope blob. Upon successful, PushSignature returns
 // signature envelope blob and manifest descriptors.
@@ -148,23 +322,67 @@ func (c *repositoryClient) PushSignature(ctx context.Context, mediaType string,
 	}
 	blobDesc, err = oras.PushBytes(ctx, pusher, mediaType, blob)
 	if err != nil {
-		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, wrapError(err)
 	}
 	manifestDesc, err = c.uploadSignatureManifest(ctx, subject, blobDesc, annotations)
 	if err != nil {
-		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, wrapError(err)
 	}
 	return blobDesc, manifestDesc, nil
 }
 
+// DeleteSignature deletes the signature manifest desc. The signature envelope
+// blob is left to the garbage collection of the registry.
+func (c *repositoryClient) DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error {
+	if desc.MediaType != artifactspec.MediaTypeArtifactManifest && desc.MediaType != ocispec.MediaTypeImageManifest {
+		return fmt.Errorf("desc.MediaType requires %q or %q, got %q", artifactspec.MediaTypeArtifactManifest, ocispec.MediaTypeImageManifest, desc.MediaType)
+	}
+	switch target := c.GraphTarget.(type) {
+	case registry.Repository:
+		return wrapError(target.Manifests().Delete(ctx, desc))
+	case content.Deleter:
+		return wrapError(target.Delete(ctx, desc))
+	default:
+		return fmt.Errorf("deleting signatures is not supported by the repository: %w", errdef.ErrUnsupported)
+	}
+}
+
 // getSignatureBlobDesc returns signature blob descriptor from
 // signature manifest blobs or layers given signature manifest descriptor
 func (c *repositoryClient) getSignatureBlobDesc(ctx context.Context, sigManifestDesc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	signatureBlobs, _, err := c.fetchSignatureManifest(ctx, sigManifestDesc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if len(signatureBlobs) != 1 {
+		return ocispec.Descriptor{}, fmt.Errorf("signature manifest requries exactly one signature envelope blob, got %d", len(signatureBlobs))
+	}
+
+	return signatureBlobs[0], nil
+}
+
+// FetchSignatureSubject returns the subject descriptor of the signature
+// manifest desc, i.e. the descriptor of the signed artifact as recorded in
+// the signature manifest.
+func (c *repositoryClient) FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	_, subject, err := c.fetchSignatureManifest(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if subject == nil {
+		return ocispec.Descriptor{}, fmt.Errorf("signature manifest %s has no subject", desc.Digest)
+	}
+	return *subject, nil
+}
+
+// fetchSignatureManifest returns the signature blob descriptors and the
+// subject of the signature manifest sigManifestDesc.
+func (c *repositoryClient) fetchSignatureManifest(ctx context.Context, sigManifestDesc ocispec.Descriptor) ([]ocispec.Descriptor, *ocispec.Descriptor, error) {
 	if sigManifestDesc.MediaType != artifactspec.MediaTypeArtifactManifest && sigManifestDesc.MediaType != ocispec.MediaTypeImageManifest {
-		return ocispec.Descriptor{}, fmt.Errorf("sigManifestDesc.MediaType requires %q or %q, got %q", artifactspec.MediaTypeArtifactManifest, ocispec.MediaTypeImageManifest, sigManifestDesc.MediaType)
+		return nil, nil, fmt.Errorf("sigManifestDesc.MediaType requires %q or %q, got %q", artifactspec.MediaTypeArtifactManifest, ocispec.MediaTypeImageManifest, sigManifestDesc.MediaType)
 	}
 	if sigManifestDesc.Size > maxManifestSizeLimit {
-		return ocispec.Descriptor{}, fmt.Errorf("signature manifest too large: %d bytes", sigManifestDesc.Size)
+		return nil, nil, fmt.Errorf("signature manifest too large: %d bytes", sigManifestDesc.Size)
 	}
 
 	// get the signature manifest from sigManifestDesc
@@ -174,31 +392,24 @@ func (c *repositoryClient) getSignatureBlobDesc(ctx context.Context, sigManifest
 	}
 	manifestJSON, err := content.FetchAll(ctx, fetcher, sigManifestDesc)
 	if err != nil {
-		return ocispec.Descriptor{}, err
+		return nil, nil, wrapError(err)
 	}
 
-	// get the signature blob descriptor from signature manifest
-	var signatureBlobs []ocispec.Descriptor
+	// get the signature blob descriptors from signature manifest
 	// OCI image manifest
 	if sigManifestDesc.MediaType == ocispec.MediaTypeImageManifest {
 		var sigManifest ocispec.Manifest
 		if err := json.Unmarshal(manifestJSON, &sigManifest); err != nil {
-			return ocispec.Descriptor{}, err
+			return nil, nil, err
 		}
-		signatureBlobs = sigManifest.Layers
-	} else { // OCI artifact manifest
-		var sigManifest artifactspec.Artifact
-		if err := json.Unmarshal(manifestJSON, &sigManifest); err != nil {
-			return ocispec.Descriptor{}, err
-		}
-		signatureBlobs = sigManifest.Blobs
+		return sigManifest.Layers, sigManifest.Subject, nil
 	}
-
-	if len(signatureBlobs) != 1 {
-		return ocispec.Descriptor{}, fmt.Errorf("signature manifest requries exactly one signature envelope blob, got %d", len(signatureBlobs))
+	// OCI artifact manifest
+	var sigManifest artifactspec.Artifact
+	if err := json.Unmarshal(manifestJSON, &sigManifest); err != nil {
+		return nil, nil, err
 	}
-
-	return signatureBlobs[0], nil
+	return sigManifest.Blobs, sigManifest.Subject, nil
 }
 
 // uploadSignatureManifest uploads the signature manifest to the registry
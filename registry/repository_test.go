@@ -16,6 +16,8 @@ package registry
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -32,10 +34,13 @@ import (
 	"github.com/notaryproject/notation-go/internal/slices"
 	"github.com/notaryproject/notation-go/registry/internal/artifactspec"
 	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/memory"
 	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
 	"oras.land/oras-go/v2/registry"
 	"oras.land/oras-go/v2/registry/remote"
 )
@@ -553,6 +558,13 @@ func TestOciLayoutRepositoryPushAndFetch(t *testing.T) {
 				if !content.Equal(expectedSignatureBlobDesc, sigDesc) {
 					return fmt.Errorf("expected to get signature blob desc: %v, got: %v", expectedSignatureBlobDesc, sigDesc)
 				}
+				sigDesc, err = repo.(*repositoryClient).FetchSignatureBlobDescriptor(context.Background(), sigManifestDesc)
+				if err != nil {
+					return fmt.Errorf("failed to fetch blob descriptor: %w", err)
+				}
+				if !content.Equal(expectedSignatureBlobDesc, sigDesc) {
+					return fmt.Errorf("expected to get signature blob desc: %v, got: %v", expectedSignatureBlobDesc, sigDesc)
+				}
 				found = true
 			}
 			if !found {
@@ -566,6 +578,215 @@ func TestOciLayoutRepositoryPushAndFetch(t *testing.T) {
 	})
 }
 
+func TestDescribeArtifact(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	repo := NewRepository(store).(*repositoryClient)
+
+	pushManifest := func(t *testing.T, manifest interface{}, mediaType string) ocispec.Descriptor {
+		t.Helper()
+		manifestJSON, err := json.Marshal(manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		desc := content.NewDescriptorFromBytes(mediaType, manifestJSON)
+		if err := store.Push(ctx, desc, bytes.NewReader(manifestJSON)); err != nil {
+			t.Fatal(err)
+		}
+		return desc
+	}
+	annotations := map[string]string{"org.example.pipeline": "release"}
+
+	t.Run("artifact type", func(t *testing.T) {
+		desc := pushManifest(t, ocispec.Manifest{
+			Versioned:    specs.Versioned{SchemaVersion: 2},
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: "application/vnd.example.sbom",
+			Config:       ocispec.DescriptorEmptyJSON,
+			Layers:       []ocispec.Descriptor{ocispec.DescriptorEmptyJSON},
+			Annotations:  annotations,
+		}, ocispec.MediaTypeImageManifest)
+		got, err := repo.DescribeArtifact(ctx, desc)
+		if err != nil {
+			t.Fatalf("DescribeArtifact() error = %v", err)
+		}
+		if !content.Equal(desc, got) || got.ArtifactType != "application/vnd.example.sbom" || !reflect.DeepEqual(annotations, got.Annotations) {
+			t.Fatalf("DescribeArtifact() = %+v", got)
+		}
+	})
+
+	t.Run("config media type", func(t *testing.T) {
+		config := ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageConfig,
+			Digest:    digest.FromString("config"),
+			Size:      6,
+		}
+		desc := pushManifest(t, ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    config,
+		}, ocispec.MediaTypeImageManifest)
+		got, err := repo.DescribeArtifact(ctx, desc)
+		if err != nil {
+			t.Fatalf("DescribeArtifact() error = %v", err)
+		}
+		if got.ArtifactType != ocispec.MediaTypeImageConfig || got.Annotations != nil {
+			t.Fatalf("DescribeArtifact() = %+v", got)
+		}
+	})
+
+	t.Run("not a manifest", func(t *testing.T) {
+		desc := ocispec.Descriptor{
+			MediaType: "application/octet-stream",
+			Digest:    digest.FromString("blob"),
+			Size:      4,
+		}
+		got, err := repo.DescribeArtifact(ctx, desc)
+		if err != nil {
+			t.Fatalf("DescribeArtifact() error = %v", err)
+		}
+		if !reflect.DeepEqual(desc, got) {
+			t.Fatalf("DescribeArtifact() = %+v, want %+v", got, desc)
+		}
+	})
+
+	t.Run("manifest not found", func(t *testing.T) {
+		desc := ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    digest.FromString("missing"),
+			Size:      7,
+		}
+		if _, err := repo.DescribeArtifact(ctx, desc); err == nil {
+			t.Fatal("DescribeArtifact() expects error, got nil")
+		}
+	})
+
+	t.Run("manifest too large", func(t *testing.T) {
+		desc := ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    digest.FromString("large"),
+			Size:      maxManifestSizeLimit + 1,
+		}
+		if _, err := repo.DescribeArtifact(ctx, desc); err == nil || !strings.Contains(err.Error(), "manifest too large") {
+			t.Fatalf("DescribeArtifact() error = %v, want manifest too large", err)
+		}
+	})
+}
+
+func TestDeleteSignature(t *testing.T) {
+	ctx := context.Background()
+	store, err := oci.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create oci.Store: %v", err)
+	}
+	repo := NewRepository(store).(*repositoryClient)
+	subject, err := oras.PushBytes(ctx, store, ocispec.MediaTypeImageManifest, []byte(`{"schemaVersion":2}`))
+	if err != nil {
+		t.Fatalf("failed to push subject: %v", err)
+	}
+	_, sigManifestDesc, err := repo.PushSignature(ctx, joseTag, []byte("signature"), subject, nil)
+	if err != nil {
+		t.Fatalf("failed to push signature: %v", err)
+	}
+
+	if err := repo.DeleteSignature(ctx, sigManifestDesc); err != nil {
+		t.Fatalf("DeleteSignature() error = %v", err)
+	}
+	exists, err := store.Exists(ctx, sigManifestDesc)
+	if err != nil {
+		t.Fatalf("Exists() error = %v", err)
+	}
+	if exists {
+		t.Fatal("signature manifest exists after DeleteSignature()")
+	}
+
+	t.Run("invalid media type", func(t *testing.T) {
+		desc := ocispec.Descriptor{
+			MediaType: joseTag,
+			Digest:    digest.FromString("signature"),
+		}
+		if err := repo.DeleteSignature(ctx, desc); err == nil {
+			t.Fatal("DeleteSignature() expects error, got nil")
+		}
+	})
+
+	t.Run("unsupported target", func(t *testing.T) {
+		repo := NewRepository(memory.New()).(*repositoryClient)
+		if err := repo.DeleteSignature(ctx, sigManifestDesc); !errors.Is(err, errdef.ErrUnsupported) {
+			t.Fatalf("DeleteSignature() error = %v, want %v", err, errdef.ErrUnsupported)
+		}
+	})
+}
+
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+	store, err := oci.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create oci.Store: %v", err)
+	}
+	desc, err := oras.PushBytes(ctx, store, ocispec.MediaTypeImageManifest, []byte(`{"schemaVersion":2}`))
+	if err != nil {
+		t.Fatalf("failed to push artifact: %v", err)
+	}
+	for _, tag := range []string{"v2", "v1", "latest"} {
+		if err := store.Tag(ctx, desc, tag); err != nil {
+			t.Fatalf("failed to tag artifact: %v", err)
+		}
+	}
+	repo := NewRepository(store).(*repositoryClient)
+	var tags []string
+	if err := repo.Tags(ctx, "latest", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	}); err != nil {
+		t.Fatalf("Tags() error = %v", err)
+	}
+	if want := []string{"v1", "v2"}; !reflect.DeepEqual(tags, want) {
+		t.Fatalf("Tags() = %v, want %v", tags, want)
+	}
+
+	t.Run("unsupported target", func(t *testing.T) {
+		repo := NewRepository(memory.New()).(*repositoryClient)
+		if err := repo.Tags(ctx, "", func([]string) error { return nil }); !errors.Is(err, errdef.ErrUnsupported) {
+			t.Fatalf("Tags() error = %v, want %v", err, errdef.ErrUnsupported)
+		}
+	})
+}
+
+func TestFetchSignatureSubject(t *testing.T) {
+	ctx := context.Background()
+	store, err := oci.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create oci.Store: %v", err)
+	}
+	repo := NewRepository(store).(*repositoryClient)
+	subject, err := oras.PushBytes(ctx, store, ocispec.MediaTypeImageManifest, []byte(`{"schemaVersion":2}`))
+	if err != nil {
+		t.Fatalf("failed to push subject: %v", err)
+	}
+	_, sigManifestDesc, err := repo.PushSignature(ctx, joseTag, []byte("signature"), subject, nil)
+	if err != nil {
+		t.Fatalf("failed to push signature: %v", err)
+	}
+
+	got, err := repo.FetchSignatureSubject(ctx, sigManifestDesc)
+	if err != nil {
+		t.Fatalf("FetchSignatureSubject() error = %v", err)
+	}
+	if got.MediaType != subject.MediaType || got.Digest != subject.Digest || got.Size != subject.Size {
+		t.Fatalf("FetchSignatureSubject() = %v, want %v", got, subject)
+	}
+
+	// a manifest without subject
+	manifestDesc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, joseTag, oras.PackManifestOptions{})
+	if err != nil {
+		t.Fatalf("failed to pack manifest: %v", err)
+	}
+	if _, err := repo.FetchSignatureSubject(ctx, manifestDesc); err == nil {
+		t.Fatal("FetchSignatureSubject() expects error for manifest without subject")
+	}
+}
+
 func TestNewRepository(t *testing.T) {
 	target, err := oci.New(t.TempDir())
 	if err != nil {
@@ -834,3 +1055,36 @@ func TestSignatureReferrers(t *testing.T) {
 		}
 	})
 }
+
+func TestFetchSignatureBlob_MaxSignatureBlobSize(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	subjectJSON, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers:    []ocispec.Descriptor{ocispec.DescriptorEmptyJSON},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, subjectJSON)
+	if err := store.Push(ctx, subject, bytes.NewReader(subjectJSON)); err != nil {
+		t.Fatal(err)
+	}
+	sigBlob := []byte("signature envelope")
+	_, sigManifestDesc, err := NewRepository(store).PushSignature(ctx, joseTag, sigBlob, subject, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewRepositoryWithOptions(store, RepositoryOptions{MaxSignatureBlobSize: int64(len(sigBlob))})
+	if got, _, err := repo.FetchSignatureBlob(ctx, sigManifestDesc); err != nil || !bytes.Equal(got, sigBlob) {
+		t.Fatalf("FetchSignatureBlob() = %q, %v, want %q", got, err, sigBlob)
+	}
+
+	repo = NewRepositoryWithOptions(store, RepositoryOptions{MaxSignatureBlobSize: int64(len(sigBlob) - 1)})
+	if _, _, err := repo.FetchSignatureBlob(ctx, sigManifestDesc); err == nil || !strings.Contains(err.Error(), "signature blob too large") {
+		t.Fatalf("FetchSignatureBlob() error = %v, want signature blob too large", err)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orasRegistry "oras.land/oras-go/v2/registry"
+)
+
+// RenewOptions contains parameters for [notation.Renew].
+type RenewOptions struct {
+	// SignerSignOptions are used to generate the renewed signatures.
+	SignerSignOptions
+
+	// ArtifactReference sets the full reference of the artifact whose
+	// signatures are renewed, e.g. "registry.io/repo:tag" or
+	// "registry.io/repo@sha256:...". The signatures are verified against the
+	// trust policy applicable to it.
+	ArtifactReference string
+
+	// VerificationPluginConfig is the plugin config used to verify the
+	// signatures before renewal.
+	VerificationPluginConfig map[string]string
+
+	// RenewBefore sets the period before the expiry of a signature in which
+	// the signature is renewed. Signatures without expiry are never renewed.
+	RenewBefore time.Duration
+
+	// MaxSignatureAttempts sets the maximum number of signatures inspected
+	// for the artifact. Must be a positive number.
+	MaxSignatureAttempts int
+
+	// DryRun reports the signatures to be renewed without signing them.
+	DryRun bool
+}
+
+// RenewedSignature describes a signature nearing its expiry and its renewal.
+type RenewedSignature struct {
+	// SignatureManifest is the descriptor of the signature manifest nearing
+	// its expiry.
+	SignatureManifest ocispec.Descriptor
+
+	// Expiry is the expiry of the signature.
+	Expiry time.Time
+
+	// UserMetadata is the user metadata carried over to the renewed
+	// signature.
+	UserMetadata map[string]string
+
+	// RenewedSignatureManifest is the descriptor of the signature manifest
+	// of the renewed signature. It is empty for a dry run.
+	RenewedSignatureManifest ocispec.Descriptor
+}
+
+// Renew finds the signatures of the artifact expiring within
+// renewOpts.RenewBefore and re-signs the artifact with signer for each of
+// them, preserving the user metadata of the signature payload and the
+// annotations of the signature manifest. The expiring signatures are not
+// deleted from the repository.
+//
+// Only the signatures passing verification with verifier are renewed, so
+// that Renew never re-signs an untrusted signature; signatures that already
+// expired fail verification and are not renewed. A signature is not renewed
+// either if a newer verified signature of the artifact carries the same user
+// metadata and does not expire within renewOpts.RenewBefore, e.g. the
+// signature was renewed by a previous run.
+//
+// The descriptor of the artifact and the renewed signatures are returned. On
+// error, the signatures renewed before the error are returned with it.
+func Renew(ctx context.Context, signer Signer, verifier Verifier, repo registry.Repository, renewOpts RenewOptions) (ocispec.Descriptor, []*RenewedSignature, error) {
+	// sanity check
+	if err := validateSignArguments(signer, renewOpts.SignerSignOptions); err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	if verifier == nil {
+		return ocispec.Descriptor{}, nil, errors.New("verifier cannot be nil")
+	}
+	if repo == nil {
+		return ocispec.Descriptor{}, nil, errors.New("repo cannot be nil")
+	}
+	if renewOpts.RenewBefore < 0 {
+		return ocispec.Descriptor{}, nil, errors.New("renew before duration cannot be a negative value")
+	}
+	if renewOpts.MaxSignatureAttempts <= 0 {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("renewOptions.MaxSignatureAttempts expects a positive number, got %d", renewOpts.MaxSignatureAttempts)
+	}
+
+	logger := log.GetLogger(ctx)
+	ref, err := orasRegistry.ParseReference(renewOpts.ArtifactReference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("renewOptions.ArtifactReference expects a full reference: %w", err)
+	}
+	if ref.Reference == "" {
+		return ocispec.Descriptor{}, nil, errors.New("renewOptions.ArtifactReference is missing digest or tag")
+	}
+	artifactManifestDesc, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to resolve reference: %w", err)
+	}
+	ref.Reference = artifactManifestDesc.Digest.String()
+	artifactRef := ref.String()
+
+	// find the verified signatures
+	var verified []*renewableSignature
+	numOfSignatureProcessed := 0
+	err = repo.ListSignatures(ctx, artifactManifestDesc, func(signatureManifests []ocispec.Descriptor) error {
+		for _, sigManifestDesc := range signatureManifests {
+			if numOfSignatureProcessed >= renewOpts.MaxSignatureAttempts {
+				return errDoneVerification
+			}
+			numOfSignatureProcessed++
+			sigBlob, sigDesc, err := repo.FetchSignatureBlob(ctx, sigManifestDesc)
+			if err != nil {
+				return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve digital signature with digest %q associated with %q from the Repository, error : %v", sigManifestDesc.Digest, artifactRef, err.Error()), InnerError: err}
+			}
+			outcome, err := verifier.Verify(ctx, artifactManifestDesc, sigBlob, VerifierVerifyOptions{
+				ArtifactReference:            artifactRef,
+				SignatureMediaType:           sigDesc.MediaType,
+				PluginConfig:                 renewOpts.VerificationPluginConfig,
+				SignatureManifestAnnotations: sigManifestDesc.Annotations,
+			})
+			if err != nil {
+				logger.Warnf("Skipping signature %v that failed verification: %v", sigManifestDesc.Digest, err)
+				continue
+			}
+			if outcome == nil || outcome.EnvelopeContent == nil {
+				logger.Warnf("Skipping signature %v whose verification was skipped", sigManifestDesc.Digest)
+				continue
+			}
+			expiry, userMetadata, err := parseRenewableSignature(outcome.EnvelopeContent, artifactManifestDesc)
+			if err != nil {
+				logger.Warnf("Skipping signature %v: %v", sigManifestDesc.Digest, err)
+				continue
+			}
+			verified = append(verified, &renewableSignature{
+				RenewedSignature: &RenewedSignature{
+					SignatureManifest: sigManifestDesc,
+					Expiry:            expiry,
+					UserMetadata:      userMetadata,
+				},
+				signingTime: outcome.EnvelopeContent.SignerInfo.SignedAttributes.SigningTime,
+			})
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDoneVerification) {
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	// renew the expiring signatures
+	renewBy := time.Now().Add(renewOpts.RenewBefore)
+	var renewed []*RenewedSignature
+	for _, sig := range verified {
+		if !sig.expiresBy(renewBy) {
+			continue
+		}
+		if renewal := findRenewal(verified, sig, renewBy); renewal != nil {
+			logger.Infof("Signature %v expiring at %v is already renewed by signature %v", sig.SignatureManifest.Digest, sig.Expiry, renewal.SignatureManifest.Digest)
+			continue
+		}
+		logger.Infof("Signature %v expires at %v and will be renewed", sig.SignatureManifest.Digest, sig.Expiry)
+		if !renewOpts.DryRun {
+			sigManifestDesc, err := renewSignature(ctx, signer, repo, artifactManifestDesc, sig.RenewedSignature, renewOpts.SignerSignOptions)
+			if err != nil {
+				return artifactManifestDesc, renewed, fmt.Errorf("failed to renew signature %v: %w", sig.SignatureManifest.Digest, err)
+			}
+			sig.RenewedSignatureManifest = sigManifestDesc
+		}
+		renewed = append(renewed, sig.RenewedSignature)
+
+		// the renewal covers the other signatures with the same user metadata
+		verified = append(verified, &renewableSignature{
+			RenewedSignature: &RenewedSignature{
+				SignatureManifest: sig.RenewedSignatureManifest,
+				UserMetadata:      sig.UserMetadata,
+			},
+			signingTime: time.Now(),
+		})
+	}
+	return artifactManifestDesc, renewed, nil
+}
+
+// renewableSignature is a verified signature of the artifact.
+type renewableSignature struct {
+	*RenewedSignature
+
+	// signingTime is the signing time of the signature.
+	signingTime time.Time
+}
+
+// expiresBy returns true if the signature expires by t.
+func (s *renewableSignature) expiresBy(t time.Time) bool {
+	return !s.Expiry.IsZero() && !s.Expiry.After(t)
+}
+
+// findRenewal returns a signature newer than sig with the same user metadata
+// and not expiring by renewBy, or nil if there is none.
+func findRenewal(signatures []*renewableSignature, sig *renewableSignature, renewBy time.Time) *renewableSignature {
+	for _, candidate := range signatures {
+		if candidate == sig || candidate.expiresBy(renewBy) || !candidate.signingTime.After(sig.signingTime) {
+			continue
+		}
+		if maps.Equal(candidate.UserMetadata, sig.UserMetadata) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// parseRenewableSignature returns the expiry and the user metadata of a
+// signature of the artifact from its verified envelope content.
+func parseRenewableSignature(content *signature.EnvelopeContent, artifactManifestDesc ocispec.Descriptor) (time.Time, map[string]string, error) {
+	var payload envelope.Payload
+	if err := json.Unmarshal(content.Payload.Content, &payload); err != nil {
+		return time.Time{}, nil, errors.New("failed to unmarshal the payload content in the signature blob to envelope.Payload")
+	}
+	if payload.TargetArtifact.Digest != artifactManifestDesc.Digest {
+		return time.Time{}, nil, fmt.Errorf("signature is not for artifact %v", artifactManifestDesc.Digest)
+	}
+
+	// the annotations of the artifact descriptor are signed along with the
+	// user metadata, so they are not carried over.
+	userMetadata := make(map[string]string)
+	for k, v := range payload.TargetArtifact.Annotations {
+		if _, ok := artifactManifestDesc.Annotations[k]; !ok {
+			userMetadata[k] = v
+		}
+	}
+	return content.SignerInfo.SignedAttributes.Expiry, userMetadata, nil
+}
+
+// renewSignature signs the artifact with the user metadata of sig and pushes
+// the signature with the annotations of the signature manifest of sig.
+func renewSignature(ctx context.Context, signer Signer, repo registry.Repository, artifactManifestDesc ocispec.Descriptor, sig *RenewedSignature, signOpts SignerSignOptions) (ocispec.Descriptor, error) {
+	// copy the annotations as the descriptor is reused for each signature
+	descToSign := artifactManifestDesc
+	if artifactManifestDesc.Annotations != nil {
+		descToSign.Annotations = make(map[string]string, len(artifactManifestDesc.Annotations))
+		for k, v := range artifactManifestDesc.Annotations {
+			descToSign.Annotations[k] = v
+		}
+	}
+	descToSign, err := addUserMetadataToDescriptor(ctx, descToSign, sig.UserMetadata)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	sigBlob, signerInfo, err := signer.Sign(ctx, descToSign, signOpts)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	// carry over the annotations of the signature manifest, except the ones
+	// generated for the new signature
+	annotations := make(map[string]string)
+	for k, v := range sig.SignatureManifest.Annotations {
+		if k != envelope.AnnotationX509ChainThumbprint && k != ocispec.AnnotationCreated {
+			annotations[k] = v
+		}
+	}
+	if signerAnts, ok := signer.(signerAnnotation); ok {
+		for k, v := range signerAnts.PluginAnnotations() {
+			annotations[k] = v
+		}
+	}
+	annotations, err = generateAnnotations(signerInfo, annotations)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	_, sigManifestDesc, err := repo.PushSignature(ctx, signOpts.SignatureMediaType, sigBlob, artifactManifestDesc, annotations)
+	if err != nil {
+		return ocispec.Descriptor{}, ErrorPushSignatureFailed{Msg: err.Error(), InnerError: err}
+	}
+	return sigManifestDesc, nil
+}
@@ -0,0 +1,348 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type renewRepository struct {
+	mock.Repository
+	pushedAnnotations []map[string]string
+}
+
+func (r *renewRepository) PushSignature(ctx context.Context, mediaType string, blob []byte, subject ocispec.Descriptor, annotations map[string]string) (blobDesc, manifestDesc ocispec.Descriptor, err error) {
+	if _, _, err := r.Repository.PushSignature(ctx, mediaType, blob, subject, annotations); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+	r.pushedAnnotations = append(r.pushedAnnotations, annotations)
+	return ocispec.Descriptor{}, ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: mock.ZeroDigest}, nil
+}
+
+// storingRepository lists and fetches the pushed signatures.
+type storingRepository struct {
+	mock.Repository
+	pushedManifests []ocispec.Descriptor
+	pushedBlobs     map[digest.Digest][]byte
+}
+
+func (r *storingRepository) ListSignatures(ctx context.Context, desc ocispec.Descriptor, fn func(signatureManifests []ocispec.Descriptor) error) error {
+	return fn(append(append([]ocispec.Descriptor(nil), r.ListSignaturesResponse...), r.pushedManifests...))
+}
+
+func (r *storingRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	if blob, ok := r.pushedBlobs[desc.Digest]; ok {
+		return blob, mock.JwsSigEnvDescriptor, nil
+	}
+	return r.Repository.FetchSignatureBlob(ctx, desc)
+}
+
+func (r *storingRepository) PushSignature(ctx context.Context, mediaType string, blob []byte, subject ocispec.Descriptor, annotations map[string]string) (blobDesc, manifestDesc ocispec.Descriptor, err error) {
+	manifestDesc = ocispec.Descriptor{
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Digest:      digest.FromBytes(blob),
+		Annotations: annotations,
+	}
+	if r.pushedBlobs == nil {
+		r.pushedBlobs = make(map[digest.Digest][]byte)
+	}
+	r.pushedBlobs[manifestDesc.Digest] = blob
+	r.pushedManifests = append(r.pushedManifests, manifestDesc)
+	return ocispec.Descriptor{}, manifestDesc, nil
+}
+
+// renewVerifier returns the content of the parsed envelopes, or the content
+// of the signatures generated by renewSigner.
+type renewVerifier struct {
+	failVerify bool
+	contents   map[string]*signature.EnvelopeContent
+}
+
+func (v *renewVerifier) Verify(_ context.Context, _ ocispec.Descriptor, sigBlob []byte, opts VerifierVerifyOptions) (*VerificationOutcome, error) {
+	outcome := &VerificationOutcome{
+		RawSignature:      sigBlob,
+		VerificationLevel: trustpolicy.LevelStrict,
+	}
+	if v.failVerify {
+		outcome.Error = errors.New("signature is not trusted")
+		return outcome, outcome.Error
+	}
+	if content, ok := v.contents[string(sigBlob)]; ok {
+		outcome.EnvelopeContent = content
+		return outcome, nil
+	}
+	sigEnv, err := signature.ParseEnvelope(opts.SignatureMediaType, sigBlob)
+	if err != nil {
+		outcome.Error = err
+		return outcome, err
+	}
+	outcome.EnvelopeContent, outcome.Error = sigEnv.Content()
+	return outcome, outcome.Error
+}
+
+type renewSigner struct {
+	signedDescs []ocispec.Descriptor
+
+	// verifier, if set, is able to verify the generated signatures.
+	verifier *renewVerifier
+}
+
+func (s *renewSigner) Sign(_ context.Context, desc ocispec.Descriptor, _ SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	s.signedDescs = append(s.signedDescs, desc)
+	signerInfo := &signature.SignerInfo{
+		SignedAttributes: signature.SignedAttributes{
+			SigningTime: time.Now(),
+			Expiry:      time.Now().Add(365 * 24 * time.Hour),
+		},
+	}
+	if s.verifier == nil {
+		return []byte("ABC"), signerInfo, nil
+	}
+	payload, err := json.Marshal(envelope.Payload{TargetArtifact: desc})
+	if err != nil {
+		return nil, nil, err
+	}
+	sigBlob := fmt.Sprintf("signature-%d", len(s.signedDescs))
+	if s.verifier.contents == nil {
+		s.verifier.contents = make(map[string]*signature.EnvelopeContent)
+	}
+	s.verifier.contents[sigBlob] = &signature.EnvelopeContent{
+		SignerInfo: *signerInfo,
+		Payload: signature.Payload{
+			ContentType: envelope.MediaTypePayloadV1,
+			Content:     payload,
+		},
+	}
+	return []byte(sigBlob), signerInfo, nil
+}
+
+func envelopeContent(t *testing.T, sigBlob []byte) *signature.EnvelopeContent {
+	t.Helper()
+	sigEnv, err := signature.ParseEnvelope("application/jose+json", sigBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := sigEnv.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestRenew(t *testing.T) {
+	renewOpts := RenewOptions{
+		SignerSignOptions: SignerSignOptions{
+			SignatureMediaType: "application/jose+json",
+		},
+		ArtifactReference:    mock.SampleArtifactUri,
+		RenewBefore:          30 * 24 * time.Hour,
+		MaxSignatureAttempts: 50,
+	}
+
+	t.Run("expiring signature", func(t *testing.T) {
+		repo := &renewRepository{Repository: mock.NewRepository()}
+		repo.FetchSignatureBlobResponse = mock.MockCaExpiredSigEnv
+		repo.ListSignaturesResponse = []ocispec.Descriptor{{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    mock.SampleDigest,
+			Annotations: map[string]string{
+				envelope.AnnotationX509ChainThumbprint: "[\"old\"]",
+				ocispec.AnnotationCreated:              "2022-07-28T23:59:00Z",
+				"io.wabbit-networks.buildId":           "123",
+			},
+		}}
+		signer := &renewSigner{}
+		artifactDesc, renewed, err := Renew(context.Background(), signer, &renewVerifier{}, repo, renewOpts)
+		if err != nil {
+			t.Fatalf("Renew() failed: %v", err)
+		}
+		if artifactDesc.Digest != mock.SampleDigest {
+			t.Fatalf("unexpected artifact descriptor %v", artifactDesc)
+		}
+		if len(renewed) != 1 {
+			t.Fatalf("expected 1 renewed signature, got %d", len(renewed))
+		}
+		if renewed[0].Expiry.IsZero() || renewed[0].RenewedSignatureManifest.Digest != mock.ZeroDigest {
+			t.Fatalf("unexpected renewed signature %+v", renewed[0])
+		}
+		if len(signer.signedDescs) != 1 || !reflect.DeepEqual(signer.signedDescs[0].Annotations, mock.Annotations) {
+			t.Fatalf("unexpected signed descriptors %+v", signer.signedDescs)
+		}
+		annotations := repo.pushedAnnotations[0]
+		if annotations["io.wabbit-networks.buildId"] != "123" {
+			t.Fatalf("signature manifest annotations should be preserved, got %v", annotations)
+		}
+		if annotations[envelope.AnnotationX509ChainThumbprint] == "[\"old\"]" || annotations[ocispec.AnnotationCreated] == "2022-07-28T23:59:00Z" {
+			t.Fatalf("generated annotations should be replaced, got %v", annotations)
+		}
+	})
+
+	t.Run("signature not expiring", func(t *testing.T) {
+		repo := &renewRepository{Repository: mock.NewRepository()}
+		signer := &renewSigner{}
+		_, renewed, err := Renew(context.Background(), signer, &renewVerifier{}, repo, renewOpts)
+		if err != nil {
+			t.Fatalf("Renew() failed: %v", err)
+		}
+		if len(renewed) != 0 || len(signer.signedDescs) != 0 {
+			t.Fatalf("expected no renewed signature, got %d", len(renewed))
+		}
+	})
+
+	t.Run("signature without expiry", func(t *testing.T) {
+		repo := &renewRepository{Repository: mock.NewRepository()}
+		repo.ResolveResponse = mock.MetadataSigEnvDescriptor
+		repo.FetchSignatureBlobResponse = mock.MockSigEnvWithMetadata
+		_, renewed, err := Renew(context.Background(), &renewSigner{}, &renewVerifier{}, repo, renewOpts)
+		if err != nil {
+			t.Fatalf("Renew() failed: %v", err)
+		}
+		if len(renewed) != 0 {
+			t.Fatalf("expected no renewed signature, got %d", len(renewed))
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		repo := &renewRepository{Repository: mock.NewRepository()}
+		repo.FetchSignatureBlobResponse = mock.MockCaExpiredSigEnv
+		signer := &renewSigner{}
+		opts := renewOpts
+		opts.DryRun = true
+		_, renewed, err := Renew(context.Background(), signer, &renewVerifier{}, repo, opts)
+		if err != nil {
+			t.Fatalf("Renew() failed: %v", err)
+		}
+		if len(renewed) != 1 || len(signer.signedDescs) != 0 || len(repo.pushedAnnotations) != 0 {
+			t.Fatalf("dry run should not sign, renewed %d, signed %d", len(renewed), len(signer.signedDescs))
+		}
+	})
+
+	t.Run("signature failing verification", func(t *testing.T) {
+		repo := &renewRepository{Repository: mock.NewRepository()}
+		repo.FetchSignatureBlobResponse = mock.MockCaExpiredSigEnv
+		signer := &renewSigner{}
+		_, renewed, err := Renew(context.Background(), signer, &renewVerifier{failVerify: true}, repo, renewOpts)
+		if err != nil {
+			t.Fatalf("Renew() failed: %v", err)
+		}
+		if len(renewed) != 0 || len(signer.signedDescs) != 0 || len(repo.pushedAnnotations) != 0 {
+			t.Fatalf("expected no renewed signature, got %d", len(renewed))
+		}
+	})
+
+	t.Run("renew twice", func(t *testing.T) {
+		repo := &storingRepository{Repository: mock.NewRepository()}
+		repo.FetchSignatureBlobResponse = mock.MockCaExpiredSigEnv
+		verifier := &renewVerifier{}
+		signer := &renewSigner{verifier: verifier}
+		for i := 0; i < 2; i++ {
+			if _, _, err := Renew(context.Background(), signer, verifier, repo, renewOpts); err != nil {
+				t.Fatalf("Renew() failed: %v", err)
+			}
+		}
+		if len(repo.pushedManifests) != 1 {
+			t.Fatalf("expected 1 pushed signature, got %d", len(repo.pushedManifests))
+		}
+
+		// expiring signatures with the same user metadata are renewed once
+		repo = &storingRepository{Repository: mock.NewRepository()}
+		repo.FetchSignatureBlobResponse = mock.MockCaExpiredSigEnv
+		repo.ListSignaturesResponse = []ocispec.Descriptor{mock.SigManfiestDescriptor, mock.SigManfiestDescriptor}
+		_, renewed, err := Renew(context.Background(), signer, verifier, repo, renewOpts)
+		if err != nil {
+			t.Fatalf("Renew() failed: %v", err)
+		}
+		if len(renewed) != 1 || len(repo.pushedManifests) != 1 {
+			t.Fatalf("expected 1 renewed signature, got %d", len(renewed))
+		}
+	})
+
+	t.Run("push failure", func(t *testing.T) {
+		repo := &renewRepository{Repository: mock.NewRepository()}
+		repo.FetchSignatureBlobResponse = mock.MockCaExpiredSigEnv
+		repo.PushSignatureError = errors.New("push error")
+		_, _, err := Renew(context.Background(), &renewSigner{}, &renewVerifier{}, repo, renewOpts)
+		var pushErr PushSignatureFailedError
+		if !errors.As(err, &pushErr) {
+			t.Fatalf("expected PushSignatureFailedError, got %v", err)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		repo := &renewRepository{Repository: mock.NewRepository()}
+		opts := renewOpts
+		opts.MaxSignatureAttempts = 0
+		if _, _, err := Renew(context.Background(), &renewSigner{}, &renewVerifier{}, repo, opts); err == nil {
+			t.Fatal("expected error for invalid MaxSignatureAttempts")
+		}
+		opts = renewOpts
+		opts.RenewBefore = -time.Hour
+		if _, _, err := Renew(context.Background(), &renewSigner{}, &renewVerifier{}, repo, opts); err == nil {
+			t.Fatal("expected error for negative RenewBefore")
+		}
+		if _, _, err := Renew(context.Background(), &renewSigner{}, &renewVerifier{}, nil, renewOpts); err == nil {
+			t.Fatal("expected error for nil repo")
+		}
+		if _, _, err := Renew(context.Background(), &renewSigner{}, nil, repo, renewOpts); err == nil {
+			t.Fatal("expected error for nil verifier")
+		}
+		opts = renewOpts
+		opts.ArtifactReference = mock.SampleDigest.String()
+		if _, _, err := Renew(context.Background(), &renewSigner{}, &renewVerifier{}, repo, opts); err == nil {
+			t.Fatal("expected error for artifact reference that is not a full reference")
+		}
+	})
+}
+
+func TestRenewPreservesUserMetadata(t *testing.T) {
+	// the signed user metadata is not part of the resolved descriptor
+	artifactDesc := mock.MetadataSigEnvDescriptor
+	artifactDesc.Annotations = map[string]string{"io.wabbit-networks.buildTime": "1672944615"}
+	_, userMetadata, err := parseRenewableSignature(envelopeContent(t, mock.MockSigEnvWithMetadata), artifactDesc)
+	if err != nil {
+		t.Fatalf("parseRenewableSignature() failed: %v", err)
+	}
+	wantMetadata := map[string]string{"io.wabbit-networks.buildId": "123"}
+	if !reflect.DeepEqual(userMetadata, wantMetadata) {
+		t.Fatalf("userMetadata = %v, want %v", userMetadata, wantMetadata)
+	}
+
+	signer := &renewSigner{}
+	repo := &renewRepository{Repository: mock.NewRepository()}
+	sig := &RenewedSignature{UserMetadata: userMetadata}
+	if _, err := renewSignature(context.Background(), signer, repo, artifactDesc, sig, SignerSignOptions{SignatureMediaType: "application/jose+json"}); err != nil {
+		t.Fatalf("renewSignature() failed: %v", err)
+	}
+	if !reflect.DeepEqual(signer.signedDescs[0].Annotations, mock.MetadataSigEnvDescriptor.Annotations) {
+		t.Fatalf("signed annotations = %v, want %v", signer.signedDescs[0].Annotations, mock.MetadataSigEnvDescriptor.Annotations)
+	}
+
+	// signature of another artifact
+	if _, _, err := parseRenewableSignature(envelopeContent(t, mock.MockSigEnvWithMetadata), mock.ImageDescriptor); err == nil {
+		t.Fatal("expected error for signature of another artifact")
+	}
+}
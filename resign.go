@@ -0,0 +1,222 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orasRegistry "oras.land/oras-go/v2/registry"
+)
+
+// ReSignOptions contains parameters for [notation.ReSign].
+type ReSignOptions struct {
+	// SignerSignOptions are used to generate the new signatures, e.g. with a
+	// fresh expiry and timestamp.
+	SignerSignOptions
+
+	// ArtifactReference sets the full reference of the artifact whose
+	// signatures are re-signed, e.g. "registry.io/repo:tag" or
+	// "registry.io/repo@sha256:...". The signatures are verified against the
+	// trust policy applicable to it.
+	ArtifactReference string
+
+	// OldThumbprint is the hex-encoded SHA-256 thumbprint of the signing
+	// certificate of the old key. Only the signatures of the old key are
+	// re-signed.
+	OldThumbprint string
+
+	// VerificationPluginConfig is the plugin config used to verify the
+	// signatures of the old key.
+	VerificationPluginConfig map[string]string
+
+	// ActionOverrides overrides the actions of the verification level of the
+	// trust policy when verifying the signatures of the old key. After a key
+	// compromise, the certificate of the old key is usually revoked, so that
+	// its signatures fail the revocation check unless the revocation action
+	// is overridden, e.g. to [trustpolicy.ActionLog]. Doing so re-signs the
+	// signatures forged with the compromised key as well: only override the
+	// action if the signatures of the artifact are known to be genuine.
+	ActionOverrides map[trustpolicy.ValidationType]trustpolicy.ValidationAction
+
+	// MaxSignatureAttempts sets the maximum number of signatures inspected
+	// for the artifact. Must be a positive number.
+	MaxSignatureAttempts int
+
+	// DeleteOld deletes the signatures of the old key once re-signed. The
+	// repository must support deleting signatures, see
+	// [registry.NewRepository].
+	DeleteOld bool
+
+	// DryRun reports the signatures to be re-signed without signing them.
+	DryRun bool
+}
+
+// ReSignedSignature describes a signature of the old key and its
+// replacement.
+type ReSignedSignature struct {
+	// SignatureManifest is the descriptor of the signature manifest of the
+	// old key.
+	SignatureManifest ocispec.Descriptor
+
+	// UserMetadata is the user metadata carried over to the new signature.
+	UserMetadata map[string]string
+
+	// ReSignedSignatureManifest is the descriptor of the signature manifest
+	// of the new signature. It is empty for a dry run.
+	ReSignedSignatureManifest ocispec.Descriptor
+
+	// Deleted is true if the signature of the old key is deleted.
+	Deleted bool
+}
+
+// ReSign re-signs the artifact with signer for each signature of the old key
+// identified by reSignOpts.OldThumbprint, preserving the user metadata of the
+// signature payload and the annotations of the signature manifest, e.g.
+// after the rotation or the compromise of the old key. The new signatures
+// get a fresh expiry and timestamp from reSignOpts.SignerSignOptions. The
+// signatures of the old key are deleted if reSignOpts.DeleteOld is set.
+//
+// Only the signatures of the old key passing verification with verifier are
+// re-signed, so that ReSign never re-signs an untrusted signature. See
+// [ReSignOptions].ActionOverrides to re-sign the signatures of a revoked
+// key.
+//
+// The descriptor of the artifact and the re-signed signatures are returned.
+// On error, the signatures re-signed before the error are returned with it.
+func ReSign(ctx context.Context, signer Signer, verifier Verifier, repo registry.Repository, reSignOpts ReSignOptions) (ocispec.Descriptor, []*ReSignedSignature, error) {
+	// sanity check
+	if err := validateSignArguments(signer, reSignOpts.SignerSignOptions); err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	if verifier == nil {
+		return ocispec.Descriptor{}, nil, errors.New("verifier cannot be nil")
+	}
+	if repo == nil {
+		return ocispec.Descriptor{}, nil, errors.New("repo cannot be nil")
+	}
+	oldThumbprint := strings.ToLower(reSignOpts.OldThumbprint)
+	if thumbprint, err := hex.DecodeString(oldThumbprint); err != nil || len(thumbprint) != sha256.Size {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("reSignOptions.OldThumbprint expects a hex-encoded SHA-256 thumbprint, got %q", reSignOpts.OldThumbprint)
+	}
+	if reSignOpts.MaxSignatureAttempts <= 0 {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("reSignOptions.MaxSignatureAttempts expects a positive number, got %d", reSignOpts.MaxSignatureAttempts)
+	}
+	var deleter signatureDeleter
+	if reSignOpts.DeleteOld {
+		var ok bool
+		if deleter, ok = repo.(signatureDeleter); !ok {
+			return ocispec.Descriptor{}, nil, errors.New("repository does not support deleting signatures")
+		}
+	}
+
+	logger := log.GetLogger(ctx)
+	ref, err := orasRegistry.ParseReference(reSignOpts.ArtifactReference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("reSignOptions.ArtifactReference expects a full reference: %w", err)
+	}
+	if ref.Reference == "" {
+		return ocispec.Descriptor{}, nil, errors.New("reSignOptions.ArtifactReference is missing digest or tag")
+	}
+	artifactManifestDesc, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to resolve reference: %w", err)
+	}
+	ref.Reference = artifactManifestDesc.Digest.String()
+	artifactRef := ref.String()
+
+	// find the verified signatures of the old key
+	var oldSignatures []*ReSignedSignature
+	numOfSignatureProcessed := 0
+	err = repo.ListSignatures(ctx, artifactManifestDesc, func(signatureManifests []ocispec.Descriptor) error {
+		for _, sigManifestDesc := range signatureManifests {
+			if numOfSignatureProcessed >= reSignOpts.MaxSignatureAttempts {
+				return errDoneVerification
+			}
+			numOfSignatureProcessed++
+			sigBlob, sigDesc, err := repo.FetchSignatureBlob(ctx, sigManifestDesc)
+			if err != nil {
+				return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve digital signature with digest %q associated with %q from the Repository, error : %v", sigManifestDesc.Digest, artifactRef, err.Error()), InnerError: err}
+			}
+			outcome, err := verifier.Verify(ctx, artifactManifestDesc, sigBlob, VerifierVerifyOptions{
+				ArtifactReference:            artifactRef,
+				SignatureMediaType:           sigDesc.MediaType,
+				PluginConfig:                 reSignOpts.VerificationPluginConfig,
+				SignatureManifestAnnotations: sigManifestDesc.Annotations,
+				ActionOverrides:              reSignOpts.ActionOverrides,
+			})
+			if err != nil {
+				logger.Warnf("Skipping signature %v that failed verification: %v", sigManifestDesc.Digest, err)
+				continue
+			}
+			if outcome == nil || outcome.EnvelopeContent == nil {
+				logger.Warnf("Skipping signature %v whose verification was skipped", sigManifestDesc.Digest)
+				continue
+			}
+			if len(outcome.EnvelopeContent.SignerInfo.CertificateChain) == 0 {
+				continue
+			}
+			thumbprint := sha256.Sum256(outcome.EnvelopeContent.SignerInfo.CertificateChain[0].Raw)
+			if hex.EncodeToString(thumbprint[:]) != oldThumbprint {
+				continue
+			}
+			_, userMetadata, err := parseRenewableSignature(outcome.EnvelopeContent, artifactManifestDesc)
+			if err != nil {
+				logger.Warnf("Skipping signature %v: %v", sigManifestDesc.Digest, err)
+				continue
+			}
+			oldSignatures = append(oldSignatures, &ReSignedSignature{
+				SignatureManifest: sigManifestDesc,
+				UserMetadata:      userMetadata,
+			})
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDoneVerification) {
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	// re-sign the signatures of the old key
+	var reSigned []*ReSignedSignature
+	for _, sig := range oldSignatures {
+		logger.Infof("Signature %v of the old key will be re-signed", sig.SignatureManifest.Digest)
+		if !reSignOpts.DryRun {
+			sigManifestDesc, err := renewSignature(ctx, signer, repo, artifactManifestDesc, &RenewedSignature{
+				SignatureManifest: sig.SignatureManifest,
+				UserMetadata:      sig.UserMetadata,
+			}, reSignOpts.SignerSignOptions)
+			if err != nil {
+				return artifactManifestDesc, reSigned, fmt.Errorf("failed to re-sign signature %v: %w", sig.SignatureManifest.Digest, err)
+			}
+			sig.ReSignedSignatureManifest = sigManifestDesc
+			if deleter != nil {
+				if err := deleter.DeleteSignature(ctx, sig.SignatureManifest); err != nil {
+					reSigned = append(reSigned, sig)
+					return artifactManifestDesc, reSigned, fmt.Errorf("failed to delete signature %v: %w", sig.SignatureManifest.Digest, err)
+				}
+				sig.Deleted = true
+			}
+		}
+		reSigned = append(reSigned, sig)
+	}
+	return artifactManifestDesc, reSigned, nil
+}
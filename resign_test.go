@@ -0,0 +1,199 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// deletingRepository records the deleted signatures.
+type deletingRepository struct {
+	renewRepository
+	deleted   []ocispec.Descriptor
+	deleteErr error
+}
+
+func (r *deletingRepository) DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error {
+	if r.deleteErr != nil {
+		return r.deleteErr
+	}
+	r.deleted = append(r.deleted, desc)
+	return nil
+}
+
+// overrideRecordingVerifier records the action overrides of the
+// verifications.
+type overrideRecordingVerifier struct {
+	renewVerifier
+	overrides []map[trustpolicy.ValidationType]trustpolicy.ValidationAction
+}
+
+func (v *overrideRecordingVerifier) Verify(ctx context.Context, desc ocispec.Descriptor, sigBlob []byte, opts VerifierVerifyOptions) (*VerificationOutcome, error) {
+	v.overrides = append(v.overrides, opts.ActionOverrides)
+	return v.renewVerifier.Verify(ctx, desc, sigBlob, opts)
+}
+
+func TestReSign(t *testing.T) {
+	leaf := envelopeContent(t, mock.MockCaValidSigEnv).SignerInfo.CertificateChain[0]
+	thumbprint := sha256.Sum256(leaf.Raw)
+	reSignOpts := ReSignOptions{
+		SignerSignOptions: SignerSignOptions{
+			SignatureMediaType: "application/jose+json",
+		},
+		ArtifactReference:    mock.SampleArtifactUri,
+		OldThumbprint:        hex.EncodeToString(thumbprint[:]),
+		MaxSignatureAttempts: 50,
+	}
+	sigManifestDesc := ocispec.Descriptor{
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Digest:      mock.SampleDigest,
+		Annotations: map[string]string{"io.wabbit-networks.buildId": "123"},
+	}
+	newRepo := func() *deletingRepository {
+		repo := &deletingRepository{renewRepository: renewRepository{Repository: mock.NewRepository()}}
+		repo.ListSignaturesResponse = []ocispec.Descriptor{sigManifestDesc}
+		return repo
+	}
+
+	t.Run("signature of the old key", func(t *testing.T) {
+		repo := newRepo()
+		signer := &renewSigner{}
+		verifier := &overrideRecordingVerifier{}
+		opts := reSignOpts
+		opts.DeleteOld = true
+		opts.ActionOverrides = map[trustpolicy.ValidationType]trustpolicy.ValidationAction{trustpolicy.TypeRevocation: trustpolicy.ActionLog}
+		artifactDesc, reSigned, err := ReSign(context.Background(), signer, verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("ReSign() failed: %v", err)
+		}
+		if artifactDesc.Digest != mock.SampleDigest {
+			t.Fatalf("unexpected artifact descriptor %v", artifactDesc)
+		}
+		if len(reSigned) != 1 || reSigned[0].ReSignedSignatureManifest.Digest != mock.ZeroDigest || !reSigned[0].Deleted {
+			t.Fatalf("unexpected re-signed signatures %+v", reSigned)
+		}
+		if len(signer.signedDescs) != 1 || !reflect.DeepEqual(signer.signedDescs[0].Annotations, mock.Annotations) {
+			t.Fatalf("unexpected signed descriptors %+v", signer.signedDescs)
+		}
+		if repo.pushedAnnotations[0]["io.wabbit-networks.buildId"] != "123" {
+			t.Fatalf("signature manifest annotations should be preserved, got %v", repo.pushedAnnotations[0])
+		}
+		if len(repo.deleted) != 1 || repo.deleted[0].Digest != sigManifestDesc.Digest {
+			t.Fatalf("deleted signatures = %v, want the old signature", repo.deleted)
+		}
+		if !reflect.DeepEqual(verifier.overrides[0], opts.ActionOverrides) {
+			t.Fatalf("verification action overrides = %v, want %v", verifier.overrides[0], opts.ActionOverrides)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		repo := newRepo()
+		signer := &renewSigner{}
+		opts := reSignOpts
+		opts.DryRun = true
+		opts.DeleteOld = true
+		_, reSigned, err := ReSign(context.Background(), signer, &renewVerifier{}, repo, opts)
+		if err != nil {
+			t.Fatalf("ReSign() failed: %v", err)
+		}
+		if len(reSigned) != 1 || len(signer.signedDescs) != 0 || len(repo.deleted) != 0 {
+			t.Fatalf("dry run re-signed or deleted signatures: %+v", reSigned)
+		}
+	})
+
+	t.Run("signature of another key", func(t *testing.T) {
+		repo := newRepo()
+		signer := &renewSigner{}
+		opts := reSignOpts
+		opts.OldThumbprint = hex.EncodeToString(make([]byte, sha256.Size))
+		_, reSigned, err := ReSign(context.Background(), signer, &renewVerifier{}, repo, opts)
+		if err != nil {
+			t.Fatalf("ReSign() failed: %v", err)
+		}
+		if len(reSigned) != 0 || len(signer.signedDescs) != 0 {
+			t.Fatalf("expected no re-signed signature, got %+v", reSigned)
+		}
+	})
+
+	t.Run("signature failing verification", func(t *testing.T) {
+		repo := newRepo()
+		signer := &renewSigner{}
+		_, reSigned, err := ReSign(context.Background(), signer, &renewVerifier{failVerify: true}, repo, reSignOpts)
+		if err != nil {
+			t.Fatalf("ReSign() failed: %v", err)
+		}
+		if len(reSigned) != 0 || len(signer.signedDescs) != 0 {
+			t.Fatalf("expected no re-signed signature, got %+v", reSigned)
+		}
+	})
+
+	t.Run("delete failure", func(t *testing.T) {
+		repo := newRepo()
+		repo.deleteErr = errors.New("delete failed")
+		opts := reSignOpts
+		opts.DeleteOld = true
+		_, reSigned, err := ReSign(context.Background(), &renewSigner{}, &renewVerifier{}, repo, opts)
+		if err == nil {
+			t.Fatal("ReSign() expects error for delete failure")
+		}
+		if len(reSigned) != 1 || reSigned[0].Deleted || reSigned[0].ReSignedSignatureManifest.Digest == "" {
+			t.Fatalf("expected the re-signed signature with the error, got %+v", reSigned)
+		}
+	})
+}
+
+func TestReSignError(t *testing.T) {
+	validOpts := ReSignOptions{
+		SignerSignOptions: SignerSignOptions{
+			SignatureMediaType: "application/jose+json",
+		},
+		ArtifactReference:    mock.SampleArtifactUri,
+		OldThumbprint:        hex.EncodeToString(make([]byte, sha256.Size)),
+		MaxSignatureAttempts: 50,
+	}
+	tests := []struct {
+		name   string
+		modify func(*ReSignOptions)
+	}{
+		{name: "invalid thumbprint", modify: func(o *ReSignOptions) { o.OldThumbprint = "old" }},
+		{name: "invalid max signature attempts", modify: func(o *ReSignOptions) { o.MaxSignatureAttempts = 0 }},
+		{name: "delete unsupported", modify: func(o *ReSignOptions) { o.DeleteOld = true }},
+		{name: "invalid reference", modify: func(o *ReSignOptions) { o.ArtifactReference = "invalid" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := validOpts
+			tt.modify(&opts)
+			repo := &renewRepository{Repository: mock.NewRepository()}
+			if _, _, err := ReSign(context.Background(), &renewSigner{}, &renewVerifier{}, repo, opts); err == nil {
+				t.Fatal("ReSign() expects error, got nil")
+			}
+		})
+	}
+	if _, _, err := ReSign(context.Background(), &renewSigner{}, nil, mock.NewRepository(), validOpts); err == nil {
+		t.Fatal("ReSign() expects error for nil verifier")
+	}
+	if _, _, err := ReSign(context.Background(), &renewSigner{}, &renewVerifier{}, nil, validOpts); err == nil {
+		t.Fatal("ReSign() expects error for nil repository")
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orasRegistry "oras.land/oras-go/v2/registry"
+)
+
+// PruneReason is the reason a signature is pruned by a [RetentionPolicy].
+type PruneReason string
+
+const (
+	// PruneReasonMaxAge prunes a signature older than
+	// [RetentionPolicy].MaxAge.
+	PruneReasonMaxAge PruneReason = "maxAge"
+
+	// PruneReasonMaxSignaturesPerKey prunes a signature exceeding
+	// [RetentionPolicy].MaxSignaturesPerKey.
+	PruneReasonMaxSignaturesPerKey PruneReason = "maxSignaturesPerKey"
+
+	// PruneReasonMaxSignatures prunes a signature exceeding
+	// [RetentionPolicy].MaxSignatures.
+	PruneReasonMaxSignatures PruneReason = "maxSignatures"
+
+	// PruneReasonUnverified prunes a signature failing verification. See
+	// [RetentionOptions].PruneUnverified.
+	PruneReasonUnverified PruneReason = "unverified"
+)
+
+// RetentionPolicy sets the signatures retained for an artifact. The newest
+// signatures, by signing time, are retained first. A zero value disables the
+// corresponding limit.
+type RetentionPolicy struct {
+	// MaxSignatures sets the maximum number of signatures retained for the
+	// artifact.
+	MaxSignatures int
+
+	// MaxAge sets the maximum age of the retained signatures, from their
+	// signing time.
+	MaxAge time.Duration
+
+	// MaxSignaturesPerKey sets the maximum number of signatures retained for
+	// the artifact per signing certificate.
+	MaxSignaturesPerKey int
+}
+
+// Validate checks the policy for negative limits.
+func (p RetentionPolicy) Validate() error {
+	if p.MaxSignatures < 0 {
+		return fmt.Errorf("retention policy MaxSignatures cannot be a negative value, got %d", p.MaxSignatures)
+	}
+	if p.MaxAge < 0 {
+		return fmt.Errorf("retention policy MaxAge cannot be a negative value, got %v", p.MaxAge)
+	}
+	if p.MaxSignaturesPerKey < 0 {
+		return fmt.Errorf("retention policy MaxSignaturesPerKey cannot be a negative value, got %d", p.MaxSignaturesPerKey)
+	}
+	return nil
+}
+
+// RetentionOptions contains parameters for [RetentionPolicy.Apply].
+type RetentionOptions struct {
+	// ArtifactReference sets the full reference of the artifact whose
+	// signatures are pruned, e.g. "registry.io/repo:tag" or
+	// "registry.io/repo@sha256:...". The signatures are verified against the
+	// trust policy applicable to it.
+	ArtifactReference string
+
+	// VerificationPluginConfig is the plugin config used to verify the
+	// signatures.
+	VerificationPluginConfig map[string]string
+
+	// MaxSignatureAttempts sets the maximum number of signatures inspected
+	// for the artifact. Must be a positive number.
+	MaxSignatureAttempts int
+
+	// PruneUnverified prunes the signatures failing verification, e.g.
+	// signatures of untrusted identities or expired signatures. Only the
+	// parsed signatures failing with [VerificationFailedError] are pruned; the
+	// inconclusive or skipped verifications are always retained. By default,
+	// the signatures failing verification are retained and do not count
+	// against the limits of the policy.
+	PruneUnverified bool
+
+	// DryRun reports the signatures to be pruned without deleting them.
+	DryRun bool
+}
+
+// PrunedSignature describes a signature pruned by a [RetentionPolicy].
+type PrunedSignature struct {
+	// SignatureManifest is the descriptor of the pruned signature manifest.
+	SignatureManifest ocispec.Descriptor
+
+	// SigningTime is the signing time of the signature. It is zero for an
+	// unverified signature.
+	SigningTime time.Time
+
+	// Thumbprint is the hex-encoded SHA-256 thumbprint of the signing
+	// certificate. It is empty for an unverified signature.
+	Thumbprint string
+
+	// Reason is the reason the signature is pruned.
+	Reason PruneReason
+}
+
+// signatureDeleter is implemented by repositories able to delete signatures.
+type signatureDeleter interface {
+	// DeleteSignature deletes the signature manifest desc.
+	DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error
+}
+
+// Apply prunes the signatures of the artifact not retained by the policy
+// from repo. Only the signatures passing verification with verifier are
+// subject to the limits of the policy, so that the signing time and the
+// signing certificate of a signature are trusted.
+//
+// repo must support deleting signatures, as the repositories created by
+// [registry.NewRepository] do, unless opts.DryRun is set.
+//
+// The descriptor of the artifact and the pruned signatures are returned. On
+// error, the signatures pruned before the error are returned with it.
+func (p RetentionPolicy) Apply(ctx context.Context, verifier Verifier, repo registry.Repository, opts RetentionOptions) (ocispec.Descriptor, []*PrunedSignature, error) {
+	// sanity check
+	if err := p.Validate(); err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	if verifier == nil {
+		return ocispec.Descriptor{}, nil, errors.New("verifier cannot be nil")
+	}
+	if repo == nil {
+		return ocispec.Descriptor{}, nil, errors.New("repo cannot be nil")
+	}
+	if opts.MaxSignatureAttempts <= 0 {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("retentionOptions.MaxSignatureAttempts expects a positive number, got %d", opts.MaxSignatureAttempts)
+	}
+	deleter, ok := repo.(signatureDeleter)
+	if !ok && !opts.DryRun {
+		return ocispec.Descriptor{}, nil, errors.New("repo does not support deleting signatures")
+	}
+
+	logger := log.GetLogger(ctx)
+	ref, err := orasRegistry.ParseReference(opts.ArtifactReference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("retentionOptions.ArtifactReference expects a full reference: %w", err)
+	}
+	if ref.Reference == "" {
+		return ocispec.Descriptor{}, nil, errors.New("retentionOptions.ArtifactReference is missing digest or tag")
+	}
+	artifactManifestDesc, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to resolve reference: %w", err)
+	}
+	ref.Reference = artifactManifestDesc.Digest.String()
+	artifactRef := ref.String()
+
+	// inspect the signatures
+	var verified, pruned []*PrunedSignature
+	numOfSignatureProcessed := 0
+	err = repo.ListSignatures(ctx, artifactManifestDesc, func(signatureManifests []ocispec.Descriptor) error {
+		for _, sigManifestDesc := range signatureManifests {
+			if numOfSignatureProcessed >= opts.MaxSignatureAttempts {
+				return errDoneVerification
+			}
+			numOfSignatureProcessed++
+			sigBlob, sigDesc, err := repo.FetchSignatureBlob(ctx, sigManifestDesc)
+			if err != nil {
+				return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("unable to retrieve digital signature with digest %q associated with %q from the Repository, error : %v", sigManifestDesc.Digest, artifactRef, err.Error()), InnerError: err}
+			}
+			outcome, err := verifier.Verify(ctx, artifactManifestDesc, sigBlob, VerifierVerifyOptions{
+				ArtifactReference:            artifactRef,
+				SignatureMediaType:           sigDesc.MediaType,
+				PluginConfig:                 opts.VerificationPluginConfig,
+				SignatureManifestAnnotations: sigManifestDesc.Annotations,
+			})
+			if err != nil || outcome == nil || outcome.EnvelopeContent == nil || len(outcome.EnvelopeContent.SignerInfo.CertificateChain) == 0 {
+				// only the signatures definitively failing verification are
+				// pruned, not the inconclusive or skipped verifications
+				var verificationErr VerificationFailedError
+				if opts.PruneUnverified && errors.As(err, &verificationErr) && outcome != nil && outcome.EnvelopeContent != nil {
+					pruned = append(pruned, &PrunedSignature{
+						SignatureManifest: sigManifestDesc,
+						Reason:            PruneReasonUnverified,
+					})
+				} else {
+					logger.Warnf("Retaining signature %v that is not verified: %v", sigManifestDesc.Digest, err)
+				}
+				continue
+			}
+			signerInfo := outcome.EnvelopeContent.SignerInfo
+			thumbprint := sha256.Sum256(signerInfo.CertificateChain[0].Raw)
+			verified = append(verified, &PrunedSignature{
+				SignatureManifest: sigManifestDesc,
+				SigningTime:       signerInfo.SignedAttributes.SigningTime,
+				Thumbprint:        hex.EncodeToString(thumbprint[:]),
+			})
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDoneVerification) {
+		return ocispec.Descriptor{}, nil, err
+	}
+	pruned = append(pruned, p.prune(verified, time.Now())...)
+
+	// delete the pruned signatures
+	for i, sig := range pruned {
+		logger.Infof("Signature %v is pruned by the retention policy: %s", sig.SignatureManifest.Digest, sig.Reason)
+		if opts.DryRun {
+			continue
+		}
+		if err := deleter.DeleteSignature(ctx, sig.SignatureManifest); err != nil {
+			return artifactManifestDesc, pruned[:i], fmt.Errorf("failed to delete signature %v: %w", sig.SignatureManifest.Digest, err)
+		}
+	}
+	return artifactManifestDesc, pruned, nil
+}
+
+// prune returns the verified signatures not retained by the policy at now,
+// with the reason they are pruned.
+func (p RetentionPolicy) prune(signatures []*PrunedSignature, now time.Time) []*PrunedSignature {
+	// newest first
+	sort.SliceStable(signatures, func(i, j int) bool {
+		return signatures[i].SigningTime.After(signatures[j].SigningTime)
+	})
+
+	var pruned []*PrunedSignature
+	retained := 0
+	retainedPerKey := make(map[string]int)
+	for _, sig := range signatures {
+		switch {
+		case p.MaxAge > 0 && now.Sub(sig.SigningTime) > p.MaxAge:
+			sig.Reason = PruneReasonMaxAge
+		case p.MaxSignaturesPerKey > 0 && retainedPerKey[sig.Thumbprint] >= p.MaxSignaturesPerKey:
+			sig.Reason = PruneReasonMaxSignaturesPerKey
+		case p.MaxSignatures > 0 && retained >= p.MaxSignatures:
+			sig.Reason = PruneReasonMaxSignatures
+		default:
+			retained++
+			retainedPerKey[sig.Thumbprint]++
+			continue
+		}
+		pruned = append(pruned, sig)
+	}
+	return pruned
+}
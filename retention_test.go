@@ -0,0 +1,311 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// retentionRepository fetches the digest of the signature manifest as the
+// signature blob, and records the deleted signatures.
+type retentionRepository struct {
+	mock.Repository
+	deleted   []digest.Digest
+	deleteErr error
+}
+
+func (r *retentionRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	return []byte(desc.Digest), mock.JwsSigEnvDescriptor, nil
+}
+
+func (r *retentionRepository) DeleteSignature(ctx context.Context, desc ocispec.Descriptor) error {
+	if r.deleteErr != nil {
+		return r.deleteErr
+	}
+	r.deleted = append(r.deleted, desc.Digest)
+	return nil
+}
+
+// retentionVerifier verifies the signature blobs found in contents, failing
+// with the errors found in errs.
+type retentionVerifier struct {
+	contents map[string]*signature.EnvelopeContent
+	errs     map[string]error
+}
+
+func (v *retentionVerifier) Verify(_ context.Context, _ ocispec.Descriptor, sigBlob []byte, _ VerifierVerifyOptions) (*VerificationOutcome, error) {
+	content := v.contents[string(sigBlob)]
+	if err, ok := v.errs[string(sigBlob)]; ok {
+		return &VerificationOutcome{RawSignature: sigBlob, EnvelopeContent: content, Error: err}, err
+	}
+	return &VerificationOutcome{RawSignature: sigBlob, EnvelopeContent: content}, nil
+}
+
+// newRetentionFixture returns a repository and a verifier with a signature
+// per signing time and key. A signature with an empty key fails
+// verification.
+func newRetentionFixture(signingTimes []time.Time, keys []string) (*retentionRepository, *retentionVerifier, []digest.Digest) {
+	repo := &retentionRepository{Repository: mock.NewRepository()}
+	repo.ListSignaturesResponse = nil
+	verifier := &retentionVerifier{
+		contents: make(map[string]*signature.EnvelopeContent),
+		errs:     make(map[string]error),
+	}
+	var digests []digest.Digest
+	for i, signingTime := range signingTimes {
+		dgst := digest.FromString(signingTime.String() + keys[i])
+		digests = append(digests, dgst)
+		repo.ListSignaturesResponse = append(repo.ListSignaturesResponse, ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    dgst,
+		})
+		verifier.contents[string(dgst)] = &signature.EnvelopeContent{
+			SignerInfo: signature.SignerInfo{
+				SignedAttributes: signature.SignedAttributes{SigningTime: signingTime},
+				CertificateChain: []*x509.Certificate{{Raw: []byte(keys[i])}},
+			},
+		}
+		if keys[i] == "" {
+			verifier.errs[string(dgst)] = VerificationFailedError{Msg: "signature is not trusted"}
+		}
+	}
+	return repo, verifier, digests
+}
+
+func prunedDigests(pruned []*PrunedSignature) []digest.Digest {
+	var digests []digest.Digest
+	for _, sig := range pruned {
+		digests = append(digests, sig.SignatureManifest.Digest)
+	}
+	return digests
+}
+
+func TestRetentionPolicyApply(t *testing.T) {
+	now := time.Now()
+	signingTimes := []time.Time{
+		now.Add(-4 * time.Hour),
+		now.Add(-1 * time.Hour),
+		now.Add(-3 * time.Hour),
+		now.Add(-2 * time.Hour),
+		now.Add(-5 * time.Hour),
+	}
+	keys := []string{"key1", "key1", "key2", "key1", ""}
+	opts := RetentionOptions{
+		ArtifactReference:    mock.SampleArtifactUri,
+		MaxSignatureAttempts: 50,
+	}
+
+	t.Run("no limits", func(t *testing.T) {
+		repo, verifier, _ := newRetentionFixture(signingTimes, keys)
+		_, pruned, err := RetentionPolicy{}.Apply(context.Background(), verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if len(pruned) != 0 || len(repo.deleted) != 0 {
+			t.Fatalf("Apply() pruned %v, want none", prunedDigests(pruned))
+		}
+	})
+
+	t.Run("max signatures", func(t *testing.T) {
+		repo, verifier, digests := newRetentionFixture(signingTimes, keys)
+		artifactDesc, pruned, err := RetentionPolicy{MaxSignatures: 2}.Apply(context.Background(), verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if artifactDesc.Digest != mock.SampleDigest {
+			t.Fatalf("Apply() artifact = %v, want %v", artifactDesc.Digest, mock.SampleDigest)
+		}
+		want := []digest.Digest{digests[2], digests[0]}
+		if got := prunedDigests(pruned); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Apply() pruned %v, want %v", got, want)
+		}
+		if !reflect.DeepEqual(repo.deleted, want) {
+			t.Fatalf("deleted %v, want %v", repo.deleted, want)
+		}
+		for _, sig := range pruned {
+			if sig.Reason != PruneReasonMaxSignatures {
+				t.Fatalf("Apply() reason = %v, want %v", sig.Reason, PruneReasonMaxSignatures)
+			}
+		}
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		repo, verifier, digests := newRetentionFixture(signingTimes, keys)
+		_, pruned, err := RetentionPolicy{MaxAge: 150 * time.Minute}.Apply(context.Background(), verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		want := []digest.Digest{digests[2], digests[0]}
+		if got := prunedDigests(pruned); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Apply() pruned %v, want %v", got, want)
+		}
+		if pruned[0].Reason != PruneReasonMaxAge || pruned[0].SigningTime != signingTimes[2] {
+			t.Fatalf("Apply() pruned %+v", pruned[0])
+		}
+	})
+
+	t.Run("max signatures per key", func(t *testing.T) {
+		repo, verifier, digests := newRetentionFixture(signingTimes, keys)
+		_, pruned, err := RetentionPolicy{MaxSignaturesPerKey: 1}.Apply(context.Background(), verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		want := []digest.Digest{digests[3], digests[0]}
+		if got := prunedDigests(pruned); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Apply() pruned %v, want %v", got, want)
+		}
+		if pruned[0].Reason != PruneReasonMaxSignaturesPerKey {
+			t.Fatalf("Apply() reason = %v, want %v", pruned[0].Reason, PruneReasonMaxSignaturesPerKey)
+		}
+		// sha256("key1")
+		if want := "8174099687a26621f4e2cdd7cc03b3dacedb3fb962255b1aafd033cabe831530"; pruned[0].Thumbprint != want {
+			t.Fatalf("Apply() thumbprint = %v, want %v", pruned[0].Thumbprint, want)
+		}
+	})
+
+	t.Run("prune unverified", func(t *testing.T) {
+		repo, verifier, digests := newRetentionFixture(signingTimes, keys)
+		opts := opts
+		opts.PruneUnverified = true
+		_, pruned, err := RetentionPolicy{MaxSignaturesPerKey: 2}.Apply(context.Background(), verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		want := []digest.Digest{digests[4], digests[0]}
+		if got := prunedDigests(pruned); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Apply() pruned %v, want %v", got, want)
+		}
+		if pruned[0].Reason != PruneReasonUnverified {
+			t.Fatalf("Apply() reason = %v, want %v", pruned[0].Reason, PruneReasonUnverified)
+		}
+	})
+
+	t.Run("prune unverified retains inconclusive verifications", func(t *testing.T) {
+		for name, setup := range map[string]func(*retentionVerifier, digest.Digest){
+			"inconclusive": func(v *retentionVerifier, dgst digest.Digest) {
+				v.errs[string(dgst)] = VerificationInconclusiveError{Msg: "revocation status unknown"}
+			},
+			"not parsed": func(v *retentionVerifier, dgst digest.Digest) {
+				v.contents[string(dgst)] = nil
+			},
+			"skipped": func(v *retentionVerifier, dgst digest.Digest) {
+				v.contents[string(dgst)] = nil
+				delete(v.errs, string(dgst))
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				repo, verifier, digests := newRetentionFixture(signingTimes, keys)
+				setup(verifier, digests[4])
+				opts := opts
+				opts.PruneUnverified = true
+				_, pruned, err := RetentionPolicy{}.Apply(context.Background(), verifier, repo, opts)
+				if err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+				if len(pruned) != 0 || len(repo.deleted) != 0 {
+					t.Fatalf("Apply() pruned %v, want none", prunedDigests(pruned))
+				}
+			})
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		repo, verifier, digests := newRetentionFixture(signingTimes, keys)
+		opts := opts
+		opts.DryRun = true
+		_, pruned, err := RetentionPolicy{MaxSignatures: 3}.Apply(context.Background(), verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if got, want := prunedDigests(pruned), []digest.Digest{digests[0]}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Apply() pruned %v, want %v", got, want)
+		}
+		if len(repo.deleted) != 0 {
+			t.Fatalf("deleted %v in dry run", repo.deleted)
+		}
+	})
+
+	t.Run("max signature attempts", func(t *testing.T) {
+		repo, verifier, digests := newRetentionFixture(signingTimes, keys)
+		opts := opts
+		opts.MaxSignatureAttempts = 2
+		_, pruned, err := RetentionPolicy{MaxSignatures: 1}.Apply(context.Background(), verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if got, want := prunedDigests(pruned), []digest.Digest{digests[0]}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Apply() pruned %v, want %v", got, want)
+		}
+	})
+
+	t.Run("delete failed", func(t *testing.T) {
+		repo, verifier, _ := newRetentionFixture(signingTimes, keys)
+		repo.deleteErr = errors.New("delete failed")
+		_, pruned, err := RetentionPolicy{MaxSignatures: 1}.Apply(context.Background(), verifier, repo, opts)
+		if err == nil {
+			t.Fatal("Apply() expects error, got nil")
+		}
+		if len(pruned) != 0 {
+			t.Fatalf("Apply() pruned %v, want none", prunedDigests(pruned))
+		}
+	})
+}
+
+func TestRetentionPolicyApplyErrors(t *testing.T) {
+	repo, verifier, _ := newRetentionFixture(nil, nil)
+	opts := RetentionOptions{
+		ArtifactReference:    mock.SampleArtifactUri,
+		MaxSignatureAttempts: 50,
+	}
+	ctx := context.Background()
+
+	if _, _, err := (RetentionPolicy{MaxSignatures: -1}).Apply(ctx, verifier, repo, opts); err == nil {
+		t.Fatal("Apply() expects error for negative MaxSignatures")
+	}
+	if _, _, err := (RetentionPolicy{MaxAge: -time.Hour}).Apply(ctx, verifier, repo, opts); err == nil {
+		t.Fatal("Apply() expects error for negative MaxAge")
+	}
+	if _, _, err := (RetentionPolicy{MaxSignaturesPerKey: -1}).Apply(ctx, verifier, repo, opts); err == nil {
+		t.Fatal("Apply() expects error for negative MaxSignaturesPerKey")
+	}
+	if _, _, err := (RetentionPolicy{}).Apply(ctx, nil, repo, opts); err == nil {
+		t.Fatal("Apply() expects error for nil verifier")
+	}
+	if _, _, err := (RetentionPolicy{}).Apply(ctx, verifier, nil, opts); err == nil {
+		t.Fatal("Apply() expects error for nil repo")
+	}
+	if _, _, err := (RetentionPolicy{}).Apply(ctx, verifier, mock.NewRepository(), opts); err == nil {
+		t.Fatal("Apply() expects error for repo not supporting deletion")
+	}
+	badOpts := opts
+	badOpts.MaxSignatureAttempts = 0
+	if _, _, err := (RetentionPolicy{}).Apply(ctx, verifier, repo, badOpts); err == nil {
+		t.Fatal("Apply() expects error for MaxSignatureAttempts")
+	}
+	badOpts = opts
+	badOpts.ArtifactReference = mock.SampleDigest.String()
+	if _, _, err := (RetentionPolicy{}).Apply(ctx, verifier, repo, badOpts); err == nil {
+		t.Fatal("Apply() expects error for invalid reference")
+	}
+}
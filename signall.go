@@ -0,0 +1,128 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	orasRegistry "oras.land/oras-go/v2/registry"
+
+	"github.com/notaryproject/notation-go/log"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// SignAllOptions contains parameters for [notation.SignAll].
+type SignAllOptions struct {
+	SignerSignOptions
+
+	// UserMetadata contains key-value pairs that are added to the signature
+	// payload
+	UserMetadata map[string]string
+
+	// Concurrency is the maximum number of artifact references signed
+	// concurrently. If set to less than or equals to zero, 4 is used.
+	Concurrency int
+
+	// FailurePolicy, if set, sets how the failed signings are handled, and a
+	// [BatchError] is returned if any signing fails. If nil, all the
+	// references are signed and the failures are only reported in the
+	// results.
+	FailurePolicy *FailurePolicy
+}
+
+// SignAllResult is the signing result of an artifact reference signed by
+// [notation.SignAll].
+type SignAllResult struct {
+	// ArtifactReference is the signed artifact reference.
+	ArtifactReference string
+
+	// Descriptor is the descriptor of the signed artifact.
+	Descriptor ocispec.Descriptor
+
+	// Error is the error that caused the signing to fail (if it fails).
+	Error error
+}
+
+// SignAll signs the artifacts referenced by artifactRefs with bounded
+// concurrency and pushes the signatures, and returns the result of each
+// reference in the order of artifactRefs.
+//
+// The repository client returned by repoFunc is created once per repository
+// and shared by the references of the repository.
+//
+// If opts.FailurePolicy is nil, an error is returned only if the arguments
+// are invalid.
+func SignAll(ctx context.Context, signer Signer, repoFunc RepositoryFunc, artifactRefs []string, opts SignAllOptions) ([]*SignAllResult, error) {
+	// sanity check
+	if signer == nil {
+		return nil, errors.New("signer cannot be nil")
+	}
+	if repoFunc == nil {
+		return nil, errors.New("repoFunc cannot be nil")
+	}
+	if err := opts.FailurePolicy.validate(); err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultVerifyAllConcurrency
+	}
+
+	repos := &repositoryCache{
+		repoFunc: repoFunc,
+		entries:  make(map[string]*repositoryCacheEntry),
+	}
+	results := make([]*SignAllResult, len(artifactRefs))
+	for i, artifactRef := range artifactRefs {
+		results[i] = &SignAllResult{ArtifactReference: artifactRef}
+	}
+	errs, aborted := runBatch(ctx, len(artifactRefs), concurrency, opts.FailurePolicy, func(ctx context.Context, i int) error {
+		result := results[i]
+		result.Descriptor, result.Error = signReference(ctx, signer, repos, result.ArtifactReference, opts)
+		return result.Error
+	})
+	for i, err := range errs {
+		results[i].Error = err
+	}
+	if opts.FailurePolicy != nil {
+		return results, newBatchError(artifactRefs, errs, aborted)
+	}
+	return results, nil
+}
+
+// signReference signs the artifact referenced by artifactRef.
+func signReference(ctx context.Context, signer Signer, repos *repositoryCache, artifactRef string, opts SignAllOptions) (ocispec.Descriptor, error) {
+	if err := ctx.Err(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	ref, err := orasRegistry.ParseReference(artifactRef)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	repo, err := repos.get(ctx, ref.Registry+"/"+ref.Repository)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to create the repository client of %q: %w", artifactRef, err)
+	}
+	desc, _, err := SignOCI(ctx, signer, repo, SignOptions{
+		SignerSignOptions: opts.SignerSignOptions,
+		ArtifactReference: artifactRef,
+		UserMetadata:      opts.UserMetadata,
+	})
+	if err != nil {
+		log.GetLogger(ctx).Warnf("Signing of %s failed with error: %v", artifactRef, err)
+	}
+	return desc, err
+}
@@ -0,0 +1,85 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/registry"
+)
+
+func TestSignAll(t *testing.T) {
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		if repository == "registry.acme-rockets.io/software/unknown" {
+			return nil, errors.New("repository not found")
+		}
+		return mock.NewRepository(), nil
+	}
+	refs := []string{
+		mock.SampleArtifactUri,
+		"registry.acme-rockets.io/software/unknown@" + mock.SampleDigest.String(),
+		"invalid reference",
+		mock.SampleArtifactUri,
+	}
+	opts := SignAllOptions{Concurrency: 2}
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+
+	results, err := SignAll(context.Background(), &dummySigner{}, repoFunc, refs, opts)
+	if err != nil {
+		t.Fatalf("SignAll() error = %v", err)
+	}
+	for _, i := range []int{0, 3} {
+		if results[i].Error != nil || results[i].Descriptor.Digest != mock.SampleDigest {
+			t.Fatalf("SignAll() result %d = %+v, want success", i, results[i])
+		}
+	}
+	for _, i := range []int{1, 2} {
+		if results[i].Error == nil {
+			t.Fatalf("SignAll() result %d expected error", i)
+		}
+	}
+
+	opts.Concurrency = 1
+	opts.FailurePolicy = &FailurePolicy{Mode: AbortAfterFailures, MaxFailures: 2}
+	results, err = SignAll(context.Background(), &dummySigner{}, repoFunc, refs, opts)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("SignAll() error = %v, want *BatchError", err)
+	}
+	if !batchErr.Aborted || len(batchErr.Items) != 3 || batchErr.Total != len(refs) {
+		t.Fatalf("SignAll() error = %+v", batchErr)
+	}
+	if !errors.Is(results[3].Error, ErrBatchAborted) {
+		t.Fatalf("SignAll() result 3 error = %v, want ErrBatchAborted", results[3].Error)
+	}
+}
+
+func TestSignAll_Error(t *testing.T) {
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		return mock.NewRepository(), nil
+	}
+	if _, err := SignAll(context.Background(), nil, repoFunc, nil, SignAllOptions{}); err == nil {
+		t.Fatal("expected error for nil signer")
+	}
+	if _, err := SignAll(context.Background(), &dummySigner{}, nil, nil, SignAllOptions{}); err == nil {
+		t.Fatal("expected error for nil repoFunc")
+	}
+	if _, err := SignAll(context.Background(), &dummySigner{}, repoFunc, nil, SignAllOptions{FailurePolicy: &FailurePolicy{Mode: AbortAfterFailures}}); err == nil {
+		t.Fatal("expected error for invalid failure policy")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orasRegistry "oras.land/oras-go/v2/registry"
+)
+
+// SignDescriptor signs the OCI artifact described by artifactManifestDesc,
+// e.g. as returned by a prior build step, and pushes the signature to the
+// Repository with artifactManifestDesc as subject. Unlike [SignOCI], the
+// artifact is not resolved in the Repository, so that it can be signed
+// before its manifest is pushed to its final location.
+//
+// signOpts.ArtifactReference is optional. If set, it must be a digest or a
+// full reference matching the digest of artifactManifestDesc, and is
+// recorded in the logs and in the audit events. Tags are rejected, as they
+// are not checked against the descriptor.
+//
+// Both artifact and signature manifest descriptors are returned upon
+// successful signing, as in [SignOCI].
+func SignDescriptor(ctx context.Context, signer Signer, repo registry.Repository, artifactManifestDesc ocispec.Descriptor, signOpts SignOptions) (_, _ ocispec.Descriptor, err error) {
+	ctx = withOperation(ctx, operationSign)
+	defer func() { endOperation(ctx, err) }()
+
+	// sanity check
+	if err := validateSignArguments(signer, signOpts.SignerSignOptions); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+	if repo == nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, errors.New("repo cannot be nil")
+	}
+	if err := validateArtifactDescriptor(artifactManifestDesc); err != nil {
+		return ocispec.Descriptor{}, ocispec.Descriptor{}, err
+	}
+
+	var repository string
+	if artifactRef := signOpts.ArtifactReference; artifactRef != "" {
+		if ref, err := orasRegistry.ParseReference(artifactRef); err == nil {
+			artifactRef = ref.Reference
+			repository = ref.Registry + "/" + ref.Repository
+			ctx = log.WithFields(ctx, log.Fields{log.FieldRepository: repository})
+		}
+		if _, err := digest.Parse(artifactRef); err != nil {
+			return ocispec.Descriptor{}, ocispec.Descriptor{}, fmt.Errorf("artifact reference %s must reference the artifact by digest", signOpts.ArtifactReference)
+		}
+		if artifactRef != artifactManifestDesc.Digest.String() {
+			return ocispec.Descriptor{}, ocispec.Descriptor{}, fmt.Errorf("user input digest %s does not match the artifact descriptor digest %s", artifactRef, artifactManifestDesc.Digest)
+		}
+	}
+	logTransportWarnings(ctx, repo)
+	return signArtifact(ctx, signer, repo, repository, artifactManifestDesc, signOpts)
+}
+
+// validateArtifactDescriptor validates the artifact manifest descriptor
+// provided by the caller.
+func validateArtifactDescriptor(desc ocispec.Descriptor) error {
+	if desc.MediaType == "" {
+		return errors.New("artifact descriptor media type cannot be empty")
+	}
+	if err := desc.Digest.Validate(); err != nil {
+		return fmt.Errorf("invalid artifact descriptor digest %q: %w", desc.Digest, err)
+	}
+	if desc.Size < 0 {
+		return fmt.Errorf("artifact descriptor size cannot be negative, got %d", desc.Size)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// subjectRecordingRepository records the subject of the pushed signature.
+type subjectRecordingRepository struct {
+	mock.Repository
+	subject *ocispec.Descriptor
+}
+
+func (r subjectRecordingRepository) PushSignature(ctx context.Context, mediaType string, blob []byte, subject ocispec.Descriptor, annotations map[string]string) (ocispec.Descriptor, ocispec.Descriptor, error) {
+	*r.subject = subject
+	return r.Repository.PushSignature(ctx, mediaType, blob, subject, annotations)
+}
+
+func TestSignDescriptor(t *testing.T) {
+	// the artifact is not resolved
+	repo := subjectRecordingRepository{Repository: mock.NewRepository(), subject: &ocispec.Descriptor{}}
+	repo.ResolveError = errors.New("artifact not found")
+	opts := SignOptions{}
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+
+	for _, artifactRef := range []string{"", mock.ImageDescriptor.Digest.String(), "localhost:5000/net-monitor@" + mock.ImageDescriptor.Digest.String()} {
+		opts.ArtifactReference = artifactRef
+		*repo.subject = ocispec.Descriptor{}
+		artifactDesc, _, err := SignDescriptor(context.Background(), &dummySigner{}, repo, mock.ImageDescriptor, opts)
+		if err != nil {
+			t.Fatalf("SignDescriptor(%q) error = %v", artifactRef, err)
+		}
+		if artifactDesc.Digest != mock.ImageDescriptor.Digest || repo.subject.Digest != mock.ImageDescriptor.Digest {
+			t.Fatalf("SignDescriptor(%q) = %v with subject %v, want %v", artifactRef, artifactDesc, repo.subject, mock.ImageDescriptor)
+		}
+	}
+}
+
+func TestSignDescriptorError(t *testing.T) {
+	validOpts := SignOptions{}
+	validOpts.SignatureMediaType = jws.MediaTypeEnvelope
+	invalidDigest := mock.ImageDescriptor
+	invalidDigest.Digest = "sha256:invalid"
+	noMediaType := mock.ImageDescriptor
+	noMediaType.MediaType = ""
+	negativeSize := mock.ImageDescriptor
+	negativeSize.Size = -1
+
+	tests := []struct {
+		name         string
+		repo         registry.Repository
+		artifactDesc ocispec.Descriptor
+		artifactRef  string
+	}{
+		{name: "nil repository", artifactDesc: mock.ImageDescriptor},
+		{name: "invalid digest", repo: mock.NewRepository(), artifactDesc: invalidDigest},
+		{name: "empty media type", repo: mock.NewRepository(), artifactDesc: noMediaType},
+		{name: "negative size", repo: mock.NewRepository(), artifactDesc: negativeSize},
+		{name: "tag reference", repo: mock.NewRepository(), artifactDesc: mock.ImageDescriptor, artifactRef: "localhost:5000/net-monitor:v1"},
+		{name: "mismatched digest", repo: mock.NewRepository(), artifactDesc: mock.ImageDescriptor, artifactRef: "localhost:5000/net-monitor@" + mock.ZeroDigest.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := validOpts
+			opts.ArtifactReference = tt.artifactRef
+			if _, _, err := SignDescriptor(context.Background(), &dummySigner{}, tt.repo, tt.artifactDesc, opts); err == nil {
+				t.Fatal("SignDescriptor() expects error, got nil")
+			}
+		})
+	}
+}
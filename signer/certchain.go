@@ -0,0 +1,132 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	corex509 "github.com/notaryproject/notation-core-go/x509"
+	"github.com/notaryproject/notation-go/fips"
+)
+
+// CertificateChainOptions contains the optional parameters of
+// [ValidateCertificateChain].
+type CertificateChainOptions struct {
+	// Time is the time at which the certificate chain must be valid, e.g.
+	// the planned signing time. It defaults to the current time.
+	Time time.Time
+
+	// PrivateKey, if set, must match the public key of the signing
+	// certificate.
+	PrivateKey crypto.PrivateKey
+}
+
+// CertificateChainResult is the result of the validation of a code signing
+// certificate chain with [ValidateCertificateChain].
+type CertificateChainResult struct {
+	// KeySpec is the key spec of the signing certificate. It is zero if the
+	// key of the signing certificate is not supported.
+	KeySpec signature.KeySpec
+
+	// SignatureAlgorithm is the signature algorithm of the signatures
+	// produced with the signing certificate. It is zero if the key of the
+	// signing certificate is not supported.
+	SignatureAlgorithm signature.Algorithm
+
+	// NotAfter is the earliest expiry of the certificates of the chain, after
+	// which the chain cannot be used for signing.
+	NotAfter time.Time
+
+	// Errors are the rules violated by the certificate chain, in the order
+	// of the checks. The certificate chain is valid if Errors is empty.
+	Errors []error
+}
+
+// Valid returns true if the certificate chain is valid.
+func (r *CertificateChainResult) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Err returns the errors of the validation joined, or nil if the
+// certificate chain is valid.
+func (r *CertificateChainResult) Err() error {
+	return errors.Join(r.Errors...)
+}
+
+// ValidateCertificateChain validates the code signing certificate chain
+// certChain, starting with the signing certificate, with the rules applied by
+// the signers of this package at signing time, e.g. for a tool to reject a
+// certificate chain at key import time:
+//   - the certificate chain must be a valid code signing certificate chain
+//     as specified by the Notary Project, at opts.Time;
+//   - the key of the signing certificate must be supported;
+//   - the key and the certificates must be FIPS-approved, if the FIPS mode
+//     is enabled. See [fips.Enabled];
+//   - the private key, if set, must match the signing certificate.
+//
+// All the rules are checked, and the violations are reported in the result.
+func ValidateCertificateChain(certChain []*x509.Certificate, opts CertificateChainOptions) *CertificateChainResult {
+	result := &CertificateChainResult{}
+	if len(certChain) == 0 {
+		result.Errors = append(result.Errors, errors.New("certificate chain cannot be empty"))
+		return result
+	}
+	for _, cert := range certChain {
+		if result.NotAfter.IsZero() || cert.NotAfter.Before(result.NotAfter) {
+			result.NotAfter = cert.NotAfter
+		}
+	}
+
+	validationTime := opts.Time
+	if validationTime.IsZero() {
+		validationTime = time.Now()
+	}
+	if err := corex509.ValidateCodeSigningCertChain(certChain, &validationTime); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("invalid code signing certificate chain: %w", err))
+	}
+
+	signingCert := certChain[0]
+	keySpec, err := signature.ExtractKeySpec(signingCert)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("unsupported key of certificate %q: %w", signingCert.Subject, err))
+	} else {
+		result.KeySpec = keySpec
+		result.SignatureAlgorithm = keySpec.SignatureAlgorithm()
+		if fips.Enabled() {
+			if err := fips.ValidateKeySpec(keySpec); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		}
+	}
+	if fips.Enabled() {
+		if err := fips.ValidateCertificateChain(certChain); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+
+	if opts.PrivateKey != nil {
+		signer, ok := opts.PrivateKey.(crypto.Signer)
+		if !ok {
+			result.Errors = append(result.Errors, fmt.Errorf("private key of type %T cannot sign", opts.PrivateKey))
+		} else if publicKey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !publicKey.Equal(signingCert.PublicKey) {
+			result.Errors = append(result.Errors, fmt.Errorf("private key does not match the public key of certificate %q", signingCert.Subject))
+		}
+	}
+	return result
+}
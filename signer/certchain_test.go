@@ -0,0 +1,82 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/testhelper"
+)
+
+func TestValidateCertificateChain(t *testing.T) {
+	leaf := testhelper.GetRSALeafCertificate()
+	root := testhelper.GetRSARootCertificate()
+	certChain := []*x509.Certificate{leaf.Cert, root.Cert}
+
+	result := ValidateCertificateChain(certChain, CertificateChainOptions{PrivateKey: leaf.PrivateKey})
+	if !result.Valid() || result.Err() != nil {
+		t.Fatalf("ValidateCertificateChain() errors = %v", result.Errors)
+	}
+	if result.KeySpec.Type != signature.KeyTypeRSA || result.SignatureAlgorithm == 0 {
+		t.Fatalf("ValidateCertificateChain() key spec = %+v, algorithm = %v", result.KeySpec, result.SignatureAlgorithm)
+	}
+	wantNotAfter := leaf.Cert.NotAfter
+	if root.Cert.NotAfter.Before(wantNotAfter) {
+		wantNotAfter = root.Cert.NotAfter
+	}
+	if !result.NotAfter.Equal(wantNotAfter) {
+		t.Fatalf("ValidateCertificateChain() NotAfter = %v, want %v", result.NotAfter, wantNotAfter)
+	}
+
+	tests := []struct {
+		name      string
+		certChain []*x509.Certificate
+		opts      CertificateChainOptions
+		numErrors int
+	}{
+		{
+			name:      "empty chain",
+			numErrors: 1,
+		},
+		{
+			name:      "expired at signing time",
+			certChain: certChain,
+			opts:      CertificateChainOptions{Time: wantNotAfter.Add(time.Hour)},
+			numErrors: 1,
+		},
+		{
+			name:      "mismatched private key",
+			certChain: certChain,
+			opts:      CertificateChainOptions{PrivateKey: testhelper.GetECLeafCertificate().PrivateKey},
+			numErrors: 1,
+		},
+		{
+			name:      "all violations",
+			certChain: []*x509.Certificate{root.Cert, leaf.Cert},
+			opts:      CertificateChainOptions{Time: wantNotAfter.Add(time.Hour), PrivateKey: leaf.PrivateKey},
+			numErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateCertificateChain(tt.certChain, tt.opts)
+			if result.Valid() || len(result.Errors) != tt.numErrors {
+				t.Fatalf("ValidateCertificateChain() errors = %v, want %d errors", result.Errors, tt.numErrors)
+			}
+		})
+	}
+}
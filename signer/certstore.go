@@ -0,0 +1,40 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import "crypto/x509"
+
+// CertStoreOptions selects the signing certificate and its private key in
+// the Windows certificate store. Either Thumbprint or Subject must be set.
+type CertStoreOptions struct {
+	// StoreName is the name of the system store. Defaults to "MY", the
+	// personal certificates.
+	StoreName string
+
+	// LocalMachine selects the store of the local machine instead of the
+	// one of the current user.
+	LocalMachine bool
+
+	// Thumbprint is the hex encoded SHA-1 thumbprint of the certificate.
+	Thumbprint string
+
+	// Subject is a substring of the subject name of the certificate. The
+	// first matching certificate with a private key is selected.
+	Subject string
+
+	// CertificateChain is the optional chain of the certificate, from the
+	// intermediate certificates to the root certificate. If not set, the
+	// chain is built from the system stores.
+	CertificateChain []*x509.Certificate
+}
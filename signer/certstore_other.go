@@ -0,0 +1,28 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package signer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NewCertStoreSigner returns a builtinSigner signing with a certificate and
+// its private key of the Windows certificate store. It is only supported on
+// Windows.
+func NewCertStoreSigner(opts CertStoreOptions) (*GenericSigner, error) {
+	return nil, fmt.Errorf("windows certificate store: %w", errors.ErrUnsupported)
+}
@@ -0,0 +1,286 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	certStoreProvSystemW          = 10
+	certSystemStoreCurrentUser    = 1 << 16
+	certSystemStoreLocalMachine   = 2 << 16
+	certStoreReadOnlyFlag         = 0x8000
+	certStoreOpenExistingFlag     = 0x4000
+	encodingX509ASNPKCS7ASN       = 0x10001
+	certFindSHA1Hash              = 1 << 16
+	certFindSubjectStrW           = 8<<16 | 7
+	cryptAcquireSilentFlag        = 0x40
+	cryptAcquireOnlyNCryptKeyFlag = 0x40000
+	bcryptPadPSS                  = 8
+	ncryptSilentFlag              = 0x40
+)
+
+var (
+	modcrypt32                            = syscall.NewLazyDLL("crypt32.dll")
+	modncrypt                             = syscall.NewLazyDLL("ncrypt.dll")
+	procCertOpenStore                     = modcrypt32.NewProc("CertOpenStore")
+	procCertCloseStore                    = modcrypt32.NewProc("CertCloseStore")
+	procCertFindCertificateInStore        = modcrypt32.NewProc("CertFindCertificateInStore")
+	procCertDuplicateCertificateContext   = modcrypt32.NewProc("CertDuplicateCertificateContext")
+	procCertFreeCertificateContext        = modcrypt32.NewProc("CertFreeCertificateContext")
+	procCryptAcquireCertificatePrivateKey = modcrypt32.NewProc("CryptAcquireCertificatePrivateKey")
+	procNCryptSignHash                    = modncrypt.NewProc("NCryptSignHash")
+	procNCryptFreeObject                  = modncrypt.NewProc("NCryptFreeObject")
+)
+
+// certContext is the CERT_CONTEXT structure.
+type certContext struct {
+	EncodingType uint32
+	Encoded      *byte
+	Length       uint32
+	CertInfo     uintptr
+	Store        uintptr
+}
+
+// cryptHashBlob is the CRYPT_HASH_BLOB structure.
+type cryptHashBlob struct {
+	Size uint32
+	Data *byte
+}
+
+// bcryptPSSPaddingInfo is the BCRYPT_PSS_PADDING_INFO structure.
+type bcryptPSSPaddingInfo struct {
+	AlgID *uint16
+	Salt  uint32
+}
+
+// NewCertStoreSigner returns a builtinSigner signing with a certificate and
+// its private key of the Windows certificate store. The private key is used
+// through CNG, so that non-exportable keys are supported.
+func NewCertStoreSigner(opts CertStoreOptions) (*GenericSigner, error) {
+	if opts.Thumbprint == "" && opts.Subject == "" {
+		return nil, errors.New("either the thumbprint or the subject of the certificate must be specified")
+	}
+	storeName := opts.StoreName
+	if storeName == "" {
+		storeName = "MY"
+	}
+	key, err := openCertStoreKey(storeName, opts)
+	if err != nil {
+		return nil, err
+	}
+	chain := []*x509.Certificate{key.cert}
+	if opts.CertificateChain != nil {
+		chain = append(chain, opts.CertificateChain...)
+	} else {
+		chains, err := key.cert.Verify(x509.VerifyOptions{
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the certificate chain: %w", err)
+		}
+		chain = chains[0]
+	}
+	return NewGenericSignerFromCryptoSigner(key, chain)
+}
+
+// certStoreKey is a crypto.Signer signing with the private key of a
+// certificate of the Windows certificate store.
+type certStoreKey struct {
+	ctx  *certContext
+	cert *x509.Certificate
+}
+
+// openCertStoreKey finds the certificate in the store.
+func openCertStoreKey(storeName string, opts CertStoreOptions) (*certStoreKey, error) {
+	name, err := syscall.UTF16PtrFromString(storeName)
+	if err != nil {
+		return nil, err
+	}
+	flags := uintptr(certSystemStoreCurrentUser)
+	if opts.LocalMachine {
+		flags = certSystemStoreLocalMachine
+	}
+	flags |= certStoreReadOnlyFlag | certStoreOpenExistingFlag
+	store, _, err := procCertOpenStore.Call(certStoreProvSystemW, 0, 0, flags, uintptr(unsafe.Pointer(name)))
+	if store == 0 {
+		return nil, fmt.Errorf("failed to open certificate store %q: %w", storeName, err)
+	}
+	defer procCertCloseStore.Call(store, 0)
+
+	var findType uintptr
+	var findPara unsafe.Pointer
+	if opts.Thumbprint != "" {
+		thumbprint, err := hex.DecodeString(strings.ReplaceAll(opts.Thumbprint, " ", ""))
+		if err != nil || len(thumbprint) != 20 {
+			return nil, fmt.Errorf("invalid SHA-1 thumbprint %q", opts.Thumbprint)
+		}
+		findType = certFindSHA1Hash
+		findPara = unsafe.Pointer(&cryptHashBlob{Size: uint32(len(thumbprint)), Data: &thumbprint[0]})
+	} else {
+		subject, err := syscall.UTF16PtrFromString(opts.Subject)
+		if err != nil {
+			return nil, err
+		}
+		findType = certFindSubjectStrW
+		findPara = unsafe.Pointer(subject)
+	}
+
+	var prev uintptr
+	for {
+		found, _, _ := procCertFindCertificateInStore.Call(store, encodingX509ASNPKCS7ASN, 0, findType, uintptr(findPara), prev)
+		if found == 0 {
+			return nil, fmt.Errorf("no certificate with a private key found in certificate store %q", storeName)
+		}
+		prev = found
+		ctx := toCertContext(found)
+		handle, free, err := acquirePrivateKey(ctx)
+		if err != nil {
+			continue
+		}
+		freePrivateKey(handle, free)
+		cert, err := x509.ParseCertificate(unsafe.Slice(ctx.Encoded, ctx.Length))
+		if err != nil {
+			procCertFreeCertificateContext.Call(found)
+			return nil, err
+		}
+		dup, _, _ := procCertDuplicateCertificateContext.Call(found)
+		procCertFreeCertificateContext.Call(found)
+		key := &certStoreKey{
+			ctx:  toCertContext(dup),
+			cert: cert,
+		}
+		runtime.SetFinalizer(key, func(k *certStoreKey) {
+			procCertFreeCertificateContext.Call(uintptr(unsafe.Pointer(k.ctx)))
+		})
+		return key, nil
+	}
+}
+
+// toCertContext converts the CERT_CONTEXT pointer returned by crypt32.
+func toCertContext(ptr uintptr) *certContext {
+	return *(**certContext)(unsafe.Pointer(&ptr))
+}
+
+// acquirePrivateKey acquires the CNG private key handle of the certificate.
+func acquirePrivateKey(ctx *certContext) (handle uintptr, free bool, err error) {
+	var keySpec uint32
+	var callerFree int32
+	r, _, err := procCryptAcquireCertificatePrivateKey.Call(
+		uintptr(unsafe.Pointer(ctx)),
+		cryptAcquireOnlyNCryptKeyFlag|cryptAcquireSilentFlag,
+		0,
+		uintptr(unsafe.Pointer(&handle)),
+		uintptr(unsafe.Pointer(&keySpec)),
+		uintptr(unsafe.Pointer(&callerFree)),
+	)
+	if r == 0 {
+		return 0, false, fmt.Errorf("CryptAcquireCertificatePrivateKey failed: %w", err)
+	}
+	return handle, callerFree != 0, nil
+}
+
+// freePrivateKey frees the private key handle if owned by the caller.
+func freePrivateKey(handle uintptr, free bool) {
+	if free {
+		procNCryptFreeObject.Call(handle)
+	}
+}
+
+// Public returns the public key of the certificate.
+func (k *certStoreKey) Public() crypto.PublicKey {
+	return k.cert.PublicKey
+}
+
+// Sign signs the digest with NCryptSignHash. RSA keys sign with RSASSA-PSS
+// only.
+func (k *certStoreKey) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	handle, free, err := acquirePrivateKey(k.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer freePrivateKey(handle, free)
+	defer runtime.KeepAlive(k)
+
+	var padding unsafe.Pointer
+	var flags uintptr = ncryptSilentFlag
+	switch k.cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		pssOpts, ok := opts.(*rsa.PSSOptions)
+		if !ok {
+			return nil, errors.New("only RSASSA-PSS is supported")
+		}
+		algID, err := cngHashAlgorithm(pssOpts.HashFunc())
+		if err != nil {
+			return nil, err
+		}
+		padding = unsafe.Pointer(&bcryptPSSPaddingInfo{
+			AlgID: algID,
+			Salt:  uint32(pssOpts.HashFunc().Size()),
+		})
+		flags |= bcryptPadPSS
+	case *ecdsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", k.cert.PublicKey)
+	}
+
+	var size uint32
+	if r, _, _ := procNCryptSignHash.Call(handle, uintptr(padding), uintptr(unsafe.Pointer(&digest[0])), uintptr(len(digest)), 0, 0, uintptr(unsafe.Pointer(&size)), flags); r != 0 {
+		return nil, fmt.Errorf("NCryptSignHash failed with status 0x%x", r)
+	}
+	sig := make([]byte, size)
+	if r, _, _ := procNCryptSignHash.Call(handle, uintptr(padding), uintptr(unsafe.Pointer(&digest[0])), uintptr(len(digest)), uintptr(unsafe.Pointer(&sig[0])), uintptr(size), uintptr(unsafe.Pointer(&size)), flags); r != 0 {
+		return nil, fmt.Errorf("NCryptSignHash failed with status 0x%x", r)
+	}
+	sig = sig[:size]
+	if _, ok := k.cert.PublicKey.(*ecdsa.PublicKey); ok {
+		// CNG returns the concatenation of R and S
+		half := len(sig) / 2
+		return asn1.Marshal(ecdsaSignature{
+			R: new(big.Int).SetBytes(sig[:half]),
+			S: new(big.Int).SetBytes(sig[half:]),
+		})
+	}
+	return sig, nil
+}
+
+// cngHashAlgorithm returns the CNG identifier of the hash algorithm.
+func cngHashAlgorithm(hash crypto.Hash) (*uint16, error) {
+	switch hash {
+	case crypto.SHA256:
+		return syscall.UTF16PtrFromString("SHA256")
+	case crypto.SHA384:
+		return syscall.UTF16PtrFromString("SHA384")
+	case crypto.SHA512:
+		return syscall.UTF16PtrFromString("SHA512")
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %v", hash)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/notaryproject/notation-core-go/signature"
+)
+
+// NewGenericSignerFromCryptoSigner returns a builtinSigner given a
+// [crypto.Signer] and cert chain. It is used for the private keys which are
+// not accessible to notation, such as the non-exportable keys of the OS key
+// stores and of the hardware security modules.
+func NewGenericSignerFromCryptoSigner(key crypto.Signer, certChain []*x509.Certificate) (*GenericSigner, error) {
+	if key == nil {
+		return nil, errors.New("key cannot be nil")
+	}
+	if len(certChain) == 0 {
+		return nil, errors.New("certificate chain cannot be empty")
+	}
+	keySpec, err := signature.ExtractKeySpec(certChain[0])
+	if err != nil {
+		return nil, err
+	}
+	if !publicKeyEqual(key.Public(), certChain[0].PublicKey) {
+		return nil, errors.New("key does not match the signing certificate")
+	}
+	return &GenericSigner{
+		signer: &cryptoPrimitiveSigner{
+			key:       key,
+			certChain: certChain,
+			keySpec:   keySpec,
+		},
+	}, nil
+}
+
+// publicKeyEqual reports whether the public keys are equal.
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+// cryptoPrimitiveSigner implements signature.Signer with a crypto.Signer.
+type cryptoPrimitiveSigner struct {
+	key       crypto.Signer
+	certChain []*x509.Certificate
+	keySpec   signature.KeySpec
+}
+
+// Sign signs the payload with RSASSA-PSS or ECDSA, and returns the raw
+// signature and the cert chain.
+func (s *cryptoPrimitiveSigner) Sign(payload []byte) ([]byte, []*x509.Certificate, error) {
+	hash := s.keySpec.SignatureAlgorithm().Hash()
+	if !hash.Available() {
+		return nil, nil, fmt.Errorf("hash algorithm %v is not available", hash)
+	}
+	h := hash.New()
+	h.Write(payload)
+	digest := h.Sum(nil)
+
+	switch s.keySpec.Type {
+	case signature.KeyTypeRSA:
+		sig, err := s.key.Sign(rand.Reader, digest, &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+			Hash:       hash,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return sig, s.certChain, nil
+	case signature.KeyTypeEC:
+		der, err := s.key.Sign(rand.Reader, digest, hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		sig, err := rawECDSASignature(der, s.keySpec.Size)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sig, s.certChain, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported key type %v", s.keySpec.Type)
+	}
+}
+
+// KeySpec returns the key spec of the signing certificate.
+func (s *cryptoPrimitiveSigner) KeySpec() (signature.KeySpec, error) {
+	return s.keySpec, nil
+}
+
+// ecdsaSignature is the ASN.1 encoding of an ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// rawECDSASignature converts the ASN.1 encoded ECDSA signature returned by
+// crypto.Signer to the concatenation of R and S used by the signature
+// envelopes.
+func rawECDSASignature(der []byte, keySize int) ([]byte, error) {
+	var sig ecdsaSignature
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("malformed ECDSA signature: %w", err)
+	} else if len(rest) > 0 {
+		return nil, errors.New("malformed ECDSA signature: trailing data")
+	}
+	size := (keySize + 7) / 8
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.BitLen() > keySize || sig.S.BitLen() > keySize {
+		return nil, errors.New("malformed ECDSA signature: invalid integers")
+	}
+	raw := make([]byte, 2*size)
+	sig.R.FillBytes(raw[:size])
+	sig.S.FillBytes(raw[size:])
+	return raw, nil
+}
@@ -0,0 +1,83 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/testhelper"
+)
+
+// opaqueSigner hides the type of the private key, like the keys of the OS
+// key stores.
+type opaqueSigner struct {
+	key crypto.Signer
+}
+
+func (s opaqueSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(rand, digest, opts)
+}
+
+func TestNewGenericSignerFromCryptoSigner(t *testing.T) {
+	for _, envelopeType := range signature.RegisteredEnvelopeTypes() {
+		for _, keyCert := range keyCertPairCollections {
+			t.Run(fmt.Sprintf("envelopeType=%v_keySpec=%v", envelopeType, keyCert.keySpecName), func(t *testing.T) {
+				key := opaqueSigner{key: keyCert.key.(crypto.Signer)}
+				s, err := NewGenericSignerFromCryptoSigner(key, keyCert.certs)
+				if err != nil {
+					t.Fatalf("NewGenericSignerFromCryptoSigner() failed: %v", err)
+				}
+				desc, opts := generateSigningContent()
+				opts.SignatureMediaType = envelopeType
+				sig, _, err := s.Sign(context.Background(), desc, opts)
+				if err != nil {
+					t.Fatalf("Sign() failed: %v", err)
+				}
+				basicVerification(t, sig, envelopeType, keyCert.certs[len(keyCert.certs)-1], nil)
+			})
+		}
+	}
+}
+
+func TestNewGenericSignerFromCryptoSignerError(t *testing.T) {
+	rsaCert := testhelper.GetRSALeafCertificate()
+	ecCert := testhelper.GetECLeafCertificate()
+	if _, err := NewGenericSignerFromCryptoSigner(nil, []*x509.Certificate{rsaCert.Cert}); err == nil {
+		t.Fatal("expected error for nil key")
+	}
+	key := opaqueSigner{key: rsaCert.PrivateKey}
+	if _, err := NewGenericSignerFromCryptoSigner(key, nil); err == nil {
+		t.Fatal("expected error for empty certificate chain")
+	}
+	_, err := NewGenericSignerFromCryptoSigner(opaqueSigner{key: ecCert.PrivateKey}, []*x509.Certificate{rsaCert.Cert})
+	if err == nil || err.Error() != "key does not match the signing certificate" {
+		t.Fatalf("expected key mismatch error, got %v", err)
+	}
+}
+
+func TestRawECDSASignature(t *testing.T) {
+	if _, err := rawECDSASignature([]byte("malformed"), 256); err == nil {
+		t.Fatal("expected error for malformed signature")
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/clock"
+	"github.com/notaryproject/notation-go/log"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ErrSigningFilesChanged is returned by [FileSigner] when the key or the
+// certificate file is replaced and FileSignerOptions.FailOnChange is set.
+var ErrSigningFilesChanged = errors.New("signing key or certificate file changed")
+
+// FileSignerOptions contains the optional parameters of NewFileSigner.
+type FileSignerOptions struct {
+	// FilesOptions are used to load the key and the certificate files.
+	FilesOptions
+
+	// FailOnChange fails the signing with [ErrSigningFilesChanged] if the
+	// key or the certificate file is replaced, instead of reloading them.
+	FailOnChange bool
+}
+
+// FileSigner signs with a key and a certificate chain loaded from files. The
+// files are checked before every signature, and reloaded if they are
+// replaced, e.g. by the rotation of the certificate, so that long-running
+// processes do not sign with a stale or expired certificate.
+type FileSigner struct {
+	keyPath       string
+	certChainPath string
+	opts          FileSignerOptions
+
+	mu       sync.Mutex
+	signer   *GenericSigner
+	keyInfo  fs.FileInfo
+	certInfo fs.FileInfo
+	notAfter time.Time
+}
+
+// NewFileSigner returns a [FileSigner] given key and certChain paths.
+func NewFileSigner(keyPath, certChainPath string, opts FileSignerOptions) (*FileSigner, error) {
+	if keyPath == "" {
+		return nil, errors.New("key path not specified")
+	}
+	if certChainPath == "" {
+		return nil, errors.New("certificate path not specified")
+	}
+	s := &FileSigner{
+		keyPath:       keyPath,
+		certChainPath: certChainPath,
+		opts:          opts,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Sign signs the artifact described by its descriptor and returns the
+// signature and SignerInfo.
+func (s *FileSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	signer, err := s.current(ctx, clock.Now(opts.Clock))
+	if err != nil {
+		return nil, nil, err
+	}
+	return signer.Sign(ctx, desc, opts)
+}
+
+// SignBlob signs the descriptor returned by genDesc, and returns the
+// signature and SignerInfo.
+func (s *FileSigner) SignBlob(ctx context.Context, genDesc notation.BlobDescriptorGenerator, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	signer, err := s.current(ctx, clock.Now(opts.Clock))
+	if err != nil {
+		return nil, nil, err
+	}
+	return signer.SignBlob(ctx, genDesc, opts)
+}
+
+// current returns the signer of the current files, reloading them if they
+// are replaced, and fails if the certificate has expired at now.
+func (s *FileSigner) current(ctx context.Context, now time.Time) (*GenericSigner, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed, err := s.changed()
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if s.opts.FailOnChange {
+			return nil, fmt.Errorf("%w: %q or %q", ErrSigningFilesChanged, s.keyPath, s.certChainPath)
+		}
+		if err := s.load(); err != nil {
+			return nil, fmt.Errorf("failed to reload the signing key and certificate: %w", err)
+		}
+		log.GetLogger(ctx).Infof("Reloaded the signing key %q and certificate %q", s.keyPath, s.certChainPath)
+	}
+	if now.After(s.notAfter) {
+		return nil, fmt.Errorf("signing certificate %q expired at %s", s.certChainPath, s.notAfter.Format(time.RFC3339))
+	}
+	return s.signer, nil
+}
+
+// changed reports whether the key or the certificate file is replaced since
+// they were loaded.
+func (s *FileSigner) changed() (bool, error) {
+	keyInfo, err := os.Stat(s.keyPath)
+	if err != nil {
+		return false, err
+	}
+	certInfo, err := os.Stat(s.certChainPath)
+	if err != nil {
+		return false, err
+	}
+	return !sameFile(s.keyInfo, keyInfo) || !sameFile(s.certInfo, certInfo), nil
+}
+
+// sameFile reports whether the file is the same and unmodified.
+func sameFile(a, b fs.FileInfo) bool {
+	return os.SameFile(a, b) && a.ModTime().Equal(b.ModTime()) && a.Size() == b.Size()
+}
+
+// load loads the key and the certificate files.
+func (s *FileSigner) load() error {
+	// stat before reading, so that a replacement while reading is detected
+	// at the next signature
+	keyInfo, err := os.Stat(s.keyPath)
+	if err != nil {
+		return err
+	}
+	certInfo, err := os.Stat(s.certChainPath)
+	if err != nil {
+		return err
+	}
+	signer, err := NewGenericSignerFromFilesWithOptions(s.keyPath, s.certChainPath, s.opts.FilesOptions)
+	if err != nil {
+		return err
+	}
+	certs, err := signer.signer.(signature.LocalSigner).CertificateChain()
+	if err != nil {
+		return err
+	}
+	s.signer = signer
+	s.keyInfo = keyInfo
+	s.certInfo = certInfo
+	s.notAfter = certs[0].NotAfter
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/clock"
+)
+
+// replaceKeyCertFiles replaces the key and the certificate files with the
+// ones of keyCert.
+func replaceKeyCertFiles(t *testing.T, keyCert *keyCertPair, keyPath, certPath string) {
+	t.Helper()
+	newKeyPath, newCertPath, err := prepareTestKeyCertFile(keyCert, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(newKeyPath, keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(newCertPath, certPath); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileSignerReload(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, certPath := filepath.Join(dir, "test.key"), filepath.Join(dir, "test.crt")
+	oldKeyCert, newKeyCert := keyCertPairCollections[0], keyCertPairCollections[1]
+	replaceKeyCertFiles(t, oldKeyCert, keyPath, certPath)
+
+	s, err := NewFileSigner(keyPath, certPath, FileSignerOptions{})
+	if err != nil {
+		t.Fatalf("NewFileSigner() failed: %v", err)
+	}
+	desc, opts := generateSigningContent()
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+	_, signerInfo, err := s.Sign(context.Background(), desc, opts)
+	if err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+	if !signerInfo.CertificateChain[0].Equal(oldKeyCert.certs[0]) {
+		t.Fatal("Sign() did not sign with the loaded certificate")
+	}
+
+	replaceKeyCertFiles(t, newKeyCert, keyPath, certPath)
+	_, signerInfo, err = s.Sign(context.Background(), desc, opts)
+	if err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+	if !signerInfo.CertificateChain[0].Equal(newKeyCert.certs[0]) {
+		t.Fatal("Sign() did not sign with the reloaded certificate")
+	}
+}
+
+func TestFileSignerFailOnChange(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, certPath := filepath.Join(dir, "test.key"), filepath.Join(dir, "test.crt")
+	replaceKeyCertFiles(t, keyCertPairCollections[0], keyPath, certPath)
+
+	s, err := NewFileSigner(keyPath, certPath, FileSignerOptions{FailOnChange: true})
+	if err != nil {
+		t.Fatalf("NewFileSigner() failed: %v", err)
+	}
+	replaceKeyCertFiles(t, keyCertPairCollections[1], keyPath, certPath)
+	desc, opts := generateSigningContent()
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+	if _, _, err := s.Sign(context.Background(), desc, opts); !errors.Is(err, ErrSigningFilesChanged) {
+		t.Fatalf("Sign() error = %v, want %v", err, ErrSigningFilesChanged)
+	}
+}
+
+func TestFileSignerClock(t *testing.T) {
+	dir := t.TempDir()
+	keyPath, certPath := filepath.Join(dir, "test.key"), filepath.Join(dir, "test.crt")
+	keyCert := keyCertPairCollections[0]
+	replaceKeyCertFiles(t, keyCert, keyPath, certPath)
+
+	s, err := NewFileSigner(keyPath, certPath, FileSignerOptions{})
+	if err != nil {
+		t.Fatalf("NewFileSigner() failed: %v", err)
+	}
+	desc, opts := generateSigningContent()
+	opts.SignatureMediaType = jws.MediaTypeEnvelope
+	opts.ExpiryDuration = time.Hour
+	signingTime := keyCert.certs[0].NotBefore.Add(time.Minute)
+	opts.Clock = clock.Func(func() time.Time { return signingTime })
+	_, signerInfo, err := s.Sign(context.Background(), desc, opts)
+	if err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+	if !signerInfo.SignedAttributes.SigningTime.Equal(signingTime.Truncate(time.Second)) {
+		t.Fatalf("SigningTime = %v, want %v", signerInfo.SignedAttributes.SigningTime, signingTime)
+	}
+	if want := signingTime.Add(time.Hour).Truncate(time.Second); !signerInfo.SignedAttributes.Expiry.Equal(want) {
+		t.Fatalf("Expiry = %v, want %v", signerInfo.SignedAttributes.Expiry, want)
+	}
+
+	opts.Clock = clock.Func(func() time.Time { return keyCert.certs[0].NotAfter.Add(time.Hour) })
+	if _, _, err := s.Sign(context.Background(), desc, opts); err == nil {
+		t.Fatal("Sign() expects error for expired signing certificate")
+	}
+}
+
+func TestNewFileSignerError(t *testing.T) {
+	if _, err := NewFileSigner("", "test.crt", FileSignerOptions{}); err == nil || err.Error() != "key path not specified" {
+		t.Fatalf("expected key path error, got %v", err)
+	}
+	if _, err := NewFileSigner("test.key", "", FileSignerOptions{}); err == nil || err.Error() != "certificate path not specified" {
+		t.Fatalf("expected certificate path error, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import "crypto/x509"
+
+// KeychainOptions selects the signing identity in the macOS Keychain. Either
+// Name or SHA1 must be set.
+type KeychainOptions struct {
+	// Name is the common name of the certificate of the identity.
+	Name string
+
+	// SHA1 is the hex encoded SHA-1 thumbprint of the certificate of the
+	// identity.
+	SHA1 string
+
+	// CertificateChain is the optional chain of the certificate, from the
+	// intermediate certificates to the root certificate. If not set, the
+	// chain is built from the system keychains.
+	CertificateChain []*x509.Certificate
+}
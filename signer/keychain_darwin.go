@@ -0,0 +1,267 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin && cgo
+
+package signer
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework Security
+#include <CoreFoundation/CoreFoundation.h>
+#include <Security/Security.h>
+
+enum {
+	algRSAPSSSHA256 = 1,
+	algRSAPSSSHA384,
+	algRSAPSSSHA512,
+	algECDSASHA256,
+	algECDSASHA384,
+	algECDSASHA512,
+};
+
+static CFArrayRef copyIdentities(OSStatus *status) {
+	const void *keys[] = { kSecClass, kSecMatchLimit, kSecReturnRef };
+	const void *values[] = { kSecClassIdentity, kSecMatchLimitAll, kCFBooleanTrue };
+	CFDictionaryRef query = CFDictionaryCreate(NULL, keys, values, 3,
+		&kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+	CFTypeRef result = NULL;
+	*status = SecItemCopyMatching(query, &result);
+	CFRelease(query);
+	return (CFArrayRef)result;
+}
+
+static SecIdentityRef identityAtIndex(CFArrayRef identities, CFIndex i) {
+	return (SecIdentityRef)CFArrayGetValueAtIndex(identities, i);
+}
+
+static CFDataRef copyCertificateData(SecIdentityRef identity) {
+	SecCertificateRef cert = NULL;
+	if (SecIdentityCopyCertificate(identity, &cert) != errSecSuccess) {
+		return NULL;
+	}
+	CFDataRef data = SecCertificateCopyData(cert);
+	CFRelease(cert);
+	return data;
+}
+
+static SecKeyRef copyPrivateKey(SecIdentityRef identity) {
+	SecKeyRef key = NULL;
+	if (SecIdentityCopyPrivateKey(identity, &key) != errSecSuccess) {
+		return NULL;
+	}
+	return key;
+}
+
+static CFDataRef createSignature(SecKeyRef key, int alg, const UInt8 *digest, CFIndex length, CFIndex *code) {
+	SecKeyAlgorithm algorithm;
+	switch (alg) {
+	case algRSAPSSSHA256: algorithm = kSecKeyAlgorithmRSASignatureDigestPSSSHA256; break;
+	case algRSAPSSSHA384: algorithm = kSecKeyAlgorithmRSASignatureDigestPSSSHA384; break;
+	case algRSAPSSSHA512: algorithm = kSecKeyAlgorithmRSASignatureDigestPSSSHA512; break;
+	case algECDSASHA256: algorithm = kSecKeyAlgorithmECDSASignatureDigestX962SHA256; break;
+	case algECDSASHA384: algorithm = kSecKeyAlgorithmECDSASignatureDigestX962SHA384; break;
+	case algECDSASHA512: algorithm = kSecKeyAlgorithmECDSASignatureDigestX962SHA512; break;
+	default: *code = errSecParam; return NULL;
+	}
+	CFDataRef data = CFDataCreate(NULL, digest, length);
+	CFErrorRef error = NULL;
+	CFDataRef signature = SecKeyCreateSignature(key, algorithm, data, &error);
+	CFRelease(data);
+	if (signature == NULL) {
+		*code = error != NULL ? CFErrorGetCode(error) : errSecInternalError;
+		if (error != NULL) {
+			CFRelease(error);
+		}
+	}
+	return signature;
+}
+*/
+import "C"
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"unsafe"
+)
+
+// The signature algorithms of createSignature, which must match the values of
+// the C enum.
+const (
+	algRSAPSSSHA256 = iota + 1
+	algRSAPSSSHA384
+	algRSAPSSSHA512
+	algECDSASHA256
+	algECDSASHA384
+	algECDSASHA512
+)
+
+// NewKeychainSigner returns a builtinSigner signing with an identity of the
+// macOS Keychain, such as an identity of the Secure Enclave. The signatures
+// are performed by the Security framework, so that the private key never
+// leaves the keychain.
+func NewKeychainSigner(opts KeychainOptions) (*GenericSigner, error) {
+	if opts.Name == "" && opts.SHA1 == "" {
+		return nil, errors.New("either the name or the SHA-1 thumbprint of the identity must be specified")
+	}
+	var thumbprint []byte
+	if opts.SHA1 != "" {
+		var err error
+		thumbprint, err = hex.DecodeString(strings.ReplaceAll(opts.SHA1, " ", ""))
+		if err != nil || len(thumbprint) != sha1.Size {
+			return nil, fmt.Errorf("invalid SHA-1 thumbprint %q", opts.SHA1)
+		}
+	}
+	key, err := findKeychainKey(opts.Name, thumbprint)
+	if err != nil {
+		return nil, err
+	}
+	chain := []*x509.Certificate{key.cert}
+	if opts.CertificateChain != nil {
+		chain = append(chain, opts.CertificateChain...)
+	} else {
+		chains, err := key.cert.Verify(x509.VerifyOptions{
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the certificate chain: %w", err)
+		}
+		chain = chains[0]
+	}
+	return NewGenericSignerFromCryptoSigner(key, chain)
+}
+
+// keychainKey is a crypto.Signer signing with the private key of an identity
+// of the macOS Keychain.
+type keychainKey struct {
+	ref  C.SecKeyRef
+	cert *x509.Certificate
+}
+
+// findKeychainKey finds the identity matching the name or the thumbprint.
+func findKeychainKey(name string, thumbprint []byte) (*keychainKey, error) {
+	var status C.OSStatus
+	identities := C.copyIdentities(&status)
+	if status == C.errSecItemNotFound {
+		return nil, errors.New("no identity found in the keychain")
+	}
+	if status != C.errSecSuccess {
+		return nil, fmt.Errorf("failed to search the keychain: OSStatus %d", int(status))
+	}
+	defer C.CFRelease(C.CFTypeRef(identities))
+
+	for i := C.CFIndex(0); i < C.CFArrayGetCount(identities); i++ {
+		identity := C.identityAtIndex(identities, i)
+		data := C.copyCertificateData(identity)
+		if data == 0 {
+			continue
+		}
+		der := C.GoBytes(unsafe.Pointer(C.CFDataGetBytePtr(data)), C.int(C.CFDataGetLength(data)))
+		C.CFRelease(C.CFTypeRef(data))
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			continue
+		}
+		if thumbprint != nil {
+			if sum := sha1.Sum(cert.Raw); string(sum[:]) != string(thumbprint) {
+				continue
+			}
+		}
+		if name != "" && cert.Subject.CommonName != name {
+			continue
+		}
+		ref := C.copyPrivateKey(identity)
+		if ref == 0 {
+			continue
+		}
+		key := &keychainKey{
+			ref:  ref,
+			cert: cert,
+		}
+		runtime.SetFinalizer(key, func(k *keychainKey) {
+			C.CFRelease(C.CFTypeRef(k.ref))
+		})
+		return key, nil
+	}
+	return nil, errors.New("no matching identity found in the keychain")
+}
+
+// Public returns the public key of the certificate.
+func (k *keychainKey) Public() crypto.PublicKey {
+	return k.cert.PublicKey
+}
+
+// Sign signs the digest with SecKeyCreateSignature. RSA keys sign with
+// RSASSA-PSS only.
+func (k *keychainKey) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	defer runtime.KeepAlive(k)
+	alg, err := keychainAlgorithm(k.cert.PublicKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(digest) == 0 {
+		return nil, errors.New("digest cannot be empty")
+	}
+
+	var code C.CFIndex
+	sig := C.createSignature(k.ref, C.int(alg), (*C.UInt8)(unsafe.Pointer(&digest[0])), C.CFIndex(len(digest)), &code)
+	if sig == 0 {
+		return nil, fmt.Errorf("keychain signing failed: error %d", int(code))
+	}
+	defer C.CFRelease(C.CFTypeRef(sig))
+	// the ECDSA signatures are ASN.1 encoded (X9.62), as crypto.Signer
+	return C.GoBytes(unsafe.Pointer(C.CFDataGetBytePtr(sig)), C.int(C.CFDataGetLength(sig))), nil
+}
+
+// keychainAlgorithm returns the signature algorithm of createSignature for
+// the public key and the signer options.
+func keychainAlgorithm(pub crypto.PublicKey, opts crypto.SignerOpts) (int, error) {
+	var alg int
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		if _, ok := opts.(*rsa.PSSOptions); !ok {
+			return 0, errors.New("only RSASSA-PSS is supported")
+		}
+		switch opts.HashFunc() {
+		case crypto.SHA256:
+			alg = algRSAPSSSHA256
+		case crypto.SHA384:
+			alg = algRSAPSSSHA384
+		case crypto.SHA512:
+			alg = algRSAPSSSHA512
+		}
+	case *ecdsa.PublicKey:
+		switch opts.HashFunc() {
+		case crypto.SHA256:
+			alg = algECDSASHA256
+		case crypto.SHA384:
+			alg = algECDSASHA384
+		case crypto.SHA512:
+			alg = algECDSASHA512
+		}
+	default:
+		return 0, fmt.Errorf("unsupported public key type %T", pub)
+	}
+	if alg == 0 {
+		return 0, fmt.Errorf("unsupported hash algorithm %v", opts.HashFunc())
+	}
+	return alg, nil
+}
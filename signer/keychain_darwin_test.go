@@ -0,0 +1,99 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin && cgo
+
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"testing"
+)
+
+func TestNewKeychainSignerOptions(t *testing.T) {
+	tests := map[string]KeychainOptions{
+		"no identity":      {},
+		"invalid hex":      {SHA1: "not a thumbprint"},
+		"short thumbprint": {SHA1: "0102030405"},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewKeychainSigner(opts); err == nil {
+				t.Fatal("NewKeychainSigner() expected error")
+			}
+		})
+	}
+}
+
+func TestKeychainAlgorithm(t *testing.T) {
+	rsaKey := &rsa.PublicKey{}
+	ecKey := &ecdsa.PublicKey{}
+	tests := []struct {
+		name string
+		pub  crypto.PublicKey
+		opts crypto.SignerOpts
+		want int
+	}{
+		{"RSA-PSS SHA-256", rsaKey, &rsa.PSSOptions{Hash: crypto.SHA256}, algRSAPSSSHA256},
+		{"RSA-PSS SHA-384", rsaKey, &rsa.PSSOptions{Hash: crypto.SHA384}, algRSAPSSSHA384},
+		{"RSA-PSS SHA-512", rsaKey, &rsa.PSSOptions{Hash: crypto.SHA512}, algRSAPSSSHA512},
+		{"ECDSA SHA-256", ecKey, crypto.SHA256, algECDSASHA256},
+		{"ECDSA SHA-384", ecKey, crypto.SHA384, algECDSASHA384},
+		{"ECDSA SHA-512", ecKey, crypto.SHA512, algECDSASHA512},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keychainAlgorithm(tt.pub, tt.opts)
+			if err != nil {
+				t.Fatalf("keychainAlgorithm() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("keychainAlgorithm() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeychainAlgorithmErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		pub  crypto.PublicKey
+		opts crypto.SignerOpts
+	}{
+		{"RSA PKCS #1 v1.5", &rsa.PublicKey{}, crypto.SHA256},
+		{"RSA-PSS SHA-1", &rsa.PublicKey{}, &rsa.PSSOptions{Hash: crypto.SHA1}},
+		{"ECDSA SHA-1", &ecdsa.PublicKey{}, crypto.SHA1},
+		{"Ed25519", ed25519.PublicKey{}, crypto.Hash(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := keychainAlgorithm(tt.pub, tt.opts); err == nil {
+				t.Fatal("keychainAlgorithm() expected error")
+			}
+		})
+	}
+}
+
+func TestKeychainKeySignErrors(t *testing.T) {
+	key := &keychainKey{cert: &x509.Certificate{PublicKey: &ecdsa.PublicKey{}}}
+	if _, err := key.Sign(nil, nil, crypto.SHA256); err == nil {
+		t.Fatal("Sign() with empty digest expected error")
+	}
+	if _, err := key.Sign(nil, make([]byte, 32), crypto.SHA1); err == nil {
+		t.Fatal("Sign() with SHA-1 expected error")
+	}
+}
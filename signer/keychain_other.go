@@ -0,0 +1,27 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !darwin || !cgo
+
+package signer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NewKeychainSigner returns a builtinSigner signing with an identity of the
+// macOS Keychain. It is only supported on macOS with cgo enabled.
+func NewKeychainSigner(opts KeychainOptions) (*GenericSigner, error) {
+	return nil, fmt.Errorf("macOS keychain: %w", errors.ErrUnsupported)
+}
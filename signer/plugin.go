@@ -14,18 +14,22 @@
 package signer
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"oras.land/oras-go/v2/content"
 
 	"github.com/notaryproject/notation-core-go/signature"
 	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/fips"
 	"github.com/notaryproject/notation-go/internal/envelope"
 	"github.com/notaryproject/notation-go/log"
 	"github.com/notaryproject/notation-go/plugin/proto"
@@ -41,9 +45,24 @@ type PluginSigner struct {
 	plugin              plugin.SignPlugin
 	keyID               string
 	pluginConfig        map[string]string
+	configResolver      PluginConfigResolver
 	manifestAnnotations map[string]string
 }
 
+// PluginConfigResolver resolves the values of the plugin config before each
+// signing operation, e.g. the secret references of a signing key resolved by
+// the SecretResolvers of the config package. It returns the resolved config,
+// leaving config unmodified.
+type PluginConfigResolver func(ctx context.Context, config map[string]string) (map[string]string, error)
+
+// PluginSignerOptions contains the options of [NewPluginSignerWithOptions].
+type PluginSignerOptions struct {
+	// PluginConfigResolver, if set, resolves the plugin config merged with
+	// the plugin config of the signing options before each signing
+	// operation, so that secrets are only resolved when needed.
+	PluginConfigResolver PluginConfigResolver
+}
+
 var algorithms = map[crypto.Hash]digest.Algorithm{
 	crypto.SHA256: digest.SHA256,
 	crypto.SHA384: digest.SHA384,
@@ -77,6 +96,17 @@ func NewPluginSigner(plugin plugin.SignPlugin, keyID string, pluginConfig map[st
 	}, nil
 }
 
+// NewPluginSignerWithOptions creates a [PluginSigner] like [NewPluginSigner]
+// with user specified options.
+func NewPluginSignerWithOptions(plugin plugin.SignPlugin, keyID string, pluginConfig map[string]string, opts PluginSignerOptions) (*PluginSigner, error) {
+	s, err := NewPluginSigner(plugin, keyID, pluginConfig)
+	if err != nil {
+		return nil, err
+	}
+	s.configResolver = opts.PluginConfigResolver
+	return s, nil
+}
+
 // PluginAnnotations returns signature manifest annotations returned from plugin
 func (s *PluginSigner) PluginAnnotations() map[string]string {
 	return s.manifestAnnotations
@@ -86,14 +116,18 @@ func (s *PluginSigner) PluginAnnotations() map[string]string {
 // signature and SignerInfo.
 func (s *PluginSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
 	logger := log.GetLogger(ctx)
-	mergedConfig := s.mergeConfig(opts.PluginConfig)
+	mergedConfig, err := s.mergeConfig(ctx, opts.PluginConfig)
+	if err != nil {
+		return nil, nil, err
+	}
 	logger.Debug("Invoking plugin's get-plugin-metadata command")
 	metadata, err := s.plugin.GetMetadata(ctx, &plugin.GetMetadataRequest{PluginConfig: mergedConfig})
 	if err != nil {
 		return nil, nil, err
 	}
 	logger.Debugf("Using plugin %v with capabilities %v to sign oci artifact %v in signature media type %v", metadata.Name, metadata.Capabilities, desc.Digest, opts.SignatureMediaType)
-	if metadata.HasCapability(plugin.CapabilitySignatureGenerator) {
+	s.auditKeyLoaded(ctx, metadata.Name, desc)
+	if hasSignatureGeneratorCapability(metadata) {
 		ks, err := s.getKeySpec(ctx, mergedConfig)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to sign with the plugin %s: %w", metadata.Name, err)
@@ -104,7 +138,7 @@ func (s *PluginSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts n
 		}
 		return sig, signerInfo, nil
 	} else if metadata.HasCapability(plugin.CapabilityEnvelopeGenerator) {
-		sig, signerInfo, err := s.generateSignatureEnvelope(ctx, desc, opts)
+		sig, signerInfo, err := s.generateSignatureEnvelope(ctx, desc, opts, mergedConfig)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to sign with the plugin %s: %w", metadata.Name, err)
 		}
@@ -117,7 +151,10 @@ func (s *PluginSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts n
 // signature and SignerInfo.
 func (s *PluginSigner) SignBlob(ctx context.Context, descGenFunc notation.BlobDescriptorGenerator, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
 	logger := log.GetLogger(ctx)
-	mergedConfig := s.mergeConfig(opts.PluginConfig)
+	mergedConfig, err := s.mergeConfig(ctx, opts.PluginConfig)
+	if err != nil {
+		return nil, nil, err
+	}
 	logger.Debug("Invoking plugin's get-plugin-metadata command")
 	metadata, err := s.plugin.GetMetadata(ctx, &plugin.GetMetadataRequest{PluginConfig: mergedConfig})
 	if err != nil {
@@ -135,14 +172,26 @@ func (s *PluginSigner) SignBlob(ctx context.Context, descGenFunc notation.BlobDe
 		return nil, nil, err
 	}
 	logger.Debugf("Using plugin %v with capabilities %v to sign blob using descriptor %+v", metadata.Name, metadata.Capabilities, desc)
-	if metadata.HasCapability(plugin.CapabilitySignatureGenerator) {
+	s.auditKeyLoaded(ctx, metadata.Name, desc)
+	if hasSignatureGeneratorCapability(metadata) {
 		return s.generateSignature(ctx, desc, opts, ks, metadata, mergedConfig)
 	} else if metadata.HasCapability(plugin.CapabilityEnvelopeGenerator) {
-		return s.generateSignatureEnvelope(ctx, desc, opts)
+		return s.generateSignatureEnvelope(ctx, desc, opts, mergedConfig)
 	}
 	return nil, nil, fmt.Errorf("plugin does not have signing capabilities")
 }
 
+// auditKeyLoaded emits the audit event of the signing key of the plugin used
+// to sign the artifact.
+func (s *PluginSigner) auditKeyLoaded(ctx context.Context, pluginName string, desc ocispec.Descriptor) {
+	audit.Emit(ctx, audit.Event{
+		Type:     audit.EventKeyLoaded,
+		Artifact: desc.Digest.String(),
+		Plugin:   pluginName,
+		KeyID:    s.keyID,
+	})
+}
+
 func (s *PluginSigner) getKeySpec(ctx context.Context, config map[string]string) (signature.KeySpec, error) {
 	logger := log.GetLogger(ctx)
 	logger.Debug("Invoking plugin's describe-key command")
@@ -166,13 +215,14 @@ func (s *PluginSigner) generateSignature(ctx context.Context, desc ocispec.Descr
 			keyID:        s.keyID,
 			pluginConfig: pluginConfig,
 			keySpec:      ks,
+			stream:       metadata.HasCapability(proto.CapabilityStreamingSignatureGenerator),
 		},
 	}
-	opts.SigningAgent = fmt.Sprintf("%s %s/%s", signingAgent, metadata.Name, metadata.Version)
+	opts.SigningAgent = fmt.Sprintf("%s %s/%s", defaultSigningAgent(), metadata.Name, metadata.Version)
 	return genericSigner.Sign(ctx, desc, opts)
 }
 
-func (s *PluginSigner) generateSignatureEnvelope(ctx context.Context, desc ocispec.Descriptor, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+func (s *PluginSigner) generateSignatureEnvelope(ctx context.Context, desc ocispec.Descriptor, opts notation.SignerSignOptions, pluginConfig map[string]string) ([]byte, *signature.SignerInfo, error) {
 	logger := log.GetLogger(ctx)
 	logger.Debug("Generating signature envelope by plugin")
 	payload := envelope.Payload{TargetArtifact: envelope.SanitizeTargetArtifact(desc)}
@@ -189,7 +239,7 @@ func (s *PluginSigner) generateSignatureEnvelope(ctx context.Context, desc ocisp
 		SignatureEnvelopeType:   opts.SignatureMediaType,
 		PayloadType:             envelope.MediaTypePayloadV1,
 		ExpiryDurationInSeconds: uint64(opts.ExpiryDuration / time.Second),
-		PluginConfig:            s.mergeConfig(opts.PluginConfig),
+		PluginConfig:            pluginConfig,
 	}
 	resp, err := s.plugin.GenerateEnvelope(ctx, req)
 	if err != nil {
@@ -215,6 +265,11 @@ func (s *PluginSigner) generateSignatureEnvelope(ctx context.Context, desc ocisp
 	if err := envelope.ValidatePayloadContentType(&envContent.Payload); err != nil {
 		return nil, nil, err
 	}
+	if fips.Enabled() {
+		if err := fips.ValidateSignerInfo(&envContent.SignerInfo); err != nil {
+			return nil, nil, err
+		}
+	}
 	content := envContent.Payload.Content
 	var signedPayload envelope.Payload
 	if err = json.Unmarshal(content, &signedPayload); err != nil {
@@ -230,7 +285,7 @@ func (s *PluginSigner) generateSignatureEnvelope(ctx context.Context, desc ocisp
 	return resp.SignatureEnvelope, &envContent.SignerInfo, nil
 }
 
-func (s *PluginSigner) mergeConfig(config map[string]string) map[string]string {
+func (s *PluginSigner) mergeConfig(ctx context.Context, config map[string]string) (map[string]string, error) {
 	c := make(map[string]string, len(s.pluginConfig)+len(config))
 
 	// First clone s.PluginConfig.
@@ -242,7 +297,16 @@ func (s *PluginSigner) mergeConfig(config map[string]string) map[string]string {
 	for k, v := range config {
 		c[k] = v
 	}
-	return c
+
+	// Finally resolve the entries, e.g. the secret references.
+	if s.configResolver != nil {
+		resolved, err := s.configResolver(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve plugin config: %w", err)
+		}
+		return resolved, nil
+	}
+	return c, nil
 }
 
 func (s *PluginSigner) describeKey(ctx context.Context, config map[string]string) (*plugin.DescribeKeyResponse, error) {
@@ -323,6 +387,22 @@ func parseCertChain(certChain [][]byte) ([]*x509.Certificate, error) {
 	return certs, nil
 }
 
+// hasSignatureGeneratorCapability returns true if the plugin generates raw
+// signatures, with or without streaming the payload.
+func hasSignatureGeneratorCapability(metadata *plugin.GetMetadataResponse) bool {
+	return metadata.HasCapability(plugin.CapabilitySignatureGenerator) ||
+		metadata.HasCapability(proto.CapabilityStreamingSignatureGenerator)
+}
+
+// streamSignPlugin is implemented by plugins supporting the
+// generate-signature-stream command, such as
+// github.com/notaryproject/notation-go/plugin.CLIPlugin.
+type streamSignPlugin interface {
+	// GenerateSignatureStream generates the raw signature of the payload
+	// read from payload, streaming the payload to the plugin.
+	GenerateSignatureStream(ctx context.Context, req *proto.GenerateSignatureStreamRequest, payload io.Reader) (*plugin.GenerateSignatureResponse, error)
+}
+
 // pluginPrimitiveSigner implements signature.Signer
 type pluginPrimitiveSigner struct {
 	ctx          context.Context
@@ -330,6 +410,10 @@ type pluginPrimitiveSigner struct {
 	keyID        string
 	pluginConfig map[string]string
 	keySpec      signature.KeySpec
+
+	// stream is true if the plugin has the SIGNATURE_GENERATOR.STREAM
+	// capability.
+	stream bool
 }
 
 // Sign signs the digest by calling the underlying plugin.
@@ -351,7 +435,19 @@ func (s *pluginPrimitiveSigner) Sign(payload []byte) ([]byte, []*x509.Certificat
 		Payload:         payload,
 		PluginConfig:    s.pluginConfig,
 	}
-	resp, err := s.plugin.GenerateSignature(s.ctx, req)
+	var resp *plugin.GenerateSignatureResponse
+	if streamPlugin, ok := s.plugin.(streamSignPlugin); ok && s.stream {
+		// stream the payload instead of embedding it in the request
+		resp, err = streamPlugin.GenerateSignatureStream(s.ctx, &proto.GenerateSignatureStreamRequest{
+			ContractVersion: req.ContractVersion,
+			KeyID:           req.KeyID,
+			KeySpec:         req.KeySpec,
+			Hash:            req.Hash,
+			PluginConfig:    req.PluginConfig,
+		}, bytes.NewReader(payload))
+	} else {
+		resp, err = s.plugin.GenerateSignature(s.ctx, req)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
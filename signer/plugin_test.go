@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
@@ -65,6 +66,8 @@ type mockPlugin struct {
 	invalidSig        bool
 	invalidCertChain  bool
 	invalidDescriptor bool
+	stream            bool
+	streamed          bool
 	annotations       map[string]string
 	key               crypto.PrivateKey
 	certs             []*x509.Certificate
@@ -98,6 +101,14 @@ func (p *mockPlugin) GetMetadata(ctx context.Context, req *proto.GetMetadataRequ
 			Capabilities:              []proto.Capability{proto.CapabilityEnvelopeGenerator},
 		}, nil
 	}
+	if p.stream {
+		return &proto.GetMetadataResponse{
+			Name:                      "testPlugin",
+			Version:                   "1.0",
+			SupportedContractVersions: []string{proto.ContractVersion},
+			Capabilities:              []proto.Capability{proto.CapabilityStreamingSignatureGenerator},
+		}, nil
+	}
 	return &proto.GetMetadataResponse{
 		Name:                      "testPlugin",
 		Version:                   "1.0",
@@ -145,6 +156,23 @@ func (p *mockPlugin) GenerateSignature(ctx context.Context, req *proto.GenerateS
 	}, nil
 }
 
+// GenerateSignatureStream generates the raw signature of the streamed payload.
+func (p *mockPlugin) GenerateSignatureStream(ctx context.Context, req *proto.GenerateSignatureStreamRequest, payload io.Reader) (*proto.GenerateSignatureResponse, error) {
+	p.streamed = true
+	content, err := io.ReadAll(payload)
+	if err != nil {
+		return nil, err
+	}
+	return p.GenerateSignature(ctx, &proto.GenerateSignatureRequest{
+		ContractVersion: req.ContractVersion,
+		KeyID:           req.KeyID,
+		KeySpec:         req.KeySpec,
+		Hash:            req.Hash,
+		Payload:         content,
+		PluginConfig:    req.PluginConfig,
+	})
+}
+
 // GenerateEnvelope generates the Envelope with signature based on the request.
 func (p *mockPlugin) GenerateEnvelope(ctx context.Context, req *proto.GenerateEnvelopeRequest) (*proto.GenerateEnvelopeResponse, error) {
 	internalPluginSigner := PluginSigner{
@@ -363,6 +391,96 @@ func TestPluginSigner_SignBlob_Valid(t *testing.T) {
 	}
 }
 
+// configRecordingPlugin records the plugin config of the metadata request.
+type configRecordingPlugin struct {
+	*mockPlugin
+	config map[string]string
+}
+
+func (p *configRecordingPlugin) GetMetadata(ctx context.Context, req *proto.GetMetadataRequest) (*proto.GetMetadataResponse, error) {
+	p.config = req.PluginConfig
+	return p.mockPlugin.GetMetadata(ctx, req)
+}
+
+func (p *configRecordingPlugin) DescribeKey(ctx context.Context, req *proto.DescribeKeyRequest) (*proto.DescribeKeyResponse, error) {
+	resp, err := p.mockPlugin.DescribeKey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.KeyID = req.KeyID
+	return resp, nil
+}
+
+func TestPluginSigner_PluginConfigResolver(t *testing.T) {
+	keyCert := keyCertPairCollections[0]
+	keySpec, _ := proto.DecodeKeySpec(proto.KeySpec(keyCert.keySpecName))
+	p := &configRecordingPlugin{mockPlugin: newMockPlugin(keyCert.key, keyCert.certs, keySpec)}
+	resolver := func(ctx context.Context, config map[string]string) (map[string]string, error) {
+		resolved := make(map[string]string, len(config))
+		for k, v := range config {
+			if v == "env://TOKEN" {
+				v = "secret"
+			}
+			resolved[k] = v
+		}
+		return resolved, nil
+	}
+	pluginSigner, err := NewPluginSignerWithOptions(p, "keyID", map[string]string{"token": "env://TOKEN"}, PluginSignerOptions{PluginConfigResolver: resolver})
+	if err != nil {
+		t.Fatalf("NewPluginSignerWithOptions() error = %v", err)
+	}
+	opts := validSignOpts
+	opts.SignatureMediaType = signature.RegisteredEnvelopeTypes()[0]
+	if _, _, err := pluginSigner.Sign(context.Background(), validSignDescriptor, opts); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if p.config["token"] != "secret" {
+		t.Fatalf("plugin config token = %q, want the resolved secret", p.config["token"])
+	}
+
+	// resolution failure
+	pluginSigner.configResolver = func(ctx context.Context, config map[string]string) (map[string]string, error) {
+		return nil, errors.New("secret not found")
+	}
+	if _, _, err := pluginSigner.Sign(context.Background(), validSignDescriptor, opts); err == nil {
+		t.Fatal("Sign() expects error when the plugin config cannot be resolved")
+	}
+	if _, _, err := pluginSigner.SignBlob(context.Background(), getDescriptorFunc(false), opts); err == nil {
+		t.Fatal("SignBlob() expects error when the plugin config cannot be resolved")
+	}
+}
+
+func TestPluginSigner_Sign_Stream(t *testing.T) {
+	for _, envelopeType := range signature.RegisteredEnvelopeTypes() {
+		t.Run(fmt.Sprintf("envelopeType=%v", envelopeType), func(t *testing.T) {
+			mockPlugin := newMockPlugin(defaultKeyCert.key, defaultKeyCert.certs, defaultKeySpec)
+			mockPlugin.stream = true
+			pluginSigner := PluginSigner{
+				plugin: mockPlugin,
+			}
+			validSignOpts.SignatureMediaType = envelopeType
+			data, signerInfo, err := pluginSigner.Sign(context.Background(), validSignDescriptor, validSignOpts)
+			basicSignTest(t, &pluginSigner, envelopeType, data, signerInfo, err)
+			if !mockPlugin.streamed {
+				t.Fatal("expected the payload to be streamed to the plugin")
+			}
+		})
+	}
+}
+
+func TestPluginSigner_Sign_NoStream(t *testing.T) {
+	mockPlugin := newMockPlugin(defaultKeyCert.key, defaultKeyCert.certs, defaultKeySpec)
+	pluginSigner := PluginSigner{
+		plugin: mockPlugin,
+	}
+	validSignOpts.SignatureMediaType = signature.RegisteredEnvelopeTypes()[0]
+	data, signerInfo, err := pluginSigner.Sign(context.Background(), validSignDescriptor, validSignOpts)
+	basicSignTest(t, &pluginSigner, validSignOpts.SignatureMediaType, data, signerInfo, err)
+	if mockPlugin.streamed {
+		t.Fatal("expected the payload not to be streamed without the SIGNATURE_GENERATOR.STREAM capability")
+	}
+}
+
 func TestPluginSigner_SignEnvelope_RunFailed(t *testing.T) {
 	for _, envelopeType := range signature.RegisteredEnvelopeTypes() {
 		t.Run(fmt.Sprintf("envelopeType=%v", envelopeType), func(t *testing.T) {
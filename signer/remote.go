@@ -0,0 +1,239 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-go/signer/remote"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// maxRemoteResponseBytes is the maximum size of the responses of the remote
+// signing service.
+const maxRemoteResponseBytes = 1 << 20
+
+// RemoteSignerOptions contains the optional parameters of NewRemoteSigner.
+type RemoteSignerOptions struct {
+	// Client is the HTTP client, e.g. with TLS client certificates. Defaults
+	// to [http.DefaultClient].
+	Client *http.Client
+
+	// Token returns the bearer token authenticating the requests, if set.
+	Token func(ctx context.Context) (string, error)
+}
+
+// RemoteSigner signs artifacts with a key of a remote signing service
+// implementing the HTTP signing protocol of package
+// [github.com/notaryproject/notation-go/signer/remote].
+type RemoteSigner struct {
+	endpoint *url.URL
+	keyID    string
+	client   *http.Client
+	token    func(ctx context.Context) (string, error)
+}
+
+// NewRemoteSigner creates a [RemoteSigner] signing with the key keyID of the
+// remote signing service at the HTTPS endpoint.
+func NewRemoteSigner(endpoint, keyID string, opts RemoteSignerOptions) (*RemoteSigner, error) {
+	if keyID == "" {
+		return nil, errors.New("keyID not specified")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signing endpoint: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid remote signing endpoint %q: an https URL is required", endpoint)
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RemoteSigner{
+		endpoint: u,
+		keyID:    keyID,
+		client:   client,
+		token:    opts.Token,
+	}, nil
+}
+
+// Sign signs the artifact described by its descriptor and returns the
+// signature and SignerInfo.
+func (s *RemoteSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	primitiveSigner, err := s.primitiveSigner(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.GetLogger(ctx).Debugf("Signing oci artifact %v with remote key %v of %v", desc.Digest, s.keyID, s.endpoint.Host)
+	genericSigner := GenericSigner{signer: primitiveSigner}
+	return genericSigner.Sign(ctx, desc, opts)
+}
+
+// SignBlob signs the descriptor returned by genDesc, and returns the
+// signature and SignerInfo.
+func (s *RemoteSigner) SignBlob(ctx context.Context, genDesc notation.BlobDescriptorGenerator, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	primitiveSigner, err := s.primitiveSigner(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	desc, err := getDescriptor(primitiveSigner.keySpec, genDesc)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.GetLogger(ctx).Debugf("Signing blob %v with remote key %v of %v", desc.Digest, s.keyID, s.endpoint.Host)
+	genericSigner := GenericSigner{signer: primitiveSigner}
+	return genericSigner.Sign(ctx, desc, opts)
+}
+
+// primitiveSigner describes the key and returns the signature.Signer of the
+// key.
+func (s *RemoteSigner) primitiveSigner(ctx context.Context) (*remotePrimitiveSigner, error) {
+	var resp remote.DescribeKeyResponse
+	if err := s.do(ctx, http.MethodGet, remote.DescribeKeyPath(s.keyID), nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.KeyID != s.keyID {
+		return nil, fmt.Errorf("keyID in describe key response %q does not match request %q", resp.KeyID, s.keyID)
+	}
+	keySpec, err := proto.DecodeKeySpec(resp.KeySpec)
+	if err != nil {
+		return nil, err
+	}
+	return &remotePrimitiveSigner{
+		ctx:     ctx,
+		signer:  s,
+		keySpec: keySpec,
+	}, nil
+}
+
+// do sends the request with the JSON body in, and decodes the JSON response
+// to out.
+func (s *RemoteSigner) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		content, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(content)
+	}
+	u := s.endpoint.JoinPath(path)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != nil {
+		token, err := s.token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get the token of the remote signing service: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteResponseBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(content) > maxRemoteResponseBytes {
+		return fmt.Errorf("response of the remote signing service exceeds %d bytes", maxRemoteResponseBytes)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		errResp := &remote.ErrorResponse{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(content, errResp); err != nil || errResp.ErrorMessage == "" {
+			errResp.ErrorMessage = http.StatusText(resp.StatusCode)
+		}
+		return errResp
+	}
+	if err := json.Unmarshal(content, out); err != nil {
+		return fmt.Errorf("malformed response of the remote signing service: %w", err)
+	}
+	return nil
+}
+
+// remotePrimitiveSigner implements signature.Signer with the sign endpoint of
+// the remote signing service.
+type remotePrimitiveSigner struct {
+	ctx     context.Context
+	signer  *RemoteSigner
+	keySpec signature.KeySpec
+}
+
+// Sign sends the digest of the payload to the remote signing service, and
+// returns the raw signature and the cert chain.
+func (s *remotePrimitiveSigner) Sign(payload []byte) ([]byte, []*x509.Certificate, error) {
+	keySpec, err := proto.EncodeKeySpec(s.keySpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashAlgorithm, err := proto.HashAlgorithmFromKeySpec(s.keySpec)
+	if err != nil {
+		return nil, nil, err
+	}
+	signingAlgorithm, err := proto.EncodeSigningAlgorithm(s.keySpec.SignatureAlgorithm())
+	if err != nil {
+		return nil, nil, err
+	}
+	hash := s.keySpec.SignatureAlgorithm().Hash()
+	h := hash.New()
+	h.Write(payload)
+
+	req := remote.SignRequest{
+		KeyID:   s.signer.keyID,
+		KeySpec: keySpec,
+		Hash:    hashAlgorithm,
+		Digest:  h.Sum(nil),
+	}
+	var resp remote.SignResponse
+	if err := s.signer.do(s.ctx, http.MethodPost, remote.SignPath(s.signer.keyID), req, &resp); err != nil {
+		return nil, nil, err
+	}
+	if resp.KeyID != req.KeyID {
+		return nil, nil, fmt.Errorf("keyID in sign response %q does not match request %q", resp.KeyID, req.KeyID)
+	}
+	if resp.SigningAlgorithm != signingAlgorithm {
+		return nil, nil, fmt.Errorf("signing algorithm in sign response %q does not match key spec %q", resp.SigningAlgorithm, keySpec)
+	}
+	certs, err := parseCertChain(resp.CertificateChain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Signature, certs, nil
+}
+
+// KeySpec returns the key spec of the remote key.
+func (s *remotePrimitiveSigner) KeySpec() (signature.KeySpec, error) {
+	return s.keySpec, nil
+}
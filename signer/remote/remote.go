@@ -0,0 +1,116 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package remote defines the HTTP signing protocol of the remote signing
+// services. The client of the protocol is the RemoteSigner of package signer.
+//
+// The protocol has two JSON endpoints, relative to the base URL of the
+// service:
+//
+//   - GET /v1/keys/{keyId} returns the DescribeKeyResponse of the key.
+//   - POST /v1/keys/{keyId}/sign signs the digest of the SignRequest and
+//     returns the SignResponse.
+//
+// The keys, the hash algorithms and the signing algorithms are encoded as in
+// the notation plugin protocol, e.g. "RSA-3072", "SHA-384" and
+// "RSASSA-PSS-SHA-384". The RSA signatures are RSASSA-PSS signatures with the
+// salt length equal to the hash length. The ECDSA signatures are the
+// concatenation of R and S. The binary fields are base64 encoded.
+//
+// The requests are authenticated with a bearer token in the Authorization
+// header, or with TLS client certificates. The failed requests return a
+// non-2xx status code and an ErrorResponse.
+package remote
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// DescribeKeyPath returns the path of the describe key endpoint of the key.
+func DescribeKeyPath(keyID string) string {
+	return "/v1/keys/" + url.PathEscape(keyID)
+}
+
+// SignPath returns the path of the sign endpoint of the key.
+func SignPath(keyID string) string {
+	return DescribeKeyPath(keyID) + "/sign"
+}
+
+// DescribeKeyResponse is the response of the describe key endpoint.
+type DescribeKeyResponse struct {
+	// KeyID is the ID of the key.
+	KeyID string `json:"keyId"`
+
+	// KeySpec is the key spec of the key.
+	KeySpec plugin.KeySpec `json:"keySpec"`
+
+	// CertificateChain is the DER encoded certificate chain of the key, from
+	// the signing certificate to the root certificate.
+	CertificateChain [][]byte `json:"certificateChain"`
+}
+
+// SignRequest is the request of the sign endpoint.
+type SignRequest struct {
+	// KeyID is the ID of the key.
+	KeyID string `json:"keyId"`
+
+	// KeySpec is the key spec of the key returned by the describe key
+	// endpoint.
+	KeySpec plugin.KeySpec `json:"keySpec"`
+
+	// Hash is the hash algorithm of the digest.
+	Hash plugin.HashAlgorithm `json:"hashAlgorithm"`
+
+	// Digest is the digest of the payload to sign.
+	Digest []byte `json:"digest"`
+}
+
+// SignResponse is the response of the sign endpoint.
+type SignResponse struct {
+	// KeyID is the ID of the key.
+	KeyID string `json:"keyId"`
+
+	// SigningAlgorithm is the signing algorithm of the signature.
+	SigningAlgorithm plugin.SignatureAlgorithm `json:"signingAlgorithm"`
+
+	// Signature is the raw signature.
+	Signature []byte `json:"signature"`
+
+	// CertificateChain is the DER encoded certificate chain of the key, from
+	// the signing certificate to the root certificate.
+	CertificateChain [][]byte `json:"certificateChain"`
+}
+
+// ErrorResponse is the response of the failed requests. It is returned as
+// error by the client.
+type ErrorResponse struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+
+	// ErrorCode is the machine readable code of the error.
+	ErrorCode string `json:"errorCode"`
+
+	// ErrorMessage is the description of the error.
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// Error returns the formatted error message.
+func (e *ErrorResponse) Error() string {
+	if e.ErrorCode == "" {
+		return fmt.Sprintf("remote signing service returned status %d: %s", e.StatusCode, e.ErrorMessage)
+	}
+	return fmt.Sprintf("remote signing service returned status %d: %s: %s", e.StatusCode, e.ErrorCode, e.ErrorMessage)
+}
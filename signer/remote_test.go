@@ -0,0 +1,150 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-go/signer/remote"
+)
+
+// newRemoteSigningServer returns a remote signing service signing with the
+// key of keyCert.
+func newRemoteSigningServer(t *testing.T, keyCert *keyCertPair, token string) *httptest.Server {
+	t.Helper()
+	keySpec, err := signature.ExtractKeySpec(keyCert.certs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedKeySpec, err := proto.EncodeKeySpec(keySpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingAlgorithm, err := proto.EncodeSigningAlgorithm(keySpec.SignatureAlgorithm())
+	if err != nil {
+		t.Fatal(err)
+	}
+	certChain := make([][]byte, len(keyCert.certs))
+	for i, cert := range keyCert.certs {
+		certChain[i] = cert.Raw
+	}
+	writeError := func(w http.ResponseWriter, status int, msg string) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(remote.ErrorResponse{ErrorCode: "ERROR", ErrorMessage: msg})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+remote.DescribeKeyPath("key"), func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(remote.DescribeKeyResponse{
+			KeyID:            "key",
+			KeySpec:          encodedKeySpec,
+			CertificateChain: certChain,
+		})
+	})
+	mux.HandleFunc("POST "+remote.SignPath("key"), func(w http.ResponseWriter, r *http.Request) {
+		var req remote.SignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		hash := keySpec.SignatureAlgorithm().Hash()
+		var sig []byte
+		switch key := keyCert.key.(type) {
+		case *rsa.PrivateKey:
+			sig, err = signRSA(req.Digest, hash, key)
+		case *ecdsa.PrivateKey:
+			sig, err = signECDSA(req.Digest, key)
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		json.NewEncoder(w).Encode(remote.SignResponse{
+			KeyID:            req.KeyID,
+			SigningAlgorithm: signingAlgorithm,
+			Signature:        sig,
+			CertificateChain: certChain,
+		})
+	})
+
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+func TestRemoteSigner(t *testing.T) {
+	for _, envelopeType := range signature.RegisteredEnvelopeTypes() {
+		for _, keyCert := range keyCertPairCollections {
+			t.Run(fmt.Sprintf("envelopeType=%v_keySpec=%v", envelopeType, keyCert.keySpecName), func(t *testing.T) {
+				server := newRemoteSigningServer(t, keyCert, "token")
+				defer server.Close()
+				s, err := NewRemoteSigner(server.URL, "key", RemoteSignerOptions{
+					Client: server.Client(),
+					Token: func(context.Context) (string, error) {
+						return "token", nil
+					},
+				})
+				if err != nil {
+					t.Fatalf("NewRemoteSigner() failed: %v", err)
+				}
+				desc, opts := generateSigningContent()
+				opts.SignatureMediaType = envelopeType
+				sig, _, err := s.Sign(context.Background(), desc, opts)
+				if err != nil {
+					t.Fatalf("Sign() failed: %v", err)
+				}
+				basicVerification(t, sig, envelopeType, keyCert.certs[len(keyCert.certs)-1], nil)
+			})
+		}
+	}
+}
+
+func TestRemoteSignerUnauthorized(t *testing.T) {
+	server := newRemoteSigningServer(t, keyCertPairCollections[0], "token")
+	defer server.Close()
+	s, err := NewRemoteSigner(server.URL, "key", RemoteSignerOptions{Client: server.Client()})
+	if err != nil {
+		t.Fatalf("NewRemoteSigner() failed: %v", err)
+	}
+	desc, opts := generateSigningContent()
+	opts.SignatureMediaType = signature.RegisteredEnvelopeTypes()[0]
+	_, _, err = s.Sign(context.Background(), desc, opts)
+	var errResp *remote.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Sign() error = %v, want unauthorized", err)
+	}
+}
+
+func TestNewRemoteSignerError(t *testing.T) {
+	if _, err := NewRemoteSigner("https://signer.example", "", RemoteSignerOptions{}); err == nil {
+		t.Fatal("expected error for empty keyID")
+	}
+	if _, err := NewRemoteSigner("http://signer.example", "key", RemoteSignerOptions{}); err == nil {
+		t.Fatal("expected error for plain HTTP endpoint")
+	}
+}
@@ -19,23 +19,41 @@ package signer
 import (
 	"context"
 	"crypto"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
-	"time"
+	"os"
 
 	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/cose"
 	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/clock"
+	"github.com/notaryproject/notation-go/fips"
 	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/internal/pkcs8"
 	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin/proto"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // signingAgent is the unprotected header field used by signature.
 const signingAgent = "notation-go/1.3.0+unreleased"
 
+// defaultSigningAgent returns the signing agent reporting the FIPS mode, if
+// enabled.
+func defaultSigningAgent() string {
+	if fips.Enabled() {
+		return signingAgent + " (" + fips.ModeFIPS + ")"
+	}
+	return signingAgent
+}
+
 // GenericSigner implements [notation.Signer] and [notation.BlobSigner].
 // It embeds signature.Signer.
 type GenericSigner struct {
@@ -70,6 +88,22 @@ func NewFromFiles(keyPath, certChainPath string) (notation.Signer, error) {
 // NewGenericSignerFromFiles returns a builtinSigner given key and certChain
 // paths.
 func NewGenericSignerFromFiles(keyPath, certChainPath string) (*GenericSigner, error) {
+	return NewGenericSignerFromFilesWithOptions(keyPath, certChainPath, FilesOptions{})
+}
+
+// FilesOptions contains the optional parameters for
+// NewGenericSignerFromFilesWithOptions.
+type FilesOptions struct {
+	// Passphrase returns the passphrase of the private key, e.g. retrieved
+	// from the OS keychain. It is called only if the private key is an
+	// encrypted PKCS #8 private key.
+	Passphrase func() ([]byte, error)
+}
+
+// NewGenericSignerFromFilesWithOptions returns a builtinSigner given key and
+// certChain paths. The private key may be an encrypted PKCS #8 private key,
+// decrypted with the passphrase of opts.
+func NewGenericSignerFromFilesWithOptions(keyPath, certChainPath string, opts FilesOptions) (*GenericSigner, error) {
 	if keyPath == "" {
 		return nil, errors.New("key path not specified")
 	}
@@ -78,7 +112,21 @@ func NewGenericSignerFromFiles(keyPath, certChainPath string) (*GenericSigner, e
 	}
 
 	// read key / cert pair
-	cert, err := tls.LoadX509KeyPair(certChainPath, keyPath)
+	certPEM, err := os.ReadFile(certChainPath)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(keyPEM); block != nil && block.Type == pkcs8.PEMBlockType {
+		keyPEM, err = decryptKeyPEM(keyPath, block, opts.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +147,27 @@ func NewGenericSignerFromFiles(keyPath, certChainPath string) (*GenericSigner, e
 	return NewGenericSigner(cert.PrivateKey, certs)
 }
 
+// decryptKeyPEM decrypts the encrypted PKCS #8 private key block of the key
+// file at keyPath, and returns the unencrypted PEM encoded private key.
+func decryptKeyPEM(keyPath string, block *pem.Block, passphrase func() ([]byte, error)) ([]byte, error) {
+	if passphrase == nil {
+		return nil, fmt.Errorf("private key %q is encrypted, but no passphrase is provided", keyPath)
+	}
+	secret, err := passphrase()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the passphrase of private key %q: %w", keyPath, err)
+	}
+	key, err := pkcs8.DecryptPrivateKey(block.Bytes, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt private key %q: %w", keyPath, err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
 // Sign signs the artifact described by its descriptor and returns the
 // signature and SignerInfo.
 func (s *GenericSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
@@ -114,7 +183,16 @@ func (s *GenericSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts
 	if opts.SigningAgent != "" {
 		signingAgentId = opts.SigningAgent
 	} else {
-		signingAgentId = signingAgent
+		signingAgentId = defaultSigningAgent()
+	}
+	if fips.Enabled() {
+		if err := s.validateFIPS(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if _, ok := s.signer.(*pluginPrimitiveSigner); !ok && audit.Enabled(ctx) {
+		// the plugin signers emit their own events
+		auditKeyLoaded(ctx, s.signer, desc)
 	}
 	if opts.Timestamper != nil && opts.TSARootCAs == nil {
 		return nil, nil, errors.New("timestamping: got Timestamper but nil TSARootCAs")
@@ -128,7 +206,7 @@ func (s *GenericSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts
 			Content:     payloadBytes,
 		},
 		Signer:                 s.signer,
-		SigningTime:            time.Now(),
+		SigningTime:            clock.Now(opts.Clock),
 		SigningScheme:          signature.SigningSchemeX509,
 		SigningAgent:           signingAgentId,
 		Timestamper:            opts.Timestamper,
@@ -140,6 +218,13 @@ func (s *GenericSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts
 	if opts.ExpiryDuration != 0 {
 		signReq.Expiry = signReq.SigningTime.Add(opts.ExpiryDuration)
 	}
+	// Add the registered custom protected headers of COSE envelopes
+	if opts.SignatureMediaType == cose.MediaTypeEnvelope {
+		signReq.ExtendedSignedAttributes, err = envelope.GenerateProtectedHeaders(ctx, payload.TargetArtifact)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 	logger.Debugf("Sign request:")
 	logger.Debugf("  ContentType:   %v", signReq.Payload.ContentType)
 	logger.Debugf("  Content:       %s", string(signReq.Payload.Content))
@@ -173,9 +258,56 @@ func (s *GenericSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts
 	if err := envelope.ValidatePayloadContentType(&envContent.Payload); err != nil {
 		return nil, nil, err
 	}
+	if fips.Enabled() {
+		if err := fips.ValidateSignerInfo(&envContent.SignerInfo); err != nil {
+			return nil, nil, err
+		}
+	}
 	return sig, &envContent.SignerInfo, nil
 }
 
+// auditKeyLoaded emits the audit event of the local signing key loaded to
+// sign the artifact.
+func auditKeyLoaded(ctx context.Context, signer signature.Signer, desc ocispec.Descriptor) {
+	event := audit.Event{
+		Type:       audit.EventKeyLoaded,
+		Artifact:   desc.Digest.String(),
+		Attributes: map[string]string{},
+	}
+	if ks, err := signer.KeySpec(); err == nil {
+		if keySpec, err := proto.EncodeKeySpec(ks); err == nil {
+			event.Attributes["keySpec"] = string(keySpec)
+		}
+	}
+	if localSigner, ok := signer.(signature.LocalSigner); ok {
+		if certs, err := localSigner.CertificateChain(); err == nil && len(certs) > 0 {
+			thumbprint := sha256.Sum256(certs[0].Raw)
+			event.Attributes["certificateThumbprint"] = hex.EncodeToString(thumbprint[:])
+		}
+	}
+	audit.Emit(ctx, event)
+}
+
+// validateFIPS fails closed if the signing key or the certificate chain of
+// the signer is not FIPS-approved.
+func (s *GenericSigner) validateFIPS() error {
+	ks, err := s.signer.KeySpec()
+	if err != nil {
+		return err
+	}
+	if err := fips.ValidateKeySpec(ks); err != nil {
+		return err
+	}
+	if localSigner, ok := s.signer.(signature.LocalSigner); ok {
+		certs, err := localSigner.CertificateChain()
+		if err != nil {
+			return err
+		}
+		return fips.ValidateCertificateChain(certs)
+	}
+	return nil
+}
+
 // SignBlob signs the descriptor returned by genDesc, and returns the
 // signature and SignerInfo.
 func (s *GenericSigner) SignBlob(ctx context.Context, genDesc notation.BlobDescriptorGenerator, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
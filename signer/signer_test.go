@@ -34,7 +34,7 @@ import (
 	"github.com/notaryproject/notation-core-go/revocation/purpose"
 	"github.com/notaryproject/notation-core-go/signature"
 	_ "github.com/notaryproject/notation-core-go/signature/cose"
-	_ "github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-core-go/signature/jws"
 	"github.com/notaryproject/notation-core-go/testhelper"
 	nx509 "github.com/notaryproject/notation-core-go/x509"
 	"github.com/notaryproject/notation-go"
@@ -426,11 +426,11 @@ func verifySigningAgent(t *testing.T, signingAgentId string, metadata *proto.Get
 	}
 
 	if metadata == nil {
-		if signingAgentId != signingAgent {
-			t.Fatalf("Expected signingAgent of %s but signature contained %s instead", signingAgent, signingAgentId)
+		if signingAgentId != defaultSigningAgent() {
+			t.Fatalf("Expected signingAgent of %s but signature contained %s instead", defaultSigningAgent(), signingAgentId)
 		}
-	} else if results["agent"] != signingAgent || results["name"] != metadata.Name || results["version"] != metadata.Version {
-		t.Fatalf("Expected signingAgent of %s %s/%s but signature contained %s instead", signingAgent, metadata.Name, metadata.Version, signingAgentId)
+	} else if results["agent"] != defaultSigningAgent() || results["name"] != metadata.Name || results["version"] != metadata.Version {
+		t.Fatalf("Expected signingAgent of %s %s/%s but signature contained %s instead", defaultSigningAgent(), metadata.Name, metadata.Version, signingAgentId)
 	}
 }
 
@@ -464,3 +464,55 @@ func validateSignWithCerts(t *testing.T, envelopeType string, key crypto.Private
 	// basic verification
 	basicVerification(t, sig, envelopeType, certs[len(certs)-1], nil)
 }
+
+func TestNewGenericSignerFromFilesWithOptions(t *testing.T) {
+	keyPath := filepath.Join("testdata", "encrypted.key")
+	certPath := filepath.Join("testdata", "encrypted.crt")
+	opts := FilesOptions{
+		Passphrase: func() ([]byte, error) {
+			return []byte("notation"), nil
+		},
+	}
+	s, err := NewGenericSignerFromFilesWithOptions(keyPath, certPath, opts)
+	if err != nil {
+		t.Fatalf("NewGenericSignerFromFilesWithOptions() failed: %v", err)
+	}
+	desc, signOpts := generateSigningContent()
+	signOpts.SignatureMediaType = jws.MediaTypeEnvelope
+	if _, _, err := s.Sign(context.Background(), desc, signOpts); err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+}
+
+func TestNewGenericSignerFromFilesWithOptionsError(t *testing.T) {
+	keyPath := filepath.Join("testdata", "encrypted.key")
+	certPath := filepath.Join("testdata", "encrypted.crt")
+	tests := map[string]struct {
+		passphrase func() ([]byte, error)
+		errMsg     string
+	}{
+		"noPassphrase": {
+			errMsg: `private key "testdata/encrypted.key" is encrypted, but no passphrase is provided`,
+		},
+		"passphraseError": {
+			passphrase: func() ([]byte, error) {
+				return nil, errors.New("not found")
+			},
+			errMsg: `failed to get the passphrase of private key "testdata/encrypted.key": not found`,
+		},
+		"wrongPassphrase": {
+			passphrase: func() ([]byte, error) {
+				return []byte("wrong"), nil
+			},
+			errMsg: `failed to decrypt private key "testdata/encrypted.key": incorrect passphrase for the encrypted private key`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewGenericSignerFromFilesWithOptions(keyPath, certPath, FilesOptions{Passphrase: tc.passphrase})
+			if err == nil || err.Error() != tc.errMsg {
+				t.Fatalf("expected error %q, got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
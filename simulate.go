@@ -0,0 +1,225 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// VerificationRecord is the recorded verification of a signature, replayed
+// by [Simulate] against a candidate trust policy. It is serializable to JSON
+// to be stored, e.g. by an admission controller.
+type VerificationRecord struct {
+	// ArtifactReference is the reference of the verified artifact.
+	ArtifactReference string `json:"artifactReference"`
+
+	// Artifact is the descriptor of the verified artifact.
+	Artifact ocispec.Descriptor `json:"artifact"`
+
+	// Signature is the signature envelope.
+	Signature []byte `json:"signature"`
+
+	// SignatureMediaType is the media type of the signature envelope.
+	SignatureMediaType string `json:"signatureMediaType"`
+
+	// Passed is true if the signature passed the verification.
+	Passed bool `json:"passed"`
+
+	// Error is the verification error if the signature failed the
+	// verification.
+	Error string `json:"error,omitempty"`
+
+	// VerifiedAt is the time of the verification.
+	VerifiedAt time.Time `json:"verifiedAt"`
+}
+
+// RecordVerifications returns a [ProgressFunc] passing the verification
+// record of each signature verified by [notation.Verify] of the artifact
+// artifactRef to record, e.g.
+//
+//	opts.Progress = notation.RecordVerifications(opts.ArtifactReference, func(r notation.VerificationRecord) {
+//		records = append(records, r)
+//	})
+func RecordVerifications(artifactRef string, record func(VerificationRecord)) ProgressFunc {
+	return func(event ProgressEvent) {
+		if event.Type != ProgressSignatureVerified || event.Outcome == nil || len(event.Outcome.RawSignature) == 0 {
+			return
+		}
+		mediaType, err := envelope.DetectMediaType(event.Outcome.RawSignature)
+		if err != nil {
+			// the signature cannot be replayed
+			return
+		}
+		r := VerificationRecord{
+			ArtifactReference:  artifactRef,
+			Artifact:           event.Artifact,
+			Signature:          event.Outcome.RawSignature,
+			SignatureMediaType: mediaType,
+			Passed:             event.Error == nil,
+			VerifiedAt:         time.Now().UTC(),
+		}
+		if event.Error != nil {
+			r.Error = event.Error.Error()
+		}
+		record(r)
+	}
+}
+
+// SimulationOptions contains parameters for [notation.Simulate].
+type SimulationOptions struct {
+	// PluginConfig is a map of plugin configs.
+	PluginConfig map[string]string
+
+	// UserMetadata contains key-value pairs that must be present in the
+	// signature
+	UserMetadata map[string]string
+}
+
+// Flip is the change of the verification result of an artifact replayed by
+// [Simulate].
+type Flip string
+
+const (
+	// FlipNone means that the result of the artifact is unchanged.
+	FlipNone Flip = ""
+
+	// FlipPassToFail means that the artifact passed the verification and
+	// fails with the candidate trust policy.
+	FlipPassToFail Flip = "passToFail"
+
+	// FlipFailToPass means that the artifact failed the verification and
+	// passes with the candidate trust policy.
+	FlipFailToPass Flip = "failToPass"
+)
+
+// ArtifactSimulation is the result of the replayed verification of an
+// artifact. An artifact passes the verification if one of its signatures
+// passes.
+type ArtifactSimulation struct {
+	// ArtifactReference is the reference of the artifact.
+	ArtifactReference string
+
+	// Artifact is the descriptor of the artifact.
+	Artifact ocispec.Descriptor
+
+	// Passed is true if the artifact passed the recorded verification.
+	Passed bool
+
+	// PassesCandidate is true if the artifact passes the verification with
+	// the candidate trust policy.
+	PassesCandidate bool
+
+	// Flip is the change of the result of the artifact.
+	Flip Flip
+
+	// Signatures are the replayed signatures of the artifact.
+	Signatures []SignatureSimulation
+}
+
+// SignatureSimulation is the result of a replayed signature verification.
+type SignatureSimulation struct {
+	// Record is the recorded verification.
+	Record VerificationRecord
+
+	// Outcome is the outcome of the verification with the candidate trust
+	// policy.
+	Outcome *VerificationOutcome
+
+	// Error is the error of the verification with the candidate trust
+	// policy, nil if the signature passes.
+	Error error
+}
+
+// SimulationReport is the report of [Simulate].
+type SimulationReport struct {
+	// Artifacts are the replayed artifacts, in the order of their first
+	// record.
+	Artifacts []*ArtifactSimulation
+
+	// PassToFail is the number of artifacts flipping from pass to fail.
+	PassToFail int
+
+	// FailToPass is the number of artifacts flipping from fail to pass.
+	FailToPass int
+}
+
+// Simulate replays the recorded verifications against the candidate
+// verifier, e.g. created with a candidate trust policy, and reports which
+// artifacts would flip from pass to fail or from fail to pass, so that a
+// trust policy change can be evaluated before it is rolled out.
+//
+// The signatures are verified offline, with the recorded envelopes, at the
+// current time: the expiry and the revocation status of the certificates
+// are evaluated at the time of the simulation.
+func Simulate(ctx context.Context, candidate Verifier, records []VerificationRecord, opts SimulationOptions) (*SimulationReport, error) {
+	if candidate == nil {
+		return nil, errors.New("candidate verifier cannot be nil")
+	}
+	logger := log.GetLogger(ctx)
+	type artifactKey struct {
+		reference string
+		digest    digest.Digest
+	}
+	report := &SimulationReport{}
+	artifacts := make(map[artifactKey]*ArtifactSimulation)
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		key := artifactKey{reference: record.ArtifactReference, digest: record.Artifact.Digest}
+		artifact, ok := artifacts[key]
+		if !ok {
+			artifact = &ArtifactSimulation{
+				ArtifactReference: record.ArtifactReference,
+				Artifact:          record.Artifact,
+			}
+			artifacts[key] = artifact
+			report.Artifacts = append(report.Artifacts, artifact)
+		}
+		outcome, err := candidate.Verify(ctx, record.Artifact, record.Signature, VerifierVerifyOptions{
+			ArtifactReference:  record.ArtifactReference,
+			SignatureMediaType: record.SignatureMediaType,
+			PluginConfig:       opts.PluginConfig,
+			UserMetadata:       opts.UserMetadata,
+		})
+		if err != nil {
+			logger.Debugf("Replayed signature of %s fails with the candidate verifier: %v", record.ArtifactReference, err)
+		}
+		artifact.Passed = artifact.Passed || record.Passed
+		artifact.PassesCandidate = artifact.PassesCandidate || err == nil
+		artifact.Signatures = append(artifact.Signatures, SignatureSimulation{
+			Record:  record,
+			Outcome: outcome,
+			Error:   err,
+		})
+	}
+	for _, artifact := range report.Artifacts {
+		switch {
+		case artifact.Passed && !artifact.PassesCandidate:
+			artifact.Flip = FlipPassToFail
+			report.PassToFail++
+		case !artifact.Passed && artifact.PassesCandidate:
+			artifact.Flip = FlipFailToPass
+			report.FailToPass++
+		}
+	}
+	return report, nil
+}
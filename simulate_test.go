@@ -0,0 +1,132 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestSimulate(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	signer, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var refs []string
+	for _, tag := range []string{"v1", "v2"} {
+		desc, err := repo.PushArtifact(ctx, tag, map[string]string{"version": tag})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref := "localhost:5000/net-monitor@" + desc.Digest.String()
+		if _, err := notation.Sign(ctx, signer, repo, notation.SignOptions{
+			SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+			ArtifactReference: ref,
+		}); err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		refs = append(refs, ref)
+	}
+	trustStore := notationtest.NewTrustStore().Add(truststore.TypeCA, "test", signer.Root())
+	newVerifier := func(trustedIdentity string) notation.Verifier {
+		policyDoc := notationtest.TrustPolicy("test")
+		policyDoc.TrustPolicies[0].TrustedIdentities = []string{trustedIdentity}
+		v, err := verifier.NewVerifierWithOptions(trustStore, verifier.VerifierOptions{OCITrustPolicy: policyDoc})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	// record the verifications of the current trust policy, trusting the
+	// signer of v1 only
+	current := newVerifier("x509.subject:" + signer.CertificateChain[0].Subject.String())
+	var records []notation.VerificationRecord
+	for i, ref := range refs {
+		if i == 1 {
+			current = newVerifier("x509.subject:CN=Unknown,O=Notary,ST=WA,C=US")
+		}
+		_, _, err := notation.Verify(ctx, current, repo, notation.VerifyOptions{
+			ArtifactReference:    ref,
+			MaxSignatureAttempts: 10,
+			Progress: notation.RecordVerifications(ref, func(r notation.VerificationRecord) {
+				records = append(records, r)
+			}),
+		})
+		if (i == 0) != (err == nil) {
+			t.Fatalf("Verify(%s) error = %v", ref, err)
+		}
+	}
+	if len(records) != 2 || !records[0].Passed || records[1].Passed || records[1].Error == "" || records[0].SignatureMediaType != jws.MediaTypeEnvelope {
+		t.Fatalf("RecordVerifications() = %+v", records)
+	}
+
+	// the records survive a JSON round trip
+	data, err := json.Marshal(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records = nil
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatal(err)
+	}
+
+	// a candidate trusting all identities lets v2 pass
+	report, err := notation.Simulate(ctx, newVerifier("*"), records, notation.SimulationOptions{})
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if report.PassToFail != 0 || report.FailToPass != 1 || len(report.Artifacts) != 2 {
+		t.Fatalf("Simulate() = %+v, want v2 failing to pass", report)
+	}
+	if report.Artifacts[0].Flip != notation.FlipNone || report.Artifacts[1].Flip != notation.FlipFailToPass || report.Artifacts[1].ArtifactReference != refs[1] {
+		t.Fatalf("Simulate() artifacts = %+v, %+v", report.Artifacts[0], report.Artifacts[1])
+	}
+
+	// a candidate at the skip level lets everything pass
+	skipPolicy := notationtest.TrustPolicy("test")
+	skipPolicy.TrustPolicies[0].SignatureVerification.VerificationLevel = trustpolicy.LevelSkip.Name
+	skipPolicy.TrustPolicies[0].TrustStores = nil
+	skipPolicy.TrustPolicies[0].TrustedIdentities = nil
+	skipVerifier, err := verifier.NewVerifierWithOptions(trustStore, verifier.VerifierOptions{OCITrustPolicy: skipPolicy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report, err := notation.Simulate(ctx, skipVerifier, records, notation.SimulationOptions{}); err != nil || report.FailToPass != 1 {
+		t.Fatalf("Simulate() = %+v, %v, want v2 failing to pass", report, err)
+	}
+
+	// a candidate trusting no known identity fails v1
+	report, err = notation.Simulate(ctx, newVerifier("x509.subject:CN=Unknown,O=Notary,ST=WA,C=US"), records, notation.SimulationOptions{})
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if report.PassToFail != 1 || report.FailToPass != 0 || report.Artifacts[0].Flip != notation.FlipPassToFail || report.Artifacts[0].Signatures[0].Error == nil {
+		t.Fatalf("Simulate() = %+v, want v1 passing to fail", report)
+	}
+
+	if _, err := notation.Simulate(ctx, nil, records, notation.SimulationOptions{}); err == nil {
+		t.Fatal("Simulate() expects error for nil verifier")
+	}
+}
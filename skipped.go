@@ -0,0 +1,92 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/internal/slices"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/opencontainers/go-digest"
+)
+
+// SkippedSignature describes a signature skipped by the verification, as its
+// envelope is not supported, e.g. an envelope version released after this
+// library.
+type SkippedSignature struct {
+	// Digest is the digest of the signature manifest.
+	Digest digest.Digest
+
+	// MediaType is the media type of the signature envelope.
+	MediaType string
+
+	// Reason is the reason the signature was skipped.
+	Reason string
+}
+
+// String returns the description of the skipped signature.
+func (s SkippedSignature) String() string {
+	return fmt.Sprintf("signature with digest %v skipped: %s", s.Digest, s.Reason)
+}
+
+// unsupportedEnvelope returns the skipped signature of the signature manifest
+// with digest sigManifestDigest, if the media type of its envelope is not
+// supported.
+func unsupportedEnvelope(sigManifestDigest digest.Digest, mediaType string) (SkippedSignature, bool) {
+	if slices.Contains(signature.RegisteredEnvelopeTypes(), mediaType) {
+		return SkippedSignature{}, false
+	}
+	return SkippedSignature{
+		Digest:    sigManifestDigest,
+		MediaType: mediaType,
+		Reason:    fmt.Sprintf("signature envelope media type %q is not supported", mediaType),
+	}, true
+}
+
+// logSkippedSignature logs the skipped signature, as a warning if warn is
+// true.
+func logSkippedSignature(ctx context.Context, skipped SkippedSignature, warn bool) {
+	logger := log.GetLogger(ctx)
+	if warn {
+		logger.Warn(skipped.String())
+		return
+	}
+	logger.Info(skipped.String())
+}
+
+// withSkippedSignatures reports the skipped signatures in the verification
+// outcomes, and in their warnings if warn is true. The outcomes are copied,
+// as they may be shared with the verification cache.
+func withSkippedSignatures(outcomes []*VerificationOutcome, skipped []SkippedSignature, warn bool) []*VerificationOutcome {
+	if len(skipped) == 0 {
+		return outcomes
+	}
+	for i, outcome := range outcomes {
+		if outcome == nil {
+			continue
+		}
+		outcomeCopy := *outcome
+		outcomeCopy.SkippedSignatures = append(append([]SkippedSignature(nil), outcome.SkippedSignatures...), skipped...)
+		if warn {
+			outcomeCopy.Warnings = append([]string(nil), outcome.Warnings...)
+			for _, s := range skipped {
+				outcomeCopy.Warnings = append(outcomeCopy.Warnings, s.String())
+			}
+		}
+		outcomes[i] = &outcomeCopy
+	}
+	return outcomes
+}
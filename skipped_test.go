@@ -0,0 +1,83 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const futureEnvelopeMediaType = "application/vnd.example.signature.v2+cbor"
+
+// futureEnvelopeRepository returns the signature envelopes of the signature
+// manifests in futureSignatures with an unsupported media type.
+type futureEnvelopeRepository struct {
+	mock.Repository
+	futureSignatures map[digest.Digest]bool
+}
+
+func (r futureEnvelopeRepository) FetchSignatureBlob(ctx context.Context, desc ocispec.Descriptor) ([]byte, ocispec.Descriptor, error) {
+	blob, blobDesc, err := r.Repository.FetchSignatureBlob(ctx, desc)
+	if r.futureSignatures[desc.Digest] {
+		blobDesc.MediaType = futureEnvelopeMediaType
+	}
+	return blob, blobDesc, err
+}
+
+func TestVerifySkipsUnsupportedEnvelopes(t *testing.T) {
+	futureSigDesc := ocispec.Descriptor{
+		MediaType: mock.SigManfiestDescriptor.MediaType,
+		Digest:    digest.FromString("future signature"),
+		Size:      mock.SigManfiestDescriptor.Size,
+	}
+	repo := futureEnvelopeRepository{
+		Repository:       mock.NewRepository(),
+		futureSignatures: map[digest.Digest]bool{futureSigDesc.Digest: true},
+	}
+	repo.ListSignaturesResponse = []ocispec.Descriptor{futureSigDesc, mock.SigManfiestDescriptor}
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}
+
+	for _, warn := range []bool{false, true} {
+		opts := VerifyOptions{ArtifactReference: mock.SampleArtifactUri, MaxSignatureAttempts: 50, WarnUnsupportedEnvelopes: warn}
+		_, outcomes, err := Verify(context.Background(), &verifier, repo, opts)
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if len(outcomes) != 1 {
+			t.Fatalf("Verify() returned %d outcomes, want 1", len(outcomes))
+		}
+		skipped := outcomes[0].SkippedSignatures
+		if len(skipped) != 1 || skipped[0].Digest != futureSigDesc.Digest || skipped[0].MediaType != futureEnvelopeMediaType {
+			t.Fatalf("SkippedSignatures = %+v, want the future signature", skipped)
+		}
+		if gotWarning := len(outcomes[0].Warnings) == 1 && outcomes[0].Warnings[0] == skipped[0].String(); gotWarning != warn {
+			t.Fatalf("Warnings = %v, want warning %v", outcomes[0].Warnings, warn)
+		}
+	}
+
+	// only unsupported envelopes
+	repo.ListSignaturesResponse = []ocispec.Descriptor{futureSigDesc}
+	opts := VerifyOptions{ArtifactReference: mock.SampleArtifactUri, MaxSignatureAttempts: 50}
+	_, _, err := Verify(context.Background(), &verifier, repo, opts)
+	if err == nil || !strings.Contains(err.Error(), "is not supported") {
+		t.Fatalf("Verify() error = %v, want skipped signature error", err)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// signatureSubjectFetcher is implemented by repositories able to fetch the
+// subject descriptor of a signature manifest.
+type signatureSubjectFetcher interface {
+	// FetchSignatureSubject returns the subject descriptor of the signature
+	// manifest desc.
+	FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error)
+}
+
+// checkSignatureSubject fetches the subject of the signature manifest
+// sigManifestDesc, bounded by timeout if positive, and returns a
+// SubjectMismatchError if it does not match the artifact.
+func checkSignatureSubject(ctx context.Context, fetcher signatureSubjectFetcher, sigManifestDesc, artifact ocispec.Descriptor, timeout time.Duration) error {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+	subject, err := fetcher.FetchSignatureSubject(ctx, sigManifestDesc)
+	if err != nil {
+		return err
+	}
+	return validateSignatureSubject(sigManifestDesc, subject, artifact)
+}
+
+// validateSignatureSubject returns a SubjectMismatchError if the subject of
+// the signature manifest sigManifestDesc has a different media type, digest
+// or size than the artifact, or annotations not matching the artifact.
+func validateSignatureSubject(sigManifestDesc, subject, artifact ocispec.Descriptor) error {
+	mismatch := func(field string, got, want any) error {
+		return SubjectMismatchError{Msg: fmt.Sprintf("signature manifest %s has subject %s %v, but the artifact has %v", sigManifestDesc.Digest, field, got, want)}
+	}
+	if subject.MediaType != artifact.MediaType {
+		return mismatch("media type", subject.MediaType, artifact.MediaType)
+	}
+	if subject.Digest != artifact.Digest {
+		return mismatch("digest", subject.Digest, artifact.Digest)
+	}
+	if subject.Size != artifact.Size {
+		return mismatch("size", subject.Size, artifact.Size)
+	}
+	for key, value := range subject.Annotations {
+		if artifactValue, ok := artifact.Annotations[key]; !ok || artifactValue != value {
+			return mismatch(fmt.Sprintf("annotation %q", key), value, artifactValue)
+		}
+	}
+	return nil
+}
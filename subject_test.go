@@ -0,0 +1,110 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// subjectRepository returns the subject of the signature manifests.
+type subjectRepository struct {
+	mock.Repository
+	subject ocispec.Descriptor
+}
+
+func (r *subjectRepository) FetchSignatureSubject(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	return r.subject, nil
+}
+
+func TestValidateSignatureSubject(t *testing.T) {
+	artifact := mock.ImageDescriptor
+	tests := []struct {
+		name    string
+		modify  func(*ocispec.Descriptor)
+		wantErr bool
+	}{
+		{
+			name:   "match",
+			modify: func(d *ocispec.Descriptor) {},
+		},
+		{
+			name:    "media type",
+			modify:  func(d *ocispec.Descriptor) { d.MediaType = ocispec.MediaTypeImageIndex },
+			wantErr: true,
+		},
+		{
+			name:    "digest",
+			modify:  func(d *ocispec.Descriptor) { d.Digest = digest.FromString("rewritten") },
+			wantErr: true,
+		},
+		{
+			name:    "size",
+			modify:  func(d *ocispec.Descriptor) { d.Size++ },
+			wantErr: true,
+		},
+		{
+			name:    "annotation",
+			modify:  func(d *ocispec.Descriptor) { d.Annotations = map[string]string{"rewritten": "true"} },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject := artifact
+			subject.Annotations = nil
+			tt.modify(&subject)
+			err := validateSignatureSubject(mock.SigManfiestDescriptor, subject, artifact)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("validateSignatureSubject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.As(err, &SubjectMismatchError{}) {
+				t.Fatalf("validateSignatureSubject() error = %v, want SubjectMismatchError", err)
+			}
+		})
+	}
+}
+
+func TestVerifyStrictSubject(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}
+	opts := VerifyOptions{
+		ArtifactReference:    mock.SampleArtifactUri,
+		MaxSignatureAttempts: 50,
+		StrictSubject:        true,
+	}
+
+	subject := mock.ImageDescriptor
+	subject.Annotations = nil
+	repo := &subjectRepository{Repository: mock.NewRepository(), subject: subject}
+	if _, _, err := Verify(context.Background(), &verifier, repo, opts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	repo.subject.Digest = digest.FromString("rewritten")
+	_, _, err := Verify(context.Background(), &verifier, repo, opts)
+	if !errors.As(err, &ErrorVerificationFailed{}) || !errors.As(err, &SubjectMismatchError{}) {
+		t.Fatalf("Verify() error = %v, want SubjectMismatchError", err)
+	}
+
+	if _, _, err := Verify(context.Background(), &verifier, mock.NewRepository(), opts); err == nil {
+		t.Fatal("Verify() expects error for repo unable to fetch signature subjects")
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// tagLister is implemented by repositories able to list their tags, e.g. the
+// repositories returned by registry.NewRepository for a remote repository.
+type tagLister interface {
+	// Tags lists the tags of the repository in lexical order, calling fn
+	// with each page of tags. If last is not empty, the tags start after
+	// last.
+	Tags(ctx context.Context, last string, fn func(tags []string) error) error
+}
+
+// ArtifactStatus is the signature status of an artifact in a
+// [RepositorySummary].
+type ArtifactStatus string
+
+const (
+	// ArtifactSigned is the status of an artifact with a signature passing
+	// verification.
+	ArtifactSigned ArtifactStatus = "signed"
+
+	// ArtifactUnsigned is the status of an artifact without signatures.
+	ArtifactUnsigned ArtifactStatus = "unsigned"
+
+	// ArtifactFailed is the status of an artifact whose signatures all fail
+	// verification, or whose verification could not be performed.
+	ArtifactFailed ArtifactStatus = "failed"
+)
+
+// RepositorySummaryOptions contains parameters for
+// [notation.SummarizeRepository].
+type RepositorySummaryOptions struct {
+	// PluginConfig is a map of plugin configs.
+	PluginConfig map[string]string
+
+	// MaxSignatureAttempts is the maximum number of signature envelopes that
+	// will be processed for verification of each artifact. If set to less
+	// than or equals to zero, an error will be returned.
+	MaxSignatureAttempts int
+
+	// UserMetadata contains key-value pairs that must be present in the
+	// signature
+	UserMetadata map[string]string
+
+	// TagFilter, if set, selects the tags of the artifacts to verify. If nil,
+	// the artifacts of all tags are verified.
+	TagFilter func(tag string) bool
+
+	// Concurrency is the maximum number of artifacts verified concurrently.
+	// If set to less than or equals to zero, 4 is used.
+	Concurrency int
+}
+
+// ArtifactSummary is the verification result of an artifact of a
+// [RepositorySummary].
+type ArtifactSummary struct {
+	// Descriptor is the manifest descriptor of the artifact. It is empty if
+	// the tags could not be resolved.
+	Descriptor ocispec.Descriptor
+
+	// Tags are the tags of the artifact selected by the tag filter.
+	Tags []string
+
+	// Status is the signature status of the artifact.
+	Status ArtifactStatus
+
+	// Outcomes are the verification outcomes of the signatures of the
+	// artifact: the outcome of the verified signature as returned by
+	// [notation.Verify], or the outcomes of the signatures failing
+	// verification.
+	Outcomes []*VerificationOutcome
+
+	// Error is the error that caused the verification to fail (if it fails).
+	Error error
+}
+
+// RepositorySummary is the aggregate verification report of the artifacts of
+// a repository returned by [notation.SummarizeRepository], e.g. for
+// compliance dashboards.
+type RepositorySummary struct {
+	// Repository is the name of the repository, e.g.
+	// "registry.example.com/software/net-monitor".
+	Repository string
+
+	// Signed is the number of artifacts with the status [ArtifactSigned].
+	Signed int
+
+	// Unsigned is the number of artifacts with the status
+	// [ArtifactUnsigned].
+	Unsigned int
+
+	// Failed is the number of artifacts with the status [ArtifactFailed].
+	Failed int
+
+	// Artifacts are the results of the artifacts, in the lexical order of
+	// their first tag.
+	Artifacts []*ArtifactSummary
+
+	// UnknownSigners are the subjects of the signing certificates of the
+	// signatures failing the authenticity verification, sorted, i.e. the
+	// signers not trusted by the trust policy.
+	UnknownSigners []string
+}
+
+// SummarizeRepository verifies the artifacts of the tags of the repository
+// named repository, e.g. "registry.example.com/software/net-monitor", with
+// bounded concurrency, and returns the aggregate report. The artifacts are
+// verified once per digest, with the trust policy applicable to the
+// repository. A failed verification is reported in the summary and does not
+// stop the verification of the other artifacts.
+//
+// repo must be able to list its tags, e.g. a repository returned by
+// registry.NewRepository for a remote repository.
+func SummarizeRepository(ctx context.Context, verifier Verifier, repo registry.Repository, repository string, opts RepositorySummaryOptions) (*RepositorySummary, error) {
+	// sanity check
+	if verifier == nil {
+		return nil, errors.New("verifier cannot be nil")
+	}
+	if repo == nil {
+		return nil, errors.New("repo cannot be nil")
+	}
+	if opts.MaxSignatureAttempts <= 0 {
+		return nil, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("repositorySummaryOptions.MaxSignatureAttempts expects a positive number, got %d", opts.MaxSignatureAttempts)}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultVerifyAllConcurrency
+	}
+
+	artifacts, err := resolveTags(ctx, repo, opts.TagFilter)
+	if err != nil {
+		return nil, err
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, artifact := range artifacts {
+		if artifact.Error != nil {
+			artifact.Status = ArtifactFailed
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			artifact.Status, artifact.Error = ArtifactFailed, ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(artifact *ArtifactSummary) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			summarizeArtifact(ctx, verifier, repo, repository, artifact, opts)
+		}(artifact)
+	}
+	wg.Wait()
+
+	summary := &RepositorySummary{
+		Repository: repository,
+		Artifacts:  artifacts,
+	}
+	unknownSigners := make(map[string]bool)
+	for _, artifact := range artifacts {
+		switch artifact.Status {
+		case ArtifactSigned:
+			summary.Signed++
+		case ArtifactUnsigned:
+			summary.Unsigned++
+		default:
+			summary.Failed++
+		}
+		for _, outcome := range artifact.Outcomes {
+			if signer := unknownSigner(outcome); signer != "" && !unknownSigners[signer] {
+				unknownSigners[signer] = true
+				summary.UnknownSigners = append(summary.UnknownSigners, signer)
+			}
+		}
+	}
+	sort.Strings(summary.UnknownSigners)
+	return summary, nil
+}
+
+// summarizeArtifact verifies the artifact and sets its status.
+func summarizeArtifact(ctx context.Context, verifier Verifier, repo registry.Repository, repository string, artifact *ArtifactSummary, opts RepositorySummaryOptions) {
+	// the outcomes of the failed signatures are not returned by Verify
+	var failedOutcomes []*VerificationOutcome
+	_, artifact.Outcomes, artifact.Error = Verify(ctx, verifier, repo, VerifyOptions{
+		ArtifactReference:    repository + "@" + artifact.Descriptor.Digest.String(),
+		PluginConfig:         opts.PluginConfig,
+		MaxSignatureAttempts: opts.MaxSignatureAttempts,
+		UserMetadata:         opts.UserMetadata,
+		Progress: func(event ProgressEvent) {
+			if event.Type == ProgressSignatureVerified && event.Error != nil && event.Outcome != nil {
+				failedOutcomes = append(failedOutcomes, event.Outcome)
+			}
+		},
+	})
+	switch {
+	case artifact.Error == nil:
+		artifact.Status = ArtifactSigned
+	case len(failedOutcomes) > 0:
+		artifact.Status, artifact.Outcomes = ArtifactFailed, failedOutcomes
+	case len(artifact.Outcomes) == 0:
+		// tell the unsigned artifacts from the failed retrievals
+		signed, err := hasSignatures(ctx, repo, artifact.Descriptor)
+		if err == nil && !signed {
+			artifact.Status, artifact.Error = ArtifactUnsigned, nil
+			return
+		}
+		artifact.Status = ArtifactFailed
+	default:
+		artifact.Status = ArtifactFailed
+	}
+}
+
+// resolveTags resolves the tags of repo selected by filter, and returns an
+// artifact per digest in the lexical order of their first tag. The tags
+// failing to resolve are returned as artifacts with an error.
+func resolveTags(ctx context.Context, repo registry.Repository, filter func(tag string) bool) ([]*ArtifactSummary, error) {
+	lister, ok := repo.(tagLister)
+	if !ok {
+		return nil, fmt.Errorf("listing tags: %w", errdef.ErrUnsupported)
+	}
+	var tags []string
+	if err := lister.Tags(ctx, "", func(page []string) error {
+		for _, tag := range page {
+			if filter == nil || filter(tag) {
+				tags = append(tags, tag)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	var artifacts []*ArtifactSummary
+	byDigest := make(map[digest.Digest]*ArtifactSummary)
+	for _, tag := range tags {
+		desc, err := repo.Resolve(ctx, tag)
+		if err != nil {
+			artifacts = append(artifacts, &ArtifactSummary{
+				Tags:  []string{tag},
+				Error: fmt.Errorf("failed to resolve tag %s: %w", tag, err),
+			})
+			continue
+		}
+		if artifact, ok := byDigest[desc.Digest]; ok {
+			artifact.Tags = append(artifact.Tags, tag)
+			continue
+		}
+		artifact := &ArtifactSummary{
+			Descriptor: desc,
+			Tags:       []string{tag},
+		}
+		byDigest[desc.Digest] = artifact
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+// hasSignatures returns true if the artifact desc has at least one signature
+// in repo.
+func hasSignatures(ctx context.Context, repo registry.Repository, desc ocispec.Descriptor) (bool, error) {
+	var signed bool
+	err := repo.ListSignatures(ctx, desc, func(signatureManifests []ocispec.Descriptor) error {
+		if len(signatureManifests) > 0 {
+			signed = true
+			return errDoneListing
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDoneListing) {
+		return false, err
+	}
+	return signed, nil
+}
+
+// unknownSigner returns the subject of the signing certificate of the
+// signature of outcome if it failed the authenticity verification, or an
+// empty string.
+func unknownSigner(outcome *VerificationOutcome) string {
+	if outcome == nil || outcome.EnvelopeContent == nil || len(outcome.EnvelopeContent.SignerInfo.CertificateChain) == 0 {
+		return ""
+	}
+	for _, result := range outcome.VerificationResults {
+		if result.Type == trustpolicy.TypeAuthenticity && result.Error != nil {
+			return outcome.EnvelopeContent.SignerInfo.CertificateChain[0].Subject.String()
+		}
+	}
+	return ""
+}
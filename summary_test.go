@@ -0,0 +1,168 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// tagRepository is a repository with tags, whose artifacts have the
+// signatures in signatures.
+type tagRepository struct {
+	mock.Repository
+	tags       map[string]ocispec.Descriptor
+	signatures map[digest.Digest][]ocispec.Descriptor
+}
+
+func (r *tagRepository) Tags(_ context.Context, last string, fn func(tags []string) error) error {
+	var tags []string
+	for tag := range r.tags {
+		if tag > last {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return fn(tags)
+}
+
+func (r *tagRepository) Resolve(_ context.Context, reference string) (ocispec.Descriptor, error) {
+	if desc, ok := r.tags[reference]; ok {
+		return desc, nil
+	}
+	for _, desc := range r.tags {
+		if desc.Digest.String() == reference {
+			return desc, nil
+		}
+	}
+	return ocispec.Descriptor{}, errdef.ErrNotFound
+}
+
+func (r *tagRepository) ListSignatures(_ context.Context, desc ocispec.Descriptor, fn func(signatureManifests []ocispec.Descriptor) error) error {
+	return fn(r.signatures[desc.Digest])
+}
+
+// untrustedVerifier fails the authenticity verification of the artifacts in
+// untrusted.
+type untrustedVerifier struct {
+	untrusted map[digest.Digest]*x509.Certificate
+}
+
+func (v *untrustedVerifier) Verify(_ context.Context, desc ocispec.Descriptor, _ []byte, _ VerifierVerifyOptions) (*VerificationOutcome, error) {
+	outcome := &VerificationOutcome{VerificationLevel: trustpolicy.LevelStrict}
+	cert, ok := v.untrusted[desc.Digest]
+	if !ok {
+		return outcome, nil
+	}
+	outcome.EnvelopeContent = &signature.EnvelopeContent{
+		SignerInfo: signature.SignerInfo{CertificateChain: []*x509.Certificate{cert}},
+	}
+	outcome.Error = errors.New("signature is not produced by a trusted signer")
+	outcome.VerificationResults = []*ValidationResult{{
+		Type:   trustpolicy.TypeAuthenticity,
+		Action: trustpolicy.ActionEnforce,
+		Error:  outcome.Error,
+	}}
+	return outcome, outcome.Error
+}
+
+func TestSummarizeRepository(t *testing.T) {
+	artifact := func(hex string) ocispec.Descriptor {
+		return ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    digest.Digest("sha256:" + hex),
+			Size:      528,
+		}
+	}
+	signedDesc := artifact("1111111111111111111111111111111111111111111111111111111111111111")
+	unsignedDesc := artifact("2222222222222222222222222222222222222222222222222222222222222222")
+	untrustedDesc := artifact("3333333333333333333333333333333333333333333333333333333333333333")
+	repo := &tagRepository{
+		Repository: mock.NewRepository(),
+		tags: map[string]ocispec.Descriptor{
+			"v1":     signedDesc,
+			"latest": signedDesc,
+			"v2":     unsignedDesc,
+			"v3":     untrustedDesc,
+			"dev":    untrustedDesc,
+		},
+		signatures: map[digest.Digest][]ocispec.Descriptor{
+			signedDesc.Digest:    {mock.SigManfiestDescriptor},
+			untrustedDesc.Digest: {mock.SigManfiestDescriptor},
+		},
+	}
+	untrustedCert := testhelper.GetRSALeafCertificate().Cert
+	verifier := &untrustedVerifier{untrusted: map[digest.Digest]*x509.Certificate{untrustedDesc.Digest: untrustedCert}}
+
+	summary, err := SummarizeRepository(context.Background(), verifier, repo, "registry.acme-rockets.io/software/net-monitor", RepositorySummaryOptions{
+		MaxSignatureAttempts: 50,
+		TagFilter:            func(tag string) bool { return tag != "dev" },
+	})
+	if err != nil {
+		t.Fatalf("SummarizeRepository() error = %v", err)
+	}
+	if summary.Signed != 1 || summary.Unsigned != 1 || summary.Failed != 1 {
+		t.Fatalf("SummarizeRepository() = %d signed, %d unsigned, %d failed, want 1 of each", summary.Signed, summary.Unsigned, summary.Failed)
+	}
+	want := []struct {
+		digest digest.Digest
+		tags   []string
+		status ArtifactStatus
+	}{
+		{signedDesc.Digest, []string{"latest", "v1"}, ArtifactSigned},
+		{unsignedDesc.Digest, []string{"v2"}, ArtifactUnsigned},
+		{untrustedDesc.Digest, []string{"v3"}, ArtifactFailed},
+	}
+	if len(summary.Artifacts) != len(want) {
+		t.Fatalf("SummarizeRepository() returned %d artifacts, want %d", len(summary.Artifacts), len(want))
+	}
+	for i, w := range want {
+		got := summary.Artifacts[i]
+		if got.Descriptor.Digest != w.digest || !reflect.DeepEqual(got.Tags, w.tags) || got.Status != w.status {
+			t.Fatalf("artifact %d = %v %v %s, want %v %v %s", i, got.Descriptor.Digest, got.Tags, got.Status, w.digest, w.tags, w.status)
+		}
+	}
+	if summary.Artifacts[1].Error != nil || summary.Artifacts[2].Error == nil {
+		t.Fatalf("SummarizeRepository() errors = %v, %v, want nil, error", summary.Artifacts[1].Error, summary.Artifacts[2].Error)
+	}
+	if wantSigners := []string{untrustedCert.Subject.String()}; !reflect.DeepEqual(summary.UnknownSigners, wantSigners) {
+		t.Fatalf("UnknownSigners = %v, want %v", summary.UnknownSigners, wantSigners)
+	}
+}
+
+func TestSummarizeRepositoryErrors(t *testing.T) {
+	verifier := &untrustedVerifier{}
+	opts := RepositorySummaryOptions{MaxSignatureAttempts: 50}
+	if _, err := SummarizeRepository(context.Background(), nil, mock.NewRepository(), "registry.acme-rockets.io/software/net-monitor", opts); err == nil {
+		t.Fatal("SummarizeRepository() expects error for nil verifier")
+	}
+	if _, err := SummarizeRepository(context.Background(), verifier, &tagRepository{}, "registry.acme-rockets.io/software/net-monitor", RepositorySummaryOptions{}); err == nil {
+		t.Fatal("SummarizeRepository() expects error for zero MaxSignatureAttempts")
+	}
+	if _, err := SummarizeRepository(context.Background(), verifier, mock.NewRepository(), "registry.acme-rockets.io/software/net-monitor", opts); !errors.Is(err, errdef.ErrUnsupported) {
+		t.Fatalf("SummarizeRepository() error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orasRegistry "oras.land/oras-go/v2/registry"
+)
+
+// ResolvedTag records the digest a tag was resolved to, so that pipelines
+// signing or verifying by tag use a single digest and detect the tag moving
+// in between with [ResolvedTag.Check].
+type ResolvedTag struct {
+	// Reference is the tag reference, e.g. "localhost:5000/net-monitor:v1".
+	Reference string
+
+	// Descriptor is the manifest descriptor the tag was resolved to.
+	Descriptor ocispec.Descriptor
+
+	// ResolvedAt is the time the tag was resolved.
+	ResolvedAt time.Time
+}
+
+// ResolveTag resolves the tag reference to the manifest descriptor it points
+// to in repo, and records it.
+func ResolveTag(ctx context.Context, repo registry.Repository, reference string) (*ResolvedTag, error) {
+	if repo == nil {
+		return nil, errors.New("repo cannot be nil")
+	}
+	ref, err := orasRegistry.ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	if err := ref.ValidateReferenceAsTag(); err != nil {
+		return nil, fmt.Errorf("reference %s is not a tag reference: %w", reference, err)
+	}
+	desc, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tag %s: %w", reference, err)
+	}
+	return &ResolvedTag{
+		Reference:  reference,
+		Descriptor: desc,
+		ResolvedAt: time.Now(),
+	}, nil
+}
+
+// DigestReference returns the reference of the resolved digest, e.g.
+// "localhost:5000/net-monitor@sha256:...", to sign or verify the artifact
+// the tag pointed to when resolved.
+func (t *ResolvedTag) DigestReference() string {
+	ref, err := orasRegistry.ParseReference(t.Reference)
+	if err != nil {
+		return ""
+	}
+	ref.Reference = t.Descriptor.Digest.String()
+	return ref.String()
+}
+
+// Check resolves the tag again, and returns a [TagMutatedError] if it has
+// moved to another digest since it was resolved.
+func (t *ResolvedTag) Check(ctx context.Context, repo registry.Repository) error {
+	current, err := ResolveTag(ctx, repo, t.Reference)
+	if err != nil {
+		return err
+	}
+	if current.Descriptor.Digest != t.Descriptor.Digest {
+		return TagMutatedError{
+			Reference: t.Reference,
+			Resolved:  t.Descriptor.Digest,
+			Current:   current.Descriptor.Digest,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/opencontainers/go-digest"
+)
+
+func TestResolveTag(t *testing.T) {
+	ctx := context.Background()
+	repo := mock.NewRepository()
+	reference := "registry.acme-rockets.io/software/net-monitor:v1"
+	resolved, err := ResolveTag(ctx, repo, reference)
+	if err != nil {
+		t.Fatalf("ResolveTag() error = %v", err)
+	}
+	if resolved.Descriptor.Digest != mock.ImageDescriptor.Digest || resolved.ResolvedAt.IsZero() {
+		t.Fatalf("ResolveTag() = %+v", resolved)
+	}
+	if want := "registry.acme-rockets.io/software/net-monitor@" + mock.ImageDescriptor.Digest.String(); resolved.DigestReference() != want {
+		t.Fatalf("DigestReference() = %q, want %q", resolved.DigestReference(), want)
+	}
+	if err := resolved.Check(ctx, repo); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	// the tag moves
+	repo.ResolveResponse.Digest = digest.FromString("moved")
+	err = resolved.Check(ctx, repo)
+	var mutatedErr TagMutatedError
+	if !errors.As(err, &mutatedErr) {
+		t.Fatalf("Check() error = %v, want TagMutatedError", err)
+	}
+	if mutatedErr.Resolved != mock.ImageDescriptor.Digest || mutatedErr.Current != digest.FromString("moved") {
+		t.Fatalf("Check() error = %+v", mutatedErr)
+	}
+}
+
+func TestResolveTagErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := ResolveTag(ctx, nil, "registry.acme-rockets.io/software/net-monitor:v1"); err == nil {
+		t.Fatal("ResolveTag() expects error for nil repo")
+	}
+	if _, err := ResolveTag(ctx, mock.NewRepository(), mock.SampleArtifactUri); err == nil {
+		t.Fatal("ResolveTag() expects error for digest reference")
+	}
+	if _, err := ResolveTag(ctx, mock.NewRepository(), "invalid reference"); err == nil {
+		t.Fatal("ResolveTag() expects error for invalid reference")
+	}
+	repo := mock.NewRepository()
+	repo.ResolveError = errors.New("resolve failed")
+	if _, err := ResolveTag(ctx, repo, "registry.acme-rockets.io/software/net-monitor:v1"); !errors.Is(err, repo.ResolveError) {
+		t.Fatalf("ResolveTag() error = %v, want %v", err, repo.ResolveError)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/notaryproject/notation-go/dir"
+)
+
+// ConfigFile is the hash of a configuration file or of a plugin file. The
+// contents of the files are never recorded.
+type ConfigFile struct {
+	// Path is the slash separated path of the file relative to the config
+	// directory, or to the parent of the plugin directory for the plugin
+	// files, e.g. "trustpolicy.oci.json" or "plugins/foo/notation-foo".
+	Path string `json:"path"`
+
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+
+	// SHA256 is the hex encoded SHA-256 digest of the file.
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Error is the error reading the file, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// configFiles are the config files hashed in the bundle. The credentials,
+// the local keys and the key usage log are not included.
+var configFiles = []string{
+	dir.PathConfigFile,
+	dir.PathSigningKeys,
+	dir.PathTrustPolicy,
+	dir.PathOCITrustPolicy,
+	dir.PathBlobTrustPolicy,
+	dir.PathRegistries,
+	dir.PathPluginLockFile,
+}
+
+// snapshot returns the hashes of the config files, of the trust store files
+// and of the plugin files. The missing files are skipped.
+func snapshot(configFS, pluginFS fs.FS) []ConfigFile {
+	var files []ConfigFile
+	for _, name := range configFiles {
+		if file, ok := hashFile(configFS, name, name); ok {
+			files = append(files, file)
+		}
+	}
+	files = append(files, hashDir(configFS, dir.TrustStoreDir, "")...)
+	files = append(files, hashDir(pluginFS, ".", dir.PathPlugins)...)
+	return files
+}
+
+// hashDir returns the hashes of the regular files under root in fsys, with
+// the paths prefixed by prefix.
+func hashDir(fsys fs.FS, root, prefix string) []ConfigFile {
+	var files []ConfigFile
+	_ = fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				files = append(files, ConfigFile{
+					Path:  path.Join(prefix, name),
+					Error: err.Error(),
+				})
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if file, ok := hashFile(fsys, name, path.Join(prefix, name)); ok {
+			files = append(files, file)
+		}
+		return nil
+	})
+	return files
+}
+
+// hashFile returns the hash of the file name in fsys, recorded as
+// displayName. It returns false if the file does not exist.
+func hashFile(fsys fs.FS, name, displayName string) (ConfigFile, bool) {
+	file := ConfigFile{Path: displayName}
+	f, err := fsys.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return file, false
+		}
+		file.Error = err.Error()
+		return file, true
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	file.Size = n
+	if err != nil {
+		file.Error = err.Error()
+		return file, true
+	}
+	file.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return file, true
+}
@@ -0,0 +1,75 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/notaryproject/notation-go/dir"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	configDir := t.TempDir()
+	libexecDir := t.TempDir()
+	pluginDir := filepath.Join(libexecDir, dir.PathPlugins)
+	writeTestFile(t, filepath.Join(configDir, dir.PathConfigFile), "{}")
+	writeTestFile(t, filepath.Join(configDir, dir.PathOCITrustPolicy), `{"version":"1.0"}`)
+	writeTestFile(t, filepath.Join(configDir, dir.TrustStoreDir, "x509", "ca", "acme", "root.crt"), "cert")
+	writeTestFile(t, filepath.Join(pluginDir, "foo", "notation-foo"), "plugin")
+
+	// secrets are not recorded
+	writeTestFile(t, filepath.Join(configDir, dir.PathCredentials), "credentials")
+	writeTestFile(t, filepath.Join(configDir, dir.LocalKeysDir, "key.key"), "key")
+	writeTestFile(t, filepath.Join(configDir, dir.PathKeyUsageLog), "usage")
+
+	files := snapshot(dir.NewSysFS(configDir), dir.NewSysFS(pluginDir))
+	want := map[string]string{
+		dir.PathConfigFile:                 "{}",
+		dir.PathOCITrustPolicy:             `{"version":"1.0"}`,
+		"truststore/x509/ca/acme/root.crt": "cert",
+		"plugins/foo/notation-foo":         "plugin",
+	}
+	if len(files) != len(want) {
+		t.Fatalf("snapshot() = %+v, want %d files", files, len(want))
+	}
+	for _, file := range files {
+		content, ok := want[file.Path]
+		if !ok {
+			t.Errorf("unexpected file %s", file.Path)
+			continue
+		}
+		sum := sha256.Sum256([]byte(content))
+		if file.SHA256 != hex.EncodeToString(sum[:]) || file.Size != int64(len(content)) || file.Error != "" {
+			t.Errorf("file = %+v", file)
+		}
+	}
+
+	// missing directories
+	if files := snapshot(dir.NewSysFS(t.TempDir()), dir.NewSysFS(filepath.Join(t.TempDir(), "missing"))); len(files) != 0 {
+		t.Fatalf("snapshot() = %+v, want no files", files)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/dump"
+	"github.com/notaryproject/notation-go/log"
+)
+
+// recordingLogger records the log messages and passes them to the base
+// Logger.
+type recordingLogger struct {
+	base     log.Logger
+	recorder *Recorder
+	fields   log.Fields
+}
+
+// WithFields returns a recordingLogger adding fields to the recorded
+// messages and passing them to the base Logger.
+func (l *recordingLogger) WithFields(fields log.Fields) log.Logger {
+	merged := make(log.Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	base := log.GetLogger(log.WithFields(log.WithLogger(context.Background(), l.base), fields))
+	return &recordingLogger{
+		base:     base,
+		recorder: l.recorder,
+		fields:   merged,
+	}
+}
+
+func (l *recordingLogger) record(level, message string) {
+	l.recorder.record(Event{
+		Type:    EventLog,
+		Level:   level,
+		Message: message,
+		Fields:  l.fields,
+	})
+}
+
+// sprintln returns the operands formatted as fmt.Sprintln without the
+// trailing newline.
+func sprintln(args []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+func (l *recordingLogger) Debug(args ...interface{}) {
+	l.record("debug", fmt.Sprint(args...))
+	l.base.Debug(args...)
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.record("debug", fmt.Sprintf(format, args...))
+	l.base.Debugf(format, args...)
+}
+
+func (l *recordingLogger) Debugln(args ...interface{}) {
+	l.record("debug", sprintln(args))
+	l.base.Debugln(args...)
+}
+
+func (l *recordingLogger) Info(args ...interface{}) {
+	l.record("info", fmt.Sprint(args...))
+	l.base.Info(args...)
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.record("info", fmt.Sprintf(format, args...))
+	l.base.Infof(format, args...)
+}
+
+func (l *recordingLogger) Infoln(args ...interface{}) {
+	l.record("info", sprintln(args))
+	l.base.Infoln(args...)
+}
+
+func (l *recordingLogger) Warn(args ...interface{}) {
+	l.record("warn", fmt.Sprint(args...))
+	l.base.Warn(args...)
+}
+
+func (l *recordingLogger) Warnf(format string, args ...interface{}) {
+	l.record("warn", fmt.Sprintf(format, args...))
+	l.base.Warnf(format, args...)
+}
+
+func (l *recordingLogger) Warnln(args ...interface{}) {
+	l.record("warn", sprintln(args))
+	l.base.Warnln(args...)
+}
+
+func (l *recordingLogger) Error(args ...interface{}) {
+	l.record("error", fmt.Sprint(args...))
+	l.base.Error(args...)
+}
+
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.record("error", fmt.Sprintf(format, args...))
+	l.base.Errorf(format, args...)
+}
+
+func (l *recordingLogger) Errorln(args ...interface{}) {
+	l.record("error", sprintln(args))
+	l.base.Errorln(args...)
+}
+
+// recordingSink records the audit events and emits them to the base Sink,
+// if any.
+type recordingSink struct {
+	base     audit.Sink
+	recorder *Recorder
+}
+
+// Emit records event and emits it to the base Sink.
+func (s *recordingSink) Emit(ctx context.Context, event audit.Event) {
+	s.recorder.record(Event{
+		Type:  EventAudit,
+		Time:  event.Time,
+		Audit: &event,
+	})
+	if s.base != nil {
+		s.base.Emit(ctx, event)
+	}
+}
+
+// recordingWriter records the dump records written by a dump.Dumper and
+// writes them to the base Dumper, if any.
+type recordingWriter struct {
+	base     *dump.Dumper
+	recorder *Recorder
+}
+
+// Write decodes the records of p, one JSON document per line as encoded by
+// a dump.Dumper.
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var record dump.Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			continue
+		}
+		w.recorder.record(Event{
+			Type: EventDump,
+			Time: record.Time,
+			Dump: &record,
+		})
+		if w.base != nil {
+			w.base.Write(record)
+		}
+	}
+	return len(p), nil
+}
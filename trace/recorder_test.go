@@ -0,0 +1,137 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package trace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/dump"
+	"github.com/notaryproject/notation-go/log"
+)
+
+// bufferLogger writes the messages to a buffer.
+type bufferLogger struct {
+	log.Logger
+	buf *bytes.Buffer
+}
+
+func (l *bufferLogger) Infof(format string, args ...interface{}) {
+	fmt.Fprintf(l.buf, format+"\n", args...)
+}
+
+func TestRecordingLogger(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := newTestRecorder(t, 0)
+	ctx := log.WithLogger(context.Background(), &bufferLogger{Logger: log.Discard, buf: &buf})
+	ctx = WithRecorder(ctx, recorder)
+	ctx = log.WithFields(ctx, log.Fields{log.FieldOperation: "verify"})
+	logger := log.GetLogger(ctx)
+	logger.Infof("verifying %s", "artifact")
+	logger.Debugln("signature", 1)
+	logger.Warn("warning")
+	logger.Error("failure")
+
+	// the messages are passed to the base logger with the fields
+	if got, want := buf.String(), "[operation=verify] verifying artifact\n"; got != want {
+		t.Fatalf("base logger messages = %q, want %q", got, want)
+	}
+	events := recorder.Bundle().Events
+	want := []struct {
+		level   string
+		message string
+	}{
+		{"info", "verifying artifact"},
+		{"debug", "signature 1"},
+		{"warn", "warning"},
+		{"error", "failure"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Type != EventLog || event.Level != want[i].level || event.Message != want[i].message {
+			t.Errorf("events[%d] = %+v, want %s %q", i, event, want[i].level, want[i].message)
+		}
+		if event.Fields[log.FieldOperation] != "verify" {
+			t.Errorf("events[%d].Fields = %v", i, event.Fields)
+		}
+	}
+
+	// no base logger
+	recorder = newTestRecorder(t, 0)
+	log.GetLogger(WithRecorder(context.Background(), recorder)).Info("message")
+	if events := recorder.Bundle().Events; len(events) != 1 || events[0].Message != "message" {
+		t.Fatalf("events = %+v", events)
+	}
+}
+
+func TestRecordingSink(t *testing.T) {
+	var emitted []audit.Event
+	recorder := newTestRecorder(t, 0)
+	ctx := audit.WithSink(context.Background(), audit.SinkFunc(func(ctx context.Context, event audit.Event) {
+		emitted = append(emitted, event)
+	}))
+	ctx = WithRecorder(ctx, recorder)
+	audit.Emit(ctx, audit.Event{Type: audit.EventKeyLoaded, KeyID: "key"})
+	if len(emitted) != 1 || emitted[0].KeyID != "key" {
+		t.Fatalf("emitted = %+v", emitted)
+	}
+	events := recorder.Bundle().Events
+	if len(events) != 1 || events[0].Type != EventAudit || events[0].Audit.KeyID != "key" {
+		t.Fatalf("events = %+v", events)
+	}
+
+	// no base sink
+	recorder = newTestRecorder(t, 0)
+	audit.Emit(WithRecorder(context.Background(), recorder), audit.Event{Type: audit.EventKeyLoaded})
+	if events := recorder.Bundle().Events; len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+}
+
+func TestRecordingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := newTestRecorder(t, 0)
+	ctx := dump.WithDumper(context.Background(), dump.NewDumper(&buf))
+	ctx = WithRecorder(ctx, recorder)
+	recordTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dump.FromContext(ctx).Write(dump.Record{
+		Type:    dump.RecordPlugin,
+		Time:    recordTime,
+		Plugin:  "foo",
+		Command: "verify-signature",
+	})
+	if !strings.Contains(buf.String(), `"plugin":"foo"`) {
+		t.Fatalf("base dumper records = %s", buf.String())
+	}
+	events := recorder.Bundle().Events
+	if len(events) != 1 || events[0].Type != EventDump || events[0].Dump.Plugin != "foo" || !events[0].Time.Equal(recordTime) {
+		t.Fatalf("events = %+v", events)
+	}
+
+	// invalid records are ignored
+	recorder = newTestRecorder(t, 0)
+	w := &recordingWriter{recorder: recorder}
+	if n, err := w.Write([]byte("invalid\n\n")); err != nil || n != 9 {
+		t.Fatalf("Write() = %d, %v", n, err)
+	}
+	if events := recorder.Bundle().Events; len(events) != 0 {
+		t.Fatalf("events = %+v", events)
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace captures a redacted trace of one Sign or Verify call into a
+// support bundle file, which users can attach to bug reports.
+// Users who want to trace a call should create a trace.Recorder, include it
+// in context by calling trace.WithRecorder, call e.g. notation.Verify with
+// the context, and write the bundle with Recorder.WriteBundle.
+// The bundle contains the hashes of the configuration files, the trust
+// policy statements matched, the outcomes, the errors, and the log messages,
+// audit events and HTTP and plugin exchanges of the call with their timings.
+// The credentials and the sensitive values are redacted as in the logs, the
+// audit events and the dump records.
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/dump"
+	"github.com/notaryproject/notation-go/log"
+)
+
+type contextKey int
+
+const (
+	// recorderKey is the associated key type for recorder entry in context.
+	recorderKey contextKey = iota
+
+	// ownerKey is the associated key type for the recorder owned by the
+	// operation of the context, if any.
+	ownerKey
+)
+
+// BundleVersion is the version of the support bundle format.
+const BundleVersion = "1.0"
+
+// EventType is the type of an [Event].
+type EventType string
+
+// Types of the events.
+const (
+	// EventLog is the type of the log messages.
+	EventLog EventType = "log"
+
+	// EventAudit is the type of the audit events.
+	EventAudit EventType = "audit"
+
+	// EventDump is the type of the HTTP and plugin exchanges.
+	EventDump EventType = "dump"
+
+	// EventPolicy is the type of the trust policy statements matched.
+	EventPolicy EventType = "policy"
+)
+
+// Bundle is the support bundle of a traced operation.
+type Bundle struct {
+	// Version is the version of the bundle format, i.e. [BundleVersion].
+	Version string `json:"version"`
+
+	// Operation is the name of the operation, e.g. "sign" or "verify".
+	Operation string `json:"operation,omitempty"`
+
+	// OperationID is the random ID of the operation, as logged with
+	// log.FieldOperationID.
+	OperationID string `json:"operationId,omitempty"`
+
+	// Start is the start time of the operation.
+	Start time.Time `json:"start"`
+
+	// Duration is the duration of the operation, if completed.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Completed reports whether the operation completed.
+	Completed bool `json:"completed"`
+
+	// Environment is the environment of the operation.
+	Environment Environment `json:"environment"`
+
+	// Config are the hashes of the configuration files and of the plugins
+	// at the start of the operation.
+	Config []ConfigFile `json:"config,omitempty"`
+
+	// PolicyStatements are the names of the trust policy statements matched
+	// by the verification.
+	PolicyStatements []string `json:"policyStatements,omitempty"`
+
+	// Outcomes are the verification outcomes.
+	Outcomes []Outcome `json:"outcomes,omitempty"`
+
+	// Error is the error of the operation, if any.
+	Error string `json:"error,omitempty"`
+
+	// Events are the events of the operation, in order.
+	Events []Event `json:"events,omitempty"`
+}
+
+// Environment is the environment of a traced operation.
+type Environment struct {
+	// GoVersion is the version of the Go runtime.
+	GoVersion string `json:"goVersion"`
+
+	// OS is the operating system.
+	OS string `json:"os"`
+
+	// Arch is the architecture.
+	Arch string `json:"arch"`
+
+	// Module is the version of the notation-go module, if known.
+	Module string `json:"module,omitempty"`
+}
+
+// Outcome is the redacted verification outcome of a signature.
+type Outcome struct {
+	// Signature is the SHA-256 digest of the signature envelope.
+	Signature string `json:"signature,omitempty"`
+
+	// VerificationLevel is the name of the verification level.
+	VerificationLevel string `json:"verificationLevel,omitempty"`
+
+	// Results are the results of the validations.
+	Results []Result `json:"results,omitempty"`
+
+	// Warnings are the warnings of the verification.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Error is the verification error, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Result is the result of a validation of a signature.
+type Result struct {
+	// Type is the type of the validation, e.g. "integrity".
+	Type string `json:"type"`
+
+	// Action is the action of the validation, e.g. "enforce".
+	Action string `json:"action"`
+
+	// Error is the validation error, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Event is an event of a traced operation.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType `json:"type"`
+
+	// Time is the time of the event.
+	Time time.Time `json:"time"`
+
+	// Elapsed is the time elapsed since the start of the operation.
+	Elapsed time.Duration `json:"elapsed"`
+
+	// Level is the level of a log message, e.g. "debug".
+	Level string `json:"level,omitempty"`
+
+	// Message is the log message, or the name of the trust policy
+	// statement matched.
+	Message string `json:"message,omitempty"`
+
+	// Fields are the structured fields of a log message.
+	Fields log.Fields `json:"fields,omitempty"`
+
+	// Audit is the audit event.
+	Audit *audit.Event `json:"audit,omitempty"`
+
+	// Dump is the record of an HTTP or plugin exchange.
+	Dump *dump.Record `json:"dump,omitempty"`
+}
+
+// RecorderOptions contains optional settings of a [Recorder].
+type RecorderOptions struct {
+	// ConfigFS is the config directory whose files are hashed. If nil,
+	// dir.ConfigFS is used.
+	ConfigFS dir.SysFS
+
+	// PluginFS is the plugin directory whose files are hashed. If nil,
+	// dir.PluginFS is used.
+	PluginFS dir.SysFS
+
+	// MaxEvents is the maximum number of events recorded. Further events
+	// are dropped and counted in a warning event of the bundle. If set to less than or
+	// equals to zero, [DefaultMaxEvents] is used.
+	MaxEvents int
+}
+
+// DefaultMaxEvents is the default maximum number of events of a bundle.
+const DefaultMaxEvents = 10000
+
+// Recorder records the trace of the first Sign or Verify call with its
+// context. It is safe for concurrent use.
+type Recorder struct {
+	opts RecorderOptions
+
+	mu            sync.Mutex
+	bundle        Bundle
+	started       bool
+	droppedEvents int
+}
+
+// NewRecorder returns a new [Recorder].
+func NewRecorder() *Recorder {
+	return NewRecorderWithOptions(RecorderOptions{})
+}
+
+// NewRecorderWithOptions returns a new [Recorder] with user specified
+// options.
+func NewRecorderWithOptions(opts RecorderOptions) *Recorder {
+	if opts.ConfigFS == nil {
+		opts.ConfigFS = dir.ConfigFS()
+	}
+	if opts.PluginFS == nil {
+		opts.PluginFS = dir.PluginFS()
+	}
+	if opts.MaxEvents <= 0 {
+		opts.MaxEvents = DefaultMaxEvents
+	}
+	return &Recorder{
+		opts: opts,
+		bundle: Bundle{
+			Version:     BundleVersion,
+			Environment: environment(),
+		},
+	}
+}
+
+// WithRecorder is used by callers to set the Recorder in the context. The
+// log messages, the audit events and the dump records of the call are
+// recorded in addition to being passed to the Logger, the audit Sink and
+// the Dumper of the context, if any.
+func WithRecorder(ctx context.Context, recorder *Recorder) context.Context {
+	ctx = context.WithValue(ctx, recorderKey, recorder)
+	ctx = log.WithLogger(ctx, &recordingLogger{
+		base:     log.GetLogger(ctx),
+		recorder: recorder,
+	})
+	ctx = audit.WithSink(ctx, &recordingSink{
+		base:     audit.GetSink(ctx),
+		recorder: recorder,
+	})
+	return dump.WithDumper(ctx, dump.NewDumper(&recordingWriter{
+		base:     dump.FromContext(ctx),
+		recorder: recorder,
+	}))
+}
+
+// fromContext returns the Recorder in the context, or nil.
+func fromContext(ctx context.Context) *Recorder {
+	recorder, _ := ctx.Value(recorderKey).(*Recorder)
+	return recorder
+}
+
+// Enabled reports whether a Recorder is set in the context, so that costly
+// outcomes are computed only if needed.
+func Enabled(ctx context.Context) bool {
+	return fromContext(ctx) != nil
+}
+
+// Start starts the trace of the operation with the Recorder in the context,
+// if any and if no operation is traced yet. The operations started within
+// the traced one, e.g. the verifications of a batch, are recorded as part of
+// it. The returned context is passed to [End].
+func Start(ctx context.Context, operation, operationID string) context.Context {
+	recorder := fromContext(ctx)
+	if recorder == nil {
+		return ctx
+	}
+	recorder.mu.Lock()
+	owner := !recorder.started
+	if owner {
+		recorder.started = true
+		recorder.bundle.Operation = operation
+		recorder.bundle.OperationID = operationID
+		recorder.bundle.Start = time.Now().UTC()
+	}
+	recorder.mu.Unlock()
+	if !owner {
+		return context.WithValue(ctx, ownerKey, (*Recorder)(nil))
+	}
+	// hashing the files outside the lock
+	config := snapshot(recorder.opts.ConfigFS, recorder.opts.PluginFS)
+	recorder.mu.Lock()
+	recorder.bundle.Config = config
+	recorder.mu.Unlock()
+	return context.WithValue(ctx, ownerKey, recorder)
+}
+
+// End ends the trace of the operation started by [Start] with ctx, with the
+// error and the outcomes of the operation.
+func End(ctx context.Context, err error, outcomes ...Outcome) {
+	recorder, _ := ctx.Value(ownerKey).(*Recorder)
+	if recorder == nil {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.bundle.Completed = true
+	recorder.bundle.Duration = time.Since(recorder.bundle.Start)
+	recorder.bundle.Outcomes = append(recorder.bundle.Outcomes, outcomes...)
+	if err != nil {
+		recorder.bundle.Error = err.Error()
+	}
+}
+
+// PolicyMatched records the name of the trust policy statement matched by
+// the verification to the Recorder in the context, if any.
+func PolicyMatched(ctx context.Context, name string) {
+	recorder := fromContext(ctx)
+	if recorder == nil {
+		return
+	}
+	recorder.mu.Lock()
+	for _, matched := range recorder.bundle.PolicyStatements {
+		if matched == name {
+			recorder.mu.Unlock()
+			recorder.record(Event{Type: EventPolicy, Message: name})
+			return
+		}
+	}
+	recorder.bundle.PolicyStatements = append(recorder.bundle.PolicyStatements, name)
+	recorder.mu.Unlock()
+	recorder.record(Event{Type: EventPolicy, Message: name})
+}
+
+// record records event.
+func (r *Recorder) record(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.bundle.Events) >= r.opts.MaxEvents {
+		r.droppedEvents++
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if !r.bundle.Start.IsZero() {
+		event.Elapsed = event.Time.Sub(r.bundle.Start)
+	}
+	r.bundle.Events = append(r.bundle.Events, event)
+}
+
+// Bundle returns a snapshot of the support bundle.
+func (r *Recorder) Bundle() *Bundle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bundle := r.bundle
+	bundle.Config = append([]ConfigFile(nil), r.bundle.Config...)
+	bundle.PolicyStatements = append([]string(nil), r.bundle.PolicyStatements...)
+	bundle.Outcomes = append([]Outcome(nil), r.bundle.Outcomes...)
+	bundle.Events = append([]Event(nil), r.bundle.Events...)
+	if r.droppedEvents > 0 {
+		bundle.Events = append(bundle.Events, Event{
+			Type:    EventLog,
+			Time:    time.Now().UTC(),
+			Level:   "warn",
+			Message: fmt.Sprintf("%d events dropped after the first %d events", r.droppedEvents, r.opts.MaxEvents),
+		})
+	}
+	return &bundle
+}
+
+// WriteBundle writes the support bundle to w as an indented JSON document.
+func (r *Recorder) WriteBundle(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.Bundle())
+}
+
+// WriteBundleFile writes the support bundle to the file at path, readable
+// and writable by the owner only.
+func (r *Recorder) WriteBundleFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	if err := r.WriteBundle(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	return nil
+}
+
+// environment returns the environment of the process.
+func environment() Environment {
+	env := Environment{
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/notaryproject/notation-go" {
+				env.Module = dep.Version
+			}
+		}
+		if info.Main.Path == "github.com/notaryproject/notation-go" {
+			env.Module = info.Main.Version
+		}
+	}
+	return env
+}
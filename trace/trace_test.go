@@ -0,0 +1,141 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-go/dir"
+)
+
+// newTestRecorder returns a Recorder hashing empty directories.
+func newTestRecorder(t *testing.T, maxEvents int) *Recorder {
+	return NewRecorderWithOptions(RecorderOptions{
+		ConfigFS:  dir.NewSysFS(t.TempDir()),
+		PluginFS:  dir.NewSysFS(t.TempDir()),
+		MaxEvents: maxEvents,
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	if Enabled(ctx) {
+		t.Fatal("Enabled() = true without recorder")
+	}
+	// no recorder, no panic
+	End(Start(ctx, "sign", "id"), nil)
+	PolicyMatched(ctx, "policy")
+
+	recorder := newTestRecorder(t, 0)
+	ctx = WithRecorder(ctx, recorder)
+	if !Enabled(ctx) {
+		t.Fatal("Enabled() = false with recorder")
+	}
+	operationCtx := Start(ctx, "verify", "0123")
+
+	// the nested operations are recorded as part of the first one
+	nestedCtx := Start(operationCtx, "verifyBlob", "4567")
+	PolicyMatched(nestedCtx, "wabbit-networks")
+	PolicyMatched(nestedCtx, "wabbit-networks")
+	End(nestedCtx, errors.New("nested error"))
+	if bundle := recorder.Bundle(); bundle.Completed {
+		t.Fatal("bundle completed by the nested operation")
+	}
+
+	End(operationCtx, errors.New("verification failed"), Outcome{VerificationLevel: "strict"})
+	bundle := recorder.Bundle()
+	if bundle.Version != BundleVersion || bundle.Operation != "verify" || bundle.OperationID != "0123" {
+		t.Fatalf("bundle = %+v", bundle)
+	}
+	if !bundle.Completed || bundle.Duration < 0 || bundle.Error != "verification failed" {
+		t.Fatalf("bundle = %+v, want completed with error", bundle)
+	}
+	if len(bundle.PolicyStatements) != 1 || bundle.PolicyStatements[0] != "wabbit-networks" {
+		t.Fatalf("bundle.PolicyStatements = %v", bundle.PolicyStatements)
+	}
+	if len(bundle.Outcomes) != 1 || bundle.Outcomes[0].VerificationLevel != "strict" {
+		t.Fatalf("bundle.Outcomes = %+v", bundle.Outcomes)
+	}
+	if len(bundle.Events) != 2 || bundle.Events[0].Type != EventPolicy || bundle.Events[0].Message != "wabbit-networks" {
+		t.Fatalf("bundle.Events = %+v", bundle.Events)
+	}
+	if bundle.Environment.GoVersion != runtime.Version() || bundle.Environment.OS != runtime.GOOS {
+		t.Fatalf("bundle.Environment = %+v", bundle.Environment)
+	}
+}
+
+func TestRecorderMaxEvents(t *testing.T) {
+	recorder := newTestRecorder(t, 2)
+	ctx := Start(WithRecorder(context.Background(), recorder), "verify", "0123")
+	for i := 0; i < 5; i++ {
+		PolicyMatched(ctx, "policy")
+	}
+	bundle := recorder.Bundle()
+	if len(bundle.Events) != 3 {
+		t.Fatalf("got %d events, want 2 events and the warning", len(bundle.Events))
+	}
+	if warning := bundle.Events[2]; warning.Level != "warn" || !strings.Contains(warning.Message, "3 events dropped") {
+		t.Fatalf("bundle.Events[2] = %+v", warning)
+	}
+}
+
+func TestWriteBundleFile(t *testing.T) {
+	recorder := newTestRecorder(t, 0)
+	ctx := Start(WithRecorder(context.Background(), recorder), "sign", "0123")
+	End(ctx, nil)
+
+	var buf bytes.Buffer
+	if err := recorder.WriteBundle(&buf); err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(buf.Bytes(), &bundle); err != nil {
+		t.Fatalf("failed to decode bundle: %v", err)
+	}
+	if bundle.Operation != "sign" || !bundle.Completed {
+		t.Fatalf("bundle = %+v", bundle)
+	}
+
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := recorder.WriteBundleFile(path); err != nil {
+		t.Fatalf("WriteBundleFile() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, buf.Bytes()) {
+		t.Fatalf("bundle file = %s, want %s", content, buf.Bytes())
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("bundle file mode = %v, want 0600", info.Mode().Perm())
+		}
+	}
+
+	if err := recorder.WriteBundleFile(filepath.Join(t.TempDir(), "missing", "bundle.json")); err == nil {
+		t.Fatal("WriteBundleFile() expects error, got nil")
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transparency provides a local append-only log of the signatures
+// produced by a process. The log is backed by a Merkle tree as specified in
+// RFC 9162, so that the inclusion of a signature in the log and the
+// append-only evolution of the log can be proven with the inclusion and the
+// consistency proofs exported by the log, without depending on an external
+// transparency log.
+//
+// The signers wrapped by [Log.Signer] and [Log.BlobSigner] append an entry to
+// the log for each signature produced.
+package transparency
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// errClosed is returned when appending to a closed Log.
+var errClosed = errors.New("signing log is closed")
+
+// Entry is an entry of the signing log.
+type Entry struct {
+	// Time is the time the entry is appended to the log.
+	Time time.Time `json:"time"`
+
+	// Artifact is the digest of the signed artifact or blob descriptor.
+	Artifact digest.Digest `json:"artifact"`
+
+	// MediaType is the media type of the signature envelope.
+	MediaType string `json:"mediaType"`
+
+	// Signature is the digest of the signature envelope.
+	Signature digest.Digest `json:"signature"`
+
+	// CertificateThumbprint is the hex encoded SHA-256 thumbprint of the
+	// signing certificate, if known.
+	CertificateThumbprint string `json:"certificateThumbprint,omitempty"`
+}
+
+// Log is an append-only signing log backed by a Merkle tree. The leaves of
+// the tree are the JSON encoded entries. Log is safe for concurrent use.
+type Log struct {
+	mu     sync.RWMutex
+	leaves [][]byte
+	hashes [][]byte
+	file   *os.File
+	closed bool
+}
+
+// NewLog returns an empty in-memory Log.
+func NewLog() *Log {
+	return &Log{}
+}
+
+// OpenLog opens the Log persisted in the file at path, one JSON encoded
+// entry per line, and creates the file if it does not exist. The entries
+// appended are written to the file. The caller must close the Log.
+func OpenLog(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{file: f}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		leaf := bytes.Clone(scanner.Bytes())
+		var entry Entry
+		if err := json.Unmarshal(leaf, &entry); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("malformed signing log entry %d in %s: %w", len(l.leaves), path, err)
+		}
+		l.leaves = append(l.leaves, leaf)
+		l.hashes = append(l.hashes, leafHash(leaf))
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Append appends the entry to the log, and returns its index. The time of
+// the entry is set if it is zero.
+func (l *Log) Append(entry Entry) (uint64, error) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	leaf, err := json.Marshal(entry)
+	if err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return 0, errClosed
+	}
+	if l.file != nil {
+		if _, err := l.file.Write(append(leaf, '\n')); err != nil {
+			return 0, fmt.Errorf("failed to append to the signing log: %w", err)
+		}
+	}
+	l.leaves = append(l.leaves, leaf)
+	l.hashes = append(l.hashes, leafHash(leaf))
+	return uint64(len(l.leaves) - 1), nil
+}
+
+// Size returns the number of entries in the log.
+func (l *Log) Size() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return uint64(len(l.leaves))
+}
+
+// Root returns the root hash of the tree of the first size entries of the
+// log. Publishing the root hash and the size, e.g. in a release note, allows
+// verifying later that the log was not rewritten.
+func (l *Log) Root(size uint64) ([]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if size > uint64(len(l.hashes)) {
+		return nil, fmt.Errorf("tree size %d exceeds the log size %d", size, len(l.hashes))
+	}
+	return rootHash(l.hashes[:size]), nil
+}
+
+// Leaf returns the encoded entry at index, as hashed in the tree.
+func (l *Log) Leaf(index uint64) ([]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if index >= uint64(len(l.leaves)) {
+		return nil, fmt.Errorf("index %d is out of the log of size %d", index, len(l.leaves))
+	}
+	return bytes.Clone(l.leaves[index]), nil
+}
+
+// Entry returns the entry at index.
+func (l *Log) Entry(index uint64) (Entry, error) {
+	leaf, err := l.Leaf(index)
+	if err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	err = json.Unmarshal(leaf, &entry)
+	return entry, err
+}
+
+// Lookup returns the index of the first entry of the signature envelope
+// with the digest signature.
+func (l *Log) Lookup(signature digest.Digest) (uint64, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for i, leaf := range l.leaves {
+		var entry Entry
+		if err := json.Unmarshal(leaf, &entry); err == nil && entry.Signature == signature {
+			return uint64(i), true
+		}
+	}
+	return 0, false
+}
+
+// InclusionProof returns the proof that the entry at index is included in
+// the tree of the first size entries of the log. The proof is verified with
+// [VerifyInclusion].
+func (l *Log) InclusionProof(index, size uint64) ([][]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if size > uint64(len(l.hashes)) {
+		return nil, fmt.Errorf("tree size %d exceeds the log size %d", size, len(l.hashes))
+	}
+	if index >= size {
+		return nil, fmt.Errorf("index %d is out of the tree of size %d", index, size)
+	}
+	return inclusionPath(index, l.hashes[:size]), nil
+}
+
+// ConsistencyProof returns the proof that the tree of the first oldSize
+// entries of the log is a prefix of the tree of the first newSize entries.
+// The proof is verified with [VerifyConsistency].
+func (l *Log) ConsistencyProof(oldSize, newSize uint64) ([][]byte, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if newSize > uint64(len(l.hashes)) {
+		return nil, fmt.Errorf("tree size %d exceeds the log size %d", newSize, len(l.hashes))
+	}
+	if oldSize == 0 || oldSize > newSize {
+		return nil, fmt.Errorf("invalid tree sizes %d and %d", oldSize, newSize)
+	}
+	return consistencyPath(oldSize, l.hashes[:newSize], true), nil
+}
+
+// Close closes the Log. No entry can be appended to a closed Log, while the
+// proofs are still exported. The file of the Log opened by [OpenLog] is
+// closed.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,145 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transparency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+func TestLog(t *testing.T) {
+	l := NewLog()
+	if root, err := l.Root(0); err != nil || len(root) == 0 {
+		t.Fatalf("Root(0) = %x, %v", root, err)
+	}
+	for i := 0; i < 5; i++ {
+		index, err := l.Append(Entry{Artifact: digest.FromString("artifact"), Signature: digest.FromBytes([]byte{byte(i)})})
+		if err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if index != uint64(i) {
+			t.Fatalf("Append() index = %d, want %d", index, i)
+		}
+	}
+	if l.Size() != 5 {
+		t.Fatalf("Size() = %d, want 5", l.Size())
+	}
+
+	index, ok := l.Lookup(digest.FromBytes([]byte{3}))
+	if !ok || index != 3 {
+		t.Fatalf("Lookup() = %d, %v, want 3", index, ok)
+	}
+	if _, ok := l.Lookup(digest.FromString("unknown")); ok {
+		t.Fatal("Lookup() found an unknown signature")
+	}
+	entry, err := l.Entry(index)
+	if err != nil || entry.Time.IsZero() || entry.Signature != digest.FromBytes([]byte{3}) {
+		t.Fatalf("Entry() = %+v, %v", entry, err)
+	}
+
+	// inclusion of the entry in the tree of size 4
+	leaf, err := l.Leaf(index)
+	if err != nil {
+		t.Fatalf("Leaf() error = %v", err)
+	}
+	root4, err := l.Root(4)
+	if err != nil {
+		t.Fatalf("Root() error = %v", err)
+	}
+	proof, err := l.InclusionProof(index, 4)
+	if err != nil {
+		t.Fatalf("InclusionProof() error = %v", err)
+	}
+	if err := VerifyInclusion(leaf, index, 4, proof, root4); err != nil {
+		t.Fatalf("VerifyInclusion() error = %v", err)
+	}
+
+	// the tree of size 4 is a prefix of the tree of size 5
+	root5, _ := l.Root(5)
+	proof, err = l.ConsistencyProof(4, 5)
+	if err != nil {
+		t.Fatalf("ConsistencyProof() error = %v", err)
+	}
+	if err := VerifyConsistency(4, 5, root4, root5, proof); err != nil {
+		t.Fatalf("VerifyConsistency() error = %v", err)
+	}
+
+	for name, fn := range map[string]func() error{
+		"Root":                        func() error { _, err := l.Root(6); return err },
+		"Leaf":                        func() error { _, err := l.Leaf(5); return err },
+		"InclusionProof index":        func() error { _, err := l.InclusionProof(4, 4); return err },
+		"InclusionProof size":         func() error { _, err := l.InclusionProof(0, 6); return err },
+		"ConsistencyProof empty tree": func() error { _, err := l.ConsistencyProof(0, 5); return err },
+		"ConsistencyProof size":       func() error { _, err := l.ConsistencyProof(5, 6); return err },
+	} {
+		if err := fn(); err == nil {
+			t.Errorf("%s expects error, got nil", name)
+		}
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := l.Append(Entry{}); err == nil {
+		t.Fatal("Append() expects error on closed log")
+	}
+}
+
+func TestOpenLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signing.log")
+	l, err := OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog() error = %v", err)
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		if _, err := l.Append(Entry{Signature: digest.FromString(s)}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	root, _ := l.Root(3)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// reopened log is consistent with the log persisted
+	l, err = OpenLog(path)
+	if err != nil {
+		t.Fatalf("OpenLog() error = %v", err)
+	}
+	defer l.Close()
+	if l.Size() != 3 {
+		t.Fatalf("Size() = %d, want 3", l.Size())
+	}
+	if _, err := l.Append(Entry{Signature: digest.FromString("d")}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	newRoot, _ := l.Root(4)
+	proof, err := l.ConsistencyProof(3, 4)
+	if err != nil {
+		t.Fatalf("ConsistencyProof() error = %v", err)
+	}
+	if err := VerifyConsistency(3, 4, root, newRoot, proof); err != nil {
+		t.Fatalf("VerifyConsistency() error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenLog(path); err == nil {
+		t.Fatal("OpenLog() expects error for malformed log")
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transparency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Domain separation prefixes of the leaf and node hashes, as specified in
+// RFC 9162, so that a leaf cannot be forged from an inner node.
+const (
+	leafHashPrefix = 0x00
+	nodeHashPrefix = 0x01
+)
+
+// ErrInvalidProof is returned when a proof fails to verify.
+var ErrInvalidProof = errors.New("invalid proof")
+
+// leafHash returns the Merkle tree hash of the leaf data.
+func leafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafHashPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// nodeHash returns the Merkle tree hash of the inner node with the children
+// left and right.
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodeHashPrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// splitPoint returns the largest power of 2 smaller than n, for n > 1.
+func splitPoint(n uint64) uint64 {
+	return 1 << (bits.Len64(n-1) - 1)
+}
+
+// rootHash returns the Merkle tree hash of the leaves.
+func rootHash(leaves [][]byte) []byte {
+	switch n := uint64(len(leaves)); n {
+	case 0:
+		empty := sha256.Sum256(nil)
+		return empty[:]
+	case 1:
+		return leaves[0]
+	default:
+		k := splitPoint(n)
+		return nodeHash(rootHash(leaves[:k]), rootHash(leaves[k:]))
+	}
+}
+
+// inclusionPath returns the audit path of the leaf m in the tree of the
+// leaves.
+func inclusionPath(m uint64, leaves [][]byte) [][]byte {
+	n := uint64(len(leaves))
+	if n <= 1 {
+		return nil
+	}
+	k := splitPoint(n)
+	if m < k {
+		return append(inclusionPath(m, leaves[:k]), rootHash(leaves[k:]))
+	}
+	return append(inclusionPath(m-k, leaves[k:]), rootHash(leaves[:k]))
+}
+
+// consistencyPath returns the consistency proof between the tree of the
+// first m leaves and the tree of the leaves.
+func consistencyPath(m uint64, leaves [][]byte, complete bool) [][]byte {
+	n := uint64(len(leaves))
+	if m == n {
+		if complete {
+			return nil
+		}
+		return [][]byte{rootHash(leaves)}
+	}
+	k := splitPoint(n)
+	if m <= k {
+		return append(consistencyPath(m, leaves[:k], complete), rootHash(leaves[k:]))
+	}
+	return append(consistencyPath(m-k, leaves[k:], false), rootHash(leaves[:k]))
+}
+
+// VerifyInclusion verifies that the leaf at index is included in the tree of
+// size with the root hash root, given the inclusion proof returned by
+// [Log.InclusionProof]. The leaf is the encoded entry returned by
+// [Log.Leaf].
+func VerifyInclusion(leaf []byte, index, size uint64, proof [][]byte, root []byte) error {
+	if index >= size {
+		return fmt.Errorf("%w: index %d is out of the tree of size %d", ErrInvalidProof, index, size)
+	}
+	fn, sn := index, size-1
+	r := leafHash(leaf)
+	for _, p := range proof {
+		if sn == 0 {
+			return fmt.Errorf("%w: inclusion proof is too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("%w: inclusion proof is too short", ErrInvalidProof)
+	}
+	if !bytes.Equal(r, root) {
+		return fmt.Errorf("%w: inclusion proof does not match the root hash", ErrInvalidProof)
+	}
+	return nil
+}
+
+// VerifyConsistency verifies that the tree of oldSize with the root hash
+// oldRoot is a prefix of the tree of newSize with the root hash newRoot,
+// given the consistency proof returned by [Log.ConsistencyProof].
+func VerifyConsistency(oldSize, newSize uint64, oldRoot, newRoot []byte, proof [][]byte) error {
+	switch {
+	case oldSize == 0 || oldSize > newSize:
+		return fmt.Errorf("%w: invalid tree sizes %d and %d", ErrInvalidProof, oldSize, newSize)
+	case oldSize == newSize:
+		if len(proof) != 0 {
+			return fmt.Errorf("%w: consistency proof of trees of the same size must be empty", ErrInvalidProof)
+		}
+		if !bytes.Equal(oldRoot, newRoot) {
+			return fmt.Errorf("%w: root hashes of trees of the same size differ", ErrInvalidProof)
+		}
+		return nil
+	}
+
+	if oldSize&(oldSize-1) == 0 {
+		// the old tree is a complete subtree of the new tree
+		proof = append([][]byte{oldRoot}, proof...)
+	}
+	if len(proof) == 0 {
+		return fmt.Errorf("%w: consistency proof is empty", ErrInvalidProof)
+	}
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return fmt.Errorf("%w: consistency proof is too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(c, fr)
+			sr = nodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("%w: consistency proof is too short", ErrInvalidProof)
+	}
+	if !bytes.Equal(fr, oldRoot) || !bytes.Equal(sr, newRoot) {
+		return fmt.Errorf("%w: consistency proof does not match the root hashes", ErrInvalidProof)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transparency
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// rfc6962Leaves are the leaves of the test vectors of the reference
+// implementation of RFC 6962.
+var rfc6962Leaves = []string{
+	"",
+	"00",
+	"10",
+	"2021",
+	"3031",
+	"40414243",
+	"5051525354555657",
+	"606162636465666768696a6b6c6d6e6f",
+}
+
+func testLeafHashes(t *testing.T) [][]byte {
+	t.Helper()
+	var hashes [][]byte
+	for _, leaf := range rfc6962Leaves {
+		data, err := hex.DecodeString(leaf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, leafHash(data))
+	}
+	return hashes
+}
+
+func TestRootHash(t *testing.T) {
+	if got := hex.EncodeToString(rootHash(nil)); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Fatalf("rootHash() of empty tree = %s", got)
+	}
+	if got := hex.EncodeToString(rootHash(testLeafHashes(t))); got != "5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328" {
+		t.Fatalf("rootHash() = %s", got)
+	}
+}
+
+func TestVerifyInclusion(t *testing.T) {
+	hashes := testLeafHashes(t)
+	for size := uint64(1); size <= uint64(len(hashes)); size++ {
+		root := rootHash(hashes[:size])
+		for index := uint64(0); index < size; index++ {
+			t.Run(fmt.Sprintf("%d/%d", index, size), func(t *testing.T) {
+				leaf, _ := hex.DecodeString(rfc6962Leaves[index])
+				proof := inclusionPath(index, hashes[:size])
+				if err := VerifyInclusion(leaf, index, size, proof, root); err != nil {
+					t.Fatalf("VerifyInclusion() error = %v", err)
+				}
+
+				// tampered leaf
+				if err := VerifyInclusion(append(leaf, 0xff), index, size, proof, root); !errors.Is(err, ErrInvalidProof) {
+					t.Fatalf("VerifyInclusion() error = %v, want ErrInvalidProof", err)
+				}
+				// wrong index
+				if size > 1 {
+					if err := VerifyInclusion(leaf, (index+1)%size, size, proof, root); !errors.Is(err, ErrInvalidProof) {
+						t.Fatalf("VerifyInclusion() error = %v, want ErrInvalidProof", err)
+					}
+				}
+				// truncated and extended proofs
+				if len(proof) > 0 {
+					if err := VerifyInclusion(leaf, index, size, proof[:len(proof)-1], root); !errors.Is(err, ErrInvalidProof) {
+						t.Fatalf("VerifyInclusion() error = %v, want ErrInvalidProof", err)
+					}
+				}
+				if err := VerifyInclusion(leaf, index, size, append(proof, root), root); !errors.Is(err, ErrInvalidProof) {
+					t.Fatalf("VerifyInclusion() error = %v, want ErrInvalidProof", err)
+				}
+			})
+		}
+	}
+	if err := VerifyInclusion(nil, 1, 1, nil, nil); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("VerifyInclusion() error = %v, want ErrInvalidProof", err)
+	}
+}
+
+func TestVerifyConsistency(t *testing.T) {
+	hashes := testLeafHashes(t)
+	for newSize := uint64(1); newSize <= uint64(len(hashes)); newSize++ {
+		newRoot := rootHash(hashes[:newSize])
+		for oldSize := uint64(1); oldSize <= newSize; oldSize++ {
+			t.Run(fmt.Sprintf("%d/%d", oldSize, newSize), func(t *testing.T) {
+				oldRoot := rootHash(hashes[:oldSize])
+				proof := consistencyPath(oldSize, hashes[:newSize], true)
+				if err := VerifyConsistency(oldSize, newSize, oldRoot, newRoot, proof); err != nil {
+					t.Fatalf("VerifyConsistency() error = %v", err)
+				}
+
+				// rewritten history
+				forged := append([][]byte(nil), hashes[:newSize]...)
+				forged[0] = leafHash([]byte("forged"))
+				if err := VerifyConsistency(oldSize, newSize, oldRoot, rootHash(forged), proof); !errors.Is(err, ErrInvalidProof) {
+					t.Fatalf("VerifyConsistency() error = %v, want ErrInvalidProof", err)
+				}
+				if oldSize < newSize {
+					if err := VerifyConsistency(oldSize, newSize, rootHash(forged[:oldSize]), newRoot, proof); !errors.Is(err, ErrInvalidProof) {
+						t.Fatalf("VerifyConsistency() error = %v, want ErrInvalidProof", err)
+					}
+					if err := VerifyConsistency(oldSize, newSize, oldRoot, newRoot, proof[:len(proof)-1]); !errors.Is(err, ErrInvalidProof) {
+						t.Fatalf("VerifyConsistency() error = %v, want ErrInvalidProof", err)
+					}
+				}
+			})
+		}
+	}
+	if err := VerifyConsistency(0, 1, nil, nil, nil); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("VerifyConsistency() error = %v, want ErrInvalidProof", err)
+	}
+	if err := VerifyConsistency(2, 1, nil, nil, nil); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("VerifyConsistency() error = %v, want ErrInvalidProof", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transparency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Signer returns a [notation.Signer] appending an entry to the log for each
+// signature produced by signer. The signature is not returned if it cannot
+// be appended to the log, so that no signature escapes the log.
+func (l *Log) Signer(signer notation.Signer) notation.Signer {
+	return &loggingSigner{log: l, signer: signer}
+}
+
+// BlobSigner returns a [notation.BlobSigner] appending an entry to the log
+// for each signature produced by signer. The signature is not returned if it
+// cannot be appended to the log.
+func (l *Log) BlobSigner(signer notation.BlobSigner) notation.BlobSigner {
+	return &loggingBlobSigner{log: l, signer: signer}
+}
+
+// loggingSigner is a notation.Signer appending the signatures to a Log.
+type loggingSigner struct {
+	log    *Log
+	signer notation.Signer
+}
+
+// Sign signs the artifact with the underlying signer, and appends the
+// signature to the log.
+func (s *loggingSigner) Sign(ctx context.Context, desc ocispec.Descriptor, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	sig, signerInfo, err := s.signer.Sign(ctx, desc, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.log.appendSignature(desc.Digest, opts.SignatureMediaType, sig, signerInfo); err != nil {
+		return nil, nil, err
+	}
+	return sig, signerInfo, nil
+}
+
+// PluginAnnotations returns the signature manifest annotations of the
+// underlying signer, if any.
+func (s *loggingSigner) PluginAnnotations() map[string]string {
+	if signer, ok := s.signer.(interface{ PluginAnnotations() map[string]string }); ok {
+		return signer.PluginAnnotations()
+	}
+	return nil
+}
+
+// loggingBlobSigner is a notation.BlobSigner appending the signatures to a
+// Log.
+type loggingBlobSigner struct {
+	log    *Log
+	signer notation.BlobSigner
+}
+
+// SignBlob signs the blob with the underlying signer, and appends the
+// signature to the log.
+func (s *loggingBlobSigner) SignBlob(ctx context.Context, genDesc notation.BlobDescriptorGenerator, opts notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	var desc ocispec.Descriptor
+	recordingGenDesc := func(hashAlgo digest.Algorithm) (ocispec.Descriptor, error) {
+		var err error
+		desc, err = genDesc(hashAlgo)
+		return desc, err
+	}
+	sig, signerInfo, err := s.signer.SignBlob(ctx, recordingGenDesc, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.log.appendSignature(desc.Digest, opts.SignatureMediaType, sig, signerInfo); err != nil {
+		return nil, nil, err
+	}
+	return sig, signerInfo, nil
+}
+
+// appendSignature appends the signature envelope sig of the artifact to the
+// log.
+func (l *Log) appendSignature(artifact digest.Digest, mediaType string, sig []byte, signerInfo *signature.SignerInfo) error {
+	entry := Entry{
+		Artifact:  artifact,
+		MediaType: mediaType,
+		Signature: digest.FromBytes(sig),
+	}
+	if signerInfo != nil && len(signerInfo.CertificateChain) > 0 {
+		thumbprint := sha256.Sum256(signerInfo.CertificateChain[0].Raw)
+		entry.CertificateThumbprint = hex.EncodeToString(thumbprint[:])
+	}
+	_, err := l.Append(entry)
+	return err
+}
@@ -0,0 +1,102 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transparency
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestLogSigner(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLog()
+	signOpts := notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope}
+	sig, _, err := l.Signer(s).Sign(ctx, artifactDesc, signOpts)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	blobSig, _, err := l.BlobSigner(s).SignBlob(ctx, func(hashAlgo digest.Algorithm) (ocispec.Descriptor, error) {
+		return ocispec.Descriptor{
+			MediaType: "text/plain",
+			Digest:    hashAlgo.FromString("blob"),
+			Size:      4,
+		}, nil
+	}, signOpts)
+	if err != nil {
+		t.Fatalf("SignBlob() error = %v", err)
+	}
+
+	if l.Size() != 2 {
+		t.Fatalf("Size() = %d, want 2", l.Size())
+	}
+	entry, _ := l.Entry(0)
+	if entry.Artifact != artifactDesc.Digest || entry.Signature != digest.FromBytes(sig) ||
+		entry.MediaType != jws.MediaTypeEnvelope || entry.CertificateThumbprint == "" {
+		t.Fatalf("Entry(0) = %+v", entry)
+	}
+	entry, _ = l.Entry(1)
+	if !strings.HasPrefix(entry.Artifact.String(), "sha") || entry.Signature != digest.FromBytes(blobSig) {
+		t.Fatalf("Entry(1) = %+v", entry)
+	}
+
+	// no signature escapes a closed log
+	l.Close()
+	if _, _, err := l.Signer(s).Sign(ctx, artifactDesc, signOpts); err == nil {
+		t.Fatal("Sign() expects error on closed log")
+	}
+}
+
+func TestLogSignerError(t *testing.T) {
+	l := NewLog()
+	signer := l.Signer(failingSigner{})
+	if _, _, err := signer.Sign(context.Background(), ocispec.Descriptor{}, notation.SignerSignOptions{}); err == nil {
+		t.Fatal("Sign() expects error, got nil")
+	}
+	if l.Size() != 0 {
+		t.Fatalf("Size() = %d, want 0", l.Size())
+	}
+	// the plugin annotations are forwarded
+	if annotations := signer.(interface{ PluginAnnotations() map[string]string }).PluginAnnotations(); annotations["key"] != "value" {
+		t.Fatalf("PluginAnnotations() = %v", annotations)
+	}
+}
+
+type failingSigner struct{}
+
+func (failingSigner) Sign(context.Context, ocispec.Descriptor, notation.SignerSignOptions) ([]byte, *signature.SignerInfo, error) {
+	return nil, nil, errors.New("sign failed")
+}
+
+func (failingSigner) PluginAnnotations() map[string]string {
+	return map[string]string{"key": "value"}
+}
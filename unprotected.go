@@ -0,0 +1,92 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go/internal/envelope"
+)
+
+// AddUnprotectedHeaders adds the headers to the unprotected header of the
+// signature envelope sig of type envelopeMediaType, and returns the updated
+// envelope, e.g. to attach a transparency log proof or a distribution hint to
+// an existing signature.
+//
+// The unprotected headers are not covered by the signature: adding them does
+// not affect the validity of the signature, and they must not be trusted
+// without an independent verification. The values are encoded as base64
+// strings in JWS envelopes and as byte strings in COSE envelopes. The names
+// registered by RFC 7515 and the names prefixed with "io.cncf.notary." are
+// reserved. An existing header with the same name is replaced.
+//
+// The digest of the envelope changes: the envelope must be pushed as a new
+// signature.
+func AddUnprotectedHeaders(envelopeMediaType string, sig []byte, headers map[string][]byte) ([]byte, error) {
+	if len(headers) == 0 {
+		return sig, nil
+	}
+	updated, err := envelope.SetUnprotectedHeaders(envelopeMediaType, sig, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add unprotected headers: %w", err)
+	}
+
+	// the signature must stay valid
+	sigEnv, err := signature.ParseEnvelope(envelopeMediaType, updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add unprotected headers: %w", err)
+	}
+	if _, err := sigEnv.Verify(); err != nil {
+		return nil, fmt.Errorf("failed to add unprotected headers: signature is invalid: %w", err)
+	}
+	return updated, nil
+}
+
+// UnprotectedHeaders returns the unprotected headers of the signature
+// envelope sig of type envelopeMediaType added with [AddUnprotectedHeaders].
+// The headers defined by the signature specification are not returned.
+//
+// The unprotected headers are not covered by the signature.
+func UnprotectedHeaders(envelopeMediaType string, sig []byte) (map[string][]byte, error) {
+	return envelope.UnprotectedHeaders(envelopeMediaType, sig)
+}
+
+// UnprotectedHeaders returns the unprotected headers of the verified
+// signature envelope added with [AddUnprotectedHeaders].
+//
+// The unprotected headers are not covered by the signature: their successful
+// verification is not implied by the outcome.
+func (outcome *VerificationOutcome) UnprotectedHeaders() (map[string][]byte, error) {
+	if outcome == nil || len(outcome.RawSignature) == 0 {
+		return nil, errors.New("verification outcome has no signature envelope")
+	}
+	envelopeMediaType, err := envelope.DetectMediaType(outcome.RawSignature)
+	if err != nil {
+		return nil, err
+	}
+	return envelope.UnprotectedHeaders(envelopeMediaType, outcome.RawSignature)
+}
+
+// validateUnprotectedHeaders validates the names of the unprotected headers
+// to be added at signing.
+func validateUnprotectedHeaders(headers map[string][]byte) error {
+	for name := range headers {
+		if err := envelope.ValidateUnprotectedHeaderName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
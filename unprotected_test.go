@@ -0,0 +1,118 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestUnprotectedHeaders(t *testing.T) {
+	ctx := context.Background()
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := verifier.NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "blob", s.Root()), verifier.VerifierOptions{
+		BlobTrustPolicy: &trustpolicy.BlobDocument{
+			Version: "1.0",
+			TrustPolicies: []trustpolicy.BlobTrustPolicy{
+				{
+					Name:                  "blob-policy",
+					SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: "strict"},
+					TrustStores:           []string{"ca:blob"},
+					TrustedIdentities:     []string{"*"},
+					GlobalPolicy:          true,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("firmware image")
+	hint := map[string][]byte{"com.example.mirror": []byte("https://mirror.example.com")}
+	proof := map[string][]byte{"com.example.inclusionProof": {0x01, 0x02, 0x03}}
+
+	for _, mediaType := range []string{jws.MediaTypeEnvelope, cose.MediaTypeEnvelope} {
+		t.Run(mediaType, func(t *testing.T) {
+			sig, _, err := notation.SignBlob(ctx, s, bytes.NewReader(content), notation.SignBlobOptions{
+				SignerSignOptions:  notation.SignerSignOptions{SignatureMediaType: mediaType},
+				ContentMediaType:   "application/octet-stream",
+				UnprotectedHeaders: hint,
+			})
+			if err != nil {
+				t.Fatalf("SignBlob() error = %v", err)
+			}
+			headers, err := notation.UnprotectedHeaders(mediaType, sig)
+			if err != nil || !reflect.DeepEqual(headers, hint) {
+				t.Fatalf("UnprotectedHeaders() = %v, %v, want %v", headers, err, hint)
+			}
+
+			// add a header to the existing signature
+			sig, err = notation.AddUnprotectedHeaders(mediaType, sig, proof)
+			if err != nil {
+				t.Fatalf("AddUnprotectedHeaders() error = %v", err)
+			}
+			_, outcome, err := notation.VerifyBlob(ctx, v, bytes.NewReader(content), sig, notation.VerifyBlobOptions{
+				BlobVerifierVerifyOptions: notation.BlobVerifierVerifyOptions{SignatureMediaType: mediaType},
+			})
+			if err != nil {
+				t.Fatalf("VerifyBlob() error = %v", err)
+			}
+			headers, err = outcome.UnprotectedHeaders()
+			if err != nil {
+				t.Fatalf("VerificationOutcome.UnprotectedHeaders() error = %v", err)
+			}
+			want := map[string][]byte{
+				"com.example.mirror":         hint["com.example.mirror"],
+				"com.example.inclusionProof": proof["com.example.inclusionProof"],
+			}
+			if !reflect.DeepEqual(headers, want) {
+				t.Fatalf("VerificationOutcome.UnprotectedHeaders() = %v, want %v", headers, want)
+			}
+
+			// reserved names
+			for _, name := range []string{"", "x5c", "io.cncf.notary.signingAgent"} {
+				if _, err := notation.AddUnprotectedHeaders(mediaType, sig, map[string][]byte{name: nil}); err == nil {
+					t.Fatalf("AddUnprotectedHeaders() expects error for header %q", name)
+				}
+			}
+			if _, _, err := notation.SignBlob(ctx, s, bytes.NewReader(content), notation.SignBlobOptions{
+				SignerSignOptions:  notation.SignerSignOptions{SignatureMediaType: mediaType},
+				ContentMediaType:   "application/octet-stream",
+				UnprotectedHeaders: map[string][]byte{"x5c": nil},
+			}); err == nil {
+				t.Fatal("SignBlob() expects error for a reserved unprotected header")
+			}
+		})
+	}
+
+	if _, err := notation.AddUnprotectedHeaders(jws.MediaTypeEnvelope, []byte("{}"), hint); err == nil {
+		t.Fatal("AddUnprotectedHeaders() expects error for an invalid signature")
+	}
+	if _, err := (&notation.VerificationOutcome{}).UnprotectedHeaders(); err == nil {
+		t.Fatal("VerificationOutcome.UnprotectedHeaders() expects error without signature envelope")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// UnsignedArtifact is an artifact without signatures returned by
+// [notation.ListUnsigned].
+type UnsignedArtifact struct {
+	// Descriptor is the manifest descriptor of the artifact.
+	Descriptor ocispec.Descriptor
+
+	// Tags are the tags of the artifact selected by the tag filter.
+	Tags []string
+}
+
+// ListUnsigned returns the artifacts of the tags of repo selected by
+// tagFilter that have no notation signature, in the lexical order of their
+// first tag. If tagFilter is nil, the artifacts of all tags are checked.
+//
+// The signatures are listed but neither fetched nor verified, so that the
+// gaps are flagged quickly across large registries; use
+// [notation.SummarizeRepository] to verify them. The artifacts whose tags
+// fail to resolve or whose signatures fail to list are not returned, and
+// their errors are joined in the returned error along with the unsigned
+// artifacts found.
+//
+// repo must be able to list its tags, e.g. a repository returned by
+// registry.NewRepository for a remote repository.
+func ListUnsigned(ctx context.Context, repo registry.Repository, tagFilter func(tag string) bool) ([]UnsignedArtifact, error) {
+	if repo == nil {
+		return nil, errors.New("repo cannot be nil")
+	}
+	artifacts, err := resolveTags(ctx, repo, tagFilter)
+	if err != nil {
+		return nil, err
+	}
+	var unsigned []UnsignedArtifact
+	var errs []error
+	for _, artifact := range artifacts {
+		if artifact.Error != nil {
+			errs = append(errs, artifact.Error)
+			continue
+		}
+		signed, err := hasSignatures(ctx, repo, artifact.Descriptor)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list the signatures of %v: %w", artifact.Descriptor.Digest, err))
+			continue
+		}
+		if !signed {
+			unsigned = append(unsigned, UnsignedArtifact{
+				Descriptor: artifact.Descriptor,
+				Tags:       artifact.Tags,
+			})
+		}
+	}
+	return unsigned, errors.Join(errs...)
+}
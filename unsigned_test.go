@@ -0,0 +1,65 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+func TestListUnsigned(t *testing.T) {
+	signedDesc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("signed"),
+	}
+	unsignedDesc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("unsigned"),
+	}
+	repo := &tagRepository{
+		Repository: mock.NewRepository(),
+		tags: map[string]ocispec.Descriptor{
+			"v1":     signedDesc,
+			"v2":     unsignedDesc,
+			"latest": unsignedDesc,
+			"dev":    unsignedDesc,
+		},
+		signatures: map[digest.Digest][]ocispec.Descriptor{
+			signedDesc.Digest: {mock.SigManfiestDescriptor},
+		},
+	}
+
+	unsigned, err := ListUnsigned(context.Background(), repo, func(tag string) bool { return tag != "dev" })
+	if err != nil {
+		t.Fatalf("ListUnsigned() error = %v", err)
+	}
+	want := []UnsignedArtifact{{Descriptor: unsignedDesc, Tags: []string{"latest", "v2"}}}
+	if !reflect.DeepEqual(unsigned, want) {
+		t.Fatalf("ListUnsigned() = %+v, want %+v", unsigned, want)
+	}
+
+	if _, err := ListUnsigned(context.Background(), mock.NewRepository(), nil); !errors.Is(err, errdef.ErrUnsupported) {
+		t.Fatalf("ListUnsigned() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := ListUnsigned(context.Background(), nil, nil); err == nil {
+		t.Fatal("ListUnsigned() expects error for nil repo")
+	}
+}
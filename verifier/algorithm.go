@@ -0,0 +1,50 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/internal/slices"
+	"github.com/notaryproject/notation-go/plugin/proto"
+)
+
+// verifySignatureAlgorithm verifies that the signature algorithm of the
+// verified signature of the outcome is one of the algorithms required by the
+// caller, if any.
+func verifySignatureAlgorithm(outcome *notation.VerificationOutcome, algorithms []signature.Algorithm) error {
+	if len(algorithms) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(algorithms))
+	for _, alg := range algorithms {
+		name, err := proto.EncodeSigningAlgorithm(alg)
+		if err != nil {
+			return fmt.Errorf("invalid required signature algorithm: %w", err)
+		}
+		names = append(names, string(name))
+	}
+	alg := outcome.EnvelopeContent.SignerInfo.SignatureAlgorithm
+	if slices.Contains(algorithms, alg) {
+		return nil
+	}
+	name, err := proto.EncodeSigningAlgorithm(alg)
+	if err != nil {
+		return notation.ErrorVerificationFailed{Msg: fmt.Sprintf("signature algorithm is not supported: %v", err)}
+	}
+	return notation.ErrorVerificationFailed{Msg: fmt.Sprintf("signature algorithm %q is not one of the required signature algorithms [%s]", name, strings.Join(names, ", "))}
+}
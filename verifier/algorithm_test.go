@@ -0,0 +1,80 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestVerifySignatureAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+	}
+	if _, _, err := notation.SignOCI(ctx, s, repo, signOpts); err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+	v, err := NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "test", s.Root()), VerifierOptions{
+		OCITrustPolicy: notationtest.TrustPolicy("test"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+	}
+	_, outcomes, err := notation.Verify(ctx, v, repo, verifyOpts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	signingAlgorithm := outcomes[0].EnvelopeContent.SignerInfo.SignatureAlgorithm
+
+	// the signing algorithm is required
+	verifyOpts.SignatureAlgorithms = []signature.Algorithm{signature.AlgorithmES384, signingAlgorithm}
+	if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	// only ECDSA P-384 is accepted
+	verifyOpts.SignatureAlgorithms = []signature.Algorithm{signature.AlgorithmES384}
+	_, _, err = notation.Verify(ctx, v, repo, verifyOpts)
+	if err == nil || !strings.Contains(err.Error(), "is not one of the required signature algorithms") {
+		t.Fatalf("Verify() error = %v, want signature algorithm error", err)
+	}
+
+	// invalid algorithm
+	verifyOpts.SignatureAlgorithms = []signature.Algorithm{0}
+	if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err == nil {
+		t.Fatal("Verify() expects error for an invalid signature algorithm")
+	}
+}
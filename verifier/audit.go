@@ -0,0 +1,89 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/internal/envelope"
+)
+
+// auditVerification emits the audit event of the acceptance or the rejection
+// of a signature. If artifact is empty, the digest of the signed artifact is
+// taken from the signature payload.
+func auditVerification(ctx context.Context, artifact string, outcome *notation.VerificationOutcome, err error) {
+	if !audit.Enabled(ctx) {
+		return
+	}
+	event := audit.Event{
+		Type:     audit.EventSignatureAccepted,
+		Artifact: artifact,
+	}
+	if err != nil {
+		event.Type = audit.EventSignatureRejected
+		event.Reason = err.Error()
+	}
+	if outcome != nil {
+		if outcome.VerificationLevel != nil {
+			event.Result = outcome.VerificationLevel.Name
+		}
+		if outcome.EnvelopeContent != nil {
+			if event.Artifact == "" {
+				if desc, err := envelope.ParsePayload(&outcome.EnvelopeContent.Payload); err == nil {
+					event.Artifact = desc.Digest.String()
+				}
+			}
+			event.Attributes = signerAttributes(outcome)
+		}
+		if len(outcome.ActionOverrides) > 0 {
+			if event.Attributes == nil {
+				event.Attributes = make(map[string]string)
+			}
+			event.Attributes["actionOverrides"] = formatActionOverrides(outcome.ActionOverrides)
+		}
+	}
+	audit.Emit(ctx, event)
+}
+
+// auditRevocation emits the audit event of the revocation check of the
+// certificate chain of the signature.
+func auditRevocation(ctx context.Context, outcome *notation.VerificationOutcome, result string, err error) {
+	if !audit.Enabled(ctx) {
+		return
+	}
+	event := audit.Event{
+		Type:       audit.EventRevocationChecked,
+		Result:     result,
+		Attributes: signerAttributes(outcome),
+	}
+	if err != nil {
+		event.Reason = err.Error()
+	}
+	audit.Emit(ctx, event)
+}
+
+// signerAttributes returns the audit attributes of the signer of the
+// signature.
+func signerAttributes(outcome *notation.VerificationOutcome) map[string]string {
+	certChain := outcome.EnvelopeContent.SignerInfo.CertificateChain
+	if len(certChain) == 0 {
+		return nil
+	}
+	return map[string]string{
+		"signer": certChain[0].Subject.String(),
+		"issuer": certChain[0].Issuer.String(),
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestAuditEvents(t *testing.T) {
+	var events []audit.Event
+	ctx := audit.WithSink(context.Background(), audit.SinkFunc(func(ctx context.Context, event audit.Event) {
+		events = append(events, event)
+	}))
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := notation.Sign(ctx, s, repo, notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+	}); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if len(events) != 2 || events[0].Type != audit.EventKeyLoaded || events[0].Artifact != artifactDesc.Digest.String() ||
+		events[0].Attributes["keySpec"] != "RSA-3072" || events[0].Attributes["certificateThumbprint"] == "" {
+		t.Fatalf("sign events = %+v", events)
+	}
+	if events[1].Type != audit.EventArtifactSigned || events[1].Artifact != artifactDesc.Digest.String() ||
+		events[1].Attributes["certificateThumbprint"] != events[0].Attributes["certificateThumbprint"] || events[1].Attributes["signature"] == "" {
+		t.Fatalf("sign events = %+v", events)
+	}
+
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+	}
+	t.Run("accepted", func(t *testing.T) {
+		events = nil
+		v, err := NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "test", s.Root()), VerifierOptions{
+			OCITrustPolicy: notationtest.TrustPolicy("test"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("got %d events, want 2: %+v", len(events), events)
+		}
+		if events[0].Type != audit.EventRevocationChecked || events[0].Result != "OK" {
+			t.Fatalf("events[0] = %+v", events[0])
+		}
+		if events[1].Type != audit.EventSignatureAccepted || events[1].Artifact != artifactDesc.Digest.String() ||
+			events[1].Result != "strict" || events[1].Attributes["signer"] == "" {
+			t.Fatalf("events[1] = %+v", events[1])
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		events = nil
+		untrusted := testhelper.GetECRootCertificate().Cert
+		v, err := NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "test", untrusted), VerifierOptions{
+			OCITrustPolicy: notationtest.TrustPolicy("test"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err == nil {
+			t.Fatal("Verify() expected error")
+		}
+		if len(events) != 1 || events[0].Type != audit.EventSignatureRejected || events[0].Reason == "" {
+			t.Fatalf("events = %+v", events)
+		}
+	})
+}
@@ -0,0 +1,240 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/plugin"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// VerificationCache stores the outcomes of successful OCI signature
+// verifications, so that verifying the same signature of the same artifact
+// under the same trust policy and trust store does not repeat the work.
+//
+// The keys are derived from the artifact digest, the signature digest, the
+// applicable trust policy, the certificates of its trust stores, the
+// verification options, the context passed to the verification plugin, and
+// the version of the verification plugin. The outcomes are shared among the callers and must
+// not be modified.
+//
+// Implementations must be safe for concurrent use.
+type VerificationCache interface {
+	// Get returns the outcome stored with key, or false if there is none.
+	Get(ctx context.Context, key string) (*notation.VerificationOutcome, bool)
+
+	// Set stores the outcome with key.
+	Set(ctx context.Context, key string, outcome *notation.VerificationOutcome)
+}
+
+// MemoryVerificationCache is an in-memory [VerificationCache] expiring the
+// outcomes after a TTL. Expired outcomes are evicted on access and when the
+// cache is full.
+//
+// The TTL bounds the staleness of the time-dependent checks of the cached
+// outcomes, such as the expiry and the revocation status of the certificates.
+type MemoryVerificationCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	outcome *notation.VerificationOutcome
+	expiry  time.Time
+}
+
+// NewMemoryVerificationCache creates a MemoryVerificationCache expiring the
+// outcomes after ttl, and holding at most maxEntries outcomes. If maxEntries
+// is not positive, the number of outcomes is not limited.
+func NewMemoryVerificationCache(ttl time.Duration, maxEntries int) *MemoryVerificationCache {
+	return &MemoryVerificationCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]memoryCacheEntry),
+	}
+}
+
+// Get returns the outcome stored with key, or false if there is none or it
+// has expired.
+func (c *MemoryVerificationCache) Get(ctx context.Context, key string) (*notation.VerificationOutcome, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiry) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.outcome, true
+}
+
+// Set stores the outcome with key. If the cache is full, the expired
+// outcomes are evicted first, followed by arbitrary outcomes if needed.
+func (c *MemoryVerificationCache) Set(ctx context.Context, key string, outcome *notation.VerificationOutcome) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expiry) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.maxEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = memoryCacheEntry{
+		outcome: outcome,
+		expiry:  now.Add(c.ttl),
+	}
+}
+
+// verificationCacheKey returns the cache key of the verification of the
+// signature of the artifact described by desc under trustPolicy.
+func verificationCacheKey(ctx context.Context, desc ocispec.Descriptor, sigBlob []byte, trustPolicy *trustpolicy.OCITrustPolicy, x509TrustStore truststore.X509TrustStore, pluginManager plugin.Manager, opts notation.VerifierVerifyOptions) (string, error) {
+	policyHash, err := hashJSON(trustPolicy)
+	if err != nil {
+		return "", err
+	}
+	trustStoreHash, err := hashTrustStores(ctx, trustPolicy.TrustStores, x509TrustStore)
+	if err != nil {
+		return "", err
+	}
+	verificationPlugin, err := verificationPluginIdentity(ctx, sigBlob, opts.SignatureMediaType, pluginManager)
+	if err != nil {
+		return "", err
+	}
+	// the artifact type and the annotations of the artifact, and the
+	// annotations of the signature manifest are passed to the verification
+	// plugin, which may enforce policies on them
+	optionsHash, err := hashJSON(struct {
+		SignatureMediaType           string                                                      `json:"signatureMediaType"`
+		PluginConfig                 map[string]string                                           `json:"pluginConfig,omitempty"`
+		UserMetadata                 map[string]string                                           `json:"userMetadata,omitempty"`
+		SignatureManifestAnnotations map[string]string                                           `json:"signatureManifestAnnotations,omitempty"`
+		ArtifactType                 string                                                      `json:"artifactType,omitempty"`
+		ArtifactAnnotations          map[string]string                                           `json:"artifactAnnotations,omitempty"`
+		VerificationPlugin           string                                                      `json:"verificationPlugin,omitempty"`
+		ActionOverrides              map[trustpolicy.ValidationType]trustpolicy.ValidationAction `json:"actionOverrides,omitempty"`
+		SignatureAlgorithms          []signature.Algorithm                                       `json:"signatureAlgorithms,omitempty"`
+	}{
+		SignatureMediaType:           opts.SignatureMediaType,
+		PluginConfig:                 opts.PluginConfig,
+		UserMetadata:                 opts.UserMetadata,
+		SignatureManifestAnnotations: opts.SignatureManifestAnnotations,
+		ArtifactType:                 desc.ArtifactType,
+		ArtifactAnnotations:          desc.Annotations,
+		VerificationPlugin:           verificationPlugin,
+		ActionOverrides:              opts.ActionOverrides,
+		SignatureAlgorithms:          opts.SignatureAlgorithms,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		desc.Digest.String(),
+		digest.FromBytes(sigBlob).String(),
+		policyHash,
+		trustStoreHash,
+		optionsHash,
+	}, "|"), nil
+}
+
+// verificationPluginIdentity returns the name and the version of the
+// verification plugin installed for the signature, or an empty string if the
+// signature does not require a verification plugin.
+func verificationPluginIdentity(ctx context.Context, sigBlob []byte, envelopeMediaType string, pluginManager plugin.Manager) (string, error) {
+	sigEnv, err := signature.ParseEnvelope(envelopeMediaType, sigBlob)
+	if err != nil {
+		return "", err
+	}
+	content, err := sigEnv.Content()
+	if err != nil {
+		return "", err
+	}
+	name, err := getVerificationPlugin(&content.SignerInfo)
+	if err != nil {
+		if errors.Is(err, errExtendedAttributeNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	if pluginManager == nil {
+		return "", errors.New("plugin manager is not configured")
+	}
+	installedPlugin, err := pluginManager.Get(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	metadata, err := installedPlugin.GetMetadata(ctx, &pluginframework.GetMetadataRequest{})
+	if err != nil {
+		return "", err
+	}
+	return metadata.Name + "@" + metadata.Version, nil
+}
+
+// hashTrustStores returns the hash of the certificates of the trust stores.
+func hashTrustStores(ctx context.Context, trustStores []string, x509TrustStore truststore.X509TrustStore) (string, error) {
+	h := sha256.New()
+	for _, trustStore := range trustStores {
+		storeType, name, found := strings.Cut(trustStore, ":")
+		if !found {
+			return "", fmt.Errorf("trust store %q is missing separator", trustStore)
+		}
+		certs, err := x509TrustStore.GetCertificates(ctx, truststore.Type(storeType), name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\n", trustStore)
+		for _, cert := range certs {
+			certHash := sha256.Sum256(cert.Raw)
+			h.Write(certHash[:])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashJSON returns the hash of the JSON encoding of v.
+func hashJSON(v interface{}) (string, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:]), nil
+}
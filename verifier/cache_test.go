@@ -0,0 +1,215 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestMemoryVerificationCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryVerificationCache(time.Hour, 2)
+	outcome := &notation.VerificationOutcome{}
+	if _, ok := cache.Get(ctx, "a"); ok {
+		t.Fatal("Get() found an outcome in the empty cache")
+	}
+	cache.Set(ctx, "a", outcome)
+	if got, ok := cache.Get(ctx, "a"); !ok || got != outcome {
+		t.Fatalf("Get() = %v, %v, want %v, true", got, ok, outcome)
+	}
+
+	cache.Set(ctx, "b", outcome)
+	cache.Set(ctx, "c", outcome)
+	if len(cache.entries) != 2 {
+		t.Fatalf("cache holds %d outcomes, want 2", len(cache.entries))
+	}
+
+	expiring := NewMemoryVerificationCache(-time.Second, 0)
+	expiring.Set(ctx, "a", outcome)
+	if _, ok := expiring.Get(ctx, "a"); ok {
+		t.Fatal("Get() returned an expired outcome")
+	}
+	if len(expiring.entries) != 0 {
+		t.Fatal("Get() did not evict the expired outcome")
+	}
+}
+
+// countingVerificationCache counts the cache hits.
+type countingVerificationCache struct {
+	*MemoryVerificationCache
+	hits int
+}
+
+func (c *countingVerificationCache) Get(ctx context.Context, key string) (*notation.VerificationOutcome, bool) {
+	outcome, ok := c.MemoryVerificationCache.Get(ctx, key)
+	if ok {
+		c.hits++
+	}
+	return outcome, ok
+}
+
+func TestVerifyWithVerificationCache(t *testing.T) {
+	dir.UserConfigDir = "testdata"
+	x509TrustStore := truststore.NewX509TrustStore(dir.ConfigFS())
+	cache := &countingVerificationCache{MemoryVerificationCache: NewMemoryVerificationCache(time.Hour, 0)}
+	policyDocument := dummyOCIPolicyDocument()
+	v, err := NewVerifierWithOptions(x509TrustStore, VerifierOptions{
+		OCITrustPolicy:    &policyDocument,
+		PluginManager:     mock.PluginManager{},
+		VerificationCache: cache,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	opts := notation.VerifierVerifyOptions{ArtifactReference: mock.SampleArtifactUri, SignatureMediaType: "application/jose+json"}
+	first, err := v.Verify(ctx, mock.ImageDescriptor, mock.MockCaValidSigEnv, opts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	second, err := v.Verify(ctx, mock.ImageDescriptor, mock.MockCaValidSigEnv, opts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if cache.hits != 1 || first != second {
+		t.Fatalf("Verify() did not return the cached outcome, hits = %d", cache.hits)
+	}
+
+	// different options do not hit the cache
+	opts.UserMetadata = map[string]string{"buildId": "101"}
+	if _, err := v.Verify(ctx, mock.ImageDescriptor, mock.MockCaValidSigEnv, opts); err == nil {
+		t.Fatal("expected error for missing user metadata")
+	}
+	if cache.hits != 1 {
+		t.Fatalf("Verify() returned a cached outcome for different options")
+	}
+
+	// failed verifications are not cached
+	if _, err := v.Verify(ctx, mock.ImageDescriptor, mock.MockCaInvalidSigEnv, opts); err == nil {
+		t.Fatal("expected error for invalid signature")
+	}
+	if _, err := v.Verify(ctx, mock.ImageDescriptor, mock.MockCaInvalidSigEnv, opts); err == nil {
+		t.Fatal("expected error for invalid signature")
+	}
+	if cache.hits != 1 {
+		t.Fatalf("Verify() returned a cached outcome of a failed verification")
+	}
+}
+
+func TestVerificationCacheKey(t *testing.T) {
+	dir.UserConfigDir = "testdata"
+	x509TrustStore := truststore.NewX509TrustStore(dir.ConfigFS())
+	ctx := context.Background()
+	policy := dummyOCIPolicyDocument().TrustPolicies[0]
+	opts := notation.VerifierVerifyOptions{SignatureMediaType: "application/jose+json"}
+	key, err := verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaValidSigEnv, &policy, x509TrustStore, mock.PluginManager{}, opts)
+	if err != nil {
+		t.Fatalf("verificationCacheKey() error = %v", err)
+	}
+
+	otherPolicy := policy
+	otherPolicy.TrustedIdentities = []string{"*"}
+	otherKey, err := verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaValidSigEnv, &otherPolicy, x509TrustStore, mock.PluginManager{}, opts)
+	if err != nil {
+		t.Fatalf("verificationCacheKey() error = %v", err)
+	}
+	if key == otherKey {
+		t.Fatal("verificationCacheKey() returned the same key for different trust policies")
+	}
+
+	otherPolicy = policy
+	otherPolicy.TrustStores = []string{"ca:valid-trust-store"}
+	otherKey, err = verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaValidSigEnv, &otherPolicy, x509TrustStore, mock.PluginManager{}, opts)
+	if err != nil {
+		t.Fatalf("verificationCacheKey() error = %v", err)
+	}
+	if key == otherKey {
+		t.Fatal("verificationCacheKey() returned the same key for different trust stores")
+	}
+
+	otherOpts := opts
+	otherOpts.SignatureManifestAnnotations = map[string]string{"org.example.pipeline": "release"}
+	otherKey, err = verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaValidSigEnv, &policy, x509TrustStore, mock.PluginManager{}, otherOpts)
+	if err != nil {
+		t.Fatalf("verificationCacheKey() error = %v", err)
+	}
+	if key == otherKey {
+		t.Fatal("verificationCacheKey() returned the same key for different signature manifest annotations")
+	}
+
+	otherDesc := mock.ImageDescriptor
+	otherDesc.ArtifactType = "application/vnd.example.sbom"
+	otherKey, err = verificationCacheKey(ctx, otherDesc, mock.MockCaValidSigEnv, &policy, x509TrustStore, mock.PluginManager{}, opts)
+	if err != nil {
+		t.Fatalf("verificationCacheKey() error = %v", err)
+	}
+	if key == otherKey {
+		t.Fatal("verificationCacheKey() returned the same key for different artifact types")
+	}
+
+	invalidPolicy := trustpolicy.OCITrustPolicy{TrustStores: []string{"invalid"}}
+	if _, err := verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaValidSigEnv, &invalidPolicy, x509TrustStore, mock.PluginManager{}, opts); err == nil {
+		t.Fatal("expected error for invalid trust store")
+	}
+}
+
+func TestVerificationCacheKeyPlugin(t *testing.T) {
+	dir.UserConfigDir = "testdata"
+	x509TrustStore := truststore.NewX509TrustStore(dir.ConfigFS())
+	ctx := context.Background()
+	policy := dummyOCIPolicyDocument().TrustPolicies[0]
+	opts := notation.VerifierVerifyOptions{SignatureMediaType: "application/jose+json"}
+	pluginManager := func(version string) artifactPluginManager {
+		return artifactPluginManager{plugin: &artifactPluginMock{
+			PluginMock: mock.PluginMock{
+				Metadata: proto.GetMetadataResponse{Name: "plugin-name", Version: version},
+			},
+		}}
+	}
+
+	key, err := verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaPluginSigEnv, &policy, x509TrustStore, pluginManager("1.0.0"), opts)
+	if err != nil {
+		t.Fatalf("verificationCacheKey() error = %v", err)
+	}
+	sameKey, err := verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaPluginSigEnv, &policy, x509TrustStore, pluginManager("1.0.0"), opts)
+	if err != nil {
+		t.Fatalf("verificationCacheKey() error = %v", err)
+	}
+	if key != sameKey {
+		t.Fatal("verificationCacheKey() returned different keys for the same plugin")
+	}
+	otherKey, err := verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaPluginSigEnv, &policy, x509TrustStore, pluginManager("1.1.0"), opts)
+	if err != nil {
+		t.Fatalf("verificationCacheKey() error = %v", err)
+	}
+	if key == otherKey {
+		t.Fatal("verificationCacheKey() returned the same key for different plugin versions")
+	}
+
+	// the key cannot be computed without the plugin
+	missingPlugin := mock.PluginManager{GetPluginError: errors.New("plugin not found")}
+	if _, err := verificationCacheKey(ctx, mock.ImageDescriptor, mock.MockCaPluginSigEnv, &policy, x509TrustStore, missingPlugin, opts); err == nil {
+		t.Fatal("expected error for missing plugin")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+// callTrustStore returns the trust store of the verification with opts: the
+// trust store of the verifier, overridden or supplemented by the trust store
+// of the call, if any. The named trust stores provided by plugins are read
+// from the plugins.
+func (v *verifier) callTrustStore(opts notation.VerifierVerifyOptions) (truststore.X509TrustStore, error) {
+	trustStore := v.trustStore
+	if opts.TrustStore != nil {
+		switch opts.TrustStoreMode {
+		case notation.TrustStoreOverride:
+			trustStore = opts.TrustStore
+		case notation.TrustStoreSupplement:
+			trustStore = supplementedTrustStore{opts.TrustStore, v.trustStore}
+		default:
+			return nil, fmt.Errorf("unsupported trust store mode %d", opts.TrustStoreMode)
+		}
+	}
+	return v.withPluginTrustStores(trustStore, opts.PluginConfig), nil
+}
+
+// supplementedTrustStore is a trust store returning the certificates of all
+// its trust stores.
+type supplementedTrustStore []truststore.X509TrustStore
+
+// GetCertificates returns the certificates of the named store in all the
+// trust stores. A named store missing from some of the trust stores is not an
+// error, unless it is missing from all of them.
+func (s supplementedTrustStore) GetCertificates(ctx context.Context, storeType truststore.Type, namedStore string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	var firstErr error
+	found := false
+	for _, trustStore := range s {
+		storeCerts, err := trustStore.GetCertificates(ctx, storeType, namedStore)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		found = true
+		certs = append(certs, storeCerts...)
+	}
+	if !found {
+		return nil, firstErr
+	}
+	return certs, nil
+}
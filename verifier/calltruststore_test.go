@@ -0,0 +1,135 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestVerifyWithCallTrustStore(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+	}
+	if _, _, err := notation.SignOCI(ctx, s, repo, signOpts); err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+	trusted := notationtest.NewTrustStore().Add(truststore.TypeCA, "test", s.Root())
+	untrusted := notationtest.NewTrustStore().Add(truststore.TypeCA, "test", testhelper.GetECRootCertificate().Cert)
+
+	tests := []struct {
+		name          string
+		verifierStore truststore.X509TrustStore
+		callStore     truststore.X509TrustStore
+		mode          notation.TrustStoreMode
+		wantErr       bool
+	}{
+		{
+			name:          "verifier trust store only",
+			verifierStore: untrusted,
+			wantErr:       true,
+		},
+		{
+			name:          "override",
+			verifierStore: untrusted,
+			callStore:     trusted,
+			mode:          notation.TrustStoreOverride,
+		},
+		{
+			name:          "override drops the verifier trust store",
+			verifierStore: trusted,
+			callStore:     untrusted,
+			mode:          notation.TrustStoreOverride,
+			wantErr:       true,
+		},
+		{
+			name:          "supplement",
+			verifierStore: untrusted,
+			callStore:     trusted,
+			mode:          notation.TrustStoreSupplement,
+		},
+		{
+			name:          "supplement missing named store",
+			verifierStore: notationtest.NewTrustStore(),
+			callStore:     trusted,
+			mode:          notation.TrustStoreSupplement,
+		},
+		{
+			name:          "unsupported mode",
+			verifierStore: trusted,
+			callStore:     trusted,
+			mode:          notation.TrustStoreMode(42),
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewVerifierWithOptions(tt.verifierStore, VerifierOptions{OCITrustPolicy: notationtest.TrustPolicy("test")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifyOpts := notation.VerifyOptions{
+				ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+				MaxSignatureAttempts: 10,
+				TrustStore:           tt.callStore,
+				TrustStoreMode:       tt.mode,
+			}
+			_, _, err = notation.Verify(ctx, v, repo, verifyOpts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSupplementedTrustStore(t *testing.T) {
+	ctx := context.Background()
+	rsaRoot := testhelper.GetRSARootCertificate().Cert
+	ecRoot := testhelper.GetECRootCertificate().Cert
+	store := supplementedTrustStore{
+		notationtest.NewTrustStore().Add(truststore.TypeCA, "test", rsaRoot),
+		notationtest.NewTrustStore().Add(truststore.TypeCA, "test", ecRoot).Add(truststore.TypeTSA, "tsa", ecRoot),
+	}
+	certs, err := store.GetCertificates(ctx, truststore.TypeCA, "test")
+	if err != nil {
+		t.Fatalf("GetCertificates() error = %v", err)
+	}
+	if len(certs) != 2 || !certs[0].Equal(rsaRoot) || !certs[1].Equal(ecRoot) {
+		t.Fatalf("GetCertificates() = %d certificates, want the roots of both trust stores", len(certs))
+	}
+	certs, err = store.GetCertificates(ctx, truststore.TypeTSA, "tsa")
+	if err != nil || len(certs) != 1 {
+		t.Fatalf("GetCertificates() = %d certificates, error = %v", len(certs), err)
+	}
+	if _, err := store.GetCertificates(ctx, truststore.TypeCA, "missing"); err == nil {
+		t.Fatal("GetCertificates() expects error for a store missing from all the trust stores")
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	corecrl "github.com/notaryproject/notation-core-go/revocation/crl"
+	"github.com/notaryproject/notation-go/clock"
 	"github.com/notaryproject/notation-go/internal/file"
 	"github.com/notaryproject/notation-go/log"
 )
@@ -52,6 +53,16 @@ import (
 type FileCache struct {
 	// root is the root directory of the cache
 	root string
+
+	// clock reads the time against which the CRLs expire
+	clock clock.Clock
+}
+
+// FileCacheOptions contains parameters for [NewFileCacheWithOptions].
+type FileCacheOptions struct {
+	// Clock reads the time against which the cached CRLs expire. If nil, the
+	// system time is used.
+	Clock clock.Clock
 }
 
 // fileCacheContent is the actual content saved in a FileCache
@@ -67,11 +78,18 @@ type fileCacheContent struct {
 //
 // An example for root is `dir.CacheFS().SysPath(dir.PathCRLCache)`
 func NewFileCache(root string) (*FileCache, error) {
+	return NewFileCacheWithOptions(root, FileCacheOptions{})
+}
+
+// NewFileCacheWithOptions creates a FileCache with root as the root directory
+// and opts.
+func NewFileCacheWithOptions(root string, opts FileCacheOptions) (*FileCache, error) {
 	if err := os.MkdirAll(root, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create crl file cache: %w", err)
 	}
 	return &FileCache{
-		root: root,
+		root:  root,
+		clock: opts.Clock,
 	}, nil
 }
 
@@ -109,11 +127,11 @@ func (c *FileCache) Get(ctx context.Context, url string) (*corecrl.Bundle, error
 	}
 
 	// check expiry
-	if err := checkExpiry(ctx, bundle.BaseCRL.NextUpdate); err != nil {
+	if err := checkExpiry(ctx, bundle.BaseCRL.NextUpdate, clock.Now(c.clock)); err != nil {
 		return nil, fmt.Errorf("check BaseCRL expiry failed: %w", err)
 	}
 	if bundle.DeltaCRL != nil {
-		if err := checkExpiry(ctx, bundle.DeltaCRL.NextUpdate); err != nil {
+		if err := checkExpiry(ctx, bundle.DeltaCRL.NextUpdate, clock.Now(c.clock)); err != nil {
 			return nil, fmt.Errorf("check DeltaCRL expiry failed: %w", err)
 		}
 	}
@@ -156,14 +174,14 @@ func (c *FileCache) fileName(url string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// checkExpiry returns nil when nextUpdate is bounded before current time
-func checkExpiry(ctx context.Context, nextUpdate time.Time) error {
+// checkExpiry returns nil when nextUpdate is bounded before now
+func checkExpiry(ctx context.Context, nextUpdate, now time.Time) error {
 	logger := log.GetLogger(ctx)
 
 	if nextUpdate.IsZero() {
 		return errors.New("crl bundle retrieved from file cache does not contain valid NextUpdate")
 	}
-	if time.Now().After(nextUpdate) {
+	if now.After(nextUpdate) {
 		logger.Debugf("CRL bundle retrieved from file cache has expired at %s", nextUpdate)
 		return corecrl.ErrCacheMiss
 	}
@@ -30,6 +30,7 @@ import (
 
 	corecrl "github.com/notaryproject/notation-core-go/revocation/crl"
 	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go/clock"
 )
 
 func TestCache(t *testing.T) {
@@ -393,3 +394,40 @@ func TestSetFailed(t *testing.T) {
 		}
 	})
 }
+
+func TestFileCacheWithClock(t *testing.T) {
+	now := time.Now()
+	certChain := testhelper.GetRevokableRSAChainWithRevocations(2, false, true)
+	crlBytes, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		NextUpdate: now.Add(time.Hour),
+	}, certChain[1].Cert, certChain[1].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseCRL, err := x509.ParseRevocationList(crlBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	current := now
+	cache, err := NewFileCacheWithOptions(t.TempDir(), FileCacheOptions{
+		Clock: clock.Func(func() time.Time { return current }),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "http://example.com"
+	if err := cache.Set(ctx, key, &corecrl.Bundle{BaseCRL: baseCRL}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, key); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	current = now.Add(2 * time.Hour)
+	if _, err := cache.Get(ctx, key); !errors.Is(err, corecrl.ErrCacheMiss) {
+		t.Fatalf("Get() error = %v, want ErrCacheMiss", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	corecrl "github.com/notaryproject/notation-core-go/revocation/crl"
+	"github.com/notaryproject/notation-go/clock"
+	"github.com/notaryproject/notation-go/log"
+)
+
+// MemoryCache implements corecrl.Cache in memory.
+//
+// Key: url of the CRL.
+//
+// Value: corecrl.Bundle.
+//
+// Expired CRL bundles are evicted on access. The cache holds at most one
+// bundle per CRL distribution point, so its size is bounded by the number of
+// distinct CRL urls of the verified certificate chains.
+//
+// It is safe for concurrent use.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	bundles map[string]*corecrl.Bundle
+	clock   clock.Clock
+}
+
+// MemoryCacheOptions contains parameters for [NewMemoryCacheWithOptions].
+type MemoryCacheOptions struct {
+	// Clock reads the time against which the cached CRLs expire. If nil, the
+	// system time is used.
+	Clock clock.Clock
+}
+
+// NewMemoryCache creates an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return NewMemoryCacheWithOptions(MemoryCacheOptions{})
+}
+
+// NewMemoryCacheWithOptions creates an empty MemoryCache with opts.
+func NewMemoryCacheWithOptions(opts MemoryCacheOptions) *MemoryCache {
+	return &MemoryCache{
+		bundles: make(map[string]*corecrl.Bundle),
+		clock:   opts.Clock,
+	}
+}
+
+// Get retrieves CRL bundle from c given url as key. If the key does not exist
+// or the content has expired, corecrl.ErrCacheMiss is returned.
+func (c *MemoryCache) Get(ctx context.Context, url string) (*corecrl.Bundle, error) {
+	logger := log.GetLogger(ctx)
+	c.mu.RLock()
+	bundle, ok := c.bundles[url]
+	c.mu.RUnlock()
+	if !ok {
+		logger.Debugf("CRL memory cache miss. Key %q does not exist", url)
+		return nil, corecrl.ErrCacheMiss
+	}
+	now := clock.Now(c.clock)
+	if isExpired(bundle.BaseCRL.NextUpdate, now) || (bundle.DeltaCRL != nil && isExpired(bundle.DeltaCRL.NextUpdate, now)) {
+		logger.Debugf("CRL bundle with key %q in memory cache has expired", url)
+		c.mu.Lock()
+		// the bundle may have been replaced in the meantime
+		if c.bundles[url] == bundle {
+			delete(c.bundles, url)
+		}
+		c.mu.Unlock()
+		return nil, corecrl.ErrCacheMiss
+	}
+	return bundle, nil
+}
+
+// Set stores the CRL bundle in c with url as key.
+func (c *MemoryCache) Set(ctx context.Context, url string, bundle *corecrl.Bundle) error {
+	if bundle == nil {
+		return errors.New("failed to store crl bundle in memory cache: bundle cannot be nil")
+	}
+	if bundle.BaseCRL == nil {
+		return errors.New("failed to store crl bundle in memory cache: bundle BaseCRL cannot be nil")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bundles[url] = bundle
+	return nil
+}
+
+// isExpired returns true if the CRL with nextUpdate has expired at now. CRLs
+// without NextUpdate are considered expired.
+func isExpired(nextUpdate, now time.Time) bool {
+	return nextUpdate.IsZero() || now.After(nextUpdate)
+}
@@ -0,0 +1,87 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crl
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	corecrl "github.com/notaryproject/notation-core-go/revocation/crl"
+	"github.com/notaryproject/notation-go/clock"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+	if _, err := cache.Get(ctx, "http://example.com/crl"); !errors.Is(err, corecrl.ErrCacheMiss) {
+		t.Fatalf("Get() error = %v, want ErrCacheMiss", err)
+	}
+
+	bundle := &corecrl.Bundle{BaseCRL: &x509.RevocationList{NextUpdate: time.Now().Add(time.Hour)}}
+	if err := cache.Set(ctx, "http://example.com/crl", bundle); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	got, err := cache.Get(ctx, "http://example.com/crl")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != bundle {
+		t.Fatalf("Get() = %v, want %v", got, bundle)
+	}
+
+	expired := &corecrl.Bundle{BaseCRL: &x509.RevocationList{NextUpdate: time.Now().Add(-time.Hour)}}
+	if err := cache.Set(ctx, "http://example.com/expired", expired); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := cache.Get(ctx, "http://example.com/expired"); !errors.Is(err, corecrl.ErrCacheMiss) {
+		t.Fatalf("Get() error = %v, want ErrCacheMiss", err)
+	}
+	if _, ok := cache.bundles["http://example.com/expired"]; ok {
+		t.Fatal("Get() did not evict the expired bundle")
+	}
+}
+
+func TestMemoryCache_SetError(t *testing.T) {
+	cache := NewMemoryCache()
+	if err := cache.Set(context.Background(), "url", nil); err == nil {
+		t.Fatal("expected error for nil bundle")
+	}
+	if err := cache.Set(context.Background(), "url", &corecrl.Bundle{}); err == nil {
+		t.Fatal("expected error for nil BaseCRL")
+	}
+}
+
+func TestMemoryCacheWithClock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	current := now
+	cache := NewMemoryCacheWithOptions(MemoryCacheOptions{
+		Clock: clock.Func(func() time.Time { return current }),
+	})
+	bundle := &corecrl.Bundle{BaseCRL: &x509.RevocationList{NextUpdate: now.Add(time.Hour)}}
+	if err := cache.Set(ctx, "http://example.com/crl", bundle); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := cache.Get(ctx, "http://example.com/crl"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	current = now.Add(2 * time.Hour)
+	if _, err := cache.Get(ctx, "http://example.com/crl"); !errors.Is(err, corecrl.ErrCacheMiss) {
+		t.Fatalf("Get() error = %v, want ErrCacheMiss", err)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+)
+
+// EndorsementRequired returns true if the trust policy applicable to the
+// artifact requires the signatures to be endorsed with a countersignature,
+// see [trustpolicy.Endorsement].
+func (v *verifier) EndorsementRequired(ctx context.Context, opts notation.VerifierVerifyOptions) (bool, error) {
+	trustPolicy, err := v.applicableTrustPolicy(ctx, opts.ArtifactReference, opts.ArtifactType)
+	if err != nil {
+		if opts.ArtifactType == "" && v.scopedByArtifactType(ctx, opts.ArtifactReference) {
+			// the applicable statement is selected once the artifact type
+			// is known
+			return false, nil
+		}
+		if v.shadowMode {
+			// the error is reported as a warning by Verify
+			return false, nil
+		}
+		return false, err
+	}
+	return trustPolicy.Endorsement != nil, nil
+}
+
+// VerifyEndorsement verifies the countersignature of the signature manifest
+// sigManifestDesc of the artifact opts.ArtifactReference against the
+// endorsement of the applicable trust policy, and returns the outcome upon
+// successful verification.
+//
+// The countersignature is verified with the verification level of the trust
+// policy, against the trust stores and the trusted identities of the
+// endorsement. The TSA trust stores and the denied identities of the trust
+// policy apply to the countersignature as well.
+func (v *verifier) VerifyEndorsement(ctx context.Context, sigManifestDesc ocispec.Descriptor, countersignature []byte, opts notation.VerifierVerifyOptions) (*notation.VerificationOutcome, error) {
+	outcome, err := v.verifyEndorsement(ctx, sigManifestDesc, countersignature, opts)
+	auditVerification(ctx, sigManifestDesc.Digest.String(), outcome, err)
+	if err != nil && v.shadowMode {
+		return shadowOutcome(ctx, outcome, err), nil
+	}
+	return outcome, err
+}
+
+func (v *verifier) verifyEndorsement(ctx context.Context, sigManifestDesc ocispec.Descriptor, countersignature []byte, opts notation.VerifierVerifyOptions) (*notation.VerificationOutcome, error) {
+	logger := log.GetLogger(ctx)
+	logger.Debugf("Verify countersignature of signature %v of artifact %s", sigManifestDesc.Digest, opts.ArtifactReference)
+	trustPolicy, err := v.applicableTrustPolicy(ctx, opts.ArtifactReference, opts.ArtifactType)
+	if err != nil {
+		return nil, err
+	}
+	endorsement := trustPolicy.Endorsement
+	if endorsement == nil {
+		return nil, fmt.Errorf("trust policy statement %q does not require an endorsement", trustPolicy.Name)
+	}
+
+	// ignore the error since we already validated the policy document
+	verificationLevel, _ := trustPolicy.SignatureVerification.GetVerificationLevel()
+	outcome := &notation.VerificationOutcome{
+		RawSignature:      countersignature,
+		VerificationLevel: verificationLevel,
+	}
+	if err := applyActionOverrides(logger, outcome, opts.ActionOverrides); err != nil {
+		return nil, err
+	}
+
+	trustStores := append([]string(nil), endorsement.TrustStores...)
+	for _, trustStore := range trustPolicy.TrustStores {
+		if strings.HasPrefix(trustStore, string(truststore.TypeTSA)+":") {
+			trustStores = append(trustStores, trustStore)
+		}
+	}
+	x509TrustStore, err := v.callTrustStore(opts)
+	if err != nil {
+		return nil, err
+	}
+	artifact := &artifactContext{
+		subject:                      &sigManifestDesc,
+		signatureManifestAnnotations: opts.SignatureManifestAnnotations,
+		progress:                     opts.Progress,
+	}
+	err = v.processSignature(ctx, countersignature, opts.SignatureMediaType, trustPolicy.Name, endorsement.TrustedIdentities, trustPolicy.DeniedIdentities, trustStores, x509TrustStore, trustPolicy.SignatureVerification, opts.PluginConfig, artifact, outcome)
+	if err != nil {
+		outcome.Error = err
+		return outcome, err
+	}
+	if err := verifySignatureAlgorithm(outcome, opts.SignatureAlgorithms); err != nil {
+		outcome.Error = err
+		return outcome, err
+	}
+
+	targetArtifact, err := envelope.ParsePayload(&outcome.EnvelopeContent.Payload)
+	if err != nil {
+		logger.Error("Failed to parse the payload content in the countersignature blob")
+		outcome.Error = err
+		return outcome, err
+	}
+	if !content.Equal(targetArtifact, sigManifestDesc) {
+		logger.Infof("Target artifact in countersignature payload: %+v", targetArtifact)
+		logger.Infof("Signature manifest that want to be verified: %+v", sigManifestDesc)
+		outcome.Error = errors.New("content descriptor mismatch")
+	}
+	return outcome, outcome.Error
+}
+
+// applicableTrustPolicy returns the OCI trust policy statement applicable to
+// the artifact of the artifact type.
+func (v *verifier) applicableTrustPolicy(ctx context.Context, artifactRef, artifactType string) (*trustpolicy.OCITrustPolicy, error) {
+	policyDoc, err := v.ociTrustPolicyDocument(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trustPolicy, err := policyDoc.GetApplicableTrustPolicyForArtifactType(artifactRef, artifactType)
+	if err != nil {
+		return nil, notation.ErrorNoApplicableTrustPolicy{Msg: err.Error()}
+	}
+	return trustPolicy, nil
+}
+
+// scopedByArtifactType returns true if the OCI trust policy statement
+// applicable to the artifact depends on its artifact type.
+func (v *verifier) scopedByArtifactType(ctx context.Context, artifactRef string) bool {
+	policyDoc, err := v.ociTrustPolicyDocument(ctx)
+	if err != nil {
+		return false
+	}
+	return policyDoc.ScopedByArtifactType(artifactRef)
+}
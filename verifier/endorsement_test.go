@@ -0,0 +1,102 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestVerifyEndorsement(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+	}
+	signOpts.ArtifactReference = artifactDesc.Digest.String()
+	_, sigManifestDesc, err := notation.SignOCI(ctx, s, repo, signOpts)
+	if err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+
+	trustStore := notationtest.NewTrustStore().
+		Add(truststore.TypeCA, "test", s.Root()).
+		Add(truststore.TypeCA, "endorsers", s.Root()).
+		Add(truststore.TypeCA, "untrusted", testhelper.GetECRootCertificate().Cert)
+	newVerifier := func(endorsersStore string) notation.Verifier {
+		policyDoc := notationtest.TrustPolicy("test")
+		policyDoc.Version = trustpolicy.VersionV2
+		policyDoc.TrustPolicies[0].Endorsement = &trustpolicy.Endorsement{
+			TrustStores:       []string{"ca:" + endorsersStore},
+			TrustedIdentities: []string{"*"},
+		}
+		v, err := NewVerifierWithOptions(trustStore, VerifierOptions{OCITrustPolicy: policyDoc})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+	}
+
+	// the signature is not endorsed yet
+	if _, _, err := notation.Verify(ctx, newVerifier("endorsers"), repo, verifyOpts); err == nil {
+		t.Fatal("Verify() expects error for a signature not endorsed")
+	}
+
+	signOpts.ArtifactReference = ""
+	countersigManifestDesc, err := notation.Countersign(ctx, s, repo, sigManifestDesc, signOpts)
+	if err != nil {
+		t.Fatalf("Countersign() error = %v", err)
+	}
+	_, outcomes, err := notation.Verify(ctx, newVerifier("endorsers"), repo, verifyOpts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	endorsement := outcomes[0].Endorsement
+	if endorsement == nil || endorsement.EnvelopeContent == nil {
+		t.Fatalf("Verify() outcome has no endorsement: %+v", outcomes[0])
+	}
+
+	// the countersignatures are not signatures of the artifact
+	v := newVerifier("endorsers")
+	if _, err := v.Verify(ctx, artifactDesc, endorsement.RawSignature, notation.VerifierVerifyOptions{
+		ArtifactReference:  verifyOpts.ArtifactReference,
+		SignatureMediaType: jws.MediaTypeEnvelope,
+	}); err == nil {
+		t.Fatalf("Verify() expects error for countersignature %v", countersigManifestDesc.Digest)
+	}
+
+	// the endorser is not trusted
+	if _, _, err := notation.Verify(ctx, newVerifier("untrusted"), repo, verifyOpts); err == nil {
+		t.Fatal("Verify() expects error for an untrusted endorser")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/fips"
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/internal/slices"
+)
+
+// ParseEnvelopeOptions contains parameters for [ParseEnvelope].
+type ParseEnvelopeOptions struct {
+	// MediaTypes is the allow-list of the envelope media types. If empty,
+	// all the envelope media types supported by notation-core-go are allowed.
+	MediaTypes []string
+
+	// MaxEnvelopeSize is the maximum size in bytes of the signature envelope.
+	// If set to less than or equals to zero, [DefaultMaxEnvelopeSize] is
+	// used.
+	MaxEnvelopeSize int64
+
+	// MaxPayloadSize is the maximum size in bytes of the payload of the
+	// signature envelope. If set to less than or equals to zero,
+	// [DefaultMaxPayloadSize] is used.
+	MaxPayloadSize int64
+
+	// MaxCertificateChainLength is the maximum number of certificates in the
+	// certificate chain of the signature envelope. If set to less than or
+	// equals to zero, [DefaultMaxCertificateChainLength] is used.
+	MaxCertificateChainLength int
+}
+
+// EnvelopeError is used when an untrusted signature envelope cannot be
+// parsed.
+type EnvelopeError struct {
+	// MediaType is the media type of the signature envelope.
+	MediaType string
+
+	Msg        string
+	InnerError error
+}
+
+func (e EnvelopeError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.InnerError != nil {
+		return e.InnerError.Error()
+	}
+	return "unable to parse the signature envelope"
+}
+
+func (e EnvelopeError) Unwrap() error {
+	return e.InnerError
+}
+
+// ParseEnvelope parses the signature envelope sigBlob of media type
+// mediaType received from an untrusted source, and verifies its integrity.
+//
+// The envelope is checked against the allow-list of media types and its size
+// is checked before it is parsed; the size of the payload and the length of
+// the certificate chain are checked before they are processed. Any failure,
+// including a panic of the envelope parser, is returned as an
+// [EnvelopeError] wrapping the underlying error.
+//
+// ParseEnvelope makes no trust decision: the signing identity, the
+// certificate chain, the expiry and the revocation status of the signature
+// are not verified.
+func ParseEnvelope(mediaType string, sigBlob []byte, opts ParseEnvelopeOptions) (envContent *signature.EnvelopeContent, err error) {
+	if len(opts.MediaTypes) > 0 && !slices.Contains(opts.MediaTypes, mediaType) {
+		return nil, EnvelopeError{
+			MediaType:  mediaType,
+			Msg:        fmt.Sprintf("signature envelope media type %q is not allowed", mediaType),
+			InnerError: &signature.UnsupportedSignatureFormatError{MediaType: mediaType},
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			envContent = nil
+			err = EnvelopeError{
+				MediaType: mediaType,
+				Msg:       fmt.Sprintf("unable to parse the signature envelope of media type %q: %v", mediaType, r),
+			}
+		}
+	}()
+
+	envContent, err = parseEnvelope(sigBlob, mediaType, envelopeLimits{
+		maxEnvelopeSize:           opts.MaxEnvelopeSize,
+		maxPayloadSize:            opts.MaxPayloadSize,
+		maxCertificateChainLength: opts.MaxCertificateChainLength,
+	})
+	if err != nil {
+		return nil, EnvelopeError{MediaType: mediaType, InnerError: err}
+	}
+	return envContent, nil
+}
+
+// parseEnvelope parses the signature envelope within the limits and verifies
+// its integrity.
+func parseEnvelope(sigBlob []byte, envelopeMediaType string, limits envelopeLimits) (*signature.EnvelopeContent, error) {
+	if err := limits.checkEnvelope(sigBlob); err != nil {
+		return nil, err
+	}
+
+	// parse the signature
+	sigEnv, err := signature.ParseEnvelope(envelopeMediaType, sigBlob)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the digital signature, error : %w", err)
+	}
+
+	// verify integrity
+	envContent, err := sigEnv.Verify()
+	if err != nil {
+		switch err.(type) {
+		case *signature.SignatureEnvelopeNotFoundError, *signature.InvalidSignatureError, *signature.SignatureIntegrityError:
+			return nil, err
+		default:
+			// unexpected error
+			return nil, notation.ErrorVerificationInconclusive{Msg: err.Error(), InnerError: err}
+		}
+	}
+
+	if err := limits.checkContent(envContent); err != nil {
+		return nil, err
+	}
+	if fips.Enabled() {
+		if err := fips.ValidateSignerInfo(&envContent.SignerInfo); err != nil {
+			return nil, err
+		}
+	}
+	if err := envelope.ValidatePayloadContentType(&envContent.Payload); err != nil {
+		return nil, err
+	}
+	return envContent, nil
+}
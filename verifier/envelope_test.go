@@ -0,0 +1,88 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go/internal/mock"
+)
+
+func TestParseEnvelope(t *testing.T) {
+	t.Run("valid envelope", func(t *testing.T) {
+		envContent, err := ParseEnvelope(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv, ParseEnvelopeOptions{
+			MediaTypes: []string{jws.MediaTypeEnvelope},
+		})
+		if err != nil {
+			t.Fatalf("ParseEnvelope() error = %v", err)
+		}
+		if len(envContent.Payload.Content) == 0 || len(envContent.SignerInfo.CertificateChain) == 0 {
+			t.Fatalf("ParseEnvelope() = %+v", envContent)
+		}
+	})
+
+	t.Run("media type not allowed", func(t *testing.T) {
+		_, err := ParseEnvelope(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv, ParseEnvelopeOptions{
+			MediaTypes: []string{cose.MediaTypeEnvelope},
+		})
+		var envelopeErr EnvelopeError
+		if !errors.As(err, &envelopeErr) || envelopeErr.MediaType != jws.MediaTypeEnvelope {
+			t.Fatalf("ParseEnvelope() error = %v, want EnvelopeError", err)
+		}
+		var formatErr *signature.UnsupportedSignatureFormatError
+		if !errors.As(err, &formatErr) {
+			t.Fatalf("ParseEnvelope() error = %v, want UnsupportedSignatureFormatError", err)
+		}
+	})
+
+	t.Run("envelope too large", func(t *testing.T) {
+		_, err := ParseEnvelope(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv, ParseEnvelopeOptions{
+			MaxEnvelopeSize: 16,
+		})
+		if !errors.As(err, &LimitExceededError{}) {
+			t.Fatalf("ParseEnvelope() error = %v, want LimitExceededError", err)
+		}
+	})
+
+	t.Run("certificate chain too long", func(t *testing.T) {
+		_, err := ParseEnvelope(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv, ParseEnvelopeOptions{
+			MaxCertificateChainLength: 1,
+		})
+		if !errors.As(err, &LimitExceededError{}) {
+			t.Fatalf("ParseEnvelope() error = %v, want LimitExceededError", err)
+		}
+	})
+
+	t.Run("malformed envelopes", func(t *testing.T) {
+		for _, blob := range [][]byte{nil, {}, []byte("{"), []byte(`{"payload":1}`), []byte("\xa1\x00")} {
+			for _, mediaType := range []string{jws.MediaTypeEnvelope, cose.MediaTypeEnvelope} {
+				if _, err := ParseEnvelope(mediaType, blob, ParseEnvelopeOptions{}); !errors.As(err, &EnvelopeError{}) {
+					t.Fatalf("ParseEnvelope(%q, %q) error = %v, want EnvelopeError", mediaType, blob, err)
+				}
+			}
+		}
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		_, err := ParseEnvelope("application/octet-stream", mock.MockCaValidSigEnv, ParseEnvelopeOptions{})
+		var formatErr *signature.UnsupportedSignatureFormatError
+		if !errors.As(err, &formatErr) {
+			t.Fatalf("ParseEnvelope() error = %v, want UnsupportedSignatureFormatError", err)
+		}
+	})
+}
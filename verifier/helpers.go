@@ -23,6 +23,7 @@ import (
 	"github.com/notaryproject/notation-core-go/signature"
 	"github.com/notaryproject/notation-go"
 	set "github.com/notaryproject/notation-go/internal/container"
+	"github.com/notaryproject/notation-go/internal/envelope"
 	notationsemver "github.com/notaryproject/notation-go/internal/semver"
 	"github.com/notaryproject/notation-go/internal/slices"
 	"github.com/notaryproject/notation-go/verifier/trustpolicy"
@@ -73,8 +74,9 @@ func getNonPluginExtendedCriticalAttributes(signerInfo *signature.SignerInfo) []
 	var criticalExtendedAttrs []signature.Attribute
 	for _, attr := range signerInfo.SignedAttributes.ExtendedAttributes {
 		attrStrKey, ok := attr.Key.(string)
-		// filter the plugin extended attributes
-		if ok && !slices.Contains(VerificationPluginHeaders, attrStrKey) {
+		// filter the plugin extended attributes and the registered custom
+		// protected headers validated by notation
+		if ok && !slices.Contains(VerificationPluginHeaders, attrStrKey) && !envelope.IsRegisteredProtectedHeader(attrStrKey) {
 			// TODO support other attribute types
 			// (COSE attribute keys can be numbers)
 			criticalExtendedAttrs = append(criticalExtendedAttrs, attr)
@@ -183,3 +185,13 @@ func isTSATrustStoreInPolicy(policyName string, trustStores []string) (bool, err
 	}
 	return false, nil
 }
+
+// validateProtectedHeaders validates the registered custom protected headers
+// of the COSE envelope content envContent against its target artifact.
+func validateProtectedHeaders(ctx context.Context, envContent *signature.EnvelopeContent) error {
+	targetArtifact, err := envelope.ParsePayload(&envContent.Payload)
+	if err != nil {
+		return err
+	}
+	return envelope.ValidateProtectedHeaders(ctx, &envContent.SignerInfo, targetArtifact)
+}
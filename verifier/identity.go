@@ -0,0 +1,113 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/notaryproject/notation-go"
+	trustpolicyInternal "github.com/notaryproject/notation-go/internal/trustpolicy"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// identityResolverPlugin is implemented by the plugins resolving the
+// organizational identities of signing certificates.
+type identityResolverPlugin interface {
+	ResolveIdentity(ctx context.Context, req *proto.ResolveIdentityRequest) (*proto.ResolveIdentityResponse, error)
+}
+
+// verifyTrustedIdentities verifies the signing certificate of the outcome
+// against the x509 trusted identities, then against the organizational
+// trusted identities resolved by the identity resolver plugin. The resolved
+// identities are recorded in the outcome.
+func (v *verifier) verifyTrustedIdentities(ctx context.Context, policyName string, trustedIdentities []string, pluginConfig map[string]string, outcome *notation.VerificationOutcome) error {
+	certs := outcome.EnvelopeContent.SignerInfo.CertificateChain
+	orgIdentities := trustedOrgIdentities(trustedIdentities)
+	if len(orgIdentities) == 0 || slices.Contains(trustedIdentities, trustpolicyInternal.Wildcard) {
+		return verifyX509TrustedIdentities(policyName, trustedIdentities, certs)
+	}
+	if len(orgIdentities) < len(trustedIdentities) {
+		// the signing certificate matching an x509 trusted identity is not
+		// resolved
+		if err := verifyX509TrustedIdentities(policyName, trustedIdentities, certs); err == nil {
+			return nil
+		}
+	}
+
+	resolvedIdentities, err := v.resolveIdentities(ctx, pluginConfig, outcome)
+	if err != nil {
+		return err
+	}
+	outcome.ResolvedIdentities = resolvedIdentities
+	for _, identity := range resolvedIdentities {
+		if slices.Contains(orgIdentities, identity) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signing certificate from the digital signature does not match the trusted identities defined in the trust policy %q, its organizational identities are %q", policyName, resolvedIdentities)
+}
+
+// resolveIdentities resolves the organizational identities of the signing
+// certificate of the outcome with the identity resolver plugin.
+func (v *verifier) resolveIdentities(ctx context.Context, pluginConfig map[string]string, outcome *notation.VerificationOutcome) ([]string, error) {
+	if v.identityResolver == "" {
+		return nil, fmt.Errorf("%q trusted identities require an identity resolver plugin", trustpolicyInternal.OrgIdentity)
+	}
+	installedPlugin, err := v.pluginManager.Get(ctx, v.identityResolver)
+	if err != nil {
+		return nil, fmt.Errorf("error while locating the identity resolver plugin %q. error: %w", v.identityResolver, err)
+	}
+	metadata, err := installedPlugin.GetMetadata(ctx, &pluginframework.GetMetadataRequest{PluginConfig: pluginConfig})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the metadata of the identity resolver plugin %q. error: %w", v.identityResolver, err)
+	}
+	resolver, ok := installedPlugin.(identityResolverPlugin)
+	if !ok || !metadata.HasCapability(proto.CapabilityIdentityResolver) {
+		return nil, fmt.Errorf("plugin %q does not have the %s capability", v.identityResolver, proto.CapabilityIdentityResolver)
+	}
+
+	certs := outcome.EnvelopeContent.SignerInfo.CertificateChain
+	certChain := make([][]byte, 0, len(certs))
+	for _, cert := range certs {
+		certChain = append(certChain, cert.Raw)
+	}
+	resp, err := resolver.ResolveIdentity(ctx, &proto.ResolveIdentityRequest{
+		Subject:          certs[0].Subject.String(),
+		CertificateChain: certChain,
+		PluginConfig:     pluginConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the organizational identities of the signing certificate with plugin %q. error: %w", v.identityResolver, err)
+	}
+	log.GetLogger(ctx).Debugf("Signing certificate %q resolved to organizational identities %q", certs[0].Subject, resp.Identities)
+	return resp.Identities, nil
+}
+
+// trustedOrgIdentities returns the names of the organizational trusted
+// identities.
+func trustedOrgIdentities(trustedIdentities []string) []string {
+	var orgIdentities []string
+	for _, identity := range trustedIdentities {
+		identityPrefix, identityValue, _ := strings.Cut(identity, ":")
+		if identityPrefix == trustpolicyInternal.OrgIdentity {
+			orgIdentities = append(orgIdentities, identityValue)
+		}
+	}
+	return orgIdentities
+}
@@ -0,0 +1,200 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// identityResolverPluginMock resolves the signing certificates to the
+// organizational identities.
+type identityResolverPluginMock struct {
+	mock.PluginMock
+	identities []string
+	reqs       []*proto.ResolveIdentityRequest
+}
+
+func (p *identityResolverPluginMock) ResolveIdentity(ctx context.Context, req *proto.ResolveIdentityRequest) (*proto.ResolveIdentityResponse, error) {
+	p.reqs = append(p.reqs, req)
+	return &proto.ResolveIdentityResponse{Identities: p.identities}, nil
+}
+
+// identityResolverPluginManager returns the identity resolver plugin.
+type identityResolverPluginManager struct {
+	plugin pluginframework.Plugin
+}
+
+func (m identityResolverPluginManager) Get(ctx context.Context, name string) (pluginframework.Plugin, error) {
+	if name != "com.example.idp" {
+		return nil, errors.New("plugin not found")
+	}
+	return m.plugin, nil
+}
+
+func (m identityResolverPluginManager) List(ctx context.Context) ([]string, error) {
+	return []string{"com.example.idp"}, nil
+}
+
+func newIdentityResolverPluginMock(identities []string, capabilities ...pluginframework.Capability) *identityResolverPluginMock {
+	return &identityResolverPluginMock{
+		PluginMock: mock.PluginMock{
+			Metadata: pluginframework.GetMetadataResponse{
+				Name:         "com.example.idp",
+				Version:      "1.0.0",
+				Capabilities: capabilities,
+			},
+		},
+		identities: identities,
+	}
+}
+
+func TestVerifyWithIdentityResolver(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+	}
+	if _, _, err := notation.SignOCI(ctx, s, repo, signOpts); err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+	trustStore := notationtest.NewTrustStore().Add(truststore.TypeCA, "test", s.Root())
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+	}
+
+	tests := []struct {
+		name              string
+		trustedIdentities []string
+		resolver          string
+		plugin            *identityResolverPluginMock
+		wantErr           bool
+		wantResolved      []string
+		wantRequests      int
+	}{
+		{
+			name:              "resolved identity trusted",
+			trustedIdentities: []string{"org.identity:payments-team"},
+			resolver:          "com.example.idp",
+			plugin:            newIdentityResolverPluginMock([]string{"release-managers", "payments-team"}, proto.CapabilityIdentityResolver),
+			wantResolved:      []string{"release-managers", "payments-team"},
+			wantRequests:      1,
+		},
+		{
+			name:              "resolved identity not trusted",
+			trustedIdentities: []string{"org.identity:payments-team"},
+			resolver:          "com.example.idp",
+			plugin:            newIdentityResolverPluginMock([]string{"release-managers"}, proto.CapabilityIdentityResolver),
+			wantErr:           true,
+			wantRequests:      1,
+		},
+		{
+			name:              "x509 identity trusted",
+			trustedIdentities: []string{"x509.subject:" + s.CertificateChain[0].Subject.String(), "org.identity:payments-team"},
+			resolver:          "com.example.idp",
+			plugin:            newIdentityResolverPluginMock(nil, proto.CapabilityIdentityResolver),
+		},
+		{
+			name:              "x509 identity not trusted",
+			trustedIdentities: []string{"x509.subject:CN=Unknown,O=Notary,ST=WA,C=US", "org.identity:payments-team"},
+			resolver:          "com.example.idp",
+			plugin:            newIdentityResolverPluginMock([]string{"payments-team"}, proto.CapabilityIdentityResolver),
+			wantResolved:      []string{"payments-team"},
+			wantRequests:      1,
+		},
+		{
+			name:              "no identity resolver",
+			trustedIdentities: []string{"org.identity:payments-team"},
+			plugin:            newIdentityResolverPluginMock([]string{"payments-team"}, proto.CapabilityIdentityResolver),
+			wantErr:           true,
+		},
+		{
+			name:              "missing capability",
+			trustedIdentities: []string{"org.identity:payments-team"},
+			resolver:          "com.example.idp",
+			plugin:            newIdentityResolverPluginMock([]string{"payments-team"}),
+			wantErr:           true,
+		},
+		{
+			name:              "plugin not installed",
+			trustedIdentities: []string{"org.identity:payments-team"},
+			resolver:          "com.example.other",
+			plugin:            newIdentityResolverPluginMock([]string{"payments-team"}, proto.CapabilityIdentityResolver),
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policyDoc := notationtest.TrustPolicy("test")
+			policyDoc.TrustPolicies[0].TrustedIdentities = tt.trustedIdentities
+			v, err := NewVerifierWithOptions(trustStore, VerifierOptions{
+				OCITrustPolicy:   policyDoc,
+				PluginManager:    identityResolverPluginManager{plugin: tt.plugin},
+				IdentityResolver: tt.resolver,
+			})
+			if err != nil {
+				t.Fatalf("NewVerifierWithOptions() error = %v", err)
+			}
+			_, outcomes, err := notation.Verify(ctx, v, repo, verifyOpts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tt.plugin.reqs) != tt.wantRequests {
+				t.Fatalf("resolve-identity requests = %d, want %d", len(tt.plugin.reqs), tt.wantRequests)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(outcomes[0].ResolvedIdentities, tt.wantResolved) {
+				t.Fatalf("ResolvedIdentities = %q, want %q", outcomes[0].ResolvedIdentities, tt.wantResolved)
+			}
+			if tt.wantRequests > 0 {
+				req := tt.plugin.reqs[0]
+				if req.Subject != s.CertificateChain[0].Subject.String() || len(req.CertificateChain) != len(s.CertificateChain) {
+					t.Fatalf("resolve-identity request = %+v", req)
+				}
+			}
+		})
+	}
+}
+
+func TestNewVerifierWithIdentityResolver(t *testing.T) {
+	_, err := NewVerifierWithOptions(notationtest.NewTrustStore(), VerifierOptions{
+		OCITrustPolicy:   notationtest.TrustPolicy("test"),
+		IdentityResolver: "com.example.idp",
+	})
+	if err == nil {
+		t.Fatal("NewVerifierWithOptions() expects error for identity resolver without plugin manager")
+	}
+}
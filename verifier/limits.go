@@ -0,0 +1,98 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/notaryproject/notation-core-go/signature"
+)
+
+// Default limits on the signatures to verify, which are untrusted input.
+const (
+	// DefaultMaxEnvelopeSize is the default maximum size in bytes of a
+	// signature envelope.
+	DefaultMaxEnvelopeSize = 32 * 1024 * 1024 // 32 MiB
+
+	// DefaultMaxPayloadSize is the default maximum size in bytes of the
+	// payload of a signature envelope.
+	DefaultMaxPayloadSize = 4 * 1024 * 1024 // 4 MiB
+
+	// DefaultMaxCertificateChainLength is the default maximum number of
+	// certificates in the certificate chain of a signature envelope.
+	DefaultMaxCertificateChainLength = 10
+)
+
+// LimitExceededError is used when a signature envelope exceeds a limit set
+// on the signatures to verify.
+type LimitExceededError struct {
+	Msg string
+}
+
+func (e LimitExceededError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return "signature envelope exceeds the limits"
+}
+
+// envelopeLimits are the limits enforced on signature envelopes. The limits
+// set to less than or equals to zero are replaced with the default ones.
+type envelopeLimits struct {
+	maxEnvelopeSize           int64
+	maxPayloadSize            int64
+	maxCertificateChainLength int
+}
+
+// newEnvelopeLimits returns the limits set in the verifier options.
+func newEnvelopeLimits(opts VerifierOptions) envelopeLimits {
+	return envelopeLimits{
+		maxEnvelopeSize:           opts.MaxEnvelopeSize,
+		maxPayloadSize:            opts.MaxPayloadSize,
+		maxCertificateChainLength: opts.MaxCertificateChainLength,
+	}
+}
+
+// checkEnvelope checks the size of the signature envelope. It is called
+// before the envelope is parsed.
+func (l envelopeLimits) checkEnvelope(sigBlob []byte) error {
+	maxSize := orDefault(l.maxEnvelopeSize, DefaultMaxEnvelopeSize)
+	if size := int64(len(sigBlob)); size > maxSize {
+		return LimitExceededError{Msg: fmt.Sprintf("signature envelope too large: %d bytes exceeds the limit of %d bytes", size, maxSize)}
+	}
+	return nil
+}
+
+// checkContent checks the size of the payload and the length of the
+// certificate chain of the signature envelope. It is called before the
+// payload and the certificate chain are processed.
+func (l envelopeLimits) checkContent(envContent *signature.EnvelopeContent) error {
+	maxSize := orDefault(l.maxPayloadSize, DefaultMaxPayloadSize)
+	if size := int64(len(envContent.Payload.Content)); size > maxSize {
+		return LimitExceededError{Msg: fmt.Sprintf("signature payload too large: %d bytes exceeds the limit of %d bytes", size, maxSize)}
+	}
+	maxLength := orDefault(l.maxCertificateChainLength, DefaultMaxCertificateChainLength)
+	if length := len(envContent.SignerInfo.CertificateChain); length > maxLength {
+		return LimitExceededError{Msg: fmt.Sprintf("certificate chain too long: %d certificates exceeds the limit of %d certificates", length, maxLength)}
+	}
+	return nil
+}
+
+// orDefault returns limit if it is set, defaultLimit otherwise.
+func orDefault[T int | int64](limit, defaultLimit T) T {
+	if limit > 0 {
+		return limit
+	}
+	return defaultLimit
+}
@@ -0,0 +1,83 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+)
+
+func TestVerifyIntegrityLimits(t *testing.T) {
+	sigEnv := mock.MockCaPluginSigEnv
+	tests := []struct {
+		name    string
+		limits  envelopeLimits
+		wantErr string
+	}{
+		{
+			name:   "default limits",
+			limits: envelopeLimits{},
+		},
+		{
+			name:    "envelope too large",
+			limits:  envelopeLimits{maxEnvelopeSize: int64(len(sigEnv) - 1)},
+			wantErr: "signature envelope too large",
+		},
+		{
+			name:    "payload too large",
+			limits:  envelopeLimits{maxPayloadSize: 1},
+			wantErr: "signature payload too large",
+		},
+		{
+			name:    "certificate chain too long",
+			limits:  envelopeLimits{maxCertificateChainLength: 1},
+			wantErr: "certificate chain too long",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := &notation.VerificationOutcome{VerificationLevel: trustpolicy.LevelStrict}
+			envContent, result := verifyIntegrity(sigEnv, "application/jose+json", tt.limits, outcome)
+			if tt.wantErr == "" {
+				if result.Error != nil || envContent == nil {
+					t.Fatalf("verifyIntegrity() error = %v", result.Error)
+				}
+				return
+			}
+			if result.Error == nil || !strings.Contains(result.Error.Error(), tt.wantErr) {
+				t.Fatalf("verifyIntegrity() error = %v, want %s", result.Error, tt.wantErr)
+			}
+			if result.Type != trustpolicy.TypeIntegrity || envContent != nil {
+				t.Fatalf("verifyIntegrity() = %v, %+v", envContent, result)
+			}
+		})
+	}
+}
+
+func TestNewEnvelopeLimits(t *testing.T) {
+	limits := newEnvelopeLimits(VerifierOptions{MaxEnvelopeSize: 10})
+	if err := limits.checkEnvelope(make([]byte, 10)); err != nil {
+		t.Fatalf("checkEnvelope() error = %v", err)
+	}
+	if err := limits.checkEnvelope(make([]byte, 11)); err == nil {
+		t.Fatal("checkEnvelope() expected error")
+	}
+	if err := (envelopeLimits{}).checkEnvelope(make([]byte, DefaultMaxEnvelopeSize+1)); err == nil {
+		t.Fatal("checkEnvelope() expected error with the default limit")
+	}
+}
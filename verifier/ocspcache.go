@@ -0,0 +1,127 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// maxOCSPResponseSize is the maximum size in bytes of a cached OCSP
+// response.
+const maxOCSPResponseSize = 20 * 1024
+
+// ocspCache is an http.RoundTripper caching the successful OCSP responses in
+// memory until their next update, so that the revocation checks of a
+// certificate, including the ones prefetched, share the OCSP response of its
+// responder. Expired responses are evicted on access.
+//
+// It is safe for concurrent use.
+type ocspCache struct {
+	transport http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]ocspCacheEntry
+}
+
+type ocspCacheEntry struct {
+	header     http.Header
+	body       []byte
+	nextUpdate time.Time
+}
+
+// newOCSPCache returns an ocspCache sending the requests with transport.
+func newOCSPCache(transport http.RoundTripper) *ocspCache {
+	return &ocspCache{
+		transport: transport,
+		entries:   make(map[string]ocspCacheEntry),
+	}
+}
+
+// RoundTrip returns the cached OCSP response of req, or sends req and caches
+// its response.
+func (c *ocspCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, err := ocspCacheKey(req)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !time.Now().Before(entry.nextUpdate) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if ok {
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        entry.header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(entry.body)),
+			ContentLength: int64(len(entry.body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize+1))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) > maxOCSPResponseSize {
+		return resp, nil
+	}
+	// the response is verified by the revocation validator, only its next
+	// update is read
+	ocspResp, err := ocsp.ParseResponse(body, nil)
+	if err != nil || ocspResp.NextUpdate.IsZero() {
+		return resp, nil
+	}
+	c.mu.Lock()
+	c.entries[key] = ocspCacheEntry{
+		header:     resp.Header.Clone(),
+		body:       body,
+		nextUpdate: ocspResp.NextUpdate,
+	}
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// ocspCacheKey returns the cache key of the OCSP request req: its URL for a
+// GET request, and its URL and body for a POST request.
+func ocspCacheKey(req *http.Request) (string, error) {
+	if req.Method != http.MethodPost || req.Body == nil {
+		return req.Method + " " + req.URL.String(), nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return req.Method + " " + req.URL.String() + " " + string(body), nil
+}
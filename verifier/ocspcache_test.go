@@ -0,0 +1,101 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go/notationtest"
+	"golang.org/x/crypto/ocsp"
+)
+
+// countingTransport counts the requests sent.
+type countingTransport struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests++
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOCSPCache(t *testing.T) {
+	root := testhelper.GetRSARootCertificate()
+	leaf := testhelper.GetRSALeafCertificate().Cert
+	responder := notationtest.NewOCSPResponder(root.Cert, root.PrivateKey)
+	defer responder.Close()
+	reqBytes, err := ocsp.CreateRequest(leaf, root.Cert, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := &countingTransport{}
+	cache := newOCSPCache(transport)
+	client := &http.Client{Transport: cache}
+	check := func(t *testing.T) {
+		t.Helper()
+		httpResp, err := client.Post(responder.URL, "application/ocsp-request", bytes.NewReader(reqBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer httpResp.Body.Close()
+		respBytes, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp, err := ocsp.ParseResponseForCert(respBytes, leaf, root.Cert); err != nil || resp.Status != ocsp.Good {
+			t.Fatalf("status = %v, %v, want good", resp, err)
+		}
+	}
+
+	check(t)
+	check(t)
+	if transport.requests != 1 {
+		t.Fatalf("sent %d requests, want the response cached", transport.requests)
+	}
+
+	// expired responses are fetched again
+	for key, entry := range cache.entries {
+		entry.nextUpdate = time.Now().Add(-time.Minute)
+		cache.entries[key] = entry
+	}
+	check(t)
+	if transport.requests != 2 {
+		t.Fatalf("sent %d requests, want the expired response fetched again", transport.requests)
+	}
+
+	// error responses are not cached
+	cache = newOCSPCache(transport)
+	client.Transport = cache
+	responder.SetFailureMode(notationtest.FailureRejection)
+	for range 2 {
+		httpResp, err := client.Post(responder.URL, "application/ocsp-request", bytes.NewReader(reqBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		httpResp.Body.Close()
+	}
+	if transport.requests != 4 || len(cache.entries) != 0 {
+		t.Fatalf("sent %d requests with %d cached responses, want the error responses not cached", transport.requests, len(cache.entries))
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+)
+
+// applyActionOverrides overrides the actions of the verification level of
+// the outcome with the action overrides of the caller, and records the
+// overrides in the outcome.
+func applyActionOverrides(logger log.Logger, outcome *notation.VerificationOutcome, overrides map[trustpolicy.ValidationType]trustpolicy.ValidationAction) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	level, err := outcome.VerificationLevel.Override(overrides)
+	if err != nil {
+		return fmt.Errorf("invalid action overrides: %w", err)
+	}
+	for _, validationType := range trustpolicy.ValidationTypes {
+		action, ok := overrides[validationType]
+		if !ok {
+			continue
+		}
+		policyAction := outcome.VerificationLevel.Enforcement[validationType]
+		logger.Warnf("Overriding the %q action %q of the trust policy with %q", validationType, policyAction, action)
+		outcome.ActionOverrides = append(outcome.ActionOverrides, notation.ActionOverride{
+			Type:         validationType,
+			PolicyAction: policyAction,
+			Action:       action,
+		})
+	}
+	outcome.VerificationLevel = level
+	return nil
+}
+
+// formatActionOverrides formats the action overrides for the audit events,
+// e.g. "expiry:enforce->log".
+func formatActionOverrides(overrides []notation.ActionOverride) string {
+	formatted := make([]string, 0, len(overrides))
+	for _, o := range overrides {
+		formatted = append(formatted, fmt.Sprintf("%s:%s->%s", o.Type, o.PolicyAction, o.Action))
+	}
+	return strings.Join(formatted, ",")
+}
@@ -0,0 +1,99 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestVerifyActionOverrides(t *testing.T) {
+	var events []audit.Event
+	ctx := audit.WithSink(context.Background(), audit.SinkFunc(func(ctx context.Context, event audit.Event) {
+		events = append(events, event)
+	}))
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := notation.Sign(ctx, s, repo, notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+	}); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	// the signer is not trusted
+	untrusted := testhelper.GetECRootCertificate().Cert
+	v, err := NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "test", untrusted), VerifierOptions{
+		OCITrustPolicy: notationtest.TrustPolicy("test"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+	}
+	if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err == nil {
+		t.Fatal("Verify() expects error without override")
+	}
+
+	// forensic run logging the authenticity failure
+	events = nil
+	verifyOpts.ActionOverrides = map[trustpolicy.ValidationType]trustpolicy.ValidationAction{
+		trustpolicy.TypeAuthenticity: trustpolicy.ActionLog,
+		trustpolicy.TypeRevocation:   trustpolicy.ActionSkip,
+	}
+	_, outcomes, err := notation.Verify(ctx, v, repo, verifyOpts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := []notation.ActionOverride{
+		{Type: trustpolicy.TypeAuthenticity, PolicyAction: trustpolicy.ActionEnforce, Action: trustpolicy.ActionLog},
+		{Type: trustpolicy.TypeRevocation, PolicyAction: trustpolicy.ActionEnforce, Action: trustpolicy.ActionSkip},
+	}
+	if !reflect.DeepEqual(outcomes[0].ActionOverrides, want) {
+		t.Fatalf("ActionOverrides = %+v, want %+v", outcomes[0].ActionOverrides, want)
+	}
+	if outcomes[0].VerificationLevel.Name != "custom" || outcomes[0].VerificationLevel.Enforcement[trustpolicy.TypeAuthenticity] != trustpolicy.ActionLog {
+		t.Fatalf("VerificationLevel = %+v", outcomes[0].VerificationLevel)
+	}
+	if len(events) != 1 || events[0].Type != audit.EventSignatureAccepted ||
+		events[0].Attributes["actionOverrides"] != "authenticity:enforce->log,revocation:enforce->skip" {
+		t.Fatalf("events = %+v", events)
+	}
+
+	// the integrity verification cannot be overridden
+	verifyOpts.ActionOverrides = map[trustpolicy.ValidationType]trustpolicy.ValidationAction{
+		trustpolicy.TypeIntegrity: trustpolicy.ActionLog,
+	}
+	if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err == nil {
+		t.Fatal("Verify() expects error for integrity override")
+	}
+}
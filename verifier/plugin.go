@@ -0,0 +1,102 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/plugin"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+// PluginTrustStoreName is the name of the dedicated trust store of type
+// [truststore.TypeCA] holding the certificates trusted to sign plugin
+// executable files.
+const PluginTrustStoreName = "plugins"
+
+// pluginTrustPolicyName is the name of the trust policy statement used for
+// verifying plugin signatures.
+const pluginTrustPolicyName = "plugins"
+
+// pluginSignatureVerifier implements [plugin.SignatureVerifier] by verifying
+// the signature of the plugin executable file as a blob signature.
+type pluginSignatureVerifier struct {
+	verifier *verifier
+}
+
+// NewPluginSignatureVerifier returns a [plugin.SignatureVerifier] that
+// verifies plugin signatures with strict verification level against the
+// "ca:plugins" trust store.
+//
+// trustedIdentities pins the identities of the plugin signers, e.g.
+// "x509.subject: C=US, ST=WA, O=example, CN=platform-team". If empty, any
+// signer chaining to the trust store is trusted.
+//
+// The trust policies and the plugin manager in opts are ignored.
+func NewPluginSignatureVerifier(trustStore truststore.X509TrustStore, trustedIdentities []string, opts VerifierOptions) (plugin.SignatureVerifier, error) {
+	if len(trustedIdentities) == 0 {
+		trustedIdentities = []string{"*"}
+	}
+	opts.OCITrustPolicy = nil
+	opts.PluginManager = nil
+	opts.BlobTrustPolicy = &trustpolicy.BlobDocument{
+		Version: "1.0",
+		TrustPolicies: []trustpolicy.BlobTrustPolicy{
+			{
+				Name: pluginTrustPolicyName,
+				SignatureVerification: trustpolicy.SignatureVerification{
+					VerificationLevel: trustpolicy.LevelStrict.Name,
+				},
+				TrustStores:       []string{string(truststore.TypeCA) + ":" + PluginTrustStoreName},
+				TrustedIdentities: trustedIdentities,
+			},
+		},
+	}
+	v, err := NewVerifierWithOptions(trustStore, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &pluginSignatureVerifier{verifier: v}, nil
+}
+
+// VerifyPluginSignature verifies the detached signature against the content
+// of the plugin executable file read from executable.
+func (v *pluginSignatureVerifier) VerifyPluginSignature(ctx context.Context, name string, executable io.Reader, signature []byte) error {
+	if len(signature) == 0 {
+		return errors.New("signature cannot be empty")
+	}
+	_, _, err := notation.VerifyBlob(ctx, v.verifier, executable, signature, notation.VerifyBlobOptions{
+		BlobVerifierVerifyOptions: notation.BlobVerifierVerifyOptions{
+			SignatureMediaType: signatureMediaType(signature),
+			TrustPolicyName:    pluginTrustPolicyName,
+		},
+	})
+	return err
+}
+
+// signatureMediaType returns the envelope media type of the signature.
+// JWS envelopes are JSON objects, anything else is treated as COSE.
+func signatureMediaType(signature []byte) string {
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("{")) {
+		return jws.MediaTypeEnvelope
+	}
+	return cose.MediaTypeEnvelope
+}
@@ -0,0 +1,143 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/internal/slices"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// MissingPluginAction is the action taken by the verifier when a signature
+// mandates a verification plugin that is not installed.
+type MissingPluginAction string
+
+const (
+	// MissingPluginFail fails the verification. It is the default action.
+	MissingPluginFail MissingPluginAction = "fail"
+
+	// MissingPluginWarn logs a warning and continues the verification without
+	// the plugin, provided that the signature has no critical extended
+	// attributes other than the verification plugin attributes. Otherwise,
+	// the verification fails.
+	MissingPluginWarn MissingPluginAction = "warn"
+
+	// MissingPluginInstall installs the plugin with the configured
+	// [PluginInstaller] and continues the verification with it.
+	MissingPluginInstall MissingPluginAction = "install"
+)
+
+// PluginInstaller installs a verification plugin on demand.
+type PluginInstaller interface {
+	// InstallPlugin installs the named plugin with version greater than or
+	// equal to minVersion, if not empty, such that the plugin can be
+	// retrieved by the plugin manager of the verifier afterwards.
+	InstallPlugin(ctx context.Context, name, minVersion string) error
+}
+
+// PluginInstallerFunc is an adapter to allow the use of an ordinary function
+// as a [PluginInstaller].
+type PluginInstallerFunc func(ctx context.Context, name, minVersion string) error
+
+// InstallPlugin calls f(ctx, name, minVersion).
+func (f PluginInstallerFunc) InstallPlugin(ctx context.Context, name, minVersion string) error {
+	return f(ctx, name, minVersion)
+}
+
+// validateMissingPluginAction validates the missing plugin action of the
+// verifier options.
+func validateMissingPluginAction(opts VerifierOptions) error {
+	switch opts.MissingPluginAction {
+	case "", MissingPluginFail, MissingPluginWarn:
+		return nil
+	case MissingPluginInstall:
+		if opts.PluginInstaller == nil {
+			return errors.New("pluginInstaller cannot be nil when the missing plugin action is install")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported missing plugin action %q", opts.MissingPluginAction)
+}
+
+// handleMissingPlugin applies the missing plugin action of the verifier after
+// the verification plugin could not be located. It returns the installed
+// plugin, or nil if the verification continues without the plugin. The
+// action taken is recorded in outcome.
+func (v *verifier) handleMissingPlugin(ctx context.Context, name, minVersion string, getErr error, outcome *notation.VerificationOutcome) (pluginframework.VerifyPlugin, error) {
+	logger := log.GetLogger(ctx)
+	notFoundErr := notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("error while locating the verification plugin %q, make sure the plugin is installed successfully before verifying the signature. error: %s", name, getErr)}
+	var integrityErr *plugin.PluginIntegrityError
+	if !errors.Is(getErr, fs.ErrNotExist) || errors.As(getErr, &integrityErr) {
+		// the plugin is installed but cannot be loaded, e.g. it is not signed
+		return nil, notFoundErr
+	}
+
+	switch v.missingPluginAction {
+	case MissingPluginWarn:
+		if attr, ok := unprocessableCriticalAttribute(&outcome.EnvelopeContent.SignerInfo); ok {
+			logger.Errorf("Critical extended attribute %v cannot be processed without the verification plugin %q", attr.Key, name)
+			return nil, notFoundErr
+		}
+		logger.Warnf("Verification plugin %q is not installed, continuing the verification without the plugin", name)
+		outcome.PluginFallback = &notation.PluginFallback{
+			PluginName: name,
+			Action:     string(MissingPluginWarn),
+			Reason:     getErr,
+		}
+		return nil, nil
+	case MissingPluginInstall:
+		logger.Infof("Verification plugin %q is not installed, installing it", name)
+		if err := v.pluginInstaller.InstallPlugin(ctx, name, minVersion); err != nil {
+			return nil, notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("failed to install the verification plugin %q. error: %s", name, err), InnerError: err}
+		}
+		installedPlugin, err := v.pluginManager.Get(ctx, name)
+		if err != nil {
+			return nil, notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("error while locating the verification plugin %q after installation. error: %s", name, err), InnerError: err}
+		}
+		outcome.PluginFallback = &notation.PluginFallback{
+			PluginName: name,
+			Action:     string(MissingPluginInstall),
+			Reason:     getErr,
+		}
+		return installedPlugin, nil
+	}
+	return nil, notFoundErr
+}
+
+// unprocessableCriticalAttribute returns the first critical extended
+// attribute of the signer that cannot be processed without the verification
+// plugin, i.e. any critical attribute other than the verification plugin
+// attributes. Attributes whose key is not a string, e.g. COSE attributes with
+// integer keys, are never known to notation, so they are always returned if
+// critical.
+func unprocessableCriticalAttribute(signerInfo *signature.SignerInfo) (signature.Attribute, bool) {
+	for _, attr := range signerInfo.SignedAttributes.ExtendedAttributes {
+		if !attr.Critical {
+			continue
+		}
+		if key, ok := attr.Key.(string); ok && slices.Contains(VerificationPluginHeaders, key) {
+			continue
+		}
+		return attr, true
+	}
+	return signature.Attribute{}, false
+}
@@ -0,0 +1,257 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/plugin"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// installingPluginManager returns the plugin only after it is installed.
+type installingPluginManager struct {
+	mock.PluginManager
+	installed  bool
+	minVersion string
+}
+
+func (pm *installingPluginManager) Get(ctx context.Context, name string) (pluginframework.Plugin, error) {
+	if !pm.installed {
+		return nil, fmt.Errorf("plugin %s not found: %w", name, os.ErrNotExist)
+	}
+	return pm.PluginManager.Get(ctx, name)
+}
+
+func (pm *installingPluginManager) InstallPlugin(ctx context.Context, name, minVersion string) error {
+	pm.installed = true
+	pm.minVersion = minVersion
+	return nil
+}
+
+func pluginOutcome(t *testing.T, sigEnv []byte) *notation.VerificationOutcome {
+	outcome := &notation.VerificationOutcome{VerificationLevel: trustpolicy.LevelStrict}
+	envContent, result := verifyIntegrity(sigEnv, "application/jose+json", envelopeLimits{}, outcome)
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	outcome.EnvelopeContent = envContent
+	return outcome
+}
+
+func TestHandleMissingPlugin(t *testing.T) {
+	ctx := context.Background()
+	notFound := fmt.Errorf("plugin not found: %w", os.ErrNotExist)
+
+	t.Run("fail by default", func(t *testing.T) {
+		v := &verifier{}
+		outcome := pluginOutcome(t, mock.MockCaCompatiblePluginVerSigEnv_1_0_0)
+		_, err := v.handleMissingPlugin(ctx, "plugin-name", "", notFound, outcome)
+		if !errors.As(err, &notation.ErrorVerificationInconclusive{}) {
+			t.Fatalf("handleMissingPlugin() error = %v, want ErrorVerificationInconclusive", err)
+		}
+		if outcome.PluginFallback != nil {
+			t.Fatalf("handleMissingPlugin() recorded fallback %+v", outcome.PluginFallback)
+		}
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		v := &verifier{missingPluginAction: MissingPluginWarn}
+		outcome := pluginOutcome(t, mock.MockCaCompatiblePluginVerSigEnv_1_0_0)
+		installedPlugin, err := v.handleMissingPlugin(ctx, "plugin-name", "", notFound, outcome)
+		if err != nil || installedPlugin != nil {
+			t.Fatalf("handleMissingPlugin() = %v, %v, want nil, nil", installedPlugin, err)
+		}
+		if outcome.PluginFallback == nil || outcome.PluginFallback.Action != "warn" || outcome.PluginFallback.PluginName != "plugin-name" {
+			t.Fatalf("handleMissingPlugin() recorded fallback %+v", outcome.PluginFallback)
+		}
+	})
+
+	t.Run("warn with critical extended attributes", func(t *testing.T) {
+		v := &verifier{missingPluginAction: MissingPluginWarn}
+		outcome := pluginOutcome(t, mock.MockCaPluginSigEnv)
+		if _, err := v.handleMissingPlugin(ctx, "plugin-name", "", notFound, outcome); err == nil {
+			t.Fatal("expected error for unprocessed critical extended attributes")
+		}
+	})
+
+	t.Run("warn with critical extended attributes with integer keys", func(t *testing.T) {
+		v := &verifier{missingPluginAction: MissingPluginWarn}
+		outcome := pluginOutcome(t, mock.MockCaCompatiblePluginVerSigEnv_1_0_0)
+		outcome.EnvelopeContent.SignerInfo.SignedAttributes.ExtendedAttributes = append(outcome.EnvelopeContent.SignerInfo.SignedAttributes.ExtendedAttributes, signature.Attribute{
+			Key:      int64(-70001),
+			Critical: true,
+			Value:    "value",
+		})
+		if _, err := v.handleMissingPlugin(ctx, "plugin-name", "", notFound, outcome); !errors.As(err, &notation.ErrorVerificationInconclusive{}) {
+			t.Fatalf("handleMissingPlugin() error = %v, want ErrorVerificationInconclusive", err)
+		}
+		if outcome.PluginFallback != nil {
+			t.Fatalf("handleMissingPlugin() recorded fallback %+v", outcome.PluginFallback)
+		}
+	})
+
+	t.Run("warn with non-critical extended attributes with integer keys", func(t *testing.T) {
+		v := &verifier{missingPluginAction: MissingPluginWarn}
+		outcome := pluginOutcome(t, mock.MockCaCompatiblePluginVerSigEnv_1_0_0)
+		outcome.EnvelopeContent.SignerInfo.SignedAttributes.ExtendedAttributes = append(outcome.EnvelopeContent.SignerInfo.SignedAttributes.ExtendedAttributes, signature.Attribute{
+			Key:   int64(-70001),
+			Value: "value",
+		})
+		if _, err := v.handleMissingPlugin(ctx, "plugin-name", "", notFound, outcome); err != nil {
+			t.Fatalf("handleMissingPlugin() error = %v", err)
+		}
+	})
+
+	t.Run("warn when the plugin fails to load", func(t *testing.T) {
+		v := &verifier{missingPluginAction: MissingPluginWarn}
+		outcome := pluginOutcome(t, mock.MockCaCompatiblePluginVerSigEnv_1_0_0)
+		if _, err := v.handleMissingPlugin(ctx, "plugin-name", "", errors.New("invalid plugin"), outcome); err == nil {
+			t.Fatal("expected error for plugin failing to load")
+		}
+	})
+
+	t.Run("install", func(t *testing.T) {
+		pm := &installingPluginManager{}
+		v := &verifier{pluginManager: pm, missingPluginAction: MissingPluginInstall, pluginInstaller: pm}
+		outcome := pluginOutcome(t, mock.MockCaCompatiblePluginVerSigEnv_1_0_0)
+		installedPlugin, err := v.handleMissingPlugin(ctx, "plugin-name", "1.0.0", notFound, outcome)
+		if err != nil || installedPlugin == nil {
+			t.Fatalf("handleMissingPlugin() = %v, %v", installedPlugin, err)
+		}
+		if pm.minVersion != "1.0.0" {
+			t.Fatalf("InstallPlugin() minVersion = %s, want 1.0.0", pm.minVersion)
+		}
+		if outcome.PluginFallback == nil || outcome.PluginFallback.Action != "install" {
+			t.Fatalf("handleMissingPlugin() recorded fallback %+v", outcome.PluginFallback)
+		}
+	})
+
+	t.Run("install failure", func(t *testing.T) {
+		installer := PluginInstallerFunc(func(ctx context.Context, name, minVersion string) error {
+			return errors.New("source unavailable")
+		})
+		v := &verifier{pluginManager: &installingPluginManager{}, missingPluginAction: MissingPluginInstall, pluginInstaller: installer}
+		outcome := pluginOutcome(t, mock.MockCaCompatiblePluginVerSigEnv_1_0_0)
+		if _, err := v.handleMissingPlugin(ctx, "plugin-name", "", notFound, outcome); err == nil {
+			t.Fatal("expected error for failed installation")
+		}
+	})
+}
+
+func TestVerifyWithMissingPluginInstall(t *testing.T) {
+	pm := &installingPluginManager{
+		PluginManager: mock.PluginManager{
+			PluginCapabilities: []proto.Capability{proto.CapabilityTrustedIdentityVerifier},
+			PluginRunnerExecuteResponse: &proto.VerifySignatureResponse{
+				VerificationResults: map[proto.Capability]*proto.VerificationResult{
+					proto.CapabilityTrustedIdentityVerifier: {Success: true},
+				},
+				ProcessedAttributes: []interface{}{mock.PluginExtendedCriticalAttribute.Key},
+			},
+		},
+	}
+	policyDocument := dummyOCIPolicyDocument()
+	dir.UserConfigDir = "testdata"
+	x509TrustStore := truststore.NewX509TrustStore(dir.ConfigFS())
+	v, err := NewVerifierWithOptions(x509TrustStore, VerifierOptions{
+		OCITrustPolicy:      &policyDocument,
+		PluginManager:       pm,
+		MissingPluginAction: MissingPluginInstall,
+		PluginInstaller:     pm,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := notation.VerifierVerifyOptions{ArtifactReference: mock.SampleArtifactUri, SignatureMediaType: "application/jose+json"}
+	outcome, err := v.Verify(context.Background(), mock.ImageDescriptor, mock.MockCaPluginSigEnv, opts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if outcome.PluginFallback == nil || outcome.PluginFallback.Action != "install" {
+		t.Fatalf("Verify() recorded fallback %+v", outcome.PluginFallback)
+	}
+}
+
+// unsignedPluginVerifier accepts any plugin signature.
+type unsignedPluginVerifier struct{}
+
+func (unsignedPluginVerifier) VerifyPluginSignature(ctx context.Context, name string, executable io.Reader, signature []byte) error {
+	return nil
+}
+
+func TestVerifyWithMissingPluginWarnUnsignedPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+	// the verification plugin is installed without its signature file
+	pluginDir := t.TempDir()
+	executablePath := filepath.Join(pluginDir, "io.cncf.notary.plugin.unittest.mock", "notation-io.cncf.notary.plugin.unittest.mock")
+	if err := os.MkdirAll(filepath.Dir(executablePath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(executablePath, nil, 0700); err != nil {
+		t.Fatal(err)
+	}
+	pm := plugin.NewCLIManagerWithOptions(dir.NewSysFS(pluginDir), plugin.CLIManagerOptions{SignatureVerifier: unsignedPluginVerifier{}})
+
+	policyDocument := dummyOCIPolicyDocument()
+	policyDocument.TrustPolicies[0].TrustedIdentities = []string{"*"}
+	policyDocument.TrustPolicies[0].RegistryScopes = []string{"*"}
+	dir.UserConfigDir = "testdata"
+	x509TrustStore := truststore.NewX509TrustStore(dir.ConfigFS())
+	v, err := NewVerifierWithOptions(x509TrustStore, VerifierOptions{
+		OCITrustPolicy:      &policyDocument,
+		PluginManager:       pm,
+		MissingPluginAction: MissingPluginWarn,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := notation.VerifierVerifyOptions{ArtifactReference: "localhost:5000/net-monitor@" + mock.TestImageDescriptor.Digest.String(), SignatureMediaType: "application/jose+json"}
+	outcome, err := v.Verify(context.Background(), mock.TestImageDescriptor, mock.MockCaCompatiblePluginVerSigEnv_1_0_0, opts)
+	if !errors.As(err, &notation.ErrorVerificationInconclusive{}) {
+		t.Fatalf("Verify() error = %v, want ErrorVerificationInconclusive", err)
+	}
+	if outcome != nil && outcome.PluginFallback != nil {
+		t.Fatalf("Verify() recorded fallback %+v for an unsigned plugin", outcome.PluginFallback)
+	}
+}
+
+func TestValidateMissingPluginAction(t *testing.T) {
+	policyDocument := dummyOCIPolicyDocument()
+	if _, err := NewVerifierWithOptions(store, VerifierOptions{OCITrustPolicy: &policyDocument, MissingPluginAction: MissingPluginInstall}); err == nil {
+		t.Fatal("expected error for missing plugin installer")
+	}
+	if _, err := NewVerifierWithOptions(store, VerifierOptions{OCITrustPolicy: &policyDocument, MissingPluginAction: "ignore"}); err == nil {
+		t.Fatal("expected error for unsupported missing plugin action")
+	}
+	if _, err := NewVerifierWithOptions(store, VerifierOptions{OCITrustPolicy: &policyDocument, MissingPluginAction: MissingPluginWarn}); err != nil {
+		t.Fatalf("NewVerifierWithOptions() error = %v", err)
+	}
+}
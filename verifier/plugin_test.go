@@ -0,0 +1,90 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/cose"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/signer"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+type pluginTrustStore struct {
+	certs []*x509.Certificate
+}
+
+func (ts pluginTrustStore) GetCertificates(ctx context.Context, storeType truststore.Type, namedStore string) ([]*x509.Certificate, error) {
+	if storeType != truststore.TypeCA || namedStore != PluginTrustStoreName {
+		return nil, nil
+	}
+	return ts.certs, nil
+}
+
+func TestPluginSignatureVerifier(t *testing.T) {
+	ctx := context.Background()
+	certTuple := testhelper.GetRSASelfSignedSigningCertTuple("Notation Plugin Signing Test")
+	s, err := signer.NewGenericSigner(certTuple.PrivateKey, []*x509.Certificate{certTuple.Cert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executable := "plugin executable content"
+
+	for _, mediaType := range []string{jws.MediaTypeEnvelope, cose.MediaTypeEnvelope} {
+		t.Run(mediaType, func(t *testing.T) {
+			sig, _, err := notation.SignBlob(ctx, s, strings.NewReader(executable), notation.SignBlobOptions{
+				SignerSignOptions: notation.SignerSignOptions{
+					SignatureMediaType: mediaType,
+				},
+				ContentMediaType: "application/octet-stream",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v, err := NewPluginSignatureVerifier(pluginTrustStore{certs: []*x509.Certificate{certTuple.Cert}}, nil, VerifierOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := v.VerifyPluginSignature(ctx, "foo", strings.NewReader(executable), sig); err != nil {
+				t.Fatalf("VerifyPluginSignature() error = %v", err)
+			}
+			if err := v.VerifyPluginSignature(ctx, "foo", strings.NewReader("tampered"), sig); err == nil {
+				t.Fatal("expected error for tampered plugin executable file")
+			}
+
+			untrusted, err := NewPluginSignatureVerifier(pluginTrustStore{}, nil, VerifierOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := untrusted.VerifyPluginSignature(ctx, "foo", strings.NewReader(executable), sig); err == nil {
+				t.Fatal("expected error for plugin signed by untrusted signer")
+			}
+
+			pinned, err := NewPluginSignatureVerifier(pluginTrustStore{certs: []*x509.Certificate{certTuple.Cert}}, []string{"x509.subject: C=US, ST=WA, O=Someone Else"}, VerifierOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := pinned.VerifyPluginSignature(ctx, "foo", strings.NewReader(executable), sig); err == nil {
+				t.Fatal("expected error for plugin signed by untrusted identity")
+			}
+		})
+	}
+}
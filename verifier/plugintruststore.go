@@ -0,0 +1,94 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/notaryproject/notation-go/plugin"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// trustStorePlugin is implemented by the plugins supplying the contents of
+// named trust stores.
+type trustStorePlugin interface {
+	GetTrustStore(ctx context.Context, req *proto.GetTrustStoreRequest) (*proto.GetTrustStoreResponse, error)
+}
+
+// withPluginTrustStores returns trustStore, reading the named trust stores
+// provided by plugins, e.g. "ca:plugin:com.example.pki:corp-roots", from the
+// plugins with pluginConfig.
+func (v *verifier) withPluginTrustStores(trustStore truststore.X509TrustStore, pluginConfig map[string]string) truststore.X509TrustStore {
+	return pluginBackedTrustStore{
+		X509TrustStore: trustStore,
+		pluginManager:  v.pluginManager,
+		pluginConfig:   pluginConfig,
+	}
+}
+
+// pluginBackedTrustStore is a trust store reading the named trust stores
+// provided by plugins from the plugins with the TRUST_STORE capability, and
+// the other named trust stores from the underlying trust store.
+type pluginBackedTrustStore struct {
+	truststore.X509TrustStore
+	pluginManager plugin.Manager
+	pluginConfig  map[string]string
+}
+
+// GetCertificates returns certificates under storeType/namedStore
+func (s pluginBackedTrustStore) GetCertificates(ctx context.Context, storeType truststore.Type, namedStore string) ([]*x509.Certificate, error) {
+	pluginName, storeName, ok := truststore.ParsePluginStoreName(namedStore)
+	if !ok {
+		return s.X509TrustStore.GetCertificates(ctx, storeType, namedStore)
+	}
+	if s.pluginManager == nil {
+		return nil, truststore.TrustStoreError{Msg: fmt.Sprintf("the trust store %q of type %q is provided by plugin %q, but the verifier has no plugin manager", namedStore, storeType, pluginName)}
+	}
+	installedPlugin, err := s.pluginManager.Get(ctx, pluginName)
+	if err != nil {
+		return nil, truststore.TrustStoreError{InnerError: err, Msg: fmt.Sprintf("error while locating the plugin %q of trust store %q of type %q. error: %s", pluginName, namedStore, storeType, err)}
+	}
+	metadata, err := installedPlugin.GetMetadata(ctx, &pluginframework.GetMetadataRequest{PluginConfig: s.pluginConfig})
+	if err != nil {
+		return nil, truststore.TrustStoreError{InnerError: err, Msg: fmt.Sprintf("failed to get the metadata of plugin %q of trust store %q of type %q. error: %s", pluginName, namedStore, storeType, err)}
+	}
+	provider, ok := installedPlugin.(trustStorePlugin)
+	if !ok || !metadata.HasCapability(proto.CapabilityTrustStore) {
+		return nil, truststore.TrustStoreError{Msg: fmt.Sprintf("plugin %q of trust store %q of type %q does not have the %s capability", pluginName, namedStore, storeType, proto.CapabilityTrustStore)}
+	}
+	resp, err := provider.GetTrustStore(ctx, &proto.GetTrustStoreRequest{
+		StoreType:    string(storeType),
+		StoreName:    storeName,
+		PluginConfig: s.pluginConfig,
+	})
+	if err != nil {
+		return nil, truststore.TrustStoreError{InnerError: err, Msg: fmt.Sprintf("failed to get the trust store %q of type %q from plugin %q. error: %s", namedStore, storeType, pluginName, err)}
+	}
+	certs := make([]*x509.Certificate, 0, len(resp.Certificates))
+	for _, der := range resp.Certificates {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, truststore.CertificateError{InnerError: err, Msg: fmt.Sprintf("failed to parse a trusted certificate of trust store %s of type %s from plugin %s", namedStore, storeType, pluginName)}
+		}
+		certs = append(certs, cert)
+	}
+	if err := truststore.ValidateCertificatesForType(storeType, certs); err != nil {
+		return nil, truststore.CertificateError{InnerError: err, Msg: fmt.Sprintf("failed to validate the trusted certificates of trust store %s of type %s from plugin %s", namedStore, storeType, pluginName)}
+	}
+	return certs, nil
+}
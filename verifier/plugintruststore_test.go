@@ -0,0 +1,203 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
+)
+
+// trustStorePluginMock supplies the contents of named trust stores.
+type trustStorePluginMock struct {
+	mock.PluginMock
+	stores map[string][][]byte
+	req    *proto.GetTrustStoreRequest
+}
+
+func (p *trustStorePluginMock) GetTrustStore(ctx context.Context, req *proto.GetTrustStoreRequest) (*proto.GetTrustStoreResponse, error) {
+	p.req = req
+	certs, ok := p.stores[req.StoreType+":"+req.StoreName]
+	if !ok {
+		return nil, errors.New("trust store not found")
+	}
+	return &proto.GetTrustStoreResponse{Certificates: certs}, nil
+}
+
+// trustStorePluginManager returns the trust store plugin.
+type trustStorePluginManager struct {
+	plugin *trustStorePluginMock
+}
+
+func (m trustStorePluginManager) Get(ctx context.Context, name string) (pluginframework.Plugin, error) {
+	if name != "com.example.pki" {
+		return nil, errors.New("plugin not found")
+	}
+	return m.plugin, nil
+}
+
+func (m trustStorePluginManager) List(ctx context.Context) ([]string, error) {
+	return []string{"com.example.pki"}, nil
+}
+
+func newTrustStorePluginMock(capabilities ...pluginframework.Capability) *trustStorePluginMock {
+	return &trustStorePluginMock{
+		PluginMock: mock.PluginMock{
+			Metadata: pluginframework.GetMetadataResponse{
+				Name:         "com.example.pki",
+				Version:      "1.0.0",
+				Capabilities: capabilities,
+			},
+		},
+		stores: map[string][][]byte{
+			"ca:corp-roots":   {testhelper.GetRSARootCertificate().Cert.Raw},
+			"tsa:corp-roots":  {testhelper.GetRSALeafCertificate().Cert.Raw},
+			"ca:invalid-cert": {[]byte("invalid")},
+		},
+	}
+}
+
+func TestVerifyWithPluginTrustStore(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+	}
+	if _, _, err := notation.SignOCI(ctx, s, repo, signOpts); err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+
+	policyDoc := notationtest.TrustPolicy("test")
+	policyDoc.TrustPolicies[0].TrustStores = []string{"ca:plugin:com.example.pki:corp-roots"}
+	trustStorePlugin := newTrustStorePluginMock(proto.CapabilityTrustStore)
+	v, err := NewVerifierWithOptions(notationtest.NewTrustStore(), VerifierOptions{
+		OCITrustPolicy: policyDoc,
+		PluginManager:  trustStorePluginManager{plugin: trustStorePlugin},
+	})
+	if err != nil {
+		t.Fatalf("NewVerifierWithOptions() error = %v", err)
+	}
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+		PluginConfig:         map[string]string{"endpoint": "https://pki.example.com"},
+	}
+	if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if trustStorePlugin.req == nil || trustStorePlugin.req.StoreType != "ca" || trustStorePlugin.req.StoreName != "corp-roots" {
+		t.Fatalf("get-trust-store request = %+v", trustStorePlugin.req)
+	}
+	if trustStorePlugin.req.PluginConfig["endpoint"] != "https://pki.example.com" {
+		t.Fatalf("get-trust-store plugin config = %v", trustStorePlugin.req.PluginConfig)
+	}
+}
+
+func TestPluginBackedTrustStore(t *testing.T) {
+	ctx := context.Background()
+	localStore := notationtest.NewTrustStore().Add(truststore.TypeCA, "local", testhelper.GetECRootCertificate().Cert)
+	newStore := func(manager trustStorePluginManager) truststore.X509TrustStore {
+		v := &verifier{trustStore: localStore, pluginManager: manager}
+		return v.withPluginTrustStores(localStore, nil)
+	}
+
+	store := newStore(trustStorePluginManager{plugin: newTrustStorePluginMock(proto.CapabilityTrustStore)})
+	certs, err := store.GetCertificates(ctx, truststore.TypeCA, "plugin:com.example.pki:corp-roots")
+	if err != nil || len(certs) != 1 || !certs[0].Equal(testhelper.GetRSARootCertificate().Cert) {
+		t.Fatalf("GetCertificates() = %v, error = %v", certs, err)
+	}
+	certs, err = store.GetCertificates(ctx, truststore.TypeCA, "local")
+	if err != nil || len(certs) != 1 || !certs[0].Equal(testhelper.GetECRootCertificate().Cert) {
+		t.Fatalf("GetCertificates() = %v, error = %v", certs, err)
+	}
+
+	var storeErr truststore.TrustStoreError
+	var certErr truststore.CertificateError
+	tests := []struct {
+		name       string
+		store      truststore.X509TrustStore
+		storeType  truststore.Type
+		namedStore string
+		target     any
+	}{
+		{
+			name:       "plugin not installed",
+			store:      store,
+			storeType:  truststore.TypeCA,
+			namedStore: "plugin:com.example.other:corp-roots",
+			target:     &storeErr,
+		},
+		{
+			name:       "missing capability",
+			store:      newStore(trustStorePluginManager{plugin: newTrustStorePluginMock(proto.CapabilityTrustedIdentityVerifier)}),
+			storeType:  truststore.TypeCA,
+			namedStore: "plugin:com.example.pki:corp-roots",
+			target:     &storeErr,
+		},
+		{
+			name:       "nil plugin manager",
+			store:      (&verifier{}).withPluginTrustStores(localStore, nil),
+			storeType:  truststore.TypeCA,
+			namedStore: "plugin:com.example.pki:corp-roots",
+			target:     &storeErr,
+		},
+		{
+			name:       "plugin error",
+			store:      store,
+			storeType:  truststore.TypeCA,
+			namedStore: "plugin:com.example.pki:missing",
+			target:     &storeErr,
+		},
+		{
+			name:       "invalid certificate",
+			store:      store,
+			storeType:  truststore.TypeCA,
+			namedStore: "plugin:com.example.pki:invalid-cert",
+			target:     &certErr,
+		},
+		{
+			name:       "non-root TSA certificate",
+			store:      store,
+			storeType:  truststore.TypeTSA,
+			namedStore: "plugin:com.example.pki:corp-roots",
+			target:     &certErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.store.GetCertificates(ctx, tt.storeType, tt.namedStore)
+			if !errors.As(err, tt.target) {
+				t.Fatalf("GetCertificates() error = %v, want %T", err, tt.target)
+			}
+		})
+	}
+}
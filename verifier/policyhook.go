@@ -0,0 +1,199 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/internal/envelope"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/tspclient-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// PolicyHook evaluates organization rules richer than the trust policy
+// supports, after the signature is verified against the trust policy. See
+// [VerifierOptions].PolicyHook.
+type PolicyHook interface {
+	// Evaluate returns the decision of the policy on the verified
+	// signature described by input.
+	Evaluate(ctx context.Context, input *PolicyInput) (*PolicyDecision, error)
+}
+
+// PolicyInput describes a verified signature to a [PolicyHook].
+type PolicyInput struct {
+	// ArtifactReference is the reference of the verified artifact.
+	ArtifactReference string `json:"artifactReference"`
+
+	// Artifact is the descriptor of the verified artifact.
+	Artifact ocispec.Descriptor `json:"artifact"`
+
+	// TrustPolicy is the name of the trust policy statement applied.
+	TrustPolicy string `json:"trustPolicy"`
+
+	// VerificationLevel is the name of the verification level applied.
+	VerificationLevel string `json:"verificationLevel"`
+
+	// Signer is the signing certificate.
+	Signer *PolicyCertificate `json:"signer,omitempty"`
+
+	// CertificateChain is the certificate chain of the signature, starting
+	// with the signing certificate.
+	CertificateChain []PolicyCertificate `json:"certificateChain"`
+
+	// SigningScheme is the signing scheme of the signature, e.g.
+	// "notary.x509".
+	SigningScheme string `json:"signingScheme"`
+
+	// SigningTime is the signing time signed by the signer.
+	SigningTime *time.Time `json:"signingTime,omitempty"`
+
+	// Expiry is the expiry of the signature, if any.
+	Expiry *time.Time `json:"expiry,omitempty"`
+
+	// Timestamp is the time of the timestamp countersignature, if any.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+
+	// SigningAgent is the unsigned signing agent of the signature.
+	SigningAgent string `json:"signingAgent,omitempty"`
+
+	// UserMetadata is the user metadata signed in the payload.
+	UserMetadata map[string]string `json:"userMetadata,omitempty"`
+}
+
+// PolicyCertificate describes a certificate of a [PolicyInput].
+type PolicyCertificate struct {
+	// Subject is the subject of the certificate, e.g. "CN=wabbit-networks.io,O=Notary,C=US".
+	Subject string `json:"subject"`
+
+	// Issuer is the issuer of the certificate.
+	Issuer string `json:"issuer"`
+
+	// SerialNumber is the serial number of the certificate in hexadecimal.
+	SerialNumber string `json:"serialNumber"`
+
+	// SHA256Fingerprint is the hex encoded SHA-256 fingerprint of the
+	// certificate.
+	SHA256Fingerprint string `json:"sha256Fingerprint"`
+
+	// NotBefore is the start of the validity of the certificate.
+	NotBefore time.Time `json:"notBefore"`
+
+	// NotAfter is the end of the validity of the certificate.
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// PolicyDecision is the decision of a [PolicyHook].
+type PolicyDecision struct {
+	// Allow is true if the signature is allowed.
+	Allow bool `json:"allow"`
+
+	// Reasons explain the decision, e.g. the rules denying the signature.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// evaluatePolicyHook evaluates the policy hook on the verified signature of
+// the outcome. If the policy denies the signature or cannot be evaluated, it
+// returns a copy of the outcome failed with the error, so that the outcome,
+// which may be cached, is not modified.
+func (v *verifier) evaluatePolicyHook(ctx context.Context, desc ocispec.Descriptor, artifactRef, policyName string, outcome *notation.VerificationOutcome) (*notation.VerificationOutcome, error) {
+	if v.policyHook == nil || outcome.EnvelopeContent == nil {
+		return outcome, nil
+	}
+	input := newPolicyInput(desc, artifactRef, policyName, outcome)
+	decision, err := v.policyHook.Evaluate(ctx, input)
+	if err != nil {
+		err = notation.ErrorVerificationFailed{Msg: fmt.Sprintf("failed to evaluate the policy hook: %v", err)}
+	} else if decision == nil || !decision.Allow {
+		msg := fmt.Sprintf("signature of %s denied by the policy hook", desc.Digest)
+		if decision != nil && len(decision.Reasons) > 0 {
+			msg += ": " + strings.Join(decision.Reasons, "; ")
+		}
+		err = notation.ErrorVerificationFailed{Msg: msg}
+	}
+	if err == nil {
+		return outcome, nil
+	}
+	log.GetLogger(ctx).Infof("Policy hook failed the verification of %v: %v", desc.Digest, err)
+	failed := *outcome
+	failed.Error = err
+	return &failed, err
+}
+
+// newPolicyInput returns the policy input describing the verified signature
+// of the outcome.
+func newPolicyInput(desc ocispec.Descriptor, artifactRef, policyName string, outcome *notation.VerificationOutcome) *PolicyInput {
+	signerInfo := outcome.EnvelopeContent.SignerInfo
+	input := &PolicyInput{
+		ArtifactReference: artifactRef,
+		Artifact:          desc,
+		TrustPolicy:       policyName,
+		CertificateChain:  make([]PolicyCertificate, 0, len(signerInfo.CertificateChain)),
+		SigningScheme:     string(signerInfo.SignedAttributes.SigningScheme),
+		SigningTime:       policyTime(signerInfo.SignedAttributes.SigningTime),
+		Expiry:            policyTime(signerInfo.SignedAttributes.Expiry),
+		SigningAgent:      signerInfo.UnsignedAttributes.SigningAgent,
+	}
+	if outcome.VerificationLevel != nil {
+		input.VerificationLevel = outcome.VerificationLevel.Name
+	}
+	for _, cert := range signerInfo.CertificateChain {
+		input.CertificateChain = append(input.CertificateChain, newPolicyCertificate(cert))
+	}
+	if len(input.CertificateChain) > 0 {
+		input.Signer = &input.CertificateChain[0]
+	}
+	if ts := signerInfo.UnsignedAttributes.TimestampSignature; len(ts) > 0 {
+		// the timestamp is verified by the verifier if required by the
+		// trust policy
+		if token, err := tspclient.ParseSignedToken(ts); err == nil {
+			if info, err := token.Info(); err == nil {
+				input.Timestamp = policyTime(info.GenTime)
+			}
+		}
+	}
+	if targetArtifact, err := envelope.ParsePayload(&outcome.EnvelopeContent.Payload); err == nil {
+		input.UserMetadata = targetArtifact.Annotations
+	}
+	return input
+}
+
+// newPolicyCertificate returns the policy description of the certificate.
+func newPolicyCertificate(cert *x509.Certificate) PolicyCertificate {
+	fingerprint := sha256.Sum256(cert.Raw)
+	return PolicyCertificate{
+		Subject:           cert.Subject.String(),
+		Issuer:            cert.Issuer.String(),
+		SerialNumber:      cert.SerialNumber.Text(16),
+		SHA256Fingerprint: hex.EncodeToString(fingerprint[:]),
+		NotBefore:         cert.NotBefore.UTC(),
+		NotAfter:          cert.NotAfter.UTC(),
+	}
+}
+
+// policyTime returns t in UTC, or nil if t is zero.
+func policyTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
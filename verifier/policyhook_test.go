@@ -0,0 +1,120 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+// policyHookFunc is a PolicyHook function.
+type policyHookFunc func(ctx context.Context, input *PolicyInput) (*PolicyDecision, error)
+
+func (f policyHookFunc) Evaluate(ctx context.Context, input *PolicyInput) (*PolicyDecision, error) {
+	return f(ctx, input)
+}
+
+func TestPolicyHook(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+		UserMetadata:      map[string]string{"team": "rockets"},
+	}
+	if _, _, err := notation.SignOCI(ctx, s, repo, signOpts); err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+
+	var (
+		inputs   []*PolicyInput
+		decision *PolicyDecision
+		hookErr  error
+	)
+	hook := policyHookFunc(func(ctx context.Context, input *PolicyInput) (*PolicyDecision, error) {
+		inputs = append(inputs, input)
+		return decision, hookErr
+	})
+	v, err := NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "test", s.Root()), VerifierOptions{
+		OCITrustPolicy:    notationtest.TrustPolicy("test"),
+		VerificationCache: NewMemoryVerificationCache(time.Hour, 10),
+		PolicyHook:        hook,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+	}
+
+	// allowed
+	decision = &PolicyDecision{Allow: true}
+	if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(inputs) != 1 {
+		t.Fatalf("policy hook evaluated %d times, want 1", len(inputs))
+	}
+	input := inputs[0]
+	if input.ArtifactReference != verifyOpts.ArtifactReference || input.Artifact.Digest != artifactDesc.Digest || input.TrustPolicy == "" || input.VerificationLevel != "strict" {
+		t.Fatalf("policy input = %+v", input)
+	}
+	if input.Signer == nil || input.Signer.Subject != s.CertificateChain[0].Subject.String() || len(input.CertificateChain) != 2 {
+		t.Fatalf("policy input signer = %+v, chain = %+v", input.Signer, input.CertificateChain)
+	}
+	if input.SigningTime == nil || input.UserMetadata["team"] != "rockets" || input.SigningScheme != "notary.x509" {
+		t.Fatalf("policy input = %+v", input)
+	}
+
+	// denied, the hook is evaluated on the cached outcome
+	decision = &PolicyDecision{Reasons: []string{"team rockets is not allowed"}}
+	_, _, err = notation.Verify(ctx, v, repo, verifyOpts)
+	var verificationErr notation.ErrorVerificationFailed
+	if !errors.As(err, &verificationErr) || !strings.Contains(err.Error(), "team rockets is not allowed") {
+		t.Fatalf("Verify() error = %v, want denied by the policy hook", err)
+	}
+	if len(inputs) != 2 {
+		t.Fatalf("policy hook evaluated %d times, want 2", len(inputs))
+	}
+
+	// the cached outcome is not modified by the denial
+	decision = &PolicyDecision{Allow: true}
+	if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	// evaluation failure
+	hookErr = errors.New("policy engine unavailable")
+	_, _, err = notation.Verify(ctx, v, repo, verifyOpts)
+	if err == nil || !strings.Contains(err.Error(), "policy engine unavailable") {
+		t.Fatalf("Verify() error = %v, want policy hook failure", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/revocation"
+	"github.com/notaryproject/notation-core-go/signature"
+)
+
+// PrefetchRevocation fetches the revocation data of the certificate chain of
+// the signature envelope in advance, so that the revocation validators of
+// the verifier cache them, e.g. the CRLs and the OCSP responses cached by a
+// [ServerVerifier]. The signature is not verified against a trust policy,
+// and the revocation status is not reported. The revocation data of the
+// timestamping certificate chain are not prefetched.
+//
+// PrefetchRevocation implements [notation.RevocationPrefetcher].
+func (v *verifier) PrefetchRevocation(ctx context.Context, sigBlob []byte, signatureMediaType string) error {
+	if v.revocationCodeSigningValidator == nil && v.revocationClient == nil {
+		return errors.New("code signing revocation validator cannot be nil")
+	}
+	envContent, err := parseEnvelope(sigBlob, signatureMediaType, v.limits)
+	if err != nil {
+		return err
+	}
+	var authenticSigningTime time.Time
+	if envContent.SignerInfo.SignedAttributes.SigningScheme == signature.SigningSchemeX509SigningAuthority {
+		authenticSigningTime, _ = envContent.SignerInfo.AuthenticSigningTime()
+	}
+
+	validator := v.revocationCodeSigningValidator
+	if validator == nil {
+		// the deprecated revocation client does not accept a context
+		validator = contextRevocation{client: v.revocationClient}
+	}
+	if _, err := withRevocationTimeout(validator, v.revocationTimeout).ValidateContext(ctx, revocation.ValidateContextOptions{
+		CertChain:            envContent.SignerInfo.CertificateChain,
+		AuthenticSigningTime: authenticSigningTime,
+	}); err != nil {
+		return fmt.Errorf("failed to check revocation status: %w", err)
+	}
+	return nil
+}
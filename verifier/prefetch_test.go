@@ -0,0 +1,105 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/revocation"
+	revocationresult "github.com/notaryproject/notation-core-go/revocation/result"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/notationtest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// recordingRevocation records the certificate chains checked.
+type recordingRevocation struct {
+	err        error
+	certChains [][]*x509.Certificate
+}
+
+func (r *recordingRevocation) Validate(certChain []*x509.Certificate, signingTime time.Time) ([]*revocationresult.CertRevocationResult, error) {
+	return r.ValidateContext(context.Background(), revocation.ValidateContextOptions{CertChain: certChain})
+}
+
+func (r *recordingRevocation) ValidateContext(ctx context.Context, opts revocation.ValidateContextOptions) ([]*revocationresult.CertRevocationResult, error) {
+	r.certChains = append(r.certChains, opts.CertChain)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return []*revocationresult.CertRevocationResult{{Result: revocationresult.ResultOK}}, nil
+}
+
+func TestPrefetchRevocation(t *testing.T) {
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    "sha256:c0d488a800e4127c334ad20d61d7bc21b4097540327217dfab52262adc02380c",
+		Size:      528,
+	}
+	sigBlob, _, err := s.Sign(context.Background(), desc, notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator := &recordingRevocation{}
+	v, err := NewVerifierWithOptions(notationtest.NewTrustStore(), VerifierOptions{
+		OCITrustPolicy:                 notationtest.TrustPolicy("test"),
+		RevocationCodeSigningValidator: validator,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prefetcher notation.RevocationPrefetcher = v
+	if err := prefetcher.PrefetchRevocation(context.Background(), sigBlob, jws.MediaTypeEnvelope); err != nil {
+		t.Fatalf("PrefetchRevocation() error = %v", err)
+	}
+	if len(validator.certChains) != 1 || len(validator.certChains[0]) != len(s.CertificateChain) || !validator.certChains[0][0].Equal(s.CertificateChain[0]) {
+		t.Fatalf("PrefetchRevocation() checked %v, want the signing certificate chain", validator.certChains)
+	}
+
+	// tampered envelope
+	if err := v.PrefetchRevocation(context.Background(), []byte("{}"), jws.MediaTypeEnvelope); err == nil {
+		t.Fatal("PrefetchRevocation() expects error for invalid envelope")
+	}
+
+	// revocation service failure
+	validator.err = errors.New("crl distribution point unavailable")
+	if err := v.PrefetchRevocation(context.Background(), sigBlob, jws.MediaTypeEnvelope); !errors.Is(err, validator.err) {
+		t.Fatalf("PrefetchRevocation() error = %v, want %v", err, validator.err)
+	}
+}
+
+func TestServerVerifierPrefetchRevocation(t *testing.T) {
+	validator := &recordingRevocation{err: errors.New("ocsp responder unavailable")}
+	s, err := NewServerVerifier(notationtest.NewTrustStore(), VerifierOptions{
+		OCITrustPolicy:                 notationtest.TrustPolicy("test"),
+		RevocationCodeSigningValidator: validator,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prefetcher notation.RevocationPrefetcher = s
+	if err := prefetcher.PrefetchRevocation(context.Background(), []byte("{}"), jws.MediaTypeEnvelope); err == nil {
+		t.Fatal("PrefetchRevocation() expects error for invalid envelope")
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxRegoResponseBytes is the maximum size of the decisions of the Open
+// Policy Agent server.
+const maxRegoResponseBytes = 1 << 20
+
+// RegoPolicyHookOptions contains the optional parameters of
+// [NewRegoPolicyHook].
+type RegoPolicyHookOptions struct {
+	// Client is the HTTP client, e.g. with TLS client certificates. Defaults
+	// to [http.DefaultClient].
+	Client *http.Client
+
+	// Token returns the bearer token authenticating the requests, if set.
+	Token func(ctx context.Context) (string, error)
+}
+
+// RegoPolicyHook is a [PolicyHook] evaluating a Rego policy with the Data API
+// of an Open Policy Agent server. The [PolicyInput] is the input document of
+// the query.
+//
+// The decision is either a boolean, e.g. of the rule
+//
+//	package notation
+//	default allow := false
+//	allow if input.signer.issuer == "CN=Acme Rockets CA,O=Acme Rockets"
+//
+// queried with the path "notation/allow", or an object with the fields
+// "allow" and, optionally, "reasons", e.g. of the rules
+//
+//	package notation
+//	reasons contains msg if { ... }
+//	decision := {"allow": count(reasons) == 0, "reasons": reasons}
+//
+// queried with the path "notation/decision". An undefined decision denies
+// the signature.
+type RegoPolicyHook struct {
+	endpoint *url.URL
+	client   *http.Client
+	token    func(ctx context.Context) (string, error)
+}
+
+// NewRegoPolicyHook creates a [RegoPolicyHook] querying the decision at path,
+// e.g. "notation/allow", of the Open Policy Agent server at serverURL, e.g.
+// "http://localhost:8181".
+func NewRegoPolicyHook(serverURL, path string, opts RegoPolicyHookOptions) (*RegoPolicyHook, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid open policy agent server URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid open policy agent server URL %q: an http or https URL is required", serverURL)
+	}
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil, errors.New("decision path not specified")
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RegoPolicyHook{
+		endpoint: u.JoinPath("v1", "data", path),
+		client:   client,
+		token:    opts.Token,
+	}, nil
+}
+
+// Evaluate queries the decision of the Rego policy on input.
+func (h *RegoPolicyHook) Evaluate(ctx context.Context, input *PolicyInput) (*PolicyDecision, error) {
+	body, err := json.Marshal(struct {
+		Input *PolicyInput `json:"input"`
+	}{Input: input})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if h.token != nil {
+		token, err := h.token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the token of the open policy agent server: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRegoResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxRegoResponseBytes {
+		return nil, fmt.Errorf("response of the open policy agent server exceeds %d bytes", maxRegoResponseBytes)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(content, &errResp); err != nil || errResp.Message == "" {
+			errResp.Message = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("open policy agent server returned status %d: %s", resp.StatusCode, errResp.Message)
+	}
+	var result struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("malformed response of the open policy agent server: %w", err)
+	}
+	return parseRegoDecision(result.Result)
+}
+
+// parseRegoDecision parses the decision of the Rego policy.
+func parseRegoDecision(result json.RawMessage) (*PolicyDecision, error) {
+	if len(result) == 0 {
+		return &PolicyDecision{Reasons: []string{"policy decision is undefined"}}, nil
+	}
+	var allow bool
+	if err := json.Unmarshal(result, &allow); err == nil {
+		return &PolicyDecision{Allow: allow}, nil
+	}
+	var decision struct {
+		Allow   *bool    `json:"allow"`
+		Reasons []string `json:"reasons"`
+	}
+	if err := json.Unmarshal(result, &decision); err != nil || decision.Allow == nil {
+		return nil, fmt.Errorf("policy decision %s is neither a boolean nor an object with a boolean allow field", result)
+	}
+	return &PolicyDecision{
+		Allow:   *decision.Allow,
+		Reasons: decision.Reasons,
+	}, nil
+}
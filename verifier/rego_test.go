@@ -0,0 +1,113 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRegoPolicyHook(t *testing.T) {
+	var gotInput PolicyInput
+	var response string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/data/notation/decision" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want bearer token", got)
+		}
+		var req struct {
+			Input PolicyInput `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode the request: %v", err)
+		}
+		gotInput = req.Input
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	hook, err := NewRegoPolicyHook(server.URL, "/notation/decision", RegoPolicyHookOptions{
+		Token: func(ctx context.Context) (string, error) { return "secret", nil },
+	})
+	if err != nil {
+		t.Fatalf("NewRegoPolicyHook() error = %v", err)
+	}
+	input := &PolicyInput{
+		ArtifactReference: "registry.acme-rockets.io/software/net-monitor:v1",
+		TrustPolicy:       "wabbit-networks-images",
+		Signer:            &PolicyCertificate{Subject: "CN=wabbit-networks.io"},
+		CertificateChain:  []PolicyCertificate{{Subject: "CN=wabbit-networks.io"}},
+	}
+
+	tests := []struct {
+		name     string
+		response string
+		want     *PolicyDecision
+	}{
+		{"boolean allow", `{"result": true}`, &PolicyDecision{Allow: true}},
+		{"boolean deny", `{"result": false}`, &PolicyDecision{}},
+		{"object", `{"result": {"allow": false, "reasons": ["unknown team"]}}`, &PolicyDecision{Reasons: []string{"unknown team"}}},
+		{"undefined", `{}`, &PolicyDecision{Reasons: []string{"policy decision is undefined"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response = tt.response
+			got, err := hook.Evaluate(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+			if gotInput.ArtifactReference != input.ArtifactReference || gotInput.Signer == nil || gotInput.Signer.Subject != input.Signer.Subject {
+				t.Fatalf("input = %+v, want %+v", gotInput, input)
+			}
+		})
+	}
+
+	for _, resp := range []string{`{"result": "yes"}`, `{"result": {"reasons": []}}`, `{`} {
+		response = resp
+		if _, err := hook.Evaluate(context.Background(), input); err == nil {
+			t.Fatalf("Evaluate() expects error for response %s", resp)
+		}
+	}
+	status = http.StatusBadRequest
+	response = `{"code": "invalid_parameter", "message": "invalid input"}`
+	if _, err := hook.Evaluate(context.Background(), input); err == nil || err.Error() != "open policy agent server returned status 400: invalid input" {
+		t.Fatalf("Evaluate() error = %v, want server error", err)
+	}
+}
+
+func TestNewRegoPolicyHookError(t *testing.T) {
+	for _, tt := range []struct {
+		serverURL string
+		path      string
+	}{
+		{"://invalid", "notation/allow"},
+		{"unix:///var/run/opa.sock", "notation/allow"},
+		{"http://localhost:8181", "/"},
+	} {
+		if _, err := NewRegoPolicyHook(tt.serverURL, tt.path, RegoPolicyHookOptions{}); err == nil {
+			t.Fatalf("NewRegoPolicyHook(%q, %q) expects error", tt.serverURL, tt.path)
+		}
+	}
+}
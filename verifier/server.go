@@ -0,0 +1,216 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/notaryproject/notation-core-go/revocation"
+	"github.com/notaryproject/notation-core-go/revocation/purpose"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/plugin"
+	"github.com/notaryproject/notation-go/verifier/crl"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ServerVerifier implements [notation.Verifier] and [notation.BlobVerifier]
+// for long-running processes such as admission controllers and registry
+// webhooks, which create the verifier once and share it among requests.
+//
+// Compared to the verifier returned by [NewVerifierWithOptions]:
+//   - the certificates of the named trust stores are loaded once and cached
+//     until [ServerVerifier.Reload] is called.
+//   - the revocation validators and their CRL cache are shared by all
+//     verifications. Unless validators are provided in the options, CRLs and
+//     OCSP responses are cached in memory.
+//   - the trust policy documents and the trust store can be reloaded with
+//     [ServerVerifier.Reload] without disrupting in-flight verifications.
+//
+// A ServerVerifier is safe for concurrent use. The per-call behavior is
+// controlled by the context and the options passed to each verification.
+//
+// Memory characteristics: a ServerVerifier holds the trust policy documents,
+// the certificates of the named trust stores referenced by the applicable
+// trust policies, and, with the default revocation validators, at most one
+// CRL bundle per CRL distribution point and one OCSP response per certificate
+// of the verified certificate chains. Expired CRLs and OCSP responses are
+// evicted on access. Memory usage therefore grows with the number of
+// distinct trust stores, CRL distribution points and certificates, not with
+// the number of verifications.
+type ServerVerifier struct {
+	trustStore *truststore.CachedX509TrustStore
+	opts       VerifierOptions
+	load       func() (*trustpolicy.OCIDocument, *trustpolicy.BlobDocument, error)
+
+	// reloadLock serializes the reloads
+	reloadLock sync.Mutex
+	current    atomic.Pointer[verifier]
+}
+
+// NewServerVerifier creates a new ServerVerifier given trustStore and
+// verifierOptions. See [NewVerifierWithOptions] for the options.
+//
+// Reload of the returned verifier reloads the trust store only.
+func NewServerVerifier(trustStore truststore.X509TrustStore, verifierOptions VerifierOptions) (*ServerVerifier, error) {
+	ociTrustPolicy := verifierOptions.OCITrustPolicy
+	blobTrustPolicy := verifierOptions.BlobTrustPolicy
+	return newServerVerifier(trustStore, verifierOptions, func() (*trustpolicy.OCIDocument, *trustpolicy.BlobDocument, error) {
+		return ociTrustPolicy, blobTrustPolicy, nil
+	})
+}
+
+// NewServerVerifierFromConfig creates a new OCI ServerVerifier based on local
+// file system. The OCI trust policy and the trust store are loaded from the
+// user configuration directory, and reloaded by Reload.
+//
+// The trust policy documents in verifierOptions are ignored. If
+// verifierOptions.PluginManager is nil, the plugins installed in the user
+// plugin directory are used.
+func NewServerVerifierFromConfig(verifierOptions VerifierOptions) (*ServerVerifier, error) {
+	if verifierOptions.PluginManager == nil {
+		verifierOptions.PluginManager = plugin.NewCLIManager(dir.PluginFS())
+	}
+	return newServerVerifier(truststore.NewX509TrustStore(dir.ConfigFS()), verifierOptions, func() (*trustpolicy.OCIDocument, *trustpolicy.BlobDocument, error) {
+		policyDocument, err := trustpolicy.LoadOCIDocument()
+		if err != nil {
+			return nil, nil, err
+		}
+		return policyDocument, nil, nil
+	})
+}
+
+func newServerVerifier(trustStore truststore.X509TrustStore, verifierOptions VerifierOptions, load func() (*trustpolicy.OCIDocument, *trustpolicy.BlobDocument, error)) (*ServerVerifier, error) {
+	if trustStore == nil {
+		return nil, errors.New("trustStore cannot be nil")
+	}
+	cachedTrustStore, ok := trustStore.(*truststore.CachedX509TrustStore)
+	if !ok {
+		cachedTrustStore = truststore.NewCachedX509TrustStore(trustStore)
+	}
+	if err := setSharedRevocation(&verifierOptions); err != nil {
+		return nil, err
+	}
+	s := &ServerVerifier{
+		trustStore: cachedTrustStore,
+		opts:       verifierOptions,
+		load:       load,
+	}
+	if err := s.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// setSharedRevocation sets the revocation validators of opts sharing an
+// in-memory CRL cache and OCSP response cache, if not provided.
+func setSharedRevocation(opts *VerifierOptions) error {
+	if opts.RevocationCodeSigningValidator != nil && opts.RevocationTimestampingValidator != nil {
+		return nil
+	}
+	ocspHTTPClient, fetcher, err := newRevocationClients(opts.Network)
+	if err != nil {
+		return err
+	}
+	fetcher.Cache = crl.NewMemoryCache()
+	fetcher.DiscardCacheError = true
+	ocspHTTPClient.Transport = newOCSPCache(ocspHTTPClient.Transport)
+
+	// RevocationClient takes precedence over the default code signing
+	// validator for backwards compatibility
+	if opts.RevocationCodeSigningValidator == nil && opts.RevocationClient == nil {
+		opts.RevocationCodeSigningValidator, err = revocation.NewWithOptions(revocation.Options{
+			OCSPHTTPClient:   ocspHTTPClient,
+			CRLFetcher:       fetcher,
+			CertChainPurpose: purpose.CodeSigning,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if opts.RevocationTimestampingValidator == nil {
+		opts.RevocationTimestampingValidator, err = revocation.NewWithOptions(revocation.Options{
+			OCSPHTTPClient:   ocspHTTPClient,
+			CRLFetcher:       fetcher,
+			CertChainPurpose: purpose.Timestamping,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload reloads the trust policy documents and discards the cached trust
+// store certificates. Verifications in progress complete with the previous
+// configuration. If the new configuration is invalid, an error is returned
+// and the previous configuration is kept.
+func (s *ServerVerifier) Reload(ctx context.Context) error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	ociTrustPolicy, blobTrustPolicy, err := s.load()
+	if err != nil {
+		return err
+	}
+	opts := s.opts
+	opts.OCITrustPolicy = ociTrustPolicy
+	opts.BlobTrustPolicy = blobTrustPolicy
+	v, err := NewVerifierWithOptions(s.trustStore, opts)
+	if err != nil {
+		return err
+	}
+	s.trustStore.Reset()
+	s.current.Store(v)
+	log.GetLogger(ctx).Debug("Reloaded the trust policies and the trust store of the server verifier")
+	return nil
+}
+
+// SkipVerify validates whether the verification level is skip.
+func (s *ServerVerifier) SkipVerify(ctx context.Context, opts notation.VerifierVerifyOptions) (bool, *trustpolicy.VerificationLevel, error) {
+	return s.current.Load().SkipVerify(ctx, opts)
+}
+
+// PrefetchRevocation fetches the revocation data of the certificate chain of
+// the signature envelope in advance into the caches of the revocation
+// validators. See [notation.Prefetch].
+func (s *ServerVerifier) PrefetchRevocation(ctx context.Context, signature []byte, signatureMediaType string) error {
+	return s.current.Load().PrefetchRevocation(ctx, signature, signatureMediaType)
+}
+
+// TrustedThumbprints returns the hex-encoded SHA-256 thumbprints of the
+// certificates trusted for the artifact.
+func (s *ServerVerifier) TrustedThumbprints(ctx context.Context, opts notation.VerifierVerifyOptions) ([]string, error) {
+	return s.current.Load().TrustedThumbprints(ctx, opts)
+}
+
+// Verify verifies the signature associated to the target OCI artifact with
+// manifest descriptor desc, and returns the outcome upon successful
+// verification.
+func (s *ServerVerifier) Verify(ctx context.Context, desc ocispec.Descriptor, signature []byte, opts notation.VerifierVerifyOptions) (*notation.VerificationOutcome, error) {
+	return s.current.Load().Verify(ctx, desc, signature, opts)
+}
+
+// VerifyBlob verifies the signature of the blob described by the descriptor
+// returned by descGenFunc, and returns the outcome upon successful
+// verification.
+func (s *ServerVerifier) VerifyBlob(ctx context.Context, descGenFunc notation.BlobDescriptorGenerator, signature []byte, opts notation.BlobVerifierVerifyOptions) (*notation.VerificationOutcome, error) {
+	return s.current.Load().VerifyBlob(ctx, descGenFunc, signature, opts)
+}
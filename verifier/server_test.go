@@ -0,0 +1,126 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+// countingTrustStore counts the loads of the underlying trust store.
+type countingTrustStore struct {
+	truststore.X509TrustStore
+	loads atomic.Int32
+}
+
+func (s *countingTrustStore) GetCertificates(ctx context.Context, storeType truststore.Type, namedStore string) ([]*x509.Certificate, error) {
+	s.loads.Add(1)
+	return s.X509TrustStore.GetCertificates(ctx, storeType, namedStore)
+}
+
+func TestServerVerifier(t *testing.T) {
+	dir.UserConfigDir = "testdata"
+	store := &countingTrustStore{X509TrustStore: truststore.NewX509TrustStore(dir.ConfigFS())}
+	policyDocument := dummyOCIPolicyDocument()
+	v, err := NewServerVerifier(store, VerifierOptions{OCITrustPolicy: &policyDocument, PluginManager: mock.PluginManager{}})
+	if err != nil {
+		t.Fatalf("NewServerVerifier() error = %v", err)
+	}
+
+	opts := notation.VerifierVerifyOptions{ArtifactReference: mock.SampleArtifactUri, SignatureMediaType: "application/jose+json"}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := v.Verify(context.Background(), mock.ImageDescriptor, mock.MockCaValidSigEnv, opts); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	loads := store.loads.Load()
+	if _, err := v.Verify(context.Background(), mock.ImageDescriptor, mock.MockCaValidSigEnv, opts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if store.loads.Load() != loads {
+		t.Fatal("Verify() loaded the cached trust store again")
+	}
+
+	if err := v.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if _, err := v.Verify(context.Background(), mock.ImageDescriptor, mock.MockCaValidSigEnv, opts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if store.loads.Load() == loads {
+		t.Fatal("Verify() did not load the trust store after Reload()")
+	}
+}
+
+func TestServerVerifier_Reload(t *testing.T) {
+	dir.UserConfigDir = "testdata"
+	policyDocument := dummyOCIPolicyDocument()
+	loadErr := errors.New("invalid trust policy")
+	var fail bool
+	v, err := newServerVerifier(truststore.NewX509TrustStore(dir.ConfigFS()), VerifierOptions{}, func() (*trustpolicy.OCIDocument, *trustpolicy.BlobDocument, error) {
+		if fail {
+			return nil, nil, loadErr
+		}
+		return &policyDocument, nil, nil
+	})
+	if err != nil {
+		t.Fatalf("newServerVerifier() error = %v", err)
+	}
+	previous := v.current.Load()
+
+	fail = true
+	if err := v.Reload(context.Background()); !errors.Is(err, loadErr) {
+		t.Fatalf("Reload() error = %v, want %v", err, loadErr)
+	}
+	if v.current.Load() != previous {
+		t.Fatal("Reload() replaced the verifier on failure")
+	}
+
+	fail = false
+	if err := v.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	current := v.current.Load()
+	if current == previous {
+		t.Fatal("Reload() did not replace the verifier")
+	}
+	if current.revocationCodeSigningValidator != previous.revocationCodeSigningValidator {
+		t.Fatal("Reload() did not share the revocation validator")
+	}
+}
+
+func TestNewServerVerifierError(t *testing.T) {
+	if _, err := NewServerVerifier(nil, VerifierOptions{}); err == nil {
+		t.Fatal("expected error for nil trust store")
+	}
+	if _, err := NewServerVerifier(truststore.NewX509TrustStore(dir.ConfigFS()), VerifierOptions{}); err == nil {
+		t.Fatal("expected error for missing trust policy")
+	}
+}
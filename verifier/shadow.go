@@ -0,0 +1,42 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"slices"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/log"
+)
+
+// shadowOutcomeWarning prefixes the warning reporting a verification failure
+// in shadow mode.
+const shadowOutcomeWarning = "shadow mode, verification failure not enforced: "
+
+// shadowOutcome returns a successful copy of the outcome of the verification
+// failed with err, with err in its ShadowedError and in its warnings. The
+// outcome may be nil, e.g. if no trust policy is applicable.
+func shadowOutcome(ctx context.Context, outcome *notation.VerificationOutcome, err error) *notation.VerificationOutcome {
+	log.GetLogger(ctx).Warnf("Verification failure not enforced in shadow mode: %v", err)
+	var shadowed notation.VerificationOutcome
+	if outcome != nil {
+		// the outcome may be shared with the verification cache
+		shadowed = *outcome
+	}
+	shadowed.Error = nil
+	shadowed.ShadowedError = err
+	shadowed.Warnings = append(slices.Clone(shadowed.Warnings), shadowOutcomeWarning+err.Error())
+	return &shadowed
+}
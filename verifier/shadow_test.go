@@ -0,0 +1,105 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/notationtest"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestShadowMode(t *testing.T) {
+	ctx := context.Background()
+	repo := notationtest.NewRepository()
+	artifactDesc, err := repo.PushArtifact(ctx, "v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := notationtest.NewSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signOpts := notation.SignOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ArtifactReference: artifactDesc.Digest.String(),
+	}
+	if _, _, err := notation.SignOCI(ctx, s, repo, signOpts); err != nil {
+		t.Fatalf("SignOCI() error = %v", err)
+	}
+	trustStore := notationtest.NewTrustStore().Add(truststore.TypeCA, "test", s.Root())
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    "localhost:5000/notationtest@" + artifactDesc.Digest.String(),
+		MaxSignatureAttempts: 10,
+	}
+
+	// the signer is not trusted
+	policyDoc := notationtest.TrustPolicy("test")
+	policyDoc.TrustPolicies[0].TrustedIdentities = []string{"x509.subject:CN=Unknown,O=Notary,ST=WA,C=US"}
+	v, err := NewVerifierWithOptions(trustStore, VerifierOptions{OCITrustPolicy: policyDoc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := notation.Verify(ctx, v, repo, verifyOpts); err == nil {
+		t.Fatal("Verify() expects error without shadow mode")
+	}
+
+	var events []audit.Event
+	auditCtx := audit.WithSink(ctx, audit.SinkFunc(func(ctx context.Context, event audit.Event) {
+		events = append(events, event)
+	}))
+	v, err = NewVerifierWithOptions(trustStore, VerifierOptions{OCITrustPolicy: policyDoc, ShadowMode: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, outcomes, err := notation.Verify(auditCtx, v, repo, verifyOpts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v, want success in shadow mode", err)
+	}
+	outcome := outcomes[0]
+	if outcome.Error != nil || outcome.ShadowedError == nil || !strings.Contains(outcome.ShadowedError.Error(), "trusted identities") {
+		t.Fatalf("outcome error = %v, shadowed error = %v", outcome.Error, outcome.ShadowedError)
+	}
+	if len(outcome.Warnings) != 1 || !strings.HasPrefix(outcome.Warnings[0], shadowOutcomeWarning) {
+		t.Fatalf("outcome warnings = %v", outcome.Warnings)
+	}
+	if outcome.EnvelopeContent == nil || len(outcome.VerificationResults) == 0 {
+		t.Fatalf("outcome detail not preserved: %+v", outcome)
+	}
+	if len(events) == 0 || events[len(events)-1].Type != audit.EventSignatureRejected {
+		t.Fatalf("audit events = %+v, want the rejection", events)
+	}
+
+	// no applicable trust policy
+	policyDoc = notationtest.TrustPolicy("test")
+	policyDoc.TrustPolicies[0].RegistryScopes = []string{"localhost:5000/other"}
+	v, err = NewVerifierWithOptions(trustStore, VerifierOptions{OCITrustPolicy: policyDoc, ShadowMode: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, outcomes, err = notation.Verify(ctx, v, repo, verifyOpts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v, want success in shadow mode", err)
+	}
+	var noPolicyErr notation.ErrorNoApplicableTrustPolicy
+	if !errors.As(outcomes[0].ShadowedError, &noPolicyErr) {
+		t.Fatalf("shadowed error = %v, want ErrorNoApplicableTrustPolicy", outcomes[0].ShadowedError)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+)
+
+type contextKey int
+
+// tenantKey is the associated key type for the tenant ID in context.
+const tenantKey contextKey = iota
+
+// WithTenant returns a context selecting the OCI trust policy document of
+// the tenant, as set in [VerifierOptions].TenantOCITrustPolicies, for the
+// verifications performed with the context.
+//
+// A multi-tenant caller, e.g. an admission controller serving several
+// namespaces, shares a single verifier and selects the policy partition per
+// request. The verification of a tenant without a trust policy document
+// fails.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenantID)
+}
+
+// TenantFromContext returns the tenant ID selected with [WithTenant], if
+// any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey).(string)
+	return tenantID, ok
+}
+
+// validateTenantTrustPolicies validates the trust policy documents of the
+// tenants.
+func validateTenantTrustPolicies(policies map[string]*trustpolicy.OCIDocument) error {
+	for tenantID, policyDoc := range policies {
+		if tenantID == "" {
+			return errors.New("tenant ID of an oci trust policy cannot be empty")
+		}
+		if policyDoc == nil {
+			return fmt.Errorf("oci trust policy of tenant %q cannot be nil", tenantID)
+		}
+		if err := policyDoc.Validate(); err != nil {
+			return fmt.Errorf("oci trust policy of tenant %q: %w", tenantID, err)
+		}
+	}
+	return nil
+}
+
+// ociTrustPolicyDocument returns the OCI trust policy document of the tenant
+// selected in ctx, or the default document if no tenant is selected.
+func (v *verifier) ociTrustPolicyDocument(ctx context.Context) (*trustpolicy.OCIDocument, error) {
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		policyDoc, ok := v.tenantOCITrustPolicyDocs[tenantID]
+		if !ok {
+			return nil, notation.ErrorNoApplicableTrustPolicy{Msg: fmt.Sprintf("tenant %q has no oci trust policy document", tenantID)}
+		}
+		return policyDoc, nil
+	}
+	if v.ociTrustPolicyDoc == nil {
+		return nil, errors.New("ociTrustPolicyDoc is nil")
+	}
+	return v.ociTrustPolicyDoc, nil
+}
@@ -0,0 +1,104 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func skipOCIPolicyDocument() *trustpolicy.OCIDocument {
+	return &trustpolicy.OCIDocument{
+		Version: "1.0",
+		TrustPolicies: []trustpolicy.OCITrustPolicy{
+			{
+				Name:                  "skip-statement",
+				RegistryScopes:        []string{"*"},
+				SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: "skip"},
+			},
+		},
+	}
+}
+
+func TestTenantTrustPolicies(t *testing.T) {
+	strictDoc := dummyOCIPolicyDocument()
+	v, err := NewVerifierWithOptions(&testTrustStore{}, VerifierOptions{
+		TenantOCITrustPolicies: map[string]*trustpolicy.OCIDocument{
+			"team-a": skipOCIPolicyDocument(),
+			"team-b": &strictDoc,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewVerifierWithOptions() error = %v", err)
+	}
+	opts := notation.VerifierVerifyOptions{
+		ArtifactReference: "registry.acme-rockets.io/software/net-monitor@sha256:60043cf45eaebc4c0867fea485a039b598f52fd09fd5b07b0b2d2f88fad9d74e",
+	}
+
+	skip, _, err := v.SkipVerify(WithTenant(context.Background(), "team-a"), opts)
+	if err != nil {
+		t.Fatalf("SkipVerify() error = %v", err)
+	}
+	if !skip {
+		t.Fatal("SkipVerify() = false for team-a, want true")
+	}
+	skip, level, err := v.SkipVerify(WithTenant(context.Background(), "team-b"), opts)
+	if err != nil {
+		t.Fatalf("SkipVerify() error = %v", err)
+	}
+	if skip || level.Name != trustpolicy.LevelStrict.Name {
+		t.Fatalf("SkipVerify() = %v, %v for team-b, want false, strict", skip, level.Name)
+	}
+
+	var errNoApplicablePolicy notation.ErrorNoApplicableTrustPolicy
+	if _, _, err := v.SkipVerify(WithTenant(context.Background(), "team-c"), opts); !errors.As(err, &errNoApplicablePolicy) {
+		t.Fatalf("SkipVerify() error = %v, want ErrorNoApplicableTrustPolicy", err)
+	}
+	if _, err := v.Verify(WithTenant(context.Background(), "team-c"), ocispec.Descriptor{}, []byte{}, opts); !errors.As(err, &errNoApplicablePolicy) {
+		t.Fatalf("Verify() error = %v, want ErrorNoApplicableTrustPolicy", err)
+	}
+	if _, _, err := v.SkipVerify(context.Background(), opts); err == nil {
+		t.Fatal("SkipVerify() expects error without tenant and default trust policy")
+	}
+}
+
+func TestTenantTrustPoliciesError(t *testing.T) {
+	invalidDoc := dummyOCIPolicyDocument()
+	invalidDoc.Version = "3.0"
+	for name, policies := range map[string]map[string]*trustpolicy.OCIDocument{
+		"empty tenant ID":  {"": skipOCIPolicyDocument()},
+		"nil document":     {"team-a": nil},
+		"invalid document": {"team-a": &invalidDoc},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewVerifierWithOptions(&testTrustStore{}, VerifierOptions{TenantOCITrustPolicies: policies}); err == nil {
+				t.Fatal("NewVerifierWithOptions() expects error, got nil")
+			}
+		})
+	}
+}
+
+func TestTenantFromContext(t *testing.T) {
+	if _, ok := TenantFromContext(context.Background()); ok {
+		t.Fatal("TenantFromContext() = true without tenant")
+	}
+	if tenantID, ok := TenantFromContext(WithTenant(context.Background(), "team-a")); !ok || tenantID != "team-a" {
+		t.Fatalf("TenantFromContext() = %q, %v, want team-a, true", tenantID, ok)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/revocation"
+	revocationresult "github.com/notaryproject/notation-core-go/revocation/result"
+)
+
+// contextRevocation adapts a [revocation.Revocation], which does not accept a
+// context, to a [revocation.Validator] returning as soon as the context is
+// done. The revocation check keeps running in the background until it
+// returns.
+type contextRevocation struct {
+	client revocation.Revocation
+}
+
+// ValidateContext checks the revocation status of the certificate chain with
+// the revocation client, giving up when ctx is done.
+func (r contextRevocation) ValidateContext(ctx context.Context, opts revocation.ValidateContextOptions) ([]*revocationresult.CertRevocationResult, error) {
+	type validateResult struct {
+		certResults []*revocationresult.CertRevocationResult
+		err         error
+	}
+	done := make(chan validateResult, 1)
+	go func() {
+		certResults, err := r.client.Validate(opts.CertChain, opts.AuthenticSigningTime)
+		done <- validateResult{certResults: certResults, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.certResults, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// timeoutValidator bounds each revocation check of a [revocation.Validator]
+// with a timeout.
+type timeoutValidator struct {
+	validator revocation.Validator
+	timeout   time.Duration
+}
+
+// ValidateContext checks the revocation status of the certificate chain with
+// the validator, within the timeout.
+func (v timeoutValidator) ValidateContext(ctx context.Context, opts revocation.ValidateContextOptions) ([]*revocationresult.CertRevocationResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+	return v.validator.ValidateContext(ctx, opts)
+}
+
+// withRevocationTimeout returns validator bounding each revocation check
+// with timeout. validator is returned as is if it is nil or timeout is not
+// positive.
+func withRevocationTimeout(validator revocation.Validator, timeout time.Duration) revocation.Validator {
+	if validator == nil || timeout <= 0 {
+		return validator
+	}
+	return timeoutValidator{validator: validator, timeout: timeout}
+}
@@ -0,0 +1,76 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verifier
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/revocation"
+	revocationresult "github.com/notaryproject/notation-core-go/revocation/result"
+)
+
+// blockingRevocation blocks the revocation checks until released.
+type blockingRevocation struct {
+	release chan struct{}
+}
+
+func (r *blockingRevocation) Validate(certChain []*x509.Certificate, signingTime time.Time) ([]*revocationresult.CertRevocationResult, error) {
+	<-r.release
+	return []*revocationresult.CertRevocationResult{{Result: revocationresult.ResultOK}}, nil
+}
+
+func (r *blockingRevocation) ValidateContext(ctx context.Context, opts revocation.ValidateContextOptions) ([]*revocationresult.CertRevocationResult, error) {
+	select {
+	case <-r.release:
+		return []*revocationresult.CertRevocationResult{{Result: revocationresult.ResultOK}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestContextRevocation(t *testing.T) {
+	r := &blockingRevocation{release: make(chan struct{})}
+	defer close(r.release)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := (contextRevocation{client: r}).ValidateContext(ctx, revocation.ValidateContextOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ValidateContext() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	released := &blockingRevocation{release: make(chan struct{})}
+	close(released.release)
+	certResults, err := (contextRevocation{client: released}).ValidateContext(context.Background(), revocation.ValidateContextOptions{})
+	if err != nil || len(certResults) != 1 {
+		t.Fatalf("ValidateContext() = %v, %v", certResults, err)
+	}
+}
+
+func TestWithRevocationTimeout(t *testing.T) {
+	r := &blockingRevocation{release: make(chan struct{})}
+	defer close(r.release)
+	if validator := withRevocationTimeout(r, 0); validator != r {
+		t.Fatal("withRevocationTimeout() wrapped the validator without timeout")
+	}
+	if validator := withRevocationTimeout(nil, time.Second); validator != nil {
+		t.Fatal("withRevocationTimeout() wrapped a nil validator")
+	}
+	validator := withRevocationTimeout(r, 10*time.Millisecond)
+	if _, err := validator.ValidateContext(context.Background(), revocation.ValidateContextOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ValidateContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
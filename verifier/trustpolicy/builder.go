@@ -0,0 +1,286 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/notaryproject/notation-go/internal/trustpolicy"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+// OCIDocumentBuilder builds an [OCIDocument] in code, e.g. to generate the
+// trust policies of a GitOps repository instead of maintaining JSON by hand.
+// The document is validated when built.
+//
+// The statements are added with [OCIDocumentBuilder.Statement]:
+//
+//	b := trustpolicy.NewOCIDocumentBuilder()
+//	b.Statement("wabbit-networks-images").
+//		RegistryScopes("registry.acme-rockets.io/software/net-monitor").
+//		Level(trustpolicy.LevelStrict.Name).
+//		TrustStore(truststore.TypeCA, "wabbit-networks").
+//		TrustedX509Subject("C=US, ST=WA, L=Seattle, O=wabbit-networks.io")
+//	policyDoc, err := b.Build()
+type OCIDocumentBuilder struct {
+	version    string
+	statements []*OCIStatementBuilder
+}
+
+// NewOCIDocumentBuilder returns an empty [OCIDocumentBuilder].
+func NewOCIDocumentBuilder() *OCIDocumentBuilder {
+	return &OCIDocumentBuilder{}
+}
+
+// Version sets the version of the document. If not set, the document has
+// version "1.0", or [VersionV2] if a statement requires it, e.g. with denied
+// identities.
+func (b *OCIDocumentBuilder) Version(version string) *OCIDocumentBuilder {
+	b.version = version
+	return b
+}
+
+// Statement adds the policy statement name to the document, and returns its
+// builder.
+func (b *OCIDocumentBuilder) Statement(name string) *OCIStatementBuilder {
+	s := &OCIStatementBuilder{
+		statement: OCITrustPolicy{Name: name},
+	}
+	b.statements = append(b.statements, s)
+	return s
+}
+
+// Build returns the document, or an error if the document is invalid.
+func (b *OCIDocumentBuilder) Build() (*OCIDocument, error) {
+	policyDoc := &OCIDocument{
+		Version:       b.version,
+		TrustPolicies: make([]OCITrustPolicy, 0, len(b.statements)),
+	}
+	requiresV2 := false
+	for _, s := range b.statements {
+		// the built document is not modified by the builder
+		statement := *s.statement.clone()
+		statement.SignatureVerification.Override = maps.Clone(statement.SignatureVerification.Override)
+		if statement.TrustStores == nil {
+			statement.TrustStores = []string{}
+		}
+		if statement.TrustedIdentities == nil {
+			statement.TrustedIdentities = []string{}
+		}
+		if len(statement.DeniedIdentities) > 0 || len(statement.ArtifactTypes) > 0 || statement.Endorsement != nil {
+			requiresV2 = true
+		}
+		policyDoc.TrustPolicies = append(policyDoc.TrustPolicies, statement)
+	}
+	if policyDoc.Version == "" {
+		policyDoc.Version = "1.0"
+		if requiresV2 {
+			policyDoc.Version = VersionV2
+		}
+	}
+	if err := policyDoc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid oci trust policy document: %w", err)
+	}
+	return policyDoc, nil
+}
+
+// OCIStatementBuilder builds a policy statement of an [OCIDocumentBuilder].
+type OCIStatementBuilder struct {
+	statement OCITrustPolicy
+}
+
+// RegistryScopes adds the registry scopes of the statement, e.g.
+// "registry.acme-rockets.io/software/net-monitor" or "*".
+func (s *OCIStatementBuilder) RegistryScopes(scopes ...string) *OCIStatementBuilder {
+	s.statement.RegistryScopes = append(s.statement.RegistryScopes, scopes...)
+	return s
+}
+
+// ArtifactTypes adds the artifact types the statement is restricted to.
+func (s *OCIStatementBuilder) ArtifactTypes(artifactTypes ...string) *OCIStatementBuilder {
+	s.statement.ArtifactTypes = append(s.statement.ArtifactTypes, artifactTypes...)
+	return s
+}
+
+// Level sets the name of the verification level of the statement, e.g.
+// [LevelStrict].Name.
+func (s *OCIStatementBuilder) Level(level string) *OCIStatementBuilder {
+	s.statement.SignatureVerification.VerificationLevel = level
+	return s
+}
+
+// Override overrides the action of the verification level for the
+// validation type.
+func (s *OCIStatementBuilder) Override(validationType ValidationType, action ValidationAction) *OCIStatementBuilder {
+	if s.statement.SignatureVerification.Override == nil {
+		s.statement.SignatureVerification.Override = make(map[ValidationType]ValidationAction)
+	}
+	s.statement.SignatureVerification.Override[validationType] = action
+	return s
+}
+
+// VerifyTimestamp sets when the timestamp countersignature is verified.
+func (s *OCIStatementBuilder) VerifyTimestamp(option TimestampOption) *OCIStatementBuilder {
+	s.statement.SignatureVerification.VerifyTimestamp = option
+	return s
+}
+
+// KeyRequirements sets the requirements of the keys of the signing
+// certificate chain.
+func (s *OCIStatementBuilder) KeyRequirements(requirements *KeyRequirements) *OCIStatementBuilder {
+	s.statement.SignatureVerification.KeyRequirements = requirements
+	return s
+}
+
+// TrustStore adds the trust store of type storeType named name.
+func (s *OCIStatementBuilder) TrustStore(storeType truststore.Type, name string) *OCIStatementBuilder {
+	s.statement.TrustStores = append(s.statement.TrustStores, string(storeType)+":"+name)
+	return s
+}
+
+// TrustedIdentities adds the trusted identities of the statement, e.g.
+// "x509.subject:C=US, O=wabbit-networks.io" or "*".
+func (s *OCIStatementBuilder) TrustedIdentities(identities ...string) *OCIStatementBuilder {
+	s.statement.TrustedIdentities = append(s.statement.TrustedIdentities, identities...)
+	return s
+}
+
+// TrustedX509Subject adds the trusted identity of the distinguished name of
+// the signing certificate subject.
+func (s *OCIStatementBuilder) TrustedX509Subject(dn string) *OCIStatementBuilder {
+	return s.TrustedIdentities(trustpolicy.X509Subject + ":" + dn)
+}
+
+// TrustedOrgIdentity adds the trusted organizational identity, e.g. a team
+// name, resolved from the signing certificate by the identity resolver
+// plugin of the verifier.
+func (s *OCIStatementBuilder) TrustedOrgIdentity(name string) *OCIStatementBuilder {
+	return s.TrustedIdentities(trustpolicy.OrgIdentity + ":" + name)
+}
+
+// DeniedX509Subject adds the denied identity of the distinguished name of
+// the signing certificate subject. It requires version [VersionV2].
+func (s *OCIStatementBuilder) DeniedX509Subject(dn string) *OCIStatementBuilder {
+	s.statement.DeniedIdentities = append(s.statement.DeniedIdentities, trustpolicy.X509Subject+":"+dn)
+	return s
+}
+
+// Endorsement requires the signatures to be endorsed with a countersignature
+// of the endorsement. It requires version [VersionV2].
+func (s *OCIStatementBuilder) Endorsement(endorsement *Endorsement) *OCIStatementBuilder {
+	s.statement.Endorsement = endorsement
+	return s
+}
+
+// BlobDocumentBuilder builds a [BlobDocument] in code. The document is
+// validated when built.
+type BlobDocumentBuilder struct {
+	statements []*BlobStatementBuilder
+}
+
+// NewBlobDocumentBuilder returns an empty [BlobDocumentBuilder].
+func NewBlobDocumentBuilder() *BlobDocumentBuilder {
+	return &BlobDocumentBuilder{}
+}
+
+// Statement adds the policy statement name to the document, and returns its
+// builder.
+func (b *BlobDocumentBuilder) Statement(name string) *BlobStatementBuilder {
+	s := &BlobStatementBuilder{
+		statement: BlobTrustPolicy{Name: name},
+	}
+	b.statements = append(b.statements, s)
+	return s
+}
+
+// Build returns the document, or an error if the document is invalid.
+func (b *BlobDocumentBuilder) Build() (*BlobDocument, error) {
+	policyDoc := &BlobDocument{
+		Version:       "1.0",
+		TrustPolicies: make([]BlobTrustPolicy, 0, len(b.statements)),
+	}
+	for _, s := range b.statements {
+		statement := s.statement
+		statement.SignatureVerification.Override = maps.Clone(statement.SignatureVerification.Override)
+		statement.TrustStores = append([]string{}, statement.TrustStores...)
+		statement.TrustedIdentities = append([]string{}, statement.TrustedIdentities...)
+		policyDoc.TrustPolicies = append(policyDoc.TrustPolicies, statement)
+	}
+	if err := policyDoc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid blob trust policy document: %w", err)
+	}
+	return policyDoc, nil
+}
+
+// BlobStatementBuilder builds a policy statement of a
+// [BlobDocumentBuilder].
+type BlobStatementBuilder struct {
+	statement BlobTrustPolicy
+}
+
+// Global makes the statement the global policy statement, applied when no
+// statement is selected by name.
+func (s *BlobStatementBuilder) Global() *BlobStatementBuilder {
+	s.statement.GlobalPolicy = true
+	return s
+}
+
+// Level sets the name of the verification level of the statement, e.g.
+// [LevelStrict].Name.
+func (s *BlobStatementBuilder) Level(level string) *BlobStatementBuilder {
+	s.statement.SignatureVerification.VerificationLevel = level
+	return s
+}
+
+// Override overrides the action of the verification level for the
+// validation type.
+func (s *BlobStatementBuilder) Override(validationType ValidationType, action ValidationAction) *BlobStatementBuilder {
+	if s.statement.SignatureVerification.Override == nil {
+		s.statement.SignatureVerification.Override = make(map[ValidationType]ValidationAction)
+	}
+	s.statement.SignatureVerification.Override[validationType] = action
+	return s
+}
+
+// VerifyTimestamp sets when the timestamp countersignature is verified.
+func (s *BlobStatementBuilder) VerifyTimestamp(option TimestampOption) *BlobStatementBuilder {
+	s.statement.SignatureVerification.VerifyTimestamp = option
+	return s
+}
+
+// TrustStore adds the trust store of type storeType named name.
+func (s *BlobStatementBuilder) TrustStore(storeType truststore.Type, name string) *BlobStatementBuilder {
+	s.statement.TrustStores = append(s.statement.TrustStores, string(storeType)+":"+name)
+	return s
+}
+
+// TrustedIdentities adds the trusted identities of the statement.
+func (s *BlobStatementBuilder) TrustedIdentities(identities ...string) *BlobStatementBuilder {
+	s.statement.TrustedIdentities = append(s.statement.TrustedIdentities, identities...)
+	return s
+}
+
+// TrustedX509Subject adds the trusted identity of the distinguished name of
+// the signing certificate subject.
+func (s *BlobStatementBuilder) TrustedX509Subject(dn string) *BlobStatementBuilder {
+	return s.TrustedIdentities(trustpolicy.X509Subject + ":" + dn)
+}
+
+// TrustedOrgIdentity adds the trusted organizational identity, e.g. a team
+// name, resolved from the signing certificate by the identity resolver
+// plugin of the verifier.
+func (s *BlobStatementBuilder) TrustedOrgIdentity(name string) *BlobStatementBuilder {
+	return s.TrustedIdentities(trustpolicy.OrgIdentity + ":" + name)
+}
@@ -0,0 +1,149 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+func TestOCIDocumentBuilder(t *testing.T) {
+	b := NewOCIDocumentBuilder()
+	b.Statement("test-statement-name").
+		RegistryScopes("registry.acme-rockets.io/software/net-monitor").
+		Level(LevelStrict.Name).
+		TrustStore(truststore.TypeCA, "valid-trust-store").
+		TrustStore(truststore.TypeSigningAuthority, "valid-trust-store").
+		TrustedX509Subject("CN=Notation Test Root,O=Notary,L=Seattle,ST=WA,C=US")
+	policyDoc, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	want := dummyOCIPolicyDocument()
+	if !reflect.DeepEqual(policyDoc, &want) {
+		t.Fatalf("Build() = %+v, want %+v", policyDoc, &want)
+	}
+
+	// the statements requiring version 2.0 upgrade the document
+	statement := b.Statement("sbom").
+		RegistryScopes("registry.acme-rockets.io/software/net-monitor").
+		ArtifactTypes("application/spdx+json").
+		Level(LevelPermissive.Name).
+		Override(TypeExpiry, ActionLog).
+		TrustStore(truststore.TypeCA, "valid-trust-store").
+		TrustedIdentities("*")
+	policyDoc, err = b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if policyDoc.Version != VersionV2 || len(policyDoc.TrustPolicies) != 2 {
+		t.Fatalf("Build() = %+v, want 2 statements in version %q", policyDoc, VersionV2)
+	}
+
+	// the built document is not modified by the builder
+	statement.Override(TypeAuthenticTimestamp, ActionLog)
+	if len(policyDoc.TrustPolicies[1].SignatureVerification.Override) != 1 {
+		t.Fatalf("built document modified by the builder: %+v", policyDoc.TrustPolicies[1].SignatureVerification.Override)
+	}
+
+	// version 1.0 rejects the statements requiring version 2.0
+	if _, err := b.Version("1.0").Build(); err == nil || !strings.Contains(err.Error(), "require version") {
+		t.Fatalf("Build() error = %v, want version error", err)
+	}
+}
+
+func TestOCIDocumentBuilderError(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *OCIDocumentBuilder)
+	}{
+		{
+			name:  "no statement",
+			build: func(b *OCIDocumentBuilder) {},
+		},
+		{
+			name: "missing registry scopes",
+			build: func(b *OCIDocumentBuilder) {
+				b.Statement("test").Level(LevelStrict.Name).TrustStore(truststore.TypeCA, "test").TrustedIdentities("*")
+			},
+		},
+		{
+			name: "invalid level",
+			build: func(b *OCIDocumentBuilder) {
+				b.Statement("test").RegistryScopes("*").Level("paranoid").TrustStore(truststore.TypeCA, "test").TrustedIdentities("*")
+			},
+		},
+		{
+			name: "denied wildcard identity",
+			build: func(b *OCIDocumentBuilder) {
+				b.Statement("test").RegistryScopes("*").Level(LevelStrict.Name).TrustStore(truststore.TypeCA, "test").TrustedIdentities("*").DeniedX509Subject("")
+			},
+		},
+		{
+			name: "incomplete endorsement",
+			build: func(b *OCIDocumentBuilder) {
+				b.Statement("test").RegistryScopes("*").Level(LevelStrict.Name).TrustStore(truststore.TypeCA, "test").TrustedIdentities("*").Endorsement(&Endorsement{})
+			},
+		},
+		{
+			name: "empty org identity",
+			build: func(b *OCIDocumentBuilder) {
+				b.Statement("test").RegistryScopes("*").Level(LevelStrict.Name).TrustStore(truststore.TypeCA, "test").TrustedOrgIdentity("")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewOCIDocumentBuilder()
+			tt.build(b)
+			if _, err := b.Build(); err == nil {
+				t.Fatal("Build() expects error, got nil")
+			}
+		})
+	}
+}
+
+func TestBlobDocumentBuilder(t *testing.T) {
+	b := NewBlobDocumentBuilder()
+	b.Statement("test-statement-name").
+		Level(LevelStrict.Name).
+		TrustStore(truststore.TypeCA, "valid-trust-store").
+		TrustStore(truststore.TypeSigningAuthority, "valid-trust-store").
+		TrustedX509Subject("CN=Notation Test Root,O=Notary,L=Seattle,ST=WA,C=US")
+	policyDoc, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	want := dummyBlobPolicyDocument()
+	if !reflect.DeepEqual(policyDoc, &want) {
+		t.Fatalf("Build() = %+v, want %+v", policyDoc, &want)
+	}
+
+	b.Statement("global").Global().Level(LevelAudit.Name).VerifyTimestamp(OptionAfterCertExpiry).Override(TypeExpiry, ActionLog).TrustStore(truststore.TypeCA, "valid-trust-store").TrustedIdentities("*")
+	policyDoc, err = b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if !policyDoc.TrustPolicies[1].GlobalPolicy {
+		t.Fatal("Build() statement is not global")
+	}
+
+	b.Statement("second-global").Global().Level(LevelStrict.Name).TrustStore(truststore.TypeCA, "valid-trust-store").TrustedIdentities("*")
+	if _, err := b.Build(); err == nil {
+		t.Fatal("Build() expects error for two global statements")
+	}
+}
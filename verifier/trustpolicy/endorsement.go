@@ -0,0 +1,57 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import "fmt"
+
+// Endorsement requires the signatures verified by an OCI trust policy
+// statement to be endorsed with a countersignature, i.e. a signature whose
+// subject is the signature manifest of the endorsed signature, e.g. by a
+// security team on top of the signature of a developer.
+//
+// The countersignatures are verified with the verification level of the
+// statement, against the trust stores and the trusted identities of the
+// endorsement instead of the ones of the statement.
+type Endorsement struct {
+	// TrustStores of the signing certificate chain of the countersignatures.
+	TrustStores []string `json:"trustStores"`
+
+	// TrustedIdentities of the endorsers.
+	TrustedIdentities []string `json:"trustedIdentities"`
+}
+
+// validate validates the endorsement of the policy statement policyName.
+func (e *Endorsement) validate(policyName string) error {
+	if len(e.TrustStores) == 0 || len(e.TrustedIdentities) == 0 {
+		return fmt.Errorf("trust policy statement %q has an endorsement missing trust stores or trusted identities, both must be specified", policyName)
+	}
+	if err := validateTrustStore(policyName, e.TrustStores); err != nil {
+		return fmt.Errorf("endorsement: %w", err)
+	}
+	if err := validateTrustedIdentities(policyName, e.TrustedIdentities); err != nil {
+		return fmt.Errorf("endorsement: %w", err)
+	}
+	return nil
+}
+
+// clone returns a deep copy of the endorsement, or nil if e is nil.
+func (e *Endorsement) clone() *Endorsement {
+	if e == nil {
+		return nil
+	}
+	return &Endorsement{
+		TrustStores:       append([]string(nil), e.TrustStores...),
+		TrustedIdentities: append([]string(nil), e.TrustedIdentities...),
+	}
+}
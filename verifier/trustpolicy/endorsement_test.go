@@ -0,0 +1,114 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func dummyOCIPolicyDocumentWithEndorsement() OCIDocument {
+	policyDoc := dummyOCIPolicyDocument()
+	policyDoc.Version = VersionV2
+	policyDoc.TrustPolicies[0].Endorsement = &Endorsement{
+		TrustStores:       []string{"ca:security-team"},
+		TrustedIdentities: []string{"x509.subject:CN=Security Team,O=Notary,L=Seattle,ST=WA,C=US"},
+	}
+	return policyDoc
+}
+
+func TestOCIDocumentEndorsement(t *testing.T) {
+	policyDoc := dummyOCIPolicyDocumentWithEndorsement()
+	if err := policyDoc.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	// the endorsement is deep copied
+	statement, err := policyDoc.GetApplicableTrustPolicy("registry.acme-rockets.io/software/net-monitor@sha256:60043cf45eaebc4c0867fea485a039b598f52fd09fd5b07b0b2d2f88fad9d74e")
+	if err != nil {
+		t.Fatalf("GetApplicableTrustPolicy() error = %v", err)
+	}
+	if !reflect.DeepEqual(statement.Endorsement, policyDoc.TrustPolicies[0].Endorsement) {
+		t.Fatalf("Endorsement = %+v, want %+v", statement.Endorsement, policyDoc.TrustPolicies[0].Endorsement)
+	}
+	statement.Endorsement.TrustStores[0] = "ca:modified"
+	if policyDoc.TrustPolicies[0].Endorsement.TrustStores[0] != "ca:security-team" {
+		t.Fatal("GetApplicableTrustPolicy() did not copy the endorsement")
+	}
+
+	// round trip through the version 2.0 layout
+	docV2, err := ConvertOCIDocument(&policyDoc)
+	if err != nil {
+		t.Fatalf("ConvertOCIDocument() error = %v", err)
+	}
+	if !reflect.DeepEqual(docV2.ToOCIDocument(), &policyDoc) {
+		t.Fatalf("ToOCIDocument() = %+v, want %+v", docV2.ToOCIDocument(), &policyDoc)
+	}
+
+	explanation, err := policyDoc.Explain("registry.acme-rockets.io/software/net-monitor:v1")
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if !strings.Contains(strings.Join(explanation.Steps, "\n"), "endorsed with a countersignature") {
+		t.Fatalf("Explain() steps do not describe the endorsement: %v", explanation.Steps)
+	}
+}
+
+func TestOCIDocumentEndorsementValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*OCIDocument)
+	}{
+		{
+			name:   "version 1.0",
+			modify: func(d *OCIDocument) { d.Version = "1.0" },
+		},
+		{
+			name: "skip level",
+			modify: func(d *OCIDocument) {
+				d.TrustPolicies[0].SignatureVerification.VerificationLevel = LevelSkip.Name
+				d.TrustPolicies[0].TrustStores = nil
+				d.TrustPolicies[0].TrustedIdentities = nil
+			},
+		},
+		{
+			name:   "missing trust stores",
+			modify: func(d *OCIDocument) { d.TrustPolicies[0].Endorsement.TrustStores = nil },
+		},
+		{
+			name:   "missing trusted identities",
+			modify: func(d *OCIDocument) { d.TrustPolicies[0].Endorsement.TrustedIdentities = nil },
+		},
+		{
+			name:   "invalid trust store",
+			modify: func(d *OCIDocument) { d.TrustPolicies[0].Endorsement.TrustStores = []string{"security-team"} },
+		},
+		{
+			name: "invalid trusted identity",
+			modify: func(d *OCIDocument) {
+				d.TrustPolicies[0].Endorsement.TrustedIdentities = []string{"*", "x509.subject:CN=Security Team"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policyDoc := dummyOCIPolicyDocumentWithEndorsement()
+			tt.modify(&policyDoc)
+			if err := policyDoc.Validate(); err == nil {
+				t.Fatal("Validate() expects error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Explanation describes how an OCI trust policy document applies to an
+// artifact, as reported by [OCIDocument.Explain].
+type Explanation struct {
+	// ArtifactReference is the explained artifact reference or repository.
+	ArtifactReference string
+
+	// RegistryScope is the repository of the artifact matched against the
+	// registry scopes of the statements.
+	RegistryScope string
+
+	// Statement is the applicable trust policy statement. It is nil if no
+	// statement applies.
+	Statement *OCITrustPolicy
+
+	// Wildcard is true if the statement applies through the wildcard
+	// registry scope.
+	Wildcard bool
+
+	// VerificationLevel is the effective verification level of the
+	// statement, including the overrides.
+	VerificationLevel *VerificationLevel
+
+	// TrustStores are the trust stores consulted by the verification.
+	TrustStores []string
+
+	// TrustedIdentities are the identities trusted by the verification.
+	TrustedIdentities []string
+
+	// Steps describe the decision path in human-readable sentences.
+	Steps []string
+}
+
+// Explain reports which statement of policyDoc applies to the artifact, the
+// trust stores and the identities consulted, and the effective verification
+// level, without contacting the registry.
+//
+// artifactReference is either a fully qualified artifact reference, e.g.
+// "registry.example.com/app@sha256:...", or a repository with an optional tag,
+// e.g. "registry.example.com/app" or "registry.example.com/app:v1". If no
+// statement applies, the explanation is returned with a nil Statement.
+func (policyDoc *OCIDocument) Explain(artifactReference string) (*Explanation, error) {
+	registryScope, err := getRepositoryFromReference(artifactReference)
+	if err != nil {
+		return nil, err
+	}
+	explanation := &Explanation{
+		ArtifactReference: artifactReference,
+		RegistryScope:     registryScope,
+	}
+	explanation.addStep("the registry scope of %q is %q", artifactReference, registryScope)
+
+	applicablePolicy, wildcardPolicy := policyDoc.matchTrustPolicies(registryScope, "")
+	switch {
+	case applicablePolicy != nil:
+		explanation.Statement = applicablePolicy
+		explanation.addStep("statement %q applies as its registry scopes contain %q", applicablePolicy.Name, registryScope)
+		if wildcardPolicy != nil {
+			explanation.addStep("statement %q with the wildcard registry scope is not used as an exact match takes precedence", wildcardPolicy.Name)
+		}
+	case wildcardPolicy != nil:
+		explanation.Statement = wildcardPolicy
+		explanation.Wildcard = true
+		explanation.addStep("no statement has %q in its registry scopes, statement %q applies through the wildcard registry scope", registryScope, wildcardPolicy.Name)
+	default:
+		explanation.addStep("no statement has %q in its registry scopes and no statement has the wildcard registry scope, the verification fails", registryScope)
+		return explanation, nil
+	}
+
+	statement := explanation.Statement
+	verificationLevel, err := statement.SignatureVerification.GetVerificationLevel()
+	if err != nil {
+		return nil, fmt.Errorf("statement %q has invalid signature verification: %w", statement.Name, err)
+	}
+	explanation.VerificationLevel = verificationLevel
+	if verificationLevel.Name == LevelSkip.Name {
+		explanation.addStep("the verification level is %q, the signature verification is skipped", LevelSkip.Name)
+		return explanation, nil
+	}
+	explanation.TrustStores = statement.TrustStores
+	explanation.TrustedIdentities = statement.TrustedIdentities
+	explanation.addStep("the verification level is %q: %s", statement.SignatureVerification.VerificationLevel, describeEnforcement(verificationLevel))
+	for _, validationType := range sortedValidationTypes(statement.SignatureVerification.Override) {
+		explanation.addStep("the %s validation is overridden to %q", validationType, statement.SignatureVerification.Override[validationType])
+	}
+	explanation.addStep("the signing certificate chain must chain to a certificate in the trust stores %s", strings.Join(statement.TrustStores, ", "))
+	explanation.addStep("the signing certificate must match one of the trusted identities %s", strings.Join(statement.TrustedIdentities, ", "))
+	if len(statement.DeniedIdentities) > 0 {
+		explanation.addStep("the signing certificate must not match any of the denied identities %s", strings.Join(statement.DeniedIdentities, ", "))
+	}
+	if endorsement := statement.Endorsement; endorsement != nil {
+		explanation.addStep("the signature must be endorsed with a countersignature chaining to a certificate in the trust stores %s and matching one of the trusted identities %s", strings.Join(endorsement.TrustStores, ", "), strings.Join(endorsement.TrustedIdentities, ", "))
+	}
+	if statement.SignatureVerification.VerifyTimestamp == OptionAfterCertExpiry {
+		explanation.addStep("the timestamp is verified only if the signing certificate chain has expired")
+	}
+	if requirements := statement.SignatureVerification.KeyRequirements; requirements != nil {
+		explanation.addStep("the signing certificate chain must meet the key requirements: %s", requirements)
+	}
+	return explanation, nil
+}
+
+func (e *Explanation) addStep(format string, args ...any) {
+	e.Steps = append(e.Steps, fmt.Sprintf(format, args...))
+}
+
+// describeEnforcement describes the actions of the verification level in
+// the order of ValidationTypes.
+func describeEnforcement(level *VerificationLevel) string {
+	var actions []string
+	for _, validationType := range ValidationTypes {
+		if action, ok := level.Enforcement[validationType]; ok {
+			actions = append(actions, fmt.Sprintf("%s=%s", validationType, action))
+		}
+	}
+	return strings.Join(actions, ", ")
+}
+
+func sortedValidationTypes(override map[ValidationType]ValidationAction) []ValidationType {
+	types := make([]ValidationType, 0, len(override))
+	for validationType := range override {
+		types = append(types, validationType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// getRepositoryFromReference returns the repository of an artifact reference
+// or of a repository with an optional tag.
+func getRepositoryFromReference(reference string) (string, error) {
+	if strings.Contains(reference, "@") {
+		return getArtifactPathFromReference(reference)
+	}
+	repository := reference
+	if i := strings.LastIndex(reference, ":"); i > strings.LastIndex(reference, "/") {
+		// drop the tag
+		repository = reference[:i]
+	}
+	if err := validateRegistryScopeFormat(repository); err != nil {
+		return "", err
+	}
+	return repository, nil
+}
@@ -0,0 +1,129 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	policyDoc := dummyOCIPolicyDocument()
+	policyDoc.TrustPolicies[0].SignatureVerification.Override = map[ValidationType]ValidationAction{
+		TypeRevocation: ActionLog,
+	}
+	policyDoc.TrustPolicies = append(policyDoc.TrustPolicies, OCITrustPolicy{
+		Name:                  "wildcard-statement",
+		RegistryScopes:        []string{"*"},
+		SignatureVerification: SignatureVerification{VerificationLevel: "skip"},
+	})
+
+	tests := []struct {
+		reference     string
+		wantScope     string
+		wantStatement string
+		wantWildcard  bool
+		wantLevel     string
+	}{
+		{
+			reference:     "registry.acme-rockets.io/software/net-monitor@sha256:hash",
+			wantScope:     "registry.acme-rockets.io/software/net-monitor",
+			wantStatement: "test-statement-name",
+			wantLevel:     "custom",
+		},
+		{
+			reference:     "registry.acme-rockets.io/software/net-monitor",
+			wantScope:     "registry.acme-rockets.io/software/net-monitor",
+			wantStatement: "test-statement-name",
+			wantLevel:     "custom",
+		},
+		{
+			reference:     "registry.acme-rockets.io/software/net-monitor:v1",
+			wantScope:     "registry.acme-rockets.io/software/net-monitor",
+			wantStatement: "test-statement-name",
+			wantLevel:     "custom",
+		},
+		{
+			reference:     "localhost:5000/other",
+			wantScope:     "localhost:5000/other",
+			wantStatement: "wildcard-statement",
+			wantWildcard:  true,
+			wantLevel:     "skip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			explanation, err := policyDoc.Explain(tt.reference)
+			if err != nil {
+				t.Fatalf("Explain() failed: %v", err)
+			}
+			if explanation.RegistryScope != tt.wantScope {
+				t.Fatalf("RegistryScope = %q, want %q", explanation.RegistryScope, tt.wantScope)
+			}
+			if explanation.Statement == nil || explanation.Statement.Name != tt.wantStatement {
+				t.Fatalf("Statement = %+v, want %q", explanation.Statement, tt.wantStatement)
+			}
+			if explanation.Wildcard != tt.wantWildcard {
+				t.Fatalf("Wildcard = %v, want %v", explanation.Wildcard, tt.wantWildcard)
+			}
+			if explanation.VerificationLevel.Name != tt.wantLevel {
+				t.Fatalf("VerificationLevel = %q, want %q", explanation.VerificationLevel.Name, tt.wantLevel)
+			}
+			if len(explanation.Steps) == 0 {
+				t.Fatal("Steps should not be empty")
+			}
+		})
+	}
+
+	t.Run("exact match details", func(t *testing.T) {
+		explanation, err := policyDoc.Explain("registry.acme-rockets.io/software/net-monitor")
+		if err != nil {
+			t.Fatalf("Explain() failed: %v", err)
+		}
+		if len(explanation.TrustStores) != 2 || len(explanation.TrustedIdentities) != 1 {
+			t.Fatalf("unexpected trust stores %v or trusted identities %v", explanation.TrustStores, explanation.TrustedIdentities)
+		}
+		if explanation.VerificationLevel.Enforcement[TypeRevocation] != ActionLog {
+			t.Fatalf("revocation override should be applied")
+		}
+		steps := strings.Join(explanation.Steps, "\n")
+		for _, want := range []string{
+			`statement "wildcard-statement" with the wildcard registry scope is not used`,
+			`the revocation validation is overridden to "log"`,
+		} {
+			if !strings.Contains(steps, want) {
+				t.Fatalf("Steps should contain %q, got:\n%s", want, steps)
+			}
+		}
+	})
+
+	t.Run("no applicable statement", func(t *testing.T) {
+		policyDoc := dummyOCIPolicyDocument()
+		explanation, err := policyDoc.Explain("registry.wabbit-networks.io/software/unsigned")
+		if err != nil {
+			t.Fatalf("Explain() failed: %v", err)
+		}
+		if explanation.Statement != nil || explanation.VerificationLevel != nil {
+			t.Fatalf("expected no applicable statement, got %+v", explanation.Statement)
+		}
+	})
+
+	t.Run("invalid reference", func(t *testing.T) {
+		for _, reference := range []string{"", "registry.acme-rockets.io", "registry.acme-rockets.io:v1"} {
+			if _, err := policyDoc.Explain(reference); err == nil {
+				t.Fatalf("Explain(%q) should fail", reference)
+			}
+		}
+	})
+}
@@ -0,0 +1,327 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/notaryproject/notation-go/internal/slices"
+	"github.com/notaryproject/notation-go/internal/trustpolicy"
+)
+
+// Impact is the semantic impact of a trust policy change on the artifacts of
+// a registry scope.
+type Impact string
+
+const (
+	// ImpactMorePermissive means that artifacts may pass verification with
+	// the new document while failing with the old one.
+	ImpactMorePermissive Impact = "morePermissive"
+
+	// ImpactLessPermissive means that artifacts passing verification with
+	// the old document may fail with the new one.
+	ImpactLessPermissive Impact = "lessPermissive"
+
+	// ImpactMixed means that the change is both more and less permissive,
+	// e.g. a trust store replaced by another one.
+	ImpactMixed Impact = "mixed"
+
+	// ImpactChanged means that the verification changes without being more
+	// or less permissive, e.g. different key requirements.
+	ImpactChanged Impact = "changed"
+)
+
+// ScopeImpact describes the impact of a trust policy change on the artifacts
+// of a registry scope and of an artifact type.
+type ScopeImpact struct {
+	// RegistryScope is the registry scope, or the wildcard registry scope
+	// for the repositories without a statement of their own.
+	RegistryScope string
+
+	// ArtifactType is the artifact type of the artifacts, or empty for the
+	// artifacts without a statement for their artifact type.
+	ArtifactType string
+
+	// OldStatement is the name of the statement applicable in the old
+	// document, or empty if none is applicable.
+	OldStatement string
+
+	// NewStatement is the name of the statement applicable in the new
+	// document, or empty if none is applicable.
+	NewStatement string
+
+	// Impact is the overall impact of the change.
+	Impact Impact
+
+	// GainedIdentities are the identities trusted by the new document but
+	// not by the old one, e.g. "*".
+	GainedIdentities []string
+
+	// LostIdentities are the identities trusted by the old document but not
+	// by the new one.
+	LostIdentities []string
+
+	// AddedTrustStores are the trust stores used by the new document but not
+	// by the old one.
+	AddedTrustStores []string
+
+	// RemovedTrustStores are the trust stores used by the old document but
+	// not by the new one.
+	RemovedTrustStores []string
+
+	// Changes describe each change, e.g.
+	// `"expiry" action changed from "enforce" to "log"`.
+	Changes []string
+}
+
+// DiffOCIDocuments compares the statements applicable to each registry scope
+// and artifact type of the OCI trust policy documents oldDoc and newDoc, and
+// returns the impact of the change on each registry scope whose verification
+// changes, sorted by registry scope and artifact type.
+//
+// The statements are compared by their effect, so that renamed or reordered
+// statements have no impact.
+func DiffOCIDocuments(oldDoc, newDoc *OCIDocument) []ScopeImpact {
+	type scopeKey struct {
+		scope        string
+		artifactType string
+	}
+	keys := map[scopeKey]bool{
+		{scope: trustpolicy.Wildcard}: true,
+	}
+	for _, doc := range []*OCIDocument{oldDoc, newDoc} {
+		for _, statement := range doc.TrustPolicies {
+			for _, scope := range statement.RegistryScopes {
+				keys[scopeKey{scope: scope}] = true
+				for _, artifactType := range statement.ArtifactTypes {
+					keys[scopeKey{scope: scope, artifactType: artifactType}] = true
+				}
+			}
+		}
+	}
+	// a typed statement with the wildcard registry scope applies to the
+	// artifacts of its types in all the registry scopes
+	var wildcardTypes, scopes []string
+	for key := range keys {
+		if key.scope == trustpolicy.Wildcard && key.artifactType != "" {
+			wildcardTypes = append(wildcardTypes, key.artifactType)
+		}
+		if key.artifactType == "" {
+			scopes = append(scopes, key.scope)
+		}
+	}
+	for _, artifactType := range wildcardTypes {
+		for _, scope := range scopes {
+			keys[scopeKey{scope: scope, artifactType: artifactType}] = true
+		}
+	}
+
+	var impacts []ScopeImpact
+	for key := range keys {
+		oldStatement := oldDoc.applicableStatement(key.scope, key.artifactType)
+		newStatement := newDoc.applicableStatement(key.scope, key.artifactType)
+		if impact, ok := diffStatements(oldStatement, newStatement); ok {
+			impact.RegistryScope = key.scope
+			impact.ArtifactType = key.artifactType
+			impacts = append(impacts, impact)
+		}
+	}
+	sort.Slice(impacts, func(i, j int) bool {
+		if impacts[i].RegistryScope != impacts[j].RegistryScope {
+			return impacts[i].RegistryScope < impacts[j].RegistryScope
+		}
+		return impacts[i].ArtifactType < impacts[j].ArtifactType
+	})
+	return impacts
+}
+
+// applicableStatement returns the statement applicable to the artifacts of
+// artifactType in the registry scope, or nil if none is applicable.
+func (policyDoc *OCIDocument) applicableStatement(scope, artifactType string) *OCITrustPolicy {
+	if scope == trustpolicy.Wildcard {
+		// no statement has an empty registry scope
+		scope = ""
+	}
+	applicable, wildcard := policyDoc.matchTrustPolicies(scope, artifactType)
+	if applicable != nil {
+		return applicable
+	}
+	return wildcard
+}
+
+// diffStatements returns the impact of replacing the statement oldStatement
+// with newStatement, and false if the verification does not change.
+func diffStatements(oldStatement, newStatement *OCITrustPolicy) (ScopeImpact, bool) {
+	var impact ScopeImpact
+	var more, less, changed bool
+	switch {
+	case oldStatement == nil && newStatement == nil:
+		return impact, false
+	case oldStatement == nil:
+		impact.NewStatement = newStatement.Name
+		impact.Changes = []string{fmt.Sprintf("statement %q now applies, the artifacts were rejected without an applicable statement", newStatement.Name)}
+		impact.Impact = ImpactMorePermissive
+		return impact, true
+	case newStatement == nil:
+		impact.OldStatement = oldStatement.Name
+		impact.Changes = []string{fmt.Sprintf("statement %q no longer applies, the artifacts are rejected without an applicable statement", oldStatement.Name)}
+		impact.Impact = ImpactLessPermissive
+		return impact, true
+	}
+	impact.OldStatement = oldStatement.Name
+	impact.NewStatement = newStatement.Name
+	addChange := func(permissive *bool, format string, args ...any) {
+		*permissive = true
+		impact.Changes = append(impact.Changes, fmt.Sprintf(format, args...))
+	}
+
+	// verification actions
+	oldLevel, oldErr := oldStatement.SignatureVerification.GetVerificationLevel()
+	newLevel, newErr := newStatement.SignatureVerification.GetVerificationLevel()
+	if oldErr != nil || newErr != nil {
+		if !reflect.DeepEqual(oldStatement.SignatureVerification, newStatement.SignatureVerification) {
+			addChange(&changed, "signature verification changed")
+		}
+	} else {
+		if oldStatement.SignatureVerification.VerificationLevel != newStatement.SignatureVerification.VerificationLevel {
+			impact.Changes = append(impact.Changes, fmt.Sprintf("verification level changed from %q to %q", oldStatement.SignatureVerification.VerificationLevel, newStatement.SignatureVerification.VerificationLevel))
+		}
+		for _, validationType := range ValidationTypes {
+			oldAction := oldLevel.Enforcement[validationType]
+			newAction := newLevel.Enforcement[validationType]
+			switch {
+			case actionStrength(newAction) < actionStrength(oldAction):
+				addChange(&more, "%q action changed from %q to %q", validationType, oldAction, newAction)
+			case actionStrength(newAction) > actionStrength(oldAction):
+				addChange(&less, "%q action changed from %q to %q", validationType, oldAction, newAction)
+			}
+		}
+	}
+	oldTimestamp := timestampOption(oldStatement.SignatureVerification.VerifyTimestamp)
+	newTimestamp := timestampOption(newStatement.SignatureVerification.VerifyTimestamp)
+	if oldTimestamp != newTimestamp {
+		if newTimestamp == OptionAfterCertExpiry {
+			addChange(&more, "timestamp verified only after the certificate expiry")
+		} else {
+			addChange(&less, "timestamp always verified")
+		}
+	}
+	if !reflect.DeepEqual(oldStatement.SignatureVerification.KeyRequirements, newStatement.SignatureVerification.KeyRequirements) {
+		switch {
+		case newStatement.SignatureVerification.KeyRequirements == nil:
+			addChange(&more, "key requirements removed")
+		case oldStatement.SignatureVerification.KeyRequirements == nil:
+			addChange(&less, "key requirements added")
+		default:
+			addChange(&changed, "key requirements changed")
+		}
+	}
+
+	// trusted identities
+	oldAny := slices.Contains(oldStatement.TrustedIdentities, trustpolicy.Wildcard)
+	newAny := slices.Contains(newStatement.TrustedIdentities, trustpolicy.Wildcard)
+	switch {
+	case !oldAny && newAny:
+		impact.GainedIdentities = []string{trustpolicy.Wildcard}
+		addChange(&more, "all identities are trusted")
+	case oldAny && !newAny:
+		impact.LostIdentities = []string{trustpolicy.Wildcard}
+		addChange(&less, "only the identities %q are trusted", newStatement.TrustedIdentities)
+	case !oldAny && !newAny:
+		impact.GainedIdentities = difference(newStatement.TrustedIdentities, oldStatement.TrustedIdentities)
+		impact.LostIdentities = difference(oldStatement.TrustedIdentities, newStatement.TrustedIdentities)
+		if len(impact.GainedIdentities) > 0 {
+			addChange(&more, "identities %q are trusted", impact.GainedIdentities)
+		}
+		if len(impact.LostIdentities) > 0 {
+			addChange(&less, "identities %q are no longer trusted", impact.LostIdentities)
+		}
+	}
+	if denied := difference(newStatement.DeniedIdentities, oldStatement.DeniedIdentities); len(denied) > 0 {
+		addChange(&less, "identities %q are denied", denied)
+	}
+	if allowed := difference(oldStatement.DeniedIdentities, newStatement.DeniedIdentities); len(allowed) > 0 {
+		addChange(&more, "identities %q are no longer denied", allowed)
+	}
+
+	// trust stores
+	impact.AddedTrustStores = difference(newStatement.TrustStores, oldStatement.TrustStores)
+	impact.RemovedTrustStores = difference(oldStatement.TrustStores, newStatement.TrustStores)
+	if len(impact.AddedTrustStores) > 0 {
+		addChange(&more, "trust stores %q are used", impact.AddedTrustStores)
+	}
+	if len(impact.RemovedTrustStores) > 0 {
+		addChange(&less, "trust stores %q are no longer used", impact.RemovedTrustStores)
+	}
+
+	// endorsement
+	if !reflect.DeepEqual(oldStatement.Endorsement, newStatement.Endorsement) {
+		switch {
+		case newStatement.Endorsement == nil:
+			addChange(&more, "endorsement no longer required")
+		case oldStatement.Endorsement == nil:
+			addChange(&less, "endorsement required")
+		default:
+			addChange(&changed, "endorsement changed")
+		}
+	}
+
+	switch {
+	case more && less:
+		impact.Impact = ImpactMixed
+	case more:
+		impact.Impact = ImpactMorePermissive
+	case less:
+		impact.Impact = ImpactLessPermissive
+	case changed:
+		impact.Impact = ImpactChanged
+	default:
+		return impact, false
+	}
+	return impact, true
+}
+
+// actionStrength returns the strength of the validation action, the
+// enforced actions being the strongest.
+func actionStrength(action ValidationAction) int {
+	switch action {
+	case ActionEnforce:
+		return 2
+	case ActionLog:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// timestampOption returns the effective timestamp option.
+func timestampOption(option TimestampOption) TimestampOption {
+	if option == "" {
+		return OptionAlways
+	}
+	return option
+}
+
+// difference returns the values of a not in b, in the order of a.
+func difference(a, b []string) []string {
+	var values []string
+	for _, v := range a {
+		if !slices.Contains(b, v) && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,132 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffOCIDocuments(t *testing.T) {
+	const (
+		netMonitor = "registry.acme-rockets.io/software/net-monitor"
+		dashboard  = "registry.acme-rockets.io/software/dashboard"
+		spdx       = "application/spdx+json"
+	)
+	newDocs := func() (*OCIDocument, *OCIDocument) {
+		oldDoc := dummyOCIPolicyDocument()
+		newDoc := dummyOCIPolicyDocument()
+		return &oldDoc, &newDoc
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		oldDoc, newDoc := newDocs()
+		// renaming a statement has no impact
+		newDoc.TrustPolicies[0].Name = "renamed"
+		if impacts := DiffOCIDocuments(oldDoc, newDoc); len(impacts) != 0 {
+			t.Fatalf("DiffOCIDocuments() = %+v, want no impact", impacts)
+		}
+	})
+
+	t.Run("more permissive", func(t *testing.T) {
+		oldDoc, newDoc := newDocs()
+		newDoc.TrustPolicies[0].TrustedIdentities = append(newDoc.TrustPolicies[0].TrustedIdentities, "x509.subject:CN=wabbit-networks.io,O=Notary,C=US")
+		newDoc.TrustPolicies[0].SignatureVerification.Override = map[ValidationType]ValidationAction{TypeExpiry: ActionLog}
+		impacts := DiffOCIDocuments(oldDoc, newDoc)
+		want := []ScopeImpact{{
+			RegistryScope:    netMonitor,
+			OldStatement:     "test-statement-name",
+			NewStatement:     "test-statement-name",
+			Impact:           ImpactMorePermissive,
+			GainedIdentities: []string{"x509.subject:CN=wabbit-networks.io,O=Notary,C=US"},
+			Changes: []string{
+				`"expiry" action changed from "enforce" to "log"`,
+				`identities ["x509.subject:CN=wabbit-networks.io,O=Notary,C=US"] are trusted`,
+			},
+		}}
+		if !reflect.DeepEqual(impacts, want) {
+			t.Fatalf("DiffOCIDocuments() = %+v, want %+v", impacts, want)
+		}
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		oldDoc, newDoc := newDocs()
+		newDoc.TrustPolicies[0].TrustStores = []string{"ca:valid-trust-store", "ca:acme-rockets"}
+		newDoc.TrustPolicies[0].SignatureVerification.VerificationLevel = "permissive"
+		impacts := DiffOCIDocuments(oldDoc, newDoc)
+		if len(impacts) != 1 || impacts[0].Impact != ImpactMixed || impacts[0].Changes[0] != `verification level changed from "strict" to "permissive"` {
+			t.Fatalf("DiffOCIDocuments() = %+v, want a mixed impact", impacts)
+		}
+		if !reflect.DeepEqual(impacts[0].AddedTrustStores, []string{"ca:acme-rockets"}) || !reflect.DeepEqual(impacts[0].RemovedTrustStores, []string{"signingAuthority:valid-trust-store"}) {
+			t.Fatalf("DiffOCIDocuments() trust stores = %+v, %+v", impacts[0].AddedTrustStores, impacts[0].RemovedTrustStores)
+		}
+	})
+
+	t.Run("wildcard statement", func(t *testing.T) {
+		oldDoc, newDoc := newDocs()
+		wildcard := OCITrustPolicy{
+			Name:                  "wildcard",
+			RegistryScopes:        []string{"*"},
+			SignatureVerification: SignatureVerification{VerificationLevel: "audit"},
+			TrustStores:           []string{"ca:valid-trust-store"},
+			TrustedIdentities:     []string{"*"},
+		}
+		newDoc.TrustPolicies = append(newDoc.TrustPolicies, wildcard)
+		impacts := DiffOCIDocuments(oldDoc, newDoc)
+		if len(impacts) != 1 || impacts[0].RegistryScope != "*" || impacts[0].Impact != ImpactMorePermissive || impacts[0].NewStatement != "wildcard" || impacts[0].OldStatement != "" {
+			t.Fatalf("DiffOCIDocuments() = %+v, want the wildcard scope more permissive", impacts)
+		}
+
+		// the dashboard repository, matched by the wildcard statement, gets a
+		// statement of its own
+		oldDoc.TrustPolicies = append(oldDoc.TrustPolicies, wildcard)
+		dashboardStatement := wildcard
+		dashboardStatement.Name = "dashboard"
+		dashboardStatement.RegistryScopes = []string{dashboard}
+		dashboardStatement.SignatureVerification = SignatureVerification{VerificationLevel: "strict"}
+		dashboardStatement.TrustedIdentities = []string{"x509.subject:CN=Notation Test Root,O=Notary,L=Seattle,ST=WA,C=US"}
+		newDoc.TrustPolicies = append(newDoc.TrustPolicies, dashboardStatement)
+		impacts = DiffOCIDocuments(oldDoc, newDoc)
+		if len(impacts) != 1 || impacts[0].RegistryScope != dashboard || impacts[0].Impact != ImpactLessPermissive || impacts[0].OldStatement != "wildcard" {
+			t.Fatalf("DiffOCIDocuments() = %+v, want the dashboard scope less permissive", impacts)
+		}
+		if !reflect.DeepEqual(impacts[0].LostIdentities, []string{"*"}) {
+			t.Fatalf("DiffOCIDocuments() lost identities = %+v, want *", impacts[0].LostIdentities)
+		}
+	})
+
+	t.Run("artifact type", func(t *testing.T) {
+		oldDoc, newDoc := newDocs()
+		oldDoc.Version = VersionV2
+		newDoc.Version = VersionV2
+		sbom := newDoc.TrustPolicies[0]
+		sbom.Name = "sbom"
+		sbom.ArtifactTypes = []string{spdx}
+		sbom.Endorsement = &Endorsement{TrustStores: []string{"ca:valid-trust-store"}, TrustedIdentities: []string{"*"}}
+		newDoc.TrustPolicies = append(newDoc.TrustPolicies, sbom)
+		impacts := DiffOCIDocuments(oldDoc, newDoc)
+		if len(impacts) != 1 || impacts[0].RegistryScope != netMonitor || impacts[0].ArtifactType != spdx || impacts[0].Impact != ImpactLessPermissive {
+			t.Fatalf("DiffOCIDocuments() = %+v, want the SBOMs less permissive", impacts)
+		}
+	})
+
+	t.Run("statement removed", func(t *testing.T) {
+		oldDoc, newDoc := newDocs()
+		newDoc.TrustPolicies = nil
+		impacts := DiffOCIDocuments(oldDoc, newDoc)
+		if len(impacts) != 1 || impacts[0].Impact != ImpactLessPermissive || impacts[0].NewStatement != "" {
+			t.Fatalf("DiffOCIDocuments() = %+v, want less permissive", impacts)
+		}
+	})
+}
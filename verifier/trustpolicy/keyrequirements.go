@@ -0,0 +1,168 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/notaryproject/notation-go/internal/slices"
+)
+
+// Elliptic curves allowed in [KeyRequirements].
+const (
+	CurveP256 = "P-256"
+	CurveP384 = "P-384"
+	CurveP521 = "P-521"
+)
+
+// Hash algorithms allowed in [KeyRequirements].
+const (
+	HashSHA256 = "SHA-256"
+	HashSHA384 = "SHA-384"
+	HashSHA512 = "SHA-512"
+)
+
+var (
+	supportedCurves         = []string{CurveP256, CurveP384, CurveP521}
+	supportedHashAlgorithms = []string{HashSHA256, HashSHA384, HashSHA512}
+)
+
+// KeyRequirements are the minimum key strength and the allowed algorithms of
+// the signing certificate chain, so that weak keys can be phased out by
+// policy.
+//
+// An empty requirement does not restrict the certificate chain.
+type KeyRequirements struct {
+	// MinRSAKeySize is the minimum size in bits of the RSA keys.
+	MinRSAKeySize int `json:"minRSAKeySize,omitempty"`
+
+	// AllowedCurves are the elliptic curves allowed for the EC keys, such as
+	// [CurveP256].
+	AllowedCurves []string `json:"allowedCurves,omitempty"`
+
+	// AllowedHashAlgorithms are the hash algorithms allowed for the signature
+	// of the envelope and of the certificates, such as [HashSHA256].
+	AllowedHashAlgorithms []string `json:"allowedHashAlgorithms,omitempty"`
+}
+
+// String describes the key requirements.
+func (r *KeyRequirements) String() string {
+	var requirements []string
+	if r.MinRSAKeySize > 0 {
+		requirements = append(requirements, fmt.Sprintf("RSA keys of at least %d bits", r.MinRSAKeySize))
+	}
+	if len(r.AllowedCurves) > 0 {
+		requirements = append(requirements, "elliptic curves "+strings.Join(r.AllowedCurves, ", "))
+	}
+	if len(r.AllowedHashAlgorithms) > 0 {
+		requirements = append(requirements, "hash algorithms "+strings.Join(r.AllowedHashAlgorithms, ", "))
+	}
+	if len(requirements) == 0 {
+		return "none"
+	}
+	return strings.Join(requirements, "; ")
+}
+
+// validate validates the key requirements of the policy statement.
+func (r *KeyRequirements) validate(policyName string) error {
+	if r.MinRSAKeySize < 0 {
+		return fmt.Errorf("trust policy statement %q has invalid keyRequirements: minRSAKeySize must not be negative, but got %d", policyName, r.MinRSAKeySize)
+	}
+	for _, curve := range r.AllowedCurves {
+		if !slices.Contains(supportedCurves, curve) {
+			return fmt.Errorf("trust policy statement %q has invalid keyRequirements: unsupported curve %q, supported curves are %q", policyName, curve, supportedCurves)
+		}
+	}
+	for _, hash := range r.AllowedHashAlgorithms {
+		if !slices.Contains(supportedHashAlgorithms, hash) {
+			return fmt.Errorf("trust policy statement %q has invalid keyRequirements: unsupported hash algorithm %q, supported hash algorithms are %q", policyName, hash, supportedHashAlgorithms)
+		}
+	}
+	return nil
+}
+
+// Check checks the certificate chain of a signature and the hash algorithm
+// of its signature algorithm against the key requirements.
+//
+// The keys of all the certificates are checked. The hash algorithms of the
+// certificate signatures are checked, except for the self-signature of the
+// root certificate which is trusted by the trust store rather than by its
+// signature.
+func (r *KeyRequirements) Check(certChain []*x509.Certificate, signatureHash crypto.Hash) error {
+	if r == nil {
+		return nil
+	}
+	if err := r.checkHash(signatureHash); err != nil {
+		return fmt.Errorf("signature does not meet the key requirements: %w", err)
+	}
+	for i, cert := range certChain {
+		if err := r.checkKey(cert.PublicKey); err != nil {
+			return fmt.Errorf("certificate with subject %q does not meet the key requirements: %w", cert.Subject, err)
+		}
+		if i == len(certChain)-1 {
+			break
+		}
+		if err := r.checkHash(certificateHash(cert.SignatureAlgorithm)); err != nil {
+			return fmt.Errorf("certificate with subject %q does not meet the key requirements: %w", cert.Subject, err)
+		}
+	}
+	return nil
+}
+
+func (r *KeyRequirements) checkKey(publicKey any) error {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if size := key.N.BitLen(); size < r.MinRSAKeySize {
+			return fmt.Errorf("RSA key size %d is less than the minimum of %d", size, r.MinRSAKeySize)
+		}
+	case *ecdsa.PublicKey:
+		if len(r.AllowedCurves) > 0 && !slices.Contains(r.AllowedCurves, key.Curve.Params().Name) {
+			return fmt.Errorf("elliptic curve %s is not allowed", key.Curve.Params().Name)
+		}
+	}
+	return nil
+}
+
+func (r *KeyRequirements) checkHash(hash crypto.Hash) error {
+	if len(r.AllowedHashAlgorithms) == 0 {
+		return nil
+	}
+	if !hash.Available() || !slices.Contains(r.AllowedHashAlgorithms, hash.String()) {
+		return fmt.Errorf("hash algorithm %s is not allowed", hash)
+	}
+	return nil
+}
+
+// certificateHash returns the hash algorithm of the certificate signature
+// algorithm, or zero if it is unknown.
+func certificateHash(alg x509.SignatureAlgorithm) crypto.Hash {
+	switch alg {
+	case x509.SHA256WithRSA, x509.SHA256WithRSAPSS, x509.ECDSAWithSHA256:
+		return crypto.SHA256
+	case x509.SHA384WithRSA, x509.SHA384WithRSAPSS, x509.ECDSAWithSHA384:
+		return crypto.SHA384
+	case x509.SHA512WithRSA, x509.SHA512WithRSAPSS, x509.ECDSAWithSHA512:
+		return crypto.SHA512
+	case x509.SHA1WithRSA, x509.ECDSAWithSHA1, x509.DSAWithSHA1:
+		return crypto.SHA1
+	case x509.MD5WithRSA:
+		return crypto.MD5
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"crypto"
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+)
+
+func TestKeyRequirementsValidate(t *testing.T) {
+	tests := []struct {
+		requirements KeyRequirements
+		wantErr      string
+	}{
+		{requirements: KeyRequirements{MinRSAKeySize: 3072, AllowedCurves: []string{CurveP384}, AllowedHashAlgorithms: []string{HashSHA384}}},
+		{requirements: KeyRequirements{MinRSAKeySize: -1}, wantErr: "minRSAKeySize must not be negative"},
+		{requirements: KeyRequirements{AllowedCurves: []string{"P-224"}}, wantErr: `unsupported curve "P-224"`},
+		{requirements: KeyRequirements{AllowedHashAlgorithms: []string{"SHA-1"}}, wantErr: `unsupported hash algorithm "SHA-1"`},
+	}
+	for _, tt := range tests {
+		sigVerification := SignatureVerification{VerificationLevel: "strict", KeyRequirements: &tt.requirements}
+		err := validatePolicyCore("test-statement-name", sigVerification, []string{"ca:valid-ts"}, []string{"*"})
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("validatePolicyCore(%+v) error = %v", tt.requirements, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("validatePolicyCore(%+v) error = %v, want %q", tt.requirements, err, tt.wantErr)
+		}
+	}
+}
+
+func TestKeyRequirementsCheck(t *testing.T) {
+	rsaChain := []*x509.Certificate{testhelper.GetRSALeafCertificate().Cert, testhelper.GetRSARootCertificate().Cert}
+	ecChain := []*x509.Certificate{testhelper.GetECLeafCertificate().Cert, testhelper.GetECRootCertificate().Cert}
+	tests := []struct {
+		name         string
+		requirements *KeyRequirements
+		certChain    []*x509.Certificate
+		hash         crypto.Hash
+		wantErr      bool
+	}{
+		{name: "nil requirements", certChain: rsaChain, hash: crypto.SHA1},
+		{name: "empty requirements", requirements: &KeyRequirements{}, certChain: rsaChain, hash: crypto.SHA384},
+		{name: "RSA key size met", requirements: &KeyRequirements{MinRSAKeySize: 3072}, certChain: rsaChain, hash: crypto.SHA384},
+		{name: "RSA key size not met", requirements: &KeyRequirements{MinRSAKeySize: 4096}, certChain: rsaChain, hash: crypto.SHA384, wantErr: true},
+		{name: "curve allowed", requirements: &KeyRequirements{AllowedCurves: []string{CurveP256, CurveP384}}, certChain: ecChain, hash: crypto.SHA384},
+		{name: "curve not allowed", requirements: &KeyRequirements{AllowedCurves: []string{CurveP521}}, certChain: ecChain, hash: crypto.SHA384, wantErr: true},
+		{name: "RSA key ignores curves", requirements: &KeyRequirements{AllowedCurves: []string{CurveP521}}, certChain: rsaChain, hash: crypto.SHA384},
+		{name: "signature hash not allowed", requirements: &KeyRequirements{AllowedHashAlgorithms: []string{HashSHA512}}, certChain: rsaChain, hash: crypto.SHA384, wantErr: true},
+		{name: "unknown signature hash", requirements: &KeyRequirements{AllowedHashAlgorithms: []string{HashSHA256}}, certChain: rsaChain, hash: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.requirements.Check(tt.certChain, tt.hash)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("certificate hash not allowed", func(t *testing.T) {
+		leaf := *rsaChain[0]
+		leaf.SignatureAlgorithm = x509.SHA1WithRSA
+		requirements := &KeyRequirements{AllowedHashAlgorithms: []string{HashSHA256, HashSHA384, HashSHA512}}
+		if err := requirements.Check([]*x509.Certificate{&leaf, rsaChain[1]}, crypto.SHA384); err == nil {
+			t.Fatal("Check() expected error")
+		}
+		root := *rsaChain[1]
+		root.SignatureAlgorithm = x509.SHA1WithRSA
+		if err := requirements.Check([]*x509.Certificate{rsaChain[0], &root}, crypto.SHA384); err != nil {
+			t.Fatalf("Check() of a root with a SHA-1 self-signature error = %v", err)
+		}
+	})
+}
+
+func TestKeyRequirementsString(t *testing.T) {
+	requirements := &KeyRequirements{MinRSAKeySize: 3072, AllowedCurves: []string{CurveP384}, AllowedHashAlgorithms: []string{HashSHA384, HashSHA512}}
+	want := "RSA keys of at least 3072 bits; elliptic curves P-384; hash algorithms SHA-384, SHA-512"
+	if got := requirements.String(); got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+	if got := (&KeyRequirements{}).String(); got != "none" {
+		t.Fatalf("String() = %q, want %q", got, "none")
+	}
+}
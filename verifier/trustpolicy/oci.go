@@ -14,6 +14,7 @@
 package trustpolicy
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -48,8 +49,25 @@ type OCITrustPolicy struct {
 	// TrustedIdentities this policy statement pins
 	TrustedIdentities []string `json:"trustedIdentities"`
 
+	// DeniedIdentities this policy statement rejects, even if they are
+	// trusted identities. It requires version 2.0 of the policy document.
+	DeniedIdentities []string `json:"deniedIdentities,omitempty"`
+
+	// Endorsement, if set, requires the signatures verified by this policy
+	// statement to be endorsed with a countersignature. It requires version
+	// 2.0 of the policy document.
+	Endorsement *Endorsement `json:"endorsement,omitempty"`
+
 	// RegistryScopes that this policy statement affects
 	RegistryScopes []string `json:"registryScopes"`
+
+	// ArtifactTypes, if set, restricts this policy statement to the
+	// artifacts of these artifact types in its registry scopes, e.g. the
+	// SBOMs attached to the images of a repository. For a registry scope, a
+	// statement with the artifact type of the artifact takes precedence
+	// over a statement without artifact types. It requires version 2.0 of
+	// the policy document.
+	ArtifactTypes []string `json:"artifactTypes,omitempty"`
 }
 
 // Document represents a trustPolicy.json document
@@ -70,27 +88,57 @@ type TrustPolicy = OCITrustPolicy
 // should not be used. To load OCI Document, use [LoadOCIDocument] function.
 var LoadDocument = LoadOCIDocument
 
-var supportedOCIPolicyVersions = []string{"1.0"}
+var supportedOCIPolicyVersions = []string{"1.0", VersionV2}
 
 // LoadOCIDocument retrieves a trust policy document from the local file system.
 // It attempts to read from [dir.PathOCITrustPolicy] first; if not found,
 // it tries [dir.PathTrustPolicy].
 // If both dir.PathOCITrustPolicy and dir.PathTrustPolicy exist,
 // dir.PathOCITrustPolicy will be read.
+//
+// Both version 1.0 and version 2.0 documents are loaded. See
+// [ParseOCIDocument].
 func LoadOCIDocument() (*OCIDocument, error) {
-	var doc OCIDocument
+	var data json.RawMessage
 
 	// attempt to load the document from dir.PathOCITrustPolicy
-	if err := getDocument(dir.PathOCITrustPolicy, &doc); err != nil {
+	if err := getDocument(dir.PathOCITrustPolicy, &data); err != nil {
 		// if the document is not found at the first path, try the second path
-		if errors.As(err, &errPolicyNotExist{}) {
-			if err := getDocument(dir.PathTrustPolicy, &doc); err != nil {
-				return nil, err
-			}
-			return &doc, nil
+		if !errors.As(err, &errPolicyNotExist{}) {
+			// if an error occurred other than the document not found, return
+			// it
+			return nil, err
 		}
+		if err := getDocument(dir.PathTrustPolicy, &data); err != nil {
+			return nil, err
+		}
+	}
+	doc, err := ParseOCIDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("malformed trust policy. To create a trust policy, see: %s", trustPolicyLink)
+	}
+	return doc, nil
+}
 
-		// if an error occurred other than the document not found, return it
+// ParseOCIDocument parses a version 1.0 or a version 2.0 trust policy
+// document for OCI artifacts. A version 2.0 document is converted to an
+// [OCIDocument] with [OCIDocumentV2.ToOCIDocument].
+func ParseOCIDocument(data []byte) (*OCIDocument, error) {
+	var header struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	if header.Version == VersionV2 {
+		var docV2 OCIDocumentV2
+		if err := json.Unmarshal(data, &docV2); err != nil {
+			return nil, err
+		}
+		return docV2.ToOCIDocument(), nil
+	}
+	var doc OCIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
 		return nil, err
 	}
 	return &doc, nil
@@ -125,6 +173,33 @@ func (policyDoc *OCIDocument) Validate() error {
 		if err := validatePolicyCore(statement.Name, statement.SignatureVerification, statement.TrustStores, statement.TrustedIdentities); err != nil {
 			return fmt.Errorf("oci trust policy: %w", err)
 		}
+		if len(statement.DeniedIdentities) > 0 {
+			if policyDoc.Version != VersionV2 {
+				return fmt.Errorf("oci trust policy statement %q has denied identities, which require version %q of the oci trust policy document", statement.Name, VersionV2)
+			}
+			if err := validateDeniedIdentities(statement.Name, statement.DeniedIdentities); err != nil {
+				return fmt.Errorf("oci trust policy: %w", err)
+			}
+		}
+		if len(statement.ArtifactTypes) > 0 {
+			if policyDoc.Version != VersionV2 {
+				return fmt.Errorf("oci trust policy statement %q has artifact types, which require version %q of the oci trust policy document", statement.Name, VersionV2)
+			}
+			if err := validateArtifactTypes(statement.Name, statement.ArtifactTypes); err != nil {
+				return fmt.Errorf("oci trust policy: %w", err)
+			}
+		}
+		if statement.Endorsement != nil {
+			if policyDoc.Version != VersionV2 {
+				return fmt.Errorf("oci trust policy statement %q has an endorsement, which requires version %q of the oci trust policy document", statement.Name, VersionV2)
+			}
+			if verificationLevel, _ := statement.SignatureVerification.GetVerificationLevel(); verificationLevel.Name == LevelSkip.Name {
+				return fmt.Errorf("oci trust policy statement %q is set to skip signature verification but requires an endorsement", statement.Name)
+			}
+			if err := statement.Endorsement.validate(statement.Name); err != nil {
+				return fmt.Errorf("oci trust policy: %w", err)
+			}
+		}
 		policyNames.Add(statement.Name)
 	}
 
@@ -138,24 +213,48 @@ func (policyDoc *OCIDocument) Validate() error {
 // GetApplicableTrustPolicy returns a pointer to the deep copied [OCITrustPolicy]
 // statement that applies to the given registry scope. If no applicable trust
 // policy is found, returns an error.
+//
+// The statements are selected in the following order of precedence:
+//  1. the statement with a registry scope matching the repository of the
+//     artifact exactly.
+//  2. the statement with the wildcard (*) registry scope.
+//
+// The statements with artifact types are not selected. Use
+// [OCIDocument.GetApplicableTrustPolicyForArtifactType] to select them.
+//
+// Between statements of the same precedence, which only occur in documents
+// failing [OCIDocument.Validate], the last statement in the document is
+// selected. Use [OCIDocument.ShadowedStatements] to find the statements that
+// never apply.
 // see https://github.com/notaryproject/specifications/tree/9c81dc773508dedc5a81c02c8d805de04f65050b/specs/trust-store-trust-policy.md#selecting-a-trust-policy-based-on-artifact-uri
 func (policyDoc *OCIDocument) GetApplicableTrustPolicy(artifactReference string) (*OCITrustPolicy, error) {
+	return policyDoc.GetApplicableTrustPolicyForArtifactType(artifactReference, "")
+}
+
+// GetApplicableTrustPolicyForArtifactType returns a pointer to the deep
+// copied [OCITrustPolicy] statement that applies to the given registry scope
+// and artifact type, e.g. "application/spdx+json". If no applicable trust
+// policy is found, returns an error.
+//
+// The statements are selected in the following order of precedence:
+//  1. the statement with a registry scope matching the repository of the
+//     artifact exactly and the artifact type in its artifact types.
+//  2. the statement with a registry scope matching the repository of the
+//     artifact exactly and no artifact types.
+//  3. the statement with the wildcard (*) registry scope and the artifact
+//     type in its artifact types.
+//  4. the statement with the wildcard (*) registry scope and no artifact
+//     types.
+//
+// If artifactType is empty, only the statements without artifact types are
+// selected, as by [OCIDocument.GetApplicableTrustPolicy].
+func (policyDoc *OCIDocument) GetApplicableTrustPolicyForArtifactType(artifactReference, artifactType string) (*OCITrustPolicy, error) {
 	artifactPath, err := getArtifactPathFromReference(artifactReference)
 	if err != nil {
 		return nil, err
 	}
 
-	var wildcardPolicy *OCITrustPolicy
-	var applicablePolicy *OCITrustPolicy
-	for _, policyStatement := range policyDoc.TrustPolicies {
-		if slices.Contains(policyStatement.RegistryScopes, trustpolicy.Wildcard) {
-			// we need to deep copy because we can't use the loop variable
-			// address. see https://stackoverflow.com/a/45967429
-			wildcardPolicy = (&policyStatement).clone()
-		} else if slices.Contains(policyStatement.RegistryScopes, artifactPath) {
-			applicablePolicy = (&policyStatement).clone()
-		}
-	}
+	applicablePolicy, wildcardPolicy := policyDoc.matchTrustPolicies(artifactPath, artifactType)
 	if applicablePolicy != nil {
 		// a policy with exact match for registry scope takes precedence over
 		// a wildcard (*) policy.
@@ -167,21 +266,79 @@ func (policyDoc *OCIDocument) GetApplicableTrustPolicy(artifactReference string)
 	}
 }
 
+// matchTrustPolicies returns the deep copied statements applicable to
+// artifactType with a registry scope matching artifactPath exactly and with
+// the wildcard registry scope, if any. A statement with artifactType in its
+// artifact types takes precedence over a statement without artifact types.
+func (policyDoc *OCIDocument) matchTrustPolicies(artifactPath, artifactType string) (applicablePolicy, wildcardPolicy *OCITrustPolicy) {
+	var typedPolicy, typedWildcardPolicy *OCITrustPolicy
+	for _, policyStatement := range policyDoc.TrustPolicies {
+		typed := len(policyStatement.ArtifactTypes) > 0
+		if typed && (artifactType == "" || !slices.Contains(policyStatement.ArtifactTypes, artifactType)) {
+			continue
+		}
+		// we need to deep copy because we can't use the loop variable
+		// address. see https://stackoverflow.com/a/45967429
+		switch {
+		case slices.Contains(policyStatement.RegistryScopes, trustpolicy.Wildcard) && typed:
+			typedWildcardPolicy = (&policyStatement).clone()
+		case slices.Contains(policyStatement.RegistryScopes, trustpolicy.Wildcard):
+			wildcardPolicy = (&policyStatement).clone()
+		case slices.Contains(policyStatement.RegistryScopes, artifactPath) && typed:
+			typedPolicy = (&policyStatement).clone()
+		case slices.Contains(policyStatement.RegistryScopes, artifactPath):
+			applicablePolicy = (&policyStatement).clone()
+		}
+	}
+	if typedPolicy != nil {
+		applicablePolicy = typedPolicy
+	}
+	if typedWildcardPolicy != nil {
+		wildcardPolicy = typedWildcardPolicy
+	}
+	return applicablePolicy, wildcardPolicy
+}
+
+// ScopedByArtifactType returns true if a statement with artifact types
+// applies to the repository of artifactReference, i.e. the applicable
+// statement depends on the artifact type of the artifact.
+func (policyDoc *OCIDocument) ScopedByArtifactType(artifactReference string) bool {
+	artifactPath, err := getArtifactPathFromReference(artifactReference)
+	if err != nil {
+		return false
+	}
+	for _, statement := range policyDoc.TrustPolicies {
+		if len(statement.ArtifactTypes) == 0 {
+			continue
+		}
+		if slices.Contains(statement.RegistryScopes, trustpolicy.Wildcard) || slices.Contains(statement.RegistryScopes, artifactPath) {
+			return true
+		}
+	}
+	return false
+}
+
 // clone returns a pointer to the deep copied [OCITrustPolicy]
 func (t *OCITrustPolicy) clone() *OCITrustPolicy {
 	return &OCITrustPolicy{
 		Name:                  t.Name,
 		SignatureVerification: t.SignatureVerification,
 		TrustedIdentities:     append([]string(nil), t.TrustedIdentities...),
+		DeniedIdentities:      append([]string(nil), t.DeniedIdentities...),
+		Endorsement:           t.Endorsement.clone(),
 		TrustStores:           append([]string(nil), t.TrustStores...),
 		RegistryScopes:        append([]string(nil), t.RegistryScopes...),
+		ArtifactTypes:         append([]string(nil), t.ArtifactTypes...),
 	}
 }
 
 // validateRegistryScopes validates if the policy document is following the
-// Notary Project spec rules for registry scopes
+// Notary Project spec rules for registry scopes. A registry scope is
+// associated with one statement without artifact types, and with one
+// statement per artifact type.
 func validateRegistryScopes(policyDoc *OCIDocument) error {
 	registryScopeCount := make(map[string]int)
+	typedRegistryScopes := make(map[[2]string]bool)
 	for _, statement := range policyDoc.TrustPolicies {
 		// Verify registry scopes are valid
 		if len(statement.RegistryScopes) == 0 {
@@ -196,7 +353,17 @@ func validateRegistryScopes(policyDoc *OCIDocument) error {
 					return err
 				}
 			}
-			registryScopeCount[scope]++
+			if len(statement.ArtifactTypes) == 0 {
+				registryScopeCount[scope]++
+				continue
+			}
+			for _, artifactType := range statement.ArtifactTypes {
+				key := [2]string{scope, artifactType}
+				if typedRegistryScopes[key] {
+					return fmt.Errorf("registry scope %q with artifact type %q is present in multiple oci trust policy statements, one registry scope value can only be associated with one statement per artifact type", scope, artifactType)
+				}
+				typedRegistryScopes[key] = true
+			}
 		}
 	}
 
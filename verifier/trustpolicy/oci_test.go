@@ -106,6 +106,106 @@ func TestApplicableTrustPolicy(t *testing.T) {
 	}
 }
 
+func TestApplicableTrustPolicyForArtifactType(t *testing.T) {
+	const sbomType = "application/spdx+json"
+	statement := func(name string, artifactTypes []string, scopes ...string) OCITrustPolicy {
+		s := dummyOCIPolicyDocument().TrustPolicies[0]
+		s.Name = name
+		s.RegistryScopes = scopes
+		s.ArtifactTypes = artifactTypes
+		return s
+	}
+	policyDoc := dummyOCIPolicyDocument()
+	policyDoc.Version = VersionV2
+	policyDoc.TrustPolicies = []OCITrustPolicy{
+		statement("app-sbom", []string{sbomType}, "registry.acme-rockets.io/app"),
+		statement("app", nil, "registry.acme-rockets.io/app"),
+		statement("sbom", []string{sbomType, "application/vnd.cyclonedx+json"}, "*"),
+		statement("default", nil, "*"),
+		statement("db-sbom", []string{sbomType}, "registry.acme-rockets.io/db"),
+	}
+	if err := policyDoc.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+
+	tests := []struct {
+		reference    string
+		artifactType string
+		want         string
+	}{
+		{"registry.acme-rockets.io/app@sha256:hash", sbomType, "app-sbom"},
+		{"registry.acme-rockets.io/app@sha256:hash", "application/vnd.oci.image.config.v1+json", "app"},
+		{"registry.acme-rockets.io/app@sha256:hash", "", "app"},
+		{"registry.acme-rockets.io/db@sha256:hash", sbomType, "db-sbom"},
+		{"registry.acme-rockets.io/db@sha256:hash", "", "default"},
+		{"registry.acme-rockets.io/other@sha256:hash", "application/vnd.cyclonedx+json", "sbom"},
+		{"registry.acme-rockets.io/other@sha256:hash", "", "default"},
+	}
+	for _, tt := range tests {
+		policy, err := policyDoc.GetApplicableTrustPolicyForArtifactType(tt.reference, tt.artifactType)
+		if err != nil {
+			t.Fatalf("GetApplicableTrustPolicyForArtifactType(%q, %q) failed: %v", tt.reference, tt.artifactType, err)
+		}
+		if policy.Name != tt.want {
+			t.Fatalf("GetApplicableTrustPolicyForArtifactType(%q, %q) = %q, want %q", tt.reference, tt.artifactType, policy.Name, tt.want)
+		}
+	}
+
+	// a statement with artifact types only applies to them
+	policyDoc.TrustPolicies = policyDoc.TrustPolicies[:1]
+	if _, err := policyDoc.GetApplicableTrustPolicy("registry.acme-rockets.io/app@sha256:hash"); err == nil {
+		t.Fatal("GetApplicableTrustPolicy() expects error for statements with artifact types only")
+	}
+	if !policyDoc.ScopedByArtifactType("registry.acme-rockets.io/app@sha256:hash") {
+		t.Fatal("ScopedByArtifactType() = false, want true")
+	}
+	if policyDoc.ScopedByArtifactType("registry.acme-rockets.io/db@sha256:hash") {
+		t.Fatal("ScopedByArtifactType() = true, want false")
+	}
+}
+
+func TestValidateArtifactTypes(t *testing.T) {
+	newDoc := func(artifactTypes ...[]string) OCIDocument {
+		policyDoc := dummyOCIPolicyDocument()
+		policyDoc.Version = VersionV2
+		policyDoc.TrustPolicies[0].ArtifactTypes = artifactTypes[0]
+		for i, types := range artifactTypes[1:] {
+			statement := policyDoc.TrustPolicies[0]
+			statement.Name = fmt.Sprintf("statement-%d", i)
+			statement.ArtifactTypes = types
+			policyDoc.TrustPolicies = append(policyDoc.TrustPolicies, statement)
+		}
+		return policyDoc
+	}
+
+	validDoc := newDoc([]string{"application/spdx+json"}, nil, []string{"application/vnd.cyclonedx+json"})
+	if err := validDoc.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+
+	tests := map[string]OCIDocument{
+		"version 1.0": func() OCIDocument {
+			d := newDoc([]string{"application/spdx+json"})
+			d.Version = "1.0"
+			return d
+		}(),
+		"not a media type":        newDoc([]string{"spdx"}),
+		"media type parameters":   newDoc([]string{"application/spdx+json; version=2.3"}),
+		"duplicate artifact type": newDoc([]string{"application/spdx+json", "application/spdx+json"}),
+		"duplicate scope and artifact type": newDoc(
+			[]string{"application/spdx+json"},
+			[]string{"application/vnd.cyclonedx+json", "application/spdx+json"},
+		),
+	}
+	for name, policyDoc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := policyDoc.Validate(); err == nil {
+				t.Fatal("Validate() expects error, got nil")
+			}
+		})
+	}
+}
+
 // TestValidatePolicyDocument calls policyDoc.Validate()
 // and tests various validations on policy elements
 func TestValidateInvalidPolicyDocument(t *testing.T) {
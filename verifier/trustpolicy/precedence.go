@@ -0,0 +1,129 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/notaryproject/notation-go/internal/slices"
+	"github.com/notaryproject/notation-go/internal/trustpolicy"
+)
+
+// ShadowedStatement describes an OCI trust policy statement that does not
+// apply to some or all of the repositories it could match, because another
+// statement takes precedence.
+//
+// The precedence of the statements is documented in
+// [OCIDocument.GetApplicableTrustPolicy].
+type ShadowedStatement struct {
+	// Name of the shadowed statement
+	Name string
+
+	// ShadowedBy maps each shadowed registry scope to the name of the
+	// statement taking precedence for it. For a statement with the wildcard
+	// registry scope, it includes the registry scopes of the statements
+	// matching a repository exactly.
+	ShadowedBy map[string]string
+
+	// Unreachable is true if the statement never applies to any artifact.
+	Unreachable bool
+}
+
+// ShadowedStatements returns the statements of policyDoc shadowed by other
+// statements, in the order of the document.
+//
+// A document passing [OCIDocument.Validate] never has unreachable statements,
+// but the statement with the wildcard registry scope is shadowed by the
+// statements with an exact registry scope. ShadowedStatements can be used to
+// lint a document before validating it.
+//
+// The statements with artifact types only shadow the statements with the
+// same artifact types, as they do not apply to the other artifacts.
+func (policyDoc *OCIDocument) ShadowedStatements() []ShadowedStatement {
+	// group the statements by their artifact types
+	groups := make(map[string][]int)
+	for i, statement := range policyDoc.TrustPolicies {
+		artifactTypes := append([]string(nil), statement.ArtifactTypes...)
+		sort.Strings(artifactTypes)
+		key := strings.Join(artifactTypes, ",")
+		groups[key] = append(groups[key], i)
+	}
+	shadowedByIndex := make(map[int]ShadowedStatement)
+	for _, indexes := range groups {
+		policyDoc.shadowedStatements(indexes, shadowedByIndex)
+	}
+
+	var shadowed []ShadowedStatement
+	for i := range policyDoc.TrustPolicies {
+		if statement, ok := shadowedByIndex[i]; ok {
+			shadowed = append(shadowed, statement)
+		}
+	}
+	return shadowed
+}
+
+// shadowedStatements adds the statements at indexes shadowed by the other
+// statements at indexes to shadowed, keyed by their index in the document.
+func (policyDoc *OCIDocument) shadowedStatements(indexes []int, shadowed map[int]ShadowedStatement) {
+	// find the statement taking precedence for each registry scope, the last
+	// one in the document wins between statements of the same specificity
+	exactIndex := make(map[string]int)
+	var exactScopes []string
+	wildcardIndex := -1
+	for _, i := range indexes {
+		statement := policyDoc.TrustPolicies[i]
+		if slices.Contains(statement.RegistryScopes, trustpolicy.Wildcard) {
+			wildcardIndex = i
+			continue
+		}
+		for _, scope := range statement.RegistryScopes {
+			if _, ok := exactIndex[scope]; !ok {
+				exactScopes = append(exactScopes, scope)
+			}
+			exactIndex[scope] = i
+		}
+	}
+
+	for _, i := range indexes {
+		statement := policyDoc.TrustPolicies[i]
+		shadowedBy := make(map[string]string)
+		isWildcard := slices.Contains(statement.RegistryScopes, trustpolicy.Wildcard)
+		for _, scope := range statement.RegistryScopes {
+			if scope == trustpolicy.Wildcard {
+				if i != wildcardIndex {
+					shadowedBy[scope] = policyDoc.TrustPolicies[wildcardIndex].Name
+				}
+			} else if j, ok := exactIndex[scope]; ok && j != i {
+				shadowedBy[scope] = policyDoc.TrustPolicies[j].Name
+			} else if isWildcard && i != wildcardIndex {
+				// the scope would be matched by the wildcard registry scope
+				shadowedBy[scope] = policyDoc.TrustPolicies[wildcardIndex].Name
+			}
+		}
+		unreachable := len(statement.RegistryScopes) > 0 && len(shadowedBy) == len(statement.RegistryScopes)
+		if i == wildcardIndex {
+			for _, scope := range exactScopes {
+				shadowedBy[scope] = policyDoc.TrustPolicies[exactIndex[scope]].Name
+			}
+		}
+		if len(shadowedBy) > 0 {
+			shadowed[i] = ShadowedStatement{
+				Name:        statement.Name,
+				ShadowedBy:  shadowedBy,
+				Unreachable: unreachable,
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShadowedStatements(t *testing.T) {
+	statement := func(name string, scopes ...string) OCITrustPolicy {
+		s := dummyOCIPolicyDocument().TrustPolicies[0]
+		s.Name = name
+		s.RegistryScopes = scopes
+		return s
+	}
+
+	t.Run("no shadowing", func(t *testing.T) {
+		policyDoc := dummyOCIPolicyDocument()
+		if got := policyDoc.ShadowedStatements(); len(got) != 0 {
+			t.Fatalf("ShadowedStatements() = %+v, want none", got)
+		}
+	})
+
+	t.Run("wildcard shadowed by exact match", func(t *testing.T) {
+		policyDoc := dummyOCIPolicyDocument()
+		policyDoc.TrustPolicies = []OCITrustPolicy{
+			statement("default", "*"),
+			statement("app", "registry.acme-rockets.io/app", "registry.acme-rockets.io/db"),
+		}
+		if err := policyDoc.Validate(); err != nil {
+			t.Fatalf("Validate() failed: %v", err)
+		}
+		want := []ShadowedStatement{
+			{
+				Name: "default",
+				ShadowedBy: map[string]string{
+					"registry.acme-rockets.io/app": "app",
+					"registry.acme-rockets.io/db":  "app",
+				},
+			},
+		}
+		if got := policyDoc.ShadowedStatements(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ShadowedStatements() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("overlapping statements", func(t *testing.T) {
+		policyDoc := dummyOCIPolicyDocument()
+		policyDoc.TrustPolicies = []OCITrustPolicy{
+			statement("default-1", "*"),
+			statement("app-1", "registry.acme-rockets.io/app", "registry.acme-rockets.io/db"),
+			statement("app-2", "registry.acme-rockets.io/app"),
+			statement("default-2", "*"),
+			statement("db", "registry.acme-rockets.io/db"),
+		}
+		want := []ShadowedStatement{
+			{
+				Name:        "default-1",
+				ShadowedBy:  map[string]string{"*": "default-2"},
+				Unreachable: true,
+			},
+			{
+				Name: "app-1",
+				ShadowedBy: map[string]string{
+					"registry.acme-rockets.io/app": "app-2",
+					"registry.acme-rockets.io/db":  "db",
+				},
+				Unreachable: true,
+			},
+			{
+				Name: "default-2",
+				ShadowedBy: map[string]string{
+					"registry.acme-rockets.io/app": "app-2",
+					"registry.acme-rockets.io/db":  "db",
+				},
+			},
+		}
+		if got := policyDoc.ShadowedStatements(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ShadowedStatements() = %+v, want %+v", got, want)
+		}
+
+		// the selected statements agree with the reported shadowing
+		for reference, name := range map[string]string{
+			"registry.acme-rockets.io/app@sha256:hash":   "app-2",
+			"registry.acme-rockets.io/db@sha256:hash":    "db",
+			"registry.acme-rockets.io/other@sha256:hash": "default-2",
+		} {
+			policy, err := policyDoc.GetApplicableTrustPolicy(reference)
+			if err != nil {
+				t.Fatalf("GetApplicableTrustPolicy() failed: %v", err)
+			}
+			if policy.Name != name {
+				t.Fatalf("GetApplicableTrustPolicy(%q) = %q, want %q", reference, policy.Name, name)
+			}
+		}
+	})
+
+	t.Run("partially shadowed", func(t *testing.T) {
+		policyDoc := dummyOCIPolicyDocument()
+		policyDoc.TrustPolicies = []OCITrustPolicy{
+			statement("app-1", "registry.acme-rockets.io/app", "registry.acme-rockets.io/db"),
+			statement("app-2", "registry.acme-rockets.io/app"),
+		}
+		want := []ShadowedStatement{
+			{
+				Name:       "app-1",
+				ShadowedBy: map[string]string{"registry.acme-rockets.io/app": "app-2"},
+			},
+		}
+		if got := policyDoc.ShadowedStatements(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ShadowedStatements() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("statements with artifact types", func(t *testing.T) {
+		policyDoc := dummyOCIPolicyDocument()
+		policyDoc.Version = VersionV2
+		sbom := statement("sbom", "*")
+		sbom.ArtifactTypes = []string{"application/spdx+json"}
+		appSBOM := statement("app-sbom", "registry.acme-rockets.io/app")
+		appSBOM.ArtifactTypes = []string{"application/spdx+json"}
+		policyDoc.TrustPolicies = []OCITrustPolicy{
+			statement("default", "*"),
+			sbom,
+			appSBOM,
+		}
+		if err := policyDoc.Validate(); err != nil {
+			t.Fatalf("Validate() failed: %v", err)
+		}
+		want := []ShadowedStatement{
+			{
+				Name:       "sbom",
+				ShadowedBy: map[string]string{"registry.acme-rockets.io/app": "app-sbom"},
+			},
+		}
+		if got := policyDoc.ShadowedStatements(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ShadowedStatements() = %+v, want %+v", got, want)
+		}
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"mime"
 	"os"
 	"strings"
 
@@ -150,6 +151,7 @@ type SignatureVerification struct {
 	VerificationLevel string                              `json:"level"`
 	Override          map[ValidationType]ValidationAction `json:"override,omitempty"`
 	VerifyTimestamp   TimestampOption                     `json:"verifyTimestamp,omitempty"`
+	KeyRequirements   *KeyRequirements                    `json:"keyRequirements,omitempty"`
 }
 
 type errPolicyNotExist struct{}
@@ -182,6 +184,14 @@ func (signatureVerification *SignatureVerification) GetVerificationLevel() (*Ver
 	if baseLevel == LevelSkip {
 		return nil, fmt.Errorf("signature verification level %q can't be used to customize signature verification", baseLevel.Name)
 	}
+	return baseLevel.Override(signatureVerification.Override)
+}
+
+// Override returns a "custom" verification level with the actions of the
+// verification level overridden by override. The same rules as the Override
+// of [SignatureVerification] apply: the integrity verification cannot be
+// overridden, and only the revocation check can be skipped.
+func (level *VerificationLevel) Override(override map[ValidationType]ValidationAction) (*VerificationLevel, error) {
 	customVerificationLevel := &VerificationLevel{
 		Name:        "custom",
 		Enforcement: make(map[ValidationType]ValidationAction),
@@ -189,12 +199,12 @@ func (signatureVerification *SignatureVerification) GetVerificationLevel() (*Ver
 
 	// populate the custom verification level with the base verification
 	// settings
-	for k, v := range baseLevel.Enforcement {
+	for k, v := range level.Enforcement {
 		customVerificationLevel.Enforcement[k] = v
 	}
 
 	// override the verification actions with the user configured settings
-	for key, value := range signatureVerification.Override {
+	for key, value := range override {
 		var validationType ValidationType
 		for _, t := range ValidationTypes {
 			if t == key {
@@ -276,6 +286,11 @@ func validatePolicyCore(name string, signatureVerification SignatureVerification
 		signatureVerification.VerifyTimestamp != OptionAfterCertExpiry {
 		return fmt.Errorf("trust policy statement %q has invalid signatureVerification: verifyTimestamp must be %q or %q, but got %q", name, OptionAlways, OptionAfterCertExpiry, signatureVerification.VerifyTimestamp)
 	}
+	if signatureVerification.KeyRequirements != nil {
+		if err := signatureVerification.KeyRequirements.validate(name); err != nil {
+			return err
+		}
+	}
 
 	// Any signature verification other than "skip" needs a trust store and
 	// trusted identities
@@ -312,6 +327,12 @@ func validateTrustStore(policyName string, trustStores []string) error {
 		if !isValidTrustStoreType(storeType) {
 			return fmt.Errorf("trust policy statement %q uses an unsupported trust store type %q in trust store value %q", policyName, storeType, trustStore)
 		}
+		if strings.HasPrefix(namedStore, truststore.PluginStorePrefix) {
+			if _, _, ok := truststore.ParsePluginStoreName(namedStore); !ok {
+				return fmt.Errorf("trust policy statement %q uses an unsupported plugin trust store name %q in trust store value %q. The required format is plugin:<PluginName>:<StoreName>, where both names follow [a-zA-Z0-9_.-]+ format", policyName, namedStore, trustStore)
+			}
+			continue
+		}
 		if !file.IsValidFileName(namedStore) {
 			return fmt.Errorf("trust policy statement %q uses an unsupported trust store name %q in trust store value %q. Named store name needs to follow [a-zA-Z0-9_.-]+ format", policyName, namedStore, trustStore)
 		}
@@ -352,6 +373,12 @@ func validateTrustedIdentities(policyName string, tis []string) error {
 				}
 				parsedDNs = append(parsedDNs, parsedDN{RawString: identity, ParsedMap: dn})
 			}
+
+			// organizational identities are resolved by the identity
+			// resolver plugin of the verifier
+			if identityPrefix == trustpolicy.OrgIdentity && identityValue == "" {
+				return fmt.Errorf("trust policy statement %q has trusted identity %q without an identity value", policyName, identity)
+			}
 		}
 	}
 
@@ -364,6 +391,38 @@ func validateTrustedIdentities(policyName string, tis []string) error {
 	return nil
 }
 
+// validateDeniedIdentities validates the denied identities of the policy
+// statement. Only x509.subject identities are supported, as they are
+// rejected by notation itself.
+func validateDeniedIdentities(policyName string, deniedIdentities []string) error {
+	for _, identity := range deniedIdentities {
+		identityPrefix, identityValue, found := strings.Cut(identity, ":")
+		if !found || identityPrefix != trustpolicy.X509Subject {
+			return fmt.Errorf("trust policy statement %q has denied identity %q, denied identities must be %q identities", policyName, identity, trustpolicy.X509Subject)
+		}
+		if _, err := pkix.ParseDistinguishedName(identityValue); identityValue == "" || err != nil {
+			return fmt.Errorf("trust policy statement %q has denied identity %q with invalid identity value", policyName, identity)
+		}
+	}
+	return nil
+}
+
+// validateArtifactTypes validates the artifact types of the policy statement.
+// Artifact types are media types, e.g. "application/spdx+json".
+func validateArtifactTypes(policyName string, artifactTypes []string) error {
+	seen := make(map[string]bool, len(artifactTypes))
+	for _, artifactType := range artifactTypes {
+		if mediaType, _, err := mime.ParseMediaType(artifactType); err != nil || mediaType != artifactType || !strings.Contains(artifactType, "/") {
+			return fmt.Errorf("trust policy statement %q has artifact type %q, artifact types must be media types without parameters", policyName, artifactType)
+		}
+		if seen[artifactType] {
+			return fmt.Errorf("trust policy statement %q has duplicate artifact type %q", policyName, artifactType)
+		}
+		seen[artifactType] = true
+	}
+	return nil
+}
+
 func validateOverlappingDNs(policyName string, parsedDNs []parsedDN) error {
 	for i, dn1 := range parsedDNs {
 		for j, dn2 := range parsedDNs {
@@ -134,6 +134,17 @@ func TestValidateTrustStore(t *testing.T) {
 	if err := validateTrustStore("test-statement-name", []string{"ca:#@$@$"}); err == nil || err.Error() != expectedErr {
 		t.Errorf("expected error '%s' but not found", expectedErr)
 	}
+
+	// valid plugin trust-store
+	if err := validateTrustStore("test-statement-name", []string{"ca:plugin:com.example.pki:corp-roots"}); err != nil {
+		t.Errorf("validateTrustStore returned error: '%v", err)
+	}
+
+	// invalid plugin trust-store name
+	expectedErr = "trust policy statement \"test-statement-name\" uses an unsupported plugin trust store name \"plugin:com.example.pki\" in trust store value \"ca:plugin:com.example.pki\". The required format is plugin:<PluginName>:<StoreName>, where both names follow [a-zA-Z0-9_.-]+ format"
+	if err := validateTrustStore("test-statement-name", []string{"ca:plugin:com.example.pki"}); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error '%s' but not found", expectedErr)
+	}
 }
 
 // TestValidateTrustedIdentities tests only valid x509.subjects are accepted
@@ -175,6 +186,16 @@ func TestValidateTrustedIdentities(t *testing.T) {
 		t.Fatalf("x509.subject identity without value should return error. Error : %q", err)
 	}
 
+	// Validate org.identity identities
+	err = validateTrustedIdentities("test-statement-name", []string{"org.identity:payments-team"})
+	if err != nil {
+		t.Fatalf("org.identity identity should not return an error. Error: %q", err)
+	}
+	err = validateTrustedIdentities("test-statement-name", []string{"org.identity:"})
+	if err == nil || err.Error() != "trust policy statement \"test-statement-name\" has trusted identity \"org.identity:\" without an identity value" {
+		t.Fatalf("org.identity identity without value should return error. Error : %q", err)
+	}
+
 	// Validate duplicate RDNs
 	invalidDN = "x509.subject:C=US,C=IN"
 	err = validateTrustedIdentities("test-statement-name", []string{invalidDN})
@@ -221,6 +242,30 @@ func TestValidateTrustedIdentities(t *testing.T) {
 	}
 }
 
+func TestVerificationLevelOverride(t *testing.T) {
+	level, err := LevelStrict.Override(map[ValidationType]ValidationAction{TypeExpiry: ActionLog})
+	if err != nil {
+		t.Fatalf("Override() error = %v", err)
+	}
+	if level.Name != "custom" || level.Enforcement[TypeExpiry] != ActionLog || level.Enforcement[TypeAuthenticity] != ActionEnforce {
+		t.Fatalf("Override() = %+v", level)
+	}
+	if LevelStrict.Enforcement[TypeExpiry] != ActionEnforce {
+		t.Fatal("Override() modified the base verification level")
+	}
+
+	for _, override := range []map[ValidationType]ValidationAction{
+		{TypeIntegrity: ActionLog},
+		{TypeExpiry: ActionSkip},
+		{"unknown": ActionLog},
+		{TypeExpiry: "ignore"},
+	} {
+		if _, err := LevelStrict.Override(override); err == nil {
+			t.Errorf("Override(%v) expects error, got nil", override)
+		}
+	}
+}
+
 func TestGetVerificationLevel(t *testing.T) {
 	tests := []struct {
 		verificationLevel   SignatureVerification
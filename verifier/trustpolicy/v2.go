@@ -0,0 +1,250 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/notaryproject/notation-go/verifier/truststore"
+)
+
+// VersionV2 is the version of the [OCIDocumentV2] layout.
+const VersionV2 = "2.0"
+
+// OCIDocumentV2 represents a version 2.0 trustpolicy.oci.json document for
+// OCI artifacts.
+//
+// Version 2.0 groups the settings of a statement by concern, so that new
+// settings are added to their group instead of the flat statement of version
+// 1.0:
+//   - the timestamp settings and the TSA trust stores are in Timestamp.
+//   - the revocation settings are in Revocation.
+//   - the identities rejected by the statement are in DeniedIdentities.
+//   - the countersignature required by the statement is in Endorsement.
+//   - the artifact types the statement is restricted to are in ArtifactTypes.
+//
+// A version 1.0 document is converted with [ConvertOCIDocument]. Both
+// versions are loaded by [LoadOCIDocument] and [ParseOCIDocument].
+type OCIDocumentV2 struct {
+	// Version of the policy document, [VersionV2].
+	Version string `json:"version"`
+
+	// TrustPolicies include each policy statement
+	TrustPolicies []OCITrustPolicyV2 `json:"trustPolicies"`
+}
+
+// OCITrustPolicyV2 represents a policy statement in the version 2.0 OCI
+// trust policy document.
+type OCITrustPolicyV2 struct {
+	// Name of the policy statement
+	Name string `json:"name"`
+
+	// RegistryScopes that this policy statement affects
+	RegistryScopes []string `json:"registryScopes"`
+
+	// ArtifactTypes, if set, restricts this policy statement to the artifacts
+	// of these artifact types in its registry scopes.
+	ArtifactTypes []string `json:"artifactTypes,omitempty"`
+
+	// SignatureVerification setting for this policy statement
+	SignatureVerification SignatureVerificationV2 `json:"signatureVerification"`
+
+	// TrustStores of the signing certificate chain this policy statement
+	// uses. The TSA trust stores are in Timestamp.
+	TrustStores []string `json:"trustStores,omitempty"`
+
+	// TrustedIdentities this policy statement pins
+	TrustedIdentities []string `json:"trustedIdentities,omitempty"`
+
+	// DeniedIdentities this policy statement rejects, even if they are
+	// trusted identities, e.g. the subject of a compromised signing
+	// certificate.
+	DeniedIdentities []string `json:"deniedIdentities,omitempty"`
+
+	// Endorsement, if set, requires the signatures verified by this policy
+	// statement to be endorsed with a countersignature.
+	Endorsement *Endorsement `json:"endorsement,omitempty"`
+
+	// Revocation sets the revocation check of this policy statement.
+	Revocation *RevocationSettings `json:"revocation,omitempty"`
+
+	// Timestamp sets the timestamp verification of this policy statement.
+	Timestamp *TimestampSettings `json:"timestamp,omitempty"`
+}
+
+// SignatureVerificationV2 represents the verification configuration in a
+// version 2.0 trust policy statement.
+type SignatureVerificationV2 struct {
+	// VerificationLevel is the name of the verification level.
+	VerificationLevel string `json:"level"`
+
+	// Override overrides the actions of the verification level. The
+	// revocation action is set by [RevocationSettings].
+	Override map[ValidationType]ValidationAction `json:"override,omitempty"`
+
+	// KeyRequirements restricts the keys of the signing certificate chain.
+	KeyRequirements *KeyRequirements `json:"keyRequirements,omitempty"`
+}
+
+// RevocationSettings sets the revocation check of a version 2.0 trust policy
+// statement.
+type RevocationSettings struct {
+	// Action overrides the action of the verification level on revocation
+	// check failures.
+	Action ValidationAction `json:"action,omitempty"`
+}
+
+// TimestampSettings sets the timestamp verification of a version 2.0 trust
+// policy statement.
+type TimestampSettings struct {
+	// Verify sets when the timestamp is verified. It defaults to
+	// [OptionAlways].
+	Verify TimestampOption `json:"verify,omitempty"`
+
+	// TrustStores are the TSA trust stores, e.g. "tsa:acme-tsa".
+	TrustStores []string `json:"trustStores,omitempty"`
+}
+
+// ConvertOCIDocument converts the version 1.0 trust policy document
+// policyDoc to version 2.0. The document is validated before conversion.
+func ConvertOCIDocument(policyDoc *OCIDocument) (*OCIDocumentV2, error) {
+	if err := policyDoc.Validate(); err != nil {
+		return nil, err
+	}
+	docV2 := &OCIDocumentV2{
+		Version:       VersionV2,
+		TrustPolicies: make([]OCITrustPolicyV2, 0, len(policyDoc.TrustPolicies)),
+	}
+	for _, statement := range policyDoc.TrustPolicies {
+		statementV2 := OCITrustPolicyV2{
+			Name:              statement.Name,
+			RegistryScopes:    append([]string(nil), statement.RegistryScopes...),
+			ArtifactTypes:     append([]string(nil), statement.ArtifactTypes...),
+			TrustedIdentities: append([]string(nil), statement.TrustedIdentities...),
+			DeniedIdentities:  append([]string(nil), statement.DeniedIdentities...),
+			Endorsement:       statement.Endorsement.clone(),
+			SignatureVerification: SignatureVerificationV2{
+				VerificationLevel: statement.SignatureVerification.VerificationLevel,
+				KeyRequirements:   statement.SignatureVerification.KeyRequirements,
+			},
+		}
+		for validationType, action := range statement.SignatureVerification.Override {
+			if validationType == TypeRevocation {
+				statementV2.Revocation = &RevocationSettings{Action: action}
+				continue
+			}
+			if statementV2.SignatureVerification.Override == nil {
+				statementV2.SignatureVerification.Override = make(map[ValidationType]ValidationAction)
+			}
+			statementV2.SignatureVerification.Override[validationType] = action
+		}
+		var tsaTrustStores []string
+		for _, trustStore := range statement.TrustStores {
+			if isTSATrustStore(trustStore) {
+				tsaTrustStores = append(tsaTrustStores, trustStore)
+			} else {
+				statementV2.TrustStores = append(statementV2.TrustStores, trustStore)
+			}
+		}
+		if statement.SignatureVerification.VerifyTimestamp != "" || len(tsaTrustStores) > 0 {
+			statementV2.Timestamp = &TimestampSettings{
+				Verify:      statement.SignatureVerification.VerifyTimestamp,
+				TrustStores: tsaTrustStores,
+			}
+		}
+		docV2.TrustPolicies = append(docV2.TrustPolicies, statementV2)
+	}
+	return docV2, nil
+}
+
+// ToOCIDocument converts the version 2.0 trust policy document to the
+// [OCIDocument] used by the verifier. The returned document has version
+// [VersionV2], so that the settings specific to version 2.0 are accepted by
+// [OCIDocument.Validate].
+func (policyDoc *OCIDocumentV2) ToOCIDocument() *OCIDocument {
+	doc := &OCIDocument{
+		Version:       policyDoc.Version,
+		TrustPolicies: make([]OCITrustPolicy, 0, len(policyDoc.TrustPolicies)),
+	}
+	for _, statementV2 := range policyDoc.TrustPolicies {
+		statement := OCITrustPolicy{
+			Name:              statementV2.Name,
+			RegistryScopes:    append([]string(nil), statementV2.RegistryScopes...),
+			ArtifactTypes:     append([]string(nil), statementV2.ArtifactTypes...),
+			TrustStores:       append([]string(nil), statementV2.TrustStores...),
+			TrustedIdentities: append([]string(nil), statementV2.TrustedIdentities...),
+			DeniedIdentities:  append([]string(nil), statementV2.DeniedIdentities...),
+			Endorsement:       statementV2.Endorsement.clone(),
+			SignatureVerification: SignatureVerification{
+				VerificationLevel: statementV2.SignatureVerification.VerificationLevel,
+				KeyRequirements:   statementV2.SignatureVerification.KeyRequirements,
+			},
+		}
+		if len(statementV2.SignatureVerification.Override) > 0 || statementV2.Revocation != nil {
+			statement.SignatureVerification.Override = make(map[ValidationType]ValidationAction)
+			for validationType, action := range statementV2.SignatureVerification.Override {
+				statement.SignatureVerification.Override[validationType] = action
+			}
+			if statementV2.Revocation != nil && statementV2.Revocation.Action != "" {
+				statement.SignatureVerification.Override[TypeRevocation] = statementV2.Revocation.Action
+			}
+		}
+		if statementV2.Timestamp != nil {
+			statement.SignatureVerification.VerifyTimestamp = statementV2.Timestamp.Verify
+			statement.TrustStores = append(statement.TrustStores, statementV2.Timestamp.TrustStores...)
+		}
+		doc.TrustPolicies = append(doc.TrustPolicies, statement)
+	}
+	return doc
+}
+
+// Validate validates the version 2.0 policy document. If any rule is
+// violated, returns an error.
+func (policyDoc *OCIDocumentV2) Validate() error {
+	// sanity check
+	if policyDoc == nil {
+		return errors.New("oci trust policy document cannot be nil")
+	}
+	if policyDoc.Version != VersionV2 {
+		return fmt.Errorf("oci trust policy document version %q is not %q", policyDoc.Version, VersionV2)
+	}
+
+	// Validate the layout of version 2.0
+	for _, statement := range policyDoc.TrustPolicies {
+		if _, ok := statement.SignatureVerification.Override[TypeRevocation]; ok {
+			return fmt.Errorf("oci trust policy statement %q overrides the revocation action in signatureVerification, set revocation.action instead", statement.Name)
+		}
+		for _, trustStore := range statement.TrustStores {
+			if isTSATrustStore(trustStore) {
+				return fmt.Errorf("oci trust policy statement %q has TSA trust store %q in trustStores, set it in timestamp.trustStores instead", statement.Name, trustStore)
+			}
+		}
+		if statement.Timestamp != nil {
+			for _, trustStore := range statement.Timestamp.TrustStores {
+				if !isTSATrustStore(trustStore) {
+					return fmt.Errorf("oci trust policy statement %q has trust store %q in timestamp.trustStores, only %q trust stores are allowed", statement.Name, trustStore, truststore.TypeTSA)
+				}
+			}
+		}
+	}
+	return policyDoc.ToOCIDocument().Validate()
+}
+
+// isTSATrustStore returns true if trustStore is a TSA trust store.
+func isTSATrustStore(trustStore string) bool {
+	storeType, _, _ := strings.Cut(trustStore, ":")
+	return storeType == string(truststore.TypeTSA)
+}
@@ -0,0 +1,221 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trustpolicy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/dir"
+)
+
+func dummyOCIPolicyDocumentWithExtensions() OCIDocument {
+	policyDoc := dummyOCIPolicyDocument()
+	policyDoc.TrustPolicies[0].TrustStores = append(policyDoc.TrustPolicies[0].TrustStores, "tsa:valid-tsa-store")
+	policyDoc.TrustPolicies[0].SignatureVerification.VerifyTimestamp = OptionAfterCertExpiry
+	policyDoc.TrustPolicies[0].SignatureVerification.Override = map[ValidationType]ValidationAction{
+		TypeExpiry:     ActionLog,
+		TypeRevocation: ActionSkip,
+	}
+	return policyDoc
+}
+
+func TestConvertOCIDocument(t *testing.T) {
+	policyDoc := dummyOCIPolicyDocumentWithExtensions()
+	docV2, err := ConvertOCIDocument(&policyDoc)
+	if err != nil {
+		t.Fatalf("ConvertOCIDocument() error = %v", err)
+	}
+	if err := docV2.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	want := &OCIDocumentV2{
+		Version: VersionV2,
+		TrustPolicies: []OCITrustPolicyV2{
+			{
+				Name:           "test-statement-name",
+				RegistryScopes: []string{"registry.acme-rockets.io/software/net-monitor"},
+				SignatureVerification: SignatureVerificationV2{
+					VerificationLevel: "strict",
+					Override:          map[ValidationType]ValidationAction{TypeExpiry: ActionLog},
+				},
+				TrustStores:       []string{"ca:valid-trust-store", "signingAuthority:valid-trust-store"},
+				TrustedIdentities: []string{"x509.subject:CN=Notation Test Root,O=Notary,L=Seattle,ST=WA,C=US"},
+				Revocation:        &RevocationSettings{Action: ActionSkip},
+				Timestamp: &TimestampSettings{
+					Verify:      OptionAfterCertExpiry,
+					TrustStores: []string{"tsa:valid-tsa-store"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(docV2, want) {
+		t.Fatalf("ConvertOCIDocument() = %+v, want %+v", docV2, want)
+	}
+
+	// round trip
+	doc := docV2.ToOCIDocument()
+	if doc.Version != VersionV2 {
+		t.Fatalf("ToOCIDocument() version = %q, want %q", doc.Version, VersionV2)
+	}
+	doc.Version = policyDoc.Version
+	if !reflect.DeepEqual(doc, &policyDoc) {
+		t.Fatalf("ToOCIDocument() = %+v, want %+v", doc, &policyDoc)
+	}
+
+	invalidDoc := dummyOCIPolicyDocument()
+	invalidDoc.Version = "3.0"
+	if _, err := ConvertOCIDocument(&invalidDoc); err == nil {
+		t.Fatal("ConvertOCIDocument() expects error for invalid document")
+	}
+}
+
+func TestOCIDocumentV2Validate(t *testing.T) {
+	newDoc := func() *OCIDocumentV2 {
+		policyDoc := dummyOCIPolicyDocument()
+		docV2, err := ConvertOCIDocument(&policyDoc)
+		if err != nil {
+			t.Fatalf("ConvertOCIDocument() error = %v", err)
+		}
+		return docV2
+	}
+
+	docV2 := newDoc()
+	docV2.TrustPolicies[0].DeniedIdentities = []string{"x509.subject:CN=Compromised,O=Notary,L=Seattle,ST=WA,C=US"}
+	if err := docV2.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*OCIDocumentV2)
+	}{
+		{
+			name:   "nil document",
+			modify: nil,
+		},
+		{
+			name:   "version 1.0",
+			modify: func(d *OCIDocumentV2) { d.Version = "1.0" },
+		},
+		{
+			name: "revocation override",
+			modify: func(d *OCIDocumentV2) {
+				d.TrustPolicies[0].SignatureVerification.Override = map[ValidationType]ValidationAction{TypeRevocation: ActionLog}
+			},
+		},
+		{
+			name: "tsa trust store in trust stores",
+			modify: func(d *OCIDocumentV2) {
+				d.TrustPolicies[0].TrustStores = append(d.TrustPolicies[0].TrustStores, "tsa:valid-tsa-store")
+			},
+		},
+		{
+			name: "ca trust store in timestamp trust stores",
+			modify: func(d *OCIDocumentV2) {
+				d.TrustPolicies[0].Timestamp = &TimestampSettings{TrustStores: []string{"ca:valid-trust-store"}}
+			},
+		},
+		{
+			name: "invalid revocation action",
+			modify: func(d *OCIDocumentV2) {
+				d.TrustPolicies[0].Revocation = &RevocationSettings{Action: "ignore"}
+			},
+		},
+		{
+			name: "wildcard denied identity",
+			modify: func(d *OCIDocumentV2) {
+				d.TrustPolicies[0].DeniedIdentities = []string{"*"}
+			},
+		},
+		{
+			name: "invalid denied identity",
+			modify: func(d *OCIDocumentV2) {
+				d.TrustPolicies[0].DeniedIdentities = []string{"x509.subject:"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var docV2 *OCIDocumentV2
+			if tt.modify != nil {
+				docV2 = newDoc()
+				tt.modify(docV2)
+			}
+			if err := docV2.Validate(); err == nil {
+				t.Fatal("Validate() expects error, got nil")
+			}
+		})
+	}
+}
+
+func TestOCIDocumentDeniedIdentitiesRequireV2(t *testing.T) {
+	policyDoc := dummyOCIPolicyDocument()
+	policyDoc.TrustPolicies[0].DeniedIdentities = []string{"x509.subject:CN=Compromised,O=Notary,L=Seattle,ST=WA,C=US"}
+	if err := policyDoc.Validate(); err == nil {
+		t.Fatal("Validate() expects error for denied identities in version 1.0")
+	}
+	policyDoc.Version = VersionV2
+	if err := policyDoc.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadOCIDocumentV2(t *testing.T) {
+	tempRoot := t.TempDir()
+	dir.UserConfigDir = tempRoot
+	policyDoc := dummyOCIPolicyDocumentWithExtensions()
+	docV2, err := ConvertOCIDocument(&policyDoc)
+	if err != nil {
+		t.Fatalf("ConvertOCIDocument() error = %v", err)
+	}
+	docV2.TrustPolicies[0].DeniedIdentities = []string{"x509.subject:CN=Compromised,O=Notary,L=Seattle,ST=WA,C=US"}
+	docV2.TrustPolicies[0].ArtifactTypes = []string{"application/spdx+json"}
+	policyJSON, _ := json.Marshal(docV2)
+	if err := os.WriteFile(filepath.Join(tempRoot, "trustpolicy.oci.json"), policyJSON, 0600); err != nil {
+		t.Fatalf("failed to write policy file: %v", err)
+	}
+
+	doc, err := LoadOCIDocument()
+	if err != nil {
+		t.Fatalf("LoadOCIDocument() error = %v", err)
+	}
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !reflect.DeepEqual(doc, docV2.ToOCIDocument()) {
+		t.Fatalf("LoadOCIDocument() = %+v, want %+v", doc, docV2.ToOCIDocument())
+	}
+}
+
+func TestParseOCIDocument(t *testing.T) {
+	policyDoc := dummyOCIPolicyDocument()
+	policyJSON, _ := json.Marshal(policyDoc)
+	doc, err := ParseOCIDocument(policyJSON)
+	if err != nil {
+		t.Fatalf("ParseOCIDocument() error = %v", err)
+	}
+	if !reflect.DeepEqual(doc, &policyDoc) {
+		t.Fatalf("ParseOCIDocument() = %+v, want %+v", doc, &policyDoc)
+	}
+
+	for _, data := range []string{`{`, `{"version": 2}`, `{"version": "2.0", "trustPolicies": {}}`} {
+		if _, err := ParseOCIDocument([]byte(data)); err == nil {
+			t.Fatalf("ParseOCIDocument(%s) expects error, got nil", data)
+		}
+	}
+}
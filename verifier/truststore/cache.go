@@ -0,0 +1,84 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truststore
+
+import (
+	"context"
+	"crypto/x509"
+	"sync"
+)
+
+// CachedX509TrustStore is an [X509TrustStore] caching the certificates of
+// the named trust stores loaded from the underlying trust store until
+// [CachedX509TrustStore.Reset] is called. Errors are not cached.
+//
+// It is safe for concurrent use.
+type CachedX509TrustStore struct {
+	store X509TrustStore
+
+	mu    sync.RWMutex
+	certs map[cacheKey][]*x509.Certificate
+
+	// generation is incremented on each reset, so that the certificates
+	// loaded before a reset are not cached after it.
+	generation uint64
+}
+
+// cacheKey identifies a named trust store.
+type cacheKey struct {
+	storeType  Type
+	namedStore string
+}
+
+// NewCachedX509TrustStore returns a [CachedX509TrustStore] caching the
+// certificates of store.
+func NewCachedX509TrustStore(store X509TrustStore) *CachedX509TrustStore {
+	return &CachedX509TrustStore{
+		store: store,
+		certs: make(map[cacheKey][]*x509.Certificate),
+	}
+}
+
+// GetCertificates returns certificates under storeType/namedStore
+func (c *CachedX509TrustStore) GetCertificates(ctx context.Context, storeType Type, namedStore string) ([]*x509.Certificate, error) {
+	key := cacheKey{storeType: storeType, namedStore: namedStore}
+	c.mu.RLock()
+	certs, ok := c.certs[key]
+	generation := c.generation
+	c.mu.RUnlock()
+	if !ok {
+		var err error
+		certs, err = c.store.GetCertificates(ctx, storeType, namedStore)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.generation == generation {
+			c.certs[key] = certs
+		}
+		c.mu.Unlock()
+	}
+	// return a copy so that callers cannot modify the cached slice
+	return append([]*x509.Certificate(nil), certs...), nil
+}
+
+// Reset discards the cached certificates so that they are loaded from the
+// underlying trust store on next use. The certificates of loads in progress
+// are returned to their callers but not cached.
+func (c *CachedX509TrustStore) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.certs = make(map[cacheKey][]*x509.Certificate)
+	c.generation++
+}
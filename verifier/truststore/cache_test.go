@@ -0,0 +1,139 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truststore
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// countingTrustStore counts the loads of the trust store.
+type countingTrustStore struct {
+	mu    sync.Mutex
+	loads int
+	err   error
+}
+
+func (s *countingTrustStore) GetCertificates(ctx context.Context, storeType Type, namedStore string) ([]*x509.Certificate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loads++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []*x509.Certificate{{}}, nil
+}
+
+func TestCachedX509TrustStore(t *testing.T) {
+	store := &countingTrustStore{}
+	cached := NewCachedX509TrustStore(store)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			certs, err := cached.GetCertificates(ctx, TypeCA, "store")
+			if err != nil || len(certs) != 1 {
+				t.Errorf("GetCertificates() = %v, %v", certs, err)
+			}
+		}()
+	}
+	wg.Wait()
+	loads := store.loads
+	if _, err := cached.GetCertificates(ctx, TypeCA, "store"); err != nil {
+		t.Fatal(err)
+	}
+	if store.loads != loads {
+		t.Fatalf("GetCertificates() loaded the cached trust store again")
+	}
+	if _, err := cached.GetCertificates(ctx, TypeSigningAuthority, "store"); err != nil {
+		t.Fatal(err)
+	}
+	if store.loads != loads+1 {
+		t.Fatalf("GetCertificates() loads = %d, want %d", store.loads, loads+1)
+	}
+
+	cached.Reset()
+	if _, err := cached.GetCertificates(ctx, TypeCA, "store"); err != nil {
+		t.Fatal(err)
+	}
+	if store.loads != loads+2 {
+		t.Fatalf("GetCertificates() after Reset() loads = %d, want %d", store.loads, loads+2)
+	}
+}
+
+func TestCachedX509TrustStore_Error(t *testing.T) {
+	store := &countingTrustStore{err: errors.New("not found")}
+	cached := NewCachedX509TrustStore(store)
+	for i := 0; i < 2; i++ {
+		if _, err := cached.GetCertificates(context.Background(), TypeCA, "store"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if store.loads != 2 {
+		t.Fatalf("GetCertificates() loads = %d, want 2 as errors are not cached", store.loads)
+	}
+}
+
+// blockingTrustStore blocks the loads of the trust store until released.
+type blockingTrustStore struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingTrustStore) GetCertificates(ctx context.Context, storeType Type, namedStore string) ([]*x509.Certificate, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return []*x509.Certificate{{}}, nil
+}
+
+func TestCachedX509TrustStore_ResetDuringLoad(t *testing.T) {
+	store := &blockingTrustStore{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	cached := NewCachedX509TrustStore(store)
+	ctx := context.Background()
+
+	done := make(chan error)
+	go func() {
+		_, err := cached.GetCertificates(ctx, TypeCA, "store")
+		done <- err
+	}()
+	<-store.started
+	cached.Reset()
+	close(store.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// the certificates loaded before the reset are not cached
+	go func() {
+		_, err := cached.GetCertificates(ctx, TypeCA, "store")
+		done <- err
+	}()
+	select {
+	case <-store.started:
+	case err := <-done:
+		t.Fatalf("GetCertificates() returned certificates loaded before Reset(), error = %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
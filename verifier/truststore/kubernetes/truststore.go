@@ -0,0 +1,282 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kubernetes provides an [truststore.X509TrustStore] reading the
+// trusted certificates from Kubernetes Secrets and ConfigMaps, so that the
+// verifiers running in a cluster do not need the trust material baked into
+// their images or mounted as volumes.
+//
+// It is a separate module, so that the users of notation-go not running in
+// Kubernetes do not depend on the Kubernetes client libraries.
+//
+// A named trust store is made of the Secrets and ConfigMaps of the namespace
+// labeled with its type and name, e.g. the "ca:acme-rockets" trust store of a
+// trust policy:
+//
+//	apiVersion: v1
+//	kind: ConfigMap
+//	metadata:
+//	  name: acme-rockets-ca
+//	  labels:
+//	    notation.notaryproject.dev/trust-store-type: ca
+//	    notation.notaryproject.dev/trust-store: acme-rockets
+//	data:
+//	  root.crt: |
+//	    -----BEGIN CERTIFICATE-----
+//	    ...
+//
+// The keys of the data with the ".crt", ".cer" or ".pem" extension hold the
+// PEM or DER encoded certificates, the other keys, e.g. "tls.key", are
+// ignored. The objects are watched with informers, so that the changes are
+// reflected without restarting the verifier.
+package kubernetes
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// LabelTrustStoreType is the label of the Secrets and ConfigMaps set to
+	// the type of their trust store, e.g. "ca".
+	LabelTrustStoreType = "notation.notaryproject.dev/trust-store-type"
+
+	// LabelTrustStore is the label of the Secrets and ConfigMaps set to the
+	// name of their trust store.
+	LabelTrustStore = "notation.notaryproject.dev/trust-store"
+)
+
+// certificateExtensions are the extensions of the keys of the data holding
+// certificates.
+var certificateExtensions = []string{".crt", ".cer", ".pem"}
+
+// Options contains the optional parameters of [NewTrustStore].
+type Options struct {
+	// Namespace of the Secrets and ConfigMaps. It is required, so that the
+	// trust stores cannot be extended from other namespaces.
+	Namespace string
+
+	// ResyncPeriod is the resync period of the informers. If set to less
+	// than or equals to zero, the informers do not resync, and rely on the
+	// watch events only.
+	ResyncPeriod time.Duration
+
+	// DisableSecrets does not read the trust stores from Secrets, e.g. if
+	// the service account of the verifier is not allowed to watch them.
+	DisableSecrets bool
+
+	// DisableConfigMaps does not read the trust stores from ConfigMaps.
+	DisableConfigMaps bool
+}
+
+// TrustStore is an [truststore.X509TrustStore] reading the trusted
+// certificates from the Secrets and ConfigMaps of a namespace.
+//
+// It is safe for concurrent use.
+type TrustStore struct {
+	secrets    corelisters.SecretNamespaceLister
+	configMaps corelisters.ConfigMapNamespaceLister
+}
+
+// NewTrustStore returns a [TrustStore] reading the Secrets and ConfigMaps of
+// opts.Namespace with client. It starts the informers watching the labeled
+// objects and waits for their caches to be synced. The informers are stopped
+// when ctx is done.
+func NewTrustStore(ctx context.Context, client kubernetes.Interface, opts Options) (*TrustStore, error) {
+	if client == nil {
+		return nil, errors.New("client cannot be nil")
+	}
+	if opts.Namespace == "" {
+		return nil, errors.New("namespace cannot be empty")
+	}
+	if opts.DisableSecrets && opts.DisableConfigMaps {
+		return nil, errors.New("secrets and configmaps cannot be both disabled")
+	}
+	resyncPeriod := opts.ResyncPeriod
+	if resyncPeriod < 0 {
+		resyncPeriod = 0
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(client, resyncPeriod,
+		informers.WithNamespace(opts.Namespace),
+		informers.WithTweakListOptions(func(listOpts *metav1.ListOptions) {
+			// only watch the objects of a trust store
+			listOpts.LabelSelector = LabelTrustStoreType + "," + LabelTrustStore
+		}),
+	)
+	store := &TrustStore{}
+	var synced []cache.InformerSynced
+	if !opts.DisableSecrets {
+		informer := factory.Core().V1().Secrets()
+		store.secrets = informer.Lister().Secrets(opts.Namespace)
+		synced = append(synced, informer.Informer().HasSynced)
+	}
+	if !opts.DisableConfigMaps {
+		informer := factory.Core().V1().ConfigMaps()
+		store.configMaps = informer.Lister().ConfigMaps(opts.Namespace)
+		synced = append(synced, informer.Informer().HasSynced)
+	}
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return nil, fmt.Errorf("failed to sync the trust stores of namespace %q: %w", opts.Namespace, context.Cause(ctx))
+	}
+	return store, nil
+}
+
+// GetCertificates returns certificates under storeType/namedStore
+func (s *TrustStore) GetCertificates(ctx context.Context, storeType truststore.Type, namedStore string) ([]*x509.Certificate, error) {
+	if !slices.Contains(truststore.Types, storeType) {
+		return nil, truststore.TrustStoreError{Msg: fmt.Sprintf("unsupported trust store type: %s", storeType)}
+	}
+	selector, err := labels.ValidatedSelectorFromSet(labels.Set{
+		LabelTrustStoreType: string(storeType),
+		LabelTrustStore:     namedStore,
+	})
+	if err != nil {
+		return nil, truststore.TrustStoreError{InnerError: err, Msg: fmt.Sprintf("trust store name %s is not a valid label value", namedStore)}
+	}
+
+	var objects []trustStoreObject
+	if s.secrets != nil {
+		secrets, err := s.secrets.List(selector)
+		if err != nil {
+			return nil, truststore.TrustStoreError{InnerError: err, Msg: fmt.Sprintf("failed to list the secrets of trust store %q of type %q", namedStore, storeType)}
+		}
+		for _, secret := range secrets {
+			objects = append(objects, secretObject(secret))
+		}
+	}
+	if s.configMaps != nil {
+		configMaps, err := s.configMaps.List(selector)
+		if err != nil {
+			return nil, truststore.TrustStoreError{InnerError: err, Msg: fmt.Sprintf("failed to list the configmaps of trust store %q of type %q", namedStore, storeType)}
+		}
+		for _, configMap := range configMaps {
+			objects = append(objects, configMapObject(configMap))
+		}
+	}
+	if len(objects) == 0 {
+		return nil, truststore.TrustStoreError{InnerError: fs.ErrNotExist, Msg: fmt.Sprintf("the trust store %q of type %q does not exist", namedStore, storeType)}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].name < objects[j].name
+	})
+
+	var certificates []*x509.Certificate
+	for _, object := range objects {
+		keys := make([]string, 0, len(object.data))
+		for key := range object.data {
+			if slices.Contains(certificateExtensions, path.Ext(key)) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			certs, err := parseCertificates(object.data[key])
+			if err != nil {
+				return nil, truststore.CertificateError{InnerError: err, Msg: fmt.Sprintf("failed to read the trusted certificate %s of %s in trust store %s of type %s", key, object.name, namedStore, storeType)}
+			}
+			if err := truststore.ValidateCertificates(certs); err != nil {
+				return nil, truststore.CertificateError{InnerError: err, Msg: fmt.Sprintf("failed to validate the trusted certificate %s of %s in trust store %s of type %s", key, object.name, namedStore, storeType)}
+			}
+			// we require TSA certificates in trust store to be root CA
+			// certificates
+			if storeType == truststore.TypeTSA {
+				for _, cert := range certs {
+					if err := isRootCACertificate(cert); err != nil {
+						return nil, truststore.CertificateError{InnerError: err, Msg: fmt.Sprintf("trusted certificate %s of %s in trust store %s of type %s is invalid: %v", key, object.name, namedStore, storeType, err)}
+					}
+				}
+			}
+			certificates = append(certificates, certs...)
+		}
+	}
+	if len(certificates) < 1 {
+		return nil, truststore.CertificateError{InnerError: fs.ErrNotExist, Msg: fmt.Sprintf("no x509 certificates were found in trust store %q of type %q", namedStore, storeType)}
+	}
+	return certificates, nil
+}
+
+// trustStoreObject is a Secret or a ConfigMap of a trust store.
+type trustStoreObject struct {
+	// name is the kind and the name of the object, e.g. "secret/acme".
+	name string
+	data map[string][]byte
+}
+
+func secretObject(secret *corev1.Secret) trustStoreObject {
+	return trustStoreObject{
+		name: "secret/" + secret.Name,
+		data: secret.Data,
+	}
+}
+
+func configMapObject(configMap *corev1.ConfigMap) trustStoreObject {
+	data := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+	for key, value := range configMap.Data {
+		data[key] = []byte(value)
+	}
+	for key, value := range configMap.BinaryData {
+		data[key] = value
+	}
+	return trustStoreObject{
+		name: "configmap/" + configMap.Name,
+		data: data,
+	}
+}
+
+// parseCertificates parses the certificates from either PEM or DER data.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		// data may be in DER format
+		return x509.ParseCertificates(data)
+	}
+	var certs []*x509.Certificate
+	for block != nil {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+		block, rest = pem.Decode(rest)
+	}
+	return certs, nil
+}
+
+// isRootCACertificate returns nil if cert is a root CA certificate
+func isRootCACertificate(cert *x509.Certificate) error {
+	if err := cert.CheckSignatureFrom(cert); err != nil {
+		return fmt.Errorf("certificate with subject %q is not a root CA certificate: %w", cert.Subject, err)
+	}
+	if string(cert.RawSubject) != string(cert.RawIssuer) {
+		return fmt.Errorf("certificate with subject %q is not a root CA certificate: issuer (%s) and subject (%s) are not the same", cert.Subject, cert.Issuer, cert.Subject)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+	"github.com/notaryproject/notation-go/verifier/truststore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testNamespace = "notation"
+
+func encodePEM(certs ...*x509.Certificate) []byte {
+	var data []byte
+	for _, cert := range certs {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return data
+}
+
+func trustStoreLabels(storeType truststore.Type, namedStore string) map[string]string {
+	return map[string]string{
+		LabelTrustStoreType: string(storeType),
+		LabelTrustStore:     namedStore,
+	}
+}
+
+func TestTrustStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rsaRoot := testhelper.GetRSARootCertificate().Cert
+	ecRoot := testhelper.GetECRootCertificate().Cert
+	leaf := testhelper.GetRSALeafCertificate().Cert
+	client := fake.NewClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "acme-ca", Namespace: testNamespace, Labels: trustStoreLabels(truststore.TypeCA, "acme")},
+			Data: map[string]string{
+				"root.crt":  string(encodePEM(rsaRoot)),
+				"README.md": "not a certificate",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "acme-ca", Namespace: testNamespace, Labels: trustStoreLabels(truststore.TypeCA, "acme")},
+			Data: map[string][]byte{
+				"root.cer": ecRoot.Raw,
+				"tls.key":  []byte("not a certificate"),
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "default", Labels: trustStoreLabels(truststore.TypeCA, "acme")},
+			Data:       map[string]string{"root.crt": string(encodePEM(leaf))},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: testNamespace},
+			Data:       map[string]string{"root.crt": string(encodePEM(leaf))},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "tsa", Namespace: testNamespace, Labels: trustStoreLabels(truststore.TypeTSA, "acme")},
+			Data:       map[string]string{"leaf.pem": string(encodePEM(leaf))},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: testNamespace, Labels: trustStoreLabels(truststore.TypeSigningAuthority, "empty")},
+			Data:       map[string]string{"README.md": "no certificates"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: testNamespace, Labels: trustStoreLabels(truststore.TypeSigningAuthority, "invalid")},
+			Data:       map[string]string{"root.crt": "invalid"},
+		},
+	)
+	store, err := NewTrustStore(ctx, client, Options{Namespace: testNamespace})
+	if err != nil {
+		t.Fatalf("NewTrustStore() error = %v", err)
+	}
+
+	certs, err := store.GetCertificates(ctx, truststore.TypeCA, "acme")
+	if err != nil {
+		t.Fatalf("GetCertificates() error = %v", err)
+	}
+	// configmaps are ordered before secrets
+	if len(certs) != 2 || !certs[0].Equal(rsaRoot) || !certs[1].Equal(ecRoot) {
+		t.Fatalf("GetCertificates() = %d certificates, want the roots of the configmap and the secret", len(certs))
+	}
+
+	// TSA certificates must be root CA certificates
+	var certErr truststore.CertificateError
+	if _, err := store.GetCertificates(ctx, truststore.TypeTSA, "acme"); !errors.As(err, &certErr) {
+		t.Fatalf("GetCertificates() error = %v, want CertificateError", err)
+	}
+	if _, err := store.GetCertificates(ctx, truststore.TypeSigningAuthority, "empty"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("GetCertificates() error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := store.GetCertificates(ctx, truststore.TypeSigningAuthority, "invalid"); !errors.As(err, &certErr) {
+		t.Fatalf("GetCertificates() error = %v, want CertificateError", err)
+	}
+	var storeErr truststore.TrustStoreError
+	for _, tt := range []struct {
+		storeType  truststore.Type
+		namedStore string
+	}{
+		{truststore.TypeCA, "missing"},
+		{"unknown", "acme"},
+		{truststore.TypeCA, "invalid/name"},
+	} {
+		if _, err := store.GetCertificates(ctx, tt.storeType, tt.namedStore); !errors.As(err, &storeErr) {
+			t.Fatalf("GetCertificates(%q, %q) error = %v, want TrustStoreError", tt.storeType, tt.namedStore, err)
+		}
+	}
+}
+
+func TestTrustStore_Refresh(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := fake.NewClientset()
+	store, err := NewTrustStore(ctx, client, Options{Namespace: testNamespace, DisableSecrets: true})
+	if err != nil {
+		t.Fatalf("NewTrustStore() error = %v", err)
+	}
+	if _, err := store.GetCertificates(ctx, truststore.TypeCA, "acme"); err == nil {
+		t.Fatal("GetCertificates() expects error before the configmap is created")
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "acme-ca", Namespace: testNamespace, Labels: trustStoreLabels(truststore.TypeCA, "acme")},
+		Data:       map[string]string{"root.crt": string(encodePEM(testhelper.GetRSARootCertificate().Cert))},
+	}
+	if _, err := client.CoreV1().ConfigMaps(testNamespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			certs, _ := store.GetCertificates(ctx, truststore.TypeCA, "acme")
+			if len(certs) == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("GetCertificates() = %d certificates, want %d", len(certs), want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor(1)
+
+	configMap.Data["root-2.crt"] = string(encodePEM(testhelper.GetECRootCertificate().Cert))
+	if _, err := client.CoreV1().ConfigMaps(testNamespace).Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(2)
+
+	if err := client.CoreV1().ConfigMaps(testNamespace).Delete(ctx, configMap.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(0)
+}
+
+func TestNewTrustStore_Error(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset()
+	if _, err := NewTrustStore(ctx, nil, Options{Namespace: testNamespace}); err == nil {
+		t.Fatal("NewTrustStore() expects error for nil client")
+	}
+	if _, err := NewTrustStore(ctx, client, Options{}); err == nil {
+		t.Fatal("NewTrustStore() expects error for empty namespace")
+	}
+	if _, err := NewTrustStore(ctx, client, Options{Namespace: testNamespace, DisableSecrets: true, DisableConfigMaps: true}); err == nil {
+		t.Fatal("NewTrustStore() expects error when all the objects are disabled")
+	}
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := NewTrustStore(cancelledCtx, client, Options{Namespace: testNamespace}); err == nil {
+		t.Fatal("NewTrustStore() expects error for cancelled context")
+	}
+}
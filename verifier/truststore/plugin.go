@@ -0,0 +1,41 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truststore
+
+import (
+	"strings"
+
+	"github.com/notaryproject/notation-go/internal/file"
+)
+
+// PluginStorePrefix is the prefix of the names of the named trust stores
+// provided by plugins, "plugin:<plugin-name>:<store-name>", e.g. the trust
+// store "ca:plugin:com.example.pki:corp-roots" of a trust policy.
+const PluginStorePrefix = "plugin:"
+
+// ParsePluginStoreName parses the name of a named trust store provided by a
+// plugin, "plugin:<plugin-name>:<store-name>", and returns the plugin name
+// and the store name in the plugin. It returns false if namedStore is not the
+// name of a trust store provided by a plugin, or if it is malformed.
+func ParsePluginStoreName(namedStore string) (pluginName, storeName string, ok bool) {
+	name, found := strings.CutPrefix(namedStore, PluginStorePrefix)
+	if !found {
+		return "", "", false
+	}
+	pluginName, storeName, found = strings.Cut(name, ":")
+	if !found || !file.IsValidFileName(pluginName) || !file.IsValidFileName(storeName) {
+		return "", "", false
+	}
+	return pluginName, storeName, true
+}
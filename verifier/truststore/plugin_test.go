@@ -0,0 +1,28 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truststore
+
+import "testing"
+
+func TestParsePluginStoreName(t *testing.T) {
+	pluginName, storeName, ok := ParsePluginStoreName("plugin:com.example.pki:corp-roots")
+	if !ok || pluginName != "com.example.pki" || storeName != "corp-roots" {
+		t.Fatalf("ParsePluginStoreName() = %q, %q, %v", pluginName, storeName, ok)
+	}
+	for _, name := range []string{"corp-roots", "plugin:", "plugin:com.example.pki", "plugin::corp-roots", "plugin:com.example.pki:", "plugin:com.example.pki:a:b"} {
+		if _, _, ok := ParsePluginStoreName(name); ok {
+			t.Fatalf("ParsePluginStoreName(%q) expects false", name)
+		}
+	}
+}
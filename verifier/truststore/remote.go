@@ -0,0 +1,296 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truststore
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/file"
+	"github.com/notaryproject/notation-go/log"
+)
+
+const (
+	// maxRemoteTrustStoreSize is the maximum size of a named trust store
+	// fetched from a remote trust store.
+	maxRemoteTrustStoreSize = 4 * 1024 * 1024
+
+	// defaultRemoteRefreshInterval is the default interval after which the
+	// named trust stores are fetched again.
+	defaultRemoteRefreshInterval = 10 * time.Minute
+
+	// maxRemoteRedirects is the maximum number of redirects followed when
+	// fetching a named trust store.
+	maxRemoteRedirects = 10
+)
+
+// RemoteX509TrustStoreOptions contains parameters for
+// [NewRemoteX509TrustStore].
+type RemoteX509TrustStoreOptions struct {
+	// RootCAs are the pinned CA certificates the TLS certificate of the
+	// endpoint must chain to. If empty, the system roots are used and
+	// SPKIPins must be set.
+	RootCAs []*x509.Certificate
+
+	// SPKIPins are the base64-encoded SHA-256 hashes of the
+	// SubjectPublicKeyInfo of the certificates pinned for the endpoint. The
+	// verified TLS certificate chain of the endpoint must contain at least
+	// one of them.
+	SPKIPins []string
+
+	// Timeout bounds each fetch of a named trust store. If set to less than
+	// or equals to zero, the fetches are only bounded by the context.
+	Timeout time.Duration
+
+	// RefreshInterval is the interval after which a fetched named trust
+	// store is fetched again. If set to less than or equals to zero, ten
+	// minutes is used.
+	RefreshInterval time.Duration
+
+	// MaxStaleness is the maximum age of the certificates of a named trust
+	// store returned when it cannot be fetched again, e.g. the endpoint is
+	// unavailable. If set to less than or equals to RefreshInterval, the
+	// certificates are not returned once they are due for refresh.
+	MaxStaleness time.Duration
+
+	// Time returns the current time. If nil, time.Now is used.
+	Time func() time.Time
+}
+
+// RemoteX509TrustStore is an [X509TrustStore] fetching the named trust
+// stores from an HTTPS endpoint, so that a fleet of verifiers gets its trust
+// roots from a central place without configuration management.
+//
+// The named trust store storeType/namedStore is fetched with a GET request
+// to <endpoint>/<storeType>/<namedStore>, which returns the PEM or DER
+// encoded certificates of the store. The TLS certificate of the endpoint is
+// pinned with [RemoteX509TrustStoreOptions].RootCAs or
+// [RemoteX509TrustStoreOptions].SPKIPins.
+//
+// It is safe for concurrent use.
+type RemoteX509TrustStore struct {
+	endpoint        *url.URL
+	client          *http.Client
+	timeout         time.Duration
+	refreshInterval time.Duration
+	maxStaleness    time.Duration
+	now             func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]*remoteEntry
+}
+
+// remoteEntry is a named trust store fetched from the endpoint.
+type remoteEntry struct {
+	certs     []*x509.Certificate
+	fetchedAt time.Time
+}
+
+// NewRemoteX509TrustStore returns a [RemoteX509TrustStore] fetching the named
+// trust stores from the HTTPS endpoint, e.g.
+// "https://trust.example.com/notation".
+func NewRemoteX509TrustStore(endpoint string, opts RemoteX509TrustStoreOptions) (*RemoteX509TrustStore, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trust store endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid trust store endpoint %q: an https URL is required", endpoint)
+	}
+	if len(opts.RootCAs) == 0 && len(opts.SPKIPins) == 0 {
+		return nil, errors.New("the trust store endpoint must be pinned with root CAs or SPKI pins")
+	}
+	pins := make([][]byte, 0, len(opts.SPKIPins))
+	for _, pin := range opts.SPKIPins {
+		hash, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("invalid SPKI pin %q: a base64-encoded SHA-256 hash is required", pin)
+		}
+		pins = append(pins, hash)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if len(opts.RootCAs) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		for _, cert := range opts.RootCAs {
+			tlsConfig.RootCAs.AddCert(cert)
+		}
+	}
+	if len(pins) > 0 {
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifySPKIPins(state, pins)
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	store := &RemoteX509TrustStore{
+		endpoint:        u,
+		client:          &http.Client{Transport: transport, CheckRedirect: checkRedirect},
+		timeout:         opts.Timeout,
+		refreshInterval: opts.RefreshInterval,
+		maxStaleness:    opts.MaxStaleness,
+		now:             opts.Time,
+		entries:         make(map[cacheKey]*remoteEntry),
+	}
+	if store.refreshInterval <= 0 {
+		store.refreshInterval = defaultRemoteRefreshInterval
+	}
+	if store.now == nil {
+		store.now = time.Now
+	}
+	return store, nil
+}
+
+// GetCertificates returns certificates under storeType/namedStore
+func (s *RemoteX509TrustStore) GetCertificates(ctx context.Context, storeType Type, namedStore string) ([]*x509.Certificate, error) {
+	if !isValidStoreType(storeType) {
+		return nil, TrustStoreError{Msg: fmt.Sprintf("unsupported trust store type: %s", storeType)}
+	}
+	if !file.IsValidFileName(namedStore) {
+		return nil, TrustStoreError{Msg: fmt.Sprintf("trust store name needs to follow [a-zA-Z0-9_.-]+ format, %s is invalid", namedStore)}
+	}
+	key := cacheKey{storeType: storeType, namedStore: namedStore}
+	// the lock is held during the fetch, so that a named trust store due for
+	// refresh is fetched once
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entries[key]
+	now := s.now()
+	if entry != nil && now.Sub(entry.fetchedAt) < s.refreshInterval {
+		return slices.Clone(entry.certs), nil
+	}
+	certs, err := s.fetch(ctx, storeType, namedStore)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// the endpoint removed the named trust store
+			delete(s.entries, key)
+			return nil, err
+		}
+		if entry != nil && now.Sub(entry.fetchedAt) < s.maxStaleness {
+			log.GetLogger(ctx).Warnf("Failed to refresh the trust store %q of type %q, using the certificates fetched at %s: %v", namedStore, storeType, entry.fetchedAt.Format(time.RFC3339), err)
+			return slices.Clone(entry.certs), nil
+		}
+		return nil, err
+	}
+	s.entries[key] = &remoteEntry{certs: certs, fetchedAt: now}
+	return slices.Clone(certs), nil
+}
+
+// fetch fetches the certificates of the named trust store from the endpoint.
+func (s *RemoteX509TrustStore) fetch(ctx context.Context, storeType Type, namedStore string) ([]*x509.Certificate, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	storeURL := s.endpoint.JoinPath(string(storeType), namedStore)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, storeURL.String(), nil)
+	if err != nil {
+		return nil, TrustStoreError{InnerError: err, Msg: fmt.Sprintf("failed to fetch the trust store %q of type %q", namedStore, storeType)}
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, TrustStoreError{InnerError: err, Msg: fmt.Sprintf("failed to fetch the trust store %q of type %q", namedStore, storeType)}
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, TrustStoreError{InnerError: fs.ErrNotExist, Msg: fmt.Sprintf("the trust store %q of type %q does not exist", namedStore, storeType)}
+	default:
+		return nil, TrustStoreError{Msg: fmt.Sprintf("failed to fetch the trust store %q of type %q: %s %q: unexpected status code %d", namedStore, storeType, resp.Request.Method, storeURL.Redacted(), resp.StatusCode)}
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteTrustStoreSize+1))
+	if err != nil {
+		return nil, TrustStoreError{InnerError: err, Msg: fmt.Sprintf("failed to fetch the trust store %q of type %q", namedStore, storeType)}
+	}
+	if len(data) > maxRemoteTrustStoreSize {
+		return nil, TrustStoreError{Msg: fmt.Sprintf("the trust store %q of type %q exceeds %d bytes", namedStore, storeType, maxRemoteTrustStoreSize)}
+	}
+
+	certs, err := parseCertificates(data)
+	if err != nil {
+		return nil, CertificateError{InnerError: err, Msg: fmt.Sprintf("failed to read the trusted certificates of trust store %s of type %s", namedStore, storeType)}
+	}
+	if len(certs) < 1 {
+		return nil, CertificateError{Msg: fmt.Sprintf("no x509 certificates were found in trust store %q of type %q", namedStore, storeType)}
+	}
+	if err := ValidateCertificatesForType(storeType, certs); err != nil {
+		return nil, CertificateError{InnerError: err, Msg: fmt.Sprintf("failed to validate the trusted certificates of trust store %s of type %s", namedStore, storeType)}
+	}
+	return certs, nil
+}
+
+// checkRedirect rejects the redirects leaving https, so that the named trust
+// stores are never fetched from an unauthenticated endpoint.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to %q is not allowed: an https URL is required", req.URL.Redacted())
+	}
+	if len(via) >= maxRemoteRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRemoteRedirects)
+	}
+	return nil
+}
+
+// verifySPKIPins returns nil if a certificate of the verified chains of the
+// TLS connection matches one of the SPKI pins.
+func verifySPKIPins(state tls.ConnectionState, pins [][]byte) error {
+	for _, chain := range state.VerifiedChains {
+		for _, cert := range chain {
+			hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			for _, pin := range pins {
+				if string(hash[:]) == string(pin) {
+					return nil
+				}
+			}
+		}
+	}
+	return errors.New("the TLS certificate chain of the trust store endpoint does not match the SPKI pins")
+}
+
+// parseCertificates parses the certificates from either PEM or DER data.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		// data may be in DER format
+		return x509.ParseCertificates(data)
+	}
+	var certs []*x509.Certificate
+	for block != nil {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+		block, rest = pem.Decode(rest)
+	}
+	return certs, nil
+}
@@ -0,0 +1,314 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truststore
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-core-go/testhelper"
+)
+
+// remoteTrustStoreServer serves the named trust stores of a remote trust
+// store.
+type remoteTrustStoreServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	stores   map[string][]byte
+	status   int
+	requests int
+}
+
+func newRemoteTrustStoreServer(t *testing.T) *remoteTrustStoreServer {
+	s := &remoteTrustStoreServer{stores: make(map[string][]byte)}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		if s.status != 0 {
+			w.WriteHeader(s.status)
+			return
+		}
+		data, ok := s.stores[strings.TrimPrefix(r.URL.Path, "/notation/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *remoteTrustStoreServer) set(path string, data []byte, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if data != nil {
+		s.stores[path] = data
+	}
+	s.status = status
+}
+
+func (s *remoteTrustStoreServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func encodePEM(certs ...*x509.Certificate) []byte {
+	var data []byte
+	for _, cert := range certs {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return data
+}
+
+func spkiPin(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func TestRemoteX509TrustStore(t *testing.T) {
+	ctx := context.Background()
+	server := newRemoteTrustStoreServer(t)
+	rsaRoot := testhelper.GetRSARootCertificate().Cert
+	ecRoot := testhelper.GetECRootCertificate().Cert
+	server.set("ca/acme", encodePEM(rsaRoot, ecRoot), 0)
+	server.set("signingAuthority/der", rsaRoot.Raw, 0)
+	server.set("tsa/acme", encodePEM(testhelper.GetRSALeafCertificate().Cert), 0)
+	server.set("ca/invalid", []byte("invalid"), 0)
+	server.set("ca/oversized", make([]byte, maxRemoteTrustStoreSize+1), 0)
+
+	store, err := NewRemoteX509TrustStore(server.URL+"/notation", RemoteX509TrustStoreOptions{
+		RootCAs: []*x509.Certificate{server.Certificate()},
+	})
+	if err != nil {
+		t.Fatalf("NewRemoteX509TrustStore() error = %v", err)
+	}
+	certs, err := store.GetCertificates(ctx, TypeCA, "acme")
+	if err != nil {
+		t.Fatalf("GetCertificates() error = %v", err)
+	}
+	if len(certs) != 2 || !certs[0].Equal(rsaRoot) || !certs[1].Equal(ecRoot) {
+		t.Fatalf("GetCertificates() = %d certificates, want the 2 roots", len(certs))
+	}
+	certs, err = store.GetCertificates(ctx, TypeSigningAuthority, "der")
+	if err != nil || len(certs) != 1 {
+		t.Fatalf("GetCertificates() = %d certificates, error = %v", len(certs), err)
+	}
+
+	if _, err := store.GetCertificates(ctx, TypeCA, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("GetCertificates() error = %v, want fs.ErrNotExist", err)
+	}
+	var certErr CertificateError
+	if _, err := store.GetCertificates(ctx, TypeCA, "invalid"); !errors.As(err, &certErr) {
+		t.Fatalf("GetCertificates() error = %v, want CertificateError", err)
+	}
+	if _, err := store.GetCertificates(ctx, TypeTSA, "acme"); !errors.As(err, &certErr) {
+		t.Fatalf("GetCertificates() error = %v, want CertificateError for non-root TSA certificate", err)
+	}
+	var storeErr TrustStoreError
+	if _, err := store.GetCertificates(ctx, TypeCA, "oversized"); !errors.As(err, &storeErr) {
+		t.Fatalf("GetCertificates() error = %v, want TrustStoreError", err)
+	}
+	if _, err := store.GetCertificates(ctx, "unknown", "acme"); !errors.As(err, &storeErr) {
+		t.Fatalf("GetCertificates() error = %v, want TrustStoreError", err)
+	}
+	if _, err := store.GetCertificates(ctx, TypeCA, "../acme"); !errors.As(err, &storeErr) {
+		t.Fatalf("GetCertificates() error = %v, want TrustStoreError", err)
+	}
+}
+
+func TestRemoteX509TrustStore_Pinning(t *testing.T) {
+	ctx := context.Background()
+	server := newRemoteTrustStoreServer(t)
+	server.set("ca/acme", encodePEM(testhelper.GetRSARootCertificate().Cert), 0)
+
+	tests := []struct {
+		name    string
+		opts    RemoteX509TrustStoreOptions
+		wantErr bool
+	}{
+		{
+			name: "spki pin",
+			opts: RemoteX509TrustStoreOptions{
+				RootCAs:  []*x509.Certificate{server.Certificate()},
+				SPKIPins: []string{spkiPin(testhelper.GetRSARootCertificate().Cert), spkiPin(server.Certificate())},
+			},
+		},
+		{
+			name: "spki pin mismatch",
+			opts: RemoteX509TrustStoreOptions{
+				RootCAs:  []*x509.Certificate{server.Certificate()},
+				SPKIPins: []string{spkiPin(testhelper.GetRSARootCertificate().Cert)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "root CA mismatch",
+			opts: RemoteX509TrustStoreOptions{
+				RootCAs: []*x509.Certificate{testhelper.GetRSARootCertificate().Cert},
+			},
+			wantErr: true,
+		},
+		{
+			name: "system roots",
+			opts: RemoteX509TrustStoreOptions{
+				SPKIPins: []string{spkiPin(server.Certificate())},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewRemoteX509TrustStore(server.URL+"/notation", tt.opts)
+			if err != nil {
+				t.Fatalf("NewRemoteX509TrustStore() error = %v", err)
+			}
+			_, err = store.GetCertificates(ctx, TypeCA, "acme")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCertificates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoteX509TrustStore_Refresh(t *testing.T) {
+	ctx := context.Background()
+	server := newRemoteTrustStoreServer(t)
+	server.set("ca/acme", encodePEM(testhelper.GetRSARootCertificate().Cert), 0)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store, err := NewRemoteX509TrustStore(server.URL+"/notation", RemoteX509TrustStoreOptions{
+		RootCAs:         []*x509.Certificate{server.Certificate()},
+		RefreshInterval: time.Minute,
+		MaxStaleness:    time.Hour,
+		Time:            func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("NewRemoteX509TrustStore() error = %v", err)
+	}
+	getCertificates := func(wantRequests int, wantErr bool) {
+		t.Helper()
+		certs, err := store.GetCertificates(ctx, TypeCA, "acme")
+		if wantErr {
+			if err == nil {
+				t.Fatal("GetCertificates() expects error")
+			}
+		} else if err != nil || len(certs) != 1 {
+			t.Fatalf("GetCertificates() = %d certificates, error = %v", len(certs), err)
+		}
+		if got := server.requestCount(); got != wantRequests {
+			t.Fatalf("requests = %d, want %d", got, wantRequests)
+		}
+	}
+	getCertificates(1, false)
+	// cached
+	now = now.Add(30 * time.Second)
+	getCertificates(1, false)
+	// refreshed
+	now = now.Add(time.Minute)
+	getCertificates(2, false)
+
+	// stale certificates are returned while the endpoint is unavailable
+	server.set("", nil, http.StatusServiceUnavailable)
+	now = now.Add(30 * time.Minute)
+	getCertificates(3, false)
+	now = now.Add(time.Hour)
+	getCertificates(4, true)
+
+	// the named trust store is removed
+	server.set("", nil, 0)
+	getCertificates(5, false)
+	server.set("", nil, http.StatusNotFound)
+	now = now.Add(2 * time.Minute)
+	getCertificates(6, true)
+	server.set("", nil, http.StatusServiceUnavailable)
+	getCertificates(7, true)
+}
+
+func TestRemoteX509TrustStore_Redirect(t *testing.T) {
+	ctx := context.Background()
+	rootCert := testhelper.GetRSARootCertificate().Cert
+	server := newRemoteTrustStoreServer(t)
+	server.set("ca/acme", encodePEM(rootCert), 0)
+
+	var plainRequests atomic.Int32
+	plainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plainRequests.Add(1)
+		w.Write(encodePEM(rootCert))
+	}))
+	defer plainServer.Close()
+	redirectServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/notation/ca/https":
+			http.Redirect(w, r, server.URL+"/notation/ca/acme", http.StatusFound)
+		default:
+			http.Redirect(w, r, plainServer.URL+r.URL.Path, http.StatusFound)
+		}
+	}))
+	defer redirectServer.Close()
+
+	store, err := NewRemoteX509TrustStore(redirectServer.URL+"/notation", RemoteX509TrustStoreOptions{
+		RootCAs: []*x509.Certificate{redirectServer.Certificate(), server.Certificate()},
+	})
+	if err != nil {
+		t.Fatalf("NewRemoteX509TrustStore() error = %v", err)
+	}
+	if certs, err := store.GetCertificates(ctx, TypeCA, "https"); err != nil || len(certs) != 1 {
+		t.Fatalf("GetCertificates() with https redirect = %d certificates, error = %v", len(certs), err)
+	}
+	var storeErr TrustStoreError
+	if _, err := store.GetCertificates(ctx, TypeCA, "http"); !errors.As(err, &storeErr) {
+		t.Fatalf("GetCertificates() with http redirect error = %v, want TrustStoreError", err)
+	}
+	if n := plainRequests.Load(); n != 0 {
+		t.Fatalf("http endpoint requested %d times, want 0", n)
+	}
+}
+
+func TestNewRemoteX509TrustStore_Error(t *testing.T) {
+	rootCAs := []*x509.Certificate{testhelper.GetRSARootCertificate().Cert}
+	tests := []struct {
+		name     string
+		endpoint string
+		opts     RemoteX509TrustStoreOptions
+	}{
+		{name: "http endpoint", endpoint: "http://trust.example.com", opts: RemoteX509TrustStoreOptions{RootCAs: rootCAs}},
+		{name: "invalid endpoint", endpoint: "https://trust.example.com/%zz", opts: RemoteX509TrustStoreOptions{RootCAs: rootCAs}},
+		{name: "missing host", endpoint: "https:///notation", opts: RemoteX509TrustStoreOptions{RootCAs: rootCAs}},
+		{name: "not pinned", endpoint: "https://trust.example.com"},
+		{name: "invalid spki pin", endpoint: "https://trust.example.com", opts: RemoteX509TrustStoreOptions{SPKIPins: []string{"invalid"}}},
+		{name: "short spki pin", endpoint: "https://trust.example.com", opts: RemoteX509TrustStoreOptions{SPKIPins: []string{base64.StdEncoding.EncodeToString([]byte("short"))}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRemoteX509TrustStore(tt.endpoint, tt.opts); err == nil {
+				t.Fatal("NewRemoteX509TrustStore() expects error")
+			}
+		})
+	}
+}
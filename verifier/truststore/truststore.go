@@ -141,6 +141,23 @@ func ValidateCertificates(certs []*x509.Certificate) error {
 	return nil
 }
 
+// ValidateCertificatesForType ensures certificates from a trust store of type
+// storeType are CA certificates or self-signed, and root CA certificates for
+// TSA trust stores.
+func ValidateCertificatesForType(storeType Type, certs []*x509.Certificate) error {
+	if err := ValidateCertificates(certs); err != nil {
+		return err
+	}
+	if storeType == TypeTSA {
+		for _, cert := range certs {
+			if err := isRootCACertificate(cert); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // isValidStoreType checks if storeType is supported
 func isValidStoreType(storeType Type) bool {
 	return slices.Contains(Types, storeType)
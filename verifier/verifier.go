@@ -18,10 +18,12 @@ package verifier
 import (
 	"context"
 	"crypto"
+	"crypto/sha256"
 	"crypto/x509"
-	"encoding/json"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"reflect"
 	"strings"
@@ -31,11 +33,15 @@ import (
 	"oras.land/oras-go/v2/content"
 
 	"github.com/notaryproject/notation-core-go/revocation"
+	corecrl "github.com/notaryproject/notation-core-go/revocation/crl"
 	"github.com/notaryproject/notation-core-go/revocation/purpose"
 	revocationresult "github.com/notaryproject/notation-core-go/revocation/result"
 	"github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/cose"
 	nx509 "github.com/notaryproject/notation-core-go/x509"
 	"github.com/notaryproject/notation-go"
+	"github.com/notaryproject/notation-go/audit"
+	"github.com/notaryproject/notation-go/clock"
 	"github.com/notaryproject/notation-go/dir"
 	"github.com/notaryproject/notation-go/internal/envelope"
 	"github.com/notaryproject/notation-go/internal/pkix"
@@ -43,7 +49,10 @@ import (
 	"github.com/notaryproject/notation-go/internal/slices"
 	trustpolicyInternal "github.com/notaryproject/notation-go/internal/trustpolicy"
 	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/network"
 	"github.com/notaryproject/notation-go/plugin"
+	"github.com/notaryproject/notation-go/plugin/proto"
+	"github.com/notaryproject/notation-go/trace"
 	"github.com/notaryproject/notation-go/verifier/trustpolicy"
 	"github.com/notaryproject/notation-go/verifier/truststore"
 	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
@@ -58,16 +67,38 @@ var algorithms = map[crypto.Hash]digest.Algorithm{
 	crypto.SHA512: digest.SHA512,
 }
 
+// isStrongerDigestAlgorithm reports whether the digest algorithm algo is
+// supported and stronger than the digest algorithm keyAlgo derived from the
+// signing key.
+func isStrongerDigestAlgorithm(algo, keyAlgo digest.Algorithm) bool {
+	for _, supported := range algorithms {
+		if algo == supported {
+			return algo.Size() > keyAlgo.Size()
+		}
+	}
+	return false
+}
+
 // verifier implements [notation.Verifier], [notation.BlobVerifier] and
 // notation.verifySkipper interfaces.
 type verifier struct {
 	ociTrustPolicyDoc               *trustpolicy.OCIDocument
+	tenantOCITrustPolicyDocs        map[string]*trustpolicy.OCIDocument
 	blobTrustPolicyDoc              *trustpolicy.BlobDocument
 	trustStore                      truststore.X509TrustStore
 	pluginManager                   plugin.Manager
 	revocationClient                revocation.Revocation
 	revocationCodeSigningValidator  revocation.Validator
 	revocationTimestampingValidator revocation.Validator
+	missingPluginAction             MissingPluginAction
+	pluginInstaller                 PluginInstaller
+	verificationCache               VerificationCache
+	revocationTimeout               time.Duration
+	limits                          envelopeLimits
+	policyHook                      PolicyHook
+	shadowMode                      bool
+	identityResolver                string
+	clock                           clock.Clock
 }
 
 // VerifierOptions specifies additional parameters that can be set when using
@@ -92,11 +123,93 @@ type VerifierOptions struct {
 	// OCITrustpolicy is the trust policy document for OCI artifacts.
 	OCITrustPolicy *trustpolicy.OCIDocument
 
+	// OCITrustPolicyV2 is the version 2.0 trust policy document for OCI
+	// artifacts. It is converted with [trustpolicy.OCIDocumentV2.ToOCIDocument]
+	// and cannot be set with OCITrustPolicy.
+	OCITrustPolicyV2 *trustpolicy.OCIDocumentV2
+
+	// TenantOCITrustPolicies maps the tenant IDs to their trust policy
+	// documents for OCI artifacts. The document of a tenant is selected with
+	// [WithTenant], instead of OCITrustPolicy, so that a single verifier
+	// serves several tenants.
+	TenantOCITrustPolicies map[string]*trustpolicy.OCIDocument
+
 	// BlobTrustPolicy is the trust policy document for Blob artifacts.
 	BlobTrustPolicy *trustpolicy.BlobDocument
 
 	// PluginManager manages plugins installed on the system.
 	PluginManager plugin.Manager
+
+	// MissingPluginAction is the action taken when a signature mandates a
+	// verification plugin that is not installed. If empty,
+	// [MissingPluginFail] is used.
+	MissingPluginAction MissingPluginAction
+
+	// PluginInstaller installs the missing verification plugins. It is
+	// required if MissingPluginAction is [MissingPluginInstall].
+	PluginInstaller PluginInstaller
+
+	// VerificationCache caches the outcomes of successful OCI signature
+	// verifications. If nil, the outcomes are not cached.
+	VerificationCache VerificationCache
+
+	// RevocationTimeout bounds each revocation check of the code signing and
+	// the timestamping certificate chains, on top of the deadline of the
+	// context passed to the verifier. If set to less than or equals to zero,
+	// the revocation checks are only bounded by the context and the timeouts
+	// of the revocation validators.
+	RevocationTimeout time.Duration
+
+	// MaxEnvelopeSize is the maximum size in bytes of a signature envelope.
+	// Larger envelopes are rejected before being parsed. If set to less than
+	// or equals to zero, [DefaultMaxEnvelopeSize] is used.
+	MaxEnvelopeSize int64
+
+	// MaxPayloadSize is the maximum size in bytes of the payload of a
+	// signature envelope. If set to less than or equals to zero,
+	// [DefaultMaxPayloadSize] is used.
+	MaxPayloadSize int64
+
+	// MaxCertificateChainLength is the maximum number of certificates in the
+	// certificate chain of a signature envelope. If set to less than or
+	// equals to zero, [DefaultMaxCertificateChainLength] is used.
+	MaxCertificateChainLength int
+
+	// PolicyHook, if set, is evaluated after each OCI signature is verified
+	// against the trust policy, and fails the verification if it denies the
+	// signature or cannot be evaluated. It is evaluated on the cached
+	// outcomes too. See [NewRegoPolicyHook] for a Rego policy evaluated by
+	// an Open Policy Agent server.
+	PolicyHook PolicyHook
+
+	// ShadowMode, if true, reports the verification failures as warnings
+	// instead of failing the verifications, so that the verification can be
+	// deployed before being enforced. The failure is preserved in the
+	// ShadowedError of the outcome, with the detail of the outcome. The
+	// audit events report the failures. The failures found by
+	// [notation.Verify] without the verifier, e.g. an artifact without
+	// signatures, are still returned.
+	ShadowMode bool
+
+	// IdentityResolver is the name of the plugin with the IDENTITY_RESOLVER
+	// capability resolving the signing certificates to organizational
+	// identities, e.g. team names looked up in LDAP or an identity provider,
+	// so that trust policies trust "org.identity:<name>" identities instead
+	// of certificate subjects. The resolved identities are recorded in the
+	// ResolvedIdentities of the outcomes. It requires PluginManager.
+	IdentityResolver string
+
+	// Clock reads the time of verification, against which the expiry of the
+	// signatures and the validity periods of the certificate chains are
+	// checked. If nil, the system time is used.
+	Clock clock.Clock
+
+	// Network configures the name resolution, the dialing and the proxy of
+	// the OCSP and CRL clients of the default revocation validators, with
+	// the proxy of network.ComponentRevocation, if any. It has no
+	// effect on the validators provided by RevocationCodeSigningValidator,
+	// RevocationTimestampingValidator and RevocationClient.
+	Network *network.Config
 }
 
 // NewOCIVerifierFromConfig returns an OCI verifier based on local file system
@@ -150,7 +263,16 @@ func NewVerifierWithOptions(trustStore truststore.X509TrustStore, verifierOption
 	if trustStore == nil {
 		return nil, errors.New("trustStore cannot be nil")
 	}
-	if ociTrustPolicy == nil && blobTrustPolicy == nil {
+	if verifierOptions.OCITrustPolicyV2 != nil {
+		if ociTrustPolicy != nil {
+			return nil, errors.New("ociTrustPolicy and ociTrustPolicyV2 cannot both be set")
+		}
+		if err := verifierOptions.OCITrustPolicyV2.Validate(); err != nil {
+			return nil, err
+		}
+		ociTrustPolicy = verifierOptions.OCITrustPolicyV2.ToOCIDocument()
+	}
+	if ociTrustPolicy == nil && blobTrustPolicy == nil && len(verifierOptions.TenantOCITrustPolicies) == 0 {
 		return nil, errors.New("ociTrustPolicy and blobTrustPolicy both cannot be nil")
 	}
 	if ociTrustPolicy != nil {
@@ -163,11 +285,30 @@ func NewVerifierWithOptions(trustStore truststore.X509TrustStore, verifierOption
 			return nil, err
 		}
 	}
+	if err := validateTenantTrustPolicies(verifierOptions.TenantOCITrustPolicies); err != nil {
+		return nil, err
+	}
+	if err := validateMissingPluginAction(verifierOptions); err != nil {
+		return nil, err
+	}
+	if verifierOptions.IdentityResolver != "" && verifierOptions.PluginManager == nil {
+		return nil, errors.New("identityResolver requires a pluginManager")
+	}
 	v := &verifier{
-		ociTrustPolicyDoc:  ociTrustPolicy,
-		blobTrustPolicyDoc: blobTrustPolicy,
-		trustStore:         trustStore,
-		pluginManager:      verifierOptions.PluginManager,
+		ociTrustPolicyDoc:        ociTrustPolicy,
+		tenantOCITrustPolicyDocs: maps.Clone(verifierOptions.TenantOCITrustPolicies),
+		blobTrustPolicyDoc:       blobTrustPolicy,
+		trustStore:               trustStore,
+		pluginManager:            verifierOptions.PluginManager,
+		missingPluginAction:      verifierOptions.MissingPluginAction,
+		pluginInstaller:          verifierOptions.PluginInstaller,
+		verificationCache:        verifierOptions.VerificationCache,
+		revocationTimeout:        verifierOptions.RevocationTimeout,
+		limits:                   newEnvelopeLimits(verifierOptions),
+		policyHook:               verifierOptions.PolicyHook,
+		shadowMode:               verifierOptions.ShadowMode,
+		identityResolver:         verifierOptions.IdentityResolver,
+		clock:                    verifierOptions.Clock,
 	}
 
 	if err := v.setRevocation(verifierOptions); err != nil {
@@ -202,10 +343,7 @@ func (v *verifier) setRevocation(verifierOptions VerifierOptions) error {
 	revocationTimestampingValidator := verifierOptions.RevocationTimestampingValidator
 	var err error
 	if revocationTimestampingValidator == nil {
-		revocationTimestampingValidator, err = revocation.NewWithOptions(revocation.Options{
-			OCSPHTTPClient:   &http.Client{Timeout: 2 * time.Second},
-			CertChainPurpose: purpose.Timestamping,
-		})
+		revocationTimestampingValidator, err = newRevocationValidator(verifierOptions.Network, purpose.Timestamping)
 		if err != nil {
 			return err
 		}
@@ -225,10 +363,7 @@ func (v *verifier) setRevocation(verifierOptions VerifierOptions) error {
 	}
 
 	// both RevocationCodeSigningValidator and RevocationClient are nil
-	revocationCodeSigningValidator, err = revocation.NewWithOptions(revocation.Options{
-		OCSPHTTPClient:   &http.Client{Timeout: 2 * time.Second},
-		CertChainPurpose: purpose.CodeSigning,
-	})
+	revocationCodeSigningValidator, err = newRevocationValidator(verifierOptions.Network, purpose.CodeSigning)
 	if err != nil {
 		return err
 	}
@@ -236,16 +371,72 @@ func (v *verifier) setRevocation(verifierOptions VerifierOptions) error {
 	return nil
 }
 
+// newRevocationValidator returns a revocation validator for
+// certChainPurpose. Its OCSP and CRL clients dial with netConfig, if any.
+func newRevocationValidator(netConfig *network.Config, certChainPurpose purpose.Purpose) (revocation.Validator, error) {
+	ocspHTTPClient, fetcher, err := newRevocationClients(netConfig)
+	if err != nil {
+		return nil, err
+	}
+	return revocation.NewWithOptions(revocation.Options{
+		OCSPHTTPClient:   ocspHTTPClient,
+		CRLFetcher:       fetcher,
+		CertChainPurpose: certChainPurpose,
+	})
+}
+
+// newRevocationClients returns the OCSP HTTP client and the CRL fetcher
+// dialing with netConfig for network.ComponentRevocation, with the timeouts
+// of the default revocation validators.
+func newRevocationClients(netConfig *network.Config) (*http.Client, *corecrl.HTTPFetcher, error) {
+	netConfig = netConfig.ForComponent(network.ComponentRevocation)
+	ocspHTTPClient, err := netConfig.HTTPClient(2 * time.Second)
+	if err != nil {
+		return nil, nil, err
+	}
+	crlHTTPClient, err := netConfig.HTTPClient(5 * time.Second)
+	if err != nil {
+		return nil, nil, err
+	}
+	fetcher, err := corecrl.NewHTTPFetcher(crlHTTPClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ocspHTTPClient, fetcher, nil
+}
+
 // SkipVerify validates whether the verification level is skip.
+//
+// If opts.ArtifactType is empty and trust policy statements restricted to
+// artifact types apply to the repository of the artifact, the verification
+// is not skipped, as the applicable statement depends on the artifact type.
 func (v *verifier) SkipVerify(ctx context.Context, opts notation.VerifierVerifyOptions) (bool, *trustpolicy.VerificationLevel, error) {
+	skip, verificationLevel, err := v.skipVerify(ctx, opts)
+	if err != nil && v.shadowMode {
+		// the error is reported as a warning by Verify
+		return false, nil, nil
+	}
+	return skip, verificationLevel, err
+}
+
+func (v *verifier) skipVerify(ctx context.Context, opts notation.VerifierVerifyOptions) (bool, *trustpolicy.VerificationLevel, error) {
 	logger := log.GetLogger(ctx)
 
 	logger.Debugf("Check verification level against artifact %v", opts.ArtifactReference)
-	trustPolicy, err := v.ociTrustPolicyDoc.GetApplicableTrustPolicy(opts.ArtifactReference)
+	policyDoc, err := v.ociTrustPolicyDocument(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+	if opts.ArtifactType == "" && policyDoc.ScopedByArtifactType(opts.ArtifactReference) {
+		logger.Debug("The applicable trust policy depends on the artifact type, verification is not skipped")
+		return false, nil, nil
+	}
+	trustPolicy, err := policyDoc.GetApplicableTrustPolicyForArtifactType(opts.ArtifactReference, opts.ArtifactType)
 	if err != nil {
 		return false, nil, notation.ErrorNoApplicableTrustPolicy{Msg: err.Error()}
 	}
 	logger.Infof("Trust policy configuration: %+v", trustPolicy)
+	trace.PolicyMatched(ctx, trustPolicy.Name)
 
 	// ignore the error since we already validated the policy document
 	verificationLevel, _ := trustPolicy.SignatureVerification.GetVerificationLevel()
@@ -258,9 +449,48 @@ func (v *verifier) SkipVerify(ctx context.Context, opts notation.VerifierVerifyO
 	return false, verificationLevel, nil
 }
 
+// TrustedThumbprints returns the hex-encoded SHA-256 thumbprints of the
+// certificates in the CA and signing authority trust stores of the trust
+// policy applicable to the artifact.
+func (v *verifier) TrustedThumbprints(ctx context.Context, opts notation.VerifierVerifyOptions) ([]string, error) {
+	policyDoc, err := v.ociTrustPolicyDocument(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trustPolicy, err := policyDoc.GetApplicableTrustPolicyForArtifactType(opts.ArtifactReference, opts.ArtifactType)
+	if err != nil {
+		return nil, notation.ErrorNoApplicableTrustPolicy{Msg: err.Error()}
+	}
+	x509TrustStore, err := v.callTrustStore(opts)
+	if err != nil {
+		return nil, err
+	}
+	var thumbprints []string
+	for _, storeType := range []truststore.Type{truststore.TypeCA, truststore.TypeSigningAuthority} {
+		certs, err := loadX509TrustStoresWithType(ctx, storeType, trustPolicy.Name, trustPolicy.TrustStores, x509TrustStore)
+		if err != nil {
+			return nil, err
+		}
+		for _, cert := range certs {
+			checkSum := sha256.Sum256(cert.Raw)
+			thumbprints = append(thumbprints, hex.EncodeToString(checkSum[:]))
+		}
+	}
+	return thumbprints, nil
+}
+
 // VerifyBlob verifies the signature of given blob, and returns the outcome upon
 // successful verification.
 func (v *verifier) VerifyBlob(ctx context.Context, descGenFunc notation.BlobDescriptorGenerator, signature []byte, opts notation.BlobVerifierVerifyOptions) (*notation.VerificationOutcome, error) {
+	outcome, err := v.verifyBlob(ctx, descGenFunc, signature, opts)
+	auditVerification(ctx, "", outcome, err)
+	if err != nil && v.shadowMode {
+		return shadowOutcome(ctx, outcome, err), nil
+	}
+	return outcome, err
+}
+
+func (v *verifier) verifyBlob(ctx context.Context, descGenFunc notation.BlobDescriptorGenerator, signature []byte, opts notation.BlobVerifierVerifyOptions) (*notation.VerificationOutcome, error) {
 	logger := log.GetLogger(ctx)
 	logger.Debugf("Verify signature of media type %v", opts.SignatureMediaType)
 	if v.blobTrustPolicyDoc == nil {
@@ -278,6 +508,7 @@ func (v *verifier) VerifyBlob(ctx context.Context, descGenFunc notation.BlobDesc
 		return nil, notation.ErrorNoApplicableTrustPolicy{Msg: err.Error()}
 	}
 	logger.Infof("Trust policy configuration: %+v", trustPolicy)
+	trace.PolicyMatched(ctx, trustPolicy.Name)
 
 	// ignore the error since we already validated the policy document
 	verificationLevel, _ := trustPolicy.SignatureVerification.GetVerificationLevel()
@@ -290,19 +521,26 @@ func (v *verifier) VerifyBlob(ctx context.Context, descGenFunc notation.BlobDesc
 		logger.Debug("Skipping signature verification")
 		return outcome, nil
 	}
-	err = v.processSignature(ctx, signature, opts.SignatureMediaType, trustPolicy.Name, trustPolicy.TrustedIdentities, trustPolicy.TrustStores, trustPolicy.SignatureVerification, opts.PluginConfig, outcome)
+	if err := applyActionOverrides(logger, outcome, opts.ActionOverrides); err != nil {
+		return nil, err
+	}
+	err = v.processSignature(ctx, signature, opts.SignatureMediaType, trustPolicy.Name, trustPolicy.TrustedIdentities, nil, trustPolicy.TrustStores, v.withPluginTrustStores(v.trustStore, opts.PluginConfig), trustPolicy.SignatureVerification, opts.PluginConfig, nil, outcome)
 	if err != nil {
 		outcome.Error = err
 		return outcome, err
 	}
+	if err := verifySignatureAlgorithm(outcome, opts.SignatureAlgorithms); err != nil {
+		outcome.Error = err
+		return outcome, err
+	}
 
-	payload := &envelope.Payload{}
-	err = json.Unmarshal(outcome.EnvelopeContent.Payload.Content, payload)
+	targetArtifact, err := envelope.ParsePayload(&outcome.EnvelopeContent.Payload)
 	if err != nil {
-		logger.Error("Failed to unmarshal the payload content in the signature blob to envelope.Payload")
+		logger.Error("Failed to parse the payload content in the signature blob")
 		outcome.Error = err
 		return outcome, err
 	}
+	payload := &envelope.Payload{TargetArtifact: targetArtifact}
 
 	cryptoHash := outcome.EnvelopeContent.SignerInfo.SignatureAlgorithm.Hash()
 	digestAlgo, ok := algorithms[cryptoHash]
@@ -312,6 +550,12 @@ func (v *verifier) VerifyBlob(ctx context.Context, descGenFunc notation.BlobDesc
 		outcome.Error = err
 		return outcome, err
 	}
+	// the blob may be digested with a stronger algorithm than the hash
+	// algorithm of the signing key, e.g. SHA-512 with a RSA-2048 key
+	if payloadDigestAlgo := payload.TargetArtifact.Digest.Algorithm(); payloadDigestAlgo != digestAlgo && isStrongerDigestAlgorithm(payloadDigestAlgo, digestAlgo) {
+		logger.Debugf("Using the digest algorithm %v of the signed payload", payloadDigestAlgo)
+		digestAlgo = payloadDigestAlgo
+	}
 
 	desc, err := descGenFunc(digestAlgo)
 	if err != nil {
@@ -345,22 +589,36 @@ func (v *verifier) VerifyBlob(ctx context.Context, descGenFunc notation.BlobDesc
 // If nil signature is present and the verification level is not 'skip',
 // an error will be returned.
 func (v *verifier) Verify(ctx context.Context, desc ocispec.Descriptor, signature []byte, opts notation.VerifierVerifyOptions) (*notation.VerificationOutcome, error) {
+	outcome, err := v.verify(ctx, desc, signature, opts)
+	auditVerification(ctx, desc.Digest.String(), outcome, err)
+	if err != nil && v.shadowMode {
+		return shadowOutcome(ctx, outcome, err), nil
+	}
+	return outcome, err
+}
+
+func (v *verifier) verify(ctx context.Context, desc ocispec.Descriptor, signature []byte, opts notation.VerifierVerifyOptions) (*notation.VerificationOutcome, error) {
 	artifactRef := opts.ArtifactReference
 	envelopeMediaType := opts.SignatureMediaType
 	pluginConfig := opts.PluginConfig
 	logger := log.GetLogger(ctx)
 
 	logger.Debugf("Verify signature against artifact %v referenced as %s in signature media type %v", desc.Digest, artifactRef, envelopeMediaType)
-	if v.ociTrustPolicyDoc == nil {
-		return nil, errors.New("ociTrustPolicyDoc is nil")
+	policyDoc, err := v.ociTrustPolicyDocument(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	trustPolicy, err := v.ociTrustPolicyDoc.GetApplicableTrustPolicy(artifactRef)
+	artifactType := opts.ArtifactType
+	if artifactType == "" {
+		artifactType = desc.ArtifactType
+	}
+	trustPolicy, err := policyDoc.GetApplicableTrustPolicyForArtifactType(artifactRef, artifactType)
 	if err != nil {
 		return nil, notation.ErrorNoApplicableTrustPolicy{Msg: err.Error()}
 	}
 
 	logger.Infof("Trust policy configuration: %+v", trustPolicy)
+	trace.PolicyMatched(ctx, trustPolicy.Name)
 	// ignore the error since we already validated the policy document
 	verificationLevel, _ := trustPolicy.SignatureVerification.GetVerificationLevel()
 
@@ -373,20 +631,50 @@ func (v *verifier) Verify(ctx context.Context, desc ocispec.Descriptor, signatur
 		logger.Debug("Skipping signature verification")
 		return outcome, nil
 	}
-	err = v.processSignature(ctx, signature, envelopeMediaType, trustPolicy.Name, trustPolicy.TrustedIdentities, trustPolicy.TrustStores, trustPolicy.SignatureVerification, pluginConfig, outcome)
+	if err := applyActionOverrides(logger, outcome, opts.ActionOverrides); err != nil {
+		return nil, err
+	}
+	x509TrustStore, err := v.callTrustStore(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var cacheKey string
+	// oversized envelopes are not parsed to compute the cache key, they fail
+	// the integrity verification
+	if v.verificationCache != nil && v.limits.checkEnvelope(signature) == nil {
+		cacheKey, err = verificationCacheKey(ctx, desc, signature, trustPolicy, x509TrustStore, v.pluginManager, opts)
+		if err != nil {
+			logger.Debugf("Failed to compute the verification cache key, the verification outcome will not be cached: %v", err)
+		} else if cachedOutcome, ok := v.verificationCache.Get(ctx, cacheKey); ok {
+			logger.Debugf("Verification outcome of artifact %v found in cache", desc.Digest)
+			return v.evaluatePolicyHook(ctx, desc, artifactRef, trustPolicy.Name, cachedOutcome)
+		}
+	}
+
+	artifact := &artifactContext{
+		subject:                      &desc,
+		signatureManifestAnnotations: opts.SignatureManifestAnnotations,
+		progress:                     opts.Progress,
+	}
+	err = v.processSignature(ctx, signature, envelopeMediaType, trustPolicy.Name, trustPolicy.TrustedIdentities, trustPolicy.DeniedIdentities, trustPolicy.TrustStores, x509TrustStore, trustPolicy.SignatureVerification, pluginConfig, artifact, outcome)
 
 	if err != nil {
 		outcome.Error = err
 		return outcome, err
 	}
+	if err := verifySignatureAlgorithm(outcome, opts.SignatureAlgorithms); err != nil {
+		outcome.Error = err
+		return outcome, err
+	}
 
-	payload := &envelope.Payload{}
-	err = json.Unmarshal(outcome.EnvelopeContent.Payload.Content, payload)
+	targetArtifact, err := envelope.ParsePayload(&outcome.EnvelopeContent.Payload)
 	if err != nil {
-		logger.Error("Failed to unmarshal the payload content in the signature blob to envelope.Payload")
+		logger.Error("Failed to parse the payload content in the signature blob")
 		outcome.Error = err
 		return outcome, err
 	}
+	payload := &envelope.Payload{TargetArtifact: targetArtifact}
 
 	if !content.Equal(payload.TargetArtifact, desc) {
 		logger.Infof("Target artifact in signature payload: %+v", payload.TargetArtifact)
@@ -401,15 +689,21 @@ func (v *verifier) Verify(ctx context.Context, desc ocispec.Descriptor, signatur
 		}
 	}
 
-	return outcome, outcome.Error
+	if outcome.Error != nil {
+		return outcome, outcome.Error
+	}
+	if cacheKey != "" {
+		v.verificationCache.Set(ctx, cacheKey, outcome)
+	}
+	return v.evaluatePolicyHook(ctx, desc, artifactRef, trustPolicy.Name, outcome)
 }
 
-func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelopeMediaType, policyName string, trustedIdentities, trustStores []string, signatureVerification trustpolicy.SignatureVerification, pluginConfig map[string]string, outcome *notation.VerificationOutcome) error {
+func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelopeMediaType, policyName string, trustedIdentities, deniedIdentities, trustStores []string, x509TrustStore truststore.X509TrustStore, signatureVerification trustpolicy.SignatureVerification, pluginConfig map[string]string, artifact *artifactContext, outcome *notation.VerificationOutcome) error {
 	logger := log.GetLogger(ctx)
 
 	// verify integrity first. notation will always verify integrity no matter
 	// what the signing scheme is
-	envContent, integrityResult := verifyIntegrity(sigBlob, envelopeMediaType, outcome)
+	envContent, integrityResult := verifyIntegrity(sigBlob, envelopeMediaType, v.limits, outcome)
 	outcome.EnvelopeContent = envContent
 	outcome.VerificationResults = append(outcome.VerificationResults, integrityResult)
 	if integrityResult.Error != nil {
@@ -417,6 +711,13 @@ func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelop
 		return integrityResult.Error
 	}
 
+	// validate the registered custom protected headers of COSE envelopes
+	if envelopeMediaType == cose.MediaTypeEnvelope {
+		if err := validateProtectedHeaders(ctx, envContent); err != nil {
+			return notation.ErrorVerificationFailed{Msg: err.Error()}
+		}
+	}
+
 	// check if we need to verify using a plugin
 	var pluginCapabilities []pluginframework.Capability
 	verificationPluginName, err := getVerificationPlugin(&outcome.EnvelopeContent.SignerInfo)
@@ -430,7 +731,7 @@ func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelop
 		logger.Debugf("Finding verification plugin %q", verificationPluginName)
 		verificationPluginMinVersion, err := getVerificationPluginMinVersion(&outcome.EnvelopeContent.SignerInfo)
 		if err != nil && err != errExtendedAttributeNotExist {
-			return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("error while getting plugin minimum version, error: %s", err)}
+			return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("error while getting plugin minimum version, error: %s", err), InnerError: err}
 		}
 
 		if v.pluginManager == nil {
@@ -438,41 +739,46 @@ func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelop
 		}
 		installedPlugin, err = v.pluginManager.Get(ctx, verificationPluginName)
 		if err != nil {
-			return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("error while locating the verification plugin %q, make sure the plugin is installed successfully before verifying the signature. error: %s", verificationPluginName, err)}
+			installedPlugin, err = v.handleMissingPlugin(ctx, verificationPluginName, verificationPluginMinVersion, err, outcome)
+			if err != nil {
+				return err
+			}
 		}
 
-		// filter the "verification" capabilities supported by the installed
-		// plugin
-		metadata, err := installedPlugin.GetMetadata(ctx, &pluginframework.GetMetadataRequest{PluginConfig: pluginConfig})
-		if err != nil {
-			return err
-		}
+		if installedPlugin != nil {
+			// filter the "verification" capabilities supported by the installed
+			// plugin
+			metadata, err := installedPlugin.GetMetadata(ctx, &pluginframework.GetMetadataRequest{PluginConfig: pluginConfig})
+			if err != nil {
+				return err
+			}
 
-		pluginVersion := metadata.Version
+			pluginVersion := metadata.Version
 
-		//checking if the plugin version is in valid semver format
-		if !notationsemver.IsValid(pluginVersion) {
-			return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("plugin %s has pluginVersion %s which is not in valid semver format", verificationPluginName, pluginVersion)}
-		}
+			//checking if the plugin version is in valid semver format
+			if !notationsemver.IsValid(pluginVersion) {
+				return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("plugin %s has pluginVersion %s which is not in valid semver format", verificationPluginName, pluginVersion)}
+			}
 
-		if !isRequiredVerificationPluginVer(pluginVersion, verificationPluginMinVersion) {
-			return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("found plugin %s with version %s but signature verification needs plugin version greater than or equal to %s", verificationPluginName, pluginVersion, verificationPluginMinVersion)}
-		}
+			if !isRequiredVerificationPluginVer(pluginVersion, verificationPluginMinVersion) {
+				return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("found plugin %s with version %s but signature verification needs plugin version greater than or equal to %s", verificationPluginName, pluginVersion, verificationPluginMinVersion)}
+			}
 
-		for _, capability := range metadata.Capabilities {
-			if capability == pluginframework.CapabilityRevocationCheckVerifier || capability == pluginframework.CapabilityTrustedIdentityVerifier {
-				pluginCapabilities = append(pluginCapabilities, capability)
+			for _, capability := range metadata.Capabilities {
+				if capability == pluginframework.CapabilityRevocationCheckVerifier || capability == pluginframework.CapabilityTrustedIdentityVerifier {
+					pluginCapabilities = append(pluginCapabilities, capability)
+				}
 			}
-		}
 
-		if len(pluginCapabilities) == 0 {
-			return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("digital signature requires plugin %q with signature verification capabilities (%q and/or %q) installed", verificationPluginName, pluginframework.CapabilityTrustedIdentityVerifier, pluginframework.CapabilityRevocationCheckVerifier)}
+			if len(pluginCapabilities) == 0 {
+				return notation.ErrorVerificationInconclusive{Msg: fmt.Sprintf("digital signature requires plugin %q with signature verification capabilities (%q and/or %q) installed", verificationPluginName, pluginframework.CapabilityTrustedIdentityVerifier, pluginframework.CapabilityRevocationCheckVerifier)}
+			}
 		}
 	}
 
 	// verify x509 trust store based authenticity
 	logger.Debug("Validating cert chain")
-	trustCerts, err := loadX509TrustStores(ctx, outcome.EnvelopeContent.SignerInfo.SignedAttributes.SigningScheme, policyName, trustStores, v.trustStore)
+	trustCerts, err := loadX509TrustStores(ctx, outcome.EnvelopeContent.SignerInfo.SignedAttributes.SigningScheme, policyName, trustStores, x509TrustStore)
 	var authenticityResult *notation.ValidationResult
 	if err != nil {
 		authenticityResult = &notation.ValidationResult{
@@ -494,7 +800,7 @@ func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelop
 	// to perform this verification rather than a plugin)
 	if !slices.Contains(pluginCapabilities, pluginframework.CapabilityTrustedIdentityVerifier) {
 		logger.Debug("Validating trust identity")
-		err = verifyX509TrustedIdentities(policyName, trustedIdentities, outcome.EnvelopeContent.SignerInfo.CertificateChain)
+		err = v.verifyTrustedIdentities(ctx, policyName, trustedIdentities, pluginConfig, outcome)
 		if err != nil {
 			authenticityResult.Error = err
 			logVerificationResult(logger, authenticityResult)
@@ -504,9 +810,36 @@ func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelop
 		}
 	}
 
+	// reject the denied identities, even if a plugin verifies the trusted
+	// identities
+	if len(deniedIdentities) > 0 {
+		logger.Debug("Validating denied identities")
+		if err := verifyX509DeniedIdentities(policyName, deniedIdentities, outcome.EnvelopeContent.SignerInfo.CertificateChain); err != nil {
+			authenticityResult.Error = err
+			logVerificationResult(logger, authenticityResult)
+		}
+		if isCriticalFailure(authenticityResult) {
+			return authenticityResult.Error
+		}
+	}
+
+	// verify the key requirements of the trust policy
+	if signatureVerification.KeyRequirements != nil {
+		logger.Debug("Validating key requirements")
+		signerInfo := outcome.EnvelopeContent.SignerInfo
+		if err := signatureVerification.KeyRequirements.Check(signerInfo.CertificateChain, signerInfo.SignatureAlgorithm.Hash()); err != nil {
+			authenticityResult.Error = err
+			logVerificationResult(logger, authenticityResult)
+		}
+		if isCriticalFailure(authenticityResult) {
+			return authenticityResult.Error
+		}
+	}
+
 	// verify expiry
 	logger.Debug("Validating expiry")
-	expiryResult := verifyExpiry(outcome)
+	timeOfVerification := clock.Now(v.clock)
+	expiryResult := verifyExpiry(outcome, timeOfVerification)
 	outcome.VerificationResults = append(outcome.VerificationResults, expiryResult)
 	logVerificationResult(logger, expiryResult)
 	if isCriticalFailure(expiryResult) {
@@ -515,7 +848,7 @@ func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelop
 
 	// verify authentic timestamp
 	logger.Debug("Validating authentic timestamp")
-	authenticTimestampResult := verifyAuthenticTimestamp(ctx, policyName, trustStores, signatureVerification, v.trustStore, v.revocationTimestampingValidator, outcome)
+	authenticTimestampResult := verifyAuthenticTimestamp(ctx, timeOfVerification, policyName, trustStores, signatureVerification, x509TrustStore, withRevocationTimeout(v.revocationTimestampingValidator, v.revocationTimeout), outcome)
 	outcome.VerificationResults = append(outcome.VerificationResults, authenticTimestampResult)
 	logVerificationResult(logger, authenticTimestampResult)
 	if isCriticalFailure(authenticTimestampResult) {
@@ -529,6 +862,9 @@ func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelop
 		!slices.Contains(pluginCapabilities, pluginframework.CapabilityRevocationCheckVerifier) {
 
 		logger.Debug("Validating revocation")
+		if artifact != nil && artifact.progress != nil {
+			artifact.progress(notation.ProgressEvent{Type: notation.ProgressRevocationCheckStarted, Artifact: *artifact.subject})
+		}
 		revocationResult := v.verifyRevocation(ctx, outcome)
 		outcome.VerificationResults = append(outcome.VerificationResults, revocationResult)
 		logVerificationResult(logger, revocationResult)
@@ -552,7 +888,7 @@ func (v *verifier) processSignature(ctx context.Context, sigBlob []byte, envelop
 
 		if len(capabilitiesToVerify) > 0 {
 			logger.Debugf("Executing verification plugin %q with capabilities %v", verificationPluginName, capabilitiesToVerify)
-			response, err := executePlugin(ctx, installedPlugin, capabilitiesToVerify, outcome.EnvelopeContent, trustedIdentities, pluginConfig)
+			response, err := executePlugin(ctx, installedPlugin, capabilitiesToVerify, outcome.EnvelopeContent, trustedIdentities, pluginConfig, artifact)
 			if err != nil {
 				return fmt.Errorf("failed to verify with plugin %s: %w", verificationPluginName, err)
 			}
@@ -579,18 +915,18 @@ func (v *verifier) verifyRevocation(ctx context.Context, outcome *notation.Verif
 		authenticSigningTime, _ = outcome.EnvelopeContent.SignerInfo.AuthenticSigningTime()
 	}
 
-	var certResults []*revocationresult.CertRevocationResult
-	var err error
-	if v.revocationCodeSigningValidator != nil {
-		certResults, err = v.revocationCodeSigningValidator.ValidateContext(ctx, revocation.ValidateContextOptions{
-			CertChain:            outcome.EnvelopeContent.SignerInfo.CertificateChain,
-			AuthenticSigningTime: authenticSigningTime,
-		})
-	} else {
-		certResults, err = v.revocationClient.Validate(outcome.EnvelopeContent.SignerInfo.CertificateChain, authenticSigningTime)
+	validator := v.revocationCodeSigningValidator
+	if validator == nil {
+		// the deprecated revocation client does not accept a context
+		validator = contextRevocation{client: v.revocationClient}
 	}
+	certResults, err := withRevocationTimeout(validator, v.revocationTimeout).ValidateContext(ctx, revocation.ValidateContextOptions{
+		CertChain:            outcome.EnvelopeContent.SignerInfo.CertificateChain,
+		AuthenticSigningTime: authenticSigningTime,
+	})
 	if err != nil {
 		logger.Debug("Error while checking revocation status, err: %s", err.Error())
+		auditRevocation(ctx, outcome, audit.ResultError, err)
 		return &notation.ValidationResult{
 			Type:   trustpolicy.TypeRevocation,
 			Action: outcome.VerificationLevel.Enforcement[trustpolicy.TypeRevocation],
@@ -612,6 +948,7 @@ func (v *verifier) verifyRevocation(ctx context.Context, outcome *notation.Verif
 		// revocationresult.ResultUnknown
 		result.Error = fmt.Errorf("signing certificate with subject %q revocation status is unknown", problematicCertSubject)
 	}
+	auditRevocation(ctx, outcome, finalResult.String(), result.Error)
 
 	return result
 }
@@ -678,38 +1015,9 @@ func processPluginResponse(capabilitiesToVerify []pluginframework.Capability, re
 	return nil
 }
 
-func verifyIntegrity(sigBlob []byte, envelopeMediaType string, outcome *notation.VerificationOutcome) (*signature.EnvelopeContent, *notation.ValidationResult) {
-	// parse the signature
-	sigEnv, err := signature.ParseEnvelope(envelopeMediaType, sigBlob)
-	if err != nil {
-		return nil, &notation.ValidationResult{
-			Error:  fmt.Errorf("unable to parse the digital signature, error : %s", err),
-			Type:   trustpolicy.TypeIntegrity,
-			Action: outcome.VerificationLevel.Enforcement[trustpolicy.TypeIntegrity],
-		}
-	}
-
-	// verify integrity
-	envContent, err := sigEnv.Verify()
+func verifyIntegrity(sigBlob []byte, envelopeMediaType string, limits envelopeLimits, outcome *notation.VerificationOutcome) (*signature.EnvelopeContent, *notation.ValidationResult) {
+	envContent, err := parseEnvelope(sigBlob, envelopeMediaType, limits)
 	if err != nil {
-		switch err.(type) {
-		case *signature.SignatureEnvelopeNotFoundError, *signature.InvalidSignatureError, *signature.SignatureIntegrityError:
-			return nil, &notation.ValidationResult{
-				Error:  err,
-				Type:   trustpolicy.TypeIntegrity,
-				Action: outcome.VerificationLevel.Enforcement[trustpolicy.TypeIntegrity],
-			}
-		default:
-			// unexpected error
-			return nil, &notation.ValidationResult{
-				Error:  notation.ErrorVerificationInconclusive{Msg: err.Error()},
-				Type:   trustpolicy.TypeIntegrity,
-				Action: outcome.VerificationLevel.Enforcement[trustpolicy.TypeIntegrity],
-			}
-		}
-	}
-
-	if err := envelope.ValidatePayloadContentType(&envContent.Payload); err != nil {
 		return nil, &notation.ValidationResult{
 			Error:  err,
 			Type:   trustpolicy.TypeIntegrity,
@@ -743,7 +1051,7 @@ func verifyAuthenticity(trustCerts []*x509.Certificate, outcome *notation.Verifi
 			}
 		default:
 			return &notation.ValidationResult{
-				Error:  notation.ErrorVerificationInconclusive{Msg: "authenticity verification failed with error : " + err.Error()},
+				Error:  notation.ErrorVerificationInconclusive{Msg: "authenticity verification failed with error : " + err.Error(), InnerError: err},
 				Type:   trustpolicy.TypeAuthenticity,
 				Action: outcome.VerificationLevel.Enforcement[trustpolicy.TypeAuthenticity],
 			}
@@ -770,8 +1078,8 @@ func verifyUserMetadata(logger log.Logger, payload *envelope.Payload, userMetada
 	return nil
 }
 
-func verifyExpiry(outcome *notation.VerificationOutcome) *notation.ValidationResult {
-	if expiry := outcome.EnvelopeContent.SignerInfo.SignedAttributes.Expiry; !expiry.IsZero() && !time.Now().Before(expiry) {
+func verifyExpiry(outcome *notation.VerificationOutcome, timeOfVerification time.Time) *notation.ValidationResult {
+	if expiry := outcome.EnvelopeContent.SignerInfo.SignedAttributes.Expiry; !expiry.IsZero() && !timeOfVerification.Before(expiry) {
 		return &notation.ValidationResult{
 			Error:  fmt.Errorf("digital signature has expired on %q", expiry.Format(time.RFC1123Z)),
 			Type:   trustpolicy.TypeExpiry,
@@ -785,7 +1093,7 @@ func verifyExpiry(outcome *notation.VerificationOutcome) *notation.ValidationRes
 	}
 }
 
-func verifyAuthenticTimestamp(ctx context.Context, policyName string, trustStores []string, signatureVerification trustpolicy.SignatureVerification, x509TrustStore truststore.X509TrustStore, r revocation.Validator, outcome *notation.VerificationOutcome) *notation.ValidationResult {
+func verifyAuthenticTimestamp(ctx context.Context, timeOfVerification time.Time, policyName string, trustStores []string, signatureVerification trustpolicy.SignatureVerification, x509TrustStore truststore.X509TrustStore, r revocation.Validator, outcome *notation.VerificationOutcome) *notation.ValidationResult {
 	logger := log.GetLogger(ctx)
 
 	signerInfo := outcome.EnvelopeContent.SignerInfo
@@ -793,7 +1101,7 @@ func verifyAuthenticTimestamp(ctx context.Context, policyName string, trustStore
 	if signerInfo.SignedAttributes.SigningScheme == signature.SigningSchemeX509 {
 		logger.Debug("Under signing scheme notary.x509...")
 		return &notation.ValidationResult{
-			Error:  verifyTimestamp(ctx, policyName, trustStores, signatureVerification, x509TrustStore, r, outcome),
+			Error:  verifyTimestamp(ctx, timeOfVerification, policyName, trustStores, signatureVerification, x509TrustStore, r, outcome),
 			Type:   trustpolicy.TypeAuthenticTimestamp,
 			Action: outcome.VerificationLevel.Enforcement[trustpolicy.TypeAuthenticTimestamp],
 		}
@@ -873,7 +1181,21 @@ func revocationFinalResult(certResults []*revocationresult.CertRevocationResult,
 	return finalResult, problematicCertSubject
 }
 
-func executePlugin(ctx context.Context, installedPlugin pluginframework.VerifyPlugin, capabilitiesToVerify []pluginframework.Capability, envelopeContent *signature.EnvelopeContent, trustedIdentities []string, pluginConfig map[string]string) (*pluginframework.VerifySignatureResponse, error) {
+// artifactContext is the context of the verified OCI artifact passed to the
+// verification plugin.
+type artifactContext struct {
+	subject                      *ocispec.Descriptor
+	signatureManifestAnnotations map[string]string
+	progress                     notation.ProgressFunc
+}
+
+// artifactSignatureVerifier is implemented by verification plugins accepting
+// the context of the verified artifact.
+type artifactSignatureVerifier interface {
+	VerifyArtifactSignature(ctx context.Context, req *proto.VerifyArtifactSignatureRequest) (*pluginframework.VerifySignatureResponse, error)
+}
+
+func executePlugin(ctx context.Context, installedPlugin pluginframework.VerifyPlugin, capabilitiesToVerify []pluginframework.Capability, envelopeContent *signature.EnvelopeContent, trustedIdentities []string, pluginConfig map[string]string, artifact *artifactContext) (*pluginframework.VerifySignatureResponse, error) {
 	logger := log.GetLogger(ctx)
 	// sanity check
 	if installedPlugin == nil {
@@ -924,9 +1246,39 @@ func executePlugin(ctx context.Context, installedPlugin pluginframework.VerifyPl
 		TrustPolicy:     policy,
 		PluginConfig:    pluginConfig,
 	}
+	if artifactVerifier, ok := installedPlugin.(artifactSignatureVerifier); ok && artifact != nil {
+		return artifactVerifier.VerifyArtifactSignature(ctx, &proto.VerifyArtifactSignatureRequest{
+			VerifySignatureRequest:       *req,
+			Subject:                      artifact.subject,
+			SignatureManifestAnnotations: artifact.signatureManifestAnnotations,
+		})
+	}
 	return installedPlugin.VerifySignature(ctx, req)
 }
 
+// verifyX509DeniedIdentities returns an error if the subject of the signing
+// certificate matches one of the denied identities.
+func verifyX509DeniedIdentities(policyName string, deniedIdentities []string, certs []*x509.Certificate) error {
+	leafCertDN, err := pkix.ParseDistinguishedName(certs[0].Subject.String())
+	if err != nil {
+		return fmt.Errorf("error while parsing the certificate subject from the digital signature. error : %q", err)
+	}
+	for _, identity := range deniedIdentities {
+		identityPrefix, identityValue, _ := strings.Cut(identity, ":")
+		if identityPrefix != trustpolicyInternal.X509Subject {
+			continue
+		}
+		deniedDN, err := pkix.ParseDistinguishedName(identityValue)
+		if err != nil {
+			return err
+		}
+		if pkix.IsSubsetDN(deniedDN, leafCertDN) {
+			return fmt.Errorf("signing certificate from the digital signature matches the denied identity %q defined in the trust policy %q", identity, policyName)
+		}
+	}
+	return nil
+}
+
 func verifyX509TrustedIdentities(policyName string, trustedIdentities []string, certs []*x509.Certificate) error {
 	if slices.Contains(trustedIdentities, trustpolicyInternal.Wildcard) {
 		return nil
@@ -991,7 +1343,7 @@ func isRequiredVerificationPluginVer(pluginVer string, minPluginVer string) bool
 
 // verifyTimestamp provides core verification logic of authentic timestamp under
 // signing scheme `notary.x509`.
-func verifyTimestamp(ctx context.Context, policyName string, trustStores []string, signatureVerification trustpolicy.SignatureVerification, x509TrustStore truststore.X509TrustStore, r revocation.Validator, outcome *notation.VerificationOutcome) error {
+func verifyTimestamp(ctx context.Context, timeOfVerification time.Time, policyName string, trustStores []string, signatureVerification trustpolicy.SignatureVerification, x509TrustStore truststore.X509TrustStore, r revocation.Validator, outcome *notation.VerificationOutcome) error {
 	logger := log.GetLogger(ctx)
 
 	signerInfo := outcome.EnvelopeContent.SignerInfo
@@ -1008,7 +1360,6 @@ func verifyTimestamp(ctx context.Context, policyName string, trustStores []strin
 	}
 
 	// check based on 'verifyTimestamp' field
-	timeOfVerification := time.Now()
 	if performTimestampVerification &&
 		signatureVerification.VerifyTimestamp == trustpolicy.OptionAfterCertExpiry {
 		// check if signing cert chain has expired
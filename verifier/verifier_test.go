@@ -15,17 +15,21 @@ package verifier
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -42,13 +46,18 @@ import (
 	corex509 "github.com/notaryproject/notation-core-go/x509"
 	"github.com/notaryproject/notation-go"
 	"github.com/notaryproject/notation-go/dir"
+	"github.com/notaryproject/notation-go/dump"
 	"github.com/notaryproject/notation-go/internal/envelope"
 	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/internal/slices"
 	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/network"
+	"github.com/notaryproject/notation-go/notationtest"
 	"github.com/notaryproject/notation-go/plugin/proto"
 	"github.com/notaryproject/notation-go/signer"
 	"github.com/notaryproject/notation-go/verifier/trustpolicy"
 	"github.com/notaryproject/notation-go/verifier/truststore"
+	pluginframework "github.com/notaryproject/notation-plugin-framework-go/plugin"
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
@@ -833,6 +842,47 @@ func TestNewVerifierWithOptionsError(t *testing.T) {
 	}
 }
 
+func TestNewVerifierWithNetwork(t *testing.T) {
+	v, err := NewVerifierWithOptions(store, VerifierOptions{
+		OCITrustPolicy: &ociPolicy,
+		PluginManager:  pm,
+		Network:        &network.Config{ConnectTimeout: 5 * time.Second},
+	})
+	if err != nil {
+		t.Fatalf("expected NewVerifierWithOptions constructor to succeed, but got %v", err)
+	}
+	if v.revocationCodeSigningValidator == nil || v.revocationTimestampingValidator == nil {
+		t.Fatal("expected non-nil revocation validators")
+	}
+
+	invalid := &network.Config{Resolver: &net.Resolver{}, Nameservers: []string{"10.0.0.53:53"}}
+	if _, err := NewVerifierWithOptions(store, VerifierOptions{
+		OCITrustPolicy: &ociPolicy,
+		PluginManager:  pm,
+		Network:        invalid,
+	}); err == nil {
+		t.Fatal("expected error for invalid network config")
+	}
+	if _, err := newServerVerifier(store, VerifierOptions{PluginManager: pm, Network: invalid}, func() (*trustpolicy.OCIDocument, *trustpolicy.BlobDocument, error) {
+		return &ociPolicy, nil, nil
+	}); err == nil {
+		t.Fatal("expected error for invalid network config")
+	}
+
+	// the proxy of the revocation component applies to the OCSP and CRL
+	// clients
+	ocspHTTPClient, _, err := newRevocationClients(&network.Config{
+		Proxy:            &network.Proxy{},
+		ComponentProxies: map[network.Component]*network.Proxy{network.ComponentRevocation: {URL: "socks5://proxy.example.com:1080"}},
+	})
+	if err != nil {
+		t.Fatalf("newRevocationClients() error = %v", err)
+	}
+	if ocspHTTPClient.Transport.(*dump.Transport).Base.(*http.Transport).Proxy == nil {
+		t.Fatal("expected the proxy of the revocation component")
+	}
+}
+
 func TestNewOCIVerifierFromConfig(t *testing.T) {
 	defer func(oldUserConfigDir string) {
 		dir.UserConfigDir = oldUserConfigDir
@@ -1296,6 +1346,53 @@ func TestVerifyX509TrustedIdentities(t *testing.T) {
 	}
 }
 
+func TestVerifyX509DeniedIdentities(t *testing.T) {
+	certs, _ := corex509.ReadCertificateFile(filepath.FromSlash("testdata/verifier/signing-cert.pem")) // cert's subject is "CN=SomeCN,OU=SomeOU,O=SomeOrg,L=Seattle,ST=WA,C=US"
+
+	tests := []struct {
+		deniedIdentities []string
+		wantErr          bool
+	}{
+		{[]string{"x509.subject:C=IND,O=SomeOrg,ST=TS"}, false},
+		{[]string{"x509.subject:C=US,O=SomeOrg,ST=WA"}, true},
+		{[]string{"x509.subject:C=IND,O=SomeOrg,ST=TS", "x509.subject:CN=SomeCN,OU=SomeOU,O=SomeOrg,L=Seattle,ST=WA,C=US"}, true},
+		{[]string{"nonX509Prefix:my-custom-identity"}, false},
+	}
+	for i, tt := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyX509DeniedIdentities("test-statement-name", tt.deniedIdentities, certs)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("verifyX509DeniedIdentities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewVerifierWithOCITrustPolicyV2(t *testing.T) {
+	policyDoc := dummyOCIPolicyDocument()
+	policyDocV2, err := trustpolicy.ConvertOCIDocument(&policyDoc)
+	if err != nil {
+		t.Fatalf("ConvertOCIDocument() error = %v", err)
+	}
+	policyDocV2.TrustPolicies[0].DeniedIdentities = []string{"x509.subject:CN=Denied,O=Notary,ST=WA,C=US"}
+
+	v, err := NewVerifierWithOptions(&testTrustStore{}, VerifierOptions{OCITrustPolicyV2: policyDocV2})
+	if err != nil {
+		t.Fatalf("NewVerifierWithOptions() error = %v", err)
+	}
+	if got := v.ociTrustPolicyDoc.TrustPolicies[0].DeniedIdentities; !reflect.DeepEqual(got, policyDocV2.TrustPolicies[0].DeniedIdentities) {
+		t.Fatalf("denied identities = %v, want %v", got, policyDocV2.TrustPolicies[0].DeniedIdentities)
+	}
+
+	if _, err := NewVerifierWithOptions(&testTrustStore{}, VerifierOptions{OCITrustPolicy: &policyDoc, OCITrustPolicyV2: policyDocV2}); err == nil {
+		t.Fatal("NewVerifierWithOptions() expects error when both OCI trust policies are set")
+	}
+	policyDocV2.Version = "1.0"
+	if _, err := NewVerifierWithOptions(&testTrustStore{}, VerifierOptions{OCITrustPolicyV2: policyDocV2}); err == nil {
+		t.Fatal("NewVerifierWithOptions() expects error for invalid version 2.0 trust policy")
+	}
+}
+
 func TestVerifyUserMetadata(t *testing.T) {
 	policyDocument := dummyOCIPolicyDocument()
 	policyDocument.TrustPolicies[0].SignatureVerification.VerificationLevel = trustpolicy.LevelAudit.Name
@@ -1597,3 +1694,450 @@ func getTestDescGenFunc(returnErr bool, customDigest digest.Digest) notation.Blo
 		}, err
 	}
 }
+
+// artifactPluginMock records the verify-signature request with the artifact
+// context.
+type artifactPluginMock struct {
+	mock.PluginMock
+	req *proto.VerifyArtifactSignatureRequest
+}
+
+func (p *artifactPluginMock) VerifyArtifactSignature(ctx context.Context, req *proto.VerifyArtifactSignatureRequest) (*proto.VerifySignatureResponse, error) {
+	p.req = req
+	return p.VerifySignature(ctx, &req.VerifySignatureRequest)
+}
+
+func TestExecutePluginWithArtifactContext(t *testing.T) {
+	envContent, result := verifyIntegrity(mock.MockCaPluginSigEnv, "application/jose+json", envelopeLimits{}, &notation.VerificationOutcome{VerificationLevel: trustpolicy.LevelStrict})
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	installedPlugin := &artifactPluginMock{
+		PluginMock: mock.PluginMock{ExecuteResponse: &proto.VerifySignatureResponse{}},
+	}
+	subject := mock.ImageDescriptor
+	subject.ArtifactType = "application/vnd.example.sbom"
+	artifact := &artifactContext{
+		subject:                      &subject,
+		signatureManifestAnnotations: map[string]string{"org.example.pipeline": "x"},
+	}
+	capabilities := []proto.Capability{proto.CapabilityTrustedIdentityVerifier}
+	if _, err := executePlugin(context.Background(), installedPlugin, capabilities, envContent, nil, nil, artifact); err != nil {
+		t.Fatalf("executePlugin() error = %v", err)
+	}
+	if installedPlugin.req == nil {
+		t.Fatal("executePlugin() did not pass the artifact context to the plugin")
+	}
+	if !reflect.DeepEqual(installedPlugin.req.Subject, &subject) {
+		t.Fatalf("executePlugin() subject = %+v, want %+v", installedPlugin.req.Subject, subject)
+	}
+	if !reflect.DeepEqual(installedPlugin.req.SignatureManifestAnnotations, artifact.signatureManifestAnnotations) {
+		t.Fatalf("executePlugin() signature manifest annotations = %v, want %v", installedPlugin.req.SignatureManifestAnnotations, artifact.signatureManifestAnnotations)
+	}
+	if !reflect.DeepEqual(installedPlugin.req.TrustPolicy.SignatureVerification, capabilities) {
+		t.Fatalf("executePlugin() trust policy = %+v", installedPlugin.req.TrustPolicy)
+	}
+
+	// plugins not accepting the artifact context get the plain request
+	if _, err := executePlugin(context.Background(), &installedPlugin.PluginMock, capabilities, envContent, nil, nil, artifact); err != nil {
+		t.Fatalf("executePlugin() error = %v", err)
+	}
+}
+
+func TestTrustedThumbprints(t *testing.T) {
+	dir.UserConfigDir = "testdata"
+	policyDocument := dummyOCIPolicyDocument()
+	v := verifier{
+		ociTrustPolicyDoc: &policyDocument,
+		trustStore:        truststore.NewX509TrustStore(dir.ConfigFS()),
+	}
+	opts := notation.VerifierVerifyOptions{ArtifactReference: mock.SampleArtifactUri}
+	thumbprints, err := v.TrustedThumbprints(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("TrustedThumbprints() failed: %v", err)
+	}
+
+	// the signature verified by the trust policy has a trusted certificate
+	// in its chain
+	sigEnv, err := signature.ParseEnvelope(jws.MediaTypeEnvelope, mock.MockCaValidSigEnv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := sigEnv.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, cert := range content.SignerInfo.CertificateChain {
+		checkSum := sha256.Sum256(cert.Raw)
+		if slices.Contains(thumbprints, hex.EncodeToString(checkSum[:])) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("TrustedThumbprints() = %v, want a certificate of the signature chain", thumbprints)
+	}
+
+	opts.ArtifactReference = "localhost:5000/no-policy@sha256:hash"
+	if _, err := v.TrustedThumbprints(context.Background(), opts); err == nil {
+		t.Fatal("expected error for artifact without applicable trust policy")
+	}
+}
+
+// artifactPluginManager returns the artifactPluginMock.
+type artifactPluginManager struct {
+	mock.PluginManager
+	plugin *artifactPluginMock
+}
+
+func (pm artifactPluginManager) Get(ctx context.Context, name string) (pluginframework.Plugin, error) {
+	return pm.plugin, nil
+}
+
+// describingRepository describes the artifact with an artifact type.
+type describingRepository struct {
+	mock.Repository
+	artifactType string
+	annotations  map[string]string
+}
+
+func (r describingRepository) DescribeArtifact(ctx context.Context, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	desc.ArtifactType = r.artifactType
+	desc.Annotations = r.annotations
+	return desc, nil
+}
+
+func TestVerifyPassesArtifactContextToPlugin(t *testing.T) {
+	policyDocument := dummyOCIPolicyDocument()
+	dir.UserConfigDir = "testdata"
+	x509TrustStore := truststore.NewX509TrustStore(dir.ConfigFS())
+	revocationClient, err := revocation.New(&http.Client{Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error while creating revocation object: %v", err)
+	}
+	installedPlugin := &artifactPluginMock{
+		PluginMock: mock.PluginMock{
+			Metadata: proto.GetMetadataResponse{
+				Name:                      "plugin-name",
+				Version:                   "1.0.0",
+				SupportedContractVersions: []string{proto.ContractVersion},
+				Capabilities:              []proto.Capability{proto.CapabilityTrustedIdentityVerifier},
+			},
+			ExecuteResponse: &proto.VerifySignatureResponse{
+				VerificationResults: map[proto.Capability]*proto.VerificationResult{
+					proto.CapabilityTrustedIdentityVerifier: {
+						Success: true,
+					},
+				},
+				ProcessedAttributes: []interface{}{mock.PluginExtendedCriticalAttribute.Key},
+			},
+		},
+	}
+	v := verifier{
+		ociTrustPolicyDoc: &policyDocument,
+		trustStore:        x509TrustStore,
+		pluginManager:     artifactPluginManager{plugin: installedPlugin},
+		revocationClient:  revocationClient,
+	}
+
+	sigManifestDesc := mock.SigManfiestDescriptor
+	sigManifestDesc.Annotations = map[string]string{"org.example.pipeline": "release"}
+	repo := describingRepository{
+		Repository:   mock.NewRepository(),
+		artifactType: "application/vnd.example.sbom",
+		annotations:  map[string]string{"org.example.build": "42"},
+	}
+	repo.ListSignaturesResponse = []ocispec.Descriptor{sigManifestDesc}
+	repo.FetchSignatureBlobResponse = mock.MockCaPluginSigEnv
+
+	verifyOpts := notation.VerifyOptions{
+		ArtifactReference:    mock.SampleArtifactUri,
+		MaxSignatureAttempts: 1,
+	}
+	if _, _, err := notation.Verify(context.Background(), &v, repo, verifyOpts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if installedPlugin.req == nil || installedPlugin.req.Subject == nil {
+		t.Fatal("Verify() did not pass the artifact context to the plugin")
+	}
+	subject := installedPlugin.req.Subject
+	if subject.Digest != mock.SampleDigest || subject.ArtifactType != repo.artifactType || !reflect.DeepEqual(subject.Annotations, repo.annotations) {
+		t.Fatalf("Verify() passed subject %+v to the plugin", subject)
+	}
+	if !reflect.DeepEqual(installedPlugin.req.SignatureManifestAnnotations, sigManifestDesc.Annotations) {
+		t.Fatalf("Verify() passed signature manifest annotations %v to the plugin, want %v", installedPlugin.req.SignatureManifestAnnotations, sigManifestDesc.Annotations)
+	}
+}
+
+func TestVerifyBlobStrongerDigestAlgorithm(t *testing.T) {
+	ctx := context.Background()
+	leaf := testhelper.GetRSALeafCertificate()
+	root := testhelper.GetRSARootCertificate()
+	blobSigner, err := signer.NewGenericSigner(leaf.PrivateKey, []*x509.Certificate{leaf.Cert, root.Cert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "blob", root.Cert), VerifierOptions{
+		BlobTrustPolicy: &trustpolicy.BlobDocument{
+			Version: "1.0",
+			TrustPolicies: []trustpolicy.BlobTrustPolicy{
+				{
+					Name:                  "blob-test-policy",
+					SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: "strict"},
+					TrustStores:           []string{"ca:blob"},
+					TrustedIdentities:     []string{"*"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const blob = "blob signed with a SHA-512 digest"
+	sig, _, err := notation.SignBlob(ctx, blobSigner, strings.NewReader(blob), notation.SignBlobOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ContentMediaType:  "text/plain",
+		DigestAlgorithm:   digest.SHA512,
+	})
+	if err != nil {
+		t.Fatalf("SignBlob() error = %v", err)
+	}
+	_, outcome, err := notation.VerifyBlob(ctx, v, strings.NewReader(blob), sig, notation.VerifyBlobOptions{
+		BlobVerifierVerifyOptions: notation.BlobVerifierVerifyOptions{
+			SignatureMediaType: jws.MediaTypeEnvelope,
+			TrustPolicyName:    "blob-test-policy",
+		},
+		ContentMediaType: "text/plain",
+	})
+	if err != nil {
+		t.Fatalf("VerifyBlob() error = %v", err)
+	}
+	var payload envelope.Payload
+	if err := json.Unmarshal(outcome.EnvelopeContent.Payload.Content, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.TargetArtifact.Digest != digest.SHA512.FromString(blob) {
+		t.Fatalf("signed digest = %v, want SHA-512 digest", payload.TargetArtifact.Digest)
+	}
+
+	if _, _, err := notation.VerifyBlob(ctx, v, strings.NewReader("tampered blob"), sig, notation.VerifyBlobOptions{
+		BlobVerifierVerifyOptions: notation.BlobVerifierVerifyOptions{
+			SignatureMediaType: jws.MediaTypeEnvelope,
+			TrustPolicyName:    "blob-test-policy",
+		},
+		ContentMediaType: "text/plain",
+	}); err == nil {
+		t.Fatal("VerifyBlob() of a tampered blob expected error")
+	}
+}
+
+func TestVerifyKeyRequirements(t *testing.T) {
+	ctx := context.Background()
+	leaf := testhelper.GetRSALeafCertificate()
+	root := testhelper.GetRSARootCertificate()
+	blobSigner, err := signer.NewGenericSigner(leaf.PrivateKey, []*x509.Certificate{leaf.Cert, root.Cert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const blob = "blob signed with a RSA 3072 key"
+	sig, _, err := notation.SignBlob(ctx, blobSigner, strings.NewReader(blob), notation.SignBlobOptions{
+		SignerSignOptions: notation.SignerSignOptions{SignatureMediaType: jws.MediaTypeEnvelope},
+		ContentMediaType:  "text/plain",
+	})
+	if err != nil {
+		t.Fatalf("SignBlob() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		level        string
+		requirements *trustpolicy.KeyRequirements
+		wantErr      bool
+	}{
+		{
+			name:         "requirements met",
+			level:        "strict",
+			requirements: &trustpolicy.KeyRequirements{MinRSAKeySize: 2048, AllowedHashAlgorithms: []string{trustpolicy.HashSHA256, trustpolicy.HashSHA384}},
+		},
+		{
+			name:         "RSA key too small",
+			level:        "strict",
+			requirements: &trustpolicy.KeyRequirements{MinRSAKeySize: 4096},
+			wantErr:      true,
+		},
+		{
+			name:         "hash algorithm not allowed",
+			level:        "strict",
+			requirements: &trustpolicy.KeyRequirements{AllowedHashAlgorithms: []string{trustpolicy.HashSHA512}},
+			wantErr:      true,
+		},
+		{
+			name:         "logged in audit level",
+			level:        "audit",
+			requirements: &trustpolicy.KeyRequirements{MinRSAKeySize: 4096},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "blob", root.Cert), VerifierOptions{
+				BlobTrustPolicy: &trustpolicy.BlobDocument{
+					Version: "1.0",
+					TrustPolicies: []trustpolicy.BlobTrustPolicy{
+						{
+							Name: "blob-test-policy",
+							SignatureVerification: trustpolicy.SignatureVerification{
+								VerificationLevel: tt.level,
+								KeyRequirements:   tt.requirements,
+							},
+							TrustStores:       []string{"ca:blob"},
+							TrustedIdentities: []string{"*"},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _, err = notation.VerifyBlob(ctx, v, strings.NewReader(blob), sig, notation.VerifyBlobOptions{
+				BlobVerifierVerifyOptions: notation.BlobVerifierVerifyOptions{
+					SignatureMediaType: jws.MediaTypeEnvelope,
+					TrustPolicyName:    "blob-test-policy",
+				},
+				ContentMediaType: "text/plain",
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyBlob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyCustomPayloadType(t *testing.T) {
+	const firmwarePayloadType = "application/vnd.example.firmware.payload.v1+json"
+	type firmwarePayload struct {
+		Image  string `json:"image"`
+		SHA256 string `json:"sha256"`
+		Size   int64  `json:"size"`
+	}
+	err := notation.RegisterPayloadType(firmwarePayloadType, func(content []byte) (ocispec.Descriptor, error) {
+		var payload firmwarePayload
+		if err := json.Unmarshal(content, &payload); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if payload.Image == "" {
+			return ocispec.Descriptor{}, errors.New("image is missing")
+		}
+		return ocispec.Descriptor{
+			MediaType: "application/vnd.example.firmware",
+			Digest:    digest.NewDigestFromEncoded(digest.SHA256, payload.SHA256),
+			Size:      payload.Size,
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaf := testhelper.GetRSALeafCertificate()
+	root := testhelper.GetRSARootCertificate()
+	localSigner, err := signature.NewLocalSigner([]*x509.Certificate{leaf.Cert, root.Cert}, leaf.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firmware := []byte("firmware image")
+	firmwareDigest := digest.FromBytes(firmware)
+	sign := func(payload firmwarePayload) []byte {
+		content, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigEnv, err := signature.NewEnvelope(jws.MediaTypeEnvelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := sigEnv.Sign(&signature.SignRequest{
+			Payload:       signature.Payload{ContentType: firmwarePayloadType, Content: content},
+			Signer:        localSigner,
+			SigningTime:   time.Now(),
+			SigningScheme: signature.SigningSchemeX509,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+
+	v, err := NewVerifierWithOptions(notationtest.NewTrustStore().Add(truststore.TypeCA, "test", root.Cert), VerifierOptions{
+		OCITrustPolicy: notationtest.TrustPolicy("test"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := ocispec.Descriptor{
+		MediaType: "application/vnd.example.firmware",
+		Digest:    firmwareDigest,
+		Size:      int64(len(firmware)),
+	}
+	opts := notation.VerifierVerifyOptions{
+		ArtifactReference:  "localhost:5000/firmware@" + firmwareDigest.String(),
+		SignatureMediaType: jws.MediaTypeEnvelope,
+	}
+
+	sig := sign(firmwarePayload{Image: "firmware.bin", SHA256: firmwareDigest.Encoded(), Size: desc.Size})
+	if _, err := v.Verify(context.Background(), desc, sig, opts); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	invalidSig := sign(firmwarePayload{SHA256: firmwareDigest.Encoded(), Size: desc.Size})
+	if _, err := v.Verify(context.Background(), desc, invalidSig, opts); err == nil || !strings.Contains(err.Error(), "image is missing") {
+		t.Fatalf("Verify() error = %v, want payload schema error", err)
+	}
+}
+
+func TestSkipVerifyArtifactType(t *testing.T) {
+	const sbomType = "application/spdx+json"
+	policyDoc := dummyOCIPolicyDocument()
+	policyDoc.Version = trustpolicy.VersionV2
+	policyDoc.TrustPolicies = append(policyDoc.TrustPolicies, trustpolicy.OCITrustPolicy{
+		Name:                  "sbom-statement",
+		RegistryScopes:        []string{"registry.acme-rockets.io/software/net-monitor"},
+		ArtifactTypes:         []string{sbomType},
+		SignatureVerification: trustpolicy.SignatureVerification{VerificationLevel: "skip"},
+	})
+	v, err := NewVerifierWithOptions(&testTrustStore{}, VerifierOptions{OCITrustPolicy: &policyDoc})
+	if err != nil {
+		t.Fatalf("NewVerifierWithOptions() error = %v", err)
+	}
+	ctx := context.Background()
+	opts := notation.VerifierVerifyOptions{
+		ArtifactReference: "registry.acme-rockets.io/software/net-monitor@sha256:60043cf45eaebc4c0867fea485a039b598f52fd09fd5b07b0b2d2f88fad9d74e",
+	}
+
+	// the artifact type is not known yet
+	skip, level, err := v.SkipVerify(ctx, opts)
+	if err != nil || skip || level != nil {
+		t.Fatalf("SkipVerify() = %v, %v, %v, want false, nil, nil", skip, level, err)
+	}
+
+	opts.ArtifactType = sbomType
+	skip, level, err = v.SkipVerify(ctx, opts)
+	if err != nil || !skip || level != trustpolicy.LevelSkip {
+		t.Fatalf("SkipVerify() = %v, %v, %v for %s, want true, skip, nil", skip, level, err, sbomType)
+	}
+
+	opts.ArtifactType = "application/vnd.oci.image.config.v1+json"
+	skip, level, err = v.SkipVerify(ctx, opts)
+	if err != nil || skip || level.Name != trustpolicy.LevelStrict.Name {
+		t.Fatalf("SkipVerify() = %v, %v, %v for image, want false, strict, nil", skip, level, err)
+	}
+
+	// the artifact type of the descriptor selects the statement
+	opts.ArtifactType = ""
+	outcome, err := v.Verify(ctx, ocispec.Descriptor{ArtifactType: sbomType}, []byte{}, opts)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if outcome.VerificationLevel != trustpolicy.LevelSkip {
+		t.Fatalf("Verify() verification level = %v, want skip", outcome.VerificationLevel.Name)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	orasRegistry "oras.land/oras-go/v2/registry"
+
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// defaultVerifyAllConcurrency is the default maximum number of artifact
+// references verified concurrently by [VerifyAll].
+const defaultVerifyAllConcurrency = 4
+
+// RepositoryFunc returns the repository client of the named repository,
+// e.g. "registry.example.com/software/net-monitor".
+type RepositoryFunc func(ctx context.Context, repository string) (registry.Repository, error)
+
+// VerifyAllOptions contains parameters for [notation.VerifyAll].
+type VerifyAllOptions struct {
+	// PluginConfig is a map of plugin configs.
+	PluginConfig map[string]string
+
+	// MaxSignatureAttempts is the maximum number of signature envelopes that
+	// will be processed for verification of each artifact. If set to less
+	// than or equals to zero, an error will be returned.
+	MaxSignatureAttempts int
+
+	// UserMetadata contains key-value pairs that must be present in the
+	// signature
+	UserMetadata map[string]string
+
+	// Concurrency is the maximum number of artifact references verified
+	// concurrently. If set to less than or equals to zero, 4 is used.
+	Concurrency int
+
+	// FailurePolicy, if set, sets how the failed verifications are handled,
+	// and a [BatchError] is returned if any verification fails. If nil, all
+	// the references are verified and the failures are only reported in
+	// the results.
+	FailurePolicy *FailurePolicy
+}
+
+// VerifyAllResult is the verification result of an artifact reference
+// verified by [notation.VerifyAll].
+type VerifyAllResult struct {
+	// ArtifactReference is the verified artifact reference.
+	ArtifactReference string
+
+	// Descriptor is the descriptor of the verified artifact.
+	Descriptor ocispec.Descriptor
+
+	// Outcomes are the verification outcomes as returned by
+	// [notation.Verify].
+	Outcomes []*VerificationOutcome
+
+	// Error is the error that caused the verification to fail (if it fails).
+	Error error
+}
+
+// VerifyAll verifies the artifacts referenced by artifactRefs with bounded
+// concurrency, and returns the result of each reference in the order of
+// artifactRefs. A failed verification is reported in its result and does not
+// stop the verification of the other references.
+//
+// The repository client returned by repoFunc is created once per repository
+// and shared by the references of the repository, so that the registry
+// sessions, e.g. authentication tokens, are reused. The verifier is shared by
+// all references; use a verifier caching the revocation data, e.g. created
+// by verifier.NewServerVerifier, to share the revocation data.
+//
+// If opts.FailurePolicy is nil, an error is returned only if the arguments
+// are invalid.
+func VerifyAll(ctx context.Context, verifier Verifier, repoFunc RepositoryFunc, artifactRefs []string, opts VerifyAllOptions) ([]*VerifyAllResult, error) {
+	// sanity check
+	if verifier == nil {
+		return nil, errors.New("verifier cannot be nil")
+	}
+	if repoFunc == nil {
+		return nil, errors.New("repoFunc cannot be nil")
+	}
+	if opts.MaxSignatureAttempts <= 0 {
+		return nil, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("verifyAllOptions.MaxSignatureAttempts expects a positive number, got %d", opts.MaxSignatureAttempts)}
+	}
+	if err := opts.FailurePolicy.validate(); err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultVerifyAllConcurrency
+	}
+
+	repos := &repositoryCache{
+		repoFunc: repoFunc,
+		entries:  make(map[string]*repositoryCacheEntry),
+	}
+	results := make([]*VerifyAllResult, len(artifactRefs))
+	for i, artifactRef := range artifactRefs {
+		results[i] = &VerifyAllResult{ArtifactReference: artifactRef}
+	}
+	errs, aborted := runBatch(ctx, len(artifactRefs), concurrency, opts.FailurePolicy, func(ctx context.Context, i int) error {
+		result := results[i]
+		result.Descriptor, result.Outcomes, result.Error = verifyReference(ctx, verifier, repos, result.ArtifactReference, opts)
+		return result.Error
+	})
+	for i, err := range errs {
+		results[i].Error = err
+	}
+	if opts.FailurePolicy != nil {
+		return results, newBatchError(artifactRefs, errs, aborted)
+	}
+	return results, nil
+}
+
+// verifyReference verifies the artifact referenced by artifactRef.
+func verifyReference(ctx context.Context, verifier Verifier, repos *repositoryCache, artifactRef string, opts VerifyAllOptions) (ocispec.Descriptor, []*VerificationOutcome, error) {
+	if err := ctx.Err(); err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	ref, err := orasRegistry.ParseReference(artifactRef)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: err.Error(), InnerError: err}
+	}
+	repo, err := repos.get(ctx, ref.Registry+"/"+ref.Repository)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("failed to create the repository client of %q: %v", artifactRef, err), InnerError: err}
+	}
+	desc, outcomes, err := Verify(ctx, verifier, repo, VerifyOptions{
+		ArtifactReference:    artifactRef,
+		PluginConfig:         opts.PluginConfig,
+		MaxSignatureAttempts: opts.MaxSignatureAttempts,
+		UserMetadata:         opts.UserMetadata,
+	})
+	if err != nil {
+		log.GetLogger(ctx).Warnf("Verification of %s failed with error: %v", artifactRef, err)
+	}
+	return desc, outcomes, err
+}
+
+// repositoryCache creates the repository clients once per repository.
+type repositoryCache struct {
+	repoFunc RepositoryFunc
+
+	mu      sync.Mutex
+	entries map[string]*repositoryCacheEntry
+}
+
+type repositoryCacheEntry struct {
+	once sync.Once
+	repo registry.Repository
+	err  error
+}
+
+// get returns the repository client of the named repository.
+func (c *repositoryCache) get(ctx context.Context, name string) (registry.Repository, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[name]
+	if !ok {
+		entry = &repositoryCacheEntry{}
+		c.entries[name] = entry
+	}
+	c.mu.Unlock()
+	entry.once.Do(func() {
+		entry.repo, entry.err = c.repoFunc(ctx, name)
+	})
+	return entry.repo, entry.err
+}
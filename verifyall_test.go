@@ -0,0 +1,180 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestVerifyAll(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}
+
+	var mu sync.Mutex
+	created := map[string]int{}
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		created[repository]++
+		if repository == "registry.acme-rockets.io/software/unknown" {
+			return nil, errors.New("repository not found")
+		}
+		return mock.NewRepository(), nil
+	}
+	refs := []string{
+		mock.SampleArtifactUri,
+		"registry.acme-rockets.io/software/unknown@" + mock.SampleDigest.String(),
+		mock.SampleArtifactUri,
+		"invalid reference",
+	}
+	results, err := VerifyAll(context.Background(), &verifier, repoFunc, refs, VerifyAllOptions{MaxSignatureAttempts: 50, Concurrency: 2})
+	if err != nil {
+		t.Fatalf("VerifyAll() error = %v", err)
+	}
+	if len(results) != len(refs) {
+		t.Fatalf("VerifyAll() returned %d results, want %d", len(results), len(refs))
+	}
+	for i, result := range results {
+		if result.ArtifactReference != refs[i] {
+			t.Fatalf("VerifyAll() result %d is for %s, want %s", i, result.ArtifactReference, refs[i])
+		}
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Error != nil || len(results[i].Outcomes) == 0 || results[i].Descriptor.Digest != mock.SampleDigest {
+			t.Fatalf("VerifyAll() result %d = %+v, want success", i, results[i])
+		}
+	}
+	for _, i := range []int{1, 3} {
+		if results[i].Error == nil {
+			t.Fatalf("VerifyAll() result %d expected error", i)
+		}
+	}
+	if created["registry.acme-rockets.io/software/net-monitor"] != 1 {
+		t.Fatalf("VerifyAll() created %d repository clients, want 1", created["registry.acme-rockets.io/software/net-monitor"])
+	}
+}
+
+// blockingVerifier records the maximum number of concurrent verifications.
+type blockingVerifier struct {
+	dummyVerifier
+	current atomic.Int32
+	max     atomic.Int32
+}
+
+func (v *blockingVerifier) Verify(ctx context.Context, desc ocispec.Descriptor, signature []byte, opts VerifierVerifyOptions) (*VerificationOutcome, error) {
+	current := v.current.Add(1)
+	defer v.current.Add(-1)
+	for {
+		max := v.max.Load()
+		if current <= max || v.max.CompareAndSwap(max, current) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return v.dummyVerifier.Verify(ctx, desc, signature, opts)
+}
+
+func TestVerifyAll_Concurrency(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := &blockingVerifier{
+		dummyVerifier: dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false},
+	}
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		return mock.NewRepository(), nil
+	}
+	refs := make([]string, 10)
+	for i := range refs {
+		refs[i] = mock.SampleArtifactUri
+	}
+	if _, err := VerifyAll(context.Background(), verifier, repoFunc, refs, VerifyAllOptions{MaxSignatureAttempts: 50, Concurrency: 3}); err != nil {
+		t.Fatalf("VerifyAll() error = %v", err)
+	}
+	if got := verifier.max.Load(); got > 3 || got < 1 {
+		t.Fatalf("VerifyAll() ran %d verifications concurrently, want at most 3", got)
+	}
+}
+
+func TestVerifyAll_Error(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		return mock.NewRepository(), nil
+	}
+	if _, err := VerifyAll(context.Background(), nil, repoFunc, nil, VerifyAllOptions{MaxSignatureAttempts: 50}); err == nil {
+		t.Fatal("expected error for nil verifier")
+	}
+	if _, err := VerifyAll(context.Background(), &verifier, nil, nil, VerifyAllOptions{MaxSignatureAttempts: 50}); err == nil {
+		t.Fatal("expected error for nil repoFunc")
+	}
+	if _, err := VerifyAll(context.Background(), &verifier, repoFunc, nil, VerifyAllOptions{}); err == nil {
+		t.Fatal("expected error for invalid MaxSignatureAttempts")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := VerifyAll(ctx, &verifier, repoFunc, []string{mock.SampleArtifactUri}, VerifyAllOptions{MaxSignatureAttempts: 50})
+	if err != nil {
+		t.Fatalf("VerifyAll() error = %v", err)
+	}
+	if !errors.Is(results[0].Error, context.Canceled) {
+		t.Fatalf("VerifyAll() result error = %v, want context.Canceled", results[0].Error)
+	}
+}
+
+func TestVerifyAll_FailurePolicy(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, false, *trustpolicy.LevelStrict, false}
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		return mock.NewRepository(), nil
+	}
+	refs := []string{
+		mock.SampleArtifactUri,
+		"invalid reference",
+		mock.SampleArtifactUri,
+	}
+
+	results, err := VerifyAll(context.Background(), &verifier, repoFunc, refs, VerifyAllOptions{MaxSignatureAttempts: 50, Concurrency: 1, FailurePolicy: &FailurePolicy{Mode: FailFast}})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("VerifyAll() error = %v, want *BatchError", err)
+	}
+	if !batchErr.Aborted || len(batchErr.Items) != 2 || batchErr.Items[0].Index != 1 {
+		t.Fatalf("VerifyAll() error = %+v", batchErr)
+	}
+	if results[0].Error != nil || results[1].Error == nil || !errors.Is(results[2].Error, ErrBatchAborted) {
+		t.Fatalf("VerifyAll() results = %+v, %+v, %+v", results[0], results[1], results[2])
+	}
+
+	results, err = VerifyAll(context.Background(), &verifier, repoFunc, refs, VerifyAllOptions{MaxSignatureAttempts: 50, FailurePolicy: &FailurePolicy{Mode: ContinueOnFailure}})
+	if !errors.As(err, &batchErr) || batchErr.Aborted || len(batchErr.Items) != 1 {
+		t.Fatalf("VerifyAll() error = %v, want a single failed item", err)
+	}
+	if results[2].Error != nil {
+		t.Fatalf("VerifyAll() result 2 error = %v", results[2].Error)
+	}
+
+	if _, err := VerifyAll(context.Background(), &verifier, repoFunc, refs, VerifyAllOptions{MaxSignatureAttempts: 50, FailurePolicy: &FailurePolicy{Mode: AbortAfterFailures}}); err == nil || errors.As(err, &batchErr) {
+		t.Fatalf("VerifyAll() error = %v, want invalid failure policy", err)
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/notaryproject/notation-go/internal/file"
+	"github.com/notaryproject/notation-go/internal/slices"
+	"github.com/notaryproject/notation-go/log"
+	"github.com/notaryproject/notation-go/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// WalkCheckpoint is the progress of [notation.Walk], saved to the checkpoint
+// file after each artifact to resume the walk after an interruption.
+type WalkCheckpoint struct {
+	// Completed are the repositories walked completely, in order.
+	Completed []string `json:"completed,omitempty"`
+
+	// Repository is the repository being walked, if any.
+	Repository string `json:"repository,omitempty"`
+
+	// LastTag is the last tag processed in Repository.
+	LastTag string `json:"lastTag,omitempty"`
+}
+
+// WalkOptions contains parameters for [notation.Walk].
+type WalkOptions struct {
+	// PluginConfig is a map of plugin configs.
+	PluginConfig map[string]string
+
+	// MaxSignatureAttempts is the maximum number of signature envelopes that
+	// will be processed for verification of each artifact. If set to less
+	// than or equals to zero, an error will be returned.
+	MaxSignatureAttempts int
+
+	// UserMetadata contains key-value pairs that must be present in the
+	// signature
+	UserMetadata map[string]string
+
+	// TagFilter, if set, selects the tags of the artifacts to verify. If nil,
+	// the artifacts of all tags are verified.
+	TagFilter func(tag string) bool
+
+	// CheckpointFile, if set, is the path of the file the progress of the
+	// walk is saved to. If the file exists, the walk resumes after the last
+	// tag it records. The file is kept once the walk completes; remove it to
+	// walk the repositories again.
+	CheckpointFile string
+
+	// RequestsPerSecond, if positive, limits the calls to each registry host
+	// to RequestsPerSecond calls per second.
+	RequestsPerSecond float64
+}
+
+// WalkResult is the verification result of a tag verified by
+// [notation.Walk].
+type WalkResult struct {
+	// Repository is the name of the repository of the tag.
+	Repository string
+
+	// Tag is the verified tag.
+	Tag string
+
+	// Descriptor is the descriptor of the verified artifact.
+	Descriptor ocispec.Descriptor
+
+	// Outcomes are the verification outcomes as returned by
+	// [notation.Verify].
+	Outcomes []*VerificationOutcome
+
+	// Error is the error that caused the verification to fail (if it fails).
+	Error error
+}
+
+// Walk verifies the artifacts of the tags of the repositories, e.g.
+// "registry.example.com/software/net-monitor", one tag at a time in the
+// lexical order of the tags, and calls fn with the result of each tag. A
+// failed verification is reported in its result and does not stop the walk.
+// The walk stops at the first error returned by fn, listing the tags or
+// saving the checkpoint.
+//
+// The walk is meant for verification sweeps of very large registries lasting
+// for days: the progress is saved to opts.CheckpointFile after each tag, so
+// that an interrupted walk resumes where it stopped, and the calls to each
+// registry host are rate limited with opts.RequestsPerSecond. The
+// repositories returned by repoFunc must be able to list their tags, e.g.
+// repositories returned by registry.NewRepository for remote repositories.
+func Walk(ctx context.Context, verifier Verifier, repoFunc RepositoryFunc, repositories []string, opts WalkOptions, fn func(result *WalkResult) error) error {
+	// sanity check
+	if verifier == nil {
+		return errors.New("verifier cannot be nil")
+	}
+	if repoFunc == nil {
+		return errors.New("repoFunc cannot be nil")
+	}
+	if fn == nil {
+		return errors.New("fn cannot be nil")
+	}
+	if opts.MaxSignatureAttempts <= 0 {
+		return ErrorSignatureRetrievalFailed{Msg: fmt.Sprintf("walkOptions.MaxSignatureAttempts expects a positive number, got %d", opts.MaxSignatureAttempts)}
+	}
+	checkpoint, err := loadWalkCheckpoint(opts.CheckpointFile)
+	if err != nil {
+		return err
+	}
+	logger := log.GetLogger(ctx)
+
+	limiters := make(map[string]*registry.RateLimiter)
+	for _, repository := range repositories {
+		if slices.Contains(checkpoint.Completed, repository) {
+			logger.Debugf("Skipping repository %s walked before the checkpoint", repository)
+			continue
+		}
+		var last string
+		if checkpoint.Repository == repository {
+			last = checkpoint.LastTag
+			logger.Infof("Resuming the walk of repository %s after tag %s", repository, last)
+		}
+		repo, err := repoFunc(ctx, repository)
+		if err != nil {
+			return fmt.Errorf("failed to create the repository client of %q: %w", repository, err)
+		}
+		host, _, _ := strings.Cut(repository, "/")
+		limiter, ok := limiters[host]
+		if !ok {
+			limiter = registry.NewRateLimiter(opts.RequestsPerSecond)
+			limiters[host] = limiter
+		}
+		repo = registry.Wrap(repo, registry.RateLimitMiddleware(limiter))
+		lister, ok := repo.(tagLister)
+		if !ok {
+			return fmt.Errorf("listing tags: %w", errdef.ErrUnsupported)
+		}
+
+		err = lister.Tags(ctx, last, func(tags []string) error {
+			for _, tag := range tags {
+				if opts.TagFilter == nil || opts.TagFilter(tag) {
+					result := &WalkResult{
+						Repository: repository,
+						Tag:        tag,
+					}
+					result.Descriptor, result.Outcomes, result.Error = Verify(ctx, verifier, repo, VerifyOptions{
+						ArtifactReference:    repository + ":" + tag,
+						PluginConfig:         opts.PluginConfig,
+						MaxSignatureAttempts: opts.MaxSignatureAttempts,
+						UserMetadata:         opts.UserMetadata,
+					})
+					if err := ctx.Err(); err != nil {
+						// the verification of the tag is interrupted
+						return err
+					}
+					if err := fn(result); err != nil {
+						return err
+					}
+				}
+				checkpoint.Repository, checkpoint.LastTag = repository, tag
+				if err := saveWalkCheckpoint(opts.CheckpointFile, checkpoint); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to walk repository %q: %w", repository, err)
+		}
+		checkpoint.Completed = append(checkpoint.Completed, repository)
+		checkpoint.Repository, checkpoint.LastTag = "", ""
+		if err := saveWalkCheckpoint(opts.CheckpointFile, checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadWalkCheckpoint loads the checkpoint from path. It returns an empty
+// checkpoint if path is empty or does not exist.
+func loadWalkCheckpoint(path string) (*WalkCheckpoint, error) {
+	checkpoint := &WalkCheckpoint{}
+	if path == "" {
+		return checkpoint, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return checkpoint, nil
+		}
+		return nil, fmt.Errorf("failed to read the walk checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("malformed walk checkpoint %s: %w", path, err)
+	}
+	return checkpoint, nil
+}
+
+// saveWalkCheckpoint saves the checkpoint to path atomically. It is a no-op
+// if path is empty.
+func saveWalkCheckpoint(path string, checkpoint *WalkCheckpoint) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := file.WriteFile(filepath.Dir(path), path, data); err != nil {
+		return fmt.Errorf("failed to save the walk checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+)
+
+func TestWalk(t *testing.T) {
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("artifact"),
+	}
+	repo := &tagRepository{
+		Repository: mock.NewRepository(),
+		tags: map[string]ocispec.Descriptor{
+			"v1":  desc,
+			"v2":  desc,
+			"v3":  desc,
+			"dev": desc,
+		},
+		signatures: map[digest.Digest][]ocispec.Descriptor{
+			desc.Digest: {mock.SigManfiestDescriptor},
+		},
+	}
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		return repo, nil
+	}
+	repositories := []string{"registry.acme-rockets.io/a", "registry.acme-rockets.io/b"}
+	opts := WalkOptions{
+		MaxSignatureAttempts: 50,
+		TagFilter:            func(tag string) bool { return tag != "dev" },
+		CheckpointFile:       filepath.Join(t.TempDir(), "checkpoint.json"),
+		RequestsPerSecond:    1000,
+	}
+	verifier := &untrustedVerifier{}
+
+	// interrupt the walk at the first tag of the second repository
+	errInterrupted := errors.New("interrupted")
+	var walked []string
+	err := Walk(context.Background(), verifier, repoFunc, repositories, opts, func(result *WalkResult) error {
+		if result.Error != nil || result.Descriptor.Digest != desc.Digest {
+			t.Fatalf("result of %s:%s = %v, %v, want success", result.Repository, result.Tag, result.Descriptor.Digest, result.Error)
+		}
+		if result.Repository == "registry.acme-rockets.io/b" && result.Tag == "v2" {
+			return errInterrupted
+		}
+		walked = append(walked, result.Repository+":"+result.Tag)
+		return nil
+	})
+	if !errors.Is(err, errInterrupted) {
+		t.Fatalf("Walk() error = %v, want %v", err, errInterrupted)
+	}
+	want := []string{
+		"registry.acme-rockets.io/a:v1",
+		"registry.acme-rockets.io/a:v2",
+		"registry.acme-rockets.io/a:v3",
+		"registry.acme-rockets.io/b:v1",
+	}
+	if !reflect.DeepEqual(walked, want) {
+		t.Fatalf("Walk() walked %v, want %v", walked, want)
+	}
+	checkpoint, err := loadWalkCheckpoint(opts.CheckpointFile)
+	if err != nil {
+		t.Fatalf("loadWalkCheckpoint() error = %v", err)
+	}
+	wantCheckpoint := &WalkCheckpoint{
+		Completed:  []string{"registry.acme-rockets.io/a"},
+		Repository: "registry.acme-rockets.io/b",
+		LastTag:    "v1",
+	}
+	if !reflect.DeepEqual(checkpoint, wantCheckpoint) {
+		t.Fatalf("checkpoint = %+v, want %+v", checkpoint, wantCheckpoint)
+	}
+
+	// resume the walk
+	walked = nil
+	collect := func(result *WalkResult) error {
+		walked = append(walked, result.Repository+":"+result.Tag)
+		return nil
+	}
+	if err := Walk(context.Background(), verifier, repoFunc, repositories, opts, collect); err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	want = []string{"registry.acme-rockets.io/b:v2", "registry.acme-rockets.io/b:v3"}
+	if !reflect.DeepEqual(walked, want) {
+		t.Fatalf("Walk() resumed with %v, want %v", walked, want)
+	}
+
+	// the completed walk is not repeated
+	walked = nil
+	if err := Walk(context.Background(), verifier, repoFunc, repositories, opts, collect); err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	if len(walked) != 0 {
+		t.Fatalf("Walk() walked %v after completion, want none", walked)
+	}
+}
+
+func TestWalkErrors(t *testing.T) {
+	verifier := &untrustedVerifier{}
+	repoFunc := func(ctx context.Context, repository string) (registry.Repository, error) {
+		return mock.NewRepository(), nil
+	}
+	fn := func(*WalkResult) error { return nil }
+	repositories := []string{"registry.acme-rockets.io/a"}
+	opts := WalkOptions{MaxSignatureAttempts: 50}
+	if err := Walk(context.Background(), nil, repoFunc, repositories, opts, fn); err == nil {
+		t.Fatal("Walk() expects error for nil verifier")
+	}
+	if err := Walk(context.Background(), verifier, repoFunc, repositories, WalkOptions{}, fn); err == nil {
+		t.Fatal("Walk() expects error for zero MaxSignatureAttempts")
+	}
+	if err := Walk(context.Background(), verifier, repoFunc, repositories, opts, fn); !errors.Is(err, errdef.ErrUnsupported) {
+		t.Fatalf("Walk() error = %v, want ErrUnsupported", err)
+	}
+}
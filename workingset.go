@@ -0,0 +1,99 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"fmt"
+	"sync"
+)
+
+// decodedEnvelopeFactor estimates the memory held by a signature in
+// verification as a multiple of the size of its envelope: the envelope
+// itself, and the payload and the certificate chain decoded from it.
+const decodedEnvelopeFactor = 2
+
+// workingSet accounts the memory held by the signature envelopes of a Verify
+// call against a budget. A nil workingSet is unlimited.
+type workingSet struct {
+	mu    sync.Mutex
+	limit int64
+	size  int64
+}
+
+// newWorkingSet returns a workingSet capped at limit bytes, or nil if limit
+// is less than or equals to zero.
+func newWorkingSet(limit int64) *workingSet {
+	if limit <= 0 {
+		return nil
+	}
+	return &workingSet{limit: limit}
+}
+
+// tryAdd adds n bytes to the working set if it fits in the budget, and
+// returns whether it was added.
+func (w *workingSet) tryAdd(n int64) bool {
+	if w == nil {
+		return true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size+n > w.limit {
+		return false
+	}
+	w.size += n
+	return true
+}
+
+// remove removes n bytes from the working set.
+func (w *workingSet) remove(n int64) {
+	if w == nil || n == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.size -= n
+}
+
+// signatureCost returns the memory held by the verification of the signature
+// envelope sigBlob, or an error if it exceeds the budget on its own.
+func (w *workingSet) signatureCost(sigBlob []byte) (int64, error) {
+	cost := decodedEnvelopeFactor * int64(len(sigBlob))
+	if w != nil && cost > w.limit {
+		return 0, fmt.Errorf("signature envelope of %d bytes exceeds the verification working set budget of %d bytes", len(sigBlob), w.limit)
+	}
+	return cost, nil
+}
+
+// admit adds the cost of the signature being verified to the working set,
+// dropping the prefetched envelopes not yet verified until it fits. The
+// dropped envelopes are fetched again when verified. cost must not exceed
+// the budget.
+func (w *workingSet) admit(cost int64, pending []*fetchedSignature) {
+	for !w.tryAdd(cost) {
+		// the prefetches completing concurrently are spilled on the next
+		// iteration
+		spillFetchedSignature(w, pending)
+	}
+}
+
+// spillFetchedSignature drops the last prefetched envelope held in the
+// working set, and returns false if there is none.
+func spillFetchedSignature(w *workingSet, pending []*fetchedSignature) bool {
+	for i := len(pending) - 1; i >= 0; i-- {
+		if pending[i].spill(w) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright The Notary Project Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notaryproject/notation-go/internal/mock"
+	"github.com/notaryproject/notation-go/verifier/trustpolicy"
+)
+
+func TestWorkingSet(t *testing.T) {
+	w := newWorkingSet(100)
+	if !w.tryAdd(60) {
+		t.Fatal("tryAdd(60) = false, want true")
+	}
+	if w.tryAdd(50) {
+		t.Fatal("tryAdd(50) = true, want false")
+	}
+	w.remove(60)
+	if !w.tryAdd(100) {
+		t.Fatal("tryAdd(100) = false, want true")
+	}
+	if _, err := w.signatureCost(make([]byte, 51)); err == nil {
+		t.Fatal("signatureCost() expects error for envelope over budget")
+	}
+	if cost, err := w.signatureCost(make([]byte, 50)); err != nil || cost != 100 {
+		t.Fatalf("signatureCost() = %d, %v, want 100", cost, err)
+	}
+
+	// unlimited
+	var unlimited *workingSet
+	if newWorkingSet(0) != nil {
+		t.Fatal("newWorkingSet(0) is not unlimited")
+	}
+	if !unlimited.tryAdd(1 << 40) {
+		t.Fatal("tryAdd() = false on unlimited working set")
+	}
+	unlimited.remove(1 << 40)
+	if _, err := unlimited.signatureCost(make([]byte, 1024)); err != nil {
+		t.Fatalf("signatureCost() error = %v", err)
+	}
+}
+
+func TestWorkingSetAdmit(t *testing.T) {
+	w := newWorkingSet(100)
+	pending := make([]*fetchedSignature, 3)
+	for i := range pending {
+		pending[i] = &fetchedSignature{done: make(chan struct{}), blob: []byte("blob")}
+		if i < 2 {
+			w.tryAdd(30)
+			pending[i].reserved = 30
+			close(pending[i].done)
+		}
+	}
+
+	w.admit(50, pending)
+	if !pending[1].spilled || pending[1].blob != nil || pending[1].reserved != 0 {
+		t.Fatal("admit() did not spill the last fetched signature")
+	}
+	if pending[0].spilled || pending[2].spilled {
+		t.Fatal("admit() spilled more fetched signatures than needed")
+	}
+	if w.size != 80 {
+		t.Fatalf("working set size = %d, want 80", w.size)
+	}
+}
+
+func TestVerifyMaxWorkingSetSize(t *testing.T) {
+	policyDocument := dummyPolicyDocument()
+	verifier := dummyVerifier{&policyDocument, mock.PluginManager{}, true, *trustpolicy.LevelStrict, false}
+	opts := VerifyOptions{
+		ArtifactReference:         mock.SampleArtifactUri,
+		MaxSignatureAttempts:      6,
+		SignatureFetchConcurrency: 4,
+	}
+
+	t.Run("signature over budget", func(t *testing.T) {
+		repo := &slowRepository{Repository: mock.NewRepository(), delay: time.Millisecond}
+		repo.ListSignaturesResponse = signatureManifests(3)
+		opts := opts
+		opts.MaxWorkingSetSize = 100
+		_, _, err := Verify(context.Background(), &verifier, repo, opts)
+		if !errors.As(err, &ErrorVerificationFailed{}) || !strings.Contains(err.Error(), "working set budget") {
+			t.Fatalf("Verify() error = %v, want ErrorVerificationFailed over budget", err)
+		}
+	})
+
+	t.Run("prefetched signatures spilled", func(t *testing.T) {
+		// the blobs are of 71 bytes, 142 bytes in verification
+		repo := &slowRepository{Repository: mock.NewRepository(), delay: time.Millisecond}
+		repo.ListSignaturesResponse = signatureManifests(6)
+		opts := opts
+		opts.MaxWorkingSetSize = 200
+		_, _, err := Verify(context.Background(), &verifier, repo, opts)
+		if !errors.As(err, &ErrorVerificationFailed{}) || strings.Contains(err.Error(), "working set budget") {
+			t.Fatalf("Verify() error = %v, want ErrorVerificationFailed", err)
+		}
+		if repo.fetched <= 6 {
+			t.Fatalf("Verify() fetched %d signature blobs, want spilled blobs fetched again", repo.fetched)
+		}
+	})
+}